@@ -0,0 +1,23 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+)
+
+// newConfigCommand builds trxgo config, which prints the configuration the
+// other commands would run with, after the APP_ENV profile and the
+// environment were applied, with secrets redacted
+func newConfigCommand(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "config",
+		Short: "Print the resolved configuration and APP_ENV profile",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(a.cfg.Redacted())
+		},
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"interview/internal/config"
+	"interview/internal/database"
+	"interview/internal/vault"
+)
+
+// errSkipped marks a check that did not run because one it depends on failed
+var errSkipped = errors.New("skipped")
+
+// doctorCheck is one check trxgo doctor runs
+type doctorCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// newDoctorCommand builds trxgo doctor
+func newDoctorCommand(a *app) *cobra.Command {
+	var timeout time.Duration
+	var checkKafka bool
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the configuration and the dependencies it names",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			checks, done := doctorChecks(a.cfg, checkKafka)
+			defer done()
+			return runDoctor(cmd.Context(), cmd.OutOrStdout(), a.cfg, checks, timeout)
+		},
+	}
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Second, "Time limit of each check")
+	cmd.Flags().BoolVar(&checkKafka, "kafka", false, "Also check the Kafka brokers, as the worker needs (implied by TRANSACTION_ASYNC_CREATE)")
+	return cmd
+}
+
+// doctorChecks returns the checks for the dependencies cfg names, in the
+// order they must run: later checks use the credentials and the database
+// connection earlier ones obtain. done releases them.
+func doctorChecks(cfg *config.Config, checkKafka bool) (checks []doctorCheck, done func()) {
+	var creds *database.Credentials
+	var db *gorm.DB
+	var closers []func()
+	done = func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+	}
+
+	if cfg.Vault.Enabled() {
+		checks = append(checks, doctorCheck{"vault", func(ctx context.Context) error {
+			issued, stop, err := vault.StartDatabaseCredentials(cfg.Vault)
+			if err != nil {
+				return err
+			}
+			closers = append(closers, stop)
+			creds = issued
+			return nil
+		}})
+	}
+
+	checks = append(checks,
+		doctorCheck{"database", func(ctx context.Context) error {
+			if cfg.Vault.Enabled() && creds == nil {
+				return errSkipped
+			}
+			opened, err := openDoctorDB(cfg.Database, creds)
+			if err != nil {
+				return err
+			}
+			db = opened
+			closers = append(closers, func() { closeDB(opened) })
+			return pingDB(ctx, db)
+		}},
+		doctorCheck{"migrations", func(ctx context.Context) error {
+			if db == nil {
+				return errSkipped
+			}
+			if err := database.CheckMigrations(db.WithContext(ctx), migratedModels...); err != nil {
+				return err
+			}
+			_, err := database.CheckConfiguredSchema(db.WithContext(ctx), cfg.Amount.Precision, cfg.Amount.Scale)
+			return err
+		}},
+	)
+
+	if cfg.Database.HasReplica() {
+		checks = append(checks, doctorCheck{"read_replica", func(ctx context.Context) error {
+			replica, err := openDoctorDB(cfg.Database.Replica(), creds)
+			if err != nil {
+				return err
+			}
+			defer closeDB(replica)
+			return pingDB(ctx, replica)
+		}})
+	}
+
+	if cfg.Redis.Enabled() {
+		checks = append(checks, doctorCheck{"redis", func(ctx context.Context) error {
+			client := redis.NewClient(&redis.Options{
+				Addr:     cfg.Redis.Addr,
+				Password: cfg.Redis.Password,
+				DB:       cfg.Redis.DB,
+			})
+			defer client.Close()
+			return client.Ping(ctx).Err()
+		}})
+	}
+
+	if checkKafka || cfg.Transaction.AsyncCreate {
+		checks = append(checks, doctorCheck{"kafka", func(ctx context.Context) error {
+			for _, broker := range cfg.Kafka.Brokers {
+				conn, err := kafka.DialContext(ctx, "tcp", broker)
+				if err != nil {
+					return fmt.Errorf("%s: %v", broker, err)
+				}
+				conn.Close()
+			}
+			return nil
+		}})
+	}
+	return checks, done
+}
+
+// runDoctor runs checks in order, each cancelled after timeout, and prints
+// one line per check to w. It fails when any check does.
+func runDoctor(ctx context.Context, w io.Writer, cfg *config.Config, checks []doctorCheck, timeout time.Duration) error {
+	fmt.Fprintf(w, "🩺 Checking %s on %s:%d\n", cfg.Database.Name, cfg.Database.Host, cfg.Database.Port)
+
+	failed := 0
+	for _, c := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := c.check(checkCtx)
+		cancel()
+
+		switch {
+		case errors.Is(err, errSkipped):
+			fmt.Fprintf(w, "⏭️  %-12s skipped\n", c.name)
+			failed++
+		case err != nil:
+			fmt.Fprintf(w, "❌ %-12s %v\n", c.name, err)
+			failed++
+		default:
+			fmt.Fprintf(w, "✅ %-12s ok\n", c.name)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+// openDoctorDB connects to cfg the way the server does, through the
+// failover hosts and with creds when they are set
+func openDoctorDB(cfg config.DatabaseConfig, creds *database.Credentials) (*gorm.DB, error) {
+	dialector, err := database.MySQLDialector(cfg, creds)
+	if err != nil {
+		return nil, err
+	}
+	return gorm.Open(dialector, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+}
+
+// pingDB checks that db accepts connections
+func pingDB(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// closeDB closes the connections of db
+func closeDB(db *gorm.DB) {
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
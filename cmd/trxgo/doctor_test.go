@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"interview/internal/config"
+)
+
+func TestRunDoctor(t *testing.T) {
+	cfg := &config.Config{Database: config.DatabaseConfig{Host: "db", Port: 3306, Name: "interview_db"}}
+	ok := func(ctx context.Context) error { return nil }
+
+	var out bytes.Buffer
+	err := runDoctor(context.Background(), &out, cfg, []doctorCheck{
+		{"database", ok},
+		{"migrations", ok},
+	}, time.Second)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "🩺 Checking interview_db on db:3306\n✅ database     ok\n✅ migrations   ok\n", out.String())
+}
+
+func TestRunDoctor_ReportsFailures(t *testing.T) {
+	cfg := &config.Config{}
+
+	var out bytes.Buffer
+	err := runDoctor(context.Background(), &out, cfg, []doctorCheck{
+		{"database", func(ctx context.Context) error { return errors.New("connection refused") }},
+		{"migrations", func(ctx context.Context) error { return errSkipped }},
+		{"redis", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+	}, 10*time.Millisecond)
+
+	assert.EqualError(t, err, "3 of 3 checks failed")
+	assert.Contains(t, out.String(), "❌ database     connection refused\n")
+	assert.Contains(t, out.String(), "⏭️  migrations   skipped\n")
+	assert.Contains(t, out.String(), "❌ redis        context deadline exceeded\n")
+}
+
+func TestDoctorChecks(t *testing.T) {
+	names := func(checks []doctorCheck) []string {
+		var names []string
+		for _, c := range checks {
+			names = append(names, c.name)
+		}
+		return names
+	}
+
+	checks, done := doctorChecks(&config.Config{}, false)
+	defer done()
+	assert.Equal(t, []string{"database", "migrations"}, names(checks))
+
+	cfg := &config.Config{
+		Redis:       config.RedisConfig{Addr: "localhost:6379"},
+		Transaction: config.TransactionConfig{AsyncCreate: true},
+	}
+	checks, done = doctorChecks(cfg, false)
+	defer done()
+	assert.Equal(t, []string{"database", "migrations", "redis", "kafka"}, names(checks))
+}
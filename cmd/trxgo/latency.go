@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"interview/internal/handlers"
+	"interview/internal/models"
+)
+
+// latencyOptions configure trxgo latency
+type latencyOptions struct {
+	userID       uint
+	runs         int
+	pollInterval time.Duration
+	timeout      time.Duration
+}
+
+// newLatencyCommand builds trxgo latency
+func newLatencyCommand(a *app) *cobra.Command {
+	var url string
+	opts := latencyOptions{}
+	cmd := &cobra.Command{
+		Use:   "latency",
+		Short: "Measure how long status changes take to reach waiting and polling clients",
+		Long: `Measure how long a transaction status change takes to reach clients of a
+running server. Each run creates a test transaction, marks it successful and
+times how long until a client waiting on /wait, which is woken by the event
+bus, and a client polling /status each see the change. The transaction is
+deleted afterwards.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if url == "" {
+				url = "http://localhost:" + a.cfg.Server.Port
+			}
+			return runLatency(cmd.Context(), cmd.OutOrStdout(), http.DefaultClient, url, opts)
+		},
+	}
+	cmd.Flags().StringVar(&url, "url", "", "Base URL of the server (default http://localhost:SERVER_PORT)")
+	cmd.Flags().UintVar(&opts.userID, "user-id", 1, "Existing user the test transactions belong to")
+	cmd.Flags().IntVar(&opts.runs, "runs", 20, "Number of status changes to measure")
+	cmd.Flags().DurationVar(&opts.pollInterval, "poll-interval", 100*time.Millisecond, "How often the polling client reads the status")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 10*time.Second, "Time limit for a change to be seen")
+	return cmd
+}
+
+// runLatency measures opts.runs status changes on the server at baseURL and
+// prints the latency percentiles of each delivery path to w
+func runLatency(ctx context.Context, w io.Writer, client *http.Client, baseURL string, opts latencyOptions) error {
+	if opts.runs <= 0 {
+		return fmt.Errorf("runs must be positive")
+	}
+
+	fmt.Fprintf(w, "📡 Measuring %d status changes on %s\n", opts.runs, baseURL)
+	api := latencyClient{client: client, base: baseURL + "/api/v1/transactions"}
+	var waited, polled []time.Duration
+	for i := 0; i < opts.runs; i++ {
+		wait, poll, err := measureStatusChange(ctx, api, opts)
+		if err != nil {
+			return fmt.Errorf("run %d: %v", i+1, err)
+		}
+		waited = append(waited, wait)
+		polled = append(polled, poll)
+	}
+
+	fmt.Fprintf(w, "%-6s %8s %8s %8s %8s\n", "", "p50", "p95", "p99", "max")
+	for _, path := range []struct {
+		name      string
+		latencies []time.Duration
+	}{{"wait", waited}, {"poll", polled}} {
+		sort.Slice(path.latencies, func(i, j int) bool { return path.latencies[i] < path.latencies[j] })
+		fmt.Fprintf(w, "%-6s %8s %8s %8s %8s\n", path.name,
+			formatLatency(percentile(path.latencies, 50)),
+			formatLatency(percentile(path.latencies, 95)),
+			formatLatency(percentile(path.latencies, 99)),
+			formatLatency(path.latencies[len(path.latencies)-1]))
+	}
+	return nil
+}
+
+// measureStatusChange creates a test transaction and returns how long after
+// the request marking it successful was sent a waiting and a polling client
+// saw the change
+func measureStatusChange(ctx context.Context, api latencyClient, opts latencyOptions) (wait, poll time.Duration, err error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+
+	var created models.Transaction
+	body := map[string]interface{}{
+		"user_id":  opts.userID,
+		"amount":   "1.00",
+		"metadata": map[string]string{"source": "latency"},
+	}
+	if err := api.call(ctx, http.MethodPost, "", body, http.StatusCreated, &created); err != nil {
+		return 0, 0, fmt.Errorf("create: %v", err)
+	}
+	path := "/" + strconv.FormatUint(uint64(created.ID), 10)
+	defer func() {
+		// The run's context may be what failed it
+		deleteCtx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+		defer cancel()
+		if deleteErr := api.call(deleteCtx, http.MethodDelete, path, nil, http.StatusOK, nil); deleteErr != nil && err == nil {
+			err = fmt.Errorf("delete: %v", deleteErr)
+		}
+	}()
+
+	// The wait reports the change even if the update lands before the
+	// server subscribes, since it names the status it last saw
+	var sent time.Time
+	started := make(chan struct{})
+	waitDone := make(chan error, 1)
+	go func() {
+		var result models.TransactionWaitResult
+		err := api.call(ctx, http.MethodGet, path+"/wait?status=pending&timeout="+opts.timeout.String(), nil, http.StatusOK, &result)
+		<-started
+		wait = time.Since(sent)
+		if err == nil && !result.Changed {
+			err = fmt.Errorf("status did not change")
+		}
+		waitDone <- err
+	}()
+	pollDone := make(chan error, 1)
+	go func() {
+		<-started
+		for {
+			var status models.TransactionStatus
+			if err := api.call(ctx, http.MethodGet, path+"/status", nil, http.StatusOK, &status); err != nil {
+				pollDone <- err
+				return
+			}
+			if status.Status == "success" {
+				poll = time.Since(sent)
+				pollDone <- nil
+				return
+			}
+			select {
+			case <-time.After(opts.pollInterval):
+			case <-ctx.Done():
+				pollDone <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	sent = time.Now()
+	close(started)
+	updateErr := api.call(ctx, http.MethodPut, path, map[string]string{"status": "success"}, http.StatusOK, nil)
+	if updateErr != nil {
+		cancel()
+	}
+	waitErr, pollErr := <-waitDone, <-pollDone
+	switch {
+	case updateErr != nil:
+		return 0, 0, fmt.Errorf("update status: %v", updateErr)
+	case waitErr != nil:
+		return 0, 0, fmt.Errorf("wait: %v", waitErr)
+	case pollErr != nil:
+		return 0, 0, fmt.Errorf("poll: %v", pollErr)
+	}
+	return wait, poll, nil
+}
+
+// latencyClient calls the transaction API of a running server
+type latencyClient struct {
+	client *http.Client
+	base   string
+}
+
+// call sends a test data request to base+path, checks its status code and
+// decodes the response data into out
+func (c latencyClient) call(ctx context.Context, method, path string, body interface{}, want int, out interface{}) error {
+	var encoded []byte
+	if body != nil {
+		var err error
+		if encoded, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, bytes.NewReader(encoded))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(handlers.TestDataHeader, "true")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Data  json.RawMessage `json:"data"`
+		Error string          `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("invalid response with status %d: %v", resp.StatusCode, err)
+	}
+	if resp.StatusCode != want {
+		return fmt.Errorf("expected status %d, got %d: %s", want, resp.StatusCode, response.Error)
+	}
+	if out != nil {
+		return json.Unmarshal(response.Data, out)
+	}
+	return nil
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// formatLatency rounds a latency for display
+func formatLatency(d time.Duration) string {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond).String()
+	}
+	return d.Round(100 * time.Microsecond).String()
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"interview/internal/models"
+)
+
+// fakeTransactionAPI serves the transaction endpoints trxgo latency calls for
+// a single transaction, waking waiters when its status changes
+type fakeTransactionAPI struct {
+	mu      sync.Mutex
+	status  string
+	changed chan struct{}
+	deleted bool
+	testing []string
+}
+
+func (f *fakeTransactionAPI) handler() http.Handler {
+	respond := func(w http.ResponseWriter, status int, data interface{}) {
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(models.APIResponse{Success: status < 300, Data: data})
+	}
+	current := func() models.TransactionStatus {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return models.TransactionStatus{Status: f.status}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/transactions", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.status, f.changed, f.deleted = "pending", make(chan struct{}), false
+		f.testing = append(f.testing, r.Header.Get("X-Test-Data"))
+		f.mu.Unlock()
+		respond(w, http.StatusCreated, models.Transaction{ID: 7, Status: "pending"})
+	})
+	mux.HandleFunc("PUT /api/v1/transactions/7", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.status = "success"
+		close(f.changed)
+		f.mu.Unlock()
+		respond(w, http.StatusOK, nil)
+	})
+	mux.HandleFunc("GET /api/v1/transactions/7/wait", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		changed := f.changed
+		f.mu.Unlock()
+		select {
+		case <-changed:
+			respond(w, http.StatusOK, models.TransactionWaitResult{Changed: true})
+		case <-r.Context().Done():
+		}
+	})
+	mux.HandleFunc("GET /api/v1/transactions/7/status", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, current())
+	})
+	mux.HandleFunc("DELETE /api/v1/transactions/7", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.deleted = true
+		f.mu.Unlock()
+		respond(w, http.StatusOK, nil)
+	})
+	return mux
+}
+
+func TestRunLatency(t *testing.T) {
+	api := &fakeTransactionAPI{}
+	server := httptest.NewServer(api.handler())
+	defer server.Close()
+
+	var out bytes.Buffer
+	err := runLatency(context.Background(), &out, server.Client(), server.URL, latencyOptions{
+		userID:       1,
+		runs:         3,
+		pollInterval: time.Millisecond,
+		timeout:      time.Second,
+	})
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "📡 Measuring 3 status changes on "+server.URL, lines[0])
+	assert.Equal(t, []string{"p50", "p95", "p99", "max"}, strings.Fields(lines[1]))
+	assert.True(t, strings.HasPrefix(lines[2], "wait "), lines[2])
+	assert.True(t, strings.HasPrefix(lines[3], "poll "), lines[3])
+	assert.True(t, api.deleted)
+	assert.Equal(t, []string{"true", "true", "true"}, api.testing)
+}
+
+func TestRunLatency_DeletesAfterFailure(t *testing.T) {
+	api := &fakeTransactionAPI{}
+	mux := http.NewServeMux()
+	mux.Handle("/", api.handler())
+	mux.HandleFunc("PUT /api/v1/transactions/7", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(models.APIResponse{Error: "Invalid status transition"})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	err := runLatency(context.Background(), new(bytes.Buffer), server.Client(), server.URL, latencyOptions{
+		userID:       1,
+		runs:         1,
+		pollInterval: time.Millisecond,
+		timeout:      100 * time.Millisecond,
+	})
+	assert.EqualError(t, err, "run 1: update status: expected status 200, got 409: Invalid status transition")
+	assert.True(t, api.deleted)
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	assert.Equal(t, time.Duration(5), percentile(sorted, 50))
+	assert.Equal(t, time.Duration(10), percentile(sorted, 95))
+	assert.Equal(t, time.Duration(1), percentile(sorted, 0))
+}
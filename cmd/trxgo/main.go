@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"interview/internal/config"
+)
+
+// app holds what the root command sets up before any subcommand runs
+type app struct {
+	cfg *config.Config
+}
+
+func main() {
+	// Long-running commands stop when interrupted
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := newRootCommand().ExecuteContext(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+// newRootCommand builds the trxgo command tree. Every subcommand runs with
+// the configuration loaded and logging set up by the root command.
+func newRootCommand() *cobra.Command {
+	a := &app{}
+	root := &cobra.Command{
+		Use:          "trxgo",
+		Short:        "Transaction management API and its tools",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return a.load()
+		},
+	}
+
+	root.AddCommand(
+		newServeCommand(a),
+		newWorkerCommand(a),
+		newMigrateCommand(a),
+		newSetupCommand(a),
+		newSeedCommand(a),
+		newDoctorCommand(a),
+		newLatencyCommand(a),
+		newConfigCommand(a),
+	)
+	return root
+}
+
+// load loads the configuration and sets up logging from it
+func (a *app) load() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %v", err)
+	}
+	setupLogging(cfg.Log.Level)
+	a.cfg = cfg
+	return nil
+}
+
+// setupLogging configures the logging system
+func setupLogging(level string) {
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+
+	logLevel, err := logrus.ParseLevel(level)
+	if err != nil {
+		logLevel = logrus.InfoLevel
+	}
+	logrus.SetLevel(logLevel)
+}
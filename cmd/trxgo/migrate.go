@@ -0,0 +1,561 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"interview/internal/config"
+	"interview/internal/database"
+	"interview/internal/models"
+	"interview/pkg/utils"
+)
+
+// newMigrateCommand builds trxgo migrate. The migrations are Go code
+// compiled into the binary, so it needs no migration files next to it.
+func newMigrateCommand(a *app) *cobra.Command {
+	var action string
+	var verbose bool
+	var verifyRows int
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Run database migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMigrate(a.cfg, action, verbose, verifyRows)
+		},
+	}
+	cmd.Flags().StringVar(&action, "action", "up", "Migration action: up, down, reset, status, verify")
+	cmd.Flags().BoolVar(&verbose, "verbose", false, "Enable verbose logging")
+	cmd.Flags().IntVar(&verifyRows, "verify-rows", 0, "Rows per table verify copies into the MySQL shadow schema; 0 copies every row")
+	return cmd
+}
+
+// runMigrate runs a migration action against the configured database
+func runMigrate(cfg *config.Config, action string, verbose bool, verifyRows int) error {
+	switch action {
+	case "up", "down", "reset", "status", "verify":
+	default:
+		return fmt.Errorf("unknown action: %s. Use: up, down, reset, status, or verify", action)
+	}
+	if verifyRows < 0 {
+		return fmt.Errorf("invalid --verify-rows: %d is negative", verifyRows)
+	}
+
+	// Connect to database
+	db, err := connectDB(cfg.Database, verbose)
+	if err != nil {
+		return err
+	}
+
+	// Only one migrator may change the schema at a time; status and verify
+	// leave it unchanged
+	if action == "status" || action == "verify" {
+		return migrateAction(db, cfg, action, verbose, verifyRows)
+	}
+	return database.WithMigrationLock(db, cfg.Database.MigrationLockTimeout, func() error {
+		return migrateAction(db, cfg, action, verbose, verifyRows)
+	})
+}
+
+// migrateAction runs a migration action against db
+func migrateAction(db *gorm.DB, cfg *config.Config, action string, verbose bool, verifyRows int) error {
+	switch action {
+	case "up":
+		if err := migrateUp(db, cfg.Amount); err != nil {
+			return fmt.Errorf("failed to run migrations: %v", err)
+		}
+		fmt.Println("✅ Migrations completed successfully")
+	case "down":
+		if err := migrateDown(db); err != nil {
+			return fmt.Errorf("failed to rollback migrations: %v", err)
+		}
+		fmt.Println("✅ Migrations rolled back successfully")
+	case "reset":
+		if err := migrateReset(db, cfg.Amount); err != nil {
+			return fmt.Errorf("failed to reset database: %v", err)
+		}
+		fmt.Println("✅ Database reset successfully")
+	case "status":
+		if err := migrateStatus(db, cfg.Amount); err != nil {
+			return fmt.Errorf("failed to check migration status: %v", err)
+		}
+	case "verify":
+		if err := migrateVerify(db, cfg, verbose, verifyRows); err != nil {
+			return fmt.Errorf("migration verification failed: %v", err)
+		}
+		fmt.Println("✅ Migrations verified successfully, no changes were applied")
+	}
+	return nil
+}
+
+func connectDB(cfg config.DatabaseConfig, verbose bool) (*gorm.DB, error) {
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+		cfg.User,
+		cfg.Password,
+		cfg.Host,
+		cfg.Port,
+		cfg.Name,
+	)
+
+	logMode := logger.Silent
+	if verbose {
+		logMode = logger.Info
+	}
+
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logMode),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return db, nil
+}
+
+// migratedModels are the models migrateUp creates tables for
+var migratedModels = []interface{}{
+	&models.User{},
+	&models.Transaction{},
+	&models.Budget{},
+	&models.Tag{},
+	&models.Case{},
+	&models.CasePin{},
+	&models.Correction{},
+	&models.ProcessedMessage{},
+}
+
+// migrateUp applies the amount column type and all model migrations
+func migrateUp(db *gorm.DB, amount config.AmountConfig) error {
+	fmt.Println("🚀 Running migrations...")
+
+	// Apply the configured amount column type; existing columns are only ever widened
+	if err := database.ConfigureAmountColumn(db, amount.Precision, amount.Scale); err != nil {
+		return fmt.Errorf("failed to configure amount column: %w", err)
+	}
+
+	// Users first, so the transactions foreign key finds a user for every row
+	if err := database.MigrateUsers(db); err != nil {
+		return err
+	}
+
+	// Auto migrate all models
+	if err := db.AutoMigrate(migratedModels...); err != nil {
+		return fmt.Errorf("failed to auto migrate: %w", err)
+	}
+
+	// Create additional indexes if needed
+	if err := createIndexes(db); err != nil {
+		return fmt.Errorf("failed to create indexes: %w", err)
+	}
+
+	if err := backfillUUIDs(db); err != nil {
+		return fmt.Errorf("failed to backfill UUIDs: %w", err)
+	}
+
+	if err := dropCorrectionTokens(db); err != nil {
+		return fmt.Errorf("failed to drop correction confirm tokens: %w", err)
+	}
+
+	// Running migrate with a binary accepts its schema, even an older one
+	if err := recordSchemaVersion(db, amount); err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+
+	return nil
+}
+
+// recordSchemaVersion records the schema version of this binary as the one
+// db is migrated to
+func recordSchemaVersion(db *gorm.DB, amount config.AmountConfig) error {
+	version, err := database.ConfiguredSchemaVersion(db, amount.Precision, amount.Scale)
+	if err != nil {
+		return err
+	}
+	return database.RecordSchemaVersion(db, version)
+}
+
+// errVerifyRollback aborts the verify transaction once the migrations have run
+var errVerifyRollback = errors.New("verify rollback")
+
+// migrateVerify runs all pending migrations without keeping their effects.
+// MySQL commits DDL implicitly, so there the migrations run against a shadow
+// schema copied from the current tables, rows and foreign keys, with at most
+// rows rows per table unless rows is 0; other databases run them inside a
+// transaction that is rolled back.
+func migrateVerify(db *gorm.DB, cfg *config.Config, verbose bool, rows int) error {
+	fmt.Println("🔍 Verifying migrations...")
+
+	if db.Dialector.Name() == "mysql" {
+		return verifyOnShadowSchema(db, cfg, verbose, rows)
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := migrateUp(tx, cfg.Amount); err != nil {
+			return err
+		}
+		return errVerifyRollback
+	})
+	if errors.Is(err, errVerifyRollback) {
+		return nil
+	}
+	return err
+}
+
+// verifyOnShadowSchema copies every table into a temporary schema, migrates
+// that schema and drops it again
+func verifyOnShadowSchema(db *gorm.DB, cfg *config.Config, verbose bool, rows int) (err error) {
+	shadow := fmt.Sprintf("%s_verify_%d", cfg.Database.Name, time.Now().Unix())
+	if err := db.Exec(fmt.Sprintf("CREATE DATABASE `%s`", shadow)).Error; err != nil {
+		return fmt.Errorf("failed to create shadow schema: %w", err)
+	}
+	defer func() {
+		if dropErr := db.Exec(fmt.Sprintf("DROP DATABASE `%s`", shadow)).Error; dropErr != nil && err == nil {
+			err = fmt.Errorf("failed to drop shadow schema %s: %w", shadow, dropErr)
+		}
+	}()
+
+	if err := copyToShadowSchema(db, shadow, rows); err != nil {
+		return err
+	}
+
+	shadowCfg := cfg.Database
+	shadowCfg.Name = shadow
+	shadowDB, err := connectDB(shadowCfg, verbose)
+	if err != nil {
+		return err
+	}
+	if sqlDB, err := shadowDB.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	return migrateUp(shadowDB, cfg.Amount)
+}
+
+// foreignKey is a foreign key constraint of the migrated schema
+type foreignKey struct {
+	Name       string
+	Table      string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	UpdateRule string
+	DeleteRule string
+}
+
+// copyToShadowSchema copies the structure, rows and foreign keys of every
+// table of db into shadow. With rows above 0 each table gets a sample of at
+// most rows rows, parents before children, and a child row only when the
+// rows it references were copied, so the foreign keys hold in the sample.
+func copyToShadowSchema(db *gorm.DB, shadow string, rows int) error {
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		return fmt.Errorf("failed to list tables: %w", err)
+	}
+	keys, err := foreignKeys(db)
+	if err != nil {
+		return fmt.Errorf("failed to list foreign keys: %w", err)
+	}
+
+	// CREATE TABLE ... LIKE copies no foreign keys; they are added once every
+	// table has its rows. Foreign key checks are session state, so the copy
+	// keeps to one connection.
+	return db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SET FOREIGN_KEY_CHECKS = 0").Error; err != nil {
+			return fmt.Errorf("failed to disable foreign key checks: %w", err)
+		}
+		defer conn.Exec("SET FOREIGN_KEY_CHECKS = 1")
+
+		for _, table := range shadowTableOrder(tables, keys) {
+			if err := conn.Exec(fmt.Sprintf("CREATE TABLE `%s`.`%s` LIKE `%s`", shadow, table, table)).Error; err != nil {
+				return fmt.Errorf("failed to copy table %s: %w", table, err)
+			}
+			result := conn.Exec(shadowCopySQL(shadow, table, keys, rows))
+			if result.Error != nil {
+				return fmt.Errorf("failed to copy rows of %s: %w", table, result.Error)
+			}
+			fmt.Printf("   Copied %s: %d rows\n", table, result.RowsAffected)
+		}
+		for _, key := range keys {
+			if err := conn.Exec(key.addSQL(shadow)).Error; err != nil {
+				return fmt.Errorf("failed to copy foreign key %s: %w", key.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// foreignKeys lists the foreign keys between the tables of db's schema
+func foreignKeys(db *gorm.DB) ([]foreignKey, error) {
+	var columns []struct {
+		ConstraintName string
+		TableName      string
+		ColumnName     string
+		RefTable       string
+		RefColumn      string
+		UpdateRule     string
+		DeleteRule     string
+	}
+	err := db.Raw(`SELECT k.CONSTRAINT_NAME AS constraint_name, k.TABLE_NAME AS table_name, k.COLUMN_NAME AS column_name,
+			k.REFERENCED_TABLE_NAME AS ref_table, k.REFERENCED_COLUMN_NAME AS ref_column,
+			r.UPDATE_RULE AS update_rule, r.DELETE_RULE AS delete_rule
+		FROM information_schema.KEY_COLUMN_USAGE k
+		JOIN information_schema.REFERENTIAL_CONSTRAINTS r
+			ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA AND r.TABLE_NAME = k.TABLE_NAME AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME
+		WHERE k.TABLE_SCHEMA = DATABASE() AND k.REFERENCED_TABLE_SCHEMA = k.TABLE_SCHEMA
+		ORDER BY k.TABLE_NAME, k.CONSTRAINT_NAME, k.ORDINAL_POSITION`).Scan(&columns).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []foreignKey
+	for _, column := range columns {
+		if n := len(keys); n == 0 || keys[n-1].Table != column.TableName || keys[n-1].Name != column.ConstraintName {
+			keys = append(keys, foreignKey{
+				Name:       column.ConstraintName,
+				Table:      column.TableName,
+				RefTable:   column.RefTable,
+				UpdateRule: column.UpdateRule,
+				DeleteRule: column.DeleteRule,
+			})
+		}
+		key := &keys[len(keys)-1]
+		key.Columns = append(key.Columns, column.ColumnName)
+		key.RefColumns = append(key.RefColumns, column.RefColumn)
+	}
+	return keys, nil
+}
+
+// addSQL returns the statement adding the foreign key to its table in shadow
+func (k foreignKey) addSQL(shadow string) string {
+	return fmt.Sprintf("ALTER TABLE `%s`.`%s` ADD CONSTRAINT `%s` FOREIGN KEY (%s) REFERENCES `%s`.`%s` (%s) ON UPDATE %s ON DELETE %s",
+		shadow, k.Table, k.Name, quoteColumns(k.Columns), shadow, k.RefTable, quoteColumns(k.RefColumns), k.UpdateRule, k.DeleteRule)
+}
+
+// shadowTableOrder orders tables so every table comes after the tables its
+// foreign keys reference. Tables in a reference cycle keep their order at
+// the end.
+func shadowTableOrder(tables []string, keys []foreignKey) []string {
+	ordered := make([]string, 0, len(tables))
+	placed := make(map[string]bool, len(tables))
+	for len(ordered) < len(tables) {
+		progress := false
+		for _, table := range tables {
+			if placed[table] || !parentsPlaced(table, keys, placed) {
+				continue
+			}
+			ordered = append(ordered, table)
+			placed[table] = true
+			progress = true
+		}
+		if !progress {
+			for _, table := range tables {
+				if !placed[table] {
+					ordered = append(ordered, table)
+				}
+			}
+			break
+		}
+	}
+	return ordered
+}
+
+// parentsPlaced reports whether every table table references is placed
+func parentsPlaced(table string, keys []foreignKey, placed map[string]bool) bool {
+	for _, key := range keys {
+		if key.Table == table && key.RefTable != table && !placed[key.RefTable] {
+			return false
+		}
+	}
+	return true
+}
+
+// shadowCopySQL returns the statement copying table's rows into shadow. With
+// rows above 0 it copies at most rows rows, leaving out rows that reference
+// rows of other tables the sample left out. References within table are not
+// followed.
+func shadowCopySQL(shadow, table string, keys []foreignKey, rows int) string {
+	query := fmt.Sprintf("INSERT INTO `%s`.`%s` SELECT * FROM `%s`", shadow, table, table)
+	if rows == 0 {
+		return query
+	}
+
+	var conditions []string
+	for _, key := range keys {
+		if key.Table != table || key.RefTable == table {
+			continue
+		}
+		// A row with a NULL in the key references nothing
+		alternatives := []string{fmt.Sprintf("(%s) IN (SELECT %s FROM `%s`.`%s`)", quoteColumns(key.Columns), quoteColumns(key.RefColumns), shadow, key.RefTable)}
+		for _, column := range key.Columns {
+			alternatives = append(alternatives, fmt.Sprintf("`%s` IS NULL", column))
+		}
+		conditions = append(conditions, "("+strings.Join(alternatives, " OR ")+")")
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return query + fmt.Sprintf(" LIMIT %d", rows)
+}
+
+// quoteColumns returns columns quoted and separated by commas
+func quoteColumns(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = "`" + column + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func migrateDown(db *gorm.DB) error {
+	fmt.Println("📉 Rolling back migrations...")
+
+	// Drop all tables in reverse order
+	if err := db.Migrator().DropTable(&models.SchemaMigration{}); err != nil {
+		return fmt.Errorf("failed to drop schema_migrations table: %w", err)
+	}
+	if err := db.Migrator().DropTable(&models.ProcessedMessage{}); err != nil {
+		return fmt.Errorf("failed to drop processed_messages table: %w", err)
+	}
+	if err := db.Migrator().DropTable(&models.Correction{}); err != nil {
+		return fmt.Errorf("failed to drop corrections table: %w", err)
+	}
+	if err := db.Migrator().DropTable(&models.CasePin{}, &models.Case{}); err != nil {
+		return fmt.Errorf("failed to drop cases tables: %w", err)
+	}
+	if err := db.Migrator().DropTable(&models.Budget{}); err != nil {
+		return fmt.Errorf("failed to drop budgets table: %w", err)
+	}
+	if err := db.Migrator().DropTable("transaction_tags", &models.Tag{}); err != nil {
+		return fmt.Errorf("failed to drop tags tables: %w", err)
+	}
+	if err := db.Migrator().DropTable(&models.Transaction{}); err != nil {
+		return fmt.Errorf("failed to drop transactions table: %w", err)
+	}
+	if err := db.Migrator().DropTable(&models.User{}); err != nil {
+		return fmt.Errorf("failed to drop users table: %w", err)
+	}
+
+	return nil
+}
+
+func migrateReset(db *gorm.DB, amount config.AmountConfig) error {
+	fmt.Println("🔄 Resetting database...")
+
+	// Drop all tables
+	if err := migrateDown(db); err != nil {
+		return err
+	}
+
+	// Run migrations again
+	if err := migrateUp(db, amount); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func createIndexes(db *gorm.DB) error {
+	// Check if we need to create any additional indexes
+	// GORM should handle most indexes from struct tags, but we can add custom ones here
+
+	// Example: Create composite index
+	if !db.Migrator().HasIndex(&models.Transaction{}, "idx_user_status") {
+		if err := db.Exec("CREATE INDEX idx_user_status ON transactions (user_id, status)").Error; err != nil {
+			return fmt.Errorf("failed to create composite index: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// uuidBackfillBatch is how many transactions backfillUUIDs updates per query
+const uuidBackfillBatch = 500
+
+// backfillUUIDs assigns a public UUID to transactions created before UUIDs
+// were introduced
+func backfillUUIDs(db *gorm.DB) error {
+	for {
+		var ids []uint
+		if err := db.Model(&models.Transaction{}).Where("uuid IS NULL").Limit(uuidBackfillBatch).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		for _, id := range ids {
+			if err := db.Model(&models.Transaction{}).Where("id = ?", id).Update("uuid", utils.NewUUID()).Error; err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// dropCorrectionTokens drops the confirm token hash corrections were
+// approved with before the admin proxy identified the operators. The column
+// is not null, so inserts leaving it out would fail.
+func dropCorrectionTokens(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&models.Correction{}, "token_hash") {
+		return nil
+	}
+	return db.Migrator().DropColumn(&models.Correction{}, "token_hash")
+}
+
+func migrateStatus(db *gorm.DB, amount config.AmountConfig) error {
+	fmt.Println("📊 Migration Status")
+	fmt.Println("==================")
+
+	// Check if tables exist
+	tables := []interface{}{
+		&models.User{},
+		&models.Transaction{},
+		&models.Budget{},
+		&models.ProcessedMessage{},
+	}
+
+	for _, table := range tables {
+		if db.Migrator().HasTable(table) {
+			fmt.Printf("✅ Table exists: %T\n", table)
+
+			// Get column info
+			columnTypes, err := db.Migrator().ColumnTypes(table)
+			if err == nil {
+				fmt.Printf("   Columns: %d\n", len(columnTypes))
+			}
+
+			// Check indexes
+			indexes, err := db.Migrator().GetIndexes(table)
+			if err == nil {
+				fmt.Printf("   Indexes: %d\n", len(indexes))
+			}
+		} else {
+			fmt.Printf("❌ Table missing: %T\n", table)
+		}
+	}
+
+	version, err := database.ConfiguredSchemaVersion(db, amount.Precision, amount.Scale)
+	if err != nil {
+		return err
+	}
+	var latest []models.SchemaMigration
+	if db.Migrator().HasTable(&models.SchemaMigration{}) {
+		if err := db.Order("id DESC").Limit(1).Find(&latest).Error; err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Schema version: %s (this binary)\n", version)
+	if len(latest) > 0 {
+		fmt.Printf("   Migrated to: %s at %s\n", latest[0].Version, latest[0].AppliedAt.UTC().Format(time.RFC3339))
+	} else {
+		fmt.Println("   Migrated to: not recorded")
+	}
+
+	fmt.Println("==================")
+	return nil
+}
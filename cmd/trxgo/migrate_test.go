@@ -0,0 +1,338 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"interview/internal/config"
+	"interview/internal/database"
+	"interview/internal/models"
+	"interview/pkg/utils"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectDB(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.DatabaseConfig
+		verbose  bool
+		wantErr  bool
+	}{
+		{
+			name: "Valid config verbose true",
+			cfg: config.DatabaseConfig{
+				Host:     "localhost",
+				Port:     3306,
+				User:     "root",
+				Password: "password",
+				Name:     "test_db",
+			},
+			verbose: true,
+			wantErr: true, // Will fail because we don't have MySQL running in test
+		},
+		{
+			name: "Valid config verbose false",
+			cfg: config.DatabaseConfig{
+				Host:     "localhost",
+				Port:     3306,
+				User:     "root",
+				Password: "password",
+				Name:     "test_db",
+			},
+			verbose: false,
+			wantErr: true, // Will fail because we don't have MySQL running in test
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := connectDB(tt.cfg, tt.verbose)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, db)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, db)
+			}
+		})
+	}
+}
+
+// testAmount is the default amount column configuration
+var testAmount = config.AmountConfig{Precision: 15, Scale: 2}
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func TestMigrateUp(t *testing.T) {
+	db := setupTestDB(t)
+
+	err := migrateUp(db, testAmount)
+	assert.NoError(t, err)
+
+	// Check if table was created
+	assert.True(t, db.Migrator().HasTable(&models.Transaction{}))
+}
+
+func TestMigrateDown(t *testing.T) {
+	db := setupTestDB(t)
+
+	// First create the table
+	err := migrateUp(db, testAmount)
+	require.NoError(t, err)
+	assert.True(t, db.Migrator().HasTable(&models.Transaction{}))
+
+	// Then drop it
+	err = migrateDown(db)
+	assert.NoError(t, err)
+	assert.False(t, db.Migrator().HasTable(&models.Transaction{}))
+}
+
+func TestMigrateReset(t *testing.T) {
+	db := setupTestDB(t)
+
+	// First create the table
+	err := migrateUp(db, testAmount)
+	require.NoError(t, err)
+	assert.True(t, db.Migrator().HasTable(&models.Transaction{}))
+
+	// Reset should drop and recreate
+	err = migrateReset(db, testAmount)
+	assert.NoError(t, err)
+	assert.True(t, db.Migrator().HasTable(&models.Transaction{}))
+}
+
+func TestMigrateUp_BackfillsUUIDs(t *testing.T) {
+	db := setupTestDB(t)
+
+	require.NoError(t, db.AutoMigrate(&models.Transaction{}))
+	for _, userID := range []uint{1, 2} {
+		require.NoError(t, db.Create(&models.Transaction{UserID: userID, Amount: decimal.NewFromInt(10)}).Error)
+	}
+
+	require.NoError(t, migrateUp(db, testAmount))
+
+	var transactions []models.Transaction
+	require.NoError(t, db.Find(&transactions).Error)
+	require.Len(t, transactions, 2)
+	for _, transaction := range transactions {
+		require.NotNil(t, transaction.UUID)
+		assert.True(t, utils.IsUUID(*transaction.UUID))
+	}
+	assert.NotEqual(t, *transactions[0].UUID, *transactions[1].UUID)
+}
+
+func TestMigrateUp_DropsCorrectionTokens(t *testing.T) {
+	db := setupTestDB(t)
+
+	require.NoError(t, db.AutoMigrate(&models.Correction{}))
+	require.NoError(t, db.Exec("ALTER TABLE corrections ADD COLUMN `token_hash` varchar(64) NOT NULL DEFAULT ''").Error)
+
+	require.NoError(t, migrateUp(db, testAmount))
+
+	assert.False(t, db.Migrator().HasColumn(&models.Correction{}, "token_hash"))
+}
+
+func TestCreateIndexes(t *testing.T) {
+	db := setupTestDB(t)
+
+	// First create the table
+	err := db.AutoMigrate(&models.Transaction{})
+	require.NoError(t, err)
+
+	err = createIndexes(db)
+	assert.NoError(t, err)
+}
+
+func TestMigrateStatus(t *testing.T) {
+	db := setupTestDB(t)
+
+	// Test with no tables
+	err := migrateStatus(db, testAmount)
+	assert.NoError(t, err)
+
+	// Test with tables
+	err = migrateUp(db, testAmount)
+	require.NoError(t, err)
+
+	err = migrateStatus(db, testAmount)
+	assert.NoError(t, err)
+}
+
+func TestMigrateDown_NoTable(t *testing.T) {
+	db := setupTestDB(t)
+
+	// Try to drop table that doesn't exist
+	err := migrateDown(db)
+	// SQLite doesn't error on DROP TABLE IF NOT EXISTS behavior
+	// This test ensures the function handles missing tables gracefully
+	assert.NoError(t, err)
+}
+
+func TestMigrateUp_MultipleRuns(t *testing.T) {
+	db := setupTestDB(t)
+
+	// Run migration multiple times
+	err := migrateUp(db, testAmount)
+	assert.NoError(t, err)
+
+	err = migrateUp(db, testAmount)
+	assert.NoError(t, err) // Should not error on second run
+
+	assert.True(t, db.Migrator().HasTable(&models.Transaction{}))
+}
+
+func TestCreateIndexes_AlreadyExists(t *testing.T) {
+	db := setupTestDB(t)
+
+	// Create table first
+	err := db.AutoMigrate(&models.Transaction{})
+	require.NoError(t, err)
+
+	// Run createIndexes multiple times
+	err = createIndexes(db)
+	assert.NoError(t, err)
+
+	err = createIndexes(db)
+	assert.NoError(t, err) // Should not error if index already exists
+}
+
+func TestMigrateVerify(t *testing.T) {
+	db := setupTestDB(t)
+	cfg := &config.Config{Amount: config.AmountConfig{Precision: 15, Scale: 2}}
+
+	err := migrateVerify(db, cfg, false, 0)
+	assert.NoError(t, err)
+
+	// Nothing is kept after verification
+	assert.False(t, db.Migrator().HasTable(&models.Transaction{}))
+	assert.False(t, db.Migrator().HasTable(&models.Budget{}))
+}
+
+func TestMigrateVerify_ExistingSchema(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Transaction{}))
+	cfg := &config.Config{Amount: config.AmountConfig{Precision: 15, Scale: 2}}
+
+	err := migrateVerify(db, cfg, false, 0)
+	assert.NoError(t, err)
+
+	assert.True(t, db.Migrator().HasTable(&models.Transaction{}))
+	assert.False(t, db.Migrator().HasTable(&models.Budget{}))
+	assert.False(t, db.Migrator().HasIndex(&models.Transaction{}, "idx_user_status"))
+}
+
+func TestMigrateVerify_ReportsErrors(t *testing.T) {
+	db := setupTestDB(t)
+	cfg := &config.Config{Amount: config.AmountConfig{Precision: 2, Scale: 4}}
+
+	err := migrateVerify(db, cfg, false, 0)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid amount column type")
+}
+
+func TestMigrateUp_RecordsSchemaVersion(t *testing.T) {
+	db := setupTestDB(t)
+
+	require.NoError(t, migrateUp(db, testAmount))
+	require.NoError(t, migrateUp(db, testAmount))
+
+	version, err := database.ConfiguredSchemaVersion(db, testAmount.Precision, testAmount.Scale)
+	require.NoError(t, err)
+	var recorded []models.SchemaMigration
+	require.NoError(t, db.Find(&recorded).Error)
+	require.Len(t, recorded, 1)
+	assert.Equal(t, version, recorded[0].Version)
+	assert.NoError(t, database.CheckSchemaVersion(db, version))
+
+	// Dropped with the other tables
+	require.NoError(t, migrateDown(db))
+	assert.False(t, db.Migrator().HasTable(&models.SchemaMigration{}))
+}
+
+func TestMigrateUp_SchemaVersionMatchesServer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trxgo.db")
+	// Each connection stands for a process, with its own parsed schemas
+	open := func() *gorm.DB {
+		db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		require.NoError(t, err)
+		return db
+	}
+	amount := config.AmountConfig{Precision: 18, Scale: 4}
+
+	require.NoError(t, migrateUp(open(), amount))
+
+	// The server and the worker check the version before configuring the
+	// amount column; the migrated database must pass and keep one version
+	server := open()
+	version, err := database.CheckConfiguredSchema(server, amount.Precision, amount.Scale)
+	require.NoError(t, err)
+	require.NoError(t, database.ConfigureAmountColumn(server, amount.Precision, amount.Scale))
+	require.NoError(t, database.RecordSchemaVersion(server, version))
+
+	require.NoError(t, migrateUp(open(), amount))
+	var recorded []models.SchemaMigration
+	require.NoError(t, server.Find(&recorded).Error)
+	require.Len(t, recorded, 1)
+	assert.Equal(t, version, recorded[0].Version)
+}
+
+func TestShadowTableOrder(t *testing.T) {
+	keys := []foreignKey{
+		{Name: "fk_transactions_user", Table: "transactions", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}},
+		{Name: "fk_transactions_parent", Table: "transactions", Columns: []string{"parent_id"}, RefTable: "transactions", RefColumns: []string{"id"}},
+		{Name: "fk_case_pins_case", Table: "case_pins", Columns: []string{"case_id"}, RefTable: "cases", RefColumns: []string{"id"}},
+		{Name: "fk_case_pins_transaction", Table: "case_pins", Columns: []string{"transaction_id"}, RefTable: "transactions", RefColumns: []string{"id"}},
+	}
+
+	order := shadowTableOrder([]string{"case_pins", "cases", "transactions", "users"}, keys)
+	assert.Equal(t, []string{"cases", "users", "transactions", "case_pins"}, order)
+
+	// Tables in a cycle still get copied
+	cycle := []foreignKey{
+		{Table: "a", RefTable: "b"},
+		{Table: "b", RefTable: "a"},
+	}
+	assert.Equal(t, []string{"c", "a", "b"}, shadowTableOrder([]string{"a", "b", "c"}, cycle))
+}
+
+func TestShadowCopySQL(t *testing.T) {
+	keys := []foreignKey{
+		{Name: "fk_transactions_user", Table: "transactions", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}},
+		{Name: "fk_transactions_parent", Table: "transactions", Columns: []string{"parent_id"}, RefTable: "transactions", RefColumns: []string{"id"}},
+	}
+
+	assert.Equal(t, "INSERT INTO `shadow`.`transactions` SELECT * FROM `transactions`", shadowCopySQL("shadow", "transactions", keys, 0))
+	assert.Equal(t, "INSERT INTO `shadow`.`users` SELECT * FROM `users` LIMIT 100", shadowCopySQL("shadow", "users", keys, 100))
+	assert.Equal(t,
+		"INSERT INTO `shadow`.`transactions` SELECT * FROM `transactions` WHERE ((`user_id`) IN (SELECT `id` FROM `shadow`.`users`) OR `user_id` IS NULL) LIMIT 100",
+		shadowCopySQL("shadow", "transactions", keys, 100))
+}
+
+func TestForeignKeyAddSQL(t *testing.T) {
+	key := foreignKey{
+		Name:       "fk_case_pins_case",
+		Table:      "case_pins",
+		Columns:    []string{"case_id"},
+		RefTable:   "cases",
+		RefColumns: []string{"id"},
+		UpdateRule: "CASCADE",
+		DeleteRule: "RESTRICT",
+	}
+
+	assert.Equal(t,
+		"ALTER TABLE `shadow`.`case_pins` ADD CONSTRAINT `fk_case_pins_case` FOREIGN KEY (`case_id`) REFERENCES `shadow`.`cases` (`id`) ON UPDATE CASCADE ON DELETE RESTRICT",
+		key.addSQL("shadow"))
+}
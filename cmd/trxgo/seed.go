@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"interview/internal/config"
+	"interview/internal/generator"
+	"interview/internal/models"
+	"interview/internal/repositories"
+	"interview/internal/services"
+	"interview/pkg/utils"
+)
+
+// generateOptions configure the data trxgo seed generates
+type generateOptions struct {
+	users        int
+	transactions int
+	months       int
+	seed         int64
+	currency     string
+	scale        int32
+}
+
+// generateBatchSize is how many generated transactions are inserted per
+// database transaction
+const generateBatchSize = 1000
+
+// newSeedCommand builds trxgo seed
+func newSeedCommand(a *app) *cobra.Command {
+	var file string
+	opts := generateOptions{}
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Load a fixture or generate realistic data",
+		Long: `Load a fixture exported from GET /api/admin/fixtures with --file, or generate
+realistic users, budgets and transactions with --transactions for dashboards
+and performance tests. Generated transactions are spread over the last
+--months, mostly during business hours, with log-normal amounts, about 85%
+successful, 10% pending and 5% failed, and a few users making most of them.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file != "" {
+				return runSeed(a.cfg, file)
+			}
+			opts.currency = a.cfg.Amount.DefaultCurrency
+			opts.scale = int32(a.cfg.Amount.CurrencyScales[opts.currency])
+			if !cmd.Flags().Changed("random-seed") {
+				opts.seed = time.Now().UnixNano()
+			}
+			return runGenerate(cmd.OutOrStdout(), a.cfg, opts)
+		},
+	}
+	cmd.Flags().StringVar(&file, "file", "", "Fixture file exported from GET /api/admin/fixtures")
+	cmd.Flags().IntVar(&opts.transactions, "transactions", 0, "Number of transactions to generate")
+	cmd.Flags().IntVar(&opts.users, "users", 100, "Number of users to generate the transactions for")
+	cmd.Flags().IntVar(&opts.months, "months", 6, "Number of months to spread the generated transactions over")
+	cmd.Flags().Int64Var(&opts.seed, "random-seed", 0, "Seed to generate the same data again (default random)")
+	cmd.MarkFlagsOneRequired("file", "transactions")
+	cmd.MarkFlagsMutuallyExclusive("file", "transactions")
+	return cmd
+}
+
+// runSeed loads a fixture file into the configured database
+func runSeed(cfg *config.Config, file string) error {
+	fixture, err := readFixture(file)
+	if err != nil {
+		return fmt.Errorf("failed to read fixture: %v", err)
+	}
+
+	db, err := gorm.Open(mysql.Open(cfg.Database.GetDSN()), &gorm.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	fmt.Printf("📥 Loading %d transactions and %d budgets into %s...\n",
+		len(fixture.Transactions), len(fixture.Budgets), cfg.Database.Name)
+
+	if err := loadFixture(db, fixture); err != nil {
+		return fmt.Errorf("failed to load fixture: %v", err)
+	}
+
+	fmt.Println("✅ Fixture loaded successfully")
+	return nil
+}
+
+// readFixture reads and decodes a fixture file
+func readFixture(path string) (*models.Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixture models.Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture file: %w", err)
+	}
+
+	return &fixture, nil
+}
+
+// loadFixture imports a fixture in a single database transaction
+func loadFixture(db *gorm.DB, fixture *models.Fixture) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		service := services.NewFixtureService(
+			repositories.NewTransactionRepository(tx),
+			repositories.NewBudgetRepository(tx),
+			repositories.NewUserRepository(tx),
+		)
+		return service.Load(context.Background(), fixture)
+	})
+}
+
+// runGenerate generates data into the configured database
+func runGenerate(w io.Writer, cfg *config.Config, opts generateOptions) error {
+	switch {
+	case opts.users <= 0:
+		return fmt.Errorf("users must be positive")
+	case opts.transactions <= 0:
+		return fmt.Errorf("transactions must be positive")
+	case opts.months <= 0:
+		return fmt.Errorf("months must be positive")
+	}
+
+	db, err := gorm.Open(mysql.Open(cfg.Database.GetDSN()), &gorm.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	fmt.Fprintf(w, "🎲 Generating %d users and %d transactions over %d months into %s (seed %d)...\n",
+		opts.users, opts.transactions, opts.months, cfg.Database.Name, opts.seed)
+
+	if err := loadGenerated(w, db, generator.New(opts.seed, time.Now(), opts.months), opts); err != nil {
+		return fmt.Errorf("failed to generate data: %v", err)
+	}
+
+	fmt.Fprintln(w, "✅ Data generated successfully")
+	return nil
+}
+
+// loadGenerated inserts generated users with their budgets and then the
+// generated transactions in batches, reporting progress to w
+func loadGenerated(w io.Writer, db *gorm.DB, gen *generator.Generator, opts generateOptions) error {
+	var existing int64
+	if err := db.Model(&models.User{}).Count(&existing).Error; err != nil {
+		return fmt.Errorf("failed to count users: %v", err)
+	}
+
+	userIDs := make([]uint, 0, opts.users)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		users := repositories.NewUserRepository(tx)
+		budgets := repositories.NewBudgetRepository(tx)
+		for i := 0; i < opts.users; i++ {
+			// Numbering after the existing users keeps emails unique across runs
+			user := gen.User(int(existing) + i + 1)
+			if err := users.Create(context.Background(), &user); err != nil {
+				return fmt.Errorf("failed to create user: %v", err)
+			}
+			userIDs = append(userIDs, user.ID)
+			if budget := gen.Budget(user.ID, opts.currency); budget != nil {
+				if err := budgets.Upsert(context.Background(), budget); err != nil {
+					return fmt.Errorf("failed to create budget: %v", err)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	next := gen.Transactions(userIDs, opts.currency, opts.scale)
+	transactions := repositories.NewTransactionRepository(db)
+	for created := 0; created < opts.transactions; {
+		batch := make([]models.Transaction, min(generateBatchSize, opts.transactions-created))
+		for i := range batch {
+			batch[i] = next()
+			uuid := utils.NewUUID()
+			batch[i].UUID = &uuid
+		}
+		if err := transactions.CreateBatch(context.Background(), batch); err != nil {
+			return fmt.Errorf("failed to create transactions: %v", err)
+		}
+		created += len(batch)
+		fmt.Fprintf(w, "   %d/%d transactions\n", created, opts.transactions)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"interview/internal/generator"
+	"interview/internal/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSeedDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Transaction{}, &models.Budget{}))
+	return db
+}
+
+func TestReadFixture(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version":1,"transactions":[{"user_id":1,"amount":"10.5","status":"success"}],"budgets":[]}`), 0o644))
+
+	fixture, err := readFixture(path)
+	assert.NoError(t, err)
+	assert.Len(t, fixture.Transactions, 1)
+}
+
+func TestReadFixture_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	require.NoError(t, os.WriteFile(path, []byte(`not json`), 0o644))
+
+	_, err := readFixture(path)
+	assert.Error(t, err)
+
+	_, err = readFixture(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestLoadFixture(t *testing.T) {
+	db := setupSeedDB(t)
+
+	fixture := &models.Fixture{
+		Version: models.FixtureVersion,
+		Transactions: []models.Transaction{
+			{ID: 42, UserID: 1, Amount: decimal.NewFromFloat(10.5), Status: "success"},
+			{ID: 43, UserID: 2, Amount: decimal.NewFromFloat(20), Status: "pending"},
+		},
+		Budgets: []models.Budget{{UserID: 1, MonthlyCap: decimal.NewFromInt(100)}},
+	}
+
+	require.NoError(t, loadFixture(db, fixture))
+
+	var count int64
+	db.Model(&models.Transaction{}).Count(&count)
+	assert.Equal(t, int64(2), count)
+	db.Model(&models.Budget{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestLoadFixture_UnsupportedVersion(t *testing.T) {
+	db := setupSeedDB(t)
+
+	err := loadFixture(db, &models.Fixture{Version: 99})
+	assert.Error(t, err)
+}
+
+func TestLoadGenerated(t *testing.T) {
+	db := setupSeedDB(t)
+	require.NoError(t, db.AutoMigrate(&models.User{}))
+	require.NoError(t, db.Create(&models.User{Name: "Existing", Email: "ada.lovelace.1@example.com"}).Error)
+
+	opts := generateOptions{users: 20, transactions: 1500, months: 3, currency: "USD", scale: 2}
+	var out bytes.Buffer
+	require.NoError(t, loadGenerated(&out, db, generator.New(1, time.Now(), opts.months), opts))
+	assert.Contains(t, out.String(), "1000/1500 transactions")
+	assert.Contains(t, out.String(), "1500/1500 transactions")
+
+	var count int64
+	db.Model(&models.User{}).Count(&count)
+	assert.Equal(t, int64(21), count)
+	db.Model(&models.Transaction{}).Count(&count)
+	assert.Equal(t, int64(1500), count)
+	db.Model(&models.Transaction{}).Where("user_id = ?", 1).Count(&count)
+	assert.Zero(t, count, "transactions belong to generated users only")
+	db.Model(&models.Transaction{}).Where("created_at < ?", time.Now().AddDate(0, -1, 0)).Count(&count)
+	assert.Positive(t, count, "transactions are spread over the months")
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"interview/internal/server"
+)
+
+// newServeCommand builds trxgo serve, which runs the API server until it is
+// interrupted and then lets in-flight requests finish
+func newServeCommand(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Run the API server until interrupted",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := server.Run(cmd.Context(), a.cfg); err != nil {
+				return fmt.Errorf("server stopped: %v", err)
+			}
+			logrus.Info("Server stopped")
+			return nil
+		},
+	}
+}
@@ -5,22 +5,31 @@ import (
 	"log"
 
 	"interview/internal/config"
+	"interview/internal/database"
 	"interview/internal/models"
 
+	"github.com/spf13/cobra"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
-func main() {
+// newSetupCommand builds trxgo setup
+func newSetupCommand(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "setup",
+		Short: "Create the database and run the migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSetup(a.cfg)
+		},
+	}
+}
+
+// runSetup creates the configured database if needed and migrates it
+func runSetup(cfg *config.Config) error {
 	fmt.Println("🚀 Database Setup Tool")
 	fmt.Println("======================")
 
-	// Load config
-	cfg, err := config.Load()
-	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
-	}
-
 	// Connect without database name first to create database
 	if err := createDatabase(cfg.Database); err != nil {
 		log.Printf("Warning: Could not create database: %v", err)
@@ -39,22 +48,35 @@ func main() {
 
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		return fmt.Errorf("failed to connect to database: %v", err)
 	}
 
 	fmt.Println("✅ Connected to database")
 
 	// Run migrations
 	fmt.Println("🏗️  Running migrations...")
-	if err := db.AutoMigrate(&models.Transaction{}); err != nil {
-		log.Fatalf("Failed to migrate: %v", err)
+	err = database.WithMigrationLock(db, cfg.Database.MigrationLockTimeout, func() error {
+		if err := database.ConfigureAmountColumn(db, cfg.Amount.Precision, cfg.Amount.Scale); err != nil {
+			return fmt.Errorf("failed to configure amount column: %v", err)
+		}
+		if err := db.AutoMigrate(&models.User{}, &models.Transaction{}, &models.Budget{}, &models.Tag{}, &models.Case{}, &models.CasePin{}, &models.Correction{}, &models.ProcessedMessage{}); err != nil {
+			return fmt.Errorf("failed to migrate: %v", err)
+		}
+		if err := recordSchemaVersion(db, cfg.Amount); err != nil {
+			return fmt.Errorf("failed to record schema version: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	fmt.Println("✅ Database setup completed successfully!")
 	fmt.Println("")
 	fmt.Println("Next steps:")
-	fmt.Println("  1. Run 'make run' to start the server")
+	fmt.Println("  1. Run 'trxgo serve' to start the server")
 	fmt.Println("  2. Visit http://localhost:8080/health to test")
+	return nil
 }
 
 func createDatabase(cfg config.DatabaseConfig) error {
@@ -3,15 +3,10 @@ package main
 import (
 	"testing"
 
-	"gorm.io/driver/sqlite"
-	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
-
 	"interview/internal/config"
 	"interview/internal/models"
 
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/require"
 )
 
 func TestCreateDatabase(t *testing.T) {
@@ -56,14 +51,6 @@ func TestCreateDatabase(t *testing.T) {
 	}
 }
 
-func setupTestDB(t *testing.T) *gorm.DB {
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
-	})
-	require.NoError(t, err)
-	return db
-}
-
 func TestAutoMigrate(t *testing.T) {
 	db := setupTestDB(t)
 
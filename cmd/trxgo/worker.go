@@ -0,0 +1,371 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+
+	"interview/internal/config"
+	"interview/internal/database"
+	"interview/internal/models"
+	"interview/internal/repositories"
+	"interview/internal/requestid"
+	"interview/internal/services"
+	"interview/internal/validation"
+	"interview/internal/vault"
+)
+
+// retryDelay is how long to wait before retrying a message that failed for a transient reason
+const retryDelay = 5 * time.Second
+
+// shutdownTimeout bounds committing the last batch once the consumer is stopping
+const shutdownTimeout = 5 * time.Second
+
+// errInvalidMessage marks messages that can never be processed; they go to the dead letter queue
+var errInvalidMessage = errors.New("invalid message")
+
+// messageReader is the part of kafka.Reader used by the consumer
+type messageReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// messageWriter is the part of kafka.Writer used for the dead letter queue
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// consumer ingests CreateTransaction commands from a Kafka topic
+type consumer struct {
+	db         *gorm.DB
+	reader     messageReader
+	dlq        messageWriter
+	validator  *validator.Validate
+	amounts    services.AmountPolicy
+	retryDelay time.Duration
+
+	// batchSize messages are committed at once, or fewer once the oldest
+	// uncommitted one has waited commitInterval
+	batchSize      int
+	commitInterval time.Duration
+}
+
+// newWorkerCommand builds trxgo worker, which consumes queued creates
+// from Kafka until it is interrupted
+func newWorkerCommand(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "worker",
+		Short: "Create transactions from the Kafka topic until interrupted",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWorker(cmd.Context(), a.cfg)
+		},
+	}
+}
+
+// runWorker runs the transaction consumer until ctx is done
+func runWorker(ctx context.Context, cfg *config.Config) error {
+	var dbCredentials *database.Credentials
+	if cfg.Vault.Enabled() {
+		creds, stopCredentials, err := vault.StartDatabaseCredentials(cfg.Vault)
+		if err != nil {
+			return fmt.Errorf("failed to get database credentials from Vault: %v", err)
+		}
+		defer stopCredentials()
+		dbCredentials = creds
+	}
+
+	dialector, err := database.MySQLDialector(cfg.Database, dbCredentials)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{TranslateError: true})
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
+	err = database.WithMigrationLock(db, cfg.Database.MigrationLockTimeout, func() error {
+		if _, err := database.CheckConfiguredSchema(db, cfg.Amount.Precision, cfg.Amount.Scale); err != nil {
+			return err
+		}
+		if err := database.ConfigureAmountColumn(db, cfg.Amount.Precision, cfg.Amount.Scale); err != nil {
+			return fmt.Errorf("failed to configure amount column: %v", err)
+		}
+		if err := db.AutoMigrate(&models.ProcessedMessage{}); err != nil {
+			return fmt.Errorf("failed to migrate database: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	amountPolicy := services.AmountPolicy{
+		Precision:         cfg.Amount.Precision,
+		Scale:             cfg.Amount.Scale,
+		DefaultCurrency:   cfg.Amount.DefaultCurrency,
+		CurrencyScales:    cfg.Amount.CurrencyScales,
+		Rounding:          cfg.Amount.Rounding,
+		CurrencyRoundings: cfg.Amount.CurrencyRoundings,
+	}
+	if err := amountPolicy.Check(); err != nil {
+		return fmt.Errorf("invalid amount configuration: %v", err)
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: cfg.Kafka.Brokers,
+		GroupID: cfg.Kafka.GroupID,
+		Topic:   cfg.Kafka.Topic,
+	})
+	defer reader.Close()
+
+	dlq := &kafka.Writer{
+		Addr:     kafka.TCP(cfg.Kafka.Brokers...),
+		Topic:    cfg.Kafka.DLQTopic,
+		Balancer: &kafka.Hash{},
+	}
+	defer dlq.Close()
+
+	c := &consumer{
+		db:         db,
+		reader:     reader,
+		dlq:        dlq,
+		validator:  validation.New(),
+		amounts:    amountPolicy,
+		retryDelay: retryDelay,
+
+		batchSize:      cfg.Kafka.CommitBatchSize,
+		commitInterval: cfg.Kafka.CommitInterval,
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"brokers":   cfg.Kafka.Brokers,
+		"topic":     cfg.Kafka.Topic,
+		"group_id":  cfg.Kafka.GroupID,
+		"dlq_topic": cfg.Kafka.DLQTopic,
+		"batch":     cfg.Kafka.CommitBatchSize,
+	}).Info("Starting transaction consumer")
+
+	if err := c.run(ctx); err != nil {
+		return fmt.Errorf("consumer stopped: %v", err)
+	}
+	logrus.Info("Consumer stopped")
+	return nil
+}
+
+// run consumes messages until ctx is done. A message's offset is committed
+// only once it has been stored, skipped as a duplicate or dead-lettered.
+// Offsets are committed in batches; when ctx is done, the messages processed
+// so far are committed before run returns.
+func (c *consumer) run(ctx context.Context) error {
+	var pending []kafka.Message
+	var deadline time.Time
+	for {
+		// While a batch is open, stop waiting for more once it is due
+		fetchCtx, cancel := ctx, context.CancelFunc(func() {})
+		if len(pending) > 0 {
+			fetchCtx, cancel = context.WithDeadline(ctx, deadline)
+		}
+		msg, err := c.reader.FetchMessage(fetchCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return c.commitOnShutdown(pending)
+			}
+			if len(pending) > 0 && errors.Is(err, context.DeadlineExceeded) {
+				if err := c.commit(ctx, pending); err != nil {
+					return err
+				}
+				pending = nil
+				continue
+			}
+			return fmt.Errorf("failed to fetch message: %w", err)
+		}
+
+		if err := c.handle(ctx, msg); err != nil {
+			if ctx.Err() != nil {
+				return c.commitOnShutdown(pending)
+			}
+			return err
+		}
+
+		if len(pending) == 0 {
+			deadline = time.Now().Add(c.commitInterval)
+		}
+		pending = append(pending, msg)
+		if len(pending) >= c.batchSize {
+			if err := c.commit(ctx, pending); err != nil {
+				if ctx.Err() != nil {
+					return c.commitOnShutdown(pending)
+				}
+				return err
+			}
+			pending = nil
+		}
+	}
+}
+
+// commit commits the offsets of processed messages
+func (c *consumer) commit(ctx context.Context, msgs []kafka.Message) error {
+	if err := c.reader.CommitMessages(ctx, msgs...); err != nil {
+		return fmt.Errorf("failed to commit messages: %w", err)
+	}
+	return nil
+}
+
+// commitOnShutdown commits the last batch once ctx is done, so a restart
+// does not redeliver messages that were already processed
+func (c *consumer) commitOnShutdown(msgs []kafka.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return c.commit(ctx, msgs)
+}
+
+// handle processes a message, dead-lettering invalid ones and retrying
+// transient failures until ctx is done
+func (c *consumer) handle(ctx context.Context, msg kafka.Message) error {
+	for {
+		err := c.process(ctx, msg)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, errInvalidMessage) {
+			return c.deadLetter(ctx, msg, err)
+		}
+
+		logrus.WithError(err).WithFields(messageFields(msg)).Warn("Failed to process message, retrying")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.retryDelay):
+		}
+	}
+}
+
+// process validates a message and creates its transaction. The message key is
+// the idempotency key: the transaction and the processed marker are stored in
+// one database transaction, so a redelivered message is skipped.
+func (c *consumer) process(ctx context.Context, msg kafka.Message) error {
+	key := string(msg.Key)
+	if key == "" {
+		return fmt.Errorf("%w: missing message key", errInvalidMessage)
+	}
+
+	var req models.CreateTransactionRequest
+	decoder := json.NewDecoder(bytes.NewReader(msg.Value))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidMessage, err)
+	}
+	if err := c.validator.Struct(req); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidMessage, err)
+	}
+
+	ctx = messageContext(ctx, msg)
+	return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var processed int64
+		if err := tx.Model(&models.ProcessedMessage{}).Where("message_key = ? AND transaction_id <> 0", key).Count(&processed).Error; err != nil {
+			return fmt.Errorf("failed to check processed messages: %w", err)
+		}
+		if processed > 0 {
+			logrus.WithFields(messageFields(msg)).Info("Skipping already processed message")
+			return nil
+		}
+
+		service := services.NewTransactionService(repositories.NewTransactionRepository(tx),
+			services.WithAmountPolicy(c.amounts),
+			services.WithBudgetService(services.NewBudgetService(repositories.NewBudgetRepository(tx), services.WithBudgetAmountPolicy(c.amounts))),
+			services.WithUserService(services.NewUserService(repositories.NewUserRepository(tx))),
+		)
+		transaction, err := service.CreateTransaction(ctx, req)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidAmount) || errors.Is(err, services.ErrBudgetExceeded) || errors.Is(err, services.ErrBudgetCurrency) || errors.Is(err, services.ErrDuplicateReference) || errors.Is(err, services.ErrUserNotFound) {
+				return fmt.Errorf("%w: %v", errInvalidMessage, err)
+			}
+			return err
+		}
+
+		// Save replaces the failure recorded for an earlier, dead-lettered delivery
+		return tx.Save(&models.ProcessedMessage{MessageKey: key, TransactionID: transaction.ID}).Error
+	})
+}
+
+// deadLetter publishes a message that cannot be processed to the dead letter
+// queue, with the reason and its origin in headers
+func (c *consumer) deadLetter(ctx context.Context, msg kafka.Message, reason error) error {
+	logrus.WithError(reason).WithFields(messageFields(msg)).Warn("Moving message to dead letter queue")
+
+	err := c.dlq.WriteMessages(ctx, kafka.Message{
+		Key:   msg.Key,
+		Value: msg.Value,
+		Headers: []kafka.Header{
+			{Key: "error", Value: []byte(reason.Error())},
+			{Key: "original_topic", Value: []byte(msg.Topic)},
+			{Key: "original_partition", Value: []byte(strconv.Itoa(msg.Partition))},
+			{Key: "original_offset", Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write to dead letter queue: %w", err)
+	}
+
+	// Record the failure so async create jobs can report it
+	if len(msg.Key) > 0 {
+		failure := &models.ProcessedMessage{MessageKey: string(msg.Key), Error: truncate(reason.Error(), maxFailureLength)}
+		if err := c.db.Save(failure).Error; err != nil {
+			logrus.WithError(err).WithFields(messageFields(msg)).Warn("Failed to record dead-lettered message")
+		}
+	}
+	return nil
+}
+
+// maxFailureLength is the size of the processed_messages error column
+const maxFailureLength = 500
+
+// truncate shortens s to at most n bytes
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
+// messageContext returns ctx with the ID of the request that queued msg, so
+// the service and query logs for the message carry it
+func messageContext(ctx context.Context, msg kafka.Message) context.Context {
+	for _, header := range msg.Headers {
+		if header.Key == requestid.Key {
+			return requestid.NewContext(ctx, string(header.Value))
+		}
+	}
+	return ctx
+}
+
+// messageFields identifies a message in log records, with the ID of the
+// request that queued it when it has one
+func messageFields(msg kafka.Message) logrus.Fields {
+	fields := logrus.Fields{
+		"key":       string(msg.Key),
+		"topic":     msg.Topic,
+		"partition": msg.Partition,
+		"offset":    msg.Offset,
+	}
+	for _, header := range msg.Headers {
+		if header.Key == requestid.Key {
+			fields[requestid.Key] = string(header.Value)
+		}
+	}
+	return fields
+}
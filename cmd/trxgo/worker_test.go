@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"interview/internal/models"
+	"interview/internal/requestid"
+	"interview/internal/services"
+	"interview/internal/validation"
+)
+
+// fakeReader serves queued messages and blocks once they run out
+type fakeReader struct {
+	messages  []kafka.Message
+	committed []kafka.Message
+	commits   int
+	cancel    context.CancelFunc
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if len(r.messages) == 0 {
+		r.cancel()
+		<-ctx.Done()
+		return kafka.Message{}, ctx.Err()
+	}
+	msg := r.messages[0]
+	r.messages = r.messages[1:]
+	return msg, nil
+}
+
+func (r *fakeReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.committed = append(r.committed, msgs...)
+	r.commits++
+	return nil
+}
+
+// fakeWriter records messages written to the dead letter queue
+type fakeWriter struct {
+	written []kafka.Message
+}
+
+func (w *fakeWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	w.written = append(w.written, msgs...)
+	return nil
+}
+
+func setupConsumer(t *testing.T, messages ...kafka.Message) (*consumer, *fakeReader, *fakeWriter, context.Context) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.Transaction{}, &models.Budget{}, &models.ProcessedMessage{}))
+	require.NoError(t, db.Create(&models.User{ID: 1, Name: "Ada", Email: "ada@example.com"}).Error)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	reader := &fakeReader{messages: messages, cancel: cancel}
+	dlq := &fakeWriter{}
+	return &consumer{
+		db:         db,
+		reader:     reader,
+		dlq:        dlq,
+		validator:  validation.New(),
+		amounts:    services.DefaultAmountPolicy(),
+		retryDelay: time.Millisecond,
+
+		batchSize:      1,
+		commitInterval: time.Second,
+	}, reader, dlq, ctx
+}
+
+func message(key, value string) kafka.Message {
+	return kafka.Message{Topic: "transactions.create", Key: []byte(key), Value: []byte(value)}
+}
+
+func header(msg kafka.Message, key string) string {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func TestConsumer_CreatesTransaction(t *testing.T) {
+	c, reader, dlq, ctx := setupConsumer(t, message("cmd-1", `{"user_id":1,"amount":"100.50"}`))
+
+	require.NoError(t, c.run(ctx))
+
+	var transactions []models.Transaction
+	require.NoError(t, c.db.Find(&transactions).Error)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, uint(1), transactions[0].UserID)
+	assert.True(t, decimal.RequireFromString("100.50").Equal(transactions[0].Amount))
+
+	var processed models.ProcessedMessage
+	require.NoError(t, c.db.First(&processed, "message_key = ?", "cmd-1").Error)
+	assert.Equal(t, transactions[0].ID, processed.TransactionID)
+
+	assert.Len(t, reader.committed, 1)
+	assert.Empty(t, dlq.written)
+}
+
+func TestConsumer_SkipsDuplicateMessages(t *testing.T) {
+	c, reader, dlq, ctx := setupConsumer(t,
+		message("cmd-1", `{"user_id":1,"amount":"100"}`),
+		message("cmd-1", `{"user_id":1,"amount":"100"}`),
+	)
+
+	require.NoError(t, c.run(ctx))
+
+	var count int64
+	require.NoError(t, c.db.Model(&models.Transaction{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+	assert.Len(t, reader.committed, 2)
+	assert.Empty(t, dlq.written)
+}
+
+func TestConsumer_DeadLettersInvalidMessages(t *testing.T) {
+	tests := []struct {
+		name  string
+		msg   kafka.Message
+		error string
+	}{
+		{"missing key", message("", `{"user_id":1,"amount":"100"}`), "missing message key"},
+		{"malformed json", message("cmd-1", `{"user_id":`), "invalid message"},
+		{"unknown field", message("cmd-1", `{"user_id":1,"amount":"100","extra":true}`), "unknown field"},
+		{"failed validation", message("cmd-1", `{"user_id":1,"amount":"0"}`), "Amount"},
+		{"invalid amount", message("cmd-1", `{"user_id":1,"amount":"1.234"}`), "invalid amount"},
+		{"unknown user", message("cmd-1", `{"user_id":2,"amount":"100"}`), "user not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, reader, dlq, ctx := setupConsumer(t, tt.msg)
+
+			require.NoError(t, c.run(ctx))
+
+			require.Len(t, dlq.written, 1)
+			assert.Equal(t, tt.msg.Value, dlq.written[0].Value)
+			assert.Contains(t, header(dlq.written[0], "error"), tt.error)
+			assert.Equal(t, "transactions.create", header(dlq.written[0], "original_topic"))
+			assert.Len(t, reader.committed, 1)
+
+			var count int64
+			require.NoError(t, c.db.Model(&models.Transaction{}).Count(&count).Error)
+			assert.Zero(t, count)
+		})
+	}
+}
+
+func TestConsumer_DeadLettersBudgetRejections(t *testing.T) {
+	c, reader, dlq, ctx := setupConsumer(t, message("cmd-1", `{"user_id":1,"amount":"500"}`))
+	require.NoError(t, c.db.Create(&models.Budget{UserID: 1, MonthlyCap: decimal.NewFromInt(100), BlockOverage: true}).Error)
+
+	require.NoError(t, c.run(ctx))
+
+	require.Len(t, dlq.written, 1)
+	assert.Contains(t, header(dlq.written[0], "error"), "budget")
+	assert.Len(t, reader.committed, 1)
+
+	var processed models.ProcessedMessage
+	require.NoError(t, c.db.First(&processed, "message_key = ?", "cmd-1").Error)
+	assert.Zero(t, processed.TransactionID)
+	assert.Contains(t, processed.Error, "budget")
+}
+
+func TestConsumer_ReprocessesDeadLetteredMessages(t *testing.T) {
+	c, reader, dlq, ctx := setupConsumer(t, message("cmd-1", `{"user_id":1,"amount":"100"}`))
+	require.NoError(t, c.db.Create(&models.ProcessedMessage{MessageKey: "cmd-1", Error: "budget exceeded"}).Error)
+
+	require.NoError(t, c.run(ctx))
+
+	var processed models.ProcessedMessage
+	require.NoError(t, c.db.First(&processed, "message_key = ?", "cmd-1").Error)
+	assert.NotZero(t, processed.TransactionID)
+	assert.Empty(t, processed.Error)
+	assert.Len(t, reader.committed, 1)
+	assert.Empty(t, dlq.written)
+}
+
+func TestConsumer_CommitsInBatches(t *testing.T) {
+	c, reader, _, ctx := setupConsumer(t,
+		message("cmd-1", `{"user_id":1,"amount":"100"}`),
+		message("cmd-2", `{"user_id":1,"amount":"100"}`),
+		message("cmd-3", `{"user_id":1,"amount":"100"}`),
+	)
+	c.batchSize = 2
+
+	require.NoError(t, c.run(ctx))
+
+	// A full batch of two, then the remaining message on shutdown
+	assert.Len(t, reader.committed, 3)
+	assert.Equal(t, 2, reader.commits)
+}
+
+// idleReader waits for the fetch deadline once its messages run out, as an
+// idle topic does, before stopping the consumer
+type idleReader struct {
+	*fakeReader
+	idled             bool
+	committedWhenIdle int
+}
+
+func (r *idleReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if len(r.messages) == 0 && !r.idled {
+		r.idled = true
+		<-ctx.Done()
+		return kafka.Message{}, ctx.Err()
+	}
+	if len(r.messages) == 0 {
+		r.committedWhenIdle = len(r.committed)
+	}
+	return r.fakeReader.FetchMessage(ctx)
+}
+
+func TestConsumer_CommitsPartialBatchAfterInterval(t *testing.T) {
+	c, reader, _, ctx := setupConsumer(t, message("cmd-1", `{"user_id":1,"amount":"100"}`))
+	idle := &idleReader{fakeReader: reader}
+	c.reader = idle
+	c.batchSize = 10
+	c.commitInterval = 10 * time.Millisecond
+
+	require.NoError(t, c.run(ctx))
+
+	assert.Equal(t, 1, idle.committedWhenIdle, "the partial batch is committed once the interval passes")
+	assert.Equal(t, 1, reader.commits)
+}
+
+func TestMessageFields_RequestID(t *testing.T) {
+	msg := message("cmd-1", "{}")
+	assert.NotContains(t, messageFields(msg), "request_id")
+
+	msg.Headers = []kafka.Header{{Key: "request_id", Value: []byte("req-1")}}
+	fields := messageFields(msg)
+	assert.Equal(t, "req-1", fields["request_id"])
+	assert.Equal(t, "cmd-1", fields["key"])
+}
+
+func TestMessageContext_RequestID(t *testing.T) {
+	msg := message("cmd-1", "{}")
+	assert.Empty(t, requestid.FromContext(messageContext(context.Background(), msg)))
+
+	msg.Headers = []kafka.Header{{Key: "request_id", Value: []byte("req-1")}}
+	assert.Equal(t, "req-1", requestid.FromContext(messageContext(context.Background(), msg)))
+}
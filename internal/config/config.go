@@ -2,8 +2,13 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"interview/pkg/money"
 
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
@@ -11,9 +16,21 @@ import (
 
 // Config represents application configuration
 type Config struct {
-	Database DatabaseConfig `json:"database"`
-	Server   ServerConfig   `json:"server"`
-	Log      LogConfig      `json:"log"`
+	// Profile is the APP_ENV profile whose defaults were applied, or ""
+	Profile string `json:"profile"`
+
+	Database    DatabaseConfig    `json:"database"`
+	Server      ServerConfig      `json:"server"`
+	Log         LogConfig         `json:"log"`
+	Dashboard   DashboardConfig   `json:"dashboard"`
+	Transaction TransactionConfig `json:"transaction"`
+	Amount      AmountConfig      `json:"amount"`
+	Kafka       KafkaConfig       `json:"kafka"`
+	Redis       RedisConfig       `json:"redis"`
+	Vault       VaultConfig       `json:"vault"`
+	Anomaly     AnomalyConfig     `json:"anomaly"`
+	Probe       ProbeConfig       `json:"probe"`
+	Routes      RoutesConfig      `json:"routes"`
 }
 
 // DatabaseConfig represents database configuration
@@ -23,12 +40,92 @@ type DatabaseConfig struct {
 	User     string `json:"user"`
 	Password string `json:"password"`
 	Name     string `json:"name"`
+
+	ReplicaHost string        `json:"replica_host"`
+	ReplicaPort int           `json:"replica_port"`
+	HedgeDelay  time.Duration `json:"hedge_delay"`
+
+	ReplicaMaxLag           time.Duration `json:"replica_max_lag"`
+	ReplicaLagCheckInterval time.Duration `json:"replica_lag_check_interval"`
+
+	SlowQueryThreshold time.Duration `json:"slow_query_threshold"`
+	ExplainSlowQueries bool          `json:"explain_slow_queries"`
+
+	// DashboardMaxOpenConns sizes the separate connection pool dashboard
+	// queries run on; 0 runs them on the main pool
+	DashboardMaxOpenConns int `json:"dashboard_max_open_conns"`
+
+	// FailoverHosts are host:port addresses to fail over to, in order, when
+	// the primary stops answering or turns read-only
+	FailoverHosts         []string      `json:"failover_hosts"`
+	FailoverCheckInterval time.Duration `json:"failover_check_interval"`
+
+	// MigrationLockTimeout is how long a migrator waits for another one to
+	// finish before giving up
+	MigrationLockTimeout time.Duration `json:"migration_lock_timeout"`
 }
 
 // ServerConfig represents server configuration
 type ServerConfig struct {
 	Host string `json:"host"`
 	Port string `json:"port"`
+
+	AdminHost string `json:"admin_host"`
+	AdminPort string `json:"admin_port"`
+	// AdminOperatorHeader is the request header the authenticating proxy
+	// in front of the admin listener sets to the operator's identity
+	AdminOperatorHeader string `json:"admin_operator_header"`
+
+	// UIEnabled serves the embedded admin UI at /ui on the public listener
+	UIEnabled bool `json:"ui_enabled"`
+
+	// ReadyTimeout bounds each dependency check of /health/ready
+	ReadyTimeout time.Duration `json:"ready_timeout"`
+
+	// Connection limits of both listeners, so slow or idle clients cannot
+	// hold connections open. Responses that stream or long-poll extend their
+	// own write deadline past WriteTimeout.
+	ReadHeaderTimeout time.Duration `json:"read_header_timeout"`
+	ReadTimeout       time.Duration `json:"read_timeout"`
+	WriteTimeout      time.Duration `json:"write_timeout"`
+	IdleTimeout       time.Duration `json:"idle_timeout"`
+	MaxHeaderBytes    int           `json:"max_header_bytes"`
+
+	// StrictJSON rejects JSON request bodies with fields the endpoint does
+	// not accept
+	StrictJSON bool `json:"strict_json"`
+
+	// TrustedProxies are the IP addresses and CIDR ranges whose
+	// X-Forwarded-For headers set a request's client IP. nil trusts every
+	// proxy and an empty list trusts none.
+	TrustedProxies []string `json:"trusted_proxies"`
+}
+
+// RouteGroups names the route groups whose middleware RoutesConfig configures
+var RouteGroups = []string{"transactions", "dashboard", "budgets", "users", "reconciliation", "cases", "meta", "admin", "health", "ui"}
+
+// RouteGroupConfig configures the middleware of one route group
+type RouteGroupConfig struct {
+	// LogLevel is the level of the group's request log records, or "none"
+	// to not log its requests
+	LogLevel string `json:"log_level"`
+	// CacheMaxAge, when positive, lets clients cache the group's successful
+	// GET responses for that long
+	CacheMaxAge time.Duration `json:"cache_max_age"`
+}
+
+// RoutesConfig configures route groups by name
+type RoutesConfig map[string]RouteGroupConfig
+
+// DefaultRouteGroup is the configuration of groups RoutesConfig leaves out
+var DefaultRouteGroup = RouteGroupConfig{LogLevel: "info"}
+
+// Group returns the configuration of the named route group
+func (r RoutesConfig) Group(name string) RouteGroupConfig {
+	if group, ok := r[name]; ok {
+		return group
+	}
+	return DefaultRouteGroup
 }
 
 // LogConfig represents logging configuration
@@ -36,6 +133,82 @@ type LogConfig struct {
 	Level string `json:"level"`
 }
 
+// DashboardConfig represents dashboard configuration
+type DashboardConfig struct {
+	CacheEnabled         bool          `json:"cache_enabled"`
+	CacheRefreshInterval time.Duration `json:"cache_refresh_interval"`
+}
+
+// TransactionConfig represents transaction endpoint configuration
+type TransactionConfig struct {
+	StatusCacheTTL time.Duration `json:"status_cache_ttl"`
+	// AsyncCreate enables POST /api/transactions?async=true, which queues
+	// creates on the Kafka topic the consumer reads
+	AsyncCreate bool `json:"async_create"`
+}
+
+// AmountConfig represents amount column and currency configuration
+type AmountConfig struct {
+	Precision       int            `json:"precision"`
+	Scale           int            `json:"scale"`
+	DefaultCurrency string         `json:"default_currency"`
+	CurrencyScales  map[string]int `json:"currency_scales"`
+	// Rounding rounds sums, averages and other derived amounts
+	Rounding money.Rounding `json:"rounding"`
+	// CurrencyRoundings overrides Rounding for the aggregates of one currency
+	CurrencyRoundings map[string]money.Rounding `json:"currency_roundings,omitempty"`
+}
+
+// KafkaConfig represents configuration of the Kafka transaction consumer
+type KafkaConfig struct {
+	Brokers  []string `json:"brokers"`
+	Topic    string   `json:"topic"`
+	GroupID  string   `json:"group_id"`
+	DLQTopic string   `json:"dlq_topic"`
+
+	// CommitBatchSize is how many processed messages the consumer commits
+	// at once; CommitInterval bounds how long a partial batch waits
+	CommitBatchSize int           `json:"commit_batch_size"`
+	CommitInterval  time.Duration `json:"commit_interval"`
+}
+
+// RedisConfig represents the Redis server instances share state through
+type RedisConfig struct {
+	Addr     string `json:"addr"`
+	Password string `json:"password"`
+	DB       int    `json:"db"`
+}
+
+// VaultConfig represents the Vault database secrets engine role database
+// credentials are requested from
+type VaultConfig struct {
+	Addr  string `json:"addr"`
+	Token string `json:"token"`
+	Mount string `json:"mount"`
+	Role  string `json:"role"`
+}
+
+// AnomalyConfig represents the transaction volume anomaly detector
+type AnomalyConfig struct {
+	Enabled bool `json:"enabled"`
+	// Window is the span of per-minute samples the baseline mostly reflects
+	Window time.Duration `json:"window"`
+	// Threshold is how many standard deviations from the baseline a minute
+	// must be to raise an alert
+	Threshold float64 `json:"threshold"`
+	// MinRate is the transactions per minute the baseline must average
+	// before anything is flagged, so quiet periods do not raise alerts
+	MinRate float64 `json:"min_rate"`
+}
+
+// ProbeConfig represents the synthetic transaction probe
+type ProbeConfig struct {
+	// Interval is how often the probe runs; zero disables it
+	Interval time.Duration `json:"interval"`
+	// UserID is the existing user synthetic transactions belong to
+	UserID uint `json:"user_id"`
+}
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	// Load .env file if it exists
@@ -43,26 +216,371 @@ func Load() (*Config, error) {
 		logrus.Debug("No .env file found, using environment variables")
 	}
 
+	// Settings stored in a secret manager take precedence over the environment
+	values, err := loadSecretValues()
+	if err != nil {
+		return nil, err
+	}
+	secretValues = values
+
+	// Defaults of the selected profile apply to every setting read below
+	profile, err := loadProfile()
+	if err != nil {
+		return nil, err
+	}
+
 	dbPort, err := strconv.Atoi(getEnv("DB_PORT", "3306"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid DB_PORT: %v", err)
 	}
 
+	replicaPort, err := strconv.Atoi(getEnv("DB_REPLICA_PORT", strconv.Itoa(dbPort)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_REPLICA_PORT: %v", err)
+	}
+
+	hedgeDelay, err := time.ParseDuration(getEnv("DB_HEDGE_DELAY", "0s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_HEDGE_DELAY: %v", err)
+	}
+	if hedgeDelay < 0 {
+		return nil, fmt.Errorf("invalid DB_HEDGE_DELAY: %s is negative", hedgeDelay)
+	}
+
+	replicaMaxLag, err := time.ParseDuration(getEnv("DB_REPLICA_MAX_LAG", "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_REPLICA_MAX_LAG: %v", err)
+	}
+
+	replicaLagCheckInterval, err := parsePositiveDuration("DB_REPLICA_LAG_CHECK_INTERVAL", "5s")
+	if err != nil {
+		return nil, err
+	}
+
+	slowQueryThreshold, err := time.ParseDuration(getEnv("DB_SLOW_QUERY_THRESHOLD", "200ms"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_SLOW_QUERY_THRESHOLD: %v", err)
+	}
+
+	explainSlowQueries, err := strconv.ParseBool(getEnv("DB_EXPLAIN_SLOW_QUERIES", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_EXPLAIN_SLOW_QUERIES: %v", err)
+	}
+
+	dashboardMaxOpenConns, err := strconv.Atoi(getEnv("DB_DASHBOARD_MAX_OPEN_CONNS", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_DASHBOARD_MAX_OPEN_CONNS: %v", err)
+	}
+	if dashboardMaxOpenConns < 0 {
+		return nil, fmt.Errorf("invalid DB_DASHBOARD_MAX_OPEN_CONNS: %d is negative", dashboardMaxOpenConns)
+	}
+
+	var failoverHosts []string
+	for _, host := range parseList(getEnv("DB_FAILOVER_HOSTS", "")) {
+		if !strings.Contains(host, ":") {
+			host += ":" + strconv.Itoa(dbPort)
+		}
+		failoverHosts = append(failoverHosts, host)
+	}
+
+	failoverCheckInterval, err := time.ParseDuration(getEnv("DB_FAILOVER_CHECK_INTERVAL", "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_FAILOVER_CHECK_INTERVAL: %v", err)
+	}
+	if failoverCheckInterval <= 0 {
+		return nil, fmt.Errorf("invalid DB_FAILOVER_CHECK_INTERVAL: %s is not positive", failoverCheckInterval)
+	}
+
+	migrationLockTimeout, err := time.ParseDuration(getEnv("DB_MIGRATION_LOCK_TIMEOUT", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_MIGRATION_LOCK_TIMEOUT: %v", err)
+	}
+	if migrationLockTimeout <= 0 {
+		return nil, fmt.Errorf("invalid DB_MIGRATION_LOCK_TIMEOUT: %s is not positive", migrationLockTimeout)
+	}
+
+	uiEnabled, err := strconv.ParseBool(getEnv("UI_ENABLED", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid UI_ENABLED: %v", err)
+	}
+
+	strictJSON, err := strconv.ParseBool(getEnv("STRICT_JSON", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid STRICT_JSON: %v", err)
+	}
+
+	trustedProxies, err := parseTrustedProxies(getEnv("TRUSTED_PROXIES", "*"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	readyTimeout, err := time.ParseDuration(getEnv("HEALTH_READY_TIMEOUT", "2s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HEALTH_READY_TIMEOUT: %v", err)
+	}
+	if readyTimeout <= 0 {
+		return nil, fmt.Errorf("invalid HEALTH_READY_TIMEOUT: %s is not positive", readyTimeout)
+	}
+
+	readHeaderTimeout, err := parsePositiveDuration("SERVER_READ_HEADER_TIMEOUT", "5s")
+	if err != nil {
+		return nil, err
+	}
+	readTimeout, err := parsePositiveDuration("SERVER_READ_TIMEOUT", "30s")
+	if err != nil {
+		return nil, err
+	}
+	writeTimeout, err := parsePositiveDuration("SERVER_WRITE_TIMEOUT", "30s")
+	if err != nil {
+		return nil, err
+	}
+	idleTimeout, err := parsePositiveDuration("SERVER_IDLE_TIMEOUT", "2m")
+	if err != nil {
+		return nil, err
+	}
+
+	maxHeaderBytes, err := strconv.Atoi(getEnv("SERVER_MAX_HEADER_BYTES", "65536"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SERVER_MAX_HEADER_BYTES: %v", err)
+	}
+	if maxHeaderBytes < 4096 {
+		return nil, fmt.Errorf("invalid SERVER_MAX_HEADER_BYTES: %d is below 4096", maxHeaderBytes)
+	}
+
+	dashboardCacheEnabled, err := strconv.ParseBool(getEnv("DASHBOARD_CACHE_ENABLED", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DASHBOARD_CACHE_ENABLED: %v", err)
+	}
+
+	// The refresh ticker panics on a zero or negative interval
+	dashboardCacheRefresh, err := parsePositiveDuration("DASHBOARD_CACHE_REFRESH_INTERVAL", "1m")
+	if err != nil {
+		return nil, err
+	}
+
+	statusCacheTTL, err := time.ParseDuration(getEnv("TRANSACTION_STATUS_CACHE_TTL", "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRANSACTION_STATUS_CACHE_TTL: %v", err)
+	}
+
+	asyncCreate, err := strconv.ParseBool(getEnv("TRANSACTION_ASYNC_CREATE", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRANSACTION_ASYNC_CREATE: %v", err)
+	}
+
+	amountPrecision, err := strconv.Atoi(getEnv("AMOUNT_PRECISION", "15"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AMOUNT_PRECISION: %v", err)
+	}
+
+	amountScale, err := strconv.Atoi(getEnv("AMOUNT_SCALE", "2"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AMOUNT_SCALE: %v", err)
+	}
+
+	currencyScales, err := parseCurrencyScales(getEnv("CURRENCY_SCALES", "USD:2,EUR:2,IDR:2,JPY:0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CURRENCY_SCALES: %v", err)
+	}
+
+	amountRounding, err := money.ParseRounding(getEnv("AMOUNT_ROUNDING", string(money.HalfUp)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AMOUNT_ROUNDING: %v", err)
+	}
+
+	currencyRoundings, err := parseCurrencyRoundings(getEnv("CURRENCY_ROUNDING", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CURRENCY_ROUNDING: %v", err)
+	}
+
+	serverHost := getEnv("SERVER_HOST", "127.0.0.1")
+
+	redisDB, err := strconv.Atoi(getEnv("REDIS_DB", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_DB: %v", err)
+	}
+
+	vaultRole := getEnv("DB_VAULT_ROLE", "")
+	vaultToken := getEnv("VAULT_TOKEN", "")
+	if vaultRole != "" && vaultToken == "" {
+		return nil, fmt.Errorf("invalid DB_VAULT_ROLE: VAULT_TOKEN is required")
+	}
+
+	commitBatchSize, err := strconv.Atoi(getEnv("KAFKA_COMMIT_BATCH_SIZE", "1"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_COMMIT_BATCH_SIZE: %v", err)
+	}
+	if commitBatchSize < 1 {
+		return nil, fmt.Errorf("invalid KAFKA_COMMIT_BATCH_SIZE: must be at least 1")
+	}
+
+	commitInterval, err := time.ParseDuration(getEnv("KAFKA_COMMIT_INTERVAL", "1s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_COMMIT_INTERVAL: %v", err)
+	}
+	if commitInterval <= 0 {
+		return nil, fmt.Errorf("invalid KAFKA_COMMIT_INTERVAL: must be positive")
+	}
+
+	anomalyEnabled, err := strconv.ParseBool(getEnv("ANOMALY_DETECTION_ENABLED", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ANOMALY_DETECTION_ENABLED: %v", err)
+	}
+
+	anomalyWindow, err := time.ParseDuration(getEnv("ANOMALY_WINDOW", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ANOMALY_WINDOW: %v", err)
+	}
+	if anomalyWindow < 2*time.Minute {
+		return nil, fmt.Errorf("invalid ANOMALY_WINDOW: must be at least 2m")
+	}
+
+	anomalyThreshold, err := strconv.ParseFloat(getEnv("ANOMALY_THRESHOLD", "3"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ANOMALY_THRESHOLD: %v", err)
+	}
+	if anomalyThreshold <= 0 {
+		return nil, fmt.Errorf("invalid ANOMALY_THRESHOLD: must be positive")
+	}
+
+	anomalyMinRate, err := strconv.ParseFloat(getEnv("ANOMALY_MIN_RATE", "5"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ANOMALY_MIN_RATE: %v", err)
+	}
+
+	probeInterval, err := time.ParseDuration(getEnv("PROBE_INTERVAL", "0s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROBE_INTERVAL: %v", err)
+	}
+
+	probeUserID, err := strconv.ParseUint(getEnv("PROBE_USER_ID", "0"), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROBE_USER_ID: %v", err)
+	}
+	if probeInterval > 0 && probeUserID == 0 {
+		return nil, fmt.Errorf("invalid PROBE_USER_ID: required when PROBE_INTERVAL is set")
+	}
+
+	routes := RoutesConfig{}
+	for _, name := range RouteGroups {
+		prefix := "ROUTES_" + strings.ToUpper(name) + "_"
+
+		logLevel := getEnv(prefix+"LOG_LEVEL", DefaultRouteGroup.LogLevel)
+		if logLevel != "none" {
+			if _, err := logrus.ParseLevel(logLevel); err != nil {
+				return nil, fmt.Errorf("invalid %sLOG_LEVEL: %v", prefix, err)
+			}
+		}
+
+		cacheMaxAge, err := time.ParseDuration(getEnv(prefix+"CACHE_MAX_AGE", "0s"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %sCACHE_MAX_AGE: %v", prefix, err)
+		}
+		if cacheMaxAge < 0 {
+			return nil, fmt.Errorf("invalid %sCACHE_MAX_AGE: must not be negative", prefix)
+		}
+
+		routes[name] = RouteGroupConfig{LogLevel: logLevel, CacheMaxAge: cacheMaxAge}
+	}
+
 	config := &Config{
+		Profile: profile,
+
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "127.0.0.1"),
 			Port:     dbPort,
 			User:     getEnv("DB_USER", "root"),
 			Password: getEnv("DB_PASSWORD", "root"),
 			Name:     getEnv("DB_NAME", "masihsama"),
+
+			ReplicaHost: getEnv("DB_REPLICA_HOST", ""),
+			ReplicaPort: replicaPort,
+			HedgeDelay:  hedgeDelay,
+
+			ReplicaMaxLag:           replicaMaxLag,
+			ReplicaLagCheckInterval: replicaLagCheckInterval,
+
+			SlowQueryThreshold: slowQueryThreshold,
+			ExplainSlowQueries: explainSlowQueries,
+
+			DashboardMaxOpenConns: dashboardMaxOpenConns,
+
+			FailoverHosts:         failoverHosts,
+			FailoverCheckInterval: failoverCheckInterval,
+
+			MigrationLockTimeout: migrationLockTimeout,
 		},
 		Server: ServerConfig{
-			Host: getEnv("SERVER_HOST", "127.0.0.1"),
+			Host: serverHost,
 			Port: getEnv("SERVER_PORT", "8080"),
+
+			AdminHost: getEnv("ADMIN_HOST", serverHost),
+			AdminPort: getEnv("ADMIN_PORT", ""),
+
+			AdminOperatorHeader: getEnv("ADMIN_OPERATOR_HEADER", ""),
+
+			UIEnabled:    uiEnabled,
+			ReadyTimeout: readyTimeout,
+
+			ReadHeaderTimeout: readHeaderTimeout,
+			ReadTimeout:       readTimeout,
+			WriteTimeout:      writeTimeout,
+			IdleTimeout:       idleTimeout,
+			MaxHeaderBytes:    maxHeaderBytes,
+
+			StrictJSON:     strictJSON,
+			TrustedProxies: trustedProxies,
 		},
 		Log: LogConfig{
 			Level: getEnv("LOG_LEVEL", "info"),
 		},
+		Dashboard: DashboardConfig{
+			CacheEnabled:         dashboardCacheEnabled,
+			CacheRefreshInterval: dashboardCacheRefresh,
+		},
+		Transaction: TransactionConfig{
+			StatusCacheTTL: statusCacheTTL,
+			AsyncCreate:    asyncCreate,
+		},
+		Amount: AmountConfig{
+			Precision:         amountPrecision,
+			Scale:             amountScale,
+			DefaultCurrency:   strings.ToUpper(getEnv("DEFAULT_CURRENCY", "USD")),
+			CurrencyScales:    currencyScales,
+			Rounding:          amountRounding,
+			CurrencyRoundings: currencyRoundings,
+		},
+		Kafka: KafkaConfig{
+			Brokers:  parseList(getEnv("KAFKA_BROKERS", "localhost:9092")),
+			Topic:    getEnv("KAFKA_TOPIC", "transactions.create"),
+			GroupID:  getEnv("KAFKA_GROUP_ID", "trxgo-consumer"),
+			DLQTopic: getEnv("KAFKA_DLQ_TOPIC", "transactions.create.dlq"),
+
+			CommitBatchSize: commitBatchSize,
+			CommitInterval:  commitInterval,
+		},
+		Redis: RedisConfig{
+			Addr:     getEnv("REDIS_ADDR", ""),
+			Password: getEnv("REDIS_PASSWORD", ""),
+			DB:       redisDB,
+		},
+		Vault: VaultConfig{
+			Addr:  getEnv("VAULT_ADDR", "http://127.0.0.1:8200"),
+			Token: vaultToken,
+			Mount: getEnv("DB_VAULT_MOUNT", "database"),
+			Role:  vaultRole,
+		},
+		Anomaly: AnomalyConfig{
+			Enabled:   anomalyEnabled,
+			Window:    anomalyWindow,
+			Threshold: anomalyThreshold,
+			MinRate:   anomalyMinRate,
+		},
+		Probe: ProbeConfig{
+			Interval: probeInterval,
+			UserID:   uint(probeUserID),
+		},
+		Routes: routes,
 	}
 
 	return config, nil
@@ -74,10 +592,184 @@ func (d *DatabaseConfig) GetDSN() string {
 		d.User, d.Password, d.Host, d.Port, d.Name)
 }
 
-// getEnv gets environment variable with fallback
+// Redacted returns a copy of the configuration that is safe to log
+func (c *Config) Redacted() Config {
+	redacted := *c
+	if redacted.Database.Password != "" {
+		redacted.Database.Password = "[REDACTED]"
+	}
+	if redacted.Redis.Password != "" {
+		redacted.Redis.Password = "[REDACTED]"
+	}
+	if redacted.Vault.Token != "" {
+		redacted.Vault.Token = "[REDACTED]"
+	}
+	return redacted
+}
+
+// Enabled reports whether instances share state through Redis
+func (r *RedisConfig) Enabled() bool {
+	return r.Addr != ""
+}
+
+// Enabled reports whether the synthetic probe runs
+func (p *ProbeConfig) Enabled() bool {
+	return p.Interval > 0
+}
+
+// Enabled reports whether database credentials come from Vault
+func (v *VaultConfig) Enabled() bool {
+	return v.Role != ""
+}
+
+// Address returns the listen address of the public API
+func (s *ServerConfig) Address() string {
+	return s.Host + ":" + s.Port
+}
+
+// HasAdminListener reports whether admin endpoints are served on their own listener
+func (s *ServerConfig) HasAdminListener() bool {
+	return s.AdminPort != ""
+}
+
+// ServesCorrections reports whether transaction corrections are served: only
+// on the admin listener, and only when a proxy identifies the operators
+func (s *ServerConfig) ServesCorrections() bool {
+	return s.HasAdminListener() && s.AdminOperatorHeader != ""
+}
+
+// AdminAddress returns the listen address of the admin endpoints
+func (s *ServerConfig) AdminAddress() string {
+	return s.AdminHost + ":" + s.AdminPort
+}
+
+// HasDashboardPool reports whether dashboard queries get their own connection pool
+func (d *DatabaseConfig) HasDashboardPool() bool {
+	return d.DashboardMaxOpenConns > 0
+}
+
+// HasFailover reports whether there are hosts to fail over to
+func (d *DatabaseConfig) HasFailover() bool {
+	return len(d.FailoverHosts) > 0
+}
+
+// Hosts returns the primary's address followed by the failover hosts
+func (d *DatabaseConfig) Hosts() []string {
+	return append([]string{fmt.Sprintf("%s:%d", d.Host, d.Port)}, d.FailoverHosts...)
+}
+
+// HasReplica reports whether a read replica is configured
+func (d *DatabaseConfig) HasReplica() bool {
+	return d.ReplicaHost != ""
+}
+
+// Replica returns the connection settings for the read replica, which shares
+// credentials and schema name with the primary
+func (d *DatabaseConfig) Replica() DatabaseConfig {
+	replica := *d
+	replica.Host = d.ReplicaHost
+	replica.Port = d.ReplicaPort
+	replica.FailoverHosts = nil
+	return replica
+}
+
+// parseList parses a comma-separated list, skipping empty entries
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseTrustedProxies parses TRUSTED_PROXIES: "*" trusts every proxy, "none"
+// trusts none, and otherwise it is a comma-separated list of IP addresses
+// and CIDR ranges
+func parseTrustedProxies(value string) ([]string, error) {
+	switch strings.TrimSpace(value) {
+	case "*":
+		return nil, nil
+	case "none":
+		return []string{}, nil
+	}
+
+	proxies := parseList(value)
+	for _, proxy := range proxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return nil, fmt.Errorf("%s is not an IP address or CIDR range", proxy)
+		}
+	}
+	return proxies, nil
+}
+
+// parseCurrencyScales parses a list like "USD:2,JPY:0" into currency scales
+func parseCurrencyScales(value string) (map[string]int, error) {
+	scales := make(map[string]int)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		currency, scale, found := strings.Cut(entry, ":")
+		if !found {
+			return nil, fmt.Errorf("expected CURRENCY:SCALE, got %q", entry)
+		}
+		n, err := strconv.Atoi(scale)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid scale for %s: %q", currency, scale)
+		}
+		scales[strings.ToUpper(strings.TrimSpace(currency))] = n
+	}
+	return scales, nil
+}
+
+// parseCurrencyRoundings parses a list like "JPY:half_even" into currency
+// roundings
+func parseCurrencyRoundings(value string) (map[string]money.Rounding, error) {
+	roundings := make(map[string]money.Rounding)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		currency, name, found := strings.Cut(entry, ":")
+		if !found {
+			return nil, fmt.Errorf("expected CURRENCY:ROUNDING, got %q", entry)
+		}
+		rounding, err := money.ParseRounding(strings.TrimSpace(name))
+		if err != nil {
+			return nil, fmt.Errorf("invalid rounding for %s: %v", currency, err)
+		}
+		roundings[strings.ToUpper(strings.TrimSpace(currency))] = rounding
+	}
+	return roundings, nil
+}
+
+// parsePositiveDuration reads the duration the environment variable key
+// holds, or fallback when it is unset, and requires it to be positive
+func parsePositiveDuration(key, fallback string) (time.Duration, error) {
+	value, err := time.ParseDuration(getEnv(key, fallback))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %v", key, err)
+	}
+	if value <= 0 {
+		return 0, fmt.Errorf("invalid %s: %s is not positive", key, value)
+	}
+	return value, nil
+}
+
+// getEnv gets a setting from the secret values or the environment, with fallback
 func getEnv(key, fallback string) string {
+	if value := secretValues[key]; value != "" {
+		return value
+	}
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
+	if value := profileValues[key]; value != "" {
+		return value
+	}
 	return fallback
 }
@@ -1,10 +1,17 @@
 package config_test
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"interview/internal/config"
+	"interview/pkg/money"
 )
 
 func TestLoad_DefaultValues(t *testing.T) {
@@ -82,3 +89,796 @@ func TestLoad_InvalidPort(t *testing.T) {
 		t.Error("Expected error for invalid port, got nil")
 	}
 }
+
+func TestLoad_DashboardCache(t *testing.T) {
+	os.Setenv("DASHBOARD_CACHE_ENABLED", "true")
+	os.Setenv("DASHBOARD_CACHE_REFRESH_INTERVAL", "30s")
+	defer func() {
+		os.Unsetenv("DASHBOARD_CACHE_ENABLED")
+		os.Unsetenv("DASHBOARD_CACHE_REFRESH_INTERVAL")
+	}()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !cfg.Dashboard.CacheEnabled {
+		t.Error("Expected dashboard cache to be enabled")
+	}
+	if cfg.Dashboard.CacheRefreshInterval.String() != "30s" {
+		t.Errorf("Expected refresh interval to be 30s, got %s", cfg.Dashboard.CacheRefreshInterval)
+	}
+}
+
+func TestLoad_UI(t *testing.T) {
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Server.UIEnabled {
+		t.Error("Expected the UI to be disabled by default")
+	}
+
+	os.Setenv("UI_ENABLED", "true")
+	defer os.Unsetenv("UI_ENABLED")
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !cfg.Server.UIEnabled {
+		t.Error("Expected the UI to be enabled")
+	}
+
+	os.Setenv("UI_ENABLED", "sometimes")
+	if _, err := config.Load(); err == nil {
+		t.Error("Expected error for invalid UI_ENABLED, got nil")
+	}
+}
+
+func TestLoad_HealthReadyTimeout(t *testing.T) {
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Server.ReadyTimeout != 2*time.Second {
+		t.Errorf("Expected a 2s readiness timeout by default, got %s", cfg.Server.ReadyTimeout)
+	}
+
+	defer os.Unsetenv("HEALTH_READY_TIMEOUT")
+	for _, value := range []string{"soon", "0s"} {
+		os.Setenv("HEALTH_READY_TIMEOUT", value)
+		if _, err := config.Load(); err == nil {
+			t.Errorf("Expected error for HEALTH_READY_TIMEOUT=%s, got nil", value)
+		}
+	}
+}
+
+func TestLoad_ServerTimeouts(t *testing.T) {
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Server.ReadHeaderTimeout != 5*time.Second || cfg.Server.ReadTimeout != 30*time.Second ||
+		cfg.Server.WriteTimeout != 30*time.Second || cfg.Server.IdleTimeout != 2*time.Minute {
+		t.Errorf("Expected the default connection timeouts, got %+v", cfg.Server)
+	}
+	if cfg.Server.MaxHeaderBytes != 64<<10 {
+		t.Errorf("Expected a 64 KiB header limit by default, got %d", cfg.Server.MaxHeaderBytes)
+	}
+
+	os.Setenv("SERVER_WRITE_TIMEOUT", "2m")
+	os.Setenv("SERVER_MAX_HEADER_BYTES", "8192")
+	defer os.Unsetenv("SERVER_WRITE_TIMEOUT")
+	defer os.Unsetenv("SERVER_MAX_HEADER_BYTES")
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Server.WriteTimeout != 2*time.Minute || cfg.Server.MaxHeaderBytes != 8192 {
+		t.Errorf("Expected a 2m write timeout and 8192 header bytes, got %+v", cfg.Server)
+	}
+
+	for key, values := range map[string][]string{
+		"SERVER_READ_HEADER_TIMEOUT": {"soon", "0s"},
+		"SERVER_IDLE_TIMEOUT":        {"-1s"},
+		"SERVER_MAX_HEADER_BYTES":    {"lots", "100"},
+	} {
+		for _, value := range values {
+			os.Setenv(key, value)
+			if _, err := config.Load(); err == nil {
+				t.Errorf("Expected error for %s=%s, got nil", key, value)
+			}
+		}
+		os.Unsetenv(key)
+	}
+}
+
+func TestLoad_MigrationLockTimeout(t *testing.T) {
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Database.MigrationLockTimeout != time.Minute {
+		t.Errorf("Expected a 1m migration lock timeout by default, got %s", cfg.Database.MigrationLockTimeout)
+	}
+
+	defer os.Unsetenv("DB_MIGRATION_LOCK_TIMEOUT")
+	for _, value := range []string{"later", "-1s"} {
+		os.Setenv("DB_MIGRATION_LOCK_TIMEOUT", value)
+		if _, err := config.Load(); err == nil {
+			t.Errorf("Expected error for DB_MIGRATION_LOCK_TIMEOUT=%s, got nil", value)
+		}
+	}
+}
+
+func TestLoad_InvalidDashboardCacheInterval(t *testing.T) {
+	defer os.Unsetenv("DASHBOARD_CACHE_REFRESH_INTERVAL")
+	for _, value := range []string{"soon", "0s", "-1m"} {
+		os.Setenv("DASHBOARD_CACHE_REFRESH_INTERVAL", value)
+		if _, err := config.Load(); err == nil {
+			t.Errorf("Expected error for DASHBOARD_CACHE_REFRESH_INTERVAL=%s, got nil", value)
+		}
+	}
+}
+
+func TestLoad_InvalidReplicaLagCheckInterval(t *testing.T) {
+	defer os.Unsetenv("DB_REPLICA_LAG_CHECK_INTERVAL")
+	for _, value := range []string{"often", "0s", "-5s"} {
+		os.Setenv("DB_REPLICA_LAG_CHECK_INTERVAL", value)
+		if _, err := config.Load(); err == nil {
+			t.Errorf("Expected error for DB_REPLICA_LAG_CHECK_INTERVAL=%s, got nil", value)
+		}
+	}
+}
+
+func TestLoad_InvalidHedgeDelay(t *testing.T) {
+	defer os.Unsetenv("DB_HEDGE_DELAY")
+	for _, value := range []string{"soon", "-50ms"} {
+		os.Setenv("DB_HEDGE_DELAY", value)
+		if _, err := config.Load(); err == nil {
+			t.Errorf("Expected error for DB_HEDGE_DELAY=%s, got nil", value)
+		}
+	}
+}
+
+func TestLoad_DashboardPool(t *testing.T) {
+	os.Unsetenv("DB_DASHBOARD_MAX_OPEN_CONNS")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Database.DashboardMaxOpenConns != 10 || !cfg.Database.HasDashboardPool() {
+		t.Errorf("Expected a 10 connection dashboard pool by default, got %d", cfg.Database.DashboardMaxOpenConns)
+	}
+
+	os.Setenv("DB_DASHBOARD_MAX_OPEN_CONNS", "0")
+	defer os.Unsetenv("DB_DASHBOARD_MAX_OPEN_CONNS")
+
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Database.HasDashboardPool() {
+		t.Error("Expected 0 to share the main pool")
+	}
+
+	for _, value := range []string{"-1", "few"} {
+		os.Setenv("DB_DASHBOARD_MAX_OPEN_CONNS", value)
+		if _, err := config.Load(); err == nil {
+			t.Errorf("Expected error for DB_DASHBOARD_MAX_OPEN_CONNS=%s, got nil", value)
+		}
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{User: "root", Password: "secret"},
+	}
+
+	redacted := cfg.Redacted()
+
+	if redacted.Database.Password != "[REDACTED]" {
+		t.Errorf("Expected password to be redacted, got %s", redacted.Database.Password)
+	}
+	if redacted.Database.User != "root" {
+		t.Errorf("Expected user to be kept, got %s", redacted.Database.User)
+	}
+	if cfg.Database.Password != "secret" {
+		t.Errorf("Expected original config to be unchanged, got %s", cfg.Database.Password)
+	}
+}
+
+func TestLoad_AdminListener(t *testing.T) {
+	os.Setenv("SERVER_HOST", "0.0.0.0")
+	os.Setenv("ADMIN_PORT", "9091")
+	defer func() {
+		os.Unsetenv("SERVER_HOST")
+		os.Unsetenv("ADMIN_PORT")
+	}()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !cfg.Server.HasAdminListener() {
+		t.Error("Expected admin listener to be enabled")
+	}
+	if cfg.Server.AdminAddress() != "0.0.0.0:9091" {
+		t.Errorf("Expected admin address to default to the server host, got %s", cfg.Server.AdminAddress())
+	}
+	if cfg.Server.Address() != "0.0.0.0:8080" {
+		t.Errorf("Expected server address '0.0.0.0:8080', got %s", cfg.Server.Address())
+	}
+	if cfg.Server.ServesCorrections() {
+		t.Error("Expected corrections to need an operator header")
+	}
+
+	os.Setenv("ADMIN_OPERATOR_HEADER", "X-Forwarded-User")
+	defer os.Unsetenv("ADMIN_OPERATOR_HEADER")
+
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !cfg.Server.ServesCorrections() {
+		t.Error("Expected corrections to be served")
+	}
+}
+
+func TestLoad_KafkaBrokers(t *testing.T) {
+	os.Setenv("KAFKA_BROKERS", "kafka-1:9092, kafka-2:9092,")
+	defer os.Unsetenv("KAFKA_BROKERS")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(cfg.Kafka.Brokers) != 2 || cfg.Kafka.Brokers[0] != "kafka-1:9092" || cfg.Kafka.Brokers[1] != "kafka-2:9092" {
+		t.Errorf("Expected brokers [kafka-1:9092 kafka-2:9092], got %v", cfg.Kafka.Brokers)
+	}
+	if cfg.Kafka.DLQTopic != "transactions.create.dlq" {
+		t.Errorf("Expected default DLQ topic, got %s", cfg.Kafka.DLQTopic)
+	}
+	if cfg.Kafka.CommitBatchSize != 1 || cfg.Kafka.CommitInterval != time.Second {
+		t.Errorf("Expected commits of 1 message within 1s by default, got %d within %s", cfg.Kafka.CommitBatchSize, cfg.Kafka.CommitInterval)
+	}
+}
+
+func TestLoad_KafkaCommitBatch(t *testing.T) {
+	os.Setenv("KAFKA_COMMIT_BATCH_SIZE", "100")
+	os.Setenv("KAFKA_COMMIT_INTERVAL", "500ms")
+	defer func() {
+		os.Unsetenv("KAFKA_COMMIT_BATCH_SIZE")
+		os.Unsetenv("KAFKA_COMMIT_INTERVAL")
+	}()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Kafka.CommitBatchSize != 100 || cfg.Kafka.CommitInterval != 500*time.Millisecond {
+		t.Errorf("Expected commits of 100 messages within 500ms, got %d within %s", cfg.Kafka.CommitBatchSize, cfg.Kafka.CommitInterval)
+	}
+
+	os.Setenv("KAFKA_COMMIT_BATCH_SIZE", "0")
+	if _, err := config.Load(); err == nil {
+		t.Error("Expected error for KAFKA_COMMIT_BATCH_SIZE of 0, got nil")
+	}
+}
+
+func TestLoad_Redis(t *testing.T) {
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Redis.Enabled() {
+		t.Error("Expected Redis to be disabled without REDIS_ADDR")
+	}
+
+	os.Setenv("REDIS_ADDR", "redis:6379")
+	os.Setenv("REDIS_PASSWORD", "secret")
+	os.Setenv("REDIS_DB", "2")
+	defer func() {
+		os.Unsetenv("REDIS_ADDR")
+		os.Unsetenv("REDIS_PASSWORD")
+		os.Unsetenv("REDIS_DB")
+	}()
+
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !cfg.Redis.Enabled() || cfg.Redis.Addr != "redis:6379" || cfg.Redis.DB != 2 {
+		t.Errorf("Unexpected Redis config %+v", cfg.Redis)
+	}
+	if cfg.Redacted().Redis.Password != "[REDACTED]" {
+		t.Errorf("Expected Redis password to be redacted, got %s", cfg.Redacted().Redis.Password)
+	}
+
+	os.Setenv("REDIS_DB", "one")
+	if _, err := config.Load(); err == nil {
+		t.Error("Expected error for invalid REDIS_DB")
+	}
+}
+
+func TestLoad_AsyncCreate(t *testing.T) {
+	os.Unsetenv("TRANSACTION_ASYNC_CREATE")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Transaction.AsyncCreate {
+		t.Error("Expected async create to be disabled by default")
+	}
+
+	os.Setenv("TRANSACTION_ASYNC_CREATE", "true")
+	defer os.Unsetenv("TRANSACTION_ASYNC_CREATE")
+
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !cfg.Transaction.AsyncCreate {
+		t.Error("Expected async create to be enabled")
+	}
+
+	os.Setenv("TRANSACTION_ASYNC_CREATE", "sometimes")
+	if _, err := config.Load(); err == nil {
+		t.Error("Expected error for invalid TRANSACTION_ASYNC_CREATE, got nil")
+	}
+}
+
+func TestLoad_AmountRounding(t *testing.T) {
+	os.Unsetenv("AMOUNT_ROUNDING")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Amount.Rounding != money.HalfUp {
+		t.Errorf("Expected half_up rounding by default, got %q", cfg.Amount.Rounding)
+	}
+
+	os.Setenv("AMOUNT_ROUNDING", "half_even")
+	defer os.Unsetenv("AMOUNT_ROUNDING")
+
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Amount.Rounding != money.HalfEven {
+		t.Errorf("Expected half_even rounding, got %q", cfg.Amount.Rounding)
+	}
+
+	os.Setenv("AMOUNT_ROUNDING", "ceiling")
+	if _, err := config.Load(); err == nil {
+		t.Error("Expected error for invalid AMOUNT_ROUNDING, got nil")
+	}
+}
+
+func TestLoad_CurrencyRounding(t *testing.T) {
+	os.Unsetenv("CURRENCY_ROUNDING")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(cfg.Amount.CurrencyRoundings) != 0 {
+		t.Errorf("Expected no currency roundings by default, got %v", cfg.Amount.CurrencyRoundings)
+	}
+
+	os.Setenv("CURRENCY_ROUNDING", "jpy:half_even, EUR:half_up")
+	defer os.Unsetenv("CURRENCY_ROUNDING")
+
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Amount.CurrencyRoundings["JPY"] != money.HalfEven || cfg.Amount.CurrencyRoundings["EUR"] != money.HalfUp {
+		t.Errorf("Expected JPY half_even and EUR half_up, got %v", cfg.Amount.CurrencyRoundings)
+	}
+
+	for _, value := range []string{"JPY", "JPY:ceiling"} {
+		os.Setenv("CURRENCY_ROUNDING", value)
+		if _, err := config.Load(); err == nil {
+			t.Errorf("Expected error for CURRENCY_ROUNDING=%s, got nil", value)
+		}
+	}
+}
+
+func TestLoad_FailoverHosts(t *testing.T) {
+	os.Setenv("DB_HOST", "db1")
+	os.Setenv("DB_PORT", "3306")
+	os.Setenv("DB_FAILOVER_HOSTS", "db2, db3:3307")
+	defer os.Unsetenv("DB_HOST")
+	defer os.Unsetenv("DB_PORT")
+	defer os.Unsetenv("DB_FAILOVER_HOSTS")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !cfg.Database.HasFailover() {
+		t.Fatal("Expected failover to be configured")
+	}
+	hosts := cfg.Database.Hosts()
+	expected := []string{"db1:3306", "db2:3306", "db3:3307"}
+	if len(hosts) != len(expected) {
+		t.Fatalf("Expected hosts %v, got %v", expected, hosts)
+	}
+	for i := range expected {
+		if hosts[i] != expected[i] {
+			t.Errorf("Expected hosts %v, got %v", expected, hosts)
+		}
+	}
+	if cfg.Database.FailoverCheckInterval != 5*time.Second {
+		t.Errorf("Expected a 5s check interval by default, got %v", cfg.Database.FailoverCheckInterval)
+	}
+	if replica := cfg.Database.Replica(); replica.HasFailover() {
+		t.Error("Expected the replica not to inherit failover hosts")
+	}
+
+	os.Setenv("DB_FAILOVER_CHECK_INTERVAL", "0s")
+	defer os.Unsetenv("DB_FAILOVER_CHECK_INTERVAL")
+	if _, err := config.Load(); err == nil {
+		t.Error("Expected error for DB_FAILOVER_CHECK_INTERVAL=0s, got nil")
+	}
+}
+
+func TestLoad_Vault(t *testing.T) {
+	os.Unsetenv("DB_VAULT_ROLE")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Vault.Enabled() {
+		t.Error("Expected Vault to be disabled by default")
+	}
+
+	os.Setenv("DB_VAULT_ROLE", "trxgo")
+	defer os.Unsetenv("DB_VAULT_ROLE")
+	if _, err := config.Load(); err == nil {
+		t.Error("Expected error for DB_VAULT_ROLE without VAULT_TOKEN, got nil")
+	}
+
+	os.Setenv("VAULT_TOKEN", "s.secret")
+	defer os.Unsetenv("VAULT_TOKEN")
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !cfg.Vault.Enabled() || cfg.Vault.Mount != "database" {
+		t.Errorf("Expected Vault role trxgo on the database mount, got %+v", cfg.Vault)
+	}
+	if redacted := cfg.Redacted(); redacted.Vault.Token != "[REDACTED]" {
+		t.Errorf("Expected Vault token to be redacted, got %s", redacted.Vault.Token)
+	}
+}
+
+func TestLoad_Probe(t *testing.T) {
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Probe.Enabled() {
+		t.Error("Expected the synthetic probe to be disabled by default")
+	}
+
+	os.Setenv("PROBE_INTERVAL", "30s")
+	defer os.Unsetenv("PROBE_INTERVAL")
+	if _, err := config.Load(); err == nil {
+		t.Error("Expected error for PROBE_INTERVAL without PROBE_USER_ID, got nil")
+	}
+
+	os.Setenv("PROBE_USER_ID", "7")
+	defer os.Unsetenv("PROBE_USER_ID")
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !cfg.Probe.Enabled() || cfg.Probe.Interval != 30*time.Second || cfg.Probe.UserID != 7 {
+		t.Errorf("Expected the probe to run every 30s as user 7, got %+v", cfg.Probe)
+	}
+}
+
+func TestLoad_Routes(t *testing.T) {
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if group := cfg.Routes.Group("dashboard"); group.LogLevel != "info" || group.CacheMaxAge != 0 {
+		t.Errorf("Expected the dashboard group to log at info without caching, got %+v", group)
+	}
+
+	os.Setenv("ROUTES_HEALTH_LOG_LEVEL", "none")
+	defer os.Unsetenv("ROUTES_HEALTH_LOG_LEVEL")
+	os.Setenv("ROUTES_DASHBOARD_CACHE_MAX_AGE", "30s")
+	defer os.Unsetenv("ROUTES_DASHBOARD_CACHE_MAX_AGE")
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Routes.Group("health").LogLevel != "none" {
+		t.Errorf("Expected health checks not to be logged, got %+v", cfg.Routes.Group("health"))
+	}
+	if cfg.Routes.Group("dashboard").CacheMaxAge != 30*time.Second {
+		t.Errorf("Expected the dashboard to be cacheable for 30s, got %+v", cfg.Routes.Group("dashboard"))
+	}
+
+	for key, value := range map[string]string{
+		"ROUTES_USERS_LOG_LEVEL":         "loud",
+		"ROUTES_ADMIN_CACHE_MAX_AGE":     "soon",
+		"ROUTES_DASHBOARD_CACHE_MAX_AGE": "-1s",
+	} {
+		previous, set := os.LookupEnv(key)
+		os.Setenv(key, value)
+		if _, err := config.Load(); err == nil {
+			t.Errorf("Expected error for %s=%s, got nil", key, value)
+		}
+		if set {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+func TestLoad_AWSSecretsManager(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" {
+			t.Errorf("Expected GetSecretValue, got %q", r.Header.Get("X-Amz-Target"))
+		}
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") || !strings.Contains(auth, "/eu-west-1/secretsmanager/aws4_request") {
+			t.Errorf("Expected a SigV4 signature, got %q", auth)
+		}
+		if r.Header.Get("X-Amz-Security-Token") != "session" {
+			t.Errorf("Expected the session token, got %q", r.Header.Get("X-Amz-Security-Token"))
+		}
+
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["SecretId"] != "trxgo/config" {
+			t.Errorf("Expected SecretId trxgo/config, got %q", body["SecretId"])
+		}
+		json.NewEncoder(w).Encode(map[string]string{"SecretString": `{"DB_PASSWORD": "from-aws", "DB_USER": "app"}`})
+	}))
+	defer server.Close()
+
+	env := map[string]string{
+		"CONFIG_SECRETS_PROVIDER": "aws",
+		"CONFIG_SECRET_ID":        "trxgo/config",
+		"CONFIG_SECRETS_ENDPOINT": server.URL,
+		"AWS_REGION":              "eu-west-1",
+		"AWS_ACCESS_KEY_ID":       "AKIDEXAMPLE",
+		"AWS_SECRET_ACCESS_KEY":   "secret",
+		"AWS_SESSION_TOKEN":       "session",
+		"DB_PASSWORD":             "from-env",
+		"DB_NAME":                 "from-env",
+	}
+	for key, value := range env {
+		os.Setenv(key, value)
+	}
+	defer func() {
+		for key := range env {
+			os.Unsetenv(key)
+		}
+	}()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Database.Password != "from-aws" || cfg.Database.User != "app" {
+		t.Errorf("Expected database credentials from the secret, got %s/%s", cfg.Database.User, cfg.Database.Password)
+	}
+	if cfg.Database.Name != "from-env" {
+		t.Errorf("Expected settings missing from the secret to come from the environment, got %s", cfg.Database.Name)
+	}
+}
+
+func TestLoad_GCPSecretManager(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/computeMetadata/v1/instance/service-accounts/default/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "token"})
+	})
+	mux.HandleFunc("/v1/projects/p/secrets/trxgo/versions/latest:access", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		data := base64.StdEncoding.EncodeToString([]byte(`{"DB_PASSWORD": "from-gcp"}`))
+		json.NewEncoder(w).Encode(map[string]interface{}{"payload": map[string]string{"data": data}})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	env := map[string]string{
+		"CONFIG_SECRETS_PROVIDER": "gcp",
+		"CONFIG_SECRET_ID":        "projects/p/secrets/trxgo",
+		"CONFIG_SECRETS_ENDPOINT": server.URL,
+		"GCE_METADATA_HOST":       strings.TrimPrefix(server.URL, "http://"),
+	}
+	for key, value := range env {
+		os.Setenv(key, value)
+	}
+	defer func() {
+		for key := range env {
+			os.Unsetenv(key)
+		}
+	}()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Database.Password != "from-gcp" {
+		t.Errorf("Expected DB_PASSWORD from the secret, got %s", cfg.Database.Password)
+	}
+
+	// Without a provider every setting comes from the environment again
+	os.Unsetenv("CONFIG_SECRETS_PROVIDER")
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Database.Password == "from-gcp" {
+		t.Errorf("Expected secret values to be dropped without a provider")
+	}
+}
+
+func TestLoad_SecretsProviderErrors(t *testing.T) {
+	os.Setenv("CONFIG_SECRETS_PROVIDER", "gcp")
+	defer os.Unsetenv("CONFIG_SECRETS_PROVIDER")
+	if _, err := config.Load(); err == nil {
+		t.Error("Expected an error for a provider without CONFIG_SECRET_ID")
+	}
+
+	os.Setenv("CONFIG_SECRETS_PROVIDER", "azure")
+	os.Setenv("CONFIG_SECRET_ID", "trxgo")
+	defer os.Unsetenv("CONFIG_SECRET_ID")
+	if _, err := config.Load(); err == nil {
+		t.Error("Expected an error for an unknown provider")
+	}
+
+	os.Setenv("CONFIG_SECRETS_PROVIDER", "aws")
+	if _, err := config.Load(); err == nil {
+		t.Error("Expected an error for the aws provider without a region or credentials")
+	}
+}
+
+func TestLoad_Anomaly(t *testing.T) {
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Anomaly.Enabled || cfg.Anomaly.Window != time.Hour || cfg.Anomaly.Threshold != 3 || cfg.Anomaly.MinRate != 5 {
+		t.Errorf("Expected disabled detector with a 1h window, threshold 3 and min rate 5, got %+v", cfg.Anomaly)
+	}
+
+	os.Setenv("ANOMALY_DETECTION_ENABLED", "true")
+	os.Setenv("ANOMALY_WINDOW", "30m")
+	os.Setenv("ANOMALY_THRESHOLD", "4.5")
+	defer func() {
+		os.Unsetenv("ANOMALY_DETECTION_ENABLED")
+		os.Unsetenv("ANOMALY_WINDOW")
+		os.Unsetenv("ANOMALY_THRESHOLD")
+	}()
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !cfg.Anomaly.Enabled || cfg.Anomaly.Window != 30*time.Minute || cfg.Anomaly.Threshold != 4.5 {
+		t.Errorf("Expected enabled detector with a 30m window and threshold 4.5, got %+v", cfg.Anomaly)
+	}
+
+	os.Setenv("ANOMALY_WINDOW", "1m")
+	if _, err := config.Load(); err == nil {
+		t.Error("Expected error for ANOMALY_WINDOW under 2m, got nil")
+	}
+	os.Setenv("ANOMALY_WINDOW", "30m")
+	os.Setenv("ANOMALY_THRESHOLD", "0")
+	if _, err := config.Load(); err == nil {
+		t.Error("Expected error for non-positive ANOMALY_THRESHOLD, got nil")
+	}
+}
+
+func TestLoad_Profiles(t *testing.T) {
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Profile != "" || cfg.Log.Level != "info" || cfg.Server.StrictJSON || cfg.Server.TrustedProxies != nil {
+		t.Errorf("Expected no profile defaults without APP_ENV, got %+v", cfg.Server)
+	}
+
+	defer os.Unsetenv("APP_ENV")
+	os.Setenv("APP_ENV", "dev")
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Profile != "dev" || cfg.Log.Level != "debug" || !cfg.Database.ExplainSlowQueries || !cfg.Server.UIEnabled {
+		t.Errorf("Expected the dev defaults, got profile %q, log level %q", cfg.Profile, cfg.Log.Level)
+	}
+
+	os.Setenv("APP_ENV", "prod")
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !cfg.Server.StrictJSON || len(cfg.Server.TrustedProxies) != 3 || cfg.Server.UIEnabled {
+		t.Errorf("Expected the prod defaults, got %+v", cfg.Server)
+	}
+
+	// The environment still overrides a profile's defaults
+	os.Setenv("STRICT_JSON", "false")
+	defer os.Unsetenv("STRICT_JSON")
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Server.StrictJSON {
+		t.Error("Expected STRICT_JSON to override the prod profile")
+	}
+
+	os.Setenv("APP_ENV", "production")
+	if _, err := config.Load(); err == nil || err.Error() != "invalid APP_ENV: production is not one of dev, prod, staging" {
+		t.Errorf("Expected error for unknown APP_ENV, got %v", err)
+	}
+}
+
+func TestLoad_TrustedProxies(t *testing.T) {
+	defer os.Unsetenv("TRUSTED_PROXIES")
+
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"*", nil},
+		{"none", []string{}},
+		{"10.0.0.1, 192.168.0.0/16", []string{"10.0.0.1", "192.168.0.0/16"}},
+	}
+	for _, tt := range tests {
+		os.Setenv("TRUSTED_PROXIES", tt.value)
+		cfg, err := config.Load()
+		if err != nil {
+			t.Fatalf("Expected no error for %q, got %v", tt.value, err)
+		}
+		if (cfg.Server.TrustedProxies == nil) != (tt.want == nil) || strings.Join(cfg.Server.TrustedProxies, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Expected trusted proxies %v for %q, got %v", tt.want, tt.value, cfg.Server.TrustedProxies)
+		}
+	}
+
+	os.Setenv("TRUSTED_PROXIES", "10.0.0.0/33")
+	if _, err := config.Load(); err == nil {
+		t.Error("Expected error for an invalid CIDR range, got nil")
+	}
+}
+
+func TestProfileDefaults(t *testing.T) {
+	defaults := config.ProfileDefaults("dev")
+	if defaults["LOG_LEVEL"] != "debug" {
+		t.Errorf("Expected the dev profile to log at debug, got %q", defaults["LOG_LEVEL"])
+	}
+
+	// Callers get a copy
+	defaults["LOG_LEVEL"] = "error"
+	if config.ProfileDefaults("dev")["LOG_LEVEL"] != "debug" {
+		t.Error("Expected ProfileDefaults to return a copy")
+	}
+
+	if config.ProfileDefaults("qa") != nil {
+		t.Error("Expected no defaults for an unknown profile")
+	}
+}
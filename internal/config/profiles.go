@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// privateNetworks are the address ranges load balancers in front of the
+// staging and prod profiles are expected to connect from
+const privateNetworks = "10.0.0.0/8,172.16.0.0/12,192.168.0.0/16"
+
+// profiles are the environments APP_ENV selects, each with the defaults it
+// layers under the environment. Settings a profile does not name keep the
+// defaults Load falls back to without a profile.
+var profiles = map[string]map[string]string{
+	"dev": {
+		"LOG_LEVEL":               "debug",
+		"DB_EXPLAIN_SLOW_QUERIES": "true",
+		"UI_ENABLED":              "true",
+	},
+	"staging": {
+		"LOG_LEVEL":               "info",
+		"DB_EXPLAIN_SLOW_QUERIES": "true",
+		"STRICT_JSON":             "true",
+		"TRUSTED_PROXIES":         privateNetworks,
+	},
+	"prod": {
+		"LOG_LEVEL":       "info",
+		"UI_ENABLED":      "false",
+		"STRICT_JSON":     "true",
+		"TRUSTED_PROXIES": privateNetworks,
+	},
+}
+
+// profileValues are the defaults of the profile APP_ENV selects. Load
+// replaces them before reading any other setting; getEnv consults them after
+// the environment.
+var profileValues map[string]string
+
+// loadProfile selects the profile APP_ENV names, or none when it is unset
+func loadProfile() (string, error) {
+	profileValues = nil
+	name := getEnv("APP_ENV", "")
+	if name == "" {
+		return "", nil
+	}
+
+	values, ok := profiles[name]
+	if !ok {
+		return "", fmt.Errorf("invalid APP_ENV: %s is not one of %s", name, strings.Join(ProfileNames(), ", "))
+	}
+	profileValues = values
+	return name, nil
+}
+
+// ProfileNames returns the profiles APP_ENV accepts, sorted
+func ProfileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProfileDefaults returns a copy of the defaults profile layers under the
+// environment, or nil for an unknown profile
+func ProfileDefaults(profile string) map[string]string {
+	values, ok := profiles[profile]
+	if !ok {
+		return nil
+	}
+	defaults := make(map[string]string, len(values))
+	for key, value := range values {
+		defaults[key] = value
+	}
+	return defaults
+}
@@ -0,0 +1,292 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"interview/pkg/httpclient"
+)
+
+// SecretSource fetches a secret holding configuration values. The secret is
+// a JSON object mapping environment variable names to values, such as
+// {"DB_PASSWORD": "..."}.
+type SecretSource interface {
+	GetSecret(ctx context.Context, id string) (string, error)
+}
+
+// secretValues are the values read from the configured secret source. Load
+// replaces them before reading any setting; getEnv consults them before the
+// environment.
+var secretValues map[string]string
+
+// NewSecretSource returns the secret source named by provider: "aws" for AWS
+// Secrets Manager or "gcp" for GCP Secret Manager. Both read their
+// credentials from the environment the way the cloud SDKs do.
+func NewSecretSource(provider string) (SecretSource, error) {
+	client, err := httpclient.New(httpclient.DefaultConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	switch provider {
+	case "aws":
+		region := os.Getenv("AWS_REGION")
+		if region == "" {
+			region = os.Getenv("AWS_DEFAULT_REGION")
+		}
+		if region == "" {
+			return nil, fmt.Errorf("AWS_REGION is required for the aws secrets provider")
+		}
+		if os.Getenv("AWS_ACCESS_KEY_ID") == "" || os.Getenv("AWS_SECRET_ACCESS_KEY") == "" {
+			return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required for the aws secrets provider")
+		}
+		endpoint := os.Getenv("CONFIG_SECRETS_ENDPOINT")
+		if endpoint == "" {
+			endpoint = "https://secretsmanager." + region + ".amazonaws.com"
+		}
+		return &awsSecretsManager{
+			endpoint:        strings.TrimSuffix(endpoint, "/"),
+			region:          region,
+			accessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			secretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			sessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			client:          client,
+		}, nil
+	case "gcp":
+		endpoint := os.Getenv("CONFIG_SECRETS_ENDPOINT")
+		if endpoint == "" {
+			endpoint = "https://secretmanager.googleapis.com"
+		}
+		metadataHost := os.Getenv("GCE_METADATA_HOST")
+		if metadataHost == "" {
+			metadataHost = "metadata.google.internal"
+		}
+		return &gcpSecretManager{
+			endpoint:    strings.TrimSuffix(endpoint, "/"),
+			metadataURL: "http://" + metadataHost + "/computeMetadata/v1/instance/service-accounts/default/token",
+			accessToken: os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"),
+			client:      client,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown secrets provider %q", provider)
+	}
+}
+
+// loadSecretValues reads the secret named by CONFIG_SECRET_ID from the
+// provider named by CONFIG_SECRETS_PROVIDER. Without a provider there are no
+// secret values and every setting comes from the environment.
+func loadSecretValues() (map[string]string, error) {
+	provider := os.Getenv("CONFIG_SECRETS_PROVIDER")
+	if provider == "" {
+		return nil, nil
+	}
+	id := os.Getenv("CONFIG_SECRET_ID")
+	if id == "" {
+		return nil, fmt.Errorf("CONFIG_SECRET_ID is required when CONFIG_SECRETS_PROVIDER is set")
+	}
+
+	source, err := NewSecretSource(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	secret, err := source.GetSecret(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %s: %v", id, err)
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal([]byte(secret), &values); err != nil {
+		return nil, fmt.Errorf("invalid secret %s: expected a JSON object of string values: %v", id, err)
+	}
+	return values, nil
+}
+
+// awsSecretsManager reads secrets from AWS Secrets Manager
+type awsSecretsManager struct {
+	endpoint        string
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+
+	client *httpclient.Client
+}
+
+// GetSecret implements SecretSource. id is a secret name or ARN; the current
+// version's SecretString is returned.
+func (a *awsSecretsManager) GetSecret(ctx context.Context, id string) (string, error) {
+	body, err := json.Marshal(map[string]string{"SecretId": id})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	a.sign(req, body)
+
+	var out struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := doSecretRequest(a.client, req, &out); err != nil {
+		return "", err
+	}
+	if out.SecretString == "" {
+		return "", fmt.Errorf("secret has no string value")
+	}
+	return out.SecretString, nil
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to req
+func (a *awsSecretsManager) sign(req *http.Request, body []byte) {
+	t := time.Now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if a.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", a.sessionToken)
+	}
+
+	headers := []string{"content-type", "host", "x-amz-date", "x-amz-target"}
+	if a.sessionToken != "" {
+		headers = append(headers, "x-amz-security-token")
+	}
+	sort.Strings(headers)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range headers {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + a.region + "/secretsmanager/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+a.secretAccessKey), date)
+	key = hmacSHA256(key, a.region)
+	key = hmacSHA256(key, "secretsmanager")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+a.accessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// gcpSecretManager reads secrets from GCP Secret Manager. It authenticates
+// with GOOGLE_OAUTH_ACCESS_TOKEN when set and otherwise with a token from the
+// metadata server, as workloads on GCE, GKE and Cloud Run do.
+type gcpSecretManager struct {
+	endpoint    string
+	metadataURL string
+	accessToken string
+
+	client *httpclient.Client
+}
+
+// GetSecret implements SecretSource. id is a secret version resource name,
+// such as projects/my-project/secrets/trxgo/versions/latest; a name without
+// a version reads the latest one.
+func (g *gcpSecretManager) GetSecret(ctx context.Context, id string) (string, error) {
+	if !strings.Contains(id, "/versions/") {
+		id += "/versions/latest"
+	}
+
+	token := g.accessToken
+	if token == "" {
+		var err error
+		if token, err = g.metadataToken(ctx); err != nil {
+			return "", fmt.Errorf("failed to get access token: %v", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.endpoint+"/v1/"+id+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	var out struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := doSecretRequest(g.client, req, &out); err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(out.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("invalid secret payload: %v", err)
+	}
+	return string(data), nil
+}
+
+// metadataToken requests an access token for the default service account
+func (g *gcpSecretManager) metadataToken(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.metadataURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	var out struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := doSecretRequest(g.client, req, &out); err != nil {
+		return "", err
+	}
+	return out.AccessToken, nil
+}
+
+// doSecretRequest sends req and decodes the JSON response into out
+func doSecretRequest(client *httpclient.Client, req *http.Request, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s returned %s: %s", req.URL.Host, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,140 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"interview/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// amountColumns are the decimal columns sized like transactions.amount.
+// Budget caps are compared with sums of amounts, so they hold them at the
+// same precision.
+var amountColumns = []struct {
+	model interface{}
+	field string
+}{
+	{&models.Transaction{}, "Amount"},
+	{&models.Budget{}, "MonthlyCap"},
+}
+
+// ConfigureAmountColumn sets the SQL type of transactions.amount and
+// budgets.monthly_cap to decimal(precision,scale). The parsed model schemas
+// are updated so later AutoMigrate calls keep the configured type, and
+// existing columns are widened in place. Narrowing is refused because it
+// could truncate stored amounts.
+func ConfigureAmountColumn(db *gorm.DB, precision, scale int) error {
+	for _, column := range amountColumns {
+		if err := configureAmountColumn(db, column.model, column.field, precision, scale); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configureAmountColumn sets the SQL type of one of the amountColumns
+func configureAmountColumn(db *gorm.DB, model interface{}, name string, precision, scale int) error {
+	field, err := applyAmountColumn(db, model, name, precision, scale)
+	if err != nil {
+		return err
+	}
+
+	migrator := db.Migrator()
+	if !migrator.HasTable(model) {
+		return nil
+	}
+
+	columnTypes, err := migrator.ColumnTypes(model)
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s table: %w", field.Schema.Table, err)
+	}
+
+	for _, columnType := range columnTypes {
+		if columnType.Name() != field.DBName {
+			continue
+		}
+
+		currentPrecision, currentScale, ok := decimalSize(columnType)
+		if !ok || (int(currentPrecision) == precision && int(currentScale) == scale) {
+			return nil
+		}
+
+		if err := checkWidening(int(currentPrecision), int(currentScale), precision, scale); err != nil {
+			return fmt.Errorf("%s.%s: %w", field.Schema.Table, field.DBName, err)
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"column": field.Schema.Table + "." + field.DBName,
+			"from":   fmt.Sprintf("decimal(%d,%d)", currentPrecision, currentScale),
+			"to":     string(field.DataType),
+		}).Info("Widening amount column")
+
+		if err := migrator.AlterColumn(model, name); err != nil {
+			return fmt.Errorf("failed to widen %s.%s column: %w", field.Schema.Table, field.DBName, err)
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// applyAmountColumns sets the type of every one of the amountColumns in db's
+// parsed schemas to decimal(precision,scale)
+func applyAmountColumns(db *gorm.DB, precision, scale int) error {
+	for _, column := range amountColumns {
+		if _, err := applyAmountColumn(db, column.model, column.field, precision, scale); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyAmountColumn sets the type of the field name in db's parsed schema of
+// model to decimal(precision,scale) and returns the field
+func applyAmountColumn(db *gorm.DB, model interface{}, name string, precision, scale int) (*schema.Field, error) {
+	if precision <= 0 || scale < 0 || scale > precision {
+		return nil, fmt.Errorf("invalid amount column type decimal(%d,%d)", precision, scale)
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, fmt.Errorf("failed to parse %T schema: %w", model, err)
+	}
+	field := stmt.Schema.LookUpField(name)
+	field.DataType = schema.DataType(fmt.Sprintf("decimal(%d,%d)", precision, scale))
+	field.Precision = precision
+	field.Scale = scale
+	return field, nil
+}
+
+// checkWidening rejects a column change that loses integer digits or scale
+func checkWidening(currentPrecision, currentScale, precision, scale int) error {
+	if scale < currentScale || precision-scale < currentPrecision-currentScale {
+		return fmt.Errorf("refusing to narrow amount column from decimal(%d,%d) to decimal(%d,%d): existing amounts could be truncated; migrate the data manually first",
+			currentPrecision, currentScale, precision, scale)
+	}
+	return nil
+}
+
+// decimalSize returns the precision and scale of a decimal column, falling
+// back to parsing the column type for drivers that don't report it
+func decimalSize(columnType gorm.ColumnType) (int64, int64, bool) {
+	if precision, scale, ok := columnType.DecimalSize(); ok {
+		return precision, scale, true
+	}
+
+	fullType, ok := columnType.ColumnType()
+	if !ok {
+		return 0, 0, false
+	}
+
+	var precision, scale int64
+	if _, err := fmt.Sscanf(strings.ToLower(fullType), "decimal(%d,%d)", &precision, &scale); err != nil {
+		return 0, 0, false
+	}
+	return precision, scale, true
+}
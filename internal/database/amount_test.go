@@ -0,0 +1,65 @@
+package database
+
+import (
+	"testing"
+
+	"interview/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestConfigureAmountColumn_NewTable(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, ConfigureAmountColumn(db, 20, 8))
+	require.NoError(t, db.AutoMigrate(&models.Transaction{}, &models.Budget{}))
+
+	for _, table := range []string{"transactions", "budgets"} {
+		var ddl string
+		db.Raw("SELECT sql FROM sqlite_master WHERE name = ?", table).Scan(&ddl)
+		assert.Contains(t, ddl, "decimal(20,8)", table)
+	}
+}
+
+func TestConfigureAmountColumn_InvalidType(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	assert.Error(t, ConfigureAmountColumn(db, 2, 4))
+	assert.Error(t, ConfigureAmountColumn(db, 0, 0))
+}
+
+func TestCheckWidening(t *testing.T) {
+	tests := []struct {
+		name             string
+		precision, scale int
+		wantErr          bool
+	}{
+		{"same type", 15, 2, false},
+		{"more scale and digits", 21, 8, false},
+		{"more integer digits", 18, 2, false},
+		{"less scale", 15, 0, true},
+		{"more scale but fewer integer digits", 15, 8, true},
+		{"less precision", 10, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkWidening(15, 2, tt.precision, tt.scale)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+// validatedConn is a connection the pool must stop reusing once valid
+// reports false, such as one to a failed-over host or opened with rotated
+// credentials. It forwards the optional driver interfaces to the wrapped
+// connection.
+type validatedConn struct {
+	driver.Conn
+	valid func() bool
+}
+
+// IsValid implements driver.Validator
+func (c *validatedConn) IsValid() bool {
+	if !c.valid() {
+		return false
+	}
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// PrepareContext implements driver.ConnPrepareContext
+func (c *validatedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+// BeginTx implements driver.ConnBeginTx
+func (c *validatedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+// ExecContext implements driver.ExecerContext
+func (c *validatedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if execer, ok := c.Conn.(driver.ExecerContext); ok {
+		return execer.ExecContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+// QueryContext implements driver.QueryerContext
+func (c *validatedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if queryer, ok := c.Conn.(driver.QueryerContext); ok {
+		return queryer.QueryContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+// Ping implements driver.Pinger
+func (c *validatedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession implements driver.SessionResetter
+func (c *validatedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+// CheckNamedValue implements driver.NamedValueChecker
+func (c *validatedConn) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	return driver.ErrSkip
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"sync"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Credentials holds the username and password new connections use. Set
+// replaces them, for example when Vault issues new ones; connections opened
+// with the old credentials are closed by the pool instead of reused.
+type Credentials struct {
+	mu         sync.RWMutex
+	username   string
+	password   string
+	generation int64
+}
+
+// NewCredentials creates credentials with the given username and password
+func NewCredentials(username, password string) *Credentials {
+	return &Credentials{username: username, password: password}
+}
+
+// Set replaces the username and password
+func (c *Credentials) Set(username, password string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.username, c.password = username, password
+	c.generation++
+}
+
+// get returns the current username and password and how many times they
+// have been replaced
+func (c *Credentials) get() (username, password string, generation int64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.username, c.password, c.generation
+}
+
+// current reports whether generation is the latest
+func (c *Credentials) current(generation int64) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.generation == generation
+}
+
+// credentialConnector opens MySQL connections to one host with the current
+// credentials
+type credentialConnector struct {
+	cfg   *mysql.Config
+	creds *Credentials
+}
+
+// Connect implements driver.Connector
+func (c *credentialConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	username, password, generation := c.creds.get()
+	cfg := c.cfg.Clone()
+	cfg.User, cfg.Passwd = username, password
+
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &validatedConn{Conn: conn, valid: func() bool { return c.creds.current(generation) }}, nil
+}
+
+// Driver implements driver.Connector
+func (c *credentialConnector) Driver() driver.Driver {
+	return &mysql.MySQLDriver{}
+}
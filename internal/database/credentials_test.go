@@ -0,0 +1,24 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCredentials_SetRetiresOldGeneration(t *testing.T) {
+	creds := NewCredentials("v-token-app-1", "secret-1")
+
+	username, password, generation := creds.get()
+	assert.Equal(t, "v-token-app-1", username)
+	assert.Equal(t, "secret-1", password)
+
+	conn := &validatedConn{valid: func() bool { return creds.current(generation) }}
+	assert.True(t, conn.IsValid())
+
+	creds.Set("v-token-app-2", "secret-2")
+	username, password, _ = creds.get()
+	assert.Equal(t, "v-token-app-2", username)
+	assert.Equal(t, "secret-2", password)
+	assert.False(t, conn.IsValid(), "connections opened with replaced credentials are retired")
+}
@@ -0,0 +1,152 @@
+package dbtest
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/go-sql-driver/mysql"
+	mysqldialect "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// Statement operations a fault can be restricted to, named after the gorm
+// callback chains
+const (
+	Create = "create"
+	Query  = "query"
+	Update = "update"
+	Delete = "delete"
+	Row    = "row"
+	Raw    = "raw"
+)
+
+// Fault makes matching statements fail with Err instead of reaching the
+// database
+type Fault struct {
+	// Table and Operation restrict the fault to statements on a table and of
+	// an operation; empty matches every statement. Raw SQL has no table.
+	Table     string
+	Operation string
+	// Skip is how many matching statements run before the fault fires
+	Skip int
+	// Times is how many matching statements fail once it fires; 0 fails all
+	// of them until the faults are reset
+	Times int
+	Err   error
+}
+
+// Faults injects errors into the statements of a database, so tests can hit
+// the error branches of repositories and services deterministically
+type Faults struct {
+	mu     sync.Mutex
+	faults []*Fault
+	fired  int
+}
+
+// Inject registers fault injection on db and returns the faults to add to.
+// Statements fail before they are sent, as if the server had answered with
+// the error. With TranslateError set, MySQL errors are translated the way the
+// MySQL dialect translates them, whichever database db is.
+func Inject(db *gorm.DB) *Faults {
+	f := &Faults{}
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("*").Register("dbtest:fault", f.inject(Create)),
+		callbacks.Query().Before("*").Register("dbtest:fault", f.inject(Query)),
+		callbacks.Update().Before("*").Register("dbtest:fault", f.inject(Update)),
+		callbacks.Delete().Before("*").Register("dbtest:fault", f.inject(Delete)),
+		callbacks.Row().Before("*").Register("dbtest:fault", f.inject(Row)),
+		callbacks.Raw().Before("*").Register("dbtest:fault", f.inject(Raw)),
+	} {
+		if err != nil {
+			panic(fmt.Sprintf("dbtest: failed to register fault injection: %v", err))
+		}
+	}
+	return f
+}
+
+// inject returns the callback failing statements of an operation that match
+// a fault
+func (f *Faults) inject(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		err := f.match(operation, tx.Statement.Table)
+		if err == nil {
+			return
+		}
+		if tx.Config.TranslateError {
+			err = mysqldialect.Dialector{}.Translate(err)
+		}
+		tx.AddError(err)
+	}
+}
+
+// Add adds a fault; faults are matched in the order they were added
+func (f *Faults) Add(fault Fault) *Faults {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = append(f.faults, &fault)
+	return f
+}
+
+// Reset removes every fault, so later statements succeed again
+func (f *Faults) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = nil
+}
+
+// Fired returns how many statements have failed with an injected error
+func (f *Faults) Fired() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.fired
+}
+
+// match returns the error of the first fault matching a statement, if any
+func (f *Faults) match(operation, table string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, fault := range f.faults {
+		if (fault.Operation != "" && fault.Operation != operation) || (fault.Table != "" && fault.Table != table) {
+			continue
+		}
+		if fault.Skip > 0 {
+			fault.Skip--
+			continue
+		}
+		if fault.Times > 0 {
+			if fault.Times--; fault.Times == 0 {
+				f.faults = append(f.faults[:i:i], f.faults[i+1:]...)
+			}
+		}
+		f.fired++
+		return fault.Err
+	}
+	return nil
+}
+
+// Deadlock returns the error MySQL reports to the transaction it rolls back
+// to break a deadlock (ER_LOCK_DEADLOCK)
+func Deadlock() error {
+	return &mysql.MySQLError{
+		Number:   1213,
+		SQLState: [5]byte{'4', '0', '0', '0', '1'},
+		Message:  "Deadlock found when trying to get lock; try restarting transaction",
+	}
+}
+
+// DuplicateKey returns the error MySQL reports when value is already stored
+// under the unique key (ER_DUP_ENTRY)
+func DuplicateKey(value, key string) error {
+	return &mysql.MySQLError{
+		Number:   1062,
+		SQLState: [5]byte{'2', '3', '0', '0', '0'},
+		Message:  fmt.Sprintf("Duplicate entry '%s' for key '%s'", value, key),
+	}
+}
+
+// ConnectionReset returns the error the MySQL driver reports when the
+// connection breaks while a statement is running
+func ConnectionReset() error {
+	return mysql.ErrInvalidConn
+}
@@ -0,0 +1,84 @@
+package dbtest_test
+
+import (
+	"errors"
+	"testing"
+
+	"interview/internal/database/dbtest"
+	"interview/internal/models"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openDB(t *testing.T, config *gorm.Config) *gorm.DB {
+	config.Logger = logger.Default.LogMode(logger.Silent)
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), config)
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.Transaction{}))
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func TestFaults_SkipAndTimes(t *testing.T) {
+	db := openDB(t, &gorm.Config{})
+	faults := dbtest.Inject(db)
+	faults.Add(dbtest.Fault{Table: "transactions", Operation: dbtest.Query, Skip: 1, Times: 2, Err: dbtest.Deadlock()})
+
+	var count int64
+	countAll := func() error { return db.Model(&models.Transaction{}).Count(&count).Error }
+	assert.NoError(t, countAll(), "skipped")
+	for i := 0; i < 2; i++ {
+		var mysqlErr *mysql.MySQLError
+		require.True(t, errors.As(countAll(), &mysqlErr))
+		assert.Equal(t, uint16(1213), mysqlErr.Number)
+	}
+	assert.NoError(t, countAll(), "the fault is used up")
+	assert.Equal(t, 2, faults.Fired())
+
+	// Other tables and operations are not affected
+	faults.Add(dbtest.Fault{Table: "transactions", Operation: dbtest.Create, Err: dbtest.ConnectionReset()})
+	assert.NoError(t, db.Create(&models.User{Name: "Ann", Email: "ann@example.com"}).Error)
+	assert.NoError(t, countAll())
+	for i := 0; i < 3; i++ {
+		err := db.Create(&models.Transaction{UserID: 1, Amount: decimal.NewFromInt(1), Status: "pending"}).Error
+		assert.ErrorIs(t, err, mysql.ErrInvalidConn)
+	}
+
+	faults.Reset()
+	assert.NoError(t, db.Create(&models.Transaction{UserID: 1, Amount: decimal.NewFromInt(1), Status: "pending"}).Error)
+	assert.NoError(t, countAll())
+	assert.Equal(t, int64(1), count)
+}
+
+func TestFaults_RawStatements(t *testing.T) {
+	db := openDB(t, &gorm.Config{})
+	dbtest.Inject(db).Add(dbtest.Fault{Operation: dbtest.Raw, Err: dbtest.ConnectionReset()})
+
+	assert.ErrorIs(t, db.Exec("DELETE FROM transactions").Error, mysql.ErrInvalidConn)
+	var one int
+	assert.NoError(t, db.Raw("SELECT 1").Scan(&one).Error, "raw queries run as row statements")
+}
+
+func TestFaults_TranslatesLikeMySQL(t *testing.T) {
+	db := openDB(t, &gorm.Config{TranslateError: true})
+	dbtest.Inject(db).Add(dbtest.Fault{Table: "users", Err: dbtest.DuplicateKey("ann@example.com", "users.idx_users_email")})
+
+	err := db.Create(&models.User{Name: "Ann", Email: "ann@example.com"}).Error
+	assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+
+	// Untranslated, the MySQL error itself is returned
+	db = openDB(t, &gorm.Config{})
+	dbtest.Inject(db).Add(dbtest.Fault{Table: "users", Err: dbtest.DuplicateKey("ann@example.com", "users.idx_users_email")})
+	err = db.Create(&models.User{Name: "Ann", Email: "ann@example.com"}).Error
+	assert.EqualError(t, err, "Error 1062 (23000): Duplicate entry 'ann@example.com' for key 'users.idx_users_email'")
+}
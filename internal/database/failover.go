@@ -0,0 +1,255 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"expvar"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"interview/internal/config"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/sirupsen/logrus"
+	gormmysql "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// failoverEvents counts failovers across all connection pools. It is
+// published as the db_failovers expvar.
+var failoverEvents = expvar.NewInt("db_failovers")
+
+// HostProbe checks that a freshly opened connection can serve writes
+type HostProbe func(ctx context.Context, conn driver.Conn) error
+
+// ReadWriteProbe rejects hosts running with read_only set, as a demoted
+// primary does after a MySQL failover
+func ReadWriteProbe(ctx context.Context, conn driver.Conn) error {
+	queryer, ok := conn.(driver.QueryerContext)
+	if !ok {
+		return errors.New("connection does not support queries")
+	}
+	rows, err := queryer.QueryContext(ctx, "SELECT @@global.read_only", nil)
+	if err != nil {
+		return fmt.Errorf("failed to check read_only: %v", err)
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, 1)
+	if err := rows.Next(values); err != nil {
+		return fmt.Errorf("failed to check read_only: %v", err)
+	}
+
+	var readOnly bool
+	switch value := values[0].(type) {
+	case int64:
+		readOnly = value != 0
+	case []byte:
+		readOnly = string(value) != "0"
+	default:
+		return fmt.Errorf("unexpected read_only value %v", value)
+	}
+	if readOnly {
+		return errors.New("host is read-only")
+	}
+	return nil
+}
+
+// Failover is a driver.Connector over a list of database hosts. New
+// connections go to the active host, which starts as the first one; when it
+// stops answering or fails its probe, the next healthy host in the list
+// becomes active. It does not fail back on its own, since after a MySQL
+// failover the old primary usually rejoins as a replica.
+type Failover struct {
+	hosts      []string
+	connectors []driver.Connector
+	probe      HostProbe
+	active     atomic.Int64
+	failovers  atomic.Int64
+}
+
+// NewFailover creates a new failover connector. connectors[i] opens
+// connections to hosts[i].
+func NewFailover(hosts []string, connectors []driver.Connector, probe HostProbe) *Failover {
+	return &Failover{hosts: hosts, connectors: connectors, probe: probe}
+}
+
+// NewMySQLFailover creates a failover connector for a MySQL DSN, replacing
+// its address with each of hosts
+func NewMySQLFailover(dsn string, hosts []string) (*Failover, error) {
+	connectors, err := mysqlConnectors(dsn, hosts, nil)
+	if err != nil {
+		return nil, err
+	}
+	return NewFailover(hosts, connectors, ReadWriteProbe), nil
+}
+
+// mysqlConnectors creates a connector for each of hosts from a MySQL DSN.
+// With creds set, connections use its current username and password instead
+// of the DSN's.
+func mysqlConnectors(dsn string, hosts []string, creds *Credentials) ([]driver.Connector, error) {
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DSN: %v", err)
+	}
+
+	connectors := make([]driver.Connector, len(hosts))
+	for i, host := range hosts {
+		hostCfg := cfg.Clone()
+		hostCfg.Addr = host
+		if creds != nil {
+			connectors[i] = &credentialConnector{cfg: hostCfg, creds: creds}
+			continue
+		}
+		connector, err := mysql.NewConnector(hostCfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid database host %s: %v", host, err)
+		}
+		connectors[i] = connector
+	}
+	return connectors, nil
+}
+
+// Active returns the host new connections go to
+func (f *Failover) Active() string {
+	return f.hosts[f.active.Load()]
+}
+
+// Failovers returns how many times the active host has changed
+func (f *Failover) Failovers() int64 {
+	return f.failovers.Load()
+}
+
+// Driver implements driver.Connector
+func (f *Failover) Driver() driver.Driver {
+	return f.connectors[0].Driver()
+}
+
+// Connect implements driver.Connector. It connects to the active host and,
+// if that fails, to the other hosts in order; the first one that answers
+// and passes the probe becomes active.
+func (f *Failover) Connect(ctx context.Context) (driver.Conn, error) {
+	active := int(f.active.Load())
+
+	var errs []error
+	for i := range f.hosts {
+		index := (active + i) % len(f.hosts)
+		conn, err := f.open(ctx, index, index != active)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.hosts[index], err))
+			continue
+		}
+		if index != active {
+			f.switchTo(active, index, errors.Join(errs...))
+		}
+		// Connections to the old host are retired once another host is active
+		host := int64(index)
+		return &validatedConn{Conn: conn, valid: func() bool { return f.active.Load() == host }}, nil
+	}
+	return nil, fmt.Errorf("no database host is available: %w", errors.Join(errs...))
+}
+
+// Check probes the active host and fails over to the next healthy host if it
+// is unhealthy
+func (f *Failover) Check(ctx context.Context) error {
+	active := int(f.active.Load())
+	conn, err := f.open(ctx, active, true)
+	if err == nil {
+		conn.Close()
+		return nil
+	}
+
+	errs := []error{fmt.Errorf("%s: %w", f.hosts[active], err)}
+	for i := 1; i < len(f.hosts); i++ {
+		index := (active + i) % len(f.hosts)
+		conn, err := f.open(ctx, index, true)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.hosts[index], err))
+			continue
+		}
+		conn.Close()
+		f.switchTo(active, index, errs[0])
+		return nil
+	}
+	return fmt.Errorf("no database host is available: %w", errors.Join(errs...))
+}
+
+// Start checks the active host every interval until the returned stop
+// function is called
+func (f *Failover) Start(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				if err := f.Check(ctx); err != nil {
+					logrus.WithError(err).Error("Failed to check database hosts")
+				}
+				cancel()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// open connects to hosts[index], running the probe on the new connection if
+// probe is set
+func (f *Failover) open(ctx context.Context, index int, probe bool) (driver.Conn, error) {
+	conn, err := f.connectors[index].Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if probe && f.probe != nil {
+		if err := f.probe(ctx, conn); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// switchTo makes hosts[to] active unless another connection already moved
+// away from hosts[from]
+func (f *Failover) switchTo(from, to int, reason error) {
+	if !f.active.CompareAndSwap(int64(from), int64(to)) {
+		return
+	}
+	f.failovers.Add(1)
+	failoverEvents.Add(1)
+	logrus.WithError(reason).WithFields(logrus.Fields{
+		"from":      f.hosts[from],
+		"to":        f.hosts[to],
+		"failovers": f.failovers.Load(),
+	}).Warn("Database failover")
+}
+
+// MySQLDialector returns the GORM dialector for cfg. When failover hosts are
+// configured, connections go through a Failover whose health checks run for
+// the life of the process, like the pool it serves. With creds set,
+// connections use its rotating credentials instead of cfg's. Either way,
+// stale connections are detected as NewLivenessConnector describes.
+func MySQLDialector(cfg config.DatabaseConfig, creds *Credentials) (gorm.Dialector, error) {
+	connectors, err := mysqlConnectors(cfg.GetDSN(), cfg.Hosts(), creds)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.HasFailover() {
+		return gormmysql.New(gormmysql.Config{Conn: sql.OpenDB(NewLivenessConnector(connectors[0], PingAfterIdle))}), nil
+	}
+
+	failover := NewFailover(cfg.Hosts(), connectors, ReadWriteProbe)
+	failover.Start(cfg.FailoverCheckInterval)
+	return gormmysql.New(gormmysql.Config{Conn: sql.OpenDB(NewLivenessConnector(failover, PingAfterIdle))}), nil
+}
@@ -0,0 +1,111 @@
+package database_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"interview/internal/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConn is a connection to a fake host
+type fakeConn struct {
+	host string
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+// fakeConnector opens fakeConns to a host that can be taken down
+type fakeConnector struct {
+	host string
+	down bool
+}
+
+func (c *fakeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	if c.down {
+		return nil, errors.New("connection refused")
+	}
+	return &fakeConn{host: c.host}, nil
+}
+
+func (c *fakeConnector) Driver() driver.Driver {
+	return nil
+}
+
+func setupFailover(probe database.HostProbe, hosts ...string) (*database.Failover, []*fakeConnector) {
+	fakes := make([]*fakeConnector, len(hosts))
+	connectors := make([]driver.Connector, len(hosts))
+	for i, host := range hosts {
+		fakes[i] = &fakeConnector{host: host}
+		connectors[i] = fakes[i]
+	}
+	return database.NewFailover(hosts, connectors, probe), fakes
+}
+
+func TestFailover_ConnectsToNextHost(t *testing.T) {
+	failover, hosts := setupFailover(nil, "db1:3306", "db2:3306")
+
+	conn, err := failover.Connect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "db1:3306", failover.Active())
+	assert.True(t, conn.(driver.Validator).IsValid())
+
+	hosts[0].down = true
+	next, err := failover.Connect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "db2:3306", failover.Active())
+	assert.Equal(t, int64(1), failover.Failovers())
+	assert.True(t, next.(driver.Validator).IsValid())
+	assert.False(t, conn.(driver.Validator).IsValid(), "connections to the old host are retired")
+
+	hosts[1].down = true
+	_, err = failover.Connect(context.Background())
+	assert.ErrorContains(t, err, "no database host is available")
+}
+
+func TestFailover_CheckSkipsReadOnlyHosts(t *testing.T) {
+	readOnly := map[string]bool{"db1:3306": true, "db2:3306": true}
+	probe := func(ctx context.Context, conn driver.Conn) error {
+		if readOnly[conn.(*fakeConn).host] {
+			return errors.New("host is read-only")
+		}
+		return nil
+	}
+	failover, _ := setupFailover(probe, "db1:3306", "db2:3306", "db3:3306")
+
+	require.NoError(t, failover.Check(context.Background()))
+	assert.Equal(t, "db3:3306", failover.Active())
+	assert.Equal(t, int64(1), failover.Failovers())
+
+	// A healthy active host stays active, even when an earlier one recovers
+	readOnly["db1:3306"] = false
+	require.NoError(t, failover.Check(context.Background()))
+	assert.Equal(t, "db3:3306", failover.Active())
+
+	readOnly["db1:3306"], readOnly["db3:3306"] = true, true
+	assert.ErrorContains(t, failover.Check(context.Background()), "no database host is available")
+	assert.Equal(t, "db3:3306", failover.Active())
+}
+
+func TestNewMySQLFailover(t *testing.T) {
+	failover, err := database.NewMySQLFailover("root:root@tcp(127.0.0.1:3306)/trxgo?parseTime=True", []string{"db1:3306", "db2:3307"})
+	require.NoError(t, err)
+	assert.Equal(t, "db1:3306", failover.Active())
+
+	_, err = database.NewMySQLFailover("not a dsn", []string{"db1:3306"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// PingAfterIdle is how long a pooled MySQL connection may sit idle before it
+// is pinged on its next use
+const PingAfterIdle = time.Minute
+
+// pingTimeout bounds the ping of an idle connection, so a host that went away
+// without closing the connection fails it quickly
+const pingTimeout = 2 * time.Second
+
+// livenessConnector keeps stale pooled connections from failing requests.
+// MySQL closes connections idle for longer than wait_timeout, and a restart
+// or a proxy in between can drop them without the client noticing.
+type livenessConnector struct {
+	driver.Connector
+	pingAfter time.Duration
+}
+
+// NewLivenessConnector wraps connector so that connections are pinged when
+// they are taken from the pool after sitting idle for pingAfter, and reads
+// and statement preparations that find a connection dead are retried on a
+// new one. Each reports driver.ErrBadConn, which makes database/sql discard
+// the connection and try another. Writes are never retried once sent, since
+// they may have been applied before the connection died.
+func NewLivenessConnector(connector driver.Connector, pingAfter time.Duration) driver.Connector {
+	return &livenessConnector{Connector: connector, pingAfter: pingAfter}
+}
+
+// Connect implements driver.Connector
+func (c *livenessConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	validated, ok := conn.(*validatedConn)
+	if !ok {
+		validated = &validatedConn{Conn: conn, valid: func() bool { return true }}
+	}
+	return &livenessConn{validatedConn: validated, pingAfter: c.pingAfter, returned: time.Now()}, nil
+}
+
+// livenessConn is a connection that knows how long it has been idle
+type livenessConn struct {
+	*validatedConn
+	pingAfter time.Duration
+	// returned is when the pool last got the connection back. The pool
+	// serializes IsValid and ResetSession, which read and write it.
+	returned time.Time
+}
+
+// IsValid implements driver.Validator; the pool calls it when the
+// connection is returned
+func (c *livenessConn) IsValid() bool {
+	c.returned = time.Now()
+	return c.validatedConn.IsValid()
+}
+
+// ResetSession implements driver.SessionResetter; the pool calls it before
+// reusing the connection
+func (c *livenessConn) ResetSession(ctx context.Context) error {
+	if err := c.validatedConn.ResetSession(ctx); err != nil {
+		return err
+	}
+	if time.Since(c.returned) < c.pingAfter {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	if err := c.validatedConn.Ping(ctx); err != nil {
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+// PrepareContext implements driver.ConnPrepareContext. Statements with
+// arguments are prepared first unless the DSN sets interpolateParams, so
+// this is where a write finds the connection dead before anything is sent.
+func (c *livenessConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.validatedConn.PrepareContext(ctx, query)
+	if staleConnection(err) {
+		return nil, driver.ErrBadConn
+	}
+	return stmt, err
+}
+
+// QueryContext implements driver.QueryerContext
+func (c *livenessConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.validatedConn.QueryContext(ctx, query, args)
+	if staleConnection(err) {
+		return nil, driver.ErrBadConn
+	}
+	return rows, err
+}
+
+// staleConnection reports whether err means the connection was dead rather
+// than the statement failing
+func staleConnection(err error) bool {
+	return errors.Is(err, mysql.ErrInvalidConn)
+}
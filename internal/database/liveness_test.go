@@ -0,0 +1,163 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"interview/internal/database"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deadableConn is a connection whose server can go away
+type deadableConn struct {
+	fakeConn
+	dead bool
+}
+
+func (c *deadableConn) Ping(ctx context.Context) error {
+	if c.dead {
+		return mysql.ErrInvalidConn
+	}
+	return nil
+}
+
+func (c *deadableConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if c.dead {
+		return nil, mysql.ErrInvalidConn
+	}
+	return emptyRows{}, nil
+}
+
+func (c *deadableConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if c.dead {
+		return nil, mysql.ErrInvalidConn
+	}
+	return driver.RowsAffected(1), nil
+}
+
+// emptyRows is a result without rows
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string              { return []string{"id"} }
+func (emptyRows) Close() error                   { return nil }
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
+
+// deadableConnector opens deadableConns, keeping track of them
+type deadableConnector struct {
+	conns []*deadableConn
+}
+
+func (c *deadableConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn := &deadableConn{}
+	c.conns = append(c.conns, conn)
+	return conn, nil
+}
+
+func (c *deadableConnector) Driver() driver.Driver {
+	return nil
+}
+
+func TestLivenessConnector_PingsIdleConnections(t *testing.T) {
+	connector := &deadableConnector{}
+	db := sql.OpenDB(database.NewLivenessConnector(connector, 0))
+	defer db.Close()
+
+	_, err := db.Exec("UPDATE transactions SET status = 'success'")
+	require.NoError(t, err)
+	connector.conns[0].dead = true
+
+	// The dead connection fails its ping and is replaced before the write
+	_, err = db.Exec("UPDATE transactions SET status = 'success'")
+	require.NoError(t, err)
+	assert.Len(t, connector.conns, 2)
+}
+
+func TestLivenessConnector_RetriesReads(t *testing.T) {
+	connector := &deadableConnector{}
+	db := sql.OpenDB(database.NewLivenessConnector(connector, database.PingAfterIdle))
+	defer db.Close()
+
+	rows, err := db.Query("SELECT id FROM transactions")
+	require.NoError(t, err)
+	rows.Close()
+	connector.conns[0].dead = true
+
+	rows, err = db.Query("SELECT id FROM transactions")
+	require.NoError(t, err)
+	rows.Close()
+	assert.Len(t, connector.conns, 2)
+}
+
+func TestLivenessConnector_DoesNotRetryWrites(t *testing.T) {
+	connector := &deadableConnector{}
+	db := sql.OpenDB(database.NewLivenessConnector(connector, database.PingAfterIdle))
+	defer db.Close()
+
+	_, err := db.Exec("UPDATE transactions SET status = 'success'")
+	require.NoError(t, err)
+	connector.conns[0].dead = true
+
+	_, err = db.Exec("UPDATE transactions SET status = 'success'")
+	assert.ErrorIs(t, err, mysql.ErrInvalidConn)
+	assert.Len(t, connector.conns, 1)
+}
+
+// preparingConn is a deadableConn whose statements are always prepared, as
+// MySQL's are when they have arguments
+type preparingConn struct {
+	deadableConn
+}
+
+func (c *preparingConn) Prepare(query string) (driver.Stmt, error) {
+	if c.dead {
+		return nil, mysql.ErrInvalidConn
+	}
+	return fakeStmt{}, nil
+}
+
+func (c *preparingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return nil, driver.ErrSkip
+}
+
+// fakeStmt is a prepared statement that affects one row
+type fakeStmt struct{}
+
+func (fakeStmt) Close() error                                    { return nil }
+func (fakeStmt) NumInput() int                                   { return -1 }
+func (fakeStmt) Exec(args []driver.Value) (driver.Result, error) { return driver.RowsAffected(1), nil }
+func (fakeStmt) Query(args []driver.Value) (driver.Rows, error)  { return emptyRows{}, nil }
+
+// preparingConnector opens preparingConns, keeping track of them
+type preparingConnector struct {
+	conns []*preparingConn
+}
+
+func (c *preparingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn := &preparingConn{}
+	c.conns = append(c.conns, conn)
+	return conn, nil
+}
+
+func (c *preparingConnector) Driver() driver.Driver {
+	return nil
+}
+
+func TestLivenessConnector_RetriesPreparing(t *testing.T) {
+	connector := &preparingConnector{}
+	db := sql.OpenDB(database.NewLivenessConnector(connector, database.PingAfterIdle))
+	defer db.Close()
+
+	_, err := db.Exec("UPDATE transactions SET status = ? WHERE id = ?", "success", 1)
+	require.NoError(t, err)
+	connector.conns[0].dead = true
+
+	_, err = db.Exec("UPDATE transactions SET status = ? WHERE id = ?", "success", 1)
+	require.NoError(t, err)
+	assert.Len(t, connector.conns, 2)
+}
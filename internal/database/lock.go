@@ -0,0 +1,213 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// MigrationLock names the advisory lock migrators hold while they run
+const MigrationLock = "trxgo_migrations"
+
+// ErrMigrationLockTimeout is returned when another migrator held the
+// migration lock for longer than a migrator was willing to wait
+var ErrMigrationLockTimeout = errors.New("timed out waiting for the migration lock")
+
+// lockPollInterval is how often a PostgreSQL migrator retries a held lock;
+// MySQL's GET_LOCK waits by itself
+var lockPollInterval = 100 * time.Millisecond
+
+// WithMigrationLock runs migrate while holding the MigrationLock advisory
+// lock, so instances starting at the same time migrate one after the other
+// instead of racing on the same tables. It waits up to timeout for the lock.
+//
+// MySQL takes it with GET_LOCK and PostgreSQL with pg_try_advisory_lock. The
+// lock belongs to one pooled connection, held for as long as migrate runs, so
+// it is released when migrate returns or when a migrator dies and its
+// connection closes. Other databases, such as SQLite in tests, run migrate
+// without a lock.
+func WithMigrationLock(db *gorm.DB, timeout time.Duration, migrate func() error) error {
+	switch db.Dialector.Name() {
+	case "mysql", "postgres":
+	default:
+		return migrate()
+	}
+
+	return db.Connection(func(conn *gorm.DB) error {
+		if err := acquireMigrationLock(conn, timeout); err != nil {
+			return err
+		}
+		defer func() {
+			if err := releaseMigrationLock(conn); err != nil {
+				logrus.WithError(err).Warn("Failed to release migration lock")
+			}
+		}()
+		return migrate()
+	})
+}
+
+// acquireMigrationLock takes the migration lock on conn, waiting up to timeout
+func acquireMigrationLock(conn *gorm.DB, timeout time.Duration) error {
+	if conn.Dialector.Name() == "mysql" {
+		// GET_LOCK returns 1 once acquired, 0 on timeout and NULL on error
+		var acquired sql.NullInt64
+		seconds := int(math.Ceil(timeout.Seconds()))
+		if err := conn.Raw("SELECT GET_LOCK(?, ?)", MigrationLock, seconds).Scan(&acquired).Error; err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %v", err)
+		}
+		if acquired.Int64 != 1 {
+			return ErrMigrationLockTimeout
+		}
+		return nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		var acquired bool
+		if err := conn.Raw("SELECT pg_try_advisory_lock(?)", migrationLockKey()).Scan(&acquired).Error; err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %v", err)
+		}
+		if acquired {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrMigrationLockTimeout
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// releaseMigrationLock releases the migration lock conn holds
+func releaseMigrationLock(conn *gorm.DB) error {
+	if conn.Dialector.Name() == "mysql" {
+		return conn.Exec("SELECT RELEASE_LOCK(?)", MigrationLock).Error
+	}
+	return conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey()).Error
+}
+
+// migrationLockKey is MigrationLock as the bigint key PostgreSQL advisory
+// locks are named by
+func migrationLockKey() int64 {
+	return lockKey(MigrationLock)
+}
+
+// lockKey is name as a bigint PostgreSQL advisory lock key
+func lockKey(name string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(name))
+	return int64(hash.Sum64())
+}
+
+// leaseTimeout bounds each attempt to take or keep a lease
+const leaseTimeout = 5 * time.Second
+
+// Lease elects the one replica that runs a background job whose effects are
+// shared, such as writing rows or raising alerts. It is an advisory lock
+// held on a connection of its own, taken with GET_LOCK on MySQL and
+// pg_try_advisory_lock on PostgreSQL, so when the holder dies its connection
+// closes and the next replica to ask takes the lease over. Other databases,
+// such as SQLite in tests, have a single process and always hold it.
+type Lease struct {
+	db   *gorm.DB
+	name string
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+// NewLease creates a lease on the advisory lock name
+func NewLease(db *gorm.DB, name string) *Lease {
+	return &Lease{db: db, name: name}
+}
+
+// Held reports whether this process holds the lease, taking it when it is
+// free. Background loops call it before each run.
+func (l *Lease) Held() bool {
+	switch l.db.Dialector.Name() {
+	case "mysql", "postgres":
+	default:
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), leaseTimeout)
+	defer cancel()
+
+	if l.conn != nil {
+		if err := l.conn.PingContext(ctx); err == nil {
+			return true
+		}
+		// The lock went with the connection
+		logrus.WithField("lease", l.name).Warn("Lost lease")
+		l.conn.Close()
+		l.conn = nil
+	}
+
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return false
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false
+	}
+	acquired, err := l.tryLock(ctx, conn)
+	if err != nil || !acquired {
+		if err != nil {
+			logrus.WithError(err).WithField("lease", l.name).Warn("Failed to take lease")
+		}
+		conn.Close()
+		return false
+	}
+
+	logrus.WithField("lease", l.name).Info("Took lease")
+	l.conn = conn
+	return true
+}
+
+// tryLock takes the lease's lock on conn without waiting
+func (l *Lease) tryLock(ctx context.Context, conn *sql.Conn) (bool, error) {
+	if l.db.Dialector.Name() == "mysql" {
+		var acquired sql.NullInt64
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", l.name).Scan(&acquired); err != nil {
+			return false, err
+		}
+		return acquired.Int64 == 1, nil
+	}
+	var acquired bool
+	err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", lockKey(l.name)).Scan(&acquired)
+	return acquired, err
+}
+
+// Release gives the lease up if this process holds it, so another replica
+// can take it over without waiting for this one's connection to close
+func (l *Lease) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), leaseTimeout)
+	defer cancel()
+
+	query, arg := "SELECT RELEASE_LOCK(?)", interface{}(l.name)
+	if l.db.Dialector.Name() != "mysql" {
+		query, arg = "SELECT pg_advisory_unlock($1)", lockKey(l.name)
+	}
+	if _, err := l.conn.ExecContext(ctx, query, arg); err != nil {
+		logrus.WithError(err).WithField("lease", l.name).Warn("Failed to release lease")
+	}
+	l.conn.Close()
+	l.conn = nil
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeLock is the lock held through GET_LOCK and RELEASE_LOCK on sqliteLocks
+// connections
+var fakeLock = make(chan struct{}, 1)
+
+func init() {
+	sql.Register("sqlite3_locks", &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			if err := conn.RegisterFunc("GET_LOCK", func(name string, seconds int64) int64 {
+				select {
+				case fakeLock <- struct{}{}:
+					return 1
+				default:
+				}
+				select {
+				case fakeLock <- struct{}{}:
+					return 1
+				case <-time.After(time.Duration(seconds) * time.Second):
+					return 0
+				}
+			}, false); err != nil {
+				return err
+			}
+			return conn.RegisterFunc("RELEASE_LOCK", func(name string) int64 {
+				<-fakeLock
+				return 1
+			}, false)
+		},
+	})
+}
+
+// mysqlNamed is a SQLite dialector named mysql, so WithMigrationLock takes
+// the lock with GET_LOCK
+type mysqlNamed struct {
+	gorm.Dialector
+}
+
+func (mysqlNamed) Name() string { return "mysql" }
+
+func openLockingDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(mysqlNamed{sqlite.New(sqlite.Config{DriverName: "sqlite3_locks", DSN: ":memory:"})}, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func TestWithMigrationLock_RunsOneMigratorAtATime(t *testing.T) {
+	db := openLockingDB(t)
+
+	var running, overlaps int32
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, WithMigrationLock(db, time.Second, func() error {
+				if atomic.AddInt32(&running, 1) > 1 {
+					atomic.AddInt32(&overlaps, 1)
+				}
+				time.Sleep(20 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return nil
+			}))
+		}()
+	}
+	wg.Wait()
+
+	assert.Zero(t, overlaps)
+	assert.Empty(t, fakeLock, "the lock should be released")
+}
+
+func TestWithMigrationLock_ReleasesAfterFailure(t *testing.T) {
+	db := openLockingDB(t)
+
+	err := WithMigrationLock(db, time.Second, func() error { return errors.New("duplicate column") })
+	assert.EqualError(t, err, "duplicate column")
+	assert.Empty(t, fakeLock, "the lock should be released")
+}
+
+func TestWithMigrationLock_Timeout(t *testing.T) {
+	db := openLockingDB(t)
+
+	// Another migrator holds the lock
+	fakeLock <- struct{}{}
+	defer func() { <-fakeLock }()
+
+	ran := false
+	err := WithMigrationLock(db, time.Millisecond, func() error {
+		ran = true
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrMigrationLockTimeout)
+	assert.False(t, ran)
+}
+
+func TestWithMigrationLock_WithoutAdvisoryLocks(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+
+	ran := false
+	assert.NoError(t, WithMigrationLock(db, time.Second, func() error {
+		ran = true
+		return nil
+	}))
+	assert.True(t, ran)
+}
+
+func TestLease_OneHolderAtATime(t *testing.T) {
+	db := openLockingDB(t)
+	first, second := NewLease(db, "trxgo_probe"), NewLease(db, "trxgo_probe")
+
+	assert.True(t, first.Held())
+	assert.True(t, first.Held(), "the holder should keep the lease")
+	assert.False(t, second.Held())
+
+	// The other replica takes the lease over once it is released
+	first.Release()
+	assert.True(t, second.Held())
+	assert.False(t, first.Held())
+
+	second.Release()
+	assert.Empty(t, fakeLock, "the lock should be released")
+}
+
+func TestLease_WithoutAdvisoryLocks(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+
+	lease := NewLease(db, "trxgo_probe")
+	assert.True(t, lease.Held())
+	lease.Release()
+}
@@ -0,0 +1,162 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"interview/internal/requestid"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// QueryLogger is a GORM logger that writes structured query logs through
+// logrus and optionally captures the EXPLAIN plan of slow queries
+type QueryLogger struct {
+	level         logger.LogLevel
+	slowThreshold time.Duration
+	explain       bool
+	db            *gorm.DB
+}
+
+// NewQueryLogger creates a new query logger. Queries slower than
+// slowThreshold are logged as warnings; a zero threshold disables slow
+// query detection.
+func NewQueryLogger(slowThreshold time.Duration, explain bool) *QueryLogger {
+	return &QueryLogger{
+		level:         logger.Warn,
+		slowThreshold: slowThreshold,
+		explain:       explain,
+	}
+}
+
+// Attach sets the connection used to run EXPLAIN for slow queries. With
+// EXPLAIN enabled it also registers callbacks that keep each query's SQL and
+// bind parameters, so the plan is requested with the query's own parameters
+// rather than with values interpolated into the logged SQL.
+func (l *QueryLogger) Attach(db *gorm.DB) error {
+	l.db = db
+	if !l.explain {
+		return nil
+	}
+	if err := db.Callback().Query().After("gorm:query").Register("trxgo:bound_query", captureBoundQuery); err != nil {
+		return err
+	}
+	return db.Callback().Row().After("gorm:row").Register("trxgo:bound_query", captureBoundQuery)
+}
+
+// boundQueryKey is the context key of a statement's boundQuery
+type boundQueryKey struct{}
+
+// boundQuery is a statement's SQL with placeholders and its bind parameters
+type boundQuery struct {
+	sql  string
+	vars []interface{}
+}
+
+// captureBoundQuery adds the statement's SQL and bind parameters to its
+// context, which GORM passes on to Trace
+func captureBoundQuery(db *gorm.DB) {
+	stmt := db.Statement
+	if stmt.SQL.Len() == 0 || stmt.Context == nil {
+		return
+	}
+	vars := make([]interface{}, len(stmt.Vars))
+	copy(vars, stmt.Vars)
+	stmt.Context = context.WithValue(stmt.Context, boundQueryKey{}, boundQuery{sql: stmt.SQL.String(), vars: vars})
+}
+
+// LogMode returns a copy of the logger with the given level
+func (l *QueryLogger) LogMode(level logger.LogLevel) logger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+// Info logs info messages
+func (l *QueryLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Info {
+		requestid.Logger(ctx).Infof(msg, data...)
+	}
+}
+
+// Warn logs warning messages
+func (l *QueryLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Warn {
+		requestid.Logger(ctx).Warnf(msg, data...)
+	}
+}
+
+// Error logs error messages
+func (l *QueryLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Error {
+		requestid.Logger(ctx).Errorf(msg, data...)
+	}
+}
+
+// Trace logs a finished query
+func (l *QueryLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	sql, rows := fc()
+	fields := logrus.Fields{
+		"sql":     sql,
+		"rows":    rows,
+		"latency": elapsed,
+	}
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= logger.Error:
+		requestid.Logger(ctx).WithFields(fields).WithError(err).Error("Query failed")
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= logger.Warn:
+		fields["slow_threshold"] = l.slowThreshold
+		if l.explain {
+			if plan, err := l.explainQuery(ctx, sql); err != nil {
+				fields["explain_error"] = err.Error()
+			} else if plan != nil {
+				fields["plan"] = plan
+			}
+		}
+		requestid.Logger(ctx).WithFields(fields).Warn("Slow query")
+	case l.level >= logger.Info:
+		requestid.Logger(ctx).WithFields(fields).Debug("Query")
+	}
+}
+
+// explainQuery runs EXPLAIN for the SELECT statement logged as sql. The
+// statement's own SQL is sent with its bind parameters, never the logged SQL,
+// whose interpolated values are only escaped for reading. A statement
+// without a bound query captured for it, or whose captured query is another
+// statement's from a reused session, is not explained.
+func (l *QueryLogger) explainQuery(ctx context.Context, sql string) ([]map[string]interface{}, error) {
+	if l.db == nil || !strings.HasPrefix(strings.ToUpper(strings.TrimSpace(sql)), "SELECT") {
+		return nil, nil
+	}
+	bound, ok := ctx.Value(boundQueryKey{}).(boundQuery)
+	if !ok || l.db.Dialector.Explain(bound.sql, bound.vars...) != sql {
+		return nil, nil
+	}
+
+	db := l.db.Session(&gorm.Session{Logger: logger.Discard})
+	rows, err := db.Statement.ConnPool.QueryContext(context.Background(), "EXPLAIN "+bound.sql, bound.vars...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var plan []map[string]interface{}
+	for rows.Next() {
+		row := map[string]interface{}{}
+		if err := db.ScanRows(rows, &row); err != nil {
+			return nil, err
+		}
+		plan = append(plan, row)
+	}
+	return plan, rows.Err()
+}
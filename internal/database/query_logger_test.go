@@ -0,0 +1,91 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"interview/internal/database"
+	"interview/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupLoggedDB(t *testing.T, queryLogger *database.QueryLogger) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: queryLogger})
+	require.NoError(t, err)
+	require.NoError(t, queryLogger.Attach(db))
+	require.NoError(t, db.AutoMigrate(&models.Transaction{}))
+	return db
+}
+
+func TestQueryLogger_SlowQueryWithExplain(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	db := setupLoggedDB(t, database.NewQueryLogger(time.Nanosecond, true))
+	hook.Reset()
+
+	var transactions []models.Transaction
+	db.Where("user_id = ?", 1).Find(&transactions)
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.WarnLevel, entry.Level)
+	assert.Equal(t, "Slow query", entry.Message)
+	assert.Contains(t, entry.Data["sql"], "user_id = 1")
+	assert.NotEmpty(t, entry.Data["plan"])
+}
+
+func TestQueryLogger_ExplainBindsParameters(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	db := setupLoggedDB(t, database.NewQueryLogger(time.Nanosecond, true))
+	hook.Reset()
+
+	// A value that would end the string literal under backslash escaping is
+	// sent as a bind parameter, not spliced into the EXPLAIN statement
+	var transactions []models.Transaction
+	db.Where("description = ?", `x\' OR 1=1; DROP TABLE transactions; --`).Find(&transactions)
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, "Slow query", entry.Message)
+	assert.NotContains(t, entry.Data, "explain_error")
+	assert.NotEmpty(t, entry.Data["plan"])
+	assert.True(t, db.Migrator().HasTable(&models.Transaction{}))
+}
+
+func TestQueryLogger_SlowQueryWithoutExplain(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	db := setupLoggedDB(t, database.NewQueryLogger(time.Nanosecond, false))
+	hook.Reset()
+
+	var transactions []models.Transaction
+	db.Find(&transactions)
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, "Slow query", entry.Message)
+	assert.NotContains(t, entry.Data, "plan")
+}
+
+func TestQueryLogger_FastQueryNotLogged(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	db := setupLoggedDB(t, database.NewQueryLogger(time.Hour, true))
+	hook.Reset()
+
+	var transactions []models.Transaction
+	db.Find(&transactions)
+
+	assert.Empty(t, hook.AllEntries())
+}
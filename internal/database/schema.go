@@ -0,0 +1,146 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"interview/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrSchemaMismatch is returned when the database was migrated past the
+// schema a binary expects
+var ErrSchemaMismatch = errors.New("database schema is newer than this binary")
+
+// SchemaVersion returns a short fingerprint of the schema the given models
+// migrate to. Migrations are driven by GORM AutoMigrate rather than numbered
+// files, so the fingerprint changes whenever a table, column type or size
+// changes and identifies which schema a running binary expects.
+func SchemaVersion(db *gorm.DB, models ...interface{}) (string, error) {
+	var definitions []string
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return "", fmt.Errorf("failed to parse schema for %T: %w", model, err)
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			definitions = append(definitions, fmt.Sprintf("%s.%s %s(%d,%d,%d)",
+				stmt.Schema.Table, field.DBName, field.DataType, field.Size, field.Precision, field.Scale))
+		}
+	}
+	sort.Strings(definitions)
+
+	sum := sha256.Sum256([]byte(strings.Join(definitions, "\n")))
+	return hex.EncodeToString(sum[:])[:12], nil
+}
+
+// ConfiguredSchemaVersion returns the SchemaVersion of models.SchemaModels
+// with the amount columns at decimal(precision,scale), as the migrations
+// leave them. The amount type is applied to the parsed schemas first, so the
+// fingerprint is the same whether or not ConfigureAmountColumn has run on db.
+func ConfiguredSchemaVersion(db *gorm.DB, precision, scale int) (string, error) {
+	if err := applyAmountColumns(db, precision, scale); err != nil {
+		return "", err
+	}
+	return SchemaVersion(db, models.SchemaModels()...)
+}
+
+// CheckConfiguredSchema returns the ConfiguredSchemaVersion of this binary
+// after checking it against db with CheckSchemaVersion
+func CheckConfiguredSchema(db *gorm.DB, precision, scale int) (string, error) {
+	version, err := ConfiguredSchemaVersion(db, precision, scale)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute schema version: %w", err)
+	}
+	if err := CheckSchemaVersion(db, version); err != nil {
+		return "", err
+	}
+	return version, nil
+}
+
+// CheckMigrations returns an error naming the first table or column of the
+// given models that db lacks, which means the migrations have not been
+// applied to it
+func CheckMigrations(db *gorm.DB, models ...interface{}) error {
+	migrator := db.Migrator()
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return fmt.Errorf("failed to parse schema for %T: %w", model, err)
+		}
+		table := stmt.Schema.Table
+
+		if !migrator.HasTable(model) {
+			return fmt.Errorf("table %s is missing", table)
+		}
+		columnTypes, err := migrator.ColumnTypes(model)
+		if err != nil {
+			return fmt.Errorf("failed to read columns of %s: %w", table, err)
+		}
+		columns := make(map[string]bool, len(columnTypes))
+		for _, column := range columnTypes {
+			columns[column.Name()] = true
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !columns[field.DBName] {
+				return fmt.Errorf("column %s.%s is missing", table, field.DBName)
+			}
+		}
+	}
+	return nil
+}
+
+// RecordSchemaVersion records in the schema_migrations table that db was
+// migrated to version, unless that is already the latest version recorded
+func RecordSchemaVersion(db *gorm.DB, version string) error {
+	if err := db.AutoMigrate(&models.SchemaMigration{}); err != nil {
+		return fmt.Errorf("failed to migrate schema_migrations: %w", err)
+	}
+
+	var latest []models.SchemaMigration
+	if err := db.Order("id DESC").Limit(1).Find(&latest).Error; err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if len(latest) > 0 && latest[0].Version == version {
+		return nil
+	}
+	return db.Create(&models.SchemaMigration{Version: version, AppliedAt: time.Now().UTC()}).Error
+}
+
+// CheckSchemaVersion returns ErrSchemaMismatch when a binary expecting
+// version would run against a newer schema: the latest version recorded in
+// the schema_migrations table differs from version, which was itself
+// recorded before it. A version never recorded belongs to a newer binary
+// whose migrations have yet to run, and a database with no versions
+// recorded has not been migrated by one yet, so both pass.
+func CheckSchemaVersion(db *gorm.DB, version string) error {
+	if !db.Migrator().HasTable(&models.SchemaMigration{}) {
+		return nil
+	}
+
+	var history []models.SchemaMigration
+	if err := db.Order("id DESC").Find(&history).Error; err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if len(history) == 0 || history[0].Version == version {
+		return nil
+	}
+	for _, migration := range history[1:] {
+		if migration.Version == version {
+			return fmt.Errorf("%w: migrated to %s at %s, after this binary's %s",
+				ErrSchemaMismatch, history[0].Version, history[0].AppliedAt.UTC().Format(time.RFC3339), version)
+		}
+	}
+	return nil
+}
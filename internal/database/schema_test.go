@@ -0,0 +1,72 @@
+package database
+
+import (
+	"testing"
+
+	"interview/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+
+	version, err := SchemaVersion(db, &models.Transaction{}, &models.Budget{})
+	require.NoError(t, err)
+	assert.Len(t, version, 12)
+
+	// Order of models does not matter
+	reordered, err := SchemaVersion(db, &models.Budget{}, &models.Transaction{})
+	require.NoError(t, err)
+	assert.Equal(t, version, reordered)
+
+	// A different set of tables yields a different version
+	partial, err := SchemaVersion(db, &models.Transaction{})
+	require.NoError(t, err)
+	assert.NotEqual(t, version, partial)
+}
+
+func TestCheckMigrations(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+
+	// Nothing migrated yet
+	assert.EqualError(t, CheckMigrations(db, &models.Budget{}), "table budgets is missing")
+
+	require.NoError(t, db.AutoMigrate(&models.Budget{}))
+	assert.NoError(t, CheckMigrations(db, &models.Budget{}))
+
+	// A column added to the model since the last migration
+	require.NoError(t, db.Migrator().DropColumn(&models.Budget{}, "block_overage"))
+	assert.EqualError(t, CheckMigrations(db, &models.Budget{}), "column budgets.block_overage is missing")
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+
+	// Nothing recorded yet
+	assert.NoError(t, CheckSchemaVersion(db, "aaaaaaaaaaaa"))
+
+	require.NoError(t, RecordSchemaVersion(db, "aaaaaaaaaaaa"))
+	require.NoError(t, RecordSchemaVersion(db, "aaaaaaaaaaaa"))
+	var count int64
+	require.NoError(t, db.Model(&models.SchemaMigration{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count, "the latest version is not recorded twice")
+	assert.NoError(t, CheckSchemaVersion(db, "aaaaaaaaaaaa"))
+
+	// A newer binary migrates past it
+	assert.NoError(t, CheckSchemaVersion(db, "bbbbbbbbbbbb"))
+	require.NoError(t, RecordSchemaVersion(db, "bbbbbbbbbbbb"))
+	assert.NoError(t, CheckSchemaVersion(db, "bbbbbbbbbbbb"))
+
+	// The older binary is refused
+	err = CheckSchemaVersion(db, "aaaaaaaaaaaa")
+	assert.ErrorIs(t, err, ErrSchemaMismatch)
+	assert.Contains(t, err.Error(), "migrated to bbbbbbbbbbbb")
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"fmt"
+
+	"interview/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateUsers creates the users table and a placeholder user for every user
+// ID that existing transactions reference without one, so the foreign key
+// from transactions to users can be added to a populated database. It must
+// run before the transactions table is migrated.
+func MigrateUsers(db *gorm.DB) error {
+	if err := db.AutoMigrate(&models.User{}); err != nil {
+		return fmt.Errorf("failed to migrate users table: %w", err)
+	}
+	if !db.Migrator().HasTable(&models.Transaction{}) {
+		return nil
+	}
+
+	var ids []uint
+	err := db.Model(&models.Transaction{}).
+		Distinct("user_id").
+		Where("user_id NOT IN (?)", db.Model(&models.User{}).Select("id")).
+		Pluck("user_id", &ids).Error
+	if err != nil {
+		return fmt.Errorf("failed to find transaction users: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	users := make([]models.User, len(ids))
+	for i, id := range ids {
+		users[i] = models.PlaceholderUser(id)
+	}
+	if err := db.CreateInBatches(users, 500).Error; err != nil {
+		return fmt.Errorf("failed to create placeholder users: %w", err)
+	}
+	return nil
+}
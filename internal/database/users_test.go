@@ -0,0 +1,38 @@
+package database
+
+import (
+	"testing"
+
+	"interview/internal/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestMigrateUsers(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+
+	// A fresh database has no transactions to find users for
+	require.NoError(t, MigrateUsers(db))
+
+	require.NoError(t, db.AutoMigrate(&models.Transaction{}))
+	require.NoError(t, db.Create(&models.User{ID: 1, Name: "Ada", Email: "ada@example.com"}).Error)
+	for _, userID := range []uint{1, 2, 2, 3} {
+		require.NoError(t, db.Create(&models.Transaction{UserID: userID, Amount: decimal.NewFromInt(10)}).Error)
+	}
+
+	require.NoError(t, MigrateUsers(db))
+	require.NoError(t, MigrateUsers(db))
+
+	var users []models.User
+	require.NoError(t, db.Order("id").Find(&users).Error)
+	require.Len(t, users, 3)
+	assert.Equal(t, "Ada", users[0].Name)
+	assert.Equal(t, models.PlaceholderUser(2).Email, users[1].Email)
+	assert.Equal(t, models.PlaceholderUser(3).Name, users[2].Name)
+}
@@ -0,0 +1,90 @@
+package deprecation
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"interview/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Headers announcing deprecations: Deprecation (RFC 9745) holds when the
+// endpoint or a field it returns was deprecated, Sunset (RFC 8594) when it
+// will stop working, and a Link with rel="deprecation" the migration notes
+const (
+	Header       = "Deprecation"
+	SunsetHeader = "Sunset"
+)
+
+// contextKey is the gin context key holding the request's warnings
+const contextKey = "deprecation_warnings"
+
+// Notice describes a deprecated endpoint or response field
+type Notice struct {
+	// Field names the deprecated response field; "" deprecates the endpoint
+	Field string
+	// Message tells clients what to use instead
+	Message string
+	// Since is when it was deprecated
+	Since time.Time
+	// Sunset, when set, is when it will be removed
+	Sunset time.Time
+	// Link points to migration notes
+	Link string
+}
+
+// Endpoint marks every request of the routes it runs on as deprecated
+func Endpoint(notice Notice) gin.HandlerFunc {
+	notice.Field = ""
+	return func(c *gin.Context) {
+		Mark(c, notice)
+		c.Next()
+	}
+}
+
+// Mark announces notice on the response to c, in the headers and in the
+// warnings of the response envelope. Handlers mark deprecated fields when
+// they return them, before writing the response. With several notices the
+// headers carry the earliest deprecation and sunset.
+func Mark(c *gin.Context, notice Notice) {
+	warning := models.Warning{Code: models.WarningDeprecated, Field: notice.Field, Message: notice.Message}
+	if !notice.Sunset.IsZero() {
+		sunset := notice.Sunset.UTC()
+		warning.Sunset = &sunset
+	}
+	c.Set(contextKey, append(Warnings(c), warning))
+
+	header := c.Writer.Header()
+	if since, ok := parseDeprecation(header.Get(Header)); !ok || notice.Since.Before(since) {
+		header.Set(Header, "@"+strconv.FormatInt(notice.Since.Unix(), 10))
+	}
+	if !notice.Sunset.IsZero() {
+		if sunset, err := http.ParseTime(header.Get(SunsetHeader)); err != nil || notice.Sunset.Before(sunset) {
+			header.Set(SunsetHeader, notice.Sunset.UTC().Format(http.TimeFormat))
+		}
+	}
+	if notice.Link != "" {
+		header.Add("Link", "<"+notice.Link+`>; rel="deprecation"`)
+	}
+}
+
+// Warnings returns the warnings marked on the response to c
+func Warnings(c *gin.Context) []models.Warning {
+	warnings, _ := c.Get(contextKey)
+	list, _ := warnings.([]models.Warning)
+	return list
+}
+
+// parseDeprecation reads a Deprecation header value, an @ and Unix seconds
+func parseDeprecation(value string) (time.Time, bool) {
+	if len(value) < 2 || value[0] != '@' {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(value[1:], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
@@ -0,0 +1,93 @@
+package deprecation_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"interview/internal/deprecation"
+	"interview/internal/models"
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	since  = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	sunset = time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func serve(handlers ...gin.HandlerFunc) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/test", handlers...)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/test?limit=10", nil))
+	return w
+}
+
+func decode(t *testing.T, w *httptest.ResponseRecorder) models.APIResponse {
+	var response models.APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func TestEndpoint(t *testing.T) {
+	notice := deprecation.Notice{Message: "Use /new instead", Since: since, Sunset: sunset, Link: "https://example.com/migrate"}
+	w := serve(deprecation.Endpoint(notice), func(c *gin.Context) {
+		utils.SuccessResponse(c, nil, "OK")
+	})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "@1790812800", w.Header().Get(deprecation.Header))
+	assert.Equal(t, "Thu, 01 Apr 2027 00:00:00 GMT", w.Header().Get(deprecation.SunsetHeader))
+	assert.Equal(t, `<https://example.com/migrate>; rel="deprecation"`, w.Header().Get("Link"))
+
+	response := decode(t, w)
+	require.Len(t, response.Warnings, 1)
+	assert.Equal(t, models.WarningDeprecated, response.Warnings[0].Code)
+	assert.Empty(t, response.Warnings[0].Field)
+	assert.Equal(t, "Use /new instead", response.Warnings[0].Message)
+	require.NotNil(t, response.Warnings[0].Sunset)
+	assert.True(t, response.Warnings[0].Sunset.Equal(sunset))
+}
+
+func TestMark_Fields(t *testing.T) {
+	w := serve(func(c *gin.Context) {
+		deprecation.Mark(c, deprecation.Notice{Field: "pagination.page", Message: "Use offset instead", Since: since})
+		deprecation.Mark(c, deprecation.Notice{Field: "data.kind", Message: "Use type instead", Since: since.AddDate(0, -1, 0), Sunset: sunset})
+		deprecation.Mark(c, deprecation.Notice{Field: "data.legacy", Message: "Removed soon", Since: since, Sunset: sunset.AddDate(0, -1, 0), Link: "https://example.com/legacy"})
+		utils.SetPaginationLinks(c, 10, 0, true)
+		utils.PaginatedResponse(c, []models.Transaction{}, models.NewPagination(20, 10, 0, 0), "OK")
+	})
+
+	// The earliest deprecation and sunset win
+	assert.Equal(t, "@1788220800", w.Header().Get(deprecation.Header))
+	assert.Equal(t, "Mon, 01 Mar 2027 00:00:00 GMT", w.Header().Get(deprecation.SunsetHeader))
+	links := w.Header().Values("Link")
+	require.Len(t, links, 2, "pagination links keep the deprecation link")
+	assert.Equal(t, `<https://example.com/legacy>; rel="deprecation"`, links[0])
+	assert.Contains(t, links[1], `rel="next"`)
+
+	response := decode(t, w)
+	require.Len(t, response.Warnings, 3)
+	assert.Equal(t, "pagination.page", response.Warnings[0].Field)
+	assert.Nil(t, response.Warnings[0].Sunset)
+	assert.Equal(t, "data.kind", response.Warnings[1].Field)
+	assert.Equal(t, "data.legacy", response.Warnings[2].Field)
+}
+
+func TestWarnings_NoneMarked(t *testing.T) {
+	w := serve(func(c *gin.Context) {
+		assert.Empty(t, deprecation.Warnings(c))
+		utils.SuccessResponse(c, nil, "OK")
+	})
+
+	assert.Empty(t, w.Header().Get(deprecation.Header))
+	assert.NotContains(t, w.Body.String(), "warnings")
+}
@@ -0,0 +1,86 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Transaction event types
+const (
+	TransactionCreated       = "transaction.created"
+	TransactionStatusChanged = "transaction.status_changed"
+	TransactionUpdated       = "transaction.updated"
+	TransactionDeleted       = "transaction.deleted"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before events are dropped for it
+const subscriberBuffer = 16
+
+// Event describes a change to a transaction
+type Event struct {
+	Type          string `json:"type"`
+	TransactionID uint   `json:"transaction_id"`
+	UserID        uint   `json:"user_id,omitempty"`
+	Status        string `json:"status,omitempty"`
+	// Reason is the client's explanation of a status change, if it gave one
+	Reason     string    `json:"reason,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Broker publishes transaction events to subscribers. Bus delivers them
+// within the process; RedisBroker delivers them to every instance.
+type Broker interface {
+	Publish(event Event)
+	Subscribe() (<-chan Event, func())
+}
+
+// Bus is an in-process publish/subscribe bus for transaction events
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[int]chan Event
+	nextID      int
+}
+
+// NewBus creates a new event bus
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[int]chan Event)}
+}
+
+// Publish delivers an event to every subscriber without blocking; subscribers
+// whose buffer is full miss the event
+func (b *Bus) Publish(event Event) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving all published events and a function
+// that cancels the subscription
+func (b *Bus) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = ch
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, id)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package events_test
+
+import (
+	"testing"
+
+	"interview/internal/events"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus_PublishSubscribe(t *testing.T) {
+	bus := events.NewBus()
+	first, cancelFirst := bus.Subscribe()
+	second, cancelSecond := bus.Subscribe()
+	defer cancelSecond()
+
+	bus.Publish(events.Event{Type: events.TransactionStatusChanged, TransactionID: 1, Status: "success"})
+
+	for _, ch := range []<-chan events.Event{first, second} {
+		event := <-ch
+		assert.Equal(t, events.TransactionStatusChanged, event.Type)
+		assert.Equal(t, uint(1), event.TransactionID)
+		assert.False(t, event.OccurredAt.IsZero())
+	}
+
+	// A cancelled subscription is closed and no longer receives events
+	cancelFirst()
+	cancelFirst()
+	bus.Publish(events.Event{Type: events.TransactionDeleted, TransactionID: 1})
+	_, ok := <-first
+	assert.False(t, ok)
+	assert.Equal(t, events.TransactionDeleted, (<-second).Type)
+}
+
+func TestBus_PublishDoesNotBlock(t *testing.T) {
+	bus := events.NewBus()
+	ch, cancel := bus.Subscribe()
+	defer cancel()
+
+	// Nobody reads, publishing past the buffer drops events
+	for i := 0; i < 100; i++ {
+		bus.Publish(events.Event{Type: events.TransactionCreated, TransactionID: uint(i)})
+	}
+
+	assert.Equal(t, uint(0), (<-ch).TransactionID)
+}
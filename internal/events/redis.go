@@ -0,0 +1,82 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// RedisChannel is the Redis pub/sub channel transaction events are published on
+const RedisChannel = "trxgo:transaction-events"
+
+// RedisBroker shares transaction events between instances through Redis
+// pub/sub. Events published by any instance, including this one, are relayed
+// to the local subscribers once Redis delivers them.
+type RedisBroker struct {
+	client *redis.Client
+	local  *Bus
+	pubsub *redis.PubSub
+	done   chan struct{}
+}
+
+// NewRedisBroker subscribes to RedisChannel and starts relaying its events;
+// call Close to stop
+func NewRedisBroker(ctx context.Context, client *redis.Client) (*RedisBroker, error) {
+	pubsub := client.Subscribe(ctx, RedisChannel)
+	// Wait for the subscription to be confirmed so no event published after
+	// this returns is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	b := &RedisBroker{client: client, local: NewBus(), pubsub: pubsub, done: make(chan struct{})}
+	go b.relay()
+	return b, nil
+}
+
+// relay delivers events received from Redis to the local subscribers
+func (b *RedisBroker) relay() {
+	defer close(b.done)
+	for msg := range b.pubsub.Channel() {
+		var event Event
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			logrus.WithError(err).Warn("Ignoring malformed transaction event")
+			continue
+		}
+		b.local.Publish(event)
+	}
+}
+
+// Publish sends an event to every instance. Like Bus, it never blocks the
+// caller on subscribers; a failure to reach Redis is logged and the event is lost.
+func (b *RedisBroker) Publish(event Event) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to encode transaction event")
+		return
+	}
+	if err := b.client.Publish(context.Background(), RedisChannel, payload).Err(); err != nil {
+		logrus.WithError(err).WithField("type", event.Type).Warn("Failed to publish transaction event")
+	}
+}
+
+// Subscribe returns a channel receiving the events of every instance and a
+// function that cancels the subscription
+func (b *RedisBroker) Subscribe() (<-chan Event, func()) {
+	return b.local.Subscribe()
+}
+
+// Close stops relaying events
+func (b *RedisBroker) Close() error {
+	err := b.pubsub.Close()
+	<-b.done
+	return err
+}
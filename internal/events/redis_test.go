@@ -0,0 +1,61 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"interview/internal/events"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRedisBroker(t *testing.T, addr string) *events.RedisBroker {
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { client.Close() })
+
+	broker, err := events.NewRedisBroker(context.Background(), client)
+	require.NoError(t, err)
+	t.Cleanup(func() { broker.Close() })
+	return broker
+}
+
+func TestRedisBroker_DeliversToEveryInstance(t *testing.T) {
+	server := miniredis.RunT(t)
+	publisher := newRedisBroker(t, server.Addr())
+	other := newRedisBroker(t, server.Addr())
+
+	local, cancelLocal := publisher.Subscribe()
+	defer cancelLocal()
+	remote, cancelRemote := other.Subscribe()
+	defer cancelRemote()
+
+	publisher.Publish(events.Event{Type: events.TransactionStatusChanged, TransactionID: 1, Status: "success"})
+
+	for _, ch := range []<-chan events.Event{local, remote} {
+		select {
+		case event := <-ch:
+			assert.Equal(t, events.TransactionStatusChanged, event.Type)
+			assert.Equal(t, uint(1), event.TransactionID)
+			assert.Equal(t, "success", event.Status)
+			assert.False(t, event.OccurredAt.IsZero())
+		case <-time.After(2 * time.Second):
+			t.Fatal("event was not delivered")
+		}
+	}
+}
+
+func TestRedisBroker_SubscribeFailsWithoutServer(t *testing.T) {
+	server := miniredis.RunT(t)
+	addr := server.Addr()
+	server.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: addr, MaxRetries: -1})
+	defer client.Close()
+
+	_, err := events.NewRedisBroker(context.Background(), client)
+	assert.Error(t, err)
+}
@@ -0,0 +1,168 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"interview/internal/models"
+	"interview/pkg/money"
+
+	"github.com/shopspring/decimal"
+)
+
+// Status ratios of generated transactions. Pending transactions are only
+// generated for the last pendingWindow, as older ones would have settled.
+const (
+	successRatio = 0.85
+	failedRatio  = 0.05
+)
+
+// pendingWindow is how recent a generated transaction must be to be pending
+const pendingWindow = 48 * time.Hour
+
+// Shares of generated transactions that are adjustments and credits
+const (
+	adjustmentRatio = 0.03
+	creditRatio     = 0.10
+)
+
+// Amounts are log-normally distributed around a median of about 33.00,
+// like card payments: mostly small, with a long tail of large ones
+const (
+	amountMu    = 3.5
+	amountSigma = 1.0
+	maxAmount   = 10000
+)
+
+// userActivitySkew is the Zipf exponent of how transactions are spread over
+// users; a few users make most of them
+const userActivitySkew = 1.2
+
+// budgetRatio is the share of generated users that get a monthly budget
+const budgetRatio = 0.2
+
+var (
+	firstNames = []string{"Ada", "Alan", "Barbara", "Claude", "Donald", "Edsger", "Frances", "Grace", "Hedy", "John", "Katherine", "Ken", "Linus", "Margaret", "Niklaus", "Radia", "Sophie", "Tim"}
+	lastNames  = []string{"Allen", "Berners-Lee", "Dijkstra", "Hamilton", "Hopper", "Johnson", "Kay", "Knuth", "Lamarr", "Liskov", "Lovelace", "McCarthy", "Perlman", "Ritchie", "Shannon", "Thompson", "Turing", "Wirth", "Wilson"}
+	channels   = []string{"web", "web", "web", "mobile", "mobile", "pos"}
+)
+
+// Generator generates realistic users, budgets and transactions for
+// development databases and performance tests. A generator with the same
+// seed and clock generates the same data.
+type Generator struct {
+	rand   *rand.Rand
+	now    time.Time
+	months int
+}
+
+// New creates a generator spreading transactions over the months before now
+func New(seed int64, now time.Time, months int) *Generator {
+	return &Generator{rand: rand.New(rand.NewSource(seed)), now: now, months: months}
+}
+
+// User returns the n-th generated user. Emails carry n so users generated
+// with different n never collide.
+func (g *Generator) User(n int) models.User {
+	first := firstNames[g.rand.Intn(len(firstNames))]
+	last := lastNames[g.rand.Intn(len(lastNames))]
+	return models.User{
+		Name:  first + " " + last,
+		Email: fmt.Sprintf("%s.%s.%d@example.com", strings.ToLower(first), strings.ToLower(strings.ReplaceAll(last, "-", "")), n),
+	}
+}
+
+// Budget returns a monthly budget for userID in currency, or nil for the
+// users that get none. Caps are multiples of 100 between 500 and 5000.
+func (g *Generator) Budget(userID uint, currency string) *models.Budget {
+	if g.rand.Float64() >= budgetRatio {
+		return nil
+	}
+	return &models.Budget{
+		UserID:       userID,
+		MonthlyCap:   decimal.NewFromInt(int64(5+g.rand.Intn(46)) * 100),
+		Currency:     currency,
+		BlockOverage: g.rand.Intn(2) == 0,
+	}
+}
+
+// Transactions returns a function generating transactions for userIDs in
+// currency with amounts at scale decimal places, each picking its user so a
+// few users make most transactions
+func (g *Generator) Transactions(userIDs []uint, currency string, scale int32) func() models.Transaction {
+	zipf := rand.NewZipf(g.rand, userActivitySkew, 1, uint64(len(userIDs)-1))
+	// Spread the most active users over the list instead of taking the first
+	order := g.rand.Perm(len(userIDs))
+
+	return func() models.Transaction {
+		createdAt := g.createdAt()
+		transaction := models.Transaction{
+			UserID:    userIDs[order[zipf.Uint64()]],
+			Amount:    g.amount(scale),
+			Currency:  currency,
+			Type:      models.TransactionTypePayment,
+			Direction: models.TransactionDirectionDebit,
+			Status:    g.status(createdAt),
+			Metadata:  models.TransactionMetadata{"channel": channels[g.rand.Intn(len(channels))], "source": "generator"},
+			Version:   1,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		}
+		if g.rand.Float64() < adjustmentRatio {
+			transaction.Type = models.TransactionTypeAdjustment
+			if g.rand.Intn(2) == 0 {
+				transaction.Amount = transaction.Amount.Neg()
+			}
+		}
+		if g.rand.Float64() < creditRatio {
+			transaction.Direction = models.TransactionDirectionCredit
+		}
+		if transaction.Status != "pending" {
+			// Settled some seconds to minutes after creation
+			transaction.UpdatedAt = createdAt.Add(time.Duration(g.rand.ExpFloat64() * float64(time.Minute)))
+			transaction.Version = 2
+		}
+		return transaction
+	}
+}
+
+// amount returns a log-normally distributed amount with scale decimal places
+func (g *Generator) amount(scale int32) decimal.Decimal {
+	amount := math.Exp(amountMu + amountSigma*g.rand.NormFloat64())
+	amount = math.Min(math.Max(amount, 1), maxAmount)
+	return money.HalfUp.Round(decimal.NewFromFloat(amount), scale)
+}
+
+// createdAt returns a time within the generator's months before now, on a
+// uniformly chosen day and mostly during business hours
+func (g *Generator) createdAt() time.Time {
+	start := g.now.AddDate(0, -g.months, 0)
+	days := int(g.now.Sub(start).Hours() / 24)
+	day := start.AddDate(0, 0, g.rand.Intn(days+1))
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, g.now.Location())
+
+	// Hours peak at 13:00 with a standard deviation of 4 hours
+	hour := math.Mod(13+4*g.rand.NormFloat64()+24, 24)
+	createdAt := day.Add(time.Duration(hour * float64(time.Hour)))
+	if createdAt.After(g.now) {
+		createdAt = g.now.Add(-time.Duration(g.rand.Int63n(int64(time.Hour))))
+	}
+	return createdAt.Truncate(time.Second)
+}
+
+// status returns a status by the generated ratios; transactions older than
+// pendingWindow are never pending
+func (g *Generator) status(createdAt time.Time) string {
+	roll := g.rand.Float64()
+	switch {
+	case roll < failedRatio:
+		return "failed"
+	case roll < failedRatio+successRatio || g.now.Sub(createdAt) > pendingWindow:
+		return "success"
+	default:
+		return "pending"
+	}
+}
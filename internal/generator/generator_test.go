@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"interview/internal/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+// generate returns n transactions of a generator seeded with seed
+func generate(seed int64, userIDs []uint, n int) []models.Transaction {
+	next := New(seed, now, 6).Transactions(userIDs, "USD", 2)
+	transactions := make([]models.Transaction, n)
+	for i := range transactions {
+		transactions[i] = next()
+	}
+	return transactions
+}
+
+func TestGenerator_SameSeedSameData(t *testing.T) {
+	userIDs := []uint{1, 2, 3}
+	assert.Equal(t, generate(7, userIDs, 50), generate(7, userIDs, 50))
+	assert.NotEqual(t, generate(7, userIDs, 50), generate(8, userIDs, 50))
+	assert.Equal(t, New(7, now, 6).User(1), New(7, now, 6).User(1))
+}
+
+func TestGenerator_Transactions(t *testing.T) {
+	userIDs := []uint{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}
+	transactions := generate(1, userIDs, 10000)
+	total := float64(len(transactions))
+
+	statuses := map[string]int{}
+	perUser := map[uint]int{}
+	for _, transaction := range transactions {
+		statuses[transaction.Status]++
+		perUser[transaction.UserID]++
+
+		assert.Contains(t, userIDs, transaction.UserID)
+		assert.False(t, transaction.CreatedAt.After(now))
+		assert.False(t, transaction.CreatedAt.Before(now.AddDate(0, -6, -1)))
+		assert.False(t, transaction.UpdatedAt.Before(transaction.CreatedAt))
+		amount := transaction.Amount.Abs()
+		assert.True(t, amount.GreaterThanOrEqual(decimal.NewFromInt(1)), amount.String())
+		assert.True(t, amount.LessThanOrEqual(decimal.NewFromInt(10000)), amount.String())
+		assert.True(t, amount.Equal(amount.Round(2)))
+		if transaction.Amount.IsNegative() {
+			assert.Equal(t, models.TransactionTypeAdjustment, transaction.Type)
+		}
+		if transaction.Status == "pending" {
+			assert.Less(t, now.Sub(transaction.CreatedAt), 48*time.Hour)
+		}
+	}
+
+	// Pending transactions are limited to the last two days of six months
+	assert.InDelta(t, 0.95*total, statuses["success"], 0.02*total)
+	assert.InDelta(t, 0.05*total, statuses["failed"], 0.01*total)
+	assert.Positive(t, statuses["pending"])
+
+	// The most active user makes far more transactions than the average
+	most := 0
+	for _, count := range perUser {
+		most = max(most, count)
+	}
+	assert.Greater(t, most, 3*len(transactions)/len(userIDs))
+}
+
+func TestGenerator_TransactionsAtScale(t *testing.T) {
+	next := New(1, now, 1).Transactions([]uint{1}, "JPY", 0)
+	for i := 0; i < 100; i++ {
+		transaction := next()
+		assert.Equal(t, uint(1), transaction.UserID)
+		assert.Equal(t, "JPY", transaction.Currency)
+		assert.True(t, transaction.Amount.Equal(transaction.Amount.Round(0)))
+	}
+}
+
+func TestGenerator_UserAndBudget(t *testing.T) {
+	g := New(1, now, 6)
+	assert.NotEqual(t, g.User(1).Email, g.User(2).Email)
+	assert.Contains(t, g.User(3).Email, ".3@example.com")
+
+	budgets := 0
+	for i := uint(1); i <= 1000; i++ {
+		if budget := g.Budget(i, "USD"); budget != nil {
+			budgets++
+			assert.Equal(t, i, budget.UserID)
+			assert.Equal(t, "USD", budget.Currency)
+			assert.True(t, budget.MonthlyCap.GreaterThanOrEqual(decimal.NewFromInt(500)))
+			assert.True(t, budget.MonthlyCap.LessThanOrEqual(decimal.NewFromInt(5000)))
+		}
+	}
+	assert.InDelta(t, 200, budgets, 50)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"interview/internal/models"
+	"interview/internal/services"
+	"interview/internal/validation"
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// BudgetHandler handles budget HTTP requests
+type BudgetHandler struct {
+	service   services.BudgetService
+	validator *validator.Validate
+}
+
+// NewBudgetHandler creates a new budget handler
+func NewBudgetHandler(service services.BudgetService) *BudgetHandler {
+	return &BudgetHandler{
+		service:   service,
+		validator: validation.New(),
+	}
+}
+
+// SetBudget handles PUT /api/budgets/:user_id
+func (h *BudgetHandler) SetBudget(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil || userID == 0 {
+		utils.BadRequestResponse(c, "Invalid user ID")
+		return
+	}
+
+	var req models.SetBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.BadRequestResponse(c, "Validation failed: "+err.Error())
+		return
+	}
+
+	budget, err := h.service.SetBudget(c.Request.Context(), uint(userID), req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAmount) {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, budget, "Budget updated successfully")
+}
+
+// GetBudget handles GET /api/budgets/:user_id
+func (h *BudgetHandler) GetBudget(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil || userID == 0 {
+		utils.BadRequestResponse(c, "Invalid user ID")
+		return
+	}
+
+	status, err := h.service.GetBudgetStatus(c.Request.Context(), uint(userID))
+	if err != nil {
+		if err.Error() == "budget not found" {
+			utils.NotFoundResponse(c, "Budget not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, status, "Budget retrieved successfully")
+}
+
+// DescribeBudget handles OPTIONS /api/budgets/:user_id
+func (h *BudgetHandler) DescribeBudget(c *gin.Context) {
+	describeResource(c, models.ResourceDescription{
+		Resource:     "budget",
+		Methods:      []string{"GET", "PUT"},
+		ContentTypes: jsonContentTypes,
+	})
+}
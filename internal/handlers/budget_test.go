@@ -0,0 +1,126 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"interview/internal/handlers"
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBudgetService is a mock implementation of BudgetService
+type MockBudgetService struct {
+	mock.Mock
+}
+
+func (m *MockBudgetService) SetBudget(ctx context.Context, userID uint, req models.SetBudgetRequest) (*models.Budget, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Budget), args.Error(1)
+}
+
+func (m *MockBudgetService) GetBudgetStatus(ctx context.Context, userID uint) (*models.BudgetStatus, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BudgetStatus), args.Error(1)
+}
+
+func (m *MockBudgetService) CheckTransaction(ctx context.Context, userID uint, currency string, amount decimal.Decimal) ([]int64, error) {
+	args := m.Called(userID, currency, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *MockBudgetService) EmitAlerts(ctx context.Context, userID uint, thresholds []int64) {
+	m.Called(userID, thresholds)
+}
+
+func setupBudgetTestRouter() (*gin.Engine, *MockBudgetService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockService := new(MockBudgetService)
+	handler := handlers.NewBudgetHandler(mockService)
+
+	api := router.Group("/api")
+	{
+		api.GET("/budgets/:user_id", handler.GetBudget)
+		api.PUT("/budgets/:user_id", handler.SetBudget)
+	}
+
+	return router, mockService
+}
+
+func TestBudgetHandler_SetBudget(t *testing.T) {
+	router, mockService := setupBudgetTestRouter()
+
+	req := models.SetBudgetRequest{MonthlyCap: decimal.NewFromInt(1000), BlockOverage: true}
+	mockService.On("SetBudget", uint(1), mock.AnythingOfType("models.SetBudgetRequest")).Return(&models.Budget{UserID: 1, MonthlyCap: req.MonthlyCap, BlockOverage: true}, nil)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("PUT", "/api/budgets/1", bytes.NewBufferString(`{"monthly_cap": 1000, "block_overage": true}`))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBudgetHandler_SetBudgetValidationFailed(t *testing.T) {
+	router, mockService := setupBudgetTestRouter()
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("PUT", "/api/budgets/1", bytes.NewBufferString(`{"monthly_cap": -5}`))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "SetBudget")
+}
+
+func TestBudgetHandler_SetBudgetTooManyDecimals(t *testing.T) {
+	router, mockService := setupBudgetTestRouter()
+
+	mockService.On("SetBudget", uint(1), mock.AnythingOfType("models.SetBudgetRequest")).Return(nil, fmt.Errorf("%w: USD amounts allow at most 2 decimal places", services.ErrInvalidAmount))
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("PUT", "/api/budgets/1", bytes.NewBufferString(`{"monthly_cap": 10.555}`))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBudgetHandler_GetBudgetNotFound(t *testing.T) {
+	router, mockService := setupBudgetTestRouter()
+
+	mockService.On("GetBudgetStatus", uint(1)).Return(nil, errors.New("budget not found"))
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("GET", "/api/budgets/1", nil)
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
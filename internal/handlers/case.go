@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"interview/internal/models"
+	"interview/internal/services"
+	"interview/internal/validation"
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// CaseHandler handles investigation case HTTP requests
+type CaseHandler struct {
+	service        services.CaseService
+	validator      *validator.Validate
+	queryValidator *validator.Validate
+}
+
+// NewCaseHandler creates a new case handler
+func NewCaseHandler(service services.CaseService) *CaseHandler {
+	return &CaseHandler{
+		service:        service,
+		validator:      validation.New(),
+		queryValidator: newQueryValidator(),
+	}
+}
+
+// CreateCase handles POST /api/cases
+func (h *CaseHandler) CreateCase(c *gin.Context) {
+	var req models.CreateCaseRequest
+	if !h.bindBody(c, &req) {
+		return
+	}
+
+	created, err := h.service.CreateCase(c.Request.Context(), req)
+	if err != nil {
+		if err.Error() == "invalid case name" {
+			utils.BadRequestResponse(c, "Invalid case name")
+			return
+		}
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.CreatedResponse(c, created, "Case created successfully")
+}
+
+// GetCases handles GET /api/cases
+func (h *CaseHandler) GetCases(c *gin.Context) {
+	var filters models.CaseFilters
+	if !bindQuery(c, h.queryValidator, &filters) {
+		return
+	}
+
+	cases, total, err := h.service.GetCasesWithCount(c.Request.Context(), filters)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	limit, offset := filters.Page()
+	pagination := models.NewPagination(total, limit, offset, len(cases))
+	utils.SetPaginationLinks(c, limit, offset, pagination.HasMore)
+	utils.PaginatedResponse(c, cases, pagination, "Cases retrieved successfully")
+}
+
+// GetCase handles GET /api/cases/:id
+func (h *CaseHandler) GetCase(c *gin.Context) {
+	id, ok := caseID(c)
+	if !ok {
+		return
+	}
+
+	found, err := h.service.GetCase(c.Request.Context(), id)
+	if err != nil {
+		caseErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, found, "Case retrieved successfully")
+}
+
+// CloseCase handles POST /api/cases/:id/close
+func (h *CaseHandler) CloseCase(c *gin.Context) {
+	id, ok := caseID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CloseCaseRequest
+	if !h.bindBody(c, &req) {
+		return
+	}
+
+	closed, err := h.service.CloseCase(c.Request.Context(), id, req)
+	if err != nil {
+		caseErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, closed, "Case closed successfully")
+}
+
+// PinTransactions handles POST /api/cases/:id/transactions
+func (h *CaseHandler) PinTransactions(c *gin.Context) {
+	id, ok := caseID(c)
+	if !ok {
+		return
+	}
+
+	var req models.PinTransactionsRequest
+	if !h.bindBody(c, &req) {
+		return
+	}
+
+	pinned, err := h.service.PinTransactions(c.Request.Context(), id, req)
+	if err != nil {
+		caseErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, pinned, "Transactions pinned successfully")
+}
+
+// UnpinTransaction handles DELETE /api/cases/:id/transactions/:transaction_id
+func (h *CaseHandler) UnpinTransaction(c *gin.Context) {
+	id, ok := caseID(c)
+	if !ok {
+		return
+	}
+	transactionID, err := strconv.ParseUint(c.Param("transaction_id"), 10, 32)
+	if err != nil || transactionID == 0 {
+		utils.BadRequestResponse(c, "Invalid transaction ID")
+		return
+	}
+
+	unpinned, err := h.service.UnpinTransaction(c.Request.Context(), id, uint(transactionID))
+	if err != nil {
+		caseErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, unpinned, "Transaction unpinned successfully")
+}
+
+// GetPinnedTransactions handles GET /api/cases/:id/transactions
+func (h *CaseHandler) GetPinnedTransactions(c *gin.Context) {
+	id, ok := caseID(c)
+	if !ok {
+		return
+	}
+
+	var filters models.CasePinFilters
+	if !bindQuery(c, h.queryValidator, &filters) {
+		return
+	}
+
+	pins, total, err := h.service.GetPinsWithCount(c.Request.Context(), id, filters)
+	if err != nil {
+		caseErrorResponse(c, err)
+		return
+	}
+
+	limit, offset := filters.Page()
+	pagination := models.NewPagination(total, limit, offset, len(pins))
+	utils.SetPaginationLinks(c, limit, offset, pagination.HasMore)
+	utils.PaginatedResponse(c, pins, pagination, "Pinned transactions retrieved successfully")
+}
+
+// bindBody binds and validates a JSON request body, writing the 400
+// response when it is invalid
+func (h *CaseHandler) bindBody(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.BadRequestResponse(c, "Validation failed: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// caseID reads the :id path parameter, writing the 400 response when it is
+// not a case ID
+func caseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		utils.BadRequestResponse(c, "Invalid case ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// caseErrorResponse writes the response for an error from the case service
+func caseErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCaseNotFound):
+		utils.NotFoundResponse(c, "Case not found")
+	case errors.Is(err, services.ErrPinNotFound), errors.Is(err, services.ErrTransactionsNotFound):
+		utils.NotFoundResponse(c, err.Error())
+	case errors.Is(err, services.ErrCaseClosed):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	default:
+		utils.InternalServerErrorResponse(c, err.Error())
+	}
+}
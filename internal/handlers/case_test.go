@@ -0,0 +1,184 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"interview/internal/handlers"
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCaseService is a mock implementation of CaseService
+type MockCaseService struct {
+	mock.Mock
+}
+
+func (m *MockCaseService) CreateCase(ctx context.Context, req models.CreateCaseRequest) (*models.Case, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Case), args.Error(1)
+}
+
+func (m *MockCaseService) GetCase(ctx context.Context, id uint) (*models.Case, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Case), args.Error(1)
+}
+
+func (m *MockCaseService) GetCasesWithCount(ctx context.Context, filters models.CaseFilters) ([]models.Case, int64, error) {
+	args := m.Called(filters)
+	return args.Get(0).([]models.Case), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCaseService) CloseCase(ctx context.Context, id uint, req models.CloseCaseRequest) (*models.Case, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Case), args.Error(1)
+}
+
+func (m *MockCaseService) PinTransactions(ctx context.Context, id uint, req models.PinTransactionsRequest) (*models.Case, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Case), args.Error(1)
+}
+
+func (m *MockCaseService) UnpinTransaction(ctx context.Context, id, transactionID uint) (*models.Case, error) {
+	args := m.Called(id, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Case), args.Error(1)
+}
+
+func (m *MockCaseService) GetPinsWithCount(ctx context.Context, id uint, filters models.CasePinFilters) ([]models.CasePin, int64, error) {
+	args := m.Called(id, filters)
+	return args.Get(0).([]models.CasePin), args.Get(1).(int64), args.Error(2)
+}
+
+func setupCaseTestRouter() (*gin.Engine, *MockCaseService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockService := new(MockCaseService)
+	handler := handlers.NewCaseHandler(mockService)
+	router.POST("/api/cases", handler.CreateCase)
+	router.GET("/api/cases", handler.GetCases)
+	router.GET("/api/cases/:id", handler.GetCase)
+	router.POST("/api/cases/:id/close", handler.CloseCase)
+	router.GET("/api/cases/:id/transactions", handler.GetPinnedTransactions)
+	router.POST("/api/cases/:id/transactions", handler.PinTransactions)
+	router.DELETE("/api/cases/:id/transactions/:transaction_id", handler.UnpinTransaction)
+	return router, mockService
+}
+
+// serveCase sends a request to the case router and decodes the response
+func serveCase(t *testing.T, router *gin.Engine, method, path, body string) (int, models.APIResponse) {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response models.APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), w.Body.String())
+	return w.Code, response
+}
+
+func TestCaseHandler_CreateCase(t *testing.T) {
+	router, mockService := setupCaseTestRouter()
+	mockService.On("CreateCase", models.CreateCaseRequest{Name: "Card testing"}).Return(&models.Case{ID: 1, Name: "Card testing", Status: models.CaseStatusOpen}, nil)
+
+	status, response := serveCase(t, router, "POST", "/api/cases", `{"name": "Card testing"}`)
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, "Case created successfully", response.Message)
+
+	status, response = serveCase(t, router, "POST", "/api/cases", `{"description": "no name"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, response.Error, "Validation failed")
+}
+
+func TestCaseHandler_GetCases(t *testing.T) {
+	router, mockService := setupCaseTestRouter()
+	mockService.On("GetCasesWithCount", models.CaseFilters{Status: "open", Limit: 1}).Return([]models.Case{{ID: 2, Name: "Refund abuse"}}, int64(2), nil)
+
+	status, response := serveCase(t, router, "GET", "/api/cases?status=open&limit=1", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "Cases retrieved successfully", response.Message)
+	require.NotNil(t, response.Pagination)
+	assert.True(t, response.Pagination.HasMore)
+
+	status, response = serveCase(t, router, "GET", "/api/cases?status=pending", "")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "Invalid status, must be open or closed", response.Error)
+}
+
+func TestCaseHandler_Errors(t *testing.T) {
+	router, mockService := setupCaseTestRouter()
+	mockService.On("GetCase", uint(42)).Return(nil, services.ErrCaseNotFound)
+	mockService.On("CloseCase", uint(1), models.CloseCaseRequest{Outcome: "dismissed"}).Return(nil, services.ErrCaseClosed)
+	mockService.On("PinTransactions", uint(1), models.PinTransactionsRequest{TransactionIDs: []uint{5}}).Return(nil, fmt.Errorf("%w: 5", services.ErrTransactionsNotFound))
+	mockService.On("UnpinTransaction", uint(1), uint(7)).Return(nil, services.ErrPinNotFound)
+
+	tests := []struct {
+		method, path, body string
+		status             int
+		message            string
+	}{
+		{"GET", "/api/cases/42", "", http.StatusNotFound, "Case not found"},
+		{"GET", "/api/cases/abc", "", http.StatusBadRequest, "Invalid case ID"},
+		{"POST", "/api/cases/1/close", `{"outcome": "dismissed"}`, http.StatusConflict, "case is closed"},
+		{"POST", "/api/cases/1/close", `{"outcome": "maybe"}`, http.StatusBadRequest, "Validation failed"},
+		{"POST", "/api/cases/1/transactions", `{"transaction_ids": [5]}`, http.StatusNotFound, "transactions not found: 5"},
+		{"POST", "/api/cases/1/transactions", `{"transaction_ids": [5, 5]}`, http.StatusBadRequest, "Validation failed"},
+		{"DELETE", "/api/cases/1/transactions/7", "", http.StatusNotFound, "transaction is not pinned to the case"},
+		{"DELETE", "/api/cases/1/transactions/x", "", http.StatusBadRequest, "Invalid transaction ID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path+" "+tt.body, func(t *testing.T) {
+			status, response := serveCase(t, router, tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.status, status)
+			assert.Contains(t, response.Error, tt.message)
+		})
+	}
+}
+
+func TestCaseHandler_GetPinnedTransactions(t *testing.T) {
+	router, mockService := setupCaseTestRouter()
+	pins := []models.CasePin{{CaseID: 1, TransactionID: 3, Note: "same card", Transaction: &models.Transaction{ID: 3, Status: "success"}}}
+	mockService.On("GetPinsWithCount", uint(1), models.CasePinFilters{}).Return(pins, int64(1), nil)
+
+	req, _ := http.NewRequest("GET", "/api/cases/1/transactions", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data []struct {
+			Note        string             `json:"note"`
+			Transaction models.Transaction `json:"transaction"`
+		} `json:"data"`
+		Message string `json:"message"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Pinned transactions retrieved successfully", response.Message)
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "same card", response.Data[0].Note)
+	assert.Equal(t, uint(3), response.Data[0].Transaction.ID)
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"interview/internal/models"
+	"interview/internal/services"
+	"interview/internal/validation"
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// maxOperatorLength is the longest operator identity a correction records
+const maxOperatorLength = 100
+
+// CorrectionHandler handles the admin transaction correction HTTP requests
+type CorrectionHandler struct {
+	service   services.CorrectionService
+	validator *validator.Validate
+	// operatorHeader is the request header the authenticating proxy in
+	// front of the admin listener sets to the operator's identity
+	operatorHeader string
+}
+
+// NewCorrectionHandler creates a new correction handler that identifies
+// operators by operatorHeader
+func NewCorrectionHandler(service services.CorrectionService, operatorHeader string) *CorrectionHandler {
+	return &CorrectionHandler{
+		service:        service,
+		validator:      validation.New(),
+		operatorHeader: operatorHeader,
+	}
+}
+
+// RequestCorrection handles POST /api/admin/transactions/:id/corrections
+func (h *CorrectionHandler) RequestCorrection(c *gin.Context) {
+	id, ok := pathID(c, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+	operator, ok := h.operator(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateCorrectionRequest
+	if !h.bindBody(c, &req) {
+		return
+	}
+
+	correction, err := h.service.RequestCorrection(c.Request.Context(), id, operator, req)
+	if err != nil {
+		correctionErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, correction, "Correction requested, pending approval by another operator")
+}
+
+// GetTransactionCorrections handles GET /api/admin/transactions/:id/corrections
+func (h *CorrectionHandler) GetTransactionCorrections(c *gin.Context) {
+	id, ok := pathID(c, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	corrections, err := h.service.GetTransactionCorrections(c.Request.Context(), id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, corrections, "Corrections retrieved successfully")
+}
+
+// GetCorrection handles GET /api/admin/corrections/:id
+func (h *CorrectionHandler) GetCorrection(c *gin.Context) {
+	id, ok := pathID(c, "id", "Invalid correction ID")
+	if !ok {
+		return
+	}
+
+	correction, err := h.service.GetCorrection(c.Request.Context(), id)
+	if err != nil {
+		correctionErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, correction, "Correction retrieved successfully")
+}
+
+// ApproveCorrection handles POST /api/admin/corrections/:id/approve
+func (h *CorrectionHandler) ApproveCorrection(c *gin.Context) {
+	id, ok := pathID(c, "id", "Invalid correction ID")
+	if !ok {
+		return
+	}
+	operator, ok := h.operator(c)
+	if !ok {
+		return
+	}
+
+	correction, err := h.service.ApproveCorrection(c.Request.Context(), id, operator)
+	if err != nil {
+		correctionErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, correction, "Correction applied successfully")
+}
+
+// bindBody binds and validates a JSON request body, writing the 400
+// response when it is invalid
+func (h *CorrectionHandler) bindBody(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.BadRequestResponse(c, "Validation failed: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// operator reads the operator's identity from the proxy's header, writing
+// the 401 response when it is missing
+func (h *CorrectionHandler) operator(c *gin.Context) (string, bool) {
+	operator := strings.TrimSpace(c.GetHeader(h.operatorHeader))
+	if operator == "" || len(operator) > maxOperatorLength {
+		utils.ErrorCodeResponse(c, http.StatusUnauthorized, "operator_required", "Missing or invalid operator identity in the "+h.operatorHeader+" header")
+		return "", false
+	}
+	return operator, true
+}
+
+// pathID reads a numeric ID path parameter, writing the 400 response with
+// message when it is not one
+func pathID(c *gin.Context, param, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(param), 10, 32)
+	if err != nil || id == 0 {
+		utils.BadRequestResponse(c, message)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// correctionErrors are the correction failures reported with an error code,
+// so operators can tell a wrong token from a stale request
+var correctionErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{services.ErrNothingToCorrect, http.StatusBadRequest, "nothing_to_correct"},
+	{services.ErrInvalidAmount, http.StatusBadRequest, "invalid_amount"},
+	{services.ErrUserNotFound, http.StatusUnprocessableEntity, "user_not_found"},
+	{services.ErrNotCorrectable, http.StatusConflict, "not_correctable"},
+	{services.ErrCorrectionNotPending, http.StatusConflict, "correction_not_pending"},
+	{services.ErrCorrectionExpired, http.StatusConflict, "correction_expired"},
+	{services.ErrVersionConflict, http.StatusConflict, "version_conflict"},
+	{services.ErrSameOperator, http.StatusForbidden, "same_operator"},
+}
+
+// correctionErrorResponse writes the response for an error from the
+// correction service
+func correctionErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCorrectionNotFound):
+		utils.NotFoundResponse(c, "Correction not found")
+		return
+	case err.Error() == "transaction not found":
+		utils.NotFoundResponse(c, "Transaction not found")
+		return
+	}
+	for _, known := range correctionErrors {
+		if errors.Is(err, known.err) {
+			utils.ErrorCodeResponse(c, known.status, known.code, err.Error())
+			return
+		}
+	}
+	utils.InternalServerErrorResponse(c, err.Error())
+}
@@ -0,0 +1,159 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"interview/internal/handlers"
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCorrectionService is a mock implementation of CorrectionService
+type MockCorrectionService struct {
+	mock.Mock
+}
+
+func (m *MockCorrectionService) RequestCorrection(ctx context.Context, transactionID uint, requestedBy string, req models.CreateCorrectionRequest) (*models.Correction, error) {
+	args := m.Called(transactionID, requestedBy, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Correction), args.Error(1)
+}
+
+func (m *MockCorrectionService) ApproveCorrection(ctx context.Context, id uint, approvedBy string) (*models.Correction, error) {
+	args := m.Called(id, approvedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Correction), args.Error(1)
+}
+
+func (m *MockCorrectionService) GetCorrection(ctx context.Context, id uint) (*models.Correction, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Correction), args.Error(1)
+}
+
+func (m *MockCorrectionService) GetTransactionCorrections(ctx context.Context, transactionID uint) ([]models.Correction, error) {
+	args := m.Called(transactionID)
+	return args.Get(0).([]models.Correction), args.Error(1)
+}
+
+// operatorHeader is the header the tests' proxy identifies operators by
+const operatorHeader = "X-Forwarded-User"
+
+func setupCorrectionTestRouter() (*gin.Engine, *MockCorrectionService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockService := new(MockCorrectionService)
+	handler := handlers.NewCorrectionHandler(mockService, operatorHeader)
+	router.POST("/api/admin/transactions/:id/corrections", handler.RequestCorrection)
+	router.GET("/api/admin/transactions/:id/corrections", handler.GetTransactionCorrections)
+	router.GET("/api/admin/corrections/:id", handler.GetCorrection)
+	router.POST("/api/admin/corrections/:id/approve", handler.ApproveCorrection)
+	return router, mockService
+}
+
+// serveAsOperator serves a request identified as operator by the proxy
+func serveAsOperator(t *testing.T, router *gin.Engine, operator, method, path, body string) (int, models.APIResponse) {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if operator != "" {
+		req.Header.Set(operatorHeader, operator)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response models.APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), w.Body.String())
+	return w.Code, response
+}
+
+func TestCorrectionHandler_RequestAndApprove(t *testing.T) {
+	router, mockService := setupCorrectionTestRouter()
+	amount := decimal.NewFromInt(10)
+	mockService.On("RequestCorrection", uint(1), "alice", mock.MatchedBy(func(req models.CreateCorrectionRequest) bool {
+		return req.UserID == nil && req.Amount.Equal(amount) && req.Reason == "Amount keyed in cents"
+	})).Return(&models.Correction{ID: 7, TransactionID: 1, Status: models.CorrectionStatusPending, RequestedBy: "alice"}, nil)
+	mockService.On("ApproveCorrection", uint(7), "bob").
+		Return(&models.Correction{ID: 7, TransactionID: 1, Status: models.CorrectionStatusApplied, RequestedBy: "alice", ApprovedBy: "bob"}, nil)
+
+	// Names in the body are ignored; the proxy's header identifies the operator
+	status, response := serveAsOperator(t, router, "alice", "POST", "/api/admin/transactions/1/corrections", `{"amount": "10", "reason": "Amount keyed in cents", "requested_by": "mallory"}`)
+	assert.Equal(t, http.StatusCreated, status)
+	data, ok := response.Data.(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "alice", data["requested_by"])
+
+	status, response = serveAsOperator(t, router, "bob", "POST", "/api/admin/corrections/7/approve", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "Correction applied successfully", response.Message)
+	data, ok = response.Data.(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, models.CorrectionStatusApplied, data["status"])
+	mockService.AssertExpectations(t)
+}
+
+func TestCorrectionHandler_GetTransactionCorrections(t *testing.T) {
+	router, mockService := setupCorrectionTestRouter()
+	mockService.On("GetTransactionCorrections", uint(1)).Return([]models.Correction{{ID: 7, TransactionID: 1}}, nil)
+
+	status, response := serveCase(t, router, "GET", "/api/admin/transactions/1/corrections", "")
+	assert.Equal(t, http.StatusOK, status)
+	corrections, ok := response.Data.([]interface{})
+	require.True(t, ok)
+	assert.Len(t, corrections, 1)
+}
+
+func TestCorrectionHandler_Errors(t *testing.T) {
+	router, mockService := setupCorrectionTestRouter()
+	mockService.On("GetCorrection", uint(42)).Return(nil, services.ErrCorrectionNotFound)
+	mockService.On("RequestCorrection", uint(2), "alice", mock.Anything).Return(nil, errors.New("transaction not found"))
+	mockService.On("RequestCorrection", uint(3), "alice", mock.Anything).Return(nil, services.ErrNothingToCorrect)
+	mockService.On("ApproveCorrection", uint(2), "alice").Return(nil, services.ErrSameOperator)
+	mockService.On("ApproveCorrection", uint(3), "bob").Return(nil, fmt.Errorf("%w: transaction changed", services.ErrVersionConflict))
+	mockService.On("ApproveCorrection", uint(4), "bob").Return(nil, services.ErrCorrectionExpired)
+
+	request := `{"user_id": 2, "reason": "Booked to the wrong user"}`
+	tests := []struct {
+		operator, method, path, body string
+		status                       int
+		message                      string
+	}{
+		{"", "GET", "/api/admin/corrections/42", "", http.StatusNotFound, "Correction not found"},
+		{"", "GET", "/api/admin/corrections/abc", "", http.StatusBadRequest, "Invalid correction ID"},
+		{"alice", "POST", "/api/admin/transactions/2/corrections", request, http.StatusNotFound, "Transaction not found"},
+		{"alice", "POST", "/api/admin/transactions/3/corrections", request, http.StatusBadRequest, "correction changes neither user_id nor amount"},
+		{"alice", "POST", "/api/admin/transactions/1/corrections", `{"user_id": 2, "reason": "typo"}`, http.StatusBadRequest, "Validation failed"},
+		{"", "POST", "/api/admin/transactions/1/corrections", request, http.StatusUnauthorized, "operator identity"},
+		{" ", "POST", "/api/admin/corrections/1/approve", "", http.StatusUnauthorized, "operator identity"},
+		{strings.Repeat("a", 101), "POST", "/api/admin/corrections/1/approve", "", http.StatusUnauthorized, "operator identity"},
+		{"alice", "POST", "/api/admin/corrections/2/approve", "", http.StatusForbidden, "correction must be approved by another operator"},
+		{"bob", "POST", "/api/admin/corrections/3/approve", "", http.StatusConflict, "transaction changed"},
+		{"bob", "POST", "/api/admin/corrections/4/approve", "", http.StatusConflict, "correction approval window has passed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.operator+" "+tt.method+" "+tt.path+" "+tt.body, func(t *testing.T) {
+			status, response := serveAsOperator(t, router, tt.operator, tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.status, status)
+			assert.Contains(t, response.Error, tt.message)
+		})
+	}
+}
@@ -1,31 +1,163 @@
 package handlers
 
 import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/reports"
 	"interview/internal/services"
 	"interview/pkg/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 )
 
 // DashboardHandler handles dashboard HTTP requests
 type DashboardHandler struct {
 	service services.DashboardService
+	// withTestData serves summaries requested with include_test=true
+	withTestData services.DashboardService
+	validator    *validator.Validate
+}
+
+// DashboardHandlerOption configures optional dashboard handler behavior
+type DashboardHandlerOption func(*DashboardHandler)
+
+// WithTestDataSummary serves include_test=true summaries, which count test
+// transactions, from service
+func WithTestDataSummary(service services.DashboardService) DashboardHandlerOption {
+	return func(h *DashboardHandler) {
+		h.withTestData = service
+	}
 }
 
 // NewDashboardHandler creates a new dashboard handler
-func NewDashboardHandler(service services.DashboardService) *DashboardHandler {
-	return &DashboardHandler{
-		service: service,
+func NewDashboardHandler(service services.DashboardService, opts ...DashboardHandlerOption) *DashboardHandler {
+	h := &DashboardHandler{
+		service:   service,
+		validator: newQueryValidator(),
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
+	return h
 }
 
 // GetSummary handles GET /api/dashboard/summary
 func (h *DashboardHandler) GetSummary(c *gin.Context) {
-	summary, err := h.service.GetSummary()
+	summary, ok := h.summary(c)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, summary, "Dashboard summary retrieved successfully")
+}
+
+// ExportSummary handles GET /api/dashboard/export. It takes the summary
+// parameters and sends the summary as an XLSX workbook.
+func (h *DashboardHandler) ExportSummary(c *gin.Context) {
+	summary, ok := h.summary(c)
+	if !ok {
+		return
+	}
+
+	// The workbook is built before anything is sent, so failures still get a
+	// JSON response
+	generatedAt := time.Now()
+	var buf bytes.Buffer
+	if err := reports.WriteDashboard(&buf, summary, generatedAt); err != nil {
+		utils.InternalServerErrorResponse(c, "failed to generate dashboard report: "+err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, reports.DashboardFilename(generatedAt)))
+	c.Data(http.StatusOK, reports.ContentType, buf.Bytes())
+}
+
+// GetTopUsers handles GET /api/dashboard/top-users
+func (h *DashboardHandler) GetTopUsers(c *gin.Context) {
+	var req models.TopUsersRequest
+	if !bindQuery(c, h.validator, &req) {
+		return
+	}
+	period, err := req.Range(time.Now())
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid date range, "+err.Error())
+		return
+	}
+
+	service, ok := h.serviceFor(c, req.IncludesTest())
+	if !ok {
+		return
+	}
+
+	top, err := service.GetTopUsers(c.Request.Context(), period, req.Order(), req.Top())
 	if err != nil {
 		utils.InternalServerErrorResponse(c, err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, summary, "Dashboard summary retrieved successfully")
+	utils.SuccessResponse(c, top, "Top users retrieved successfully")
+}
+
+// summary binds the summary request and gets the summary it asks for,
+// writing the error response when that fails
+func (h *DashboardHandler) summary(c *gin.Context) (*models.DashboardSummary, bool) {
+	var req models.DashboardSummaryRequest
+	if !bindQuery(c, h.validator, &req) {
+		return nil, false
+	}
+	period, err := req.Range(time.Now())
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid date range, "+err.Error())
+		return nil, false
+	}
+
+	service, ok := h.serviceFor(c, req.IncludesTest())
+	if !ok {
+		return nil, false
+	}
+
+	// mode=lenient returns the sections that succeeded with per-section errors
+	var summary *models.DashboardSummary
+	switch {
+	case period != nil:
+		summary, err = service.GetPeriodSummary(c.Request.Context(), *period, req.Lenient())
+	case req.Lenient():
+		summary, err = service.GetPartialSummary(c.Request.Context())
+	default:
+		summary, err = service.GetSummary(c.Request.Context())
+	}
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return nil, false
+	}
+	return summary, true
+}
+
+// serviceFor returns the service that answers a request, which counts test
+// transactions only when includeTest is set, writing the error response when
+// that is not enabled
+func (h *DashboardHandler) serviceFor(c *gin.Context, includeTest bool) (services.DashboardService, bool) {
+	if !includeTest {
+		return h.service, true
+	}
+	if h.withTestData == nil {
+		utils.ErrorResponse(c, http.StatusNotImplemented, "Summaries including test data are not enabled")
+		return nil, false
+	}
+	return h.withTestData, true
+}
+
+// DescribeSummary handles OPTIONS /api/dashboard/summary
+func (h *DashboardHandler) DescribeSummary(c *gin.Context) {
+	describeResource(c, models.ResourceDescription{
+		Resource:     "dashboard_summary",
+		Methods:      []string{"GET"},
+		ContentTypes: jsonContentTypes,
+		Filters:      []string{"mode", "include_test", "period", "from", "to"},
+	})
 }
@@ -1,19 +1,24 @@
 package handlers_test
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/shopspring/decimal"
 
 	"interview/internal/handlers"
 	"interview/internal/models"
+	"interview/internal/reports"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
 )
 
 // MockDashboardService is a mock implementation of DashboardService
@@ -21,7 +26,7 @@ type MockDashboardService struct {
 	mock.Mock
 }
 
-func (m *MockDashboardService) GetSummary() (*models.DashboardSummary, error) {
+func (m *MockDashboardService) GetSummary(ctx context.Context) (*models.DashboardSummary, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -29,6 +34,30 @@ func (m *MockDashboardService) GetSummary() (*models.DashboardSummary, error) {
 	return args.Get(0).(*models.DashboardSummary), args.Error(1)
 }
 
+func (m *MockDashboardService) GetPartialSummary(ctx context.Context) (*models.DashboardSummary, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DashboardSummary), args.Error(1)
+}
+
+func (m *MockDashboardService) GetPeriodSummary(ctx context.Context, period models.SummaryPeriod, partial bool) (*models.DashboardSummary, error) {
+	args := m.Called(period, partial)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DashboardSummary), args.Error(1)
+}
+
+func (m *MockDashboardService) GetTopUsers(ctx context.Context, period models.SummaryPeriod, sortBy string, limit int) (*models.TopUsers, error) {
+	args := m.Called(period, sortBy, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TopUsers), args.Error(1)
+}
+
 func setupDashboardTestRouter() (*gin.Engine, *MockDashboardService) {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
@@ -38,11 +67,39 @@ func setupDashboardTestRouter() (*gin.Engine, *MockDashboardService) {
 	api := router.Group("/api")
 	{
 		api.GET("/dashboard/summary", handler.GetSummary)
+		api.GET("/dashboard/export", handler.ExportSummary)
+		api.GET("/dashboard/top-users", handler.GetTopUsers)
 	}
 
 	return router, mockService
 }
 
+func TestDashboardHandler_GetSummaryIncludeTest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	liveService, testDataService := new(MockDashboardService), new(MockDashboardService)
+	testDataService.On("GetSummary").Return(&models.DashboardSummary{TodaySuccessfulTransactions: 12}, nil)
+
+	router := gin.New()
+	router.GET("/api/dashboard/summary", handlers.NewDashboardHandler(liveService, handlers.WithTestDataSummary(testDataService)).GetSummary)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/dashboard/summary?include_test=true", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"today_successful_transactions":12`)
+	testDataService.AssertExpectations(t)
+	liveService.AssertNotCalled(t, "GetSummary")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/dashboard/summary?include_test=maybe", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Handlers without a test data service cannot include it
+	router, _ = setupDashboardTestRouter()
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/dashboard/summary?include_test=true", nil))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
+
 func TestDashboardHandler_GetSummary(t *testing.T) {
 	router, mockService := setupDashboardTestRouter()
 
@@ -85,3 +142,199 @@ func TestDashboardHandler_GetSummaryServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	mockService.AssertExpectations(t)
 }
+
+func TestDashboardHandler_GetSummaryLenient(t *testing.T) {
+	router, mockService := setupDashboardTestRouter()
+
+	partialSummary := &models.DashboardSummary{
+		TodaySuccessfulTransactions: 10,
+		TodaySuccessfulAmount:       decimal.NewFromFloat(1500.50),
+		Errors: map[string]string{
+			"average_transaction_per_user": "failed to get average transactions per user: database error",
+		},
+	}
+
+	mockService.On("GetPartialSummary").Return(partialSummary, nil)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("GET", "/api/dashboard/summary?mode=lenient", nil)
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "average_transaction_per_user")
+	mockService.AssertNotCalled(t, "GetSummary")
+	mockService.AssertExpectations(t)
+}
+
+func TestDashboardHandler_GetSummaryInvalidMode(t *testing.T) {
+	router, mockService := setupDashboardTestRouter()
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("GET", "/api/dashboard/summary?mode=sloppy", nil)
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"success":false,"error":"Invalid mode, must be strict or lenient","fields":{"mode":"must be strict or lenient"}}`, w.Body.String())
+	mockService.AssertNotCalled(t, "GetSummary")
+	mockService.AssertNotCalled(t, "GetPartialSummary")
+}
+
+func TestDashboardHandler_GetSummaryReportsEveryInvalidParameter(t *testing.T) {
+	router, mockService := setupDashboardTestRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/dashboard/summary?mode=sloppy&include_test=maybe", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{
+		"success": false,
+		"error": "Invalid include_test, must be true or false; Invalid mode, must be strict or lenient",
+		"fields": {
+			"mode": "must be strict or lenient",
+			"include_test": "must be true or false"
+		}
+	}`, w.Body.String())
+	mockService.AssertNotCalled(t, "GetSummary")
+}
+
+func TestDashboardHandler_ExportSummary(t *testing.T) {
+	router, mockService := setupDashboardTestRouter()
+
+	mockService.On("GetPartialSummary").Return(&models.DashboardSummary{
+		TodaySuccessfulTransactions: 10,
+		StatusCounts:                models.StatusCounts{Success: 5, Pending: 3, Failed: 2},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/dashboard/export?mode=lenient", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, reports.ContentType, w.Header().Get("Content-Type"))
+	assert.Regexp(t, `^attachment; filename="dashboard-\d{4}-\d{2}-\d{2}\.xlsx"$`, w.Header().Get("Content-Disposition"))
+
+	f, err := excelize.OpenReader(w.Body)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, []string{reports.SummarySheet, reports.StatusCountsSheet, reports.CurrencyTotalsSheet, reports.LatestTransactionsSheet}, f.GetSheetList())
+	pending, err := f.GetCellValue(reports.StatusCountsSheet, "B3")
+	require.NoError(t, err)
+	assert.Equal(t, "3", pending)
+	mockService.AssertExpectations(t)
+}
+
+func TestDashboardHandler_ExportSummaryErrors(t *testing.T) {
+	router, mockService := setupDashboardTestRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/dashboard/export?mode=sloppy", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+	mockService.On("GetSummary").Return((*models.DashboardSummary)(nil), errors.New("service error"))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/dashboard/export", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+}
+
+func TestDashboardHandler_GetSummaryPeriod(t *testing.T) {
+	router, mockService := setupDashboardTestRouter()
+
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 30, 23, 59, 59, 0, time.UTC)
+	period := models.SummaryPeriod{From: from, To: to}
+	mockService.On("GetPeriodSummary", period, true).Return(&models.DashboardSummary{TodaySuccessfulTransactions: 42, Period: &period}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/dashboard/summary?from=2024-06-01T00:00:00Z&to=2024-06-30T23:59:59Z&mode=lenient", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"today_successful_transactions":42`)
+	assert.Contains(t, w.Body.String(), `"period":{"from":"2024-06-01T00:00:00Z","to":"2024-06-30T23:59:59Z"}`)
+	mockService.AssertExpectations(t)
+
+	// Shortcuts end now, so only their length is known up front
+	mockService.On("GetPeriodSummary", mock.MatchedBy(func(p models.SummaryPeriod) bool {
+		return p.To.Sub(p.From) > 6*24*time.Hour && p.To.Sub(p.From) <= 7*24*time.Hour
+	}), false).Return(&models.DashboardSummary{}, nil).Once()
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/dashboard/summary?period=7d", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "GetSummary")
+}
+
+func TestDashboardHandler_GetSummaryInvalidPeriod(t *testing.T) {
+	router, mockService := setupDashboardTestRouter()
+
+	for query, fields := range map[string]string{
+		"period=1y":                           `{"period":"must be today, 7d or 30d"}`,
+		"period=7d&from=2024-06-01T00:00:00Z": `{"period":"must not be combined with from or to"}`,
+		"from=yesterday":                      `{"from":"must be an RFC 3339 timestamp"}`,
+		"to=2024-06-30T23:59:59Z":             `{"from":"is required with to"}`,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/dashboard/summary?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Contains(t, w.Body.String(), `"fields":`+fields, query)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/dashboard/summary?from=2024-06-02T00:00:00Z&to=2024-06-01T00:00:00Z", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid date range, from must not be after to")
+	mockService.AssertNotCalled(t, "GetPeriodSummary", mock.Anything, mock.Anything)
+}
+
+func TestDashboardHandler_GetTopUsers(t *testing.T) {
+	router, mockService := setupDashboardTestRouter()
+
+	period := models.SummaryPeriod{From: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 6, 30, 23, 59, 59, 0, time.UTC)}
+	mockService.On("GetTopUsers", period, models.TopUsersByCount, models.MaxTopUsers).Return(&models.TopUsers{
+		Period: period,
+		SortBy: models.TopUsersByCount,
+		Users:  []models.UserVolume{{UserID: 1, Name: "Alice", Count: 3, Amount: decimal.NewFromInt(300)}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/dashboard/top-users?from=2024-06-01T00:00:00Z&to=2024-06-30T23:59:59Z&sort_by=count&limit=500", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"users":[{"user_id":1,"name":"Alice","count":3,"amount":"300"}]`)
+	mockService.AssertExpectations(t)
+
+	// Without parameters the top users by amount of today are returned
+	mockService.On("GetTopUsers", mock.MatchedBy(func(p models.SummaryPeriod) bool {
+		return p.To.Sub(p.From) <= 24*time.Hour
+	}), models.TopUsersByAmount, models.DefaultTopUsers).Return(&models.TopUsers{}, nil).Once()
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/dashboard/top-users", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDashboardHandler_GetTopUsersErrors(t *testing.T) {
+	router, mockService := setupDashboardTestRouter()
+
+	for query, status := range map[string]int{
+		"sort_by=name":      http.StatusBadRequest,
+		"period=1y":         http.StatusBadRequest,
+		"limit=many":        http.StatusBadRequest,
+		"include_test=true": http.StatusNotImplemented,
+		"from=2024-06-02T00:00:00Z&to=2024-06-01T00:00:00Z": http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/dashboard/top-users?"+query, nil))
+		assert.Equal(t, status, w.Code, query)
+	}
+	mockService.AssertNotCalled(t, "GetTopUsers", mock.Anything, mock.Anything, mock.Anything)
+
+	mockService.On("GetTopUsers", mock.Anything, models.TopUsersByAmount, models.DefaultTopUsers).Return(nil, errors.New("service error"))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/dashboard/top-users", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"interview/internal/models"
+	"interview/internal/services"
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FixtureHandler handles fixture HTTP requests
+type FixtureHandler struct {
+	service services.FixtureService
+}
+
+// NewFixtureHandler creates a new fixture handler
+func NewFixtureHandler(service services.FixtureService) *FixtureHandler {
+	return &FixtureHandler{
+		service: service,
+	}
+}
+
+// ExportFixture handles GET /api/admin/fixtures
+func (h *FixtureHandler) ExportFixture(c *gin.Context) {
+	var filters models.TransactionFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		utils.BadRequestResponse(c, "Invalid query parameters")
+		return
+	}
+
+	if !excludeTestDataByDefault(c, &filters) {
+		return
+	}
+
+	fixture, err := h.service.Export(c.Request.Context(), filters)
+	if err != nil {
+		if err.Error() == "invalid status filter" {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	// The fixture is served bare (not in the API envelope) so the download
+	// can be fed straight to trxgo seed
+	filename := fmt.Sprintf("fixture-%s.json", fixture.ExportedAt.Format("20060102-150405"))
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.JSON(http.StatusOK, fixture)
+}
+
+// DescribeFixtures handles OPTIONS /api/admin/fixtures
+func (h *FixtureHandler) DescribeFixtures(c *gin.Context) {
+	describeResource(c, models.ResourceDescription{
+		Resource:     "fixtures",
+		Methods:      []string{"GET"},
+		ContentTypes: jsonContentTypes,
+		Filters:      []string{"user_id", "status", "is_test", "include_test"},
+		Limits: map[string]int{
+			"max_transactions": services.MaxFixtureTransactions,
+		},
+	})
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthCheck checks one dependency the server needs to serve requests
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// HealthHandler handles readiness HTTP requests
+type HealthHandler struct {
+	timeout time.Duration
+	checks  []HealthCheck
+}
+
+// NewHealthHandler creates a new health handler running checks, each
+// cancelled after timeout
+func NewHealthHandler(timeout time.Duration, checks ...HealthCheck) *HealthHandler {
+	return &HealthHandler{
+		timeout: timeout,
+		checks:  checks,
+	}
+}
+
+// Ready handles GET /health/ready. It runs every check concurrently and
+// answers 200 when all pass and 503 when any fails, with the status of each.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	errs := make([]error, len(h.checks))
+	var wg sync.WaitGroup
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(c.Request.Context(), h.timeout)
+			defer cancel()
+			errs[i] = check.Check(ctx)
+		}()
+	}
+	wg.Wait()
+
+	status, code := "OK", http.StatusOK
+	checks := make(gin.H, len(h.checks))
+	for i, check := range h.checks {
+		if errs[i] != nil {
+			status, code = "unavailable", http.StatusServiceUnavailable
+			checks[check.Name] = gin.H{"status": "down", "error": errs[i].Error()}
+			continue
+		}
+		checks[check.Name] = gin.H{"status": "up"}
+	}
+
+	c.JSON(code, gin.H{"status": status, "checks": checks})
+}
@@ -0,0 +1,80 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"interview/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveReady(t *testing.T, handler *handlers.HealthHandler) (int, map[string]interface{}) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/health/ready", handler.Ready)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/health/ready", nil))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return w.Code, body
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	handler := handlers.NewHealthHandler(time.Second,
+		handlers.HealthCheck{Name: "database", Check: up},
+		handlers.HealthCheck{Name: "migrations", Check: up},
+	)
+
+	code, body := serveReady(t, handler)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "OK", body["status"])
+	assert.Equal(t, map[string]interface{}{
+		"database":   map[string]interface{}{"status": "up"},
+		"migrations": map[string]interface{}{"status": "up"},
+	}, body["checks"])
+}
+
+func TestHealthHandler_ReadyReportsFailedChecks(t *testing.T) {
+	handler := handlers.NewHealthHandler(time.Second,
+		handlers.HealthCheck{Name: "database", Check: func(ctx context.Context) error { return nil }},
+		handlers.HealthCheck{Name: "migrations", Check: func(ctx context.Context) error { return errors.New("table budgets is missing") }},
+	)
+
+	code, body := serveReady(t, handler)
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unavailable", body["status"])
+	assert.Equal(t, map[string]interface{}{
+		"database":   map[string]interface{}{"status": "up"},
+		"migrations": map[string]interface{}{"status": "down", "error": "table budgets is missing"},
+	}, body["checks"])
+}
+
+func TestHealthHandler_ReadyTimesOutChecks(t *testing.T) {
+	handler := handlers.NewHealthHandler(10*time.Millisecond, handlers.HealthCheck{
+		Name: "database",
+		Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	code, body := serveReady(t, handler)
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, map[string]interface{}{
+		"database": map[string]interface{}{"status": "down", "error": context.DeadlineExceeded.Error()},
+	}, body["checks"])
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/validation"
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+// MetaHandler handles requests describing the API itself
+type MetaHandler struct {
+	examples []models.EndpointExample
+}
+
+// NewMetaHandler creates a new meta handler, describing each example
+// request's fields from its request model
+func NewMetaHandler() *MetaHandler {
+	examples := endpointExamples()
+	for i := range examples {
+		request := examples[i].Request
+		if examples[i].Query != "" {
+			request, examples[i].Request = examples[i].Request, nil
+		}
+		examples[i].Fields = validation.Describe(request)
+	}
+	return &MetaHandler{examples: examples}
+}
+
+// GetExamples handles GET /api/meta/examples
+func (h *MetaHandler) GetExamples(c *gin.Context) {
+	utils.SuccessResponse(c, h.examples, "Examples retrieved successfully")
+}
+
+// endpointExamples returns an example of each endpoint that takes a request
+// body or validated query parameters. Request holds the request model,
+// filled in as the example; for query parameters it only names the model
+// and Query holds the example. Paths are relative to the base URL.
+func endpointExamples() []models.EndpointExample {
+	createdAt := time.Date(2025, 6, 28, 10, 0, 0, 0, time.UTC)
+	uuid := "9b2f6c1e-3d4a-4f5b-8c6d-7e8f9a0b1c2d"
+	reference := "ORD-2025-0042"
+	description := "Office supplies"
+
+	create := models.CreateTransactionRequest{
+		UserID:      1,
+		Amount:      decimal.RequireFromString("100.50"),
+		Currency:    "USD",
+		Type:        models.TransactionTypePayment,
+		Direction:   models.TransactionDirectionDebit,
+		Metadata:    models.TransactionMetadata{"invoice": "INV-1042"},
+		ReferenceID: reference,
+	}
+	transaction := models.Transaction{
+		ID:          1,
+		UUID:        &uuid,
+		ReferenceID: &reference,
+		UserID:      create.UserID,
+		Amount:      create.Amount,
+		Currency:    create.Currency,
+		Type:        create.Type,
+		Direction:   create.Direction,
+		Status:      "pending",
+		Metadata:    create.Metadata,
+		Version:     1,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+	described := transaction
+	described.Description = description
+	described.Version = 2
+	tagged := transaction
+	tagged.Tags = []models.Tag{{Name: "refund"}, {Name: "vip"}}
+	reconcile := models.ReconciliationRequest{Items: []models.ReconciliationItem{
+		{Reference: reference, Amount: create.Amount, Status: "success"},
+		{Reference: "ORD-2025-0043", Amount: decimal.RequireFromString("25.00"), Status: "success"},
+	}}
+	reconciled := exampleResponse(models.ReconciliationResult{
+		Compared:   2,
+		Mismatched: 2,
+		Mismatches: []models.ReconciliationMismatch{
+			{Index: 0, Reference: reference, Kinds: []string{models.MismatchStatus}, Provider: reconcile.Items[0], Transaction: &transaction},
+			{Index: 1, Reference: "ORD-2025-0043", Kinds: []string{models.MismatchMissing}, Provider: reconcile.Items[1]},
+		},
+	}, "Reconciliation completed successfully")
+	reconciled.Pagination = models.NewPagination(2, models.DefaultPageSize, 0, 2)
+	budget := models.SetBudgetRequest{MonthlyCap: decimal.RequireFromString("1000.00"), Currency: "USD", BlockOverage: true}
+	user := models.CreateUserRequest{Name: "Grace Hopper", Email: "grace@example.com"}
+	openCase := models.CreateCaseRequest{Name: "Card testing on merchant 42", Description: "Bursts of small payments from new cards"}
+	investigation := models.Case{
+		ID:          1,
+		Name:        openCase.Name,
+		Description: openCase.Description,
+		Status:      models.CaseStatusOpen,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+	pinned := investigation
+	pinned.TransactionCount = 1
+	closed := pinned
+	closeCase := models.CloseCaseRequest{Outcome: models.CaseOutcomeConfirmed, Resolution: "Cards reported to the issuer"}
+	closed.Status = models.CaseStatusClosed
+	closed.Outcome = closeCase.Outcome
+	closed.Resolution = closeCase.Resolution
+	closed.ClosedAt = &createdAt
+
+	return []models.EndpointExample{
+		{
+			Method:   http.MethodPost,
+			Path:     "/transactions",
+			Request:  create,
+			Status:   http.StatusCreated,
+			Response: exampleResponse(transaction, "Transaction created successfully"),
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/transactions/bulk",
+			Request: []models.CreateTransactionRequest{create},
+			Status:  http.StatusCreated,
+			Response: exampleResponse(models.BulkCreateResult{
+				Created: 1,
+				Results: []models.BulkCreateItemResult{{Index: 0, Status: models.BulkItemCreated, Transaction: &transaction}},
+			}, "1 of 1 transactions created"),
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/transactions/lookup",
+			Request: models.TransactionLookupRequest{IDs: []uint{1, 2}},
+			Status:  http.StatusOK,
+			Response: exampleResponse(models.TransactionLookupResult{
+				Transactions: []models.Transaction{transaction},
+				Missing:      []uint{2},
+			}, "Transactions retrieved successfully"),
+		},
+		{
+			Method:   http.MethodPut,
+			Path:     "/transactions/{id}",
+			Request:  models.UpdateTransactionRequest{Status: "success"},
+			Status:   http.StatusOK,
+			Response: exampleResponse(nil, "Transaction updated successfully"),
+		},
+		{
+			Method:   http.MethodPatch,
+			Path:     "/transactions/{id}",
+			Request:  models.PatchTransactionRequest{Description: &description},
+			Status:   http.StatusOK,
+			Response: exampleResponse(described, "Transaction updated successfully"),
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/transactions/{id}/tags",
+			Request:  models.AddTagsRequest{Tags: []string{"refund", "vip"}},
+			Status:   http.StatusOK,
+			Response: exampleResponse(tagged, "Tags added successfully"),
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/dashboard/summary",
+			Request: models.DashboardSummaryRequest{},
+			Query:   "mode=lenient&include_test=false",
+			Status:  http.StatusOK,
+			Response: exampleResponse(models.DashboardSummary{
+				AverageTransactionPerUser: decimal.NewFromInt(1),
+				LatestTransactions:        []models.Transaction{transaction},
+				StatusCounts:              models.StatusCounts{Pending: 1},
+			}, "Dashboard summary retrieved successfully"),
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/dashboard/top-users",
+			Request: models.TopUsersRequest{},
+			Query:   "limit=5&sort_by=count&period=7d",
+			Status:  http.StatusOK,
+			Response: exampleResponse(models.TopUsers{
+				Period: models.SummaryPeriod{From: createdAt.AddDate(0, 0, -6), To: createdAt},
+				SortBy: models.TopUsersByCount,
+				Users:  []models.UserVolume{{UserID: 1, Name: "John Doe", Count: 1, Amount: decimal.NewFromInt(100)}},
+			}, "Top users retrieved successfully"),
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/budgets/{user_id}",
+			Request: budget,
+			Status:  http.StatusOK,
+			Response: exampleResponse(models.Budget{
+				UserID:       1,
+				MonthlyCap:   budget.MonthlyCap,
+				Currency:     budget.Currency,
+				BlockOverage: budget.BlockOverage,
+				CreatedAt:    createdAt,
+				UpdatedAt:    createdAt,
+			}, "Budget updated successfully"),
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/reconciliation/compare",
+			Request:  reconcile,
+			Status:   http.StatusOK,
+			Response: reconciled,
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/cases",
+			Request:  openCase,
+			Status:   http.StatusCreated,
+			Response: exampleResponse(investigation, "Case created successfully"),
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/cases/{id}/transactions",
+			Request:  models.PinTransactionsRequest{TransactionIDs: []uint{1}, Note: "Same card as the chargeback"},
+			Status:   http.StatusOK,
+			Response: exampleResponse(pinned, "Transactions pinned successfully"),
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/cases/{id}/close",
+			Request:  closeCase,
+			Status:   http.StatusOK,
+			Response: exampleResponse(closed, "Case closed successfully"),
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/users",
+			Request: user,
+			Status:  http.StatusCreated,
+			Response: exampleResponse(models.User{
+				ID:        1,
+				Name:      user.Name,
+				Email:     user.Email,
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+			}, "User created successfully"),
+		},
+	}
+}
+
+// exampleResponse returns the successful response envelope holding data
+func exampleResponse(data interface{}, message string) models.APIResponse {
+	return models.APIResponse{Success: true, Data: data, Message: message}
+}
@@ -0,0 +1,51 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"interview/internal/handlers"
+	"interview/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetaHandler_GetExamples(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/meta/examples", handlers.NewMetaHandler().GetExamples)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/meta/examples", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data []models.EndpointExample `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	examples := make(map[string]models.EndpointExample)
+	for _, example := range response.Data {
+		examples[example.Method+" "+example.Path] = example
+	}
+
+	create := examples["POST /transactions"]
+	assert.Equal(t, http.StatusCreated, create.Status)
+	assert.Contains(t, create.Fields, models.FieldSchema{Name: "amount", In: "body", Type: "decimal", Required: true, Rules: []string{"required", "decimal_nonzero"}})
+	assert.NotNil(t, create.Request)
+	assert.Equal(t, "Transaction created successfully", create.Response.Message)
+
+	budget := examples["PUT /budgets/{user_id}"]
+	assert.Contains(t, budget.Fields, models.FieldSchema{Name: "monthly_cap", In: "body", Type: "decimal", Required: true, Rules: []string{"required", "decimal_positive"}})
+	assert.Contains(t, budget.Fields, models.FieldSchema{Name: "currency", In: "body", Type: "string", Rules: []string{"len=3", "alpha"}})
+
+	summary := examples["GET /dashboard/summary"]
+	assert.Nil(t, summary.Request)
+	assert.NotEmpty(t, summary.Query)
+	assert.Contains(t, summary.Fields, models.FieldSchema{Name: "mode", In: "query", Type: "string", Rules: []string{"oneof=strict lenient"}})
+}
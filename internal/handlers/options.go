@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"strings"
+
+	"interview/internal/models"
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// jsonContentTypes lists the content types accepted and produced by the API
+var jsonContentTypes = []string{"application/json"}
+
+// describeResource responds to an OPTIONS request with the resource's capabilities
+func describeResource(c *gin.Context, description models.ResourceDescription) {
+	description.Methods = append(description.Methods, "OPTIONS")
+	c.Header("Allow", strings.Join(description.Methods, ", "))
+	utils.SuccessResponse(c, description, "Resource description retrieved successfully")
+}
@@ -0,0 +1,52 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"interview/internal/handlers"
+	"interview/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionHandler_DescribeTransactions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := handlers.NewTransactionHandler(new(MockTransactionService))
+	router.OPTIONS("/api/transactions", handler.DescribeTransactions)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("OPTIONS", "/api/transactions", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "GET, POST, OPTIONS", w.Header().Get("Allow"))
+
+	var response struct {
+		Data models.ResourceDescription `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "transactions", response.Data.Resource)
+	assert.Equal(t, []string{"application/json", "application/x-ndjson"}, response.Data.ContentTypes)
+	assert.Contains(t, response.Data.Filters, "status")
+	assert.Equal(t, models.MaxPageSize, response.Data.Limits["max_page_size"])
+}
+
+func TestTransactionHandler_DescribeTransaction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := handlers.NewTransactionHandler(new(MockTransactionService))
+	router.OPTIONS("/api/transactions/:id", handler.DescribeTransaction)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("OPTIONS", "/api/transactions/1", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "GET, PUT, PATCH, DELETE, OPTIONS", w.Header().Get("Allow"))
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"interview/internal/validation"
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// newQueryValidator creates a validator for query parameter models. Errors
+// name fields by their form tag, which is the parameter clients send.
+func newQueryValidator() *validator.Validate {
+	validator := validation.New()
+	validator.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return validator
+}
+
+// bindQuery binds the query parameters into req and validates them, writing
+// a 400 response naming each invalid parameter when they do not pass
+func bindQuery(c *gin.Context, v *validator.Validate, req interface{}) bool {
+	if err := c.ShouldBindQuery(req); err != nil {
+		utils.BadRequestResponse(c, "Invalid query parameters")
+		return false
+	}
+
+	err := v.Struct(req)
+	if err == nil {
+		return true
+	}
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		utils.BadRequestResponse(c, "Validation failed: "+err.Error())
+		return false
+	}
+
+	fields := make(map[string]string, len(invalid))
+	for _, fieldErr := range invalid {
+		fields[fieldErr.Field()] = fieldMessage(fieldErr)
+	}
+	utils.ValidationErrorResponse(c, fields)
+	return false
+}
+
+// fieldMessage describes a failed validation rule to clients
+func fieldMessage(err validator.FieldError) string {
+	switch err.Tag() {
+	case "oneof":
+		options := strings.Fields(err.Param())
+		if len(options) == 1 {
+			return "must be " + options[0]
+		}
+		return "must be " + strings.Join(options[:len(options)-1], ", ") + " or " + options[len(options)-1]
+	case "boolean":
+		return "must be true or false"
+	case "required":
+		return "is required"
+	case "required_with":
+		return "is required with " + paramNames(err.Param(), " and ")
+	case "excluded_with":
+		return "must not be combined with " + paramNames(err.Param(), " or ")
+	case "datetime":
+		return "must be an RFC 3339 timestamp"
+	case "min":
+		return "must be at least " + err.Param()
+	case "max":
+		return "must be at most " + err.Param()
+	default:
+		return "failed the " + err.Tag() + " rule"
+	}
+}
+
+// paramNames joins the fields a rule names, which are single word struct
+// fields named like their query parameter
+func paramNames(fields, sep string) string {
+	return strings.ToLower(strings.Join(strings.Fields(fields), sep))
+}
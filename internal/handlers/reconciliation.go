@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"interview/internal/models"
+	"interview/internal/services"
+	"interview/internal/validation"
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ReconciliationHandler handles reconciliation HTTP requests
+type ReconciliationHandler struct {
+	service   services.ReconciliationService
+	validator *validator.Validate
+}
+
+// NewReconciliationHandler creates a new reconciliation handler
+func NewReconciliationHandler(service services.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		service:   service,
+		validator: validation.New(),
+	}
+}
+
+// Compare handles POST /api/reconciliation/compare
+func (h *ReconciliationHandler) Compare(c *gin.Context) {
+	var filters models.ReconciliationFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		utils.BadRequestResponse(c, "Invalid query parameters")
+		return
+	}
+
+	var req models.ReconciliationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.BadRequestResponse(c, "Validation failed: "+err.Error())
+		return
+	}
+
+	result, err := h.service.Compare(c.Request.Context(), req, filters)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	limit, offset := filters.Page()
+	pagination := models.NewPagination(int64(result.Mismatched), limit, offset, len(result.Mismatches))
+	utils.PaginatedResponse(c, result, pagination, "Reconciliation completed successfully")
+}
@@ -0,0 +1,90 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"interview/internal/handlers"
+	"interview/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockReconciliationService is a mock implementation of ReconciliationService
+type MockReconciliationService struct {
+	mock.Mock
+}
+
+func (m *MockReconciliationService) Compare(ctx context.Context, req models.ReconciliationRequest, filters models.ReconciliationFilters) (*models.ReconciliationResult, error) {
+	args := m.Called(req, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReconciliationResult), args.Error(1)
+}
+
+func setupReconciliationTestRouter() (*gin.Engine, *MockReconciliationService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockService := new(MockReconciliationService)
+	handler := handlers.NewReconciliationHandler(mockService)
+	router.POST("/api/reconciliation/compare", handler.Compare)
+	return router, mockService
+}
+
+func TestReconciliationHandler_Compare(t *testing.T) {
+	router, mockService := setupReconciliationTestRouter()
+
+	mockService.On("Compare", mock.AnythingOfType("models.ReconciliationRequest"), models.ReconciliationFilters{Limit: 1}).Return(&models.ReconciliationResult{
+		Compared:   2,
+		Mismatched: 2,
+		Mismatches: []models.ReconciliationMismatch{{Index: 0, Reference: "ORD-1", Kinds: []string{models.MismatchMissing}}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	body := `{"items": [{"reference": "ORD-1", "amount": 10, "status": "success"}, {"reference": "ORD-2", "amount": "20.50", "status": "failed"}]}`
+	req, _ := http.NewRequest("POST", "/api/reconciliation/compare?limit=1", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response struct {
+		Data       models.ReconciliationResult `json:"data"`
+		Pagination models.Pagination           `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Data.Mismatched)
+	assert.Equal(t, int64(2), response.Pagination.Total)
+	assert.True(t, response.Pagination.HasMore)
+	mockService.AssertExpectations(t)
+}
+
+func TestReconciliationHandler_CompareValidation(t *testing.T) {
+	router, mockService := setupReconciliationTestRouter()
+
+	tests := map[string]string{
+		"empty":             `{"items": []}`,
+		"duplicate":         `{"items": [{"reference": "ORD-1", "amount": 1, "status": "success"}, {"reference": "ORD-1", "amount": 2, "status": "success"}]}`,
+		"unknown status":    `{"items": [{"reference": "ORD-1", "amount": 1, "status": "settled"}]}`,
+		"missing reference": `{"items": [{"amount": 1, "status": "success"}]}`,
+		"malformed":         `{"items": "ORD-1"}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/api/reconciliation/compare", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		})
+	}
+	mockService.AssertNotCalled(t, "Compare")
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"interview/internal/models"
+	"interview/internal/services"
+	"interview/internal/validation"
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// TagHandler handles transaction tag HTTP requests
+type TagHandler struct {
+	service   services.TagService
+	validator *validator.Validate
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(service services.TagService) *TagHandler {
+	return &TagHandler{
+		service:   service,
+		validator: validation.New(),
+	}
+}
+
+// AddTags handles POST /api/transactions/:id/tags
+func (h *TagHandler) AddTags(c *gin.Context) {
+	id, ok := transactionID(c, h.service)
+	if !ok {
+		return
+	}
+
+	var req models.AddTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.BadRequestResponse(c, "Validation failed: "+err.Error())
+		return
+	}
+
+	transaction, err := h.service.AddTags(c.Request.Context(), id, req)
+	if err != nil {
+		if err.Error() == "transaction not found" {
+			utils.NotFoundResponse(c, "Transaction not found")
+			return
+		}
+		if err.Error() == "invalid tag" {
+			utils.BadRequestResponse(c, "Invalid tag")
+			return
+		}
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, transaction, "Tags added successfully")
+}
+
+// RemoveTag handles DELETE /api/transactions/:id/tags/:tag
+func (h *TagHandler) RemoveTag(c *gin.Context) {
+	id, ok := transactionID(c, h.service)
+	if !ok {
+		return
+	}
+
+	transaction, err := h.service.RemoveTag(c.Request.Context(), id, c.Param("tag"))
+	if err != nil {
+		if err.Error() == "transaction not found" {
+			utils.NotFoundResponse(c, "Transaction not found")
+			return
+		}
+		if err.Error() == "tag not found" {
+			utils.NotFoundResponse(c, "Tag not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, transaction, "Tag removed successfully")
+}
@@ -0,0 +1,131 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"interview/internal/handlers"
+	"interview/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTagService is a mock implementation of TagService
+type MockTagService struct {
+	mock.Mock
+}
+
+func (m *MockTagService) AddTags(ctx context.Context, transactionID uint, req models.AddTagsRequest) (*models.Transaction, error) {
+	args := m.Called(transactionID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTagService) RemoveTag(ctx context.Context, transactionID uint, name string) (*models.Transaction, error) {
+	args := m.Called(transactionID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTagService) ResolveTransactionID(ctx context.Context, uuid string) (uint, error) {
+	args := m.Called(uuid)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func setupTagRouter() (*gin.Engine, *MockTagService) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockTagService)
+	handler := handlers.NewTagHandler(mockService)
+
+	router := gin.New()
+	router.POST("/api/transactions/:id/tags", handler.AddTags)
+	router.DELETE("/api/transactions/:id/tags/:tag", handler.RemoveTag)
+	return router, mockService
+}
+
+func TestTagHandler_AddTags(t *testing.T) {
+	router, mockService := setupTagRouter()
+
+	mockService.On("AddTags", uint(1), models.AddTagsRequest{Tags: []string{"refund", "vip"}}).
+		Return(&models.Transaction{ID: 1, Tags: []models.Tag{{ID: 1, Name: "refund"}, {ID: 2, Name: "vip"}}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/transactions/1/tags", bytes.NewBufferString(`{"tags": ["refund", "vip"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data struct {
+			Tags []string `json:"tags"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{"refund", "vip"}, response.Data.Tags)
+	mockService.AssertExpectations(t)
+}
+
+func TestTagHandler_AddTagsErrors(t *testing.T) {
+	router, mockService := setupTagRouter()
+
+	mockService.On("AddTags", uint(999), mock.Anything).Return(nil, errors.New("transaction not found"))
+	mockService.On("AddTags", uint(2), mock.Anything).Return(nil, errors.New("invalid tag"))
+
+	tests := []struct {
+		path string
+		body string
+		code int
+	}{
+		{"/api/transactions/abc/tags", `{"tags": ["refund"]}`, http.StatusBadRequest},
+		{"/api/transactions/1/tags", `{"tags": []}`, http.StatusBadRequest},
+		{"/api/transactions/1/tags", `{"tags": [""]}`, http.StatusBadRequest},
+		{"/api/transactions/2/tags", `{"tags": [" "]}`, http.StatusBadRequest},
+		{"/api/transactions/999/tags", `{"tags": ["refund"]}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", tt.path, bytes.NewBufferString(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tt.code, w.Code, "%s %s", tt.path, tt.body)
+	}
+}
+
+func TestTagHandler_RemoveTag(t *testing.T) {
+	router, mockService := setupTagRouter()
+
+	mockService.On("RemoveTag", uint(1), "vip").Return(&models.Transaction{ID: 1}, nil)
+	mockService.On("RemoveTag", uint(1), "missing").Return(nil, errors.New("tag not found"))
+	mockService.On("RemoveTag", uint(999), "vip").Return(nil, errors.New("transaction not found"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/transactions/1/tags/vip", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/api/transactions/1/tags/missing", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Tag not found")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/api/transactions/999/tags/vip", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Transaction not found")
+
+	mockService.AssertExpectations(t)
+}
@@ -1,14 +1,22 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"interview/internal/models"
 	"interview/internal/services"
+	"interview/internal/validation"
 	"interview/pkg/utils"
 
-	"github.com/shopspring/decimal"
-
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
@@ -16,26 +24,30 @@ import (
 // TransactionHandler handles transaction HTTP requests
 type TransactionHandler struct {
 	service   services.TransactionService
+	jobs      services.CreateJobService
 	validator *validator.Validate
 }
 
-// NewTransactionHandler creates a new transaction handler
-func NewTransactionHandler(service services.TransactionService) *TransactionHandler {
-	validator := validator.New()
-
-	// Register custom validation for decimal.Decimal
-	validator.RegisterValidation("decimal_positive", validateDecimalPositive)
+// TransactionHandlerOption configures optional transaction handler behavior
+type TransactionHandlerOption func(*TransactionHandler)
 
-	return &TransactionHandler{
-		service:   service,
-		validator: validator,
+// WithCreateJobs enables ?async=true creates, queued through jobs
+func WithCreateJobs(jobs services.CreateJobService) TransactionHandlerOption {
+	return func(h *TransactionHandler) {
+		h.jobs = jobs
 	}
 }
 
-// validateDecimalPositive validates that a decimal.Decimal value is positive
-func validateDecimalPositive(fl validator.FieldLevel) bool {
-	amount := fl.Field().Interface().(decimal.Decimal)
-	return amount.GreaterThan(decimal.Zero)
+// NewTransactionHandler creates a new transaction handler
+func NewTransactionHandler(service services.TransactionService, opts ...TransactionHandlerOption) *TransactionHandler {
+	h := &TransactionHandler{
+		service:   service,
+		validator: validation.New(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // CreateTransaction handles POST /api/transactions
@@ -51,9 +63,39 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 		utils.BadRequestResponse(c, "Validation failed: "+err.Error())
 		return
 	}
+	isTest, err := testDataHeader(c)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid X-Test-Data header, must be true or false")
+		return
+	}
+	req.IsTest = req.IsTest || isTest
 
-	transaction, err := h.service.CreateTransaction(req)
+	if async := c.Query("async"); async != "" {
+		enabled, err := strconv.ParseBool(async)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid async, must be true or false")
+			return
+		}
+		if enabled {
+			h.enqueueTransaction(c, req)
+			return
+		}
+	}
+
+	transaction, err := h.service.CreateTransaction(c.Request.Context(), req)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidAmount) {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrBudgetExceeded) || errors.Is(err, services.ErrBudgetCurrency) || errors.Is(err, services.ErrUserNotFound) || errors.Is(err, services.ErrRejectedByHook) {
+			utils.ErrorResponse(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrDuplicateReference) {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
 		utils.InternalServerErrorResponse(c, err.Error())
 		return
 	}
@@ -61,34 +103,240 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 	utils.CreatedResponse(c, transaction, "Transaction created successfully")
 }
 
+// TestDataHeader marks the transactions a create request makes as test data,
+// like their is_test field
+const TestDataHeader = "X-Test-Data"
+
+// testDataHeader parses the TestDataHeader of a request; it is false when absent
+func testDataHeader(c *gin.Context) (bool, error) {
+	value := c.GetHeader(TestDataHeader)
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// enqueueTransaction queues a validated create request and responds with the
+// job to poll. The amount policy, budget and user checks run when the job is
+// processed, and a job that fails them reports the reason.
+func (h *TransactionHandler) enqueueTransaction(c *gin.Context, req models.CreateTransactionRequest) {
+	if h.jobs == nil {
+		utils.ErrorResponse(c, http.StatusNotImplemented, "Async create is not enabled")
+		return
+	}
+
+	job, err := h.jobs.Enqueue(c.Request.Context(), req)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	c.Header("Location", strings.TrimSuffix(c.Request.URL.Path, "/")+"/jobs/"+job.ID)
+	utils.AcceptedResponse(c, job, "Transaction queued for creation")
+}
+
+// GetCreateJob handles GET /api/transactions/jobs/:job_id
+func (h *TransactionHandler) GetCreateJob(c *gin.Context) {
+	if h.jobs == nil {
+		utils.ErrorResponse(c, http.StatusNotImplemented, "Async create is not enabled")
+		return
+	}
+
+	id := c.Param("job_id")
+	if !utils.IsUUID(id) {
+		utils.BadRequestResponse(c, "Invalid job ID")
+		return
+	}
+
+	job, err := h.jobs.GetJob(c.Request.Context(), id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, job, "Job retrieved successfully")
+}
+
+// CreateTransactions handles POST /api/transactions/bulk
+func (h *TransactionHandler) CreateTransactions(c *gin.Context) {
+	var reqs []models.CreateTransactionRequest
+	if err := c.ShouldBindJSON(&reqs); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+
+	if len(reqs) == 0 || len(reqs) > models.MaxBulkTransactions {
+		utils.BadRequestResponse(c, fmt.Sprintf("Request must contain 1 to %d transactions", models.MaxBulkTransactions))
+		return
+	}
+
+	// The header marks every item as test data
+	isTest, err := testDataHeader(c)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid X-Test-Data header, must be true or false")
+		return
+	}
+	for i := range reqs {
+		reqs[i].IsTest = reqs[i].IsTest || isTest
+	}
+
+	// Items failing validation are reported here and never reach the service
+	result := &models.BulkCreateResult{Results: make([]models.BulkCreateItemResult, len(reqs))}
+	var valid []models.CreateTransactionRequest
+	var validIndexes []int
+	for i, req := range reqs {
+		result.Results[i].Index = i
+		if err := h.validator.Struct(req); err != nil {
+			result.Results[i].Status = models.BulkItemFailed
+			result.Results[i].Error = "Validation failed: " + err.Error()
+			result.Failed++
+			continue
+		}
+		valid = append(valid, req)
+		validIndexes = append(validIndexes, i)
+	}
+
+	if len(valid) > 0 {
+		created, err := h.service.CreateTransactions(c.Request.Context(), valid)
+		if err != nil {
+			utils.InternalServerErrorResponse(c, err.Error())
+			return
+		}
+		for i, item := range created.Results {
+			item.Index = validIndexes[i]
+			result.Results[item.Index] = item
+		}
+		result.Created = created.Created
+		result.Deduplicated = created.Deduplicated
+		result.Failed += created.Failed
+	}
+
+	if result.Created == 0 {
+		utils.SuccessResponse(c, result, "No transactions created")
+		return
+	}
+	utils.CreatedResponse(c, result, fmt.Sprintf("%d of %d transactions created", result.Created, len(reqs)))
+}
+
 // GetTransactions handles GET /api/transactions
 func (h *TransactionHandler) GetTransactions(c *gin.Context) {
+	filters, ok := bindTransactionFilters(c)
+	if !ok {
+		return
+	}
+	stream, ok := wantsStream(c)
+	if !ok {
+		return
+	}
+	if stream {
+		h.streamTransactions(c, filters)
+		return
+	}
+
+	transactions, total, err := h.service.GetTransactionsWithCount(c.Request.Context(), filters)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	limit, offset := filters.Page()
+	pagination := models.NewPagination(total, limit, offset, len(transactions))
+	utils.SetPaginationLinks(c, limit, offset, pagination.HasMore)
+	utils.PaginatedResponse(c, transactions, pagination, "Transactions retrieved successfully")
+}
+
+// excludeTestDataByDefault leaves test transactions out of an export's
+// filters unless include_test=true or is_test is set, writing a 400 response
+// when include_test is invalid
+func excludeTestDataByDefault(c *gin.Context, filters *models.TransactionFilters) bool {
+	includeTest, err := strconv.ParseBool(c.DefaultQuery("include_test", "false"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid include_test, must be true or false")
+		return false
+	}
+	if !includeTest && filters.IsTest == nil {
+		live := false
+		filters.IsTest = &live
+	}
+	return true
+}
+
+// bindTransactionFilters binds the transaction list query parameters, writing
+// a 400 response when they are invalid
+func bindTransactionFilters(c *gin.Context) (models.TransactionFilters, bool) {
 	var filters models.TransactionFilters
 
 	if err := c.ShouldBindQuery(&filters); err != nil {
 		utils.BadRequestResponse(c, "Invalid query parameters")
+		return filters, false
+	}
+
+	if filters.From != nil && filters.To != nil && filters.From.After(*filters.To) {
+		utils.BadRequestResponse(c, "Invalid date range, from must not be after to")
+		return filters, false
+	}
+
+	if filters.MinAmount != nil && filters.MaxAmount != nil && filters.MinAmount.GreaterThan(*filters.MaxAmount) {
+		utils.BadRequestResponse(c, "Invalid amount range, min_amount must not be greater than max_amount")
+		return filters, false
+	}
+	filters.Tag = services.NormalizeTag(filters.Tag)
+	for param, values := range c.Request.URL.Query() {
+		if key, ok := strings.CutPrefix(param, models.MetadataFilterPrefix); ok {
+			if filters.Metadata == nil {
+				filters.Metadata = map[string]string{}
+			}
+			filters.Metadata[key] = values[0]
+		}
+	}
+	return filters, true
+}
+
+// LookupTransactions handles POST /api/transactions/lookup
+func (h *TransactionHandler) LookupTransactions(c *gin.Context) {
+	var req models.TransactionLookupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.BadRequestResponse(c, "Validation failed: "+err.Error())
 		return
 	}
 
-	transactions, err := h.service.GetTransactions(filters)
+	result, err := h.service.LookupTransactions(c.Request.Context(), req.IDs)
 	if err != nil {
-		utils.BadRequestResponse(c, err.Error())
+		utils.InternalServerErrorResponse(c, err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, transactions, "Transactions retrieved successfully")
+	utils.SuccessResponse(c, result, "Transactions retrieved successfully")
 }
 
 // GetTransaction handles GET /api/transactions/:id
 func (h *TransactionHandler) GetTransaction(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.ParseUint(idParam, 10, 32)
+	id, ok := transactionID(c, h.service)
+	if !ok {
+		return
+	}
+
+	transaction, err := h.service.GetTransaction(c.Request.Context(), id)
 	if err != nil {
-		utils.BadRequestResponse(c, "Invalid transaction ID")
+		if err.Error() == "transaction not found" {
+			utils.NotFoundResponse(c, "Transaction not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, err.Error())
 		return
 	}
 
-	transaction, err := h.service.GetTransaction(uint(id))
+	utils.SuccessResponse(c, transaction, "Transaction retrieved successfully")
+}
+
+// GetTransactionByReference handles GET /api/transactions/by-reference/:ref
+func (h *TransactionHandler) GetTransactionByReference(c *gin.Context) {
+	transaction, err := h.service.GetTransactionByReference(c.Request.Context(), c.Param("ref"))
 	if err != nil {
 		if err.Error() == "transaction not found" {
 			utils.NotFoundResponse(c, "Transaction not found")
@@ -101,17 +349,46 @@ func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 	utils.SuccessResponse(c, transaction, "Transaction retrieved successfully")
 }
 
+// GetTransactionStatus handles GET /api/transactions/:id/status
+func (h *TransactionHandler) GetTransactionStatus(c *gin.Context) {
+	id, ok := transactionID(c, h.service)
+	if !ok {
+		return
+	}
+
+	status, err := h.service.GetTransactionStatus(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "transaction not found" {
+			utils.NotFoundResponse(c, "Transaction not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	// Pollers revalidate with If-None-Match and get an empty 304 while nothing changed
+	etag := fmt.Sprintf(`"%s-%d"`, status.Status, status.UpdatedAt.UnixNano())
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, max-age=1")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	utils.SuccessResponse(c, status, "Transaction status retrieved successfully")
+}
+
 // UpdateTransaction handles PUT /api/transactions/:id
 func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.ParseUint(idParam, 10, 32)
-	if err != nil {
-		utils.BadRequestResponse(c, "Invalid transaction ID")
+	id, ok := transactionID(c, h.service)
+	if !ok {
 		return
 	}
 
 	var req models.UpdateTransactionRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
 		utils.BadRequestResponse(c, "Invalid request body")
 		return
 	}
@@ -121,7 +398,7 @@ func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 		return
 	}
 
-	err = h.service.UpdateTransactionStatus(uint(id), req.Status)
+	err := h.service.UpdateTransaction(c.Request.Context(), id, req)
 	if err != nil {
 		if err.Error() == "transaction not found" {
 			utils.NotFoundResponse(c, "Transaction not found")
@@ -131,6 +408,16 @@ func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 			utils.BadRequestResponse(c, "Invalid status")
 			return
 		}
+		if err.Error() == "no fields to update" {
+			utils.BadRequestResponse(c, "No fields to update")
+			return
+		}
+		for _, updateErr := range updateErrors {
+			if errors.Is(err, updateErr.err) {
+				utils.ErrorCodeResponse(c, updateErr.status, updateErr.code, err.Error())
+				return
+			}
+		}
 		utils.InternalServerErrorResponse(c, err.Error())
 		return
 	}
@@ -138,24 +425,211 @@ func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 	utils.SuccessResponse(c, nil, "Transaction updated successfully")
 }
 
+// updateErrors are the update failures reported with an error code, so
+// clients can tell a stale version from a rejected status change
+var updateErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{services.ErrInvalidTransition, http.StatusConflict, "invalid_transition"},
+	{services.ErrVersionConflict, http.StatusConflict, "version_conflict"},
+	{services.ErrVersionRequired, http.StatusBadRequest, "version_required"},
+	{services.ErrReasonWithoutChange, http.StatusBadRequest, "reason_without_status_change"},
+	{services.ErrTooManyMetadataKeys, http.StatusBadRequest, "too_many_metadata_keys"},
+}
+
+// PatchTransaction handles PATCH /api/transactions/:id
+func (h *TransactionHandler) PatchTransaction(c *gin.Context) {
+	id, ok := transactionID(c, h.service)
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+	for _, field := range models.ImmutableTransactionFields {
+		if _, ok := fields[field]; ok {
+			utils.BadRequestResponse(c, "Field "+field+" cannot be changed")
+			return
+		}
+	}
+
+	var req models.PatchTransactionRequest
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.BadRequestResponse(c, "Validation failed: "+err.Error())
+		return
+	}
+
+	transaction, err := h.service.PatchTransaction(c.Request.Context(), id, req)
+	if err != nil {
+		if err.Error() == "transaction not found" {
+			utils.NotFoundResponse(c, "Transaction not found")
+			return
+		}
+		if err.Error() == "invalid status" {
+			utils.BadRequestResponse(c, "Invalid status")
+			return
+		}
+		if errors.Is(err, services.ErrInvalidTransition) {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrVersionConflict) {
+			utils.ErrorCodeResponse(c, http.StatusConflict, "version_conflict", err.Error())
+			return
+		}
+		if err.Error() == "no fields to update" {
+			utils.BadRequestResponse(c, "No fields to update")
+			return
+		}
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, transaction, "Transaction updated successfully")
+}
+
+// RefundTransaction handles POST /api/transactions/:id/refund
+func (h *TransactionHandler) RefundTransaction(c *gin.Context) {
+	id, ok := transactionID(c, h.service)
+	if !ok {
+		return
+	}
+
+	refund, err := h.service.RefundTransaction(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "transaction not found" {
+			utils.NotFoundResponse(c, "Transaction not found")
+			return
+		}
+		if errors.Is(err, services.ErrNotRefundable) {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.CreatedResponse(c, refund, "Transaction refunded successfully")
+}
+
 // DeleteTransaction handles DELETE /api/transactions/:id
 func (h *TransactionHandler) DeleteTransaction(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.ParseUint(idParam, 10, 32)
-	if err != nil {
-		utils.BadRequestResponse(c, "Invalid transaction ID")
+	id, ok := transactionID(c, h.service)
+	if !ok {
 		return
 	}
 
-	err = h.service.DeleteTransaction(uint(id))
+	err := h.service.DeleteTransaction(c.Request.Context(), id)
 	if err != nil {
 		if err.Error() == "transaction not found" {
 			utils.NotFoundResponse(c, "Transaction not found")
 			return
 		}
+		if errors.Is(err, services.ErrRejectedByHook) {
+			utils.ErrorResponse(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrNotDeletable) {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
 		utils.InternalServerErrorResponse(c, err.Error())
 		return
 	}
 
 	utils.SuccessResponse(c, nil, "Transaction deleted successfully")
 }
+
+// Bounds of the timeout accepted by WaitForStatusChange
+const (
+	defaultWaitTimeout = 30 * time.Second
+	maxWaitTimeout     = 60 * time.Second
+)
+
+// WaitForStatusChange handles GET /api/transactions/:id/wait
+func (h *TransactionHandler) WaitForStatusChange(c *gin.Context) {
+	id, ok := transactionID(c, h.service)
+	if !ok {
+		return
+	}
+
+	timeout := defaultWaitTimeout
+	if value := c.Query("timeout"); value != "" {
+		var err error
+		timeout, err = time.ParseDuration(value)
+		if err != nil || timeout <= 0 || timeout > maxWaitTimeout {
+			utils.BadRequestResponse(c, "Invalid timeout, must be a duration up to "+maxWaitTimeout.String())
+			return
+		}
+	}
+
+	status := c.Query("status")
+	if status != "" && status != "pending" && status != "success" && status != "failed" && status != "refunded" {
+		utils.BadRequestResponse(c, "Invalid status")
+		return
+	}
+
+	// The wait may outlast the server's write timeout
+	extendWriteDeadline(c, timeout+streamWriteTimeout)
+	result, err := h.service.WaitForStatusChange(c.Request.Context(), id, status, timeout)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			// The client went away, nobody is left to respond to
+			return
+		}
+		if err.Error() == "transaction not found" {
+			utils.NotFoundResponse(c, "Transaction not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	message := "Transaction status unchanged"
+	if result.Changed {
+		message = "Transaction status changed"
+	}
+	utils.SuccessResponse(c, result, message)
+}
+
+// DescribeTransactions handles OPTIONS /api/transactions
+func (h *TransactionHandler) DescribeTransactions(c *gin.Context) {
+	describeResource(c, models.ResourceDescription{
+		Resource:     "transactions",
+		Methods:      []string{"GET", "POST"},
+		ContentTypes: listContentTypes,
+		Filters:      []string{"user_id", "status", "from", "to", "min_amount", "max_amount", "tag", "direction", "is_test", "metadata.<key>", "sort_by", "order", "limit", "offset", "stream"},
+		Limits: map[string]int{
+			"default_page_size": models.DefaultPageSize,
+			"max_page_size":     models.MaxPageSize,
+			"max_bulk_size":     models.MaxBulkTransactions,
+		},
+	})
+}
+
+// DescribeTransaction handles OPTIONS /api/transactions/:id
+func (h *TransactionHandler) DescribeTransaction(c *gin.Context) {
+	describeResource(c, models.ResourceDescription{
+		Resource:     "transaction",
+		Methods:      []string{"GET", "PUT", "PATCH", "DELETE"},
+		ContentTypes: jsonContentTypes,
+	})
+}
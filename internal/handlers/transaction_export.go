@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"interview/internal/models"
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportFlushRows is how many CSV rows are buffered before they are sent
+const exportFlushRows = 500
+
+// exportColumns is the CSV header of transaction exports
+var exportColumns = []string{
+	"id", "uuid", "reference_id", "user_id", "amount", "currency", "type", "direction",
+	"status", "description", "metadata", "parent_id", "is_test", "version", "created_at", "updated_at",
+}
+
+// ExportTransactions handles GET /api/transactions/export. It takes the list
+// filters and sort order, but no pagination: every matching transaction is
+// streamed as CSV while it is read from the database.
+func (h *TransactionHandler) ExportTransactions(c *gin.Context) {
+	if format := c.DefaultQuery("format", "csv"); format != "csv" {
+		utils.ValidationErrorResponse(c, map[string]string{"format": "must be csv"})
+		return
+	}
+	filters, ok := bindTransactionFilters(c)
+	if !ok {
+		return
+	}
+	// Like every export, test data is left out unless include_test=true
+	if !excludeTestDataByDefault(c, &filters) {
+		return
+	}
+	filters.Limit, filters.Offset = 0, 0
+
+	// The header is sent with the first row, so errors before it still get a
+	// JSON response
+	var writer *csv.Writer
+	rows := 0
+	start := func() error {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="transactions.csv"`)
+		writer = csv.NewWriter(c.Writer)
+		return writer.Write(exportColumns)
+	}
+
+	extendWriteDeadline(c, streamWriteTimeout)
+	err := h.service.ExportTransactions(c.Request.Context(), filters, func(transaction *models.Transaction) error {
+		if writer == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := writer.Write(exportRow(transaction)); err != nil {
+			return err
+		}
+		if rows++; rows%exportFlushRows == 0 {
+			writer.Flush()
+			c.Writer.Flush()
+			extendWriteDeadline(c, streamWriteTimeout)
+		}
+		return writer.Error()
+	})
+	if err != nil && writer == nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+	if err != nil {
+		// The status is already sent, so the client sees a truncated file
+		c.Error(err)
+		return
+	}
+
+	if writer == nil {
+		if err := start(); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		c.Error(err)
+	}
+}
+
+// exportRow formats a transaction as a CSV row in exportColumns order
+func exportRow(transaction *models.Transaction) []string {
+	metadata := ""
+	if len(transaction.Metadata) > 0 {
+		encoded, _ := json.Marshal(transaction.Metadata)
+		metadata = string(encoded)
+	}
+	parentID := ""
+	if transaction.ParentID != nil {
+		parentID = strconv.FormatUint(uint64(*transaction.ParentID), 10)
+	}
+
+	return []string{
+		strconv.FormatUint(uint64(transaction.ID), 10),
+		stringValue(transaction.UUID),
+		csvText(stringValue(transaction.ReferenceID)),
+		strconv.FormatUint(uint64(transaction.UserID), 10),
+		transaction.Amount.String(),
+		transaction.Currency,
+		transaction.Type,
+		transaction.Direction,
+		transaction.Status,
+		csvText(transaction.Description),
+		csvText(metadata),
+		parentID,
+		strconv.FormatBool(transaction.IsTest),
+		strconv.FormatUint(uint64(transaction.Version), 10),
+		transaction.CreatedAt.UTC().Format(time.RFC3339),
+		transaction.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// stringValue returns the string s points to, or "" for nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// csvText keeps client supplied text from being read as a formula by
+// spreadsheets, by prefixing values starting with a formula character with '
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/services"
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/websocket"
+)
+
+// Timing of live feed connections: a ping is sent every feedPingPeriod, and a
+// client that does not answer within feedPongWait is disconnected
+const (
+	feedWriteWait  = 10 * time.Second
+	feedPongWait   = 60 * time.Second
+	feedPingPeriod = feedPongWait * 9 / 10
+)
+
+// feedReadLimit is the largest message accepted from feed clients, which
+// only send control frames
+const feedReadLimit = 512
+
+// FeedHandler handles the live transaction feed
+type FeedHandler struct {
+	feed           *services.TransactionFeed
+	upgrader       websocket.Upgrader
+	queryValidator *validator.Validate
+}
+
+// NewFeedHandler creates a new live transaction feed handler
+func NewFeedHandler(feed *services.TransactionFeed) *FeedHandler {
+	return &FeedHandler{
+		feed: feed,
+		// Like the CORS policy, the feed is open to pages of any origin
+		upgrader:       websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }},
+		queryValidator: newQueryValidator(),
+	}
+}
+
+// StreamTransactions handles GET /api/ws/transactions. It upgrades to a
+// WebSocket and sends every created or updated transaction matching the
+// user_id and status filters as a JSON text message until either side closes.
+func (h *FeedHandler) StreamTransactions(c *gin.Context) {
+	var filter models.TransactionFeedFilter
+	if !bindQuery(c, h.queryValidator, &filter) {
+		return
+	}
+	if !websocket.IsWebSocketUpgrade(c.Request) {
+		utils.BadRequestResponse(c, "Expected a WebSocket upgrade request")
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already responded
+		c.Error(err)
+		return
+	}
+	defer conn.Close()
+
+	transactions, cancel := h.feed.Subscribe(filter)
+	defer cancel()
+
+	// Reading answers the client's control frames and notices when it goes away
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		conn.SetReadLimit(feedReadLimit)
+		conn.SetReadDeadline(time.Now().Add(feedPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(feedPongWait))
+		})
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(feedPingPeriod)
+	defer ping.Stop()
+	for {
+		select {
+		case event, ok := <-transactions:
+			conn.SetWriteDeadline(time.Now().Add(feedWriteWait))
+			if !ok {
+				// The feed stopped because the server is shutting down
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+				return
+			}
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(feedWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-gone:
+			return
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"interview/internal/events"
+	"interview/internal/handlers"
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupFeedServer(t *testing.T) (*httptest.Server, *events.Bus, *MockTransactionService) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockTransactionService)
+	bus := events.NewBus()
+	feed := services.NewTransactionFeed(bus, mockService)
+	t.Cleanup(feed.Start())
+
+	router := gin.New()
+	router.GET("/api/ws/transactions", handlers.NewFeedHandler(feed).StreamTransactions)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server, bus, mockService
+}
+
+func TestFeedHandler_StreamTransactions(t *testing.T) {
+	server, bus, mockService := setupFeedServer(t)
+	mockService.On("GetTransaction", uint(1)).Return(&models.Transaction{ID: 1, UserID: 7, Status: "pending"}, nil)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/ws/transactions?user_id=7"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The subscription starts after the handshake, so publish until it is seen
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			bus.Publish(events.Event{Type: events.TransactionCreated, TransactionID: 2, UserID: 8, Status: "pending"})
+			bus.Publish(events.Event{Type: events.TransactionCreated, TransactionID: 1, UserID: 7, Status: "pending"})
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var event models.TransactionFeedEvent
+	require.NoError(t, conn.ReadJSON(&event))
+	assert.Equal(t, events.TransactionCreated, event.Type)
+	assert.Equal(t, uint(1), event.Transaction.ID)
+	mockService.AssertNotCalled(t, "GetTransaction", uint(2))
+}
+
+func TestFeedHandler_StreamTransactions_InvalidStatus(t *testing.T) {
+	server, _, _ := setupFeedServer(t)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/ws/transactions?status=unknown"
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestFeedHandler_StreamTransactions_NotUpgrade(t *testing.T) {
+	server, _, _ := setupFeedServer(t)
+
+	resp, err := http.Get(server.URL + "/api/ws/transactions")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// transactionResolver maps public transaction UUIDs to their IDs
+type transactionResolver interface {
+	ResolveTransactionID(ctx context.Context, uuid string) (uint, error)
+}
+
+// transactionID reads the :id path parameter, which may be either a numeric
+// transaction ID or a public UUID, and writes the error response when it
+// cannot be resolved
+func transactionID(c *gin.Context, resolver transactionResolver) (uint, bool) {
+	param := c.Param("id")
+	if id, err := strconv.ParseUint(param, 10, 32); err == nil {
+		return uint(id), true
+	}
+	if !utils.IsUUID(param) {
+		utils.BadRequestResponse(c, "Invalid transaction ID")
+		return 0, false
+	}
+
+	id, err := resolver.ResolveTransactionID(c.Request.Context(), param)
+	if err != nil {
+		if err.Error() == "transaction not found" {
+			utils.NotFoundResponse(c, "Transaction not found")
+			return 0, false
+		}
+		utils.InternalServerErrorResponse(c, err.Error())
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"interview/internal/models"
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// ndjsonContentType is the media type of newline delimited JSON
+const ndjsonContentType = "application/x-ndjson"
+
+// listContentTypes lists the content types transaction lists are produced in
+var listContentTypes = []string{binding.MIMEJSON, ndjsonContentType}
+
+// streamWriteTimeout is how long a streaming response may take to send each
+// batch of rows, however long the whole response takes
+const streamWriteTimeout = 30 * time.Second
+
+// extendWriteDeadline gives the response d from now to be sent, past the
+// server's write timeout. Writers without deadlines, as in tests, are left
+// as they are.
+func extendWriteDeadline(c *gin.Context, d time.Duration) {
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(d))
+}
+
+// wantsStream reports whether a transaction list request asks for NDJSON,
+// with Accept: application/x-ndjson or ?stream=true, writing the 400
+// response when stream is invalid
+func wantsStream(c *gin.Context) (stream, ok bool) {
+	if value, set := c.GetQuery("stream"); set {
+		stream, err := strconv.ParseBool(value)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid stream, must be true or false")
+			return false, false
+		}
+		if stream {
+			return true, true
+		}
+	}
+	return c.NegotiateFormat(binding.MIMEJSON, ndjsonContentType) == ndjsonContentType, true
+}
+
+// streamTransactions writes every transaction matching the filters as one
+// JSON object per line while they are read from the database. Like exports,
+// it ignores pagination.
+func (h *TransactionHandler) streamTransactions(c *gin.Context, filters models.TransactionFilters) {
+	filters.Limit, filters.Offset = 0, 0
+
+	// The header is sent with the first row, so errors before it still get a
+	// JSON response
+	var encoder *json.Encoder
+	rows := 0
+	extendWriteDeadline(c, streamWriteTimeout)
+	err := h.service.ExportTransactions(c.Request.Context(), filters, func(transaction *models.Transaction) error {
+		if encoder == nil {
+			c.Header("Content-Type", ndjsonContentType)
+			encoder = json.NewEncoder(c.Writer)
+		}
+		if err := encoder.Encode(transaction); err != nil {
+			return err
+		}
+		if rows++; rows%exportFlushRows == 0 {
+			c.Writer.Flush()
+			extendWriteDeadline(c, streamWriteTimeout)
+		}
+		return nil
+	})
+	if err != nil && encoder == nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+	if err != nil {
+		// The status is already sent, so the client sees a truncated stream
+		c.Error(err)
+		return
+	}
+
+	if encoder == nil {
+		c.Data(http.StatusOK, ndjsonContentType, nil)
+	}
+}
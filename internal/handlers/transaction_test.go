@@ -2,20 +2,26 @@ package handlers_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"interview/internal/handlers"
 	"interview/internal/models"
+	"interview/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockTransactionService is a mock implementation of TransactionService
@@ -23,7 +29,7 @@ type MockTransactionService struct {
 	mock.Mock
 }
 
-func (m *MockTransactionService) CreateTransaction(req models.CreateTransactionRequest) (*models.Transaction, error) {
+func (m *MockTransactionService) CreateTransaction(ctx context.Context, req models.CreateTransactionRequest) (*models.Transaction, error) {
 	args := m.Called(req)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -31,7 +37,15 @@ func (m *MockTransactionService) CreateTransaction(req models.CreateTransactionR
 	return args.Get(0).(*models.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionService) GetTransaction(id uint) (*models.Transaction, error) {
+func (m *MockTransactionService) CreateTransactions(ctx context.Context, reqs []models.CreateTransactionRequest) (*models.BulkCreateResult, error) {
+	args := m.Called(reqs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BulkCreateResult), args.Error(1)
+}
+
+func (m *MockTransactionService) GetTransaction(ctx context.Context, id uint) (*models.Transaction, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -39,21 +53,94 @@ func (m *MockTransactionService) GetTransaction(id uint) (*models.Transaction, e
 	return args.Get(0).(*models.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionService) GetTransactions(filters models.TransactionFilters) ([]models.Transaction, error) {
+func (m *MockTransactionService) GetTransactionByReference(ctx context.Context, referenceID string) (*models.Transaction, error) {
+	args := m.Called(referenceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) ResolveTransactionID(ctx context.Context, uuid string) (uint, error) {
+	args := m.Called(uuid)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockTransactionService) GetTransactionStatus(ctx context.Context, id uint) (*models.TransactionStatus, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TransactionStatus), args.Error(1)
+}
+
+func (m *MockTransactionService) GetTransactions(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, error) {
 	args := m.Called(filters)
 	return args.Get(0).([]models.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionService) UpdateTransactionStatus(id uint, status string) error {
+func (m *MockTransactionService) ExportTransactions(ctx context.Context, filters models.TransactionFilters, fn func(*models.Transaction) error) error {
+	args := m.Called(filters)
+	for _, transaction := range args.Get(0).([]models.Transaction) {
+		if err := fn(&transaction); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTransactionService) GetTransactionsWithCount(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, int64, error) {
+	args := m.Called(filters)
+	return args.Get(0).([]models.Transaction), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTransactionService) LookupTransactions(ctx context.Context, ids []uint) (*models.TransactionLookupResult, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TransactionLookupResult), args.Error(1)
+}
+
+func (m *MockTransactionService) UpdateTransactionStatus(ctx context.Context, id uint, status string) error {
 	args := m.Called(id, status)
 	return args.Error(0)
 }
 
-func (m *MockTransactionService) DeleteTransaction(id uint) error {
+func (m *MockTransactionService) UpdateTransaction(ctx context.Context, id uint, req models.UpdateTransactionRequest) error {
+	args := m.Called(id, req)
+	return args.Error(0)
+}
+
+func (m *MockTransactionService) PatchTransaction(ctx context.Context, id uint, req models.PatchTransactionRequest) (*models.Transaction, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) DeleteTransaction(ctx context.Context, id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+func (m *MockTransactionService) RefundTransaction(ctx context.Context, id uint) (*models.Transaction, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) WaitForStatusChange(ctx context.Context, id uint, lastStatus string, timeout time.Duration) (*models.TransactionWaitResult, error) {
+	args := m.Called(ctx, id, lastStatus, timeout)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TransactionWaitResult), args.Error(1)
+}
+
 func setupTestRouter() (*gin.Engine, *MockTransactionService) {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
@@ -64,9 +151,15 @@ func setupTestRouter() (*gin.Engine, *MockTransactionService) {
 	{
 		api.POST("/transactions", handler.CreateTransaction)
 		api.GET("/transactions", handler.GetTransactions)
+		api.POST("/transactions/bulk", handler.CreateTransactions)
+		api.POST("/transactions/lookup", handler.LookupTransactions)
+		api.GET("/transactions/export", handler.ExportTransactions)
 		api.GET("/transactions/:id", handler.GetTransaction)
 		api.PUT("/transactions/:id", handler.UpdateTransaction)
+		api.PATCH("/transactions/:id", handler.PatchTransaction)
 		api.DELETE("/transactions/:id", handler.DeleteTransaction)
+		api.GET("/transactions/:id/wait", handler.WaitForStatusChange)
+		api.GET("/transactions/:id/status", handler.GetTransactionStatus)
 	}
 
 	return router, mockService
@@ -100,6 +193,42 @@ func TestTransactionHandler_CreateTransaction(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestTransactionHandler_CreateTestTransaction(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	req := models.CreateTransactionRequest{UserID: 1, Amount: decimal.NewFromInt(1), IsTest: true}
+	mockService.On("CreateTransaction", req).Return(&models.Transaction{ID: 1, IsTest: true}, nil)
+
+	for _, test := range []struct {
+		name   string
+		body   string
+		header string
+	}{
+		{name: "header", body: `{"user_id":1,"amount":"1"}`, header: "true"},
+		{name: "body", body: `{"user_id":1,"amount":"1","is_test":true}`},
+		{name: "header does not clear the body field", body: `{"user_id":1,"amount":"1","is_test":true}`, header: "false"},
+	} {
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("POST", "/api/transactions", bytes.NewBufferString(test.body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		if test.header != "" {
+			httpReq.Header.Set(handlers.TestDataHeader, test.header)
+		}
+
+		router.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusCreated, w.Code, test.name)
+	}
+	mockService.AssertNumberOfCalls(t, "CreateTransaction", 3)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/api/transactions", bytes.NewBufferString(`{"user_id":1,"amount":"1"}`))
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(handlers.TestDataHeader, "sandbox")
+	router.ServeHTTP(w, httpReq)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestTransactionHandler_CreateTransactionInvalidJSON(t *testing.T) {
 	router, _ := setupTestRouter()
 
@@ -131,6 +260,291 @@ func TestTransactionHandler_CreateTransactionValidationFailed(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestTransactionHandler_CreateTransactionInvalidDirection(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/api/transactions", bytes.NewBufferString(`{"user_id": 1, "amount": "10", "direction": "inbound"}`))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "CreateTransaction")
+}
+
+func TestTransactionHandler_CreateTransactionMetadataTooLarge(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	metadata := map[string]string{}
+	for i := 0; i < 21; i++ {
+		metadata[fmt.Sprintf("key%d", i)] = "value"
+	}
+	body, _ := json.Marshal(map[string]interface{}{"user_id": 1, "amount": "10", "metadata": metadata})
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/api/transactions", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "CreateTransaction")
+}
+
+func TestTransactionHandler_CreateTransactionDuplicateReference(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	req := models.CreateTransactionRequest{UserID: 1, Amount: decimal.NewFromInt(10), ReferenceID: "ORD-1"}
+	mockService.On("CreateTransaction", req).Return((*models.Transaction)(nil), fmt.Errorf("%w: ORD-1", services.ErrDuplicateReference))
+
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/api/transactions", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_CreateTransactionRejectedByHook(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	req := models.CreateTransactionRequest{UserID: 1, Amount: decimal.NewFromInt(10)}
+	mockService.On("CreateTransaction", req).Return((*models.Transaction)(nil), fmt.Errorf("%w: needs approval", services.ErrRejectedByHook))
+
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/api/transactions", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_CreateTransactionUnknownUser(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	req := models.CreateTransactionRequest{UserID: 42, Amount: decimal.NewFromInt(10)}
+	mockService.On("CreateTransaction", req).Return((*models.Transaction)(nil), fmt.Errorf("%w: 42", services.ErrUserNotFound))
+
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/api/transactions", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "user not found: 42")
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_RefundTransaction(t *testing.T) {
+	router, mockService := setupTestRouter()
+	router.POST("/refund/:id", handlers.NewTransactionHandler(mockService).RefundTransaction)
+
+	parentID := uint(7)
+	mockService.On("RefundTransaction", uint(7)).Return(&models.Transaction{ID: 8, ParentID: &parentID, Type: models.TransactionTypeRefund, Status: "success"}, nil)
+	mockService.On("RefundTransaction", uint(9)).Return(nil, fmt.Errorf("%w: status is pending, not success", services.ErrNotRefundable))
+	mockService.On("RefundTransaction", uint(404)).Return(nil, errors.New("transaction not found"))
+
+	tests := []struct {
+		id     string
+		status int
+		body   string
+	}{
+		{"7", http.StatusCreated, `"parent_id":7`},
+		{"9", http.StatusConflict, "transaction cannot be refunded"},
+		{"404", http.StatusNotFound, "Transaction not found"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/refund/"+tt.id, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.status, w.Code, tt.id)
+		assert.Contains(t, w.Body.String(), tt.body)
+	}
+	mockService.AssertExpectations(t)
+}
+
+// MockCreateJobService is a mock implementation of CreateJobService
+type MockCreateJobService struct {
+	mock.Mock
+}
+
+func (m *MockCreateJobService) Enqueue(ctx context.Context, req models.CreateTransactionRequest) (*models.CreateJob, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreateJob), args.Error(1)
+}
+
+func (m *MockCreateJobService) GetJob(ctx context.Context, id string) (*models.CreateJob, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreateJob), args.Error(1)
+}
+
+func setupAsyncRouter(jobs services.CreateJobService) (*gin.Engine, *MockTransactionService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockService := new(MockTransactionService)
+	var opts []handlers.TransactionHandlerOption
+	if jobs != nil {
+		opts = append(opts, handlers.WithCreateJobs(jobs))
+	}
+	handler := handlers.NewTransactionHandler(mockService, opts...)
+
+	router.POST("/api/transactions", handler.CreateTransaction)
+	router.GET("/api/transactions/jobs/:job_id", handler.GetCreateJob)
+	return router, mockService
+}
+
+func TestTransactionHandler_CreateTransactionAsync(t *testing.T) {
+	jobs := new(MockCreateJobService)
+	router, mockService := setupAsyncRouter(jobs)
+
+	req := models.CreateTransactionRequest{UserID: 1, Amount: decimal.NewFromInt(10)}
+	jobID := "8a1c2f4e-5b6d-4e7f-8a9b-0c1d2e3f4a5b"
+	jobs.On("Enqueue", req).Return(&models.CreateJob{ID: jobID, Status: models.CreateJobPending}, nil)
+
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/api/transactions?async=true", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "/api/transactions/jobs/"+jobID, w.Header().Get("Location"))
+	assert.Contains(t, w.Body.String(), `"status":"pending"`)
+	jobs.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "CreateTransaction", mock.Anything)
+}
+
+func TestTransactionHandler_CreateTransactionAsyncErrors(t *testing.T) {
+	body := `{"user_id":1,"amount":"10"}`
+	tests := []struct {
+		name   string
+		jobs   services.CreateJobService
+		query  string
+		status int
+	}{
+		{"invalid async", new(MockCreateJobService), "?async=maybe", http.StatusBadRequest},
+		{"not enabled", nil, "?async=true", http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, _ := setupAsyncRouter(tt.jobs)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/api/transactions"+tt.query, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestTransactionHandler_GetCreateJob(t *testing.T) {
+	jobs := new(MockCreateJobService)
+	router, _ := setupAsyncRouter(jobs)
+
+	jobID := "8a1c2f4e-5b6d-4e7f-8a9b-0c1d2e3f4a5b"
+	jobs.On("GetJob", jobID).Return(&models.CreateJob{ID: jobID, Status: models.CreateJobSucceeded, TransactionID: 7}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/transactions/jobs/"+jobID, nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"succeeded"`)
+	assert.Contains(t, w.Body.String(), `"transaction_id":7`)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/transactions/jobs/not-a-job", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	jobs.AssertExpectations(t)
+
+	router, _ = setupAsyncRouter(nil)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/transactions/jobs/"+jobID, nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
+
+func TestTransactionHandler_GetTransactionByReference(t *testing.T) {
+	mockService := new(MockTransactionService)
+	handler := handlers.NewTransactionHandler(mockService)
+
+	router, _ := setupTestRouter()
+	router.GET("/transactions/by-reference/:ref", handler.GetTransactionByReference)
+
+	reference := "ORD-1"
+	mockService.On("GetTransactionByReference", "ORD-1").Return(&models.Transaction{ID: 7, ReferenceID: &reference}, nil)
+	mockService.On("GetTransactionByReference", "missing").Return(nil, errors.New("transaction not found"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/transactions/by-reference/ORD-1", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"reference_id":"ORD-1"`)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/transactions/by-reference/missing", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_GetTransactionByUUID(t *testing.T) {
+	mockService := new(MockTransactionService)
+	handler := handlers.NewTransactionHandler(mockService)
+
+	router, _ := setupTestRouter()
+	router.GET("/transactions/:id", handler.GetTransaction)
+
+	uuid := "9b2f6c1e-3d4a-4f5b-8c6d-7e8f9a0b1c2d"
+	missing := "00000000-0000-4000-8000-000000000000"
+	mockService.On("ResolveTransactionID", uuid).Return(uint(7), nil)
+	mockService.On("ResolveTransactionID", missing).Return(uint(0), errors.New("transaction not found"))
+	mockService.On("GetTransaction", uint(7)).Return(&models.Transaction{ID: 7, UUID: &uuid}, nil)
+
+	// Numeric IDs are still accepted during the transition to UUIDs
+	for _, path := range []string{"/transactions/" + uuid, "/transactions/7"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Contains(t, w.Body.String(), `"uuid":"`+uuid+`"`)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/transactions/"+missing, nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/transactions/not-a-uuid", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockService.AssertExpectations(t)
+	mockService.AssertNumberOfCalls(t, "GetTransaction", 2)
+}
+
 func TestTransactionHandler_CreateTransactionServiceError(t *testing.T) {
 	router, mockService := setupTestRouter()
 
@@ -152,6 +566,28 @@ func TestTransactionHandler_CreateTransactionServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestTransactionHandler_CreateTransactionInvalidAmount(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	req := models.CreateTransactionRequest{
+		UserID:   1,
+		Amount:   decimal.NewFromFloat(100.50),
+		Currency: "JPY",
+	}
+
+	mockService.On("CreateTransaction", req).Return((*models.Transaction)(nil), fmt.Errorf("%w: JPY allows at most 0 decimal places", services.ErrInvalidAmount))
+
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/api/transactions", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestTransactionHandler_GetTransaction(t *testing.T) {
 	router, mockService := setupTestRouter()
 
@@ -220,7 +656,7 @@ func TestTransactionHandler_GetTransactions(t *testing.T) {
 		{ID: 2, UserID: 2, Amount: decimal.NewFromFloat(200.00), Status: "success"},
 	}
 
-	mockService.On("GetTransactions", mock.AnythingOfType("models.TransactionFilters")).Return(expectedTxs, nil)
+	mockService.On("GetTransactionsWithCount", mock.AnythingOfType("models.TransactionFilters")).Return(expectedTxs, int64(len(expectedTxs)), nil)
 
 	w := httptest.NewRecorder()
 	httpReq, _ := http.NewRequest("GET", "/api/transactions", nil)
@@ -238,7 +674,7 @@ func TestTransactionHandler_GetTransactionsWithFilters(t *testing.T) {
 		{ID: 1, UserID: 1, Amount: decimal.NewFromFloat(100.50), Status: "pending"},
 	}
 
-	mockService.On("GetTransactions", mock.AnythingOfType("models.TransactionFilters")).Return(expectedTxs, nil)
+	mockService.On("GetTransactionsWithCount", mock.AnythingOfType("models.TransactionFilters")).Return(expectedTxs, int64(len(expectedTxs)), nil)
 
 	w := httptest.NewRecorder()
 	httpReq, _ := http.NewRequest("GET", "/api/transactions?user_id=1&status=pending&limit=10&offset=0", nil)
@@ -249,6 +685,32 @@ func TestTransactionHandler_GetTransactionsWithFilters(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestTransactionHandler_GetTransactionsLinkHeader(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	expectedTxs := []models.Transaction{
+		{ID: 3, UserID: 1, Amount: decimal.NewFromFloat(100.50), Status: "pending"},
+		{ID: 4, UserID: 1, Amount: decimal.NewFromFloat(200.00), Status: "pending"},
+	}
+
+	mockService.On("GetTransactionsWithCount", mock.AnythingOfType("models.TransactionFilters")).Return(expectedTxs, int64(10), nil)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("GET", "/api/transactions?user_id=1&limit=2&offset=2", nil)
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `</api/transactions?limit=2&offset=0&user_id=1>; rel="first", `+
+		`</api/transactions?limit=2&offset=0&user_id=1>; rel="prev", `+
+		`</api/transactions?limit=2&offset=4&user_id=1>; rel="next"`, w.Header().Get("Link"))
+
+	var response models.APIResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, &models.Pagination{Total: 10, Limit: 2, Offset: 2, Page: 2, HasMore: true}, response.Pagination)
+	mockService.AssertExpectations(t)
+}
+
 func TestTransactionHandler_GetTransactionsInvalidQuery(t *testing.T) {
 	router, _ := setupTestRouter()
 
@@ -264,7 +726,7 @@ func TestTransactionHandler_GetTransactionsInvalidQuery(t *testing.T) {
 func TestTransactionHandler_GetTransactionsServiceError(t *testing.T) {
 	router, mockService := setupTestRouter()
 
-	mockService.On("GetTransactions", mock.AnythingOfType("models.TransactionFilters")).Return([]models.Transaction{}, errors.New("service error"))
+	mockService.On("GetTransactionsWithCount", mock.AnythingOfType("models.TransactionFilters")).Return([]models.Transaction{}, int64(0), errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	httpReq, _ := http.NewRequest("GET", "/api/transactions", nil)
@@ -282,7 +744,7 @@ func TestTransactionHandler_UpdateTransaction(t *testing.T) {
 		Status: "success",
 	}
 
-	mockService.On("UpdateTransactionStatus", uint(1), "success").Return(nil)
+	mockService.On("UpdateTransaction", uint(1), models.UpdateTransactionRequest{Status: "success"}).Return(nil)
 
 	body, _ := json.Marshal(req)
 	w := httptest.NewRecorder()
@@ -349,7 +811,7 @@ func TestTransactionHandler_UpdateTransactionNotFound(t *testing.T) {
 		Status: "success",
 	}
 
-	mockService.On("UpdateTransactionStatus", uint(1), "success").Return(errors.New("transaction not found"))
+	mockService.On("UpdateTransaction", uint(1), models.UpdateTransactionRequest{Status: "success"}).Return(errors.New("transaction not found"))
 
 	body, _ := json.Marshal(req)
 	w := httptest.NewRecorder()
@@ -369,7 +831,7 @@ func TestTransactionHandler_UpdateTransactionServiceError(t *testing.T) {
 		Status: "success",
 	}
 
-	mockService.On("UpdateTransactionStatus", uint(1), "success").Return(errors.New("service error"))
+	mockService.On("UpdateTransaction", uint(1), models.UpdateTransactionRequest{Status: "success"}).Return(errors.New("service error"))
 
 	body, _ := json.Marshal(req)
 	w := httptest.NewRecorder()
@@ -396,18 +858,48 @@ func TestTransactionHandler_DeleteTransaction(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
-func TestTransactionHandler_DeleteTransactionInvalidID(t *testing.T) {
-	router, _ := setupTestRouter()
+func TestTransactionHandler_DeleteTransactionRejectedByHook(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("DeleteTransaction", uint(1)).Return(fmt.Errorf("%w: kept for audit", services.ErrRejectedByHook))
 
 	w := httptest.NewRecorder()
-	httpReq, _ := http.NewRequest("DELETE", "/api/transactions/invalid", nil)
+	httpReq, _ := http.NewRequest("DELETE", "/api/transactions/1", nil)
 
 	router.ServeHTTP(w, httpReq)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "kept for audit")
+	mockService.AssertExpectations(t)
 }
 
-func TestTransactionHandler_DeleteTransactionNotFound(t *testing.T) {
+func TestTransactionHandler_DeleteTransactionRefundPair(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("DeleteTransaction", uint(1)).Return(services.ErrNotDeletable)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("DELETE", "/api/transactions/1", nil)
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "refunds cannot be deleted")
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_DeleteTransactionInvalidID(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("DELETE", "/api/transactions/invalid", nil)
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTransactionHandler_DeleteTransactionNotFound(t *testing.T) {
 	router, mockService := setupTestRouter()
 
 	mockService.On("DeleteTransaction", uint(1)).Return(errors.New("transaction not found"))
@@ -461,7 +953,7 @@ func TestTransactionHandler_UpdateTransactionServiceNotFound(t *testing.T) {
 	router, _ := setupTestRouter()
 	router.PUT("/transactions/:id", handler.UpdateTransaction)
 
-	mockService.On("UpdateTransactionStatus", uint(1), "success").Return(errors.New("transaction not found"))
+	mockService.On("UpdateTransaction", uint(1), models.UpdateTransactionRequest{Status: "success"}).Return(errors.New("transaction not found"))
 
 	reqBody := `{"status": "success"}`
 	req, _ := http.NewRequest("PUT", "/transactions/1", strings.NewReader(reqBody))
@@ -480,7 +972,7 @@ func TestTransactionHandler_UpdateTransactionInvalidStatusFromService(t *testing
 	router, _ := setupTestRouter()
 	router.PUT("/transactions/:id", handler.UpdateTransaction)
 
-	mockService.On("UpdateTransactionStatus", uint(1), "success").Return(errors.New("invalid status"))
+	mockService.On("UpdateTransaction", uint(1), models.UpdateTransactionRequest{Status: "success"}).Return(errors.New("invalid status"))
 
 	reqBody := `{"status": "success"}`
 	req, _ := http.NewRequest("PUT", "/transactions/1", strings.NewReader(reqBody))
@@ -492,6 +984,25 @@ func TestTransactionHandler_UpdateTransactionInvalidStatusFromService(t *testing
 	mockService.AssertExpectations(t)
 }
 
+func TestTransactionHandler_UpdateTransactionInvalidTransition(t *testing.T) {
+	mockService := new(MockTransactionService)
+	handler := handlers.NewTransactionHandler(mockService)
+
+	router, _ := setupTestRouter()
+	router.PUT("/transactions/:id", handler.UpdateTransaction)
+
+	mockService.On("UpdateTransaction", uint(1), models.UpdateTransactionRequest{Status: "pending"}).Return(fmt.Errorf("%w: cannot change status from success to pending", services.ErrInvalidTransition))
+
+	reqBody := `{"status": "pending"}`
+	req, _ := http.NewRequest("PUT", "/transactions/1", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestTransactionHandler_UpdateTransactionInternalError(t *testing.T) {
 	mockService := new(MockTransactionService)
 	handler := handlers.NewTransactionHandler(mockService)
@@ -499,7 +1010,7 @@ func TestTransactionHandler_UpdateTransactionInternalError(t *testing.T) {
 	router, _ := setupTestRouter()
 	router.PUT("/transactions/:id", handler.UpdateTransaction)
 
-	mockService.On("UpdateTransactionStatus", uint(1), "success").Return(errors.New("database error"))
+	mockService.On("UpdateTransaction", uint(1), models.UpdateTransactionRequest{Status: "success"}).Return(errors.New("database error"))
 
 	reqBody := `{"status": "success"}`
 	req, _ := http.NewRequest("PUT", "/transactions/1", strings.NewReader(reqBody))
@@ -510,3 +1021,655 @@ func TestTransactionHandler_UpdateTransactionInternalError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	mockService.AssertExpectations(t)
 }
+
+func TestTransactionHandler_WaitForStatusChange(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	result := &models.TransactionWaitResult{
+		Transaction: &models.Transaction{ID: 1, Status: "success"},
+		Changed:     true,
+	}
+	mockService.On("WaitForStatusChange", mock.Anything, uint(1), "pending", 5*time.Second).Return(result, nil)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("GET", "/api/transactions/1/wait?timeout=5s&status=pending", nil)
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Transaction status changed")
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_WaitForStatusChangeOutlastsWriteTimeout(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	result := &models.TransactionWaitResult{Transaction: &models.Transaction{ID: 1, Status: "pending"}}
+	mockService.On("WaitForStatusChange", mock.Anything, uint(1), "", 1*time.Second).
+		After(300*time.Millisecond).Return(result, nil)
+
+	server := httptest.NewUnstartedServer(router)
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/transactions/1/wait?timeout=1s")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_WaitForStatusChangeInvalidTimeout(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	for _, timeout := range []string{"soon", "0s", "5m"} {
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("GET", "/api/transactions/1/wait?timeout="+timeout, nil)
+
+		router.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, timeout)
+	}
+	mockService.AssertNotCalled(t, "WaitForStatusChange")
+}
+
+func TestTransactionHandler_WaitForStatusChangeNotFound(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("WaitForStatusChange", mock.Anything, uint(999), "", 30*time.Second).Return(nil, errors.New("transaction not found"))
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("GET", "/api/transactions/999/wait", nil)
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTransactionHandler_LookupTransactions(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	result := &models.TransactionLookupResult{
+		Transactions: []models.Transaction{{ID: 2, Status: "success"}},
+		Missing:      []uint{9},
+	}
+	mockService.On("LookupTransactions", []uint{2, 9}).Return(result, nil)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/api/transactions/lookup", bytes.NewBufferString(`{"ids":[2,9]}`))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"missing":[9]`)
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_LookupTransactionsValidation(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	ids := make([]string, models.MaxLookupIDs+1)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i + 1)
+	}
+
+	for _, body := range []string{`{"ids":[]}`, `{"ids":[0]}`, `{"ids":[` + strings.Join(ids, ",") + `]}`, `not json`} {
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("POST", "/api/transactions/lookup", bytes.NewBufferString(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	mockService.AssertNotCalled(t, "LookupTransactions")
+}
+
+func TestTransactionHandler_GetTransactionStatus(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	updatedAt := time.Date(2025, 6, 28, 10, 0, 0, 0, time.UTC)
+	mockService.On("GetTransactionStatus", uint(1)).Return(&models.TransactionStatus{Status: "success", UpdatedAt: updatedAt}, nil)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("GET", "/api/transactions/1/status", nil)
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"success":true,"data":{"status":"success","updated_at":"2025-06-28T10:00:00Z"},"message":"Transaction status retrieved successfully"}`, w.Body.String())
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, "private, max-age=1", w.Header().Get("Cache-Control"))
+
+	// Revalidating with the same ETag returns an empty 304
+	w = httptest.NewRecorder()
+	httpReq, _ = http.NewRequest("GET", "/api/transactions/1/status", nil)
+	httpReq.Header.Set("If-None-Match", etag)
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestTransactionHandler_GetTransactionStatusNotFound(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("GetTransactionStatus", uint(999)).Return(nil, errors.New("transaction not found"))
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("GET", "/api/transactions/999/status", nil)
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTransactionHandler_GetTransactionsDateRange(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 30, 23, 59, 59, 0, time.UTC)
+	mockService.On("GetTransactionsWithCount", mock.MatchedBy(func(filters models.TransactionFilters) bool {
+		return filters.From != nil && filters.From.Equal(from) && filters.To != nil && filters.To.Equal(to)
+	})).Return([]models.Transaction{}, int64(0), nil)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("GET", "/api/transactions?from=2025-06-01T00:00:00Z&to=2025-06-30T23:59:59Z", nil)
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_GetTransactionsInvalidDateRange(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	for _, query := range []string{
+		"from=2025-06-30T00:00:00Z&to=2025-06-01T00:00:00Z",
+		"from=2025-06-01",
+		"to=yesterday",
+	} {
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("GET", "/api/transactions?"+query, nil)
+
+		router.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockService.AssertNotCalled(t, "GetTransactionsWithCount")
+}
+
+func TestTransactionHandler_GetTransactionsAmountRange(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("GetTransactionsWithCount", mock.MatchedBy(func(filters models.TransactionFilters) bool {
+		return filters.MinAmount != nil && filters.MinAmount.Equal(decimal.NewFromInt(100)) &&
+			filters.MaxAmount != nil && filters.MaxAmount.Equal(decimal.RequireFromString("500.50"))
+	})).Return([]models.Transaction{}, int64(0), nil)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("GET", "/api/transactions?min_amount=100&max_amount=500.50", nil)
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_GetTransactionsByTag(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("GetTransactionsWithCount", mock.MatchedBy(func(filters models.TransactionFilters) bool {
+		return filters.Tag == "refund"
+	})).Return([]models.Transaction{}, int64(0), nil)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("GET", "/api/transactions?tag=Refund", nil)
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_GetTransactionsByMetadata(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("GetTransactionsWithCount", mock.MatchedBy(func(filters models.TransactionFilters) bool {
+		return len(filters.Metadata) == 2 && filters.Metadata["invoice"] == "INV-1" && filters.Metadata["region"] == "eu"
+	})).Return([]models.Transaction{}, int64(0), nil)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("GET", "/api/transactions?metadata.invoice=INV-1&metadata.region=eu&status=pending", nil)
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_GetTransactionsInvalidAmountRange(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	for _, query := range []string{
+		"min_amount=500&max_amount=100",
+		"min_amount=abc",
+		"max_amount=1e",
+	} {
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("GET", "/api/transactions?"+query, nil)
+
+		router.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockService.AssertNotCalled(t, "GetTransactionsWithCount")
+}
+
+func TestTransactionHandler_GetTransactionsSorted(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("GetTransactionsWithCount", mock.MatchedBy(func(filters models.TransactionFilters) bool {
+		return filters.SortBy == "amount" && filters.Order == "asc"
+	})).Return([]models.Transaction{}, int64(0), nil)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("GET", "/api/transactions?sort_by=amount&order=asc", nil)
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_PatchTransaction(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	status := "success"
+	description := "June invoice"
+	expected := models.PatchTransactionRequest{
+		Status:      &status,
+		Description: &description,
+		Metadata:    models.TransactionMetadata{"invoice": "INV-1"},
+	}
+	mockService.On("PatchTransaction", uint(1), expected).Return(&models.Transaction{ID: 1, Status: status, Description: description}, nil)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("PATCH", "/api/transactions/1", bytes.NewBufferString(`{"status":"success","description":"June invoice","metadata":{"invoice":"INV-1"}}`))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"description":"June invoice"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_PatchTransactionRejectedFields(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	tests := []struct {
+		body    string
+		message string
+	}{
+		{`{"amount":"10"}`, "Field amount cannot be changed"},
+		{`{"status":"success","user_id":2}`, "Field user_id cannot be changed"},
+		{`{"colour":"red"}`, "Invalid request body"},
+		{`{"status":"done"}`, "Validation failed"},
+		{`{"metadata":{"":"x"}}`, "Validation failed"},
+		{`[]`, "Invalid request body"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("PATCH", "/api/transactions/1", bytes.NewBufferString(tt.body))
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, tt.body)
+		assert.Contains(t, w.Body.String(), tt.message, tt.body)
+	}
+	mockService.AssertNotCalled(t, "PatchTransaction")
+}
+
+func TestTransactionHandler_PatchTransactionErrors(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("PatchTransaction", uint(1), models.PatchTransactionRequest{}).Return(nil, errors.New("no fields to update"))
+	mockService.On("PatchTransaction", uint(999), mock.Anything).Return(nil, errors.New("transaction not found"))
+	mockService.On("PatchTransaction", uint(2), mock.Anything).Return(nil, fmt.Errorf("%w: cannot change status from failed to success", services.ErrInvalidTransition))
+	mockService.On("PatchTransaction", uint(3), mock.Anything).Return(nil, fmt.Errorf("%w: transaction changed while it was being updated", services.ErrVersionConflict))
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("PATCH", "/api/transactions/1", bytes.NewBufferString(`{}`))
+	router.ServeHTTP(w, httpReq)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	httpReq, _ = http.NewRequest("PATCH", "/api/transactions/999", bytes.NewBufferString(`{"description":"x"}`))
+	router.ServeHTTP(w, httpReq)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	httpReq, _ = http.NewRequest("PATCH", "/api/transactions/2", bytes.NewBufferString(`{"status":"success"}`))
+	router.ServeHTTP(w, httpReq)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = httptest.NewRecorder()
+	httpReq, _ = http.NewRequest("PATCH", "/api/transactions/3", bytes.NewBufferString(`{"metadata":{"invoice":"INV-1"}}`))
+	router.ServeHTTP(w, httpReq)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"version_conflict"`)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_CreateTransactions(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("CreateTransactions", []models.CreateTransactionRequest{
+		{UserID: 1, Amount: decimal.NewFromInt(100)},
+		{UserID: 3, Amount: decimal.NewFromInt(5000)},
+	}).Return(&models.BulkCreateResult{
+		Created: 1,
+		Failed:  1,
+		Results: []models.BulkCreateItemResult{
+			{Index: 0, Transaction: &models.Transaction{ID: 10, UserID: 1, Amount: decimal.NewFromInt(100), Status: "pending"}},
+			{Index: 1, Error: "monthly budget exceeded: 0 of 100 used"},
+		},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/api/transactions/bulk", bytes.NewBufferString(`[
+		{"user_id": 1, "amount": "100"},
+		{"user_id": 0, "amount": "50"},
+		{"user_id": 3, "amount": "5000"}
+	]`))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response struct {
+		Data models.BulkCreateResult `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Data.Created)
+	assert.Equal(t, 2, response.Data.Failed)
+	assert.Equal(t, uint(10), response.Data.Results[0].Transaction.ID)
+	assert.Contains(t, response.Data.Results[1].Error, "Validation failed")
+	assert.Equal(t, 2, response.Data.Results[2].Index)
+	assert.Contains(t, response.Data.Results[2].Error, "monthly budget exceeded")
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_CreateTransactionsReplay(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	mockService.On("CreateTransactions", []models.CreateTransactionRequest{
+		{UserID: 1, Amount: decimal.NewFromInt(100), ReferenceID: "ORD-1"},
+	}).Return(&models.BulkCreateResult{
+		Deduplicated: 1,
+		Results: []models.BulkCreateItemResult{
+			{Index: 0, Status: models.BulkItemDeduplicated, Transaction: &models.Transaction{ID: 10, UserID: 1, Amount: decimal.NewFromInt(100), Status: "success"}},
+		},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/api/transactions/bulk", bytes.NewBufferString(`[
+		{"user_id": 1, "amount": "100", "reference_id": "ORD-1"},
+		{"user_id": 0, "amount": "50", "reference_id": "ORD-2"}
+	]`))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, httpReq)
+
+	// Nothing new was stored
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data models.BulkCreateResult `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 0, response.Data.Created)
+	assert.Equal(t, 1, response.Data.Deduplicated)
+	assert.Equal(t, 1, response.Data.Failed)
+	assert.Equal(t, models.BulkItemDeduplicated, response.Data.Results[0].Status)
+	assert.Equal(t, uint(10), response.Data.Results[0].Transaction.ID)
+	assert.Equal(t, models.BulkItemFailed, response.Data.Results[1].Status)
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_CreateTransactionsAllInvalid(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/api/transactions/bulk", bytes.NewBufferString(`[{"user_id": 0, "amount": "50"}]`))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "No transactions created")
+	mockService.AssertNotCalled(t, "CreateTransactions")
+}
+
+func TestTransactionHandler_CreateTransactionsInvalidBatch(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	tooMany := "[" + strings.TrimSuffix(strings.Repeat(`{"user_id":1,"amount":"1"},`, models.MaxBulkTransactions+1), ",") + "]"
+	for _, body := range []string{`[]`, `{"user_id": 1}`, tooMany} {
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("POST", "/api/transactions/bulk", bytes.NewBufferString(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+	mockService.AssertNotCalled(t, "CreateTransactions")
+}
+
+func TestTransactionHandler_UpdateTransactionWithMetadataPatch(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	invoice := "INV-2"
+	version := uint(3)
+	mockService.On("UpdateTransaction", uint(1), models.UpdateTransactionRequest{
+		Status:          "failed",
+		Reason:          "card declined",
+		Metadata:        models.MetadataPatch{"invoice": &invoice, "region": nil},
+		ExpectedVersion: &version,
+	}).Return(nil)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("PUT", "/api/transactions/1", bytes.NewBufferString(
+		`{"status":"failed","reason":"card declined","metadata":{"invoice":"INV-2","region":null},"expected_version":3}`))
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_UpdateTransactionValidation(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	tests := []struct {
+		body    string
+		message string
+	}{
+		{`{}`, "Validation failed"},
+		{`{"reason":"card declined","metadata":{"invoice":"INV-2"},"expected_version":3}`, "Validation failed"},
+		{`{"metadata":{"invoice":"INV-2"}}`, "Validation failed"},
+		{`{"status":"success","expected_version":0}`, "Validation failed"},
+		{`{"status":"success","colour":"red"}`, "Invalid request body"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("PUT", "/api/transactions/1", bytes.NewBufferString(tt.body))
+		router.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, tt.body)
+		assert.Contains(t, w.Body.String(), tt.message, tt.body)
+	}
+	mockService.AssertNotCalled(t, "UpdateTransaction")
+}
+
+func TestTransactionHandler_UpdateTransactionErrorCodes(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{fmt.Errorf("%w: expected version 2, transaction is at version 3", services.ErrVersionConflict), http.StatusConflict, "version_conflict"},
+		{fmt.Errorf("%w: cannot change status from success to pending", services.ErrInvalidTransition), http.StatusConflict, "invalid_transition"},
+		{services.ErrReasonWithoutChange, http.StatusBadRequest, "reason_without_status_change"},
+		{fmt.Errorf("%w: at most 20 are allowed", services.ErrTooManyMetadataKeys), http.StatusBadRequest, "too_many_metadata_keys"},
+	}
+
+	for _, tt := range tests {
+		router, mockService := setupTestRouter()
+		mockService.On("UpdateTransaction", uint(1), mock.Anything).Return(tt.err)
+
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("PUT", "/api/transactions/1", bytes.NewBufferString(`{"status":"success"}`))
+		router.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, tt.status, w.Code, tt.code)
+		assert.Contains(t, w.Body.String(), `"code":"`+tt.code+`"`)
+		assert.Contains(t, w.Body.String(), tt.err.Error())
+	}
+}
+
+func TestTransactionHandler_ExportTransactions(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	reference := "=HYPERLINK(\"x\")"
+	parentID := uint(1)
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	transactions := []models.Transaction{
+		{ID: 1, UserID: 1, Amount: decimal.RequireFromString("100.50"), Currency: "USD", Type: "payment", Direction: "debit", Status: "refunded", Version: 2, CreatedAt: created, UpdatedAt: created},
+		{ID: 2, UserID: 1, Amount: decimal.RequireFromString("-100.50"), Currency: "USD", Type: "refund", Direction: "credit", Status: "success",
+			ReferenceID: &reference, Description: "Refund, partial", Metadata: models.TransactionMetadata{"channel": "web"}, ParentID: &parentID, Version: 1, CreatedAt: created, UpdatedAt: created},
+	}
+	live := false
+	mockService.On("ExportTransactions", models.TransactionFilters{UserID: 1, SortBy: "amount", IsTest: &live}).Return(transactions, nil)
+
+	req, _ := http.NewRequest("GET", "/api/transactions/export?format=csv&user_id=1&sort_by=amount&limit=1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="transactions.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, `id,uuid,reference_id,user_id,amount,currency,type,direction,status,description,metadata,parent_id,is_test,version,created_at,updated_at
+1,,,1,100.5,USD,payment,debit,refunded,,,,false,2,2024-01-02T03:04:05Z,2024-01-02T03:04:05Z
+2,,"'=HYPERLINK(""x"")",1,-100.5,USD,refund,credit,success,"Refund, partial","{""channel"":""web""}",1,false,1,2024-01-02T03:04:05Z,2024-01-02T03:04:05Z
+`, w.Body.String())
+}
+
+func TestTransactionHandler_ExportTransactionsEmpty(t *testing.T) {
+	router, mockService := setupTestRouter()
+	mockService.On("ExportTransactions", models.TransactionFilters{Status: "failed"}).Return([]models.Transaction{}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/transactions/export?status=failed&include_test=true", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "id,uuid,reference_id,user_id,amount,currency,type,direction,status,description,metadata,parent_id,is_test,version,created_at,updated_at\n", w.Body.String())
+}
+
+func TestTransactionHandler_ExportTransactionsInvalid(t *testing.T) {
+	router, mockService := setupTestRouter()
+	live := false
+	mockService.On("ExportTransactions", models.TransactionFilters{Status: "lost", IsTest: &live}).Return([]models.Transaction{}, errors.New("invalid status filter"))
+
+	tests := []struct {
+		query   string
+		message string
+	}{
+		{"format=xlsx", "Invalid format, must be csv"},
+		{"from=2024-02-01T00:00:00Z&to=2024-01-01T00:00:00Z", "Invalid date range, from must not be after to"},
+		{"status=lost", "invalid status filter"},
+		{"include_test=maybe", "Invalid include_test, must be true or false"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/api/transactions/export?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+			var response models.APIResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Contains(t, response.Message+response.Error, tt.message)
+		})
+	}
+}
+
+func TestTransactionHandler_GetTransactionsStream(t *testing.T) {
+	router, mockService := setupTestRouter()
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	transactions := []models.Transaction{
+		{ID: 1, UserID: 1, Amount: decimal.RequireFromString("100.50"), Currency: "USD", Status: "success", CreatedAt: created, UpdatedAt: created},
+		{ID: 2, UserID: 1, Amount: decimal.RequireFromString("5"), Currency: "USD", Status: "pending", CreatedAt: created, UpdatedAt: created},
+	}
+	mockService.On("ExportTransactions", models.TransactionFilters{UserID: 1}).Return(transactions, nil)
+
+	for name, req := range map[string]*http.Request{
+		"accept": httptest.NewRequest("GET", "/api/transactions?user_id=1&limit=1", nil),
+		"query":  httptest.NewRequest("GET", "/api/transactions?user_id=1&offset=5&stream=true", nil),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if name == "accept" {
+				req.Header.Set("Accept", "application/x-ndjson")
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+			lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+			require.Len(t, lines, 2)
+			var first models.Transaction
+			require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+			assert.Equal(t, uint(1), first.ID)
+			assert.True(t, first.Amount.Equal(decimal.RequireFromString("100.50")))
+			assert.Contains(t, lines[1], `"status":"pending"`)
+		})
+	}
+	mockService.AssertNotCalled(t, "GetTransactionsWithCount", mock.Anything)
+}
+
+func TestTransactionHandler_GetTransactionsStreamErrors(t *testing.T) {
+	router, mockService := setupTestRouter()
+	mockService.On("ExportTransactions", models.TransactionFilters{Status: "lost"}).Return([]models.Transaction{}, errors.New("invalid status filter"))
+	mockService.On("ExportTransactions", models.TransactionFilters{Status: "failed"}).Return([]models.Transaction{}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/transactions?stream=maybe", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid stream, must be true or false")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/transactions?stream=true&status=lost", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+	// An empty result is an empty stream
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/transactions?stream=true&status=failed", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Body.String())
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"interview/internal/models"
+	"interview/internal/services"
+	"interview/internal/validation"
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// UserHandler handles user HTTP requests
+type UserHandler struct {
+	service   services.UserService
+	validator *validator.Validate
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(service services.UserService) *UserHandler {
+	return &UserHandler{
+		service:   service,
+		validator: validation.New(),
+	}
+}
+
+// CreateUser handles POST /api/users
+func (h *UserHandler) CreateUser(c *gin.Context) {
+	var req models.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.BadRequestResponse(c, "Validation failed: "+err.Error())
+		return
+	}
+
+	user, err := h.service.CreateUser(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, services.ErrDuplicateEmail) {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.CreatedResponse(c, user, "User created successfully")
+}
+
+// GetUsers handles GET /api/users
+func (h *UserHandler) GetUsers(c *gin.Context) {
+	var filters models.UserFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		utils.BadRequestResponse(c, "Invalid query parameters")
+		return
+	}
+
+	users, total, err := h.service.GetUsersWithCount(c.Request.Context(), filters)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	limit, offset := filters.Page()
+	pagination := models.NewPagination(total, limit, offset, len(users))
+	utils.SetPaginationLinks(c, limit, offset, pagination.HasMore)
+	utils.PaginatedResponse(c, users, pagination, "Users retrieved successfully")
+}
+
+// GetUser handles GET /api/users/:id
+func (h *UserHandler) GetUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		utils.BadRequestResponse(c, "Invalid user ID")
+		return
+	}
+
+	user, err := h.service.GetUser(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			utils.NotFoundResponse(c, "User not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, user, "User retrieved successfully")
+}
+
+// DescribeUsers handles OPTIONS /api/users
+func (h *UserHandler) DescribeUsers(c *gin.Context) {
+	describeResource(c, models.ResourceDescription{
+		Resource:     "users",
+		Methods:      []string{"GET", "POST"},
+		ContentTypes: jsonContentTypes,
+		Filters:      []string{"limit", "offset"},
+		Limits:       map[string]int{"max_page_size": models.MaxPageSize},
+	})
+}
+
+// DescribeUser handles OPTIONS /api/users/:id
+func (h *UserHandler) DescribeUser(c *gin.Context) {
+	describeResource(c, models.ResourceDescription{
+		Resource:     "user",
+		Methods:      []string{"GET"},
+		ContentTypes: jsonContentTypes,
+	})
+}
@@ -0,0 +1,148 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"interview/internal/handlers"
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUserService is a mock implementation of UserService
+type MockUserService struct {
+	mock.Mock
+}
+
+func (m *MockUserService) CreateUser(ctx context.Context, req models.CreateUserRequest) (*models.User, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserService) GetUser(ctx context.Context, id uint) (*models.User, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserService) GetUsersWithCount(ctx context.Context, filters models.UserFilters) ([]models.User, int64, error) {
+	args := m.Called(filters)
+	return args.Get(0).([]models.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserService) CheckUser(ctx context.Context, id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func setupUserRouter() (*gin.Engine, *MockUserService) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockUserService)
+	handler := handlers.NewUserHandler(mockService)
+
+	router := gin.New()
+	router.POST("/api/users", handler.CreateUser)
+	router.GET("/api/users", handler.GetUsers)
+	router.GET("/api/users/:id", handler.GetUser)
+	return router, mockService
+}
+
+func TestUserHandler_CreateUser(t *testing.T) {
+	router, mockService := setupUserRouter()
+
+	req := models.CreateUserRequest{Name: "Ada Lovelace", Email: "ada@example.com"}
+	mockService.On("CreateUser", req).Return(&models.User{ID: 1, Name: req.Name, Email: req.Email}, nil).Once()
+	mockService.On("CreateUser", req).Return(nil, fmt.Errorf("%w: ada@example.com", services.ErrDuplicateEmail)).Once()
+
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/api/users", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, httpReq)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = httptest.NewRecorder()
+	httpReq, _ = http.NewRequest("POST", "/api/users", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, httpReq)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_CreateUserValidation(t *testing.T) {
+	router, mockService := setupUserRouter()
+
+	for _, body := range []string{`{"name": "Ada"}`, `{"name": "Ada", "email": "not-an-email"}`, `{"email": "ada@example.com"}`, `{`} {
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("POST", "/api/users", bytes.NewBufferString(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, httpReq)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+
+	mockService.AssertNotCalled(t, "CreateUser")
+}
+
+func TestUserHandler_GetUser(t *testing.T) {
+	router, mockService := setupUserRouter()
+
+	mockService.On("GetUser", uint(1)).Return(&models.User{ID: 1, Name: "Ada"}, nil)
+	mockService.On("GetUser", uint(2)).Return(nil, services.ErrUserNotFound)
+	mockService.On("GetUser", uint(3)).Return(nil, errors.New("failed to get user: db down"))
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/api/users/1", http.StatusOK},
+		{"/api/users/2", http.StatusNotFound},
+		{"/api/users/3", http.StatusInternalServerError},
+		{"/api/users/0", http.StatusBadRequest},
+		{"/api/users/abc", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", tt.path, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tt.code, w.Code, tt.path)
+	}
+}
+
+func TestUserHandler_GetUsers(t *testing.T) {
+	router, mockService := setupUserRouter()
+
+	users := []models.User{{ID: 3, Name: "Edsger"}}
+	mockService.On("GetUsersWithCount", models.UserFilters{Limit: 1, Offset: 2}).Return(users, int64(5), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/users?limit=1&offset=2", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Link"), `rel="next"`)
+
+	var response struct {
+		Data       []models.User     `json:"data"`
+		Pagination models.Pagination `json:"pagination"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, users[0].ID, response.Data[0].ID)
+	assert.Equal(t, int64(5), response.Pagination.Total)
+	assert.True(t, response.Pagination.HasMore)
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheControlMiddleware lets clients cache successful GET responses for
+// maxAge. Other responses are left without a Cache-Control header.
+func CacheControlMiddleware(maxAge time.Duration) gin.HandlerFunc {
+	value := "private, max-age=" + strconv.Itoa(int(maxAge.Seconds()))
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet {
+			c.Writer = &cacheControlWriter{ResponseWriter: c.Writer, value: value}
+		}
+		c.Next()
+	}
+}
+
+// cacheControlWriter adds a Cache-Control header when a 200 response is
+// written; the status is only known then
+type cacheControlWriter struct {
+	gin.ResponseWriter
+	value string
+}
+
+func (w *cacheControlWriter) setHeader() {
+	if !w.Written() && w.Status() == http.StatusOK && w.Header().Get("Cache-Control") == "" {
+		w.Header().Set("Cache-Control", w.value)
+	}
+}
+
+func (w *cacheControlWriter) WriteHeaderNow() {
+	w.setHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *cacheControlWriter) Write(data []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cacheControlWriter) WriteString(s string) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.WriteString(s)
+}
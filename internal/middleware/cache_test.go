@@ -0,0 +1,52 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"interview/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheControlMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.CacheControlMiddleware(time.Minute))
+
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "test"})
+	})
+	router.GET("/missing", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	})
+	router.GET("/no-store", func(c *gin.Context) {
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, gin.H{"message": "test"})
+	})
+	router.POST("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "test"})
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{"GET", "/test", "private, max-age=60"},
+		{"GET", "/missing", ""},
+		{"GET", "/no-store", "no-store"},
+		{"POST", "/test", ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(tt.method, tt.path, nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.want, w.Header().Get("Cache-Control"), tt.method+" "+tt.path)
+	}
+}
@@ -11,9 +11,9 @@ import (
 func CORSMiddleware() gin.HandlerFunc {
 	return cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "API-Version", "X-Test-Data", "X-Request-ID"},
+		ExposeHeaders:    []string{"Content-Length", "API-Version", "X-Request-ID", "Deprecation", "Sunset", "Link"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	})
@@ -48,4 +48,5 @@ func TestCORSMiddlewareOptionsRequest(t *testing.T) {
 
 	assert.Equal(t, http.StatusNoContent, w.Code)
 	assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), "POST")
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), "PATCH")
 }
@@ -3,14 +3,23 @@ package middleware
 import (
 	"time"
 
+	"interview/internal/requestid"
+
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
 // LoggerMiddleware provides request logging
 func LoggerMiddleware() gin.HandlerFunc {
+	return LevelLoggerMiddleware(logrus.InfoLevel)
+}
+
+// LevelLoggerMiddleware provides request logging at level, so noisy routes
+// such as health checks can log below the configured log level. Records
+// carry the request ID RequestIDMiddleware assigned.
+func LevelLoggerMiddleware(level logrus.Level) gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		logrus.WithFields(logrus.Fields{
+		fields := logrus.Fields{
 			"status_code": param.StatusCode,
 			"latency":     param.Latency,
 			"client_ip":   param.ClientIP,
@@ -20,7 +29,11 @@ func LoggerMiddleware() gin.HandlerFunc {
 			"body_size":   param.BodySize,
 			"user_agent":  param.Request.UserAgent(),
 			"timestamp":   param.TimeStamp.Format(time.RFC3339),
-		}).Info("HTTP Request")
+		}
+		if id, ok := param.Keys[requestid.Key].(string); ok {
+			fields[requestid.Key] = id
+		}
+		logrus.WithFields(fields).Log(level, "HTTP Request")
 
 		return ""
 	})
@@ -3,6 +3,7 @@ package middleware
 import (
 	"net/http"
 
+	"interview/internal/requestid"
 	"interview/pkg/utils"
 
 	"github.com/gin-gonic/gin"
@@ -12,7 +13,7 @@ import (
 // RecoveryMiddleware provides panic recovery
 func RecoveryMiddleware() gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
-		logrus.WithFields(logrus.Fields{
+		requestid.Logger(c.Request.Context()).WithFields(logrus.Fields{
 			"error":  recovered,
 			"path":   c.Request.URL.Path,
 			"method": c.Request.Method,
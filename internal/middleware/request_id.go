@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"interview/internal/requestid"
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDMiddleware gives every request an ID: the client's X-Request-ID
+// when it is valid and a new UUID otherwise. The ID is returned in the
+// X-Request-ID response header and carried in the request context, so logs
+// and queued work can be correlated with the request.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = utils.NewUUID()
+		}
+
+		c.Set(requestid.Key, id)
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+		c.Header(requestid.Header, id)
+		c.Next()
+	}
+}
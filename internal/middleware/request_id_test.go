@@ -0,0 +1,85 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"interview/internal/middleware"
+	"interview/internal/models"
+	"interview/internal/requestid"
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestIDMiddleware())
+
+	var fromContext string
+	router.GET("/test", func(c *gin.Context) {
+		fromContext = requestid.FromContext(c.Request.Context())
+		utils.SuccessResponse(c, nil, "ok")
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"generated", "", false},
+		{"propagated", "client-req.42:a_b", true},
+		{"too long", strings.Repeat("a", requestid.MaxLength+1), false},
+		{"unsafe", "bad id\n", false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/test", nil)
+		if tt.header != "" {
+			req.Header.Set(requestid.Header, tt.header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		id := w.Header().Get(requestid.Header)
+		if tt.keep {
+			assert.Equal(t, tt.header, id, tt.name)
+		} else {
+			assert.True(t, utils.IsUUID(id), tt.name)
+		}
+		assert.Equal(t, id, fromContext, tt.name)
+
+		var response models.APIResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, id, response.RequestID, tt.name)
+	}
+}
+
+func TestLoggerMiddleware_RequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	router := gin.New()
+	router.Use(middleware.RequestIDMiddleware(), middleware.LoggerMiddleware())
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set(requestid.Header, "req-1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.InfoLevel, entry.Level)
+	assert.Equal(t, "req-1", entry.Data["request_id"])
+}
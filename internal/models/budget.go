@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Budget represents a monthly volume cap for a user account. The cap is in
+// one currency and only the user's transactions in it count towards it.
+type Budget struct {
+	UserID       uint            `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	MonthlyCap   decimal.Decimal `json:"monthly_cap" gorm:"not null;type:decimal(15,2)"`
+	Currency     string          `json:"currency" gorm:"size:3;not null;default:'USD'"`
+	BlockOverage bool            `json:"block_overage" gorm:"not null;default:false"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// SetBudgetRequest represents request body for setting a budget. The cap is
+// checked against the currency's scale and the amount column size.
+type SetBudgetRequest struct {
+	MonthlyCap   decimal.Decimal `json:"monthly_cap" validate:"required,decimal_positive"`
+	Currency     string          `json:"currency" validate:"omitempty,len=3,alpha"`
+	BlockOverage bool            `json:"block_overage"`
+}
+
+// BudgetStatus represents a budget together with the current month's consumption
+type BudgetStatus struct {
+	Budget
+	Period       string          `json:"period"`
+	Consumed     decimal.Decimal `json:"consumed"`
+	Remaining    decimal.Decimal `json:"remaining"`
+	UsagePercent decimal.Decimal `json:"usage_percent"`
+}
@@ -0,0 +1,90 @@
+package models
+
+import "time"
+
+// Case represents an investigation that operators pin transactions to.
+// Cases are open until they are closed with an outcome.
+type Case struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	Name        string `json:"name" gorm:"size:100;not null"`
+	Description string `json:"description" gorm:"size:1000;not null;default:''"`
+	Status      string `json:"status" gorm:"size:6;not null;default:'open';index"`
+	Outcome     string `json:"outcome,omitempty" gorm:"size:12;not null;default:''"`
+	Resolution  string `json:"resolution,omitempty" gorm:"size:1000;not null;default:''"`
+	// TransactionCount is the number of transactions pinned to the case,
+	// counted when the case is read
+	TransactionCount int64      `json:"transaction_count" gorm:"->;-:migration"`
+	ClosedAt         *time.Time `json:"closed_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// Case statuses
+const (
+	CaseStatusOpen   = "open"
+	CaseStatusClosed = "closed"
+)
+
+// Case outcomes: the suspicion was confirmed, dismissed, or could not be
+// settled either way
+const (
+	CaseOutcomeConfirmed    = "confirmed"
+	CaseOutcomeDismissed    = "dismissed"
+	CaseOutcomeInconclusive = "inconclusive"
+)
+
+// CasePin pins a transaction to a case. Pins are removed with their case or
+// transaction.
+type CasePin struct {
+	CaseID        uint         `json:"-" gorm:"primaryKey;autoIncrement:false"`
+	TransactionID uint         `json:"-" gorm:"primaryKey;autoIncrement:false;index"`
+	Note          string       `json:"note" gorm:"size:255;not null;default:''"`
+	Transaction   *Transaction `json:"transaction" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Case          *Case        `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	CreatedAt     time.Time    `json:"pinned_at"`
+}
+
+// MaxPinsPerRequest is the most transactions a single pin request may hold; keep in sync with PinTransactionsRequest
+const MaxPinsPerRequest = 100
+
+// CreateCaseRequest represents request body for opening a case
+type CreateCaseRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=1000"`
+}
+
+// PinTransactionsRequest represents request body for pinning transactions to
+// a case; the note is stored with each pin
+type PinTransactionsRequest struct {
+	TransactionIDs []uint `json:"transaction_ids" validate:"required,min=1,max=100,unique,dive,min=1"`
+	Note           string `json:"note" validate:"max=255"`
+}
+
+// CloseCaseRequest represents request body for closing a case
+type CloseCaseRequest struct {
+	Outcome    string `json:"outcome" validate:"required,oneof=confirmed dismissed inconclusive"`
+	Resolution string `json:"resolution" validate:"max=1000"`
+}
+
+// CaseFilters represents filters and pagination for case lists
+type CaseFilters struct {
+	Status string `form:"status" validate:"omitempty,oneof=open closed"`
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
+}
+
+// Page returns the effective limit and offset of the filters
+func (f CaseFilters) Page() (limit, offset int) {
+	return page(f.Limit, f.Offset)
+}
+
+// CasePinFilters represents the pagination of a case's pinned transactions
+type CasePinFilters struct {
+	Limit  int `form:"limit"`
+	Offset int `form:"offset"`
+}
+
+// Page returns the effective limit and offset of the filters
+func (f CasePinFilters) Page() (limit, offset int) {
+	return page(f.Limit, f.Offset)
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Correction fixes a data-entry error in a transaction's user or amount. One
+// operator requests it and a second one applies it by approving it; both are
+// identified by the proxy in front of the admin listener. Corrections are
+// kept, applied or not, as the audit record of the change.
+type Correction struct {
+	ID            uint `json:"id" gorm:"primaryKey"`
+	TransactionID uint `json:"transaction_id" gorm:"not null;index"`
+	// TransactionVersion is the version of the transaction the correction
+	// was requested for; it is only applied while the transaction is at it
+	TransactionVersion uint   `json:"transaction_version" gorm:"not null"`
+	Status             string `json:"status" gorm:"size:8;not null;default:'pending';index"`
+	Reason             string `json:"reason" gorm:"size:1000;not null"`
+	RequestedBy        string `json:"requested_by" gorm:"size:100;not null"`
+	ApprovedBy         string `json:"approved_by,omitempty" gorm:"size:100;not null;default:''"`
+	OldUserID          uint   `json:"old_user_id" gorm:"not null"`
+	NewUserID          uint   `json:"new_user_id" gorm:"not null"`
+	// Amounts are stored as text, so the record keeps them exactly whatever
+	// the scale of the transactions' amount column
+	OldAmount decimal.Decimal `json:"old_amount" gorm:"type:varchar(40);not null"`
+	NewAmount decimal.Decimal `json:"new_amount" gorm:"type:varchar(40);not null"`
+	ExpiresAt time.Time       `json:"expires_at" gorm:"not null"`
+	AppliedAt *time.Time      `json:"applied_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Correction statuses
+const (
+	CorrectionStatusPending = "pending"
+	CorrectionStatusApplied = "applied"
+)
+
+// CreateCorrectionRequest represents request body for requesting a
+// correction; user_id, amount or both must be set
+type CreateCorrectionRequest struct {
+	UserID *uint            `json:"user_id" validate:"omitempty,min=1"`
+	Amount *decimal.Decimal `json:"amount" validate:"omitempty,decimal_nonzero"`
+	Reason string           `json:"reason" validate:"required,min=10,max=1000"`
+}
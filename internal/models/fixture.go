@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// FixtureVersion is the current fixture file format version
+const FixtureVersion = 1
+
+// Fixture represents a portable, sanitized snapshot of transactions and
+// their related records
+type Fixture struct {
+	Version      int                `json:"version"`
+	ExportedAt   time.Time          `json:"exported_at"`
+	Filters      TransactionFilters `json:"filters"`
+	Transactions []Transaction      `json:"transactions"`
+	Budgets      []Budget           `json:"budgets"`
+}
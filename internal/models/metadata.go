@@ -0,0 +1,63 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// MetadataFilterPrefix prefixes the query parameters that filter transaction lists by metadata
+const MetadataFilterPrefix = "metadata."
+
+// TransactionMetadata holds free-form string attributes of a transaction,
+// stored as a JSON object
+type TransactionMetadata map[string]string
+
+// Value implements driver.Valuer; empty metadata is stored as NULL
+func (m TransactionMetadata) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (m *TransactionMetadata) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("unsupported metadata value")
+	}
+	return json.Unmarshal(data, m)
+}
+
+// MetadataPatch changes transaction metadata: keys with a value are set, keys
+// set to null are removed and keys left out are kept
+type MetadataPatch map[string]*string
+
+// Apply returns metadata with the patch applied, leaving metadata unchanged
+func (p MetadataPatch) Apply(metadata TransactionMetadata) TransactionMetadata {
+	patched := make(TransactionMetadata, len(metadata)+len(p))
+	for key, value := range metadata {
+		patched[key] = value
+	}
+	for key, value := range p {
+		if value == nil {
+			delete(patched, key)
+		} else {
+			patched[key] = *value
+		}
+	}
+	return patched
+}
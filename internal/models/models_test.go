@@ -1,7 +1,9 @@
 package models_test
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"interview/internal/models"
 
@@ -127,3 +129,167 @@ func TestStatusCounts(t *testing.T) {
 		t.Errorf("Expected total count to be 17, got %d", total)
 	}
 }
+
+func TestNewPagination(t *testing.T) {
+	pagination := models.NewPagination(45, 20, 40, 5)
+
+	if pagination.Page != 3 {
+		t.Errorf("Expected Page to be 3, got %d", pagination.Page)
+	}
+	if pagination.HasMore {
+		t.Error("Expected HasMore to be false on the last page")
+	}
+
+	pagination = models.NewPagination(45, 20, 0, 20)
+	if pagination.Page != 1 || !pagination.HasMore {
+		t.Errorf("Expected first page with more results, got %+v", pagination)
+	}
+}
+
+func TestTransactionFiltersPage(t *testing.T) {
+	limit, offset := models.TransactionFilters{Limit: 500, Offset: -1}.Page()
+
+	if limit != models.DefaultPageSize {
+		t.Errorf("Expected limit to be %d, got %d", models.DefaultPageSize, limit)
+	}
+	if offset != 0 {
+		t.Errorf("Expected offset to be 0, got %d", offset)
+	}
+}
+
+func TestMetadataPatchApply(t *testing.T) {
+	invoice := "INV-2"
+	metadata := models.TransactionMetadata{"invoice": "INV-1", "region": "eu", "team": "billing"}
+
+	patched := models.MetadataPatch{"invoice": &invoice, "region": nil, "unknown": nil}.Apply(metadata)
+
+	if len(patched) != 2 || patched["invoice"] != "INV-2" || patched["team"] != "billing" {
+		t.Errorf("Expected invoice INV-2 and team billing, got %v", patched)
+	}
+	if metadata["invoice"] != "INV-1" || len(metadata) != 3 {
+		t.Errorf("Expected the original metadata to be unchanged, got %v", metadata)
+	}
+}
+
+func TestDashboardSummaryRequestRange(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 30, 0, 0, time.UTC)
+
+	period, err := models.DashboardSummaryRequest{}.Range(now)
+	if err != nil || period != nil {
+		t.Errorf("Expected no period without parameters, got %v, %v", period, err)
+	}
+
+	period, err = models.DashboardSummaryRequest{Period: "7d"}.Range(now)
+	if err != nil {
+		t.Fatalf("Expected period 7d to resolve, got %v", err)
+	}
+	if want := time.Date(2024, 6, 9, 0, 0, 0, 0, time.UTC); !period.From.Equal(want) || !period.To.Equal(now) {
+		t.Errorf("Expected 7d to cover %v to %v, got %+v", want, now, period)
+	}
+
+	period, err = models.DashboardSummaryRequest{Period: "today"}.Range(now)
+	if err != nil || !period.From.Equal(time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected today to start at midnight, got %+v, %v", period, err)
+	}
+
+	period, err = models.DashboardSummaryRequest{From: "2024-06-01T00:00:00Z"}.Range(now)
+	if err != nil || !period.To.Equal(now) {
+		t.Errorf("Expected a from without a to to end now, got %+v, %v", period, err)
+	}
+
+	_, err = models.DashboardSummaryRequest{From: "2024-06-02T00:00:00Z", To: "2024-06-01T00:00:00Z"}.Range(now)
+	if err == nil {
+		t.Error("Expected an error when from is after to")
+	}
+}
+
+func TestTopUsersRequest(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 30, 0, 0, time.UTC)
+
+	req := models.TopUsersRequest{}
+	period, err := req.Range(now)
+	if err != nil || !period.From.Equal(time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)) || !period.To.Equal(now) {
+		t.Errorf("Expected top users to cover today by default, got %+v, %v", period, err)
+	}
+	if req.Top() != models.DefaultTopUsers || req.Order() != models.TopUsersByAmount {
+		t.Errorf("Expected the default limit and order, got %d, %s", req.Top(), req.Order())
+	}
+
+	req = models.TopUsersRequest{Limit: 1000, SortBy: models.TopUsersByCount, From: "2024-06-01T00:00:00Z"}
+	if req.Top() != models.MaxTopUsers {
+		t.Errorf("Expected the limit to be capped at %d, got %d", models.MaxTopUsers, req.Top())
+	}
+	if req.Order() != models.TopUsersByCount {
+		t.Errorf("Expected order by count, got %s", req.Order())
+	}
+	period, err = req.Range(now)
+	if err != nil || !period.From.Equal(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)) || !period.To.Equal(now) {
+		t.Errorf("Expected a from without a to to end now, got %+v, %v", period, err)
+	}
+
+	if _, err := (models.TopUsersRequest{From: "2024-06-02T00:00:00Z", To: "2024-06-01T00:00:00Z"}).Range(now); err == nil {
+		t.Error("Expected an error when from is after to")
+	}
+}
+
+func TestAPIResponseAppendJSON(t *testing.T) {
+	uuid := "0b9f6c1e-4a57-4b8e-9a77-3c1c8f0de5a1"
+	reference := "ref<1>&\"quoted\""
+	parentID := uint(7)
+	created := time.Date(2024, 6, 15, 12, 30, 0, 123456789, time.FixedZone("WIB", 7*3600))
+	transactions := []models.Transaction{
+		{ID: 1, UserID: 2, Amount: decimal.RequireFromString("-12.50"), Currency: "USD", Type: "adjustment", Direction: "credit", Status: "success", Version: 1, CreatedAt: created, UpdatedAt: created},
+		{
+			ID: 8, UUID: &uuid, ReferenceID: &reference, UserID: 3, Amount: decimal.NewFromInt(100), Currency: "IDR",
+			Type: "refund", Direction: "debit", Status: "refunded", Description: "tab\t, newline\n, control \x01, line sep \u2028, invalid \xff, emoji \U0001F600",
+			Metadata: models.TransactionMetadata{"region": "eu", "invoice": "INV-1"}, ParentID: &parentID, IsTest: true, Version: 3,
+			Tags: []models.Tag{{ID: 1, Name: "travel"}, {ID: 2, Name: "q3"}}, CreatedAt: created, UpdatedAt: created.UTC(),
+		},
+	}
+
+	responses := []models.APIResponse{
+		{Success: true, Data: transactions, Pagination: models.NewPagination(12, 2, 0, 2), Message: "Transactions retrieved successfully", RequestID: "req-1"},
+		{Success: true, Data: []models.Transaction{}, Pagination: models.NewPagination(0, 20, 0, 0)},
+		{Success: true, Data: []models.Transaction(nil)},
+		{Success: true, Data: &models.TransactionStatus{Status: "pending", UpdatedAt: created}, Message: "Transaction status retrieved successfully"},
+		{Success: false, Error: "Transaction not found", Code: "not_found"},
+	}
+	for _, response := range responses {
+		want, err := json.Marshal(response)
+		if err != nil {
+			t.Fatalf("Expected the response to marshal, got %v", err)
+		}
+		got, ok := response.AppendJSON(nil)
+		if !ok {
+			t.Errorf("Expected %T to be encoded", response.Data)
+		}
+		if string(got) != string(want) {
+			t.Errorf("Expected %s, got %s", want, got)
+		}
+	}
+
+	// Other data is left to encoding/json
+	for _, response := range []models.APIResponse{
+		{Success: true, Data: map[string]string{"key": "value"}},
+		{Success: true, Data: (*models.TransactionStatus)(nil)},
+		{Success: false, Fields: map[string]string{"mode": "must be strict or lenient"}},
+		{Success: true, Data: []models.Transaction{}, Warnings: []models.Warning{{Code: models.WarningDeprecated, Message: "Use offset instead"}}},
+	} {
+		if got, ok := response.AppendJSON([]byte("prefix")); ok || string(got) != "prefix" {
+			t.Errorf("Expected %T to be left to encoding/json, got %s", response.Data, got)
+		}
+	}
+}
+
+func TestTransactionAppendJSON_Amounts(t *testing.T) {
+	for _, amount := range []string{
+		"0", "0.00", "-0.5", "1", "10", "100.50", "-12.345", "0.001", "0.0100", "1e3", "999999999999999999", "-9999999999999999.99",
+		"123456789012345678901234567890.12", "0.000000000000000000001",
+	} {
+		transaction := models.Transaction{Amount: decimal.RequireFromString(amount)}
+		want, _ := json.Marshal(&transaction)
+		if got := transaction.AppendJSON(nil); string(got) != string(want) {
+			t.Errorf("Expected amount %s to encode as %s, got %s", amount, want, got)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// ProcessedMessage records a consumed message so redelivered copies are not
+// processed twice. A dead-lettered message is recorded with its Error and no
+// TransactionID, so its outcome can be reported, and is processed again if
+// it is redelivered.
+type ProcessedMessage struct {
+	MessageKey    string    `json:"message_key" gorm:"primaryKey;size:191"`
+	TransactionID uint      `json:"transaction_id" gorm:"not null"`
+	Error         string    `json:"error,omitempty" gorm:"size:500;not null;default:''"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Statuses of an asynchronous create job
+const (
+	CreateJobPending   = "pending"
+	CreateJobSucceeded = "succeeded"
+	CreateJobFailed    = "failed"
+)
+
+// CreateJob reports the progress of a transaction created with ?async=true.
+// Its ID is the key of the queued message.
+type CreateJob struct {
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	TransactionID uint   `json:"transaction_id,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
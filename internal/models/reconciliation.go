@@ -0,0 +1,58 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// MaxReconciliationItems is the most provider records a single comparison may hold; keep in sync with ReconciliationRequest
+const MaxReconciliationItems = 1000
+
+// ReconciliationItem is a provider's record of a transaction, matched to ours by reference ID
+type ReconciliationItem struct {
+	Reference string          `json:"reference" validate:"required,max=64"`
+	Amount    decimal.Decimal `json:"amount" validate:"required"`
+	Status    string          `json:"status" validate:"required,oneof=pending success failed refunded"`
+}
+
+// ReconciliationRequest represents request body for comparing provider records with ours
+type ReconciliationRequest struct {
+	Items []ReconciliationItem `json:"items" validate:"required,min=1,max=1000,unique=Reference,dive"`
+}
+
+// Reconciliation mismatch kinds: we have no transaction with the provider's
+// reference, or ours has a different amount or status
+const (
+	MismatchMissing = "missing"
+	MismatchAmount  = "amount"
+	MismatchStatus  = "status"
+)
+
+// ReconciliationMismatch represents a provider record that disagrees with
+// ours; Index is its position in the request and Transaction is our record,
+// unless it is missing
+type ReconciliationMismatch struct {
+	Index       int                `json:"index"`
+	Reference   string             `json:"reference"`
+	Kinds       []string           `json:"kinds"`
+	Provider    ReconciliationItem `json:"provider"`
+	Transaction *Transaction       `json:"transaction,omitempty"`
+}
+
+// ReconciliationResult represents the outcome of a comparison. Matched and
+// Mismatched count the whole request; Mismatches holds one page of the
+// mismatches, in request order.
+type ReconciliationResult struct {
+	Compared   int                      `json:"compared"`
+	Matched    int                      `json:"matched"`
+	Mismatched int                      `json:"mismatched"`
+	Mismatches []ReconciliationMismatch `json:"mismatches"`
+}
+
+// ReconciliationFilters represents the pagination of reconciliation mismatches
+type ReconciliationFilters struct {
+	Limit  int `form:"limit"`
+	Offset int `form:"offset"`
+}
+
+// Page returns the effective limit and offset of the filters
+func (f ReconciliationFilters) Page() (limit, offset int) {
+	return page(f.Limit, f.Offset)
+}
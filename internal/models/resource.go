@@ -0,0 +1,33 @@
+package models
+
+// ResourceDescription describes the capabilities of an API resource, returned by OPTIONS requests
+type ResourceDescription struct {
+	Resource     string         `json:"resource"`
+	Methods      []string       `json:"methods"`
+	ContentTypes []string       `json:"content_types"`
+	Filters      []string       `json:"filters,omitempty"`
+	Limits       map[string]int `json:"limits,omitempty"`
+}
+
+// FieldSchema describes a request field and the validations it must pass.
+// In is "body" for JSON fields and "query" for query parameters; Rules are
+// the field's validator tags, such as "max=64" or "decimal_scale=2".
+type FieldSchema struct {
+	Name     string   `json:"name"`
+	In       string   `json:"in"`
+	Type     string   `json:"type"`
+	Required bool     `json:"required"`
+	Rules    []string `json:"rules,omitempty"`
+}
+
+// EndpointExample describes the request an endpoint accepts and shows an
+// example request and the response it gets, returned by GET /api/meta/examples
+type EndpointExample struct {
+	Method   string        `json:"method"`
+	Path     string        `json:"path"`
+	Fields   []FieldSchema `json:"fields,omitempty"`
+	Request  interface{}   `json:"request,omitempty"`
+	Query    string        `json:"query,omitempty"`
+	Status   int           `json:"status"`
+	Response APIResponse   `json:"response"`
+}
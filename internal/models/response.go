@@ -1,9 +1,60 @@
 package models
 
+import "time"
+
 // APIResponse represents standard API response structure
 type APIResponse struct {
-	Success bool        `json:"success"`
-	Data    interface{} `json:"data,omitempty"`
-	Message string      `json:"message,omitempty"`
-	Error   string      `json:"error,omitempty"`
+	Success    bool        `json:"success"`
+	Data       interface{} `json:"data,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+	Message    string      `json:"message,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	// Code identifies the error for clients that handle errors programmatically
+	Code string `json:"code,omitempty"`
+	// Fields maps each invalid request parameter to what is wrong with it
+	Fields map[string]string `json:"fields,omitempty"`
+	// Warnings tell clients about deprecated endpoints and fields the
+	// response uses
+	Warnings []Warning `json:"warnings,omitempty"`
+	// RequestID correlates the response with the request's log records
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// WarningDeprecated is the code of warnings about deprecations
+const WarningDeprecated = "deprecated"
+
+// Warning tells a client about something in a successful response it
+// should act on
+type Warning struct {
+	Code string `json:"code"`
+	// Field names the response field the warning is about; it is left out
+	// when the warning is about the endpoint
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+	// Sunset is when the deprecated endpoint or field will be removed
+	Sunset *time.Time `json:"sunset,omitempty"`
+}
+
+// Pagination represents pagination metadata for list responses
+type Pagination struct {
+	Total   int64 `json:"total"`
+	Limit   int   `json:"limit"`
+	Offset  int   `json:"offset"`
+	Page    int   `json:"page"`
+	HasMore bool  `json:"has_more"`
+}
+
+// NewPagination builds pagination metadata for a page of count items
+func NewPagination(total int64, limit, offset, count int) *Pagination {
+	page := 1
+	if limit > 0 {
+		page = offset/limit + 1
+	}
+	return &Pagination{
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		Page:    page,
+		HasMore: int64(offset+count) < total,
+	}
 }
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// SchemaMigration records the schema version a migration left the database
+// at, so a binary can tell whether the schema was migrated past the one it
+// expects
+type SchemaMigration struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Version   string    `json:"version" gorm:"size:12;not null"`
+	AppliedAt time.Time `json:"applied_at" gorm:"not null"`
+}
+
+// SchemaModels returns the models whose tables make up the schema version
+func SchemaModels() []interface{} {
+	return []interface{}{&User{}, &Transaction{}, &Budget{}, &Tag{}, &Case{}, &CasePin{}, &Correction{}}
+}
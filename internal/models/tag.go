@@ -0,0 +1,41 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Tag represents a free-form label attached to transactions. Tags are
+// rendered as their name.
+type Tag struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"size:64;not null;uniqueIndex"`
+	CreatedAt time.Time
+}
+
+// MarshalJSON renders the tag as its name
+func (t Tag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Name)
+}
+
+// UnmarshalJSON reads a tag rendered by MarshalJSON
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &t.Name)
+}
+
+// MaxTagsPerRequest is the most tags a single add request may hold; keep in sync with AddTagsRequest
+const MaxTagsPerRequest = 20
+
+// AddTagsRequest represents request body for tagging a transaction
+type AddTagsRequest struct {
+	Tags []string `json:"tags" validate:"required,min=1,max=20,dive,required,max=64"`
+}
+
+// TagSummary represents the number and total amount of transactions carrying a tag
+type TagSummary struct {
+	Name        string          `json:"name"`
+	Count       int             `json:"count"`
+	TotalAmount decimal.Decimal `json:"total_amount"`
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"errors"
+	"strconv"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -8,45 +10,395 @@ import (
 
 // Transaction represents the transaction model
 type Transaction struct {
-	ID        uint            `json:"id" gorm:"primaryKey"`
-	UserID    uint            `json:"user_id" gorm:"not null;index"`
-	Amount    decimal.Decimal `json:"amount" gorm:"not null;type:decimal(15,2)"`
-	Status    string          `json:"status" gorm:"not null;default:'pending';index"`
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
+	ID          uint                `json:"id" gorm:"primaryKey"`
+	UUID        *string             `json:"uuid,omitempty" gorm:"size:36;uniqueIndex"`
+	ReferenceID *string             `json:"reference_id,omitempty" gorm:"size:64;uniqueIndex"`
+	UserID      uint                `json:"user_id" gorm:"not null;index"`
+	Amount      decimal.Decimal     `json:"amount" gorm:"not null;type:decimal(15,2)"`
+	Currency    string              `json:"currency" gorm:"size:3;not null;default:'USD'"`
+	Type        string              `json:"type" gorm:"size:20;not null;default:'payment';index"`
+	Direction   string              `json:"direction" gorm:"size:6;not null;default:'debit';index"`
+	Status      string              `json:"status" gorm:"not null;default:'pending';index"`
+	Description string              `json:"description" gorm:"size:255;not null;default:''"`
+	Metadata    TransactionMetadata `json:"metadata,omitempty" gorm:"type:text"`
+	// ParentID is the transaction a refund reverses
+	ParentID *uint `json:"parent_id,omitempty" gorm:"index"`
+	// IsTest marks sandbox and probe traffic, which dashboard, budget and
+	// volume aggregates and fixture exports leave out
+	IsTest bool `json:"is_test,omitempty" gorm:"not null;default:false;index"`
+	// Version starts at 1 and grows with every update, so clients can make
+	// an update conditional on the transaction not having changed
+	Version   uint      `json:"version" gorm:"not null;default:1"`
+	Tags      []Tag     `json:"tags,omitempty" gorm:"many2many:transaction_tags"`
+	User      *User     `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// Transaction types. Only adjustments may carry a negative amount. Refunds
+// are created by refunding a transaction, never directly.
+const (
+	TransactionTypePayment    = "payment"
+	TransactionTypeAdjustment = "adjustment"
+	TransactionTypeRefund     = "refund"
+)
+
+// Transaction directions, from the user's point of view: debits take money
+// out of the account and credits put money in
+const (
+	TransactionDirectionDebit  = "debit"
+	TransactionDirectionCredit = "credit"
+)
+
 // TransactionFilters represents filters for transaction queries
 type TransactionFilters struct {
-	UserID uint   `form:"user_id"`
-	Status string `form:"status"`
-	Limit  int    `form:"limit"`
-	Offset int    `form:"offset"`
+	UserID    uint             `form:"user_id" json:"user_id,omitempty"`
+	Status    string           `form:"status" json:"status,omitempty"`
+	From      *time.Time       `form:"from" time_format:"2006-01-02T15:04:05Z07:00" json:"from,omitempty"`
+	To        *time.Time       `form:"to" time_format:"2006-01-02T15:04:05Z07:00" json:"to,omitempty"`
+	MinAmount *decimal.Decimal `form:"min_amount" json:"min_amount,omitempty"`
+	MaxAmount *decimal.Decimal `form:"max_amount" json:"max_amount,omitempty"`
+	Tag       string           `form:"tag" json:"tag,omitempty"`
+	Direction string           `form:"direction" json:"direction,omitempty"`
+	IsTest    *bool            `form:"is_test" json:"is_test,omitempty"`
+	// Metadata holds metadata.<key>=<value> query filters; every pair must match
+	Metadata map[string]string `form:"-" json:"metadata,omitempty"`
+	SortBy   string            `form:"sort_by" json:"sort_by,omitempty"`
+	Order    string            `form:"order" json:"order,omitempty"`
+	Limit    int               `form:"limit" json:"limit,omitempty"`
+	Offset   int               `form:"offset" json:"offset,omitempty"`
+}
+
+// Default and maximum page sizes for transaction and user lists
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Page returns the effective limit and offset of the filters
+func (f TransactionFilters) Page() (limit, offset int) {
+	return page(f.Limit, f.Offset)
+}
+
+// page clamps a requested limit and offset to the page size bounds
+func page(limit, offset int) (int, int) {
+	if limit == 0 || limit > MaxPageSize {
+		limit = DefaultPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// Sort returns the effective sort column and direction of the filters,
+// newest first by default
+func (f TransactionFilters) Sort() (column string, desc bool) {
+	column = f.SortBy
+	if column == "" {
+		column = "created_at"
+	}
+	return column, f.Order != "asc"
 }
 
 // CreateTransactionRequest represents request body for creating transaction
 type CreateTransactionRequest struct {
-	UserID uint            `json:"user_id" validate:"required,min=1"`
-	Amount decimal.Decimal `json:"amount" validate:"required,decimal_positive"`
+	UserID    uint                `json:"user_id" validate:"required,min=1"`
+	Amount    decimal.Decimal     `json:"amount" validate:"required,decimal_nonzero"`
+	Currency  string              `json:"currency" validate:"omitempty,len=3,alpha"`
+	Type      string              `json:"type" validate:"omitempty,oneof=payment adjustment"`
+	Direction string              `json:"direction" validate:"omitempty,oneof=debit credit"`
+	Metadata  TransactionMetadata `json:"metadata" validate:"omitempty,max=20,dive,keys,min=1,max=64,endkeys,max=255"`
+	// ReferenceID is the upstream system's identifier for the transaction; it must be unique
+	ReferenceID string `json:"reference_id" validate:"omitempty,max=64"`
+	// IsTest marks the transaction as test data; the X-Test-Data header sets it too
+	IsTest bool `json:"is_test"`
+}
+
+// MaxBulkTransactions is the most transactions a single bulk create may hold
+const MaxBulkTransactions = 500
+
+// Bulk create item outcomes: the item was stored, matched a transaction
+// stored with its reference ID before, or was rejected
+const (
+	BulkItemCreated      = "created"
+	BulkItemDeduplicated = "deduplicated"
+	BulkItemFailed       = "failed"
+)
+
+// BulkCreateItemResult represents the outcome of one item of a bulk create;
+// Index is the item's position in the request. Deduplicated items hold the
+// transaction stored before or created for an earlier item.
+type BulkCreateItemResult struct {
+	Index       int          `json:"index"`
+	Status      string       `json:"status"`
+	Transaction *Transaction `json:"transaction,omitempty"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// BulkCreateResult represents the outcome of a bulk create, in request order
+type BulkCreateResult struct {
+	Created      int                    `json:"created"`
+	Deduplicated int                    `json:"deduplicated"`
+	Failed       int                    `json:"failed"`
+	Results      []BulkCreateItemResult `json:"results"`
 }
 
-// UpdateTransactionRequest represents request body for updating transaction
+// MaxLookupIDs is the most transactions a single lookup may resolve; keep in sync with TransactionLookupRequest
+const MaxLookupIDs = 100
+
+// TransactionLookupRequest represents request body for looking up transactions by ID
+type TransactionLookupRequest struct {
+	IDs []uint `json:"ids" validate:"required,min=1,max=100,dive,min=1"`
+}
+
+// TransactionLookupResult represents the transactions found by a lookup, in
+// request order, and the requested IDs that do not exist
+type TransactionLookupResult struct {
+	Transactions []Transaction `json:"transactions"`
+	Missing      []uint        `json:"missing"`
+}
+
+// UpdateTransactionRequest represents request body for updating transaction.
+// Status may be left out when only metadata changes, and a reason needs a
+// status. Metadata is a patch merged into the stored metadata, so it must
+// name the version it was based on in expected_version.
 type UpdateTransactionRequest struct {
-	Status string `json:"status" validate:"required,oneof=pending success failed"`
+	Status          string        `json:"status" validate:"required_without=Metadata,required_with=Reason,omitempty,oneof=pending success failed"`
+	Reason          string        `json:"reason,omitempty" validate:"omitempty,max=255"`
+	Metadata        MetadataPatch `json:"metadata,omitempty" validate:"omitempty,max=20,dive,keys,min=1,max=64,endkeys,omitempty,max=255"`
+	ExpectedVersion *uint         `json:"expected_version,omitempty" validate:"required_with=Metadata,omitempty,min=1"`
+}
+
+// PatchTransactionRequest represents request body for partially updating a
+// transaction; omitted fields are left unchanged and metadata, when present,
+// replaces the existing metadata
+type PatchTransactionRequest struct {
+	Status      *string             `json:"status" validate:"omitempty,oneof=pending success failed"`
+	Description *string             `json:"description" validate:"omitempty,max=255"`
+	Metadata    TransactionMetadata `json:"metadata" validate:"omitempty,max=20,dive,keys,min=1,max=64,endkeys,max=255"`
+}
+
+// ImmutableTransactionFields are the transaction fields a patch may not change
+var ImmutableTransactionFields = []string{"id", "uuid", "reference_id", "user_id", "amount", "currency", "type", "direction", "parent_id", "is_test", "version", "created_at", "updated_at"}
+
+// TransactionStatus represents the lightweight status of a transaction
+type TransactionStatus struct {
+	Status    string    `json:"status"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TransactionWaitResult represents the outcome of waiting for a status change
+type TransactionWaitResult struct {
+	Transaction *Transaction `json:"transaction"`
+	Changed     bool         `json:"changed"`
+}
+
+// TransactionFeedFilter selects the transactions pushed to a live feed
+// subscriber; empty fields match every transaction
+type TransactionFeedFilter struct {
+	UserID uint   `form:"user_id"`
+	Status string `form:"status" validate:"omitempty,oneof=pending success failed refunded"`
+}
+
+// TransactionFeedEvent represents a transaction pushed to live feed
+// subscribers when it is created or its status changes
+type TransactionFeedEvent struct {
+	Type        string       `json:"type"`
+	Transaction *Transaction `json:"transaction"`
+}
+
+// DashboardSummaryRequest holds the dashboard summary query parameters.
+// Values are validated as strings so every invalid parameter is reported.
+type DashboardSummaryRequest struct {
+	Mode        string `form:"mode" validate:"omitempty,oneof=strict lenient"`
+	IncludeTest string `form:"include_test" validate:"omitempty,boolean"`
+	From        string `form:"from" validate:"required_with=To,omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To          string `form:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Period      string `form:"period" validate:"omitempty,oneof=today 7d 30d,excluded_with=From To"`
 }
 
-// DashboardSummary represents dashboard summary response
+// summaryPeriodDays is how many calendar days, ending today, each period
+// shortcut covers
+var summaryPeriodDays = map[string]int{"today": 1, "7d": 7, "30d": 30}
+
+// SummaryPeriod is the time range, inclusive at both ends, the successful
+// totals, status counts and direction totals of a dashboard summary cover
+type SummaryPeriod struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Range resolves the requested period relative to now. It is nil when the
+// request names none, which leaves the summary covering today. A shortcut
+// starts at local midnight and ends at now, as does a from without a to.
+func (r DashboardSummaryRequest) Range(now time.Time) (*SummaryPeriod, error) {
+	return resolvePeriod(r.Period, r.From, r.To, now)
+}
+
+// resolvePeriod resolves a validated period shortcut or from and to
+// parameters relative to now, or returns nil when none is set
+func resolvePeriod(period, fromParam, toParam string, now time.Time) (*SummaryPeriod, error) {
+	if days, ok := summaryPeriodDays[period]; ok {
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		return &SummaryPeriod{From: midnight.AddDate(0, 0, 1-days), To: now}, nil
+	}
+	if fromParam == "" {
+		return nil, nil
+	}
+
+	// Both are validated, so they parse
+	from, _ := time.Parse(time.RFC3339, fromParam)
+	to := now
+	if toParam != "" {
+		to, _ = time.Parse(time.RFC3339, toParam)
+	}
+	if from.After(to) {
+		return nil, errors.New("from must not be after to")
+	}
+	return &SummaryPeriod{From: from, To: to}, nil
+}
+
+// Lenient reports whether the summary should return the sections that
+// succeeded instead of failing as a whole
+func (r DashboardSummaryRequest) Lenient() bool {
+	return r.Mode == "lenient"
+}
+
+// IncludesTest reports whether the summary should count test transactions
+func (r DashboardSummaryRequest) IncludesTest() bool {
+	includeTest, _ := strconv.ParseBool(r.IncludeTest)
+	return includeTest
+}
+
+// DashboardSummary represents dashboard summary response. Period is set when
+// the summary covers a requested period instead of today.
 type DashboardSummary struct {
-	TodaySuccessfulTransactions int             `json:"today_successful_transactions"`
-	TodaySuccessfulAmount       decimal.Decimal `json:"today_successful_amount"`
-	AverageTransactionPerUser   decimal.Decimal `json:"average_transaction_per_user"`
-	LatestTransactions          []Transaction   `json:"latest_transactions"`
-	StatusCounts                StatusCounts    `json:"status_counts"`
+	TodaySuccessfulTransactions int                `json:"today_successful_transactions"`
+	TodaySuccessfulAmount       decimal.Decimal    `json:"today_successful_amount"`
+	AverageTransactionPerUser   decimal.Decimal    `json:"average_transaction_per_user"`
+	LatestTransactions          []Transaction      `json:"latest_transactions"`
+	StatusCounts                StatusCounts       `json:"status_counts"`
+	DirectionTotals             DirectionTotals    `json:"direction_totals"`
+	CurrencyTotals              []CurrencyTotal    `json:"currency_totals"`
+	AmountDistribution          AmountDistribution `json:"amount_distribution"`
+	Tags                        []TagSummary       `json:"tags,omitempty"`
+	Period                      *SummaryPeriod     `json:"period,omitempty"`
+	Errors                      map[string]string  `json:"errors,omitempty"`
+}
+
+// Default and maximum number of users a top users request returns
+const (
+	DefaultTopUsers = 10
+	MaxTopUsers     = 100
+)
+
+// Orders of a top users request
+const (
+	TopUsersByAmount = "amount"
+	TopUsersByCount  = "count"
+)
+
+// TopUsersRequest holds the top users query parameters. The period is
+// validated as strings like DashboardSummaryRequest.
+type TopUsersRequest struct {
+	Limit       int    `form:"limit"`
+	SortBy      string `form:"sort_by" validate:"omitempty,oneof=amount count"`
+	IncludeTest string `form:"include_test" validate:"omitempty,boolean"`
+	From        string `form:"from" validate:"required_with=To,omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To          string `form:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Period      string `form:"period" validate:"omitempty,oneof=today 7d 30d,excluded_with=From To"`
+}
+
+// Range resolves the requested period relative to now like
+// DashboardSummaryRequest.Range, covering today when the request names none
+func (r TopUsersRequest) Range(now time.Time) (SummaryPeriod, error) {
+	period := r.Period
+	if period == "" && r.From == "" {
+		period = "today"
+	}
+	resolved, err := resolvePeriod(period, r.From, r.To, now)
+	if err != nil {
+		return SummaryPeriod{}, err
+	}
+	return *resolved, nil
+}
+
+// Top returns how many users to return, DefaultTopUsers by default and at
+// most MaxTopUsers
+func (r TopUsersRequest) Top() int {
+	if r.Limit <= 0 {
+		return DefaultTopUsers
+	}
+	return min(r.Limit, MaxTopUsers)
+}
+
+// Order returns the effective order, by total amount by default
+func (r TopUsersRequest) Order() string {
+	if r.SortBy == "" {
+		return TopUsersByAmount
+	}
+	return r.SortBy
+}
+
+// IncludesTest reports whether test transactions should be counted
+func (r TopUsersRequest) IncludesTest() bool {
+	includeTest, _ := strconv.ParseBool(r.IncludeTest)
+	return includeTest
+}
+
+// UserVolume is the number and total amount of a user's successful
+// transactions
+type UserVolume struct {
+	UserID uint            `json:"user_id"`
+	Name   string          `json:"name"`
+	Count  int             `json:"count"`
+	Amount decimal.Decimal `json:"amount"`
+}
+
+// TopUsers represents the highest-volume users of a period, highest first
+type TopUsers struct {
+	Period SummaryPeriod `json:"period"`
+	SortBy string        `json:"sort_by"`
+	Users  []UserVolume  `json:"users"`
 }
 
 // StatusCounts represents transaction status counts
 type StatusCounts struct {
-	Success int `json:"success"`
-	Pending int `json:"pending"`
-	Failed  int `json:"failed"`
+	Success  int `json:"success"`
+	Pending  int `json:"pending"`
+	Failed   int `json:"failed"`
+	Refunded int `json:"refunded"`
+}
+
+// DirectionTotals represents the number and total amount of successful
+// transactions in each direction
+type DirectionTotals struct {
+	Debit  DirectionTotal `json:"debit"`
+	Credit DirectionTotal `json:"credit"`
+}
+
+// DirectionTotal represents the number and total amount of successful transactions in one direction
+type DirectionTotal struct {
+	Count  int             `json:"count"`
+	Amount decimal.Decimal `json:"amount"`
+}
+
+// CurrencyTotal represents the number and total amount of successful transactions in one currency
+type CurrencyTotal struct {
+	Currency string          `json:"currency"`
+	Count    int             `json:"count"`
+	Amount   decimal.Decimal `json:"amount"`
+}
+
+// AmountDistribution represents the spread of the amounts of successful
+// transactions. The median and p95 are nearest-rank percentiles, so like the
+// minimum and maximum they are amounts of actual transactions; all are zero
+// when there are none.
+type AmountDistribution struct {
+	Count  int             `json:"count"`
+	Min    decimal.Decimal `json:"min"`
+	Max    decimal.Decimal `json:"max"`
+	Median decimal.Decimal `json:"median"`
+	P95    decimal.Decimal `json:"p95"`
 }
@@ -0,0 +1,285 @@
+package models
+
+import (
+	"slices"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+)
+
+// The AppendJSON encoders below write the same bytes encoding/json does for
+// the responses of the hottest endpoints, without reflection. Builds with the
+// fastjson tag answer GET /api/transactions and the status endpoint with
+// them; keep them in sync with the json tags of the types they encode.
+
+// AppendJSON appends the JSON encoding of r to dst. It reports false, leaving
+// dst as it was, when r holds data the hand-written encoders do not cover.
+func (r *APIResponse) AppendJSON(dst []byte) ([]byte, bool) {
+	if len(r.Fields) > 0 || len(r.Warnings) > 0 {
+		return dst, false
+	}
+	start := len(dst)
+
+	dst = append(dst, `{"success":`...)
+	dst = strconv.AppendBool(dst, r.Success)
+	switch data := r.Data.(type) {
+	case nil:
+	case []Transaction:
+		dst = append(dst, `,"data":`...)
+		dst = AppendTransactionsJSON(dst, data)
+	case *TransactionStatus:
+		if data == nil {
+			return dst[:start], false
+		}
+		dst = append(dst, `,"data":`...)
+		dst = data.AppendJSON(dst)
+	default:
+		return dst[:start], false
+	}
+	if r.Pagination != nil {
+		dst = append(dst, `,"pagination":`...)
+		dst = r.Pagination.AppendJSON(dst)
+	}
+	dst = appendStringField(dst, `,"message":`, r.Message)
+	dst = appendStringField(dst, `,"error":`, r.Error)
+	dst = appendStringField(dst, `,"code":`, r.Code)
+	dst = appendStringField(dst, `,"request_id":`, r.RequestID)
+	return append(dst, '}'), true
+}
+
+// AppendJSON appends the JSON encoding of p to dst
+func (p *Pagination) AppendJSON(dst []byte) []byte {
+	dst = append(dst, `{"total":`...)
+	dst = strconv.AppendInt(dst, p.Total, 10)
+	dst = append(dst, `,"limit":`...)
+	dst = strconv.AppendInt(dst, int64(p.Limit), 10)
+	dst = append(dst, `,"offset":`...)
+	dst = strconv.AppendInt(dst, int64(p.Offset), 10)
+	dst = append(dst, `,"page":`...)
+	dst = strconv.AppendInt(dst, int64(p.Page), 10)
+	dst = append(dst, `,"has_more":`...)
+	dst = strconv.AppendBool(dst, p.HasMore)
+	return append(dst, '}')
+}
+
+// AppendJSON appends the JSON encoding of s to dst
+func (s *TransactionStatus) AppendJSON(dst []byte) []byte {
+	dst = append(dst, `{"status":`...)
+	dst = appendJSONString(dst, s.Status)
+	dst = append(dst, `,"updated_at":`...)
+	dst = appendJSONTime(dst, s.UpdatedAt)
+	return append(dst, '}')
+}
+
+// AppendTransactionsJSON appends the JSON encoding of transactions to dst; a
+// nil slice is encoded as null
+func AppendTransactionsJSON(dst []byte, transactions []Transaction) []byte {
+	if transactions == nil {
+		return append(dst, "null"...)
+	}
+	dst = append(dst, '[')
+	for i := range transactions {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = transactions[i].AppendJSON(dst)
+	}
+	return append(dst, ']')
+}
+
+// AppendJSON appends the JSON encoding of t to dst
+func (t *Transaction) AppendJSON(dst []byte) []byte {
+	dst = append(dst, `{"id":`...)
+	dst = strconv.AppendUint(dst, uint64(t.ID), 10)
+	if t.UUID != nil {
+		dst = append(dst, `,"uuid":`...)
+		dst = appendJSONString(dst, *t.UUID)
+	}
+	if t.ReferenceID != nil {
+		dst = append(dst, `,"reference_id":`...)
+		dst = appendJSONString(dst, *t.ReferenceID)
+	}
+	dst = append(dst, `,"user_id":`...)
+	dst = strconv.AppendUint(dst, uint64(t.UserID), 10)
+	dst = append(dst, `,"amount":`...)
+	dst = append(dst, '"')
+	dst = appendDecimal(dst, t.Amount)
+	dst = append(dst, '"')
+	dst = append(dst, `,"currency":`...)
+	dst = appendJSONString(dst, t.Currency)
+	dst = append(dst, `,"type":`...)
+	dst = appendJSONString(dst, t.Type)
+	dst = append(dst, `,"direction":`...)
+	dst = appendJSONString(dst, t.Direction)
+	dst = append(dst, `,"status":`...)
+	dst = appendJSONString(dst, t.Status)
+	dst = append(dst, `,"description":`...)
+	dst = appendJSONString(dst, t.Description)
+	if len(t.Metadata) > 0 {
+		dst = append(dst, `,"metadata":`...)
+		dst = appendMetadataJSON(dst, t.Metadata)
+	}
+	if t.ParentID != nil {
+		dst = append(dst, `,"parent_id":`...)
+		dst = strconv.AppendUint(dst, uint64(*t.ParentID), 10)
+	}
+	if t.IsTest {
+		dst = append(dst, `,"is_test":true`...)
+	}
+	dst = append(dst, `,"version":`...)
+	dst = strconv.AppendUint(dst, uint64(t.Version), 10)
+	if len(t.Tags) > 0 {
+		dst = append(dst, `,"tags":[`...)
+		for i, tag := range t.Tags {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendJSONString(dst, tag.Name)
+		}
+		dst = append(dst, ']')
+	}
+	dst = append(dst, `,"created_at":`...)
+	dst = appendJSONTime(dst, t.CreatedAt)
+	dst = append(dst, `,"updated_at":`...)
+	dst = appendJSONTime(dst, t.UpdatedAt)
+	return append(dst, '}')
+}
+
+// appendMetadataJSON appends metadata as a JSON object with sorted keys, as
+// encoding/json orders map keys
+func appendMetadataJSON(dst []byte, metadata TransactionMetadata) []byte {
+	// Requests hold at most 20 keys, so the keys of most metadata fit on the stack
+	var stack [20]string
+	keys := stack[:0]
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	dst = append(dst, '{')
+	for i, key := range keys {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = appendJSONString(dst, key)
+		dst = append(dst, ':')
+		dst = appendJSONString(dst, metadata[key])
+	}
+	return append(dst, '}')
+}
+
+// appendDecimal appends d formatted as Decimal.String formats it, without
+// its big.Int allocations when the coefficient fits in an int64
+func appendDecimal(dst []byte, d decimal.Decimal) []byte {
+	if d.NumDigits() > 18 || d.Exponent() > 0 {
+		return append(dst, d.String()...)
+	}
+	coefficient := d.CoefficientInt64()
+	if coefficient == 0 {
+		return append(dst, '0')
+	}
+	if coefficient < 0 {
+		dst = append(dst, '-')
+		coefficient = -coefficient
+	}
+
+	var buf [20]byte
+	digits := strconv.AppendInt(buf[:0], coefficient, 10)
+	scale := int(-d.Exponent())
+	// String trims trailing zeros of the fraction
+	for scale > 0 && digits[len(digits)-1] == '0' {
+		digits = digits[:len(digits)-1]
+		scale--
+	}
+
+	switch {
+	case scale == 0:
+		return append(dst, digits...)
+	case len(digits) > scale:
+		dst = append(dst, digits[:len(digits)-scale]...)
+		dst = append(dst, '.')
+		return append(dst, digits[len(digits)-scale:]...)
+	default:
+		dst = append(dst, '0', '.')
+		for i := len(digits); i < scale; i++ {
+			dst = append(dst, '0')
+		}
+		return append(dst, digits...)
+	}
+}
+
+// appendStringField appends a string field left out when empty
+func appendStringField(dst []byte, name, value string) []byte {
+	if value == "" {
+		return dst
+	}
+	dst = append(dst, name...)
+	return appendJSONString(dst, value)
+}
+
+// appendJSONTime appends t quoted in RFC 3339 with nanoseconds, as
+// time.Time's MarshalJSON does
+func appendJSONTime(dst []byte, t time.Time) []byte {
+	dst = append(dst, '"')
+	dst = t.AppendFormat(dst, time.RFC3339Nano)
+	return append(dst, '"')
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString appends s as a JSON string, escaped like encoding/json
+// escapes it: HTML characters, U+2028 and U+2029 are escaped too and invalid
+// UTF-8 is replaced with U+FFFD
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= ' ' && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				dst = append(dst, '\\', b)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
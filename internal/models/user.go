@@ -0,0 +1,43 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// User represents an account that transactions belong to
+type User struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"size:100;not null"`
+	Email     string    `json:"email" gorm:"size:255;not null;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreateUserRequest represents request body for creating a user
+type CreateUserRequest struct {
+	Name  string `json:"name" validate:"required,max=100"`
+	Email string `json:"email" validate:"required,email,max=255"`
+}
+
+// UserFilters represents the pagination of user lists
+type UserFilters struct {
+	Limit  int `form:"limit"`
+	Offset int `form:"offset"`
+}
+
+// Page returns the effective limit and offset of the filters
+func (f UserFilters) Page() (limit, offset int) {
+	return page(f.Limit, f.Offset)
+}
+
+// PlaceholderUser is the user created for an ID that transactions reference
+// without a user record, such as rows from before users existed or fixtures
+// with pseudonymized user IDs
+func PlaceholderUser(id uint) User {
+	return User{
+		ID:    id,
+		Name:  fmt.Sprintf("User %d", id),
+		Email: fmt.Sprintf("user-%d@placeholder.invalid", id),
+	}
+}
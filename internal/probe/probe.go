@@ -0,0 +1,166 @@
+package probe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"interview/internal/handlers"
+	"interview/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// metrics are the probe's counters, published as the synthetic_probe expvar
+var metrics = expvar.NewMap("synthetic_probe")
+
+// basePath is the API version the probe calls
+const basePath = "/api/v1/transactions"
+
+// Probe exercises the transaction API end to end: it creates a test
+// transaction, marks it successful, reads its status back and deletes it.
+// Requests go through the same router as client requests, including its
+// middleware, but are served in process.
+type Probe struct {
+	handler http.Handler
+	userID  uint
+}
+
+// New creates a probe calling handler on behalf of userID, which must exist
+func New(handler http.Handler, userID uint) *Probe {
+	return &Probe{handler: handler, userID: userID}
+}
+
+// Run runs the probe once and records its outcome in the synthetic_probe
+// expvar. The test transaction is deleted even when a later step fails.
+func (p *Probe) Run(ctx context.Context) error {
+	started := time.Now()
+	err := p.run(ctx)
+	duration := time.Since(started)
+
+	metrics.Add("runs", 1)
+	metrics.Set("last_duration_ms", intVar(duration.Milliseconds()))
+	if err != nil {
+		metrics.Add("failures", 1)
+		metrics.Set("last_error", stringVar(err.Error()))
+		return err
+	}
+	metrics.Set("last_success", stringVar(time.Now().UTC().Format(time.RFC3339)))
+	metrics.Set("last_error", stringVar(""))
+	return nil
+}
+
+// run creates, transitions, reads and deletes a test transaction
+func (p *Probe) run(ctx context.Context) (err error) {
+	var created models.Transaction
+	body := map[string]interface{}{
+		"user_id":  p.userID,
+		"amount":   "1.00",
+		"metadata": map[string]string{"source": "synthetic-probe"},
+	}
+	if err := p.call(ctx, http.MethodPost, basePath, body, http.StatusCreated, &created); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	path := basePath + "/" + strconv.FormatUint(uint64(created.ID), 10)
+	defer func() {
+		if deleteErr := p.call(ctx, http.MethodDelete, path, nil, http.StatusOK, nil); deleteErr != nil && err == nil {
+			err = fmt.Errorf("delete: %w", deleteErr)
+		}
+	}()
+
+	if err := p.call(ctx, http.MethodPut, path, map[string]string{"status": "success"}, http.StatusOK, nil); err != nil {
+		return fmt.Errorf("update status: %w", err)
+	}
+
+	var status models.TransactionStatus
+	if err := p.call(ctx, http.MethodGet, path+"/status", nil, http.StatusOK, &status); err != nil {
+		return fmt.Errorf("get status: %w", err)
+	}
+	if status.Status != "success" {
+		return fmt.Errorf("get status: expected success, got %s", status.Status)
+	}
+	return nil
+}
+
+// call sends a request through the handler, checks its status code and
+// decodes the response data into out
+func (p *Probe) call(ctx context.Context, method, path string, body interface{}, want int, out interface{}) error {
+	var encoded []byte
+	if body != nil {
+		var err error
+		if encoded, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(encoded)).WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(handlers.TestDataHeader, "true")
+	recorder := httptest.NewRecorder()
+	p.handler.ServeHTTP(recorder, req)
+
+	var response struct {
+		Data  json.RawMessage `json:"data"`
+		Error string          `json:"error"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		return fmt.Errorf("invalid response with status %d: %v", recorder.Code, err)
+	}
+	if recorder.Code != want {
+		return fmt.Errorf("expected status %d, got %d: %s", want, recorder.Code, response.Error)
+	}
+	if out != nil {
+		return json.Unmarshal(response.Data, out)
+	}
+	return nil
+}
+
+// Start runs the probe every interval until the returned stop function is
+// called. Each run may take at most interval. Runs are skipped while leader
+// reports false, so only one replica creates probe transactions.
+func (p *Probe) Start(interval time.Duration, leader func() bool) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if !leader() {
+					continue
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				if err := p.Run(ctx); err != nil {
+					logrus.WithError(err).Warn("Synthetic probe failed")
+				}
+				cancel()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+func intVar(value int64) *expvar.Int {
+	v := new(expvar.Int)
+	v.Set(value)
+	return v
+}
+
+func stringVar(value string) *expvar.String {
+	v := new(expvar.String)
+	v.Set(value)
+	return v
+}
@@ -0,0 +1,107 @@
+package probe_test
+
+import (
+	"context"
+	"expvar"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"interview/internal/handlers"
+	"interview/internal/models"
+	"interview/internal/probe"
+	"interview/internal/repositories"
+	"interview/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupProbe(t *testing.T, userID uint) (*probe.Probe, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.Transaction{}, &models.Tag{}))
+	require.NoError(t, db.Create(&models.User{ID: 1, Name: "Probe", Email: "probe@example.com"}).Error)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	service := services.NewTransactionService(repositories.NewTransactionRepository(db),
+		services.WithUserService(services.NewUserService(repositories.NewUserRepository(db))),
+	)
+	handler := handlers.NewTransactionHandler(service)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	transactions := router.Group("/api/v1/transactions")
+	transactions.POST("", handler.CreateTransaction)
+	transactions.PUT("/:id", handler.UpdateTransaction)
+	transactions.GET("/:id/status", handler.GetTransactionStatus)
+	transactions.DELETE("/:id", handler.DeleteTransaction)
+
+	return probe.New(router, userID), db
+}
+
+func metric(name string) string {
+	value := expvar.Get("synthetic_probe").(*expvar.Map).Get(name)
+	if value == nil {
+		return ""
+	}
+	return value.String()
+}
+
+func TestProbe_Run(t *testing.T) {
+	p, db := setupProbe(t, 1)
+
+	// A middle step sees the test transaction before it is deleted
+	var seen []models.Transaction
+	require.NoError(t, db.Callback().Update().After("gorm:update").Register("probe_test:capture", func(tx *gorm.DB) {
+		db.Session(&gorm.Session{NewDB: true}).Find(&seen)
+	}))
+
+	require.NoError(t, p.Run(context.Background()))
+
+	require.Len(t, seen, 1)
+	assert.True(t, seen[0].IsTest)
+	assert.Equal(t, "success", seen[0].Status)
+
+	var count int64
+	require.NoError(t, db.Model(&models.Transaction{}).Count(&count).Error)
+	assert.Zero(t, count, "the test transaction is deleted")
+	assert.NotEqual(t, `""`, metric("last_success"))
+	assert.Equal(t, `""`, metric("last_error"))
+}
+
+func TestProbe_RunFailure(t *testing.T) {
+	p, _ := setupProbe(t, 99)
+	failures := metric("failures")
+
+	err := p.Run(context.Background())
+
+	assert.ErrorContains(t, err, "create: expected status 201, got 422")
+	assert.NotEqual(t, failures, metric("failures"))
+	assert.Contains(t, metric("last_error"), "create")
+}
+
+func TestProbe_StartOnlyRunsOnLeader(t *testing.T) {
+	p, _ := setupProbe(t, 1)
+
+	var asked atomic.Int32
+	runs := metric("runs")
+	stop := p.Start(5*time.Millisecond, func() bool {
+		asked.Add(1)
+		return false
+	})
+	require.Eventually(t, func() bool { return asked.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	stop()
+
+	assert.Equal(t, runs, metric("runs"), "a replica without the lease should not run the probe")
+}
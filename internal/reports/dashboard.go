@@ -0,0 +1,243 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"interview/internal/models"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ContentType is the media type of XLSX workbooks
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// Sheets of dashboard reports, in workbook order
+const (
+	SummarySheet            = "Summary"
+	StatusCountsSheet       = "Status Counts"
+	CurrencyTotalsSheet     = "Currency Totals"
+	LatestTransactionsSheet = "Latest Transactions"
+)
+
+// amountFormat displays amounts with two decimals and thousands separators
+const amountFormat = "#,##0.00"
+
+// latestColumns is the header of the latest transactions sheet
+var latestColumns = []interface{}{
+	"ID", "UUID", "Reference ID", "User ID", "Amount", "Currency", "Type",
+	"Direction", "Status", "Description", "Created At (UTC)",
+}
+
+// DashboardFilename returns the attachment name of a dashboard report
+// generated at generatedAt
+func DashboardFilename(generatedAt time.Time) string {
+	return fmt.Sprintf("dashboard-%s.xlsx", generatedAt.UTC().Format("2006-01-02"))
+}
+
+// WriteDashboard writes summary to w as an XLSX workbook with a summary, a
+// status counts, a currency totals and a latest transactions sheet. Sections that failed in a
+// lenient summary are listed at the end of the summary sheet.
+func WriteDashboard(w io.Writer, summary *models.DashboardSummary, generatedAt time.Time) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName("Sheet1", SummarySheet); err != nil {
+		return err
+	}
+	for _, sheet := range []string{StatusCountsSheet, CurrencyTotalsSheet, LatestTransactionsSheet} {
+		if _, err := f.NewSheet(sheet); err != nil {
+			return err
+		}
+	}
+
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	amount, err := f.NewStyle(&excelize.Style{CustomNumFmt: stringPtr(amountFormat)})
+	if err != nil {
+		return err
+	}
+	dateTime, err := f.NewStyle(&excelize.Style{CustomNumFmt: stringPtr("yyyy-mm-dd hh:mm:ss")})
+	if err != nil {
+		return err
+	}
+
+	st := styles{bold: bold, amount: amount, dateTime: dateTime}
+	if err := writeSummary(f, summary, generatedAt, st); err != nil {
+		return err
+	}
+	if err := writeStatusCounts(f, summary, st); err != nil {
+		return err
+	}
+	if err := writeCurrencyTotals(f, summary, st); err != nil {
+		return err
+	}
+	if err := writeLatestTransactions(f, summary, st); err != nil {
+		return err
+	}
+
+	return f.Write(w)
+}
+
+// styles are the cell styles shared by the sheets of a workbook
+type styles struct {
+	bold, amount, dateTime int
+}
+
+// cellStyle is a style to set on a single cell
+type cellStyle struct {
+	cell  string
+	style int
+}
+
+// writeSummary fills the summary sheet with one metric per row. A summary
+// covering a requested period ends with the period instead of saying today.
+func writeSummary(f *excelize.File, summary *models.DashboardSummary, generatedAt time.Time, st styles) error {
+	successful := "Today's Successful"
+	if summary.Period != nil {
+		successful = "Successful"
+	}
+	rows := [][]interface{}{
+		{"Metric", "Value"},
+		{"Generated At (UTC)", generatedAt.UTC()},
+		{successful + " Transactions", summary.TodaySuccessfulTransactions},
+		{successful + " Amount", summary.TodaySuccessfulAmount.InexactFloat64()},
+		{"Average Transactions Per User", summary.AverageTransactionPerUser.InexactFloat64()},
+		{"Successful Debits", summary.DirectionTotals.Debit.Count},
+		{"Successful Debit Amount", summary.DirectionTotals.Debit.Amount.InexactFloat64()},
+		{"Successful Credits", summary.DirectionTotals.Credit.Count},
+		{"Successful Credit Amount", summary.DirectionTotals.Credit.Amount.InexactFloat64()},
+	}
+	cellStyles := []cellStyle{{"B2", st.dateTime}, {"B4", st.amount}, {"B5", st.amount}, {"B7", st.amount}, {"B9", st.amount}}
+	if summary.Period != nil {
+		rows = append(rows,
+			[]interface{}{"Period From (UTC)", summary.Period.From.UTC()},
+			[]interface{}{"Period To (UTC)", summary.Period.To.UTC()},
+		)
+		cellStyles = append(cellStyles, cellStyle{"B10", st.dateTime}, cellStyle{"B11", st.dateTime})
+	}
+	sections := make([]string, 0, len(summary.Errors))
+	for section := range summary.Errors {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+	for _, section := range sections {
+		rows = append(rows, []interface{}{"Error: " + section, summary.Errors[section]})
+	}
+
+	if err := writeRows(f, SummarySheet, rows, st.bold); err != nil {
+		return err
+	}
+	for _, style := range cellStyles {
+		if err := f.SetCellStyle(SummarySheet, style.cell, style.cell, style.style); err != nil {
+			return err
+		}
+	}
+	return f.SetColWidth(SummarySheet, "A", "B", 32)
+}
+
+// writeStatusCounts fills the status counts sheet with one status per row
+func writeStatusCounts(f *excelize.File, summary *models.DashboardSummary, st styles) error {
+	counts := summary.StatusCounts
+	rows := [][]interface{}{
+		{"Status", "Count"},
+		{"success", counts.Success},
+		{"pending", counts.Pending},
+		{"failed", counts.Failed},
+		{"refunded", counts.Refunded},
+		{"total", counts.Success + counts.Pending + counts.Failed + counts.Refunded},
+	}
+	if err := writeRows(f, StatusCountsSheet, rows, st.bold); err != nil {
+		return err
+	}
+	return f.SetColWidth(StatusCountsSheet, "A", "B", 16)
+}
+
+// writeCurrencyTotals fills the currency totals sheet with one currency per
+// row, in currency order
+func writeCurrencyTotals(f *excelize.File, summary *models.DashboardSummary, st styles) error {
+	rows := [][]interface{}{{"Currency", "Transactions", "Amount"}}
+	for _, total := range summary.CurrencyTotals {
+		rows = append(rows, []interface{}{total.Currency, total.Count, total.Amount.InexactFloat64()})
+	}
+	if err := writeRows(f, CurrencyTotalsSheet, rows, st.bold); err != nil {
+		return err
+	}
+	if last := len(rows); last > 1 {
+		if err := f.SetCellStyle(CurrencyTotalsSheet, "C2", fmt.Sprintf("C%d", last), st.amount); err != nil {
+			return err
+		}
+	}
+	return f.SetColWidth(CurrencyTotalsSheet, "A", "C", 16)
+}
+
+// writeLatestTransactions fills the latest transactions sheet with one
+// transaction per row, newest first
+func writeLatestTransactions(f *excelize.File, summary *models.DashboardSummary, st styles) error {
+	rows := [][]interface{}{latestColumns}
+	for _, transaction := range summary.LatestTransactions {
+		rows = append(rows, []interface{}{
+			transaction.ID,
+			stringValue(transaction.UUID),
+			stringValue(transaction.ReferenceID),
+			transaction.UserID,
+			transaction.Amount.InexactFloat64(),
+			transaction.Currency,
+			transaction.Type,
+			transaction.Direction,
+			transaction.Status,
+			transaction.Description,
+			transaction.CreatedAt.UTC(),
+		})
+	}
+	if err := writeRows(f, LatestTransactionsSheet, rows, st.bold); err != nil {
+		return err
+	}
+
+	if last := len(rows); last > 1 {
+		if err := f.SetCellStyle(LatestTransactionsSheet, "E2", fmt.Sprintf("E%d", last), st.amount); err != nil {
+			return err
+		}
+		if err := f.SetCellStyle(LatestTransactionsSheet, "K2", fmt.Sprintf("K%d", last), st.dateTime); err != nil {
+			return err
+		}
+	}
+	if err := f.SetColWidth(LatestTransactionsSheet, "B", "C", 38); err != nil {
+		return err
+	}
+	if err := f.SetColWidth(LatestTransactionsSheet, "J", "J", 40); err != nil {
+		return err
+	}
+	return f.SetColWidth(LatestTransactionsSheet, "K", "K", 20)
+}
+
+// writeRows writes rows to sheet from A1, the first one in bold as header
+func writeRows(f *excelize.File, sheet string, rows [][]interface{}, header int) error {
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			return err
+		}
+	}
+	return f.SetRowStyle(sheet, 1, 1, header)
+}
+
+// stringValue returns the string s points to, or "" for nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// stringPtr returns a pointer to s
+func stringPtr(s string) *string {
+	return &s
+}
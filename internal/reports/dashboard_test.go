@@ -0,0 +1,113 @@
+package reports
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"interview/internal/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+var generatedAt = time.Date(2024, 6, 15, 12, 30, 0, 0, time.UTC)
+
+// openDashboard writes summary as a workbook and opens it again
+func openDashboard(t *testing.T, summary *models.DashboardSummary) *excelize.File {
+	var buf bytes.Buffer
+	require.NoError(t, WriteDashboard(&buf, summary, generatedAt))
+	f, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestWriteDashboard(t *testing.T) {
+	uuid, reference := "0190a0c4-0000-7000-8000-000000000001", "=HYPERLINK(\"x\")"
+	f := openDashboard(t, &models.DashboardSummary{
+		TodaySuccessfulTransactions: 5,
+		TodaySuccessfulAmount:       decimal.RequireFromString("1250.75"),
+		AverageTransactionPerUser:   decimal.RequireFromString("3.2"),
+		StatusCounts:                models.StatusCounts{Success: 15, Pending: 8, Failed: 2, Refunded: 1},
+		DirectionTotals: models.DirectionTotals{
+			Debit:  models.DirectionTotal{Count: 12, Amount: decimal.RequireFromString("980.25")},
+			Credit: models.DirectionTotal{Count: 3, Amount: decimal.NewFromInt(450)},
+		},
+		CurrencyTotals: []models.CurrencyTotal{
+			{Currency: "JPY", Count: 1, Amount: decimal.NewFromInt(5000)},
+			{Currency: "USD", Count: 4, Amount: decimal.RequireFromString("1250.75")},
+		},
+		LatestTransactions: []models.Transaction{{
+			ID: 10, UUID: &uuid, ReferenceID: &reference, UserID: 2, Amount: decimal.NewFromInt(250),
+			Currency: "USD", Type: models.TransactionTypePayment, Direction: models.TransactionDirectionDebit,
+			Status: "success", Description: "Coffee", CreatedAt: generatedAt.Add(-time.Hour),
+		}},
+	})
+
+	assert.Equal(t, []string{SummarySheet, StatusCountsSheet, CurrencyTotalsSheet, LatestTransactionsSheet}, f.GetSheetList())
+
+	rows, err := f.GetRows(SummarySheet, excelize.Options{RawCellValue: true})
+	require.NoError(t, err)
+	require.Len(t, rows, 9)
+	assert.Equal(t, []string{"Today's Successful Transactions", "5"}, rows[2])
+	assert.Equal(t, []string{"Today's Successful Amount", "1250.75"}, rows[3])
+	assert.Equal(t, []string{"Successful Credit Amount", "450"}, rows[8])
+	generated, err := f.GetCellValue(SummarySheet, "B2")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-06-15 12:30:00", generated)
+
+	rows, err = f.GetRows(StatusCountsSheet)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"Status", "Count"}, {"success", "15"}, {"pending", "8"}, {"failed", "2"}, {"refunded", "1"}, {"total", "26"}}, rows)
+
+	rows, err = f.GetRows(CurrencyTotalsSheet, excelize.Options{RawCellValue: true})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"Currency", "Transactions", "Amount"}, {"JPY", "1", "5000"}, {"USD", "4", "1250.75"}}, rows)
+
+	rows, err = f.GetRows(LatestTransactionsSheet)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "ID", rows[0][0])
+	assert.Equal(t, []string{"10", uuid, reference, "2", "250.00", "USD", "payment", "debit", "success", "Coffee", "2024-06-15 11:30:00"}, rows[1])
+
+	// Client supplied text is stored as text, never as a formula
+	formula, err := f.GetCellFormula(LatestTransactionsSheet, "C2")
+	require.NoError(t, err)
+	assert.Empty(t, formula)
+}
+
+func TestWriteDashboard_PartialSummary(t *testing.T) {
+	f := openDashboard(t, &models.DashboardSummary{
+		Errors: map[string]string{"tags": "timeout", "latest_transactions": "connection refused"},
+	})
+
+	rows, err := f.GetRows(SummarySheet)
+	require.NoError(t, err)
+	require.Len(t, rows, 11)
+	assert.Equal(t, []string{"Error: latest_transactions", "connection refused"}, rows[9])
+	assert.Equal(t, []string{"Error: tags", "timeout"}, rows[10])
+
+	rows, err = f.GetRows(LatestTransactionsSheet)
+	require.NoError(t, err)
+	assert.Len(t, rows, 1)
+}
+
+func TestWriteDashboard_PeriodSummary(t *testing.T) {
+	period := &models.SummaryPeriod{From: time.Date(2024, 6, 9, 0, 0, 0, 0, time.UTC), To: generatedAt}
+	f := openDashboard(t, &models.DashboardSummary{TodaySuccessfulTransactions: 40, Period: period})
+
+	rows, err := f.GetRows(SummarySheet)
+	require.NoError(t, err)
+	require.Len(t, rows, 11)
+	assert.Equal(t, []string{"Successful Transactions", "40"}, rows[2])
+	assert.Equal(t, []string{"Period From (UTC)", "2024-06-09 00:00:00"}, rows[9])
+	assert.Equal(t, []string{"Period To (UTC)", "2024-06-15 12:30:00"}, rows[10])
+}
+
+func TestDashboardFilename(t *testing.T) {
+	local := time.Date(2024, 6, 16, 1, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+	assert.Equal(t, "dashboard-2024-06-15.xlsx", DashboardFilename(local))
+}
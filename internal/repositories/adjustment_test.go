@@ -0,0 +1,53 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionRepository_AggregatesWithNegativeAdjustments(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	transactions := []models.Transaction{
+		{UserID: 1, Amount: decimal.NewFromFloat(100.00), Type: models.TransactionTypePayment, Status: "success"},
+		{UserID: 1, Amount: decimal.NewFromFloat(-30.25), Type: models.TransactionTypeAdjustment, Status: "success"},
+		{UserID: 2, Amount: decimal.NewFromFloat(50.00), Type: models.TransactionTypePayment, Status: "success"},
+		{UserID: 2, Amount: decimal.NewFromFloat(-10.00), Type: models.TransactionTypeAdjustment, Status: "failed"},
+	}
+	for i := range transactions {
+		assert.NoError(t, repo.Create(context.Background(), &transactions[i]))
+	}
+
+	// Adjustments count as transactions and their signed amounts net out the total
+	count, amount, err := repo.GetTodaySuccessful(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.True(t, amount.Equal(decimal.NewFromFloat(119.75)), "expected 119.75, got %s", amount)
+
+	avg, err := repo.GetAveragePerUser(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, avg.Equal(decimal.NewFromInt(2)), "expected 2, got %s", avg)
+
+	counts, err := repo.GetStatusCounts(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCounts{Success: 3, Failed: 1}, counts)
+}
+
+func TestTransactionRepository_TodaySuccessfulNetNegative(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	assert.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromFloat(10.00), Type: models.TransactionTypePayment, Status: "success"}))
+	assert.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromFloat(-15.00), Type: models.TransactionTypeAdjustment, Status: "success"}))
+
+	_, amount, err := repo.GetTodaySuccessful(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, amount.Equal(decimal.NewFromFloat(-5.00)), "expected -5, got %s", amount)
+}
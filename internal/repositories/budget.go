@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"interview/internal/models"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BudgetRepository interface defines budget repository methods
+type BudgetRepository interface {
+	Upsert(ctx context.Context, budget *models.Budget) error
+	GetByUserID(ctx context.Context, userID uint) (*models.Budget, error)
+	GetVolume(ctx context.Context, userID uint, currency string, from, to time.Time) (decimal.Decimal, error)
+}
+
+// budgetRepository implements BudgetRepository interface
+type budgetRepository struct {
+	db *gorm.DB
+}
+
+// NewBudgetRepository creates a new budget repository
+func NewBudgetRepository(db *gorm.DB) BudgetRepository {
+	return &budgetRepository{db: db}
+}
+
+// Upsert creates or replaces a user's budget
+func (r *budgetRepository) Upsert(ctx context.Context, budget *models.Budget) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"monthly_cap", "currency", "block_overage", "updated_at"}),
+	}).Create(budget).Error
+}
+
+// GetByUserID gets a user's budget
+func (r *budgetRepository) GetByUserID(ctx context.Context, userID uint) (*models.Budget, error) {
+	var budget models.Budget
+	err := r.db.WithContext(ctx).First(&budget, "user_id = ?", userID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &budget, nil
+}
+
+// GetVolume gets the signed sum of a user's non-failed transactions in
+// currency created in [from, to). Refunded transactions and their refunds
+// cancel out, so neither is counted, and test transactions are left out.
+func (r *budgetRepository) GetVolume(ctx context.Context, userID uint, currency string, from, to time.Time) (decimal.Decimal, error) {
+	var volume decimal.Decimal
+	err := r.db.WithContext(ctx).Model(&models.Transaction{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ? AND currency = ? AND status NOT IN ? AND type <> ? AND is_test = ? AND created_at >= ? AND created_at < ?", userID, currency, []string{"failed", "refunded"}, models.TransactionTypeRefund, false, from, to).
+		Scan(&volume).Error
+	return volume, err
+}
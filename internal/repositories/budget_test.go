@@ -0,0 +1,46 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudgetRepository_UpsertAndVolume(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	require.NoError(t, db.AutoMigrate(&models.Budget{}))
+	repo := repositories.NewBudgetRepository(db)
+
+	assert.NoError(t, repo.Upsert(context.Background(), &models.Budget{UserID: 1, MonthlyCap: decimal.NewFromInt(100)}))
+	assert.NoError(t, repo.Upsert(context.Background(), &models.Budget{UserID: 1, MonthlyCap: decimal.NewFromInt(500), BlockOverage: true}))
+
+	budget, err := repo.GetByUserID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.True(t, budget.MonthlyCap.Equal(decimal.NewFromInt(500)))
+	assert.True(t, budget.BlockOverage)
+
+	db.Create(&models.Transaction{UserID: 1, Amount: decimal.NewFromInt(40), Status: "success"})
+	db.Create(&models.Transaction{UserID: 1, Amount: decimal.NewFromInt(60), Status: "pending"})
+	db.Create(&models.Transaction{UserID: 1, Amount: decimal.NewFromInt(999), Status: "failed"})
+	db.Create(&models.Transaction{UserID: 2, Amount: decimal.NewFromInt(999), Status: "success"})
+	db.Create(&models.Transaction{UserID: 1, Amount: decimal.NewFromInt(999), Currency: "EUR", Status: "success"})
+	// A refunded transaction and its refund cancel out
+	db.Create(&models.Transaction{UserID: 1, Amount: decimal.NewFromInt(30), Status: "refunded"})
+	db.Create(&models.Transaction{UserID: 1, Amount: decimal.NewFromInt(30), Type: models.TransactionTypeRefund, Direction: models.TransactionDirectionCredit, Status: "success"})
+
+	now := time.Now()
+	volume, err := repo.GetVolume(context.Background(), 1, "USD", now.Add(-time.Hour), now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, volume.Equal(decimal.NewFromInt(100)), "expected 100, got %s", volume)
+
+	volume, err = repo.GetVolume(context.Background(), 1, "EUR", now.Add(-time.Hour), now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, volume.Equal(decimal.NewFromInt(999)), "expected 999, got %s", volume)
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"interview/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CaseRepository interface defines investigation case repository methods
+type CaseRepository interface {
+	Create(ctx context.Context, c *models.Case) error
+	GetByID(ctx context.Context, id uint) (*models.Case, error)
+	GetAllWithCount(ctx context.Context, filters models.CaseFilters) ([]models.Case, int64, error)
+	Close(ctx context.Context, id uint, outcome, resolution string, closedAt time.Time) error
+	Pin(ctx context.Context, caseID uint, transactionIDs []uint, note string) error
+	Unpin(ctx context.Context, caseID, transactionID uint) (bool, error)
+	GetPinsWithCount(ctx context.Context, caseID uint, filters models.CasePinFilters) ([]models.CasePin, int64, error)
+}
+
+// caseRepository implements CaseRepository interface
+type caseRepository struct {
+	db *gorm.DB
+}
+
+// NewCaseRepository creates a new case repository
+func NewCaseRepository(db *gorm.DB) CaseRepository {
+	return &caseRepository{db: db}
+}
+
+// Create creates a new case
+func (r *caseRepository) Create(ctx context.Context, c *models.Case) error {
+	return r.db.WithContext(ctx).Create(c).Error
+}
+
+// withTransactionCount selects cases together with their number of pins
+func withTransactionCount(db *gorm.DB) *gorm.DB {
+	return db.Select("cases.*, (SELECT COUNT(*) FROM case_pins WHERE case_pins.case_id = cases.id) AS transaction_count")
+}
+
+// GetByID gets a case by ID
+func (r *caseRepository) GetByID(ctx context.Context, id uint) (*models.Case, error) {
+	var c models.Case
+	err := r.db.WithContext(ctx).Scopes(withTransactionCount).First(&c, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetAllWithCount gets a page of cases, newest first, along with the total
+// number of cases matching the filters
+func (r *caseRepository) GetAllWithCount(ctx context.Context, filters models.CaseFilters) ([]models.Case, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Case{})
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	limit, offset := filters.Page()
+	var cases []models.Case
+	err := query.Scopes(withTransactionCount).Order("id DESC").Limit(limit).Offset(offset).Find(&cases).Error
+	return cases, total, err
+}
+
+// Close closes an open case with an outcome. It returns
+// gorm.ErrRecordNotFound when the case does not exist or is already closed.
+func (r *caseRepository) Close(ctx context.Context, id uint, outcome, resolution string, closedAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.Case{}).
+		Where("id = ? AND status = ?", id, models.CaseStatusOpen).
+		Updates(map[string]interface{}{
+			"status":     models.CaseStatusClosed,
+			"outcome":    outcome,
+			"resolution": resolution,
+			"closed_at":  closedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Pin pins transactions to a case. Transactions already pinned to it keep
+// their pin and note.
+func (r *caseRepository) Pin(ctx context.Context, caseID uint, transactionIDs []uint, note string) error {
+	pins := make([]models.CasePin, len(transactionIDs))
+	for i, transactionID := range transactionIDs {
+		pins[i] = models.CasePin{CaseID: caseID, TransactionID: transactionID, Note: note}
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&pins).Error
+}
+
+// Unpin removes a transaction from a case, reporting whether it was pinned
+func (r *caseRepository) Unpin(ctx context.Context, caseID, transactionID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Where("case_id = ? AND transaction_id = ?", caseID, transactionID).Delete(&models.CasePin{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetPinsWithCount gets a page of the transactions pinned to a case, most
+// recently pinned first, along with the number of pins
+func (r *caseRepository) GetPinsWithCount(ctx context.Context, caseID uint, filters models.CasePinFilters) ([]models.CasePin, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.CasePin{}).Where("case_id = ?", caseID)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	limit, offset := filters.Page()
+	var pins []models.CasePin
+	err := query.Preload("Transaction.Tags").
+		Order("created_at DESC").Order("transaction_id DESC").
+		Limit(limit).Offset(offset).
+		Find(&pins).Error
+	return pins, total, err
+}
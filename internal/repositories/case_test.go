@@ -0,0 +1,97 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupCaseDB(t *testing.T) *gorm.DB {
+	db := setupSQLiteDB(t, t.Name())
+	require.NoError(t, db.AutoMigrate(&models.Tag{}, &models.Case{}, &models.CasePin{}))
+	for i := 0; i < 3; i++ {
+		require.NoError(t, db.Create(&models.Transaction{UserID: 1, Amount: decimal.NewFromInt(10), Status: "success"}).Error)
+	}
+	return db
+}
+
+func TestCaseRepository_PinAndCount(t *testing.T) {
+	db := setupCaseDB(t)
+	repo := repositories.NewCaseRepository(db)
+
+	c := &models.Case{Name: "Card testing", Status: models.CaseStatusOpen}
+	require.NoError(t, repo.Create(context.Background(), c))
+	require.NoError(t, repo.Create(context.Background(), &models.Case{Name: "Empty", Status: models.CaseStatusOpen}))
+
+	require.NoError(t, repo.Pin(context.Background(), c.ID, []uint{1, 2}, "same card"))
+	// Pinning again keeps the first pin and its note
+	require.NoError(t, repo.Pin(context.Background(), c.ID, []uint{2, 3}, "same device"))
+
+	found, err := repo.GetByID(context.Background(), c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), found.TransactionCount)
+
+	cases, total, err := repo.GetAllWithCount(context.Background(), models.CaseFilters{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, cases, 2)
+	assert.Equal(t, "Empty", cases[0].Name)
+	assert.Zero(t, cases[0].TransactionCount)
+	assert.Equal(t, int64(3), cases[1].TransactionCount)
+
+	pins, total, err := repo.GetPinsWithCount(context.Background(), c.ID, models.CasePinFilters{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, pins, 2)
+	require.NotNil(t, pins[0].Transaction)
+	notes := map[uint]string{}
+	for _, pin := range pins {
+		notes[pin.Transaction.ID] = pin.Note
+	}
+	pins, _, err = repo.GetPinsWithCount(context.Background(), c.ID, models.CasePinFilters{Limit: 2, Offset: 2})
+	require.NoError(t, err)
+	require.Len(t, pins, 1)
+	notes[pins[0].Transaction.ID] = pins[0].Note
+	assert.Equal(t, map[uint]string{1: "same card", 2: "same card", 3: "same device"}, notes)
+
+	removed, err := repo.Unpin(context.Background(), c.ID, 2)
+	assert.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = repo.Unpin(context.Background(), c.ID, 2)
+	assert.NoError(t, err)
+	assert.False(t, removed)
+}
+
+func TestCaseRepository_Close(t *testing.T) {
+	db := setupCaseDB(t)
+	repo := repositories.NewCaseRepository(db)
+
+	c := &models.Case{Name: "Refund abuse", Status: models.CaseStatusOpen}
+	require.NoError(t, repo.Create(context.Background(), c))
+
+	closedAt := time.Date(2025, 6, 28, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.Close(context.Background(), c.ID, models.CaseOutcomeConfirmed, "Account blocked", closedAt))
+	assert.ErrorIs(t, repo.Close(context.Background(), c.ID, models.CaseOutcomeDismissed, "", closedAt), gorm.ErrRecordNotFound)
+	assert.ErrorIs(t, repo.Close(context.Background(), 42, models.CaseOutcomeDismissed, "", closedAt), gorm.ErrRecordNotFound)
+
+	found, err := repo.GetByID(context.Background(), c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CaseStatusClosed, found.Status)
+	assert.Equal(t, models.CaseOutcomeConfirmed, found.Outcome)
+	assert.Equal(t, "Account blocked", found.Resolution)
+	require.NotNil(t, found.ClosedAt)
+	assert.True(t, closedAt.Equal(*found.ClosedAt))
+
+	open, total, err := repo.GetAllWithCount(context.Background(), models.CaseFilters{Status: models.CaseStatusOpen})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, open)
+}
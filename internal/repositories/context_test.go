@@ -0,0 +1,53 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"interview/internal/database"
+	"interview/internal/models"
+	"interview/internal/repositories"
+	"interview/internal/requestid"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestTransactionRepository_QueryLogCarriesRequestID(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	queryLogger := database.NewQueryLogger(time.Nanosecond, false)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: queryLogger})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Transaction{}))
+
+	repo := repositories.NewTransactionRepository(db)
+	transaction := &models.Transaction{UserID: 7, Amount: decimal.NewFromInt(10), Status: "success"}
+	require.NoError(t, repo.Create(context.Background(), transaction))
+	hook.Reset()
+
+	_, err = repo.GetByID(requestid.NewContext(context.Background(), "req-1"), transaction.ID)
+	require.NoError(t, err)
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, "Slow query", entry.Message)
+	assert.Equal(t, "req-1", entry.Data[requestid.Key])
+}
+
+func TestTransactionRepository_CancelledContext(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.GetAll(ctx, models.TransactionFilters{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"interview/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrTransactionChanged is returned when applying a correction to a
+// transaction that changed since the correction was requested
+var ErrTransactionChanged = errors.New("transaction changed since the correction was requested")
+
+// CorrectionRepository interface defines transaction correction repository methods
+type CorrectionRepository interface {
+	Create(ctx context.Context, correction *models.Correction) error
+	GetByID(ctx context.Context, id uint) (*models.Correction, error)
+	GetByTransactionID(ctx context.Context, transactionID uint) ([]models.Correction, error)
+	Apply(ctx context.Context, correction *models.Correction, approvedBy string, appliedAt time.Time) error
+}
+
+// correctionRepository implements CorrectionRepository interface
+type correctionRepository struct {
+	db *gorm.DB
+}
+
+// NewCorrectionRepository creates a new correction repository
+func NewCorrectionRepository(db *gorm.DB) CorrectionRepository {
+	return &correctionRepository{db: db}
+}
+
+// Create creates a new correction
+func (r *correctionRepository) Create(ctx context.Context, correction *models.Correction) error {
+	return r.db.WithContext(ctx).Create(correction).Error
+}
+
+// GetByID gets a correction by ID
+func (r *correctionRepository) GetByID(ctx context.Context, id uint) (*models.Correction, error) {
+	var correction models.Correction
+	err := r.db.WithContext(ctx).First(&correction, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &correction, nil
+}
+
+// GetByTransactionID gets the corrections of a transaction, oldest first
+func (r *correctionRepository) GetByTransactionID(ctx context.Context, transactionID uint) ([]models.Correction, error) {
+	var corrections []models.Correction
+	err := r.db.WithContext(ctx).Where("transaction_id = ?", transactionID).Order("id").Find(&corrections).Error
+	return corrections, err
+}
+
+// Apply marks a pending correction applied and changes its transaction to
+// the corrected user and amount in one database transaction. It returns
+// gorm.ErrRecordNotFound when the correction is no longer pending and
+// ErrTransactionChanged when the transaction is no longer at the version the
+// correction was requested for.
+func (r *correctionRepository) Apply(ctx context.Context, correction *models.Correction, approvedBy string, appliedAt time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Correction{}).
+			Where("id = ? AND status = ?", correction.ID, models.CorrectionStatusPending).
+			Updates(map[string]interface{}{
+				"status":      models.CorrectionStatusApplied,
+				"approved_by": approvedBy,
+				"applied_at":  appliedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		result = tx.Model(&models.Transaction{}).
+			Where("id = ? AND version = ?", correction.TransactionID, correction.TransactionVersion).
+			Updates(withNextVersion(map[string]interface{}{
+				"user_id": correction.NewUserID,
+				"amount":  correction.NewAmount,
+			}))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTransactionChanged
+		}
+		return nil
+	})
+}
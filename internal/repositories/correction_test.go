@@ -0,0 +1,92 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupCorrectionDB(t *testing.T) (*gorm.DB, *models.Transaction) {
+	db := setupSQLiteDB(t, t.Name())
+	require.NoError(t, db.AutoMigrate(&models.Correction{}))
+	transaction := &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(1000), Status: "success"}
+	require.NoError(t, db.Create(transaction).Error)
+	require.NoError(t, db.First(transaction, transaction.ID).Error)
+	return db, transaction
+}
+
+func newCorrection(transaction *models.Transaction) *models.Correction {
+	return &models.Correction{
+		TransactionID:      transaction.ID,
+		TransactionVersion: transaction.Version,
+		Status:             models.CorrectionStatusPending,
+		Reason:             "Amount keyed in cents",
+		RequestedBy:        "alice",
+		OldUserID:          transaction.UserID,
+		NewUserID:          2,
+		OldAmount:          transaction.Amount,
+		NewAmount:          decimal.NewFromInt(10),
+		ExpiresAt:          time.Now().Add(time.Hour),
+	}
+}
+
+func TestCorrectionRepository_Apply(t *testing.T) {
+	db, transaction := setupCorrectionDB(t)
+	repo := repositories.NewCorrectionRepository(db)
+
+	correction := newCorrection(transaction)
+	require.NoError(t, repo.Create(context.Background(), correction))
+	require.NoError(t, repo.Create(context.Background(), newCorrection(transaction)))
+
+	appliedAt := time.Now()
+	require.NoError(t, repo.Apply(context.Background(), correction, "bob", appliedAt))
+
+	var corrected models.Transaction
+	require.NoError(t, db.First(&corrected, transaction.ID).Error)
+	assert.Equal(t, uint(2), corrected.UserID)
+	assert.True(t, decimal.NewFromInt(10).Equal(corrected.Amount))
+	assert.Equal(t, transaction.Version+1, corrected.Version)
+
+	found, err := repo.GetByID(context.Background(), correction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CorrectionStatusApplied, found.Status)
+	assert.Equal(t, "bob", found.ApprovedBy)
+	require.NotNil(t, found.AppliedAt)
+	assert.True(t, decimal.NewFromInt(1000).Equal(found.OldAmount))
+
+	// Applying again finds no pending correction
+	assert.ErrorIs(t, repo.Apply(context.Background(), correction, "bob", appliedAt), gorm.ErrRecordNotFound)
+
+	corrections, err := repo.GetByTransactionID(context.Background(), transaction.ID)
+	require.NoError(t, err)
+	require.Len(t, corrections, 2)
+	assert.Equal(t, correction.ID, corrections[0].ID)
+}
+
+func TestCorrectionRepository_ApplyTransactionChanged(t *testing.T) {
+	db, transaction := setupCorrectionDB(t)
+	repo := repositories.NewCorrectionRepository(db)
+
+	correction := newCorrection(transaction)
+	require.NoError(t, repo.Create(context.Background(), correction))
+	require.NoError(t, db.Model(&models.Transaction{}).Where("id = ?", transaction.ID).
+		Updates(map[string]interface{}{"status": "refunded", "version": gorm.Expr("version + 1")}).Error)
+
+	assert.ErrorIs(t, repo.Apply(context.Background(), correction, "bob", time.Now()), repositories.ErrTransactionChanged)
+
+	// The correction stays pending and the transaction keeps its amount
+	found, err := repo.GetByID(context.Background(), correction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CorrectionStatusPending, found.Status)
+	var unchanged models.Transaction
+	require.NoError(t, db.First(&unchanged, transaction.ID).Error)
+	assert.True(t, decimal.NewFromInt(1000).Equal(unchanged.Amount))
+}
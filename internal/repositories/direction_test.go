@@ -0,0 +1,39 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionRepository_Directions(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	for _, transaction := range []models.Transaction{
+		{UserID: 1, Amount: decimal.NewFromInt(100), Direction: models.TransactionDirectionDebit, Status: "success"},
+		{UserID: 1, Amount: decimal.NewFromInt(40), Direction: models.TransactionDirectionDebit, Status: "success"},
+		{UserID: 1, Amount: decimal.NewFromInt(250), Direction: models.TransactionDirectionCredit, Status: "success"},
+		{UserID: 1, Amount: decimal.NewFromInt(999), Direction: models.TransactionDirectionCredit, Status: "pending"},
+	} {
+		transaction := transaction
+		require.NoError(t, repo.Create(context.Background(), &transaction))
+	}
+
+	credits, err := repo.GetAll(context.Background(), models.TransactionFilters{Direction: models.TransactionDirectionCredit})
+	require.NoError(t, err)
+	assert.Len(t, credits, 2)
+
+	totals, err := repo.GetDirectionTotals(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, totals.Debit.Count)
+	assert.True(t, decimal.NewFromInt(140).Equal(totals.Debit.Amount))
+	assert.Equal(t, 1, totals.Credit.Count)
+	assert.True(t, decimal.NewFromInt(250).Equal(totals.Credit.Amount))
+}
@@ -0,0 +1,52 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionRepository_AmountDistribution(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	from := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	// Stored out of order, so the percentiles depend on the sort
+	for _, i := range []int64{7, 20, 1, 13, 2, 19, 8, 14, 3, 11, 16, 5, 10, 4, 17, 6, 12, 18, 9, 15} {
+		transaction := models.Transaction{UserID: 1, Amount: decimal.NewFromInt(i * 10), Status: "success", CreatedAt: from.Add(time.Duration(i) * time.Hour)}
+		require.NoError(t, repo.Create(context.Background(), &transaction))
+	}
+	for _, transaction := range []models.Transaction{
+		{UserID: 1, Amount: decimal.NewFromInt(5000), Status: "pending", CreatedAt: from},
+		{UserID: 1, Amount: decimal.NewFromInt(5000), Status: "success", CreatedAt: from, IsTest: true},
+	} {
+		transaction := transaction
+		require.NoError(t, repo.Create(context.Background(), &transaction))
+	}
+
+	distribution, err := repo.GetAmountDistribution(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 20, distribution.Count)
+	assert.True(t, decimal.NewFromInt(10).Equal(distribution.Min), distribution.Min.String())
+	assert.True(t, decimal.NewFromInt(200).Equal(distribution.Max), distribution.Max.String())
+	assert.True(t, decimal.NewFromInt(100).Equal(distribution.Median), distribution.Median.String())
+	assert.True(t, decimal.NewFromInt(190).Equal(distribution.P95), distribution.P95.String())
+
+	// The first three hours hold 10, 20 and 30
+	distribution, err = repo.GetAmountDistributionBetween(context.Background(), from, from.Add(3*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 3, distribution.Count)
+	assert.True(t, decimal.NewFromInt(20).Equal(distribution.Median), distribution.Median.String())
+	assert.True(t, decimal.NewFromInt(30).Equal(distribution.P95), distribution.P95.String())
+
+	distribution, err = repo.GetAmountDistributionBetween(context.Background(), from.AddDate(1, 0, 0), from.AddDate(2, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, models.AmountDistribution{}, distribution)
+}
@@ -0,0 +1,159 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionRepository_GetByIDs(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(10), Status: "pending"}))
+	}
+
+	transactions, err := repo.GetByIDs(context.Background(), []uint{3, 1, 42})
+	assert.NoError(t, err)
+	assert.Len(t, transactions, 2)
+
+	ids := []uint{transactions[0].ID, transactions[1].ID}
+	assert.ElementsMatch(t, []uint{1, 3}, ids)
+}
+
+func TestTransactionRepository_GetAllWithCount(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(10), Status: "success"}))
+	}
+	assert.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 2, Amount: decimal.NewFromInt(10), Status: "success"}))
+
+	transactions, total, err := repo.GetAllWithCount(context.Background(), models.TransactionFilters{UserID: 1, Limit: 2, Offset: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+	assert.Len(t, transactions, 1)
+}
+
+func TestTransactionRepository_GetAllDateRange(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for day := 0; day < 5; day++ {
+		assert.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(10), Status: "success", CreatedAt: base.AddDate(0, 0, day)}))
+	}
+
+	from := base.AddDate(0, 0, 1)
+	to := base.AddDate(0, 0, 3)
+	transactions, total, err := repo.GetAllWithCount(context.Background(), models.TransactionFilters{From: &from, To: &to})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, transactions, 3)
+
+	transactions, err = repo.GetAll(context.Background(), models.TransactionFilters{From: &to})
+	assert.NoError(t, err)
+	assert.Len(t, transactions, 2)
+}
+
+func TestTransactionRepository_GetAllAmountRange(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	for _, amount := range []string{"5.50", "10.00", "99.99", "100.00", "250.75"} {
+		assert.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.RequireFromString(amount), Status: "success"}))
+	}
+
+	min := decimal.RequireFromString("10")
+	max := decimal.RequireFromString("100")
+	transactions, total, err := repo.GetAllWithCount(context.Background(), models.TransactionFilters{MinAmount: &min, MaxAmount: &max})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, transactions, 3)
+
+	transactions, err = repo.GetAll(context.Background(), models.TransactionFilters{MinAmount: &max})
+	assert.NoError(t, err)
+	assert.Len(t, transactions, 2)
+}
+
+func TestTransactionRepository_GetAllSorted(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	for _, amount := range []int64{30, 10, 20} {
+		assert.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(amount), Status: "success"}))
+	}
+
+	amounts := func(transactions []models.Transaction) []string {
+		var result []string
+		for _, transaction := range transactions {
+			result = append(result, transaction.Amount.String())
+		}
+		return result
+	}
+
+	transactions, err := repo.GetAll(context.Background(), models.TransactionFilters{SortBy: "amount", Order: "asc"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10", "20", "30"}, amounts(transactions))
+
+	transactions, err = repo.GetAll(context.Background(), models.TransactionFilters{SortBy: "amount"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"30", "20", "10"}, amounts(transactions))
+}
+
+func TestTransactionRepository_CreateBatch(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	transactions := make([]models.Transaction, 250)
+	for i := range transactions {
+		transactions[i] = models.Transaction{UserID: 1, Amount: decimal.NewFromInt(int64(i + 1)), Status: "pending"}
+	}
+
+	assert.NoError(t, repo.CreateBatch(context.Background(), transactions))
+	assert.NotZero(t, transactions[0].ID)
+	assert.NotZero(t, transactions[249].ID)
+
+	_, total, err := repo.GetAllWithCount(context.Background(), models.TransactionFilters{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(250), total)
+}
+
+func TestTransactionRepository_Each(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	for _, amount := range []int64{30, 10, 20} {
+		assert.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(amount), Status: "success", Metadata: models.TransactionMetadata{"channel": "web"}}))
+	}
+	assert.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 2, Amount: decimal.NewFromInt(40), Status: "success"}))
+
+	// Pagination is ignored, the sort order is kept
+	var amounts []string
+	err := repo.Each(context.Background(), models.TransactionFilters{UserID: 1, SortBy: "amount", Order: "asc", Limit: 1, Offset: 1}, func(transaction *models.Transaction) error {
+		amounts = append(amounts, transaction.Amount.String())
+		assert.Equal(t, "web", transaction.Metadata["channel"])
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10", "20", "30"}, amounts)
+
+	// An error from fn stops the cursor
+	calls := 0
+	stop := errors.New("stop")
+	err = repo.Each(context.Background(), models.TransactionFilters{}, func(transaction *models.Transaction) error {
+		calls++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+}
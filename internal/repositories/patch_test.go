@@ -0,0 +1,94 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestTransactionRepository_UpdateDescriptionAndMetadata(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	transaction := &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(10), Status: "pending"}
+	assert.NoError(t, repo.Create(context.Background(), transaction))
+
+	loaded, err := repo.GetByID(context.Background(), transaction.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, loaded.Description)
+	assert.Nil(t, loaded.Metadata)
+
+	assert.NoError(t, repo.Update(context.Background(), transaction.ID, map[string]interface{}{
+		"description": "June invoice",
+		"metadata":    models.TransactionMetadata{"invoice": "INV-1"},
+	}))
+
+	loaded, err = repo.GetByID(context.Background(), transaction.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "June invoice", loaded.Description)
+	assert.Equal(t, models.TransactionMetadata{"invoice": "INV-1"}, loaded.Metadata)
+
+	assert.NoError(t, repo.Update(context.Background(), transaction.ID, map[string]interface{}{"metadata": models.TransactionMetadata{}}))
+
+	loaded, err = repo.GetByID(context.Background(), transaction.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, loaded.Metadata)
+}
+
+func TestTransactionRepository_FilterByMetadata(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	for _, transaction := range []models.Transaction{
+		{UserID: 1, Amount: decimal.NewFromInt(10), Status: "pending", Metadata: models.TransactionMetadata{"invoice": "INV-1", "region": "eu"}},
+		{UserID: 1, Amount: decimal.NewFromInt(20), Status: "pending", Metadata: models.TransactionMetadata{"invoice": "INV-2", "region": "eu"}},
+		{UserID: 1, Amount: decimal.NewFromInt(30), Status: "pending"},
+	} {
+		transaction := transaction
+		assert.NoError(t, repo.Create(context.Background(), &transaction))
+	}
+
+	found, total, err := repo.GetAllWithCount(context.Background(), models.TransactionFilters{Metadata: map[string]string{"region": "eu"}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, found, 2)
+
+	found, err = repo.GetAll(context.Background(), models.TransactionFilters{Metadata: map[string]string{"region": "eu", "invoice": "INV-2"}})
+	assert.NoError(t, err)
+	if assert.Len(t, found, 1) {
+		assert.Equal(t, "INV-2", found[0].Metadata["invoice"])
+	}
+
+	found, err = repo.GetAll(context.Background(), models.TransactionFilters{Metadata: map[string]string{"region": "us"}})
+	assert.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestTransactionRepository_UpdateVersion(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	transaction := &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(10), Status: "pending", Version: 1}
+	assert.NoError(t, repo.Create(context.Background(), transaction))
+
+	// Every update moves the version on
+	assert.NoError(t, repo.Update(context.Background(), transaction.ID, map[string]interface{}{"description": "June invoice"}))
+	loaded, err := repo.GetByID(context.Background(), transaction.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), loaded.Version)
+
+	err = repo.UpdateVersion(context.Background(), transaction.ID, 1, map[string]interface{}{"status": "success"})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	assert.NoError(t, repo.UpdateVersion(context.Background(), transaction.ID, 2, map[string]interface{}{"status": "success"}))
+	loaded, err = repo.GetByID(context.Background(), transaction.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "success", loaded.Status)
+	assert.Equal(t, uint(3), loaded.Version)
+}
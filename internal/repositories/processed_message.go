@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+	"interview/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ProcessedMessageRepository interface defines processed message repository methods
+type ProcessedMessageRepository interface {
+	GetByKey(ctx context.Context, key string) (*models.ProcessedMessage, error)
+}
+
+// processedMessageRepository implements ProcessedMessageRepository interface
+type processedMessageRepository struct {
+	db *gorm.DB
+}
+
+// NewProcessedMessageRepository creates a new processed message repository
+func NewProcessedMessageRepository(db *gorm.DB) ProcessedMessageRepository {
+	return &processedMessageRepository{db: db}
+}
+
+// GetByKey gets the record of the consumed message with the given key
+func (r *processedMessageRepository) GetByKey(ctx context.Context, key string) (*models.ProcessedMessage, error) {
+	var message models.ProcessedMessage
+	err := r.db.WithContext(ctx).First(&message, "message_key = ?", key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
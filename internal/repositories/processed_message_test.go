@@ -0,0 +1,28 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestProcessedMessageRepository_GetByKey(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	require.NoError(t, db.AutoMigrate(&models.ProcessedMessage{}))
+	require.NoError(t, db.Create(&models.ProcessedMessage{MessageKey: "cmd-1", TransactionID: 7}).Error)
+
+	repo := repositories.NewProcessedMessageRepository(db)
+
+	message, err := repo.GetByKey(context.Background(), "cmd-1")
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), message.TransactionID)
+
+	_, err = repo.GetByKey(context.Background(), "cmd-2")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
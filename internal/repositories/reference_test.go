@@ -0,0 +1,51 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestTransactionRepository_ReferenceID(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	reference := "ORD-1"
+	require.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(10), Status: "pending", ReferenceID: &reference}))
+	// Transactions without a reference ID do not collide
+	require.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(20), Status: "pending"}))
+	require.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(30), Status: "pending"}))
+
+	err := repo.Create(context.Background(), &models.Transaction{UserID: 2, Amount: decimal.NewFromInt(40), Status: "pending", ReferenceID: &reference})
+	assert.Error(t, err)
+
+	transaction, err := repo.GetByReferenceID(context.Background(), "ORD-1")
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(10).Equal(transaction.Amount))
+
+	_, err = repo.GetByReferenceID(context.Background(), "ORD-2")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestTransactionRepository_GetByReferenceIDs(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	for _, reference := range []string{"ORD-1", "ORD-2", "ORD-3"} {
+		reference := reference
+		require.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(10), Status: "pending", ReferenceID: &reference}))
+	}
+	require.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(20), Status: "pending"}))
+
+	transactions, err := repo.GetByReferenceIDs(context.Background(), []string{"ORD-3", "ORD-1", "ORD-42"})
+	require.NoError(t, err)
+	require.Len(t, transactions, 2)
+	assert.ElementsMatch(t, []string{"ORD-1", "ORD-3"}, []string{*transactions[0].ReferenceID, *transactions[1].ReferenceID})
+}
@@ -0,0 +1,70 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestTransactionRepository_Refund(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	original := &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(10), Status: "success"}
+	require.NoError(t, repo.Create(context.Background(), original))
+
+	refund := &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(10), Type: models.TransactionTypeRefund, Direction: models.TransactionDirectionCredit, Status: "success", ParentID: &original.ID}
+	require.NoError(t, repo.Refund(context.Background(), original.ID, refund))
+	assert.NotZero(t, refund.ID)
+
+	stored, err := repo.GetByID(context.Background(), original.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "refunded", stored.Status)
+
+	stored, err = repo.GetByID(context.Background(), refund.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.ParentID)
+	assert.Equal(t, original.ID, *stored.ParentID)
+
+	// A second refund finds the original no longer successful and creates nothing
+	again := &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(10), Type: models.TransactionTypeRefund, Status: "success", ParentID: &original.ID}
+	assert.ErrorIs(t, repo.Refund(context.Background(), original.ID, again), gorm.ErrRecordNotFound)
+
+	var refunds int64
+	require.NoError(t, db.Model(&models.Transaction{}).Where("parent_id = ?", original.ID).Count(&refunds).Error)
+	assert.Equal(t, int64(1), refunds)
+}
+
+func TestTransactionRepository_RefundsLeaveSuccessfulVolume(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	kept := &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(25), Status: "success"}
+	original := &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(10), Status: "success"}
+	require.NoError(t, repo.Create(context.Background(), kept))
+	require.NoError(t, repo.Create(context.Background(), original))
+	refund := &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(10), Type: models.TransactionTypeRefund, Direction: models.TransactionDirectionCredit, Status: "success", ParentID: &original.ID}
+	require.NoError(t, repo.Refund(context.Background(), original.ID, refund))
+
+	// Neither the refunded original nor its refund is successful volume
+	count, amount, err := repo.GetTodaySuccessful(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.True(t, decimal.NewFromInt(25).Equal(amount), amount.String())
+
+	totals, err := repo.GetDirectionTotals(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, totals.Debit.Count)
+	assert.Zero(t, totals.Credit.Count)
+
+	distribution, err := repo.GetAmountDistribution(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, distribution.Count)
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"interview/internal/models"
+)
+
+// readResult is the outcome of a single read attempt
+type readResult struct {
+	transaction *models.Transaction
+	err         error
+}
+
+// hedgedGetByID reads from the replica and, if it hasn't answered within
+// the hedge delay, also from the primary. The first successful response wins;
+// if both fail the primary's error is returned.
+func (r *transactionRepository) hedgedGetByID(ctx context.Context, id uint) (*models.Transaction, error) {
+	if r.options.HedgeDelay <= 0 {
+		return getByID(r.replica.WithContext(ctx), id)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	replicaResult := make(chan readResult, 1)
+	primaryResult := make(chan readResult, 1)
+
+	go func() {
+		tx, err := getByID(r.replica.WithContext(ctx), id)
+		replicaResult <- readResult{tx, err}
+	}()
+
+	timer := time.NewTimer(r.options.HedgeDelay)
+	defer timer.Stop()
+
+	startPrimary := func() {
+		go func() {
+			tx, err := getByID(r.db.WithContext(ctx), id)
+			primaryResult <- readResult{tx, err}
+		}()
+	}
+
+	select {
+	case res := <-replicaResult:
+		if res.err == nil {
+			return res.transaction, nil
+		}
+		// Replica failed (or is lagging) before the hedge fired, fall back now
+		startPrimary()
+		res = <-primaryResult
+		return res.transaction, res.err
+	case <-timer.C:
+		startPrimary()
+	}
+
+	var lastErr error
+	for pending := 2; pending > 0; pending-- {
+		select {
+		case res := <-replicaResult:
+			if res.err == nil {
+				return res.transaction, nil
+			}
+			replicaResult = nil
+		case res := <-primaryResult:
+			if res.err == nil {
+				return res.transaction, nil
+			}
+			lastErr = res.err
+			primaryResult = nil
+		}
+	}
+
+	return nil, lastErr
+}
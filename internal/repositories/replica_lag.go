@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// LagProbe measures how far the replica is behind the primary
+type LagProbe func() (time.Duration, error)
+
+// replicationStatusQueries are tried in order: MySQL 8.0.22 renamed SHOW
+// SLAVE STATUS to SHOW REPLICA STATUS and 8.4 removed the old statement
+var replicationStatusQueries = []string{"SHOW REPLICA STATUS", "SHOW SLAVE STATUS"}
+
+// lagColumns are the lag columns reported by SHOW REPLICA STATUS and SHOW
+// SLAVE STATUS respectively
+var lagColumns = []string{"Seconds_Behind_Source", "Seconds_Behind_Master"}
+
+// ReplicationStatusProbe measures lag with SHOW REPLICA STATUS on the
+// replica, falling back to SHOW SLAVE STATUS on servers older than 8.0.22
+func ReplicationStatusProbe(replica *gorm.DB) LagProbe {
+	return func() (time.Duration, error) {
+		var err error
+		for _, query := range replicationStatusQueries {
+			var lag time.Duration
+			var found bool
+			lag, found, err = probeReplicationStatus(replica, query)
+			if found {
+				return lag, err
+			}
+		}
+		return 0, fmt.Errorf("failed to get replication status: %v", err)
+	}
+}
+
+// probeReplicationStatus runs a replication status query. found is false
+// when the server does not support the statement.
+func probeReplicationStatus(replica *gorm.DB, query string) (lag time.Duration, found bool, err error) {
+	rows, err := replica.Raw(query).Rows()
+	if err != nil {
+		return 0, false, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return 0, true, errors.New("replication is not configured on replica")
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, true, err
+	}
+	values := make([]sql.RawBytes, len(columns))
+	scanArgs := make([]interface{}, len(columns))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+	if err := rows.Scan(scanArgs...); err != nil {
+		return 0, true, err
+	}
+
+	lag, err = replicationLag(columns, values)
+	return lag, true, err
+}
+
+// replicationLag reads the lag from a replication status row
+func replicationLag(columns []string, values []sql.RawBytes) (time.Duration, error) {
+	for i, column := range columns {
+		for _, lagColumn := range lagColumns {
+			if column != lagColumn {
+				continue
+			}
+			// NULL means the replication threads are not running
+			if values[i] == nil {
+				return 0, errors.New("replication is stopped on replica")
+			}
+			seconds, err := strconv.Atoi(string(values[i]))
+			if err != nil {
+				return 0, fmt.Errorf("invalid %s: %v", column, err)
+			}
+			return time.Duration(seconds) * time.Second, nil
+		}
+	}
+
+	return 0, errors.New("replication lag not reported by replica")
+}
+
+// ReplicaLagMonitor tracks replica lag and reports whether the replica is
+// fresh enough to serve reads
+type ReplicaLagMonitor struct {
+	probe   LagProbe
+	maxLag  time.Duration
+	healthy atomic.Bool
+}
+
+// NewReplicaLagMonitor creates a new replica lag monitor. The replica is
+// considered unhealthy until the first successful check.
+func NewReplicaLagMonitor(probe LagProbe, maxLag time.Duration) *ReplicaLagMonitor {
+	return &ReplicaLagMonitor{probe: probe, maxLag: maxLag}
+}
+
+// Healthy reports whether the last check found the replica within maxLag
+func (m *ReplicaLagMonitor) Healthy() bool {
+	return m.healthy.Load()
+}
+
+// Check probes the replica and updates its health. Probe errors mark the
+// replica unhealthy so reads fall back to the primary.
+func (m *ReplicaLagMonitor) Check() error {
+	lag, err := m.probe()
+	if err != nil {
+		m.healthy.Store(false)
+		return err
+	}
+
+	healthy := lag <= m.maxLag
+	if m.healthy.Swap(healthy) != healthy {
+		logrus.WithFields(logrus.Fields{
+			"lag":     lag,
+			"max_lag": m.maxLag,
+			"healthy": healthy,
+		}).Warn("Replica health changed")
+	}
+	return nil
+}
+
+// Start checks the replica every interval until the returned stop function is called
+func (m *ReplicaLagMonitor) Start(interval time.Duration) (stop func()) {
+	if err := m.Check(); err != nil {
+		logrus.WithError(err).Warn("Failed to check replica lag")
+	}
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := m.Check(); err != nil {
+					logrus.WithError(err).Warn("Failed to check replica lag")
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicationLag(t *testing.T) {
+	// SHOW REPLICA STATUS on MySQL 8.0.22 and later
+	lag, err := replicationLag([]string{"Replica_IO_State", "Seconds_Behind_Source"}, []sql.RawBytes{sql.RawBytes("Waiting"), sql.RawBytes("3")})
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Second, lag)
+
+	// SHOW SLAVE STATUS on older servers
+	lag, err = replicationLag([]string{"Slave_IO_State", "Seconds_Behind_Master"}, []sql.RawBytes{sql.RawBytes("Waiting"), sql.RawBytes("7")})
+	require.NoError(t, err)
+	assert.Equal(t, 7*time.Second, lag)
+
+	_, err = replicationLag([]string{"Seconds_Behind_Source"}, []sql.RawBytes{nil})
+	assert.EqualError(t, err, "replication is stopped on replica")
+
+	_, err = replicationLag([]string{"Replica_IO_State"}, []sql.RawBytes{sql.RawBytes("Waiting")})
+	assert.EqualError(t, err, "replication lag not reported by replica")
+}
@@ -0,0 +1,122 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupSQLiteDB opens a named in-memory database so primary and replica stay separate
+func setupSQLiteDB(t *testing.T, name string) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Transaction{}))
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM transactions")
+	})
+	return db
+}
+
+func TestTransactionRepository_GetByIDFromReplica(t *testing.T) {
+	primary := setupSQLiteDB(t, t.Name()+"_primary")
+	replica := setupSQLiteDB(t, t.Name()+"_replica")
+
+	replica.Create(&models.Transaction{ID: 1, UserID: 1, Amount: decimal.NewFromFloat(10), Status: "success"})
+
+	repo := repositories.NewTransactionRepositoryWithReplica(primary, replica, repositories.ReplicaOptions{})
+
+	tx, err := repo.GetByID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "success", tx.Status)
+}
+
+func TestTransactionRepository_GetByIDFromPrimarySkipsReplica(t *testing.T) {
+	primary := setupSQLiteDB(t, t.Name()+"_primary")
+	replica := setupSQLiteDB(t, t.Name()+"_replica")
+
+	// The replica still has the row as it was before an update
+	primary.Create(&models.Transaction{ID: 1, UserID: 1, Amount: decimal.NewFromFloat(10), Status: "success"})
+	replica.Create(&models.Transaction{ID: 1, UserID: 1, Amount: decimal.NewFromFloat(10), Status: "pending"})
+
+	repo := repositories.NewTransactionRepositoryWithReplica(primary, replica, repositories.ReplicaOptions{})
+
+	tx, err := repo.GetByIDFromPrimary(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "success", tx.Status)
+}
+
+func TestTransactionRepository_GetByIDHedgedFallsBackToPrimary(t *testing.T) {
+	primary := setupSQLiteDB(t, t.Name()+"_primary")
+	replica := setupSQLiteDB(t, t.Name()+"_replica")
+
+	// The replica hasn't caught up with the write yet
+	primary.Create(&models.Transaction{ID: 1, UserID: 1, Amount: decimal.NewFromFloat(10), Status: "pending"})
+
+	repo := repositories.NewTransactionRepositoryWithReplica(primary, replica, repositories.ReplicaOptions{HedgeDelay: 10 * time.Millisecond})
+
+	tx, err := repo.GetByID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), tx.ID)
+}
+
+func TestTransactionRepository_GetByIDHedgedNotFound(t *testing.T) {
+	primary := setupSQLiteDB(t, t.Name()+"_primary")
+	replica := setupSQLiteDB(t, t.Name()+"_replica")
+
+	repo := repositories.NewTransactionRepositoryWithReplica(primary, replica, repositories.ReplicaOptions{HedgeDelay: 10 * time.Millisecond})
+
+	tx, err := repo.GetByID(context.Background(), 42)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, tx)
+}
+
+func TestTransactionRepository_GetAllRoutesToPrimaryWhenReplicaLags(t *testing.T) {
+	primary := setupSQLiteDB(t, t.Name()+"_primary")
+	replica := setupSQLiteDB(t, t.Name()+"_replica")
+
+	primary.Create(&models.Transaction{UserID: 1, Amount: decimal.NewFromFloat(10), Status: "pending"})
+
+	lag := time.Minute
+	monitor := repositories.NewReplicaLagMonitor(func() (time.Duration, error) {
+		return lag, nil
+	}, 5*time.Second)
+
+	repo := repositories.NewTransactionRepositoryWithReplica(primary, replica, repositories.ReplicaOptions{LagMonitor: monitor})
+
+	// Lagging replica, reads go to the primary
+	assert.NoError(t, monitor.Check())
+	assert.False(t, monitor.Healthy())
+	transactions, err := repo.GetAll(context.Background(), models.TransactionFilters{})
+	assert.NoError(t, err)
+	assert.Len(t, transactions, 1)
+
+	// Caught-up replica, reads go back to the replica
+	lag = time.Second
+	assert.NoError(t, monitor.Check())
+	assert.True(t, monitor.Healthy())
+	transactions, err = repo.GetAll(context.Background(), models.TransactionFilters{})
+	assert.NoError(t, err)
+	assert.Len(t, transactions, 0)
+}
+
+func TestReplicaLagMonitor_ProbeErrorMarksUnhealthy(t *testing.T) {
+	monitor := repositories.NewReplicaLagMonitor(func() (time.Duration, error) {
+		return 0, errors.New("replication is stopped on replica")
+	}, 5*time.Second)
+
+	assert.Error(t, monitor.Check())
+	assert.False(t, monitor.Healthy())
+}
@@ -0,0 +1,96 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionRepository_SummaryPeriod(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	from := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 16, 23, 59, 59, 0, time.UTC)
+	for _, transaction := range []models.Transaction{
+		{UserID: 1, Amount: decimal.NewFromInt(100), Direction: models.TransactionDirectionDebit, Status: "success", CreatedAt: from},
+		{UserID: 1, Amount: decimal.NewFromInt(250), Direction: models.TransactionDirectionCredit, Status: "success", CreatedAt: to},
+		{UserID: 1, Amount: decimal.NewFromInt(40), Direction: models.TransactionDirectionDebit, Status: "pending", CreatedAt: from.Add(time.Hour)},
+		{UserID: 1, Amount: decimal.NewFromInt(60), Direction: models.TransactionDirectionDebit, Status: "failed", CreatedAt: from.Add(time.Hour)},
+		{UserID: 1, Amount: decimal.NewFromInt(70), Direction: models.TransactionDirectionDebit, Status: "refunded", CreatedAt: from.Add(time.Hour)},
+		// Outside the period
+		{UserID: 1, Amount: decimal.NewFromInt(999), Direction: models.TransactionDirectionDebit, Status: "success", CreatedAt: from.Add(-time.Second)},
+		{UserID: 1, Amount: decimal.NewFromInt(999), Direction: models.TransactionDirectionCredit, Status: "pending", CreatedAt: to.Add(time.Second)},
+		// Test data
+		{UserID: 1, Amount: decimal.NewFromInt(999), Direction: models.TransactionDirectionDebit, Status: "success", CreatedAt: from, IsTest: true},
+	} {
+		transaction := transaction
+		require.NoError(t, repo.Create(context.Background(), &transaction))
+	}
+
+	count, amount, err := repo.GetSuccessfulBetween(context.Background(), from, to)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.True(t, decimal.NewFromInt(350).Equal(amount), amount.String())
+
+	counts, err := repo.GetStatusCountsBetween(context.Background(), from, to)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusCounts{Success: 2, Pending: 1, Failed: 1, Refunded: 1}, counts)
+
+	totals, err := repo.GetDirectionTotalsBetween(context.Background(), from, to)
+	require.NoError(t, err)
+	assert.Equal(t, 1, totals.Debit.Count)
+	assert.True(t, decimal.NewFromInt(100).Equal(totals.Debit.Amount))
+	assert.Equal(t, 1, totals.Credit.Count)
+	assert.True(t, decimal.NewFromInt(250).Equal(totals.Credit.Amount))
+
+	// An empty period has nothing to sum
+	count, amount, err = repo.GetSuccessfulBetween(context.Background(), to.AddDate(1, 0, 0), to.AddDate(2, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	assert.True(t, amount.IsZero())
+}
+
+func TestTransactionRepository_CurrencyTotals(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	now := time.Now()
+	for _, transaction := range []models.Transaction{
+		{UserID: 1, Amount: decimal.NewFromInt(100), Currency: "USD", Status: "success", CreatedAt: now},
+		{UserID: 2, Amount: decimal.RequireFromString("0.25"), Currency: "USD", Status: "success", CreatedAt: now},
+		{UserID: 1, Amount: decimal.NewFromInt(5000), Currency: "JPY", Status: "success", CreatedAt: now},
+		{UserID: 1, Amount: decimal.NewFromInt(40), Currency: "JPY", Status: "pending", CreatedAt: now},
+		// Test data
+		{UserID: 1, Amount: decimal.NewFromInt(999), Currency: "EUR", Status: "success", CreatedAt: now, IsTest: true},
+	} {
+		transaction := transaction
+		require.NoError(t, repo.Create(context.Background(), &transaction))
+	}
+
+	totals, err := repo.GetCurrencyTotals(context.Background())
+	require.NoError(t, err)
+	require.Len(t, totals, 2)
+	assert.Equal(t, "JPY", totals[0].Currency)
+	assert.Equal(t, 1, totals[0].Count)
+	assert.True(t, decimal.NewFromInt(5000).Equal(totals[0].Amount), totals[0].Amount.String())
+	assert.Equal(t, "USD", totals[1].Currency)
+	assert.Equal(t, 2, totals[1].Count)
+	assert.True(t, decimal.RequireFromString("100.25").Equal(totals[1].Amount), totals[1].Amount.String())
+
+	between, err := repo.GetCurrencyTotalsBetween(context.Background(), now.Add(-time.Minute), now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Len(t, between, 2)
+
+	// An empty period has no currencies
+	between, err = repo.GetCurrencyTotalsBetween(context.Background(), now.AddDate(1, 0, 0), now.AddDate(2, 0, 0))
+	require.NoError(t, err)
+	assert.Empty(t, between)
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"interview/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagRepository interface defines tag repository methods
+type TagRepository interface {
+	AddTags(ctx context.Context, transactionID uint, names []string) error
+	RemoveTag(ctx context.Context, transactionID uint, name string) (bool, error)
+	GetSummaries(ctx context.Context, limit int) ([]models.TagSummary, error)
+}
+
+// tagRepository implements TagRepository interface
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new tag repository
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// AddTags attaches the named tags to a transaction, creating tags that do not
+// exist yet. Tags the transaction already carries are left as they are.
+func (r *tagRepository) AddTags(ctx context.Context, transactionID uint, names []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tags := make([]models.Tag, len(names))
+		for i, name := range names {
+			tags[i] = models.Tag{Name: name}
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+			return err
+		}
+
+		// IDs are not filled in for tags that already existed
+		if err := tx.Where("name IN ?", names).Find(&tags).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Transaction{ID: transactionID}).Association("Tags").Append(tags)
+	})
+}
+
+// RemoveTag detaches the named tag from a transaction, reporting whether the
+// transaction carried it
+func (r *tagRepository) RemoveTag(ctx context.Context, transactionID uint, name string) (bool, error) {
+	result := r.db.WithContext(ctx).Exec(
+		"DELETE FROM transaction_tags WHERE transaction_id = ? AND tag_id IN (SELECT id FROM tags WHERE name = ?)",
+		transactionID, name,
+	)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetSummaries gets the most used tags with the number and total amount of
+// the transactions carrying them
+func (r *tagRepository) GetSummaries(ctx context.Context, limit int) ([]models.TagSummary, error) {
+	var summaries []models.TagSummary
+	err := r.db.WithContext(ctx).Table("transaction_tags").
+		Select("tags.name AS name, COUNT(*) AS count, COALESCE(SUM(transactions.amount), 0) AS total_amount").
+		Joins("JOIN tags ON tags.id = transaction_tags.tag_id").
+		Joins("JOIN transactions ON transactions.id = transaction_tags.transaction_id").
+		Scopes(liveData).
+		Group("tags.name").
+		Order("count DESC, name").
+		Limit(limit).
+		Scan(&summaries).Error
+	return summaries, err
+}
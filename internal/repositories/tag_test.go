@@ -0,0 +1,116 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupTagDB(t *testing.T) *gorm.DB {
+	db := setupSQLiteDB(t, t.Name())
+	require.NoError(t, db.AutoMigrate(&models.Tag{}))
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM transaction_tags")
+		db.Exec("DELETE FROM tags")
+	})
+	return db
+}
+
+func tagNames(transaction *models.Transaction) []string {
+	var names []string
+	for _, tag := range transaction.Tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+func TestTagRepository_AddAndRemoveTags(t *testing.T) {
+	db := setupTagDB(t)
+	transactions := repositories.NewTransactionRepository(db)
+	tags := repositories.NewTagRepository(db)
+
+	transaction := &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(10), Status: "pending"}
+	require.NoError(t, transactions.Create(context.Background(), transaction))
+
+	require.NoError(t, tags.AddTags(context.Background(), transaction.ID, []string{"refund", "vip"}))
+	// Adding a tag the transaction already carries is a no-op
+	require.NoError(t, tags.AddTags(context.Background(), transaction.ID, []string{"vip", "manual"}))
+
+	loaded, err := transactions.GetByID(context.Background(), transaction.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"refund", "vip", "manual"}, tagNames(loaded))
+
+	removed, err := tags.RemoveTag(context.Background(), transaction.ID, "vip")
+	require.NoError(t, err)
+	assert.True(t, removed)
+
+	removed, err = tags.RemoveTag(context.Background(), transaction.ID, "vip")
+	require.NoError(t, err)
+	assert.False(t, removed)
+
+	loaded, err = transactions.GetByID(context.Background(), transaction.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"refund", "manual"}, tagNames(loaded))
+}
+
+func TestTagRepository_FilterAndSummarize(t *testing.T) {
+	db := setupTagDB(t)
+	transactions := repositories.NewTransactionRepository(db)
+	tags := repositories.NewTagRepository(db)
+
+	first := &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(10), Status: "pending"}
+	second := &models.Transaction{UserID: 2, Amount: decimal.NewFromInt(25), Status: "success"}
+	untagged := &models.Transaction{UserID: 3, Amount: decimal.NewFromInt(40), Status: "success"}
+	for _, transaction := range []*models.Transaction{first, second, untagged} {
+		require.NoError(t, transactions.Create(context.Background(), transaction))
+	}
+	require.NoError(t, tags.AddTags(context.Background(), first.ID, []string{"refund", "vip"}))
+	require.NoError(t, tags.AddTags(context.Background(), second.ID, []string{"refund"}))
+
+	found, total, err := transactions.GetAllWithCount(context.Background(), models.TransactionFilters{Tag: "refund"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, found, 2)
+
+	found, err = transactions.GetAll(context.Background(), models.TransactionFilters{Tag: "vip"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, first.ID, found[0].ID)
+	assert.ElementsMatch(t, []string{"refund", "vip"}, tagNames(&found[0]))
+
+	summaries, err := tags.GetSummaries(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "refund", summaries[0].Name)
+	assert.Equal(t, 2, summaries[0].Count)
+	assert.True(t, decimal.NewFromInt(35).Equal(summaries[0].TotalAmount))
+	assert.Equal(t, "vip", summaries[1].Name)
+	assert.Equal(t, 1, summaries[1].Count)
+}
+
+func TestTransactionRepository_DeleteRemovesTags(t *testing.T) {
+	db := setupTagDB(t)
+	transactions := repositories.NewTransactionRepository(db)
+	tags := repositories.NewTagRepository(db)
+
+	transaction := &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(10), Status: "pending"}
+	require.NoError(t, transactions.Create(context.Background(), transaction))
+	require.NoError(t, tags.AddTags(context.Background(), transaction.ID, []string{"refund"}))
+
+	require.NoError(t, transactions.Delete(context.Background(), transaction.ID))
+
+	var associations int64
+	require.NoError(t, db.Table("transaction_tags").Count(&associations).Error)
+	assert.Zero(t, associations)
+
+	summaries, err := tags.GetSummaries(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, summaries)
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import "gorm.io/gorm"
+
+// includeTestDataKey is the GORM setting that makes dashboard queries count
+// test transactions
+const includeTestDataKey = "trxgo:include_test_data"
+
+// IncludeTestData returns a session of db whose dashboard queries count test
+// transactions, which they leave out by default
+func IncludeTestData(db *gorm.DB) *gorm.DB {
+	return db.Set(includeTestDataKey, true).Session(&gorm.Session{})
+}
+
+// includesTestData reports whether db was returned by IncludeTestData
+func includesTestData(db *gorm.DB) bool {
+	include, _ := db.Get(includeTestDataKey)
+	return include == true
+}
+
+// liveData scopes a query on transactions to those that are not test data,
+// unless the session includes test data
+func liveData(db *gorm.DB) *gorm.DB {
+	if includesTestData(db) {
+		return db
+	}
+	return db.Where("transactions.is_test = ?", false)
+}
@@ -0,0 +1,93 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositories_ExcludeTestTransactions(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	require.NoError(t, db.AutoMigrate(&models.Budget{}))
+	repo := repositories.NewTransactionRepository(db)
+
+	require.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(100), Status: "success"}))
+	require.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 2, Amount: decimal.NewFromInt(1), Status: "success", IsTest: true}))
+
+	count, amount, err := repo.GetTodaySuccessful(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.True(t, decimal.NewFromInt(100).Equal(amount))
+
+	average, err := repo.GetAveragePerUser(context.Background())
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(1).Equal(average), "expected 1, got %s", average)
+
+	latest, err := repo.GetLatest(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Len(t, latest, 1)
+
+	counts, err := repo.GetStatusCounts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, counts.Success)
+
+	totals, err := repo.GetDirectionTotals(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, totals.Debit.Count)
+
+	now := time.Now()
+	volume, err := repositories.NewBudgetRepository(db).GetVolume(context.Background(), 2, "USD", now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, volume.IsZero())
+
+	created, _, err := repositories.NewVolumeRepository(db).GetCreated(now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created)
+
+	// Test transactions are still listed, so sandbox clients see their own
+	all, err := repo.GetAll(context.Background(), models.TransactionFilters{})
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	isTest := true
+	test, err := repo.GetAll(context.Background(), models.TransactionFilters{IsTest: &isTest})
+	require.NoError(t, err)
+	require.Len(t, test, 1)
+	assert.Equal(t, uint(2), test[0].UserID)
+}
+
+func TestRepositories_IncludeTestData(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	require.NoError(t, db.AutoMigrate(&models.Tag{}))
+	repo := repositories.NewTransactionRepository(repositories.IncludeTestData(db))
+
+	require.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(100), Status: "success"}))
+	require.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 2, Amount: decimal.NewFromInt(1), Status: "success", IsTest: true}))
+
+	// Each query gets a fresh statement, so repeated calls count the same rows
+	for i := 0; i < 2; i++ {
+		count, _, err := repo.GetTodaySuccessful(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	}
+
+	counts, err := repo.GetStatusCounts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, counts.Success)
+
+	latest, err := repo.GetLatest(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Len(t, latest, 2)
+
+	// The session of the original db still leaves test data out
+	count, _, err := repositories.NewTransactionRepository(db).GetTodaySuccessful(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
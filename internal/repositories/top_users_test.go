@@ -0,0 +1,59 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionRepository_GetTopUsersBetween(t *testing.T) {
+	db := setupUserDB(t)
+	repo := repositories.NewTransactionRepository(db)
+
+	for _, user := range []models.User{{ID: 1, Name: "Alice", Email: "alice@example.com"}, {ID: 2, Name: "Bob", Email: "bob@example.com"}, {ID: 3, Name: "Carol", Email: "carol@example.com"}} {
+		user := user
+		require.NoError(t, db.Create(&user).Error)
+	}
+
+	from := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 16, 23, 59, 59, 0, time.UTC)
+	for _, transaction := range []models.Transaction{
+		{UserID: 1, Amount: decimal.NewFromInt(500), Status: "success", CreatedAt: from},
+		{UserID: 2, Amount: decimal.NewFromInt(100), Status: "success", CreatedAt: from},
+		{UserID: 2, Amount: decimal.NewFromInt(150), Status: "success", CreatedAt: to},
+		{UserID: 3, Amount: decimal.NewFromInt(50), Status: "success", CreatedAt: to},
+		// Left out: not successful, outside the period or test data
+		{UserID: 3, Amount: decimal.NewFromInt(999), Status: "pending", CreatedAt: from},
+		{UserID: 3, Amount: decimal.NewFromInt(999), Status: "success", CreatedAt: from.Add(-time.Second)},
+		{UserID: 3, Amount: decimal.NewFromInt(999), Status: "success", CreatedAt: from, IsTest: true},
+	} {
+		transaction := transaction
+		require.NoError(t, repo.Create(context.Background(), &transaction))
+	}
+
+	users, err := repo.GetTopUsersBetween(context.Background(), from, to, models.TopUsersByAmount, 2)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, uint(1), users[0].UserID)
+	assert.Equal(t, "Alice", users[0].Name)
+	assert.Equal(t, 1, users[0].Count)
+	assert.True(t, decimal.NewFromInt(500).Equal(users[0].Amount), users[0].Amount.String())
+	assert.Equal(t, uint(2), users[1].UserID)
+	assert.True(t, decimal.NewFromInt(250).Equal(users[1].Amount), users[1].Amount.String())
+
+	users, err = repo.GetTopUsersBetween(context.Background(), from, to, models.TopUsersByCount, 10)
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+	assert.Equal(t, []uint{2, 1, 3}, []uint{users[0].UserID, users[1].UserID, users[2].UserID})
+	assert.Equal(t, 2, users[0].Count)
+
+	_, err = repo.GetTopUsersBetween(context.Background(), from, to, "name", 10)
+	assert.Error(t, err)
+}
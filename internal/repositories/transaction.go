@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"interview/internal/models"
@@ -8,24 +10,62 @@ import (
 	"github.com/shopspring/decimal"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // TransactionRepository interface defines transaction repository methods
 type TransactionRepository interface {
-	Create(tx *models.Transaction) error
-	GetByID(id uint) (*models.Transaction, error)
-	GetAll(filters models.TransactionFilters) ([]models.Transaction, error)
-	Update(id uint, updates map[string]interface{}) error
-	Delete(id uint) error
-	GetTodaySuccessful() (int, decimal.Decimal, error)
-	GetAveragePerUser() (decimal.Decimal, error)
-	GetLatest(limit int) ([]models.Transaction, error)
-	GetStatusCounts() (models.StatusCounts, error)
+	Create(ctx context.Context, tx *models.Transaction) error
+	CreateBatch(ctx context.Context, transactions []models.Transaction) error
+	GetByID(ctx context.Context, id uint) (*models.Transaction, error)
+	// GetByIDFromPrimary gets a transaction by ID from the primary, never the
+	// replica, for reads whose result is checked or changed and written back
+	GetByIDFromPrimary(ctx context.Context, id uint) (*models.Transaction, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]models.Transaction, error)
+	GetByReferenceID(ctx context.Context, referenceID string) (*models.Transaction, error)
+	GetByReferenceIDs(ctx context.Context, referenceIDs []string) ([]models.Transaction, error)
+	GetByUUID(ctx context.Context, uuid string) (*models.Transaction, error)
+	GetAll(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, error)
+	GetAllWithCount(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, int64, error)
+	// Each calls fn with every transaction matching the filters in their sort
+	// order, ignoring pagination, reading them one at a time from a cursor
+	Each(ctx context.Context, filters models.TransactionFilters, fn func(*models.Transaction) error) error
+	Update(ctx context.Context, id uint, updates map[string]interface{}) error
+	UpdateVersion(ctx context.Context, id, version uint, updates map[string]interface{}) error
+	Refund(ctx context.Context, parentID uint, refund *models.Transaction) error
+	Delete(ctx context.Context, id uint) error
+	GetTodaySuccessful(ctx context.Context) (int, decimal.Decimal, error)
+	GetAveragePerUser(ctx context.Context) (decimal.Decimal, error)
+	GetLatest(ctx context.Context, limit int) ([]models.Transaction, error)
+	GetStatusCounts(ctx context.Context) (models.StatusCounts, error)
+	GetDirectionTotals(ctx context.Context) (models.DirectionTotals, error)
+	GetSuccessfulBetween(ctx context.Context, from, to time.Time) (int, decimal.Decimal, error)
+	GetStatusCountsBetween(ctx context.Context, from, to time.Time) (models.StatusCounts, error)
+	GetDirectionTotalsBetween(ctx context.Context, from, to time.Time) (models.DirectionTotals, error)
+	// GetCurrencyTotals and GetCurrencyTotalsBetween total successful
+	// transactions by currency, today or from from to to, inclusive
+	GetCurrencyTotals(ctx context.Context) ([]models.CurrencyTotal, error)
+	GetCurrencyTotalsBetween(ctx context.Context, from, to time.Time) ([]models.CurrencyTotal, error)
+	GetTopUsersBetween(ctx context.Context, from, to time.Time, sortBy string, limit int) ([]models.UserVolume, error)
+	GetAmountDistribution(ctx context.Context) (models.AmountDistribution, error)
+	GetAmountDistributionBetween(ctx context.Context, from, to time.Time) (models.AmountDistribution, error)
 }
 
 // transactionRepository implements TransactionRepository interface
 type transactionRepository struct {
-	db *gorm.DB
+	db      *gorm.DB
+	replica *gorm.DB
+	options ReplicaOptions
+}
+
+// ReplicaOptions configures how reads are routed to a read replica
+type ReplicaOptions struct {
+	// HedgeDelay, when positive, issues a second GetByID to the primary after
+	// this delay and takes the first successful response
+	HedgeDelay time.Duration
+	// LagMonitor, when set, routes reads back to the primary while the
+	// replica is lagging
+	LagMonitor *ReplicaLagMonitor
 }
 
 // NewTransactionRepository creates a new transaction repository
@@ -33,25 +73,149 @@ func NewTransactionRepository(db *gorm.DB) TransactionRepository {
 	return &transactionRepository{db: db}
 }
 
+// NewTransactionRepositoryWithReplica creates a new transaction repository
+// that serves reads from a read replica
+func NewTransactionRepositoryWithReplica(db, replica *gorm.DB, options ReplicaOptions) TransactionRepository {
+	return &transactionRepository{db: db, replica: replica, options: options}
+}
+
+// useReplica reports whether reads should currently go to the replica
+func (r *transactionRepository) useReplica() bool {
+	if r.replica == nil {
+		return false
+	}
+	return r.options.LagMonitor == nil || r.options.LagMonitor.Healthy()
+}
+
+// reader returns the connection reads with ctx should use
+func (r *transactionRepository) reader(ctx context.Context) *gorm.DB {
+	if r.useReplica() {
+		return r.replica.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
+
 // Create creates a new transaction
-func (r *transactionRepository) Create(tx *models.Transaction) error {
-	return r.db.Create(tx).Error
+func (r *transactionRepository) Create(ctx context.Context, tx *models.Transaction) error {
+	return r.db.WithContext(ctx).Create(tx).Error
+}
+
+// createBatchSize is how many rows CreateBatch inserts per statement
+const createBatchSize = 100
+
+// CreateBatch creates transactions in one database transaction, filling in their IDs
+func (r *transactionRepository) CreateBatch(ctx context.Context, transactions []models.Transaction) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(transactions, createBatchSize).Error
+	})
 }
 
 // GetByID gets a transaction by ID
-func (r *transactionRepository) GetByID(id uint) (*models.Transaction, error) {
+func (r *transactionRepository) GetByID(ctx context.Context, id uint) (*models.Transaction, error) {
+	if r.useReplica() {
+		return r.hedgedGetByID(ctx, id)
+	}
+	return getByID(r.db.WithContext(ctx), id)
+}
+
+// GetByIDFromPrimary gets a transaction by ID from the primary, so state
+// checks before a write never see a row the replica has yet to catch up on
+func (r *transactionRepository) GetByIDFromPrimary(ctx context.Context, id uint) (*models.Transaction, error) {
+	return getByID(r.db.WithContext(ctx), id)
+}
+
+// getByID gets a transaction by ID from the given connection
+func getByID(db *gorm.DB, id uint) (*models.Transaction, error) {
 	var transaction models.Transaction
-	err := r.db.First(&transaction, id).Error
+	err := db.Preload("Tags").First(&transaction, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &transaction, nil
 }
 
-// GetAll gets all transactions with filters
-func (r *transactionRepository) GetAll(filters models.TransactionFilters) ([]models.Transaction, error) {
+// GetByReferenceID gets a transaction by its upstream reference ID
+func (r *transactionRepository) GetByReferenceID(ctx context.Context, referenceID string) (*models.Transaction, error) {
+	var transaction models.Transaction
+	err := r.db.WithContext(ctx).Preload("Tags").First(&transaction, "reference_id = ?", referenceID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// GetByReferenceIDs gets the transactions with the given reference IDs in a
+// single query, in no particular order
+func (r *transactionRepository) GetByReferenceIDs(ctx context.Context, referenceIDs []string) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := r.reader(ctx).Preload("Tags").Where("reference_id IN ?", referenceIDs).Find(&transactions).Error
+	return transactions, err
+}
+
+// GetByUUID gets a transaction by its public UUID
+func (r *transactionRepository) GetByUUID(ctx context.Context, uuid string) (*models.Transaction, error) {
+	var transaction models.Transaction
+	err := r.db.WithContext(ctx).Preload("Tags").First(&transaction, "uuid = ?", uuid).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// GetByIDs gets the transactions with the given IDs in a single query, in no particular order
+func (r *transactionRepository) GetByIDs(ctx context.Context, ids []uint) ([]models.Transaction, error) {
 	var transactions []models.Transaction
-	query := r.db.Model(&models.Transaction{})
+	err := r.reader(ctx).Preload("Tags").Where("id IN ?", ids).Find(&transactions).Error
+	return transactions, err
+}
+
+// GetAll gets all transactions with filters
+func (r *transactionRepository) GetAll(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, error) {
+	return findPage(filteredQuery(r.reader(ctx), filters), filters)
+}
+
+// GetAllWithCount gets a page of transactions with filters together with the
+// total number of transactions matching the filters
+func (r *transactionRepository) GetAllWithCount(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, int64, error) {
+	var total int64
+	query := filteredQuery(r.reader(ctx), filters)
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	transactions, err := findPage(query, filters)
+	return transactions, total, err
+}
+
+// Each reads every transaction matching the filters from a cursor, so the
+// result is never held in memory. Tags are not loaded.
+func (r *transactionRepository) Each(ctx context.Context, filters models.TransactionFilters, fn func(*models.Transaction) error) error {
+	db := r.reader(ctx)
+	column, desc := filters.Sort()
+	rows, err := filteredQuery(db, filters).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: desc}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: desc}).
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transaction models.Transaction
+		if err := db.ScanRows(rows, &transaction); err != nil {
+			return err
+		}
+		if err := fn(&transaction); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// filteredQuery applies the transaction filters, without pagination
+func filteredQuery(db *gorm.DB, filters models.TransactionFilters) *gorm.DB {
+	query := db.Model(&models.Transaction{})
 
 	// Apply filters
 	if filters.UserID != 0 {
@@ -60,48 +224,127 @@ func (r *transactionRepository) GetAll(filters models.TransactionFilters) ([]mod
 	if filters.Status != "" {
 		query = query.Where("status = ?", filters.Status)
 	}
-
-	// Set default limit and offset
-	limit := filters.Limit
-	if limit == 0 || limit > 100 {
-		limit = 20
+	if filters.From != nil {
+		query = query.Where("created_at >= ?", *filters.From)
+	}
+	if filters.To != nil {
+		query = query.Where("created_at <= ?", *filters.To)
+	}
+	if filters.MinAmount != nil {
+		query = query.Where("amount >= ?", *filters.MinAmount)
+	}
+	if filters.MaxAmount != nil {
+		query = query.Where("amount <= ?", *filters.MaxAmount)
+	}
+	if filters.Direction != "" {
+		query = query.Where("direction = ?", filters.Direction)
+	}
+	if filters.IsTest != nil {
+		query = query.Where("is_test = ?", *filters.IsTest)
 	}
-	offset := filters.Offset
-	if offset < 0 {
-		offset = 0
+	for key, value := range filters.Metadata {
+		query = query.Where("JSON_EXTRACT(metadata, ?) = ?", `$."`+key+`"`, value)
 	}
+	if filters.Tag != "" {
+		query = query.Where("id IN (?)", db.Table("transaction_tags").
+			Select("transaction_tags.transaction_id").
+			Joins("JOIN tags ON tags.id = transaction_tags.tag_id").
+			Where("tags.name = ?", filters.Tag))
+	}
+
+	return query
+}
+
+// findPage loads the page of the query selected by the filters in their sort
+// order. Ties are broken by ID so pages do not overlap.
+func findPage(query *gorm.DB, filters models.TransactionFilters) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+
+	// Set default limit and offset
+	limit, offset := filters.Page()
+	column, desc := filters.Sort()
 
-	err := query.Limit(limit).Offset(offset).Order("created_at DESC").Find(&transactions).Error
+	err := query.Preload("Tags").Limit(limit).Offset(offset).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: desc}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: desc}).
+		Find(&transactions).Error
 	return transactions, err
 }
 
 // Update updates a transaction
-func (r *transactionRepository) Update(id uint, updates map[string]interface{}) error {
-	return r.db.Model(&models.Transaction{}).Where("id = ?", id).Updates(updates).Error
+func (r *transactionRepository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&models.Transaction{}).Where("id = ?", id).Updates(withNextVersion(updates)).Error
 }
 
-// Delete deletes a transaction
-func (r *transactionRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Transaction{}, id).Error
+// UpdateVersion applies updates only while the transaction is at version. It
+// returns gorm.ErrRecordNotFound when the transaction does not exist or has
+// changed since.
+func (r *transactionRepository) UpdateVersion(ctx context.Context, id, version uint, updates map[string]interface{}) error {
+	result := r.db.WithContext(ctx).Model(&models.Transaction{}).
+		Where("id = ? AND version = ?", id, version).
+		Updates(withNextVersion(updates))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// withNextVersion adds a version increment to a copy of updates
+func withNextVersion(updates map[string]interface{}) map[string]interface{} {
+	versioned := make(map[string]interface{}, len(updates)+1)
+	for column, value := range updates {
+		versioned[column] = value
+	}
+	versioned["version"] = gorm.Expr("version + 1")
+	return versioned
+}
+
+// Refund marks a successful transaction refunded and creates its refund in
+// one database transaction. It returns gorm.ErrRecordNotFound when the parent
+// does not exist or is no longer successful.
+func (r *transactionRepository) Refund(ctx context.Context, parentID uint, refund *models.Transaction) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Transaction{}).
+			Where("id = ? AND status = ?", parentID, "success").
+			Updates(withNextVersion(map[string]interface{}{"status": "refunded"}))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(refund).Error
+	})
+}
+
+// Delete deletes a transaction together with its tag associations
+func (r *transactionRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Select("Tags").Delete(&models.Transaction{ID: id}).Error
 }
 
 // GetTodaySuccessful gets today's successful transactions count and amount
-func (r *transactionRepository) GetTodaySuccessful() (int, decimal.Decimal, error) {
+func (r *transactionRepository) GetTodaySuccessful(ctx context.Context) (int, decimal.Decimal, error) {
+	db := r.db.WithContext(ctx)
 	var count int64
 	var totalAmount decimal.Decimal
 
 	today := time.Now().Format("2006-01-02")
 
-	err := r.db.Model(&models.Transaction{}).
-		Where("status = ? AND DATE(created_at) = ?", "success", today).
+	err := db.Model(&models.Transaction{}).
+		Where("DATE(created_at) = ?", today).
+		Scopes(successfulVolume, liveData).
 		Count(&count).Error
 	if err != nil {
 		return 0, decimal.Zero, err
 	}
 
-	err = r.db.Model(&models.Transaction{}).
+	err = db.Model(&models.Transaction{}).
 		Select("COALESCE(SUM(amount), 0)").
-		Where("status = ? AND DATE(created_at) = ?", "success", today).
+		Where("DATE(created_at) = ?", today).
+		Scopes(successfulVolume, liveData).
 		Scan(&totalAmount).Error
 	if err != nil {
 		return 0, decimal.Zero, err
@@ -111,16 +354,22 @@ func (r *transactionRepository) GetTodaySuccessful() (int, decimal.Decimal, erro
 }
 
 // GetAveragePerUser gets average transactions per user
-func (r *transactionRepository) GetAveragePerUser() (decimal.Decimal, error) {
+func (r *transactionRepository) GetAveragePerUser(ctx context.Context) (decimal.Decimal, error) {
+	db := r.db.WithContext(ctx)
 	var result struct {
 		Average decimal.Decimal
 	}
 
-	err := r.db.Raw(`
+	where := "WHERE is_test = false"
+	if includesTestData(db) {
+		where = ""
+	}
+	err := db.Raw(`
 		SELECT COALESCE(AVG(user_transaction_count), 0) as average
 		FROM (
 			SELECT user_id, COUNT(*) as user_transaction_count
 			FROM transactions
+			` + where + `
 			GROUP BY user_id
 		) as user_counts
 	`).Scan(&result).Error
@@ -129,18 +378,20 @@ func (r *transactionRepository) GetAveragePerUser() (decimal.Decimal, error) {
 }
 
 // GetLatest gets latest transactions
-func (r *transactionRepository) GetLatest(limit int) ([]models.Transaction, error) {
+func (r *transactionRepository) GetLatest(ctx context.Context, limit int) ([]models.Transaction, error) {
 	var transactions []models.Transaction
-	err := r.db.Order("created_at DESC").Limit(limit).Find(&transactions).Error
+	err := r.reader(ctx).Scopes(liveData).Order("created_at DESC").Limit(limit).Find(&transactions).Error
 	return transactions, err
 }
 
 // GetStatusCounts gets transaction counts by status
-func (r *transactionRepository) GetStatusCounts() (models.StatusCounts, error) {
+func (r *transactionRepository) GetStatusCounts(ctx context.Context) (models.StatusCounts, error) {
+	db := r.db.WithContext(ctx)
 	var counts models.StatusCounts
 
-	err := r.db.Model(&models.Transaction{}).
+	err := db.Model(&models.Transaction{}).
 		Select("status, COUNT(*) as count").
+		Scopes(liveData).
 		Group("status").
 		Scan(&[]struct {
 			Status string
@@ -152,14 +403,222 @@ func (r *transactionRepository) GetStatusCounts() (models.StatusCounts, error) {
 	}
 
 	// Get individual counts
-	var successCount, pendingCount, failedCount int64
-	r.db.Model(&models.Transaction{}).Where("status = ?", "success").Count(&successCount)
-	r.db.Model(&models.Transaction{}).Where("status = ?", "pending").Count(&pendingCount)
-	r.db.Model(&models.Transaction{}).Where("status = ?", "failed").Count(&failedCount)
+	var successCount, pendingCount, failedCount, refundedCount int64
+	db.Model(&models.Transaction{}).Where("status = ?", "success").Scopes(liveData).Count(&successCount)
+	db.Model(&models.Transaction{}).Where("status = ?", "pending").Scopes(liveData).Count(&pendingCount)
+	db.Model(&models.Transaction{}).Where("status = ?", "failed").Scopes(liveData).Count(&failedCount)
+	db.Model(&models.Transaction{}).Where("status = ?", "refunded").Scopes(liveData).Count(&refundedCount)
 
 	counts.Success = int(successCount)
 	counts.Pending = int(pendingCount)
 	counts.Failed = int(failedCount)
+	counts.Refunded = int(refundedCount)
+
+	return counts, nil
+}
+
+// GetDirectionTotals gets the number and total amount of successful transactions per direction
+func (r *transactionRepository) GetDirectionTotals(ctx context.Context) (models.DirectionTotals, error) {
+	return directionTotals(r.db.WithContext(ctx).Model(&models.Transaction{}))
+}
+
+// successfulVolume scopes a query on transactions to the successful ones
+// that count as volume. A refund is not counted: its original is refunded
+// and drops out instead, as in budget consumption.
+func successfulVolume(db *gorm.DB) *gorm.DB {
+	return db.Where("transactions.status = ? AND transactions.type <> ?", "success", models.TransactionTypeRefund)
+}
+
+// createdBetween scopes a query on transactions to those created from from
+// to to, inclusive
+func createdBetween(from, to time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("transactions.created_at >= ? AND transactions.created_at <= ?", from, to)
+	}
+}
 
+// GetSuccessfulBetween gets the count and amount of successful transactions
+// created from from to to, inclusive
+func (r *transactionRepository) GetSuccessfulBetween(ctx context.Context, from, to time.Time) (int, decimal.Decimal, error) {
+	var result struct {
+		Count  int
+		Amount decimal.Decimal
+	}
+	err := r.db.WithContext(ctx).Model(&models.Transaction{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount").
+		Scopes(successfulVolume, liveData, createdBetween(from, to)).
+		Scan(&result).Error
+	return result.Count, result.Amount, err
+}
+
+// GetStatusCountsBetween gets the counts by status of transactions created
+// from from to to, inclusive
+func (r *transactionRepository) GetStatusCountsBetween(ctx context.Context, from, to time.Time) (models.StatusCounts, error) {
+	var counts models.StatusCounts
+
+	var rows []struct {
+		Status string
+		Count  int
+	}
+	err := r.db.WithContext(ctx).Model(&models.Transaction{}).
+		Select("status, COUNT(*) AS count").
+		Scopes(liveData, createdBetween(from, to)).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return counts, err
+	}
+
+	for _, row := range rows {
+		switch row.Status {
+		case "success":
+			counts.Success = row.Count
+		case "pending":
+			counts.Pending = row.Count
+		case "failed":
+			counts.Failed = row.Count
+		case "refunded":
+			counts.Refunded = row.Count
+		}
+	}
 	return counts, nil
 }
+
+// GetDirectionTotalsBetween gets the number and total amount per direction of
+// successful transactions created from from to to, inclusive
+func (r *transactionRepository) GetDirectionTotalsBetween(ctx context.Context, from, to time.Time) (models.DirectionTotals, error) {
+	return directionTotals(r.db.WithContext(ctx).Model(&models.Transaction{}).Scopes(createdBetween(from, to)))
+}
+
+// GetCurrencyTotals gets the count and amount of today's successful
+// transactions in each currency
+func (r *transactionRepository) GetCurrencyTotals(ctx context.Context) ([]models.CurrencyTotal, error) {
+	today := time.Now().Format("2006-01-02")
+	return currencyTotals(r.db.WithContext(ctx).Model(&models.Transaction{}).Where("DATE(created_at) = ?", today))
+}
+
+// GetCurrencyTotalsBetween gets the count and amount of successful
+// transactions in each currency created from from to to, inclusive
+func (r *transactionRepository) GetCurrencyTotalsBetween(ctx context.Context, from, to time.Time) ([]models.CurrencyTotal, error) {
+	return currencyTotals(r.db.WithContext(ctx).Model(&models.Transaction{}).Scopes(createdBetween(from, to)))
+}
+
+// GetAmountDistribution gets the distribution of the amounts of successful
+// transactions
+func (r *transactionRepository) GetAmountDistribution(ctx context.Context) (models.AmountDistribution, error) {
+	return amountDistribution(r.db.WithContext(ctx).Model(&models.Transaction{}))
+}
+
+// GetAmountDistributionBetween gets the distribution of the amounts of
+// successful transactions created from from to to, inclusive
+func (r *transactionRepository) GetAmountDistributionBetween(ctx context.Context, from, to time.Time) (models.AmountDistribution, error) {
+	return amountDistribution(r.db.WithContext(ctx).Model(&models.Transaction{}).Scopes(createdBetween(from, to)))
+}
+
+// amountDistribution computes the distribution of the amounts of successful
+// transactions matching query. Each percentile is read as the amount at its
+// rank, so the database sorts the amounts instead of sending them all.
+func amountDistribution(query *gorm.DB) (models.AmountDistribution, error) {
+	successful := query.Scopes(successfulVolume, liveData).Session(&gorm.Session{})
+
+	var bounds struct {
+		Count     int
+		MinAmount decimal.Decimal
+		MaxAmount decimal.Decimal
+	}
+	err := successful.
+		Select("COUNT(*) AS count, COALESCE(MIN(amount), 0) AS min_amount, COALESCE(MAX(amount), 0) AS max_amount").
+		Scan(&bounds).Error
+	if err != nil || bounds.Count == 0 {
+		return models.AmountDistribution{}, err
+	}
+
+	distribution := models.AmountDistribution{Count: bounds.Count, Min: bounds.MinAmount, Max: bounds.MaxAmount}
+	for _, percentile := range []struct {
+		percent int
+		amount  *decimal.Decimal
+	}{{50, &distribution.Median}, {95, &distribution.P95}} {
+		// The nearest rank is the smallest covering percent of the amounts
+		rank := (percentile.percent*bounds.Count + 99) / 100
+		var row struct {
+			Amount decimal.Decimal
+		}
+		err := successful.Select("amount").Order("amount").Offset(rank - 1).Limit(1).Scan(&row).Error
+		if err != nil {
+			return models.AmountDistribution{}, err
+		}
+		*percentile.amount = row.Amount
+	}
+	return distribution, nil
+}
+
+// topUsersOrder maps the orders of top users to their ORDER BY, with ties
+// settled by the other measure and then by user
+var topUsersOrder = map[string]string{
+	models.TopUsersByAmount: "amount DESC, count DESC, transactions.user_id",
+	models.TopUsersByCount:  "count DESC, amount DESC, transactions.user_id",
+}
+
+// GetTopUsersBetween gets the limit users with the highest total amount, or
+// number, of successful transactions created from from to to, inclusive
+func (r *transactionRepository) GetTopUsersBetween(ctx context.Context, from, to time.Time, sortBy string, limit int) ([]models.UserVolume, error) {
+	order, ok := topUsersOrder[sortBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid top users order %q", sortBy)
+	}
+
+	var users []models.UserVolume
+	err := r.db.WithContext(ctx).Model(&models.Transaction{}).
+		Select("transactions.user_id, users.name, COUNT(*) AS count, COALESCE(SUM(transactions.amount), 0) AS amount").
+		Joins("JOIN users ON users.id = transactions.user_id").
+		Scopes(successfulVolume, liveData, createdBetween(from, to)).
+		Group("transactions.user_id, users.name").
+		Order(order).
+		Limit(limit).
+		Scan(&users).Error
+	return users, err
+}
+
+// directionTotals totals the successful transactions query selects by direction
+func directionTotals(query *gorm.DB) (models.DirectionTotals, error) {
+	var totals models.DirectionTotals
+
+	var rows []struct {
+		Direction string
+		Count     int
+		Amount    decimal.Decimal
+	}
+	err := query.
+		Select("direction, COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount").
+		Scopes(successfulVolume, liveData).
+		Group("direction").
+		Scan(&rows).Error
+	if err != nil {
+		return totals, err
+	}
+
+	for _, row := range rows {
+		total := models.DirectionTotal{Count: row.Count, Amount: row.Amount}
+		switch row.Direction {
+		case models.TransactionDirectionDebit:
+			totals.Debit = total
+		case models.TransactionDirectionCredit:
+			totals.Credit = total
+		}
+	}
+
+	return totals, nil
+}
+
+// currencyTotals totals the successful transactions matching query by
+// currency, in currency order
+func currencyTotals(query *gorm.DB) ([]models.CurrencyTotal, error) {
+	totals := []models.CurrencyTotal{}
+	err := query.
+		Select("currency, COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount").
+		Scopes(successfulVolume, liveData).
+		Group("currency").
+		Order("currency").
+		Scan(&totals).Error
+	return totals, err
+}
@@ -1,14 +1,16 @@
 package repositories_test
 
 import (
+	"context"
 	"testing"
-	"time"
 
+	"interview/internal/database/dbtest"
 	"interview/internal/models"
 	"interview/internal/repositories"
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
@@ -41,7 +43,7 @@ func TestTransactionRepository_Create(t *testing.T) {
 		Status: "pending",
 	}
 
-	err := repo.Create(transaction)
+	err := repo.Create(context.Background(), transaction)
 	if err != nil {
 		t.Fatalf("Failed to create transaction: %v", err)
 	}
@@ -69,13 +71,13 @@ func TestTransactionRepository_GetByID(t *testing.T) {
 		Status: "pending",
 	}
 
-	err := repo.Create(transaction)
+	err := repo.Create(context.Background(), transaction)
 	if err != nil {
 		t.Fatalf("Failed to create transaction: %v", err)
 	}
 
 	// Test GetByID
-	retrieved, err := repo.GetByID(transaction.ID)
+	retrieved, err := repo.GetByID(context.Background(), transaction.ID)
 	if err != nil {
 		t.Fatalf("Failed to get transaction: %v", err)
 	}
@@ -103,8 +105,8 @@ func TestTransactionRepository_GetAll(t *testing.T) {
 	tx1 := &models.Transaction{UserID: 9999, Amount: decimal.NewFromFloat(100.50), Status: "pending"}
 	tx2 := &models.Transaction{UserID: 9999, Amount: decimal.NewFromFloat(200.00), Status: "success"}
 
-	repo.Create(tx1)
-	repo.Create(tx2)
+	repo.Create(context.Background(), tx1)
+	repo.Create(context.Background(), tx2)
 
 	// Test GetAll with filters
 	filters := models.TransactionFilters{
@@ -113,7 +115,7 @@ func TestTransactionRepository_GetAll(t *testing.T) {
 		Offset: 0,
 	}
 
-	transactions, err := repo.GetAll(filters)
+	transactions, err := repo.GetAll(context.Background(), filters)
 	if err != nil {
 		t.Fatalf("Failed to get transactions: %v", err)
 	}
@@ -142,7 +144,7 @@ func TestTransactionRepository_Update(t *testing.T) {
 		Status: "pending",
 	}
 
-	err := repo.Create(transaction)
+	err := repo.Create(context.Background(), transaction)
 	if err != nil {
 		t.Fatalf("Failed to create transaction: %v", err)
 	}
@@ -152,13 +154,13 @@ func TestTransactionRepository_Update(t *testing.T) {
 		"status": "success",
 	}
 
-	err = repo.Update(transaction.ID, updates)
+	err = repo.Update(context.Background(), transaction.ID, updates)
 	if err != nil {
 		t.Fatalf("Failed to update transaction: %v", err)
 	}
 
 	// Verify update
-	updated, err := repo.GetByID(transaction.ID)
+	updated, err := repo.GetByID(context.Background(), transaction.ID)
 	if err != nil {
 		t.Fatalf("Failed to get updated transaction: %v", err)
 	}
@@ -186,19 +188,19 @@ func TestTransactionRepository_Delete(t *testing.T) {
 		Status: "pending",
 	}
 
-	err := repo.Create(transaction)
+	err := repo.Create(context.Background(), transaction)
 	if err != nil {
 		t.Fatalf("Failed to create transaction: %v", err)
 	}
 
 	// Test Delete
-	err = repo.Delete(transaction.ID)
+	err = repo.Delete(context.Background(), transaction.ID)
 	if err != nil {
 		t.Fatalf("Failed to delete transaction: %v", err)
 	}
 
 	// Verify deletion
-	_, err = repo.GetByID(transaction.ID)
+	_, err = repo.GetByID(context.Background(), transaction.ID)
 	if err == nil {
 		t.Error("Expected error when getting deleted transaction")
 	}
@@ -220,10 +222,10 @@ func TestTransactionRepository_GetTodaySuccessful(t *testing.T) {
 	}
 
 	for _, tx := range transactions {
-		repo.Create(tx)
+		repo.Create(context.Background(), tx)
 	}
 
-	count, amount, err := repo.GetTodaySuccessful()
+	count, amount, err := repo.GetTodaySuccessful(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to get today's successful transactions: %v", err)
 	}
@@ -255,10 +257,10 @@ func TestTransactionRepository_GetAveragePerUser(t *testing.T) {
 	}
 
 	for _, tx := range transactions {
-		repo.Create(tx)
+		repo.Create(context.Background(), tx)
 	}
 
-	average, err := repo.GetAveragePerUser()
+	average, err := repo.GetAveragePerUser(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to get average per user: %v", err)
 	}
@@ -287,10 +289,10 @@ func TestTransactionRepository_GetLatest(t *testing.T) {
 	}
 
 	for _, tx := range transactions {
-		repo.Create(tx)
+		repo.Create(context.Background(), tx)
 	}
 
-	latest, err := repo.GetLatest(2)
+	latest, err := repo.GetLatest(context.Background(), 2)
 	if err != nil {
 		t.Fatalf("Failed to get latest transactions: %v", err)
 	}
@@ -320,10 +322,10 @@ func TestTransactionRepository_GetStatusCounts(t *testing.T) {
 	}
 
 	for _, tx := range transactions {
-		repo.Create(tx)
+		repo.Create(context.Background(), tx)
 	}
 
-	counts, err := repo.GetStatusCounts()
+	counts, err := repo.GetStatusCounts(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to get status counts: %v", err)
 	}
@@ -352,7 +354,7 @@ func TestTransactionRepository_GetAllWithFilters(t *testing.T) {
 	}
 
 	for _, tx := range transactions {
-		repo.Create(tx)
+		repo.Create(context.Background(), tx)
 	}
 
 	// Test with UserID filter
@@ -360,7 +362,7 @@ func TestTransactionRepository_GetAllWithFilters(t *testing.T) {
 		UserID: 9999,
 	}
 
-	result, err := repo.GetAll(filters)
+	result, err := repo.GetAll(context.Background(), filters)
 	if err != nil {
 		t.Fatalf("Failed to get transactions with UserID filter: %v", err)
 	}
@@ -374,7 +376,7 @@ func TestTransactionRepository_GetAllWithFilters(t *testing.T) {
 		Status: "success",
 	}
 
-	result, err = repo.GetAll(filters)
+	result, err = repo.GetAll(context.Background(), filters)
 	if err != nil {
 		t.Fatalf("Failed to get transactions with Status filter: %v", err)
 	}
@@ -388,7 +390,7 @@ func TestTransactionRepository_GetAllWithFilters(t *testing.T) {
 		Limit: 1,
 	}
 
-	result, err = repo.GetAll(filters)
+	result, err = repo.GetAll(context.Background(), filters)
 	if err != nil {
 		t.Fatalf("Failed to get transactions with Limit: %v", err)
 	}
@@ -416,7 +418,7 @@ func TestTransactionRepository_GetAllWithZeroLimit(t *testing.T) {
 			Amount: decimal.NewFromInt(int64(i * 100)),
 			Status: "success",
 		}
-		repo.Create(tx)
+		repo.Create(context.Background(), tx)
 	}
 
 	// Test with zero limit (should default to 20)
@@ -424,7 +426,7 @@ func TestTransactionRepository_GetAllWithZeroLimit(t *testing.T) {
 		Limit: 0,
 	}
 
-	result, err := repo.GetAll(filters)
+	result, err := repo.GetAll(context.Background(), filters)
 	if err != nil {
 		t.Fatalf("Failed to get transactions: %v", err)
 	}
@@ -452,7 +454,7 @@ func TestTransactionRepository_GetAllWithLargeLimit(t *testing.T) {
 			Amount: decimal.NewFromInt(int64(i * 100)),
 			Status: "success",
 		}
-		repo.Create(tx)
+		repo.Create(context.Background(), tx)
 	}
 
 	// Test with limit > 100 (should be capped to 100)
@@ -460,7 +462,7 @@ func TestTransactionRepository_GetAllWithLargeLimit(t *testing.T) {
 		Limit: 150,
 	}
 
-	result, err := repo.GetAll(filters)
+	result, err := repo.GetAll(context.Background(), filters)
 	if err != nil {
 		t.Fatalf("Failed to get transactions: %v", err)
 	}
@@ -496,7 +498,7 @@ func TestTransactionRepository_GetAllWithNegativeOffset(t *testing.T) {
 			Amount: decimal.NewFromInt(int64(i * 100)),
 			Status: "success",
 		}
-		repo.Create(tx)
+		repo.Create(context.Background(), tx)
 	}
 
 	// Test with negative offset (should default to 0)
@@ -505,7 +507,7 @@ func TestTransactionRepository_GetAllWithNegativeOffset(t *testing.T) {
 		Limit:  10,
 	}
 
-	result, err := repo.GetAll(filters)
+	result, err := repo.GetAll(context.Background(), filters)
 	if err != nil {
 		t.Fatalf("Failed to get transactions: %v", err)
 	}
@@ -536,7 +538,7 @@ func TestTransactionRepository_GetTodaySuccessfulNoData(t *testing.T) {
 	// Clean all transactions
 	db.Exec("DELETE FROM transactions WHERE user_id >= 9960")
 
-	count, amount, err := repo.GetTodaySuccessful()
+	count, amount, err := repo.GetTodaySuccessful(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to get today's successful transactions: %v", err)
 	}
@@ -567,10 +569,10 @@ func TestTransactionRepository_GetStatusCountsDetailed(t *testing.T) {
 	}
 
 	for _, tx := range transactions {
-		repo.Create(tx)
+		repo.Create(context.Background(), tx)
 	}
 
-	counts, err := repo.GetStatusCounts()
+	counts, err := repo.GetStatusCounts(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to get status counts: %v", err)
 	}
@@ -591,289 +593,56 @@ func TestTransactionRepository_GetStatusCountsDetailed(t *testing.T) {
 }
 
 func TestTransactionRepository_GetTodaySuccessful_CountError(t *testing.T) {
-	// This test simulates a database error during count operation
-	// In practice, this is hard to simulate with real DB, but we test the error path
-	db := setupTestDB(t)
-	if db == nil {
-		return
-	}
-
-	repo := repositories.NewTransactionRepository(db)
-
-	// Force an error by closing the database connection
-	sqlDB, err := db.DB()
-	if err == nil {
-		sqlDB.Close()
+	db := setupSQLiteDB(t, t.Name())
+	faults := dbtest.Inject(db)
+	faults.Add(dbtest.Fault{Table: "transactions", Err: dbtest.ConnectionReset()})
 
-		// Now try to call GetTodaySuccessful - should get an error
-		count, amount, err := repo.GetTodaySuccessful()
-
-		// Should handle the error gracefully
-		if err != nil {
-			assert.Equal(t, 0, count)
-			assert.True(t, amount.Equal(decimal.Zero))
-		}
-	}
-}
-
-func TestTransactionRepository_GetStatusCounts_Error(t *testing.T) {
-	// Test error handling in GetStatusCounts
-	db := setupTestDB(t)
-	if db == nil {
-		return
-	}
-
-	repo := repositories.NewTransactionRepository(db)
-
-	// Force an error by closing the database connection
-	sqlDB, err := db.DB()
-	if err == nil {
-		sqlDB.Close()
-
-		// Now try to call GetStatusCounts - should get an error
-		statusCounts, err := repo.GetStatusCounts()
-
-		// Should handle the error gracefully
-		if err != nil {
-			assert.Equal(t, 0, statusCounts.Success)
-			assert.Equal(t, 0, statusCounts.Pending)
-			assert.Equal(t, 0, statusCounts.Failed)
-		}
-	}
+	count, amount, err := repositories.NewTransactionRepository(db).GetTodaySuccessful(context.Background())
+	assert.ErrorIs(t, err, dbtest.ConnectionReset())
+	assert.Equal(t, 0, count)
+	assert.True(t, amount.Equal(decimal.Zero))
+	assert.Equal(t, 1, faults.Fired(), "the sum is not queried after the count failed")
 }
 
 func TestTransactionRepository_GetTodaySuccessful_SumError(t *testing.T) {
-	// Test error in the SUM query
-	db := setupTestDB(t)
-	if db == nil {
-		return
-	}
-
+	db := setupSQLiteDB(t, t.Name())
 	repo := repositories.NewTransactionRepository(db)
+	require.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromFloat(100.50), Status: "success"}))
 
-	// First create a transaction to ensure Count passes
-	tx := &models.Transaction{
-		UserID: 99998,
-		Amount: decimal.NewFromFloat(100.50),
-		Status: "success",
-	}
-	repo.Create(tx)
-
-	// Temporarily alter the table to make SUM fail but Count succeed
-	// We'll change the amount column type to cause an error in SUM
-	sqlDB, _ := db.DB()
-
-	// First test normal operation
-	count, amount, err := repo.GetTodaySuccessful()
-	assert.NoError(t, err)
-	assert.GreaterOrEqual(t, count, 1)
-	assert.True(t, amount.GreaterThan(decimal.Zero))
-
-	// Now close connection to test error path
-	sqlDB.Close()
+	// The count runs, the sum fails
+	dbtest.Inject(db).Add(dbtest.Fault{Table: "transactions", Skip: 1, Err: dbtest.ConnectionReset()})
 
-	// This should error
-	count2, amount2, err2 := repo.GetTodaySuccessful()
-	assert.Error(t, err2)
-	assert.Equal(t, 0, count2)
-	assert.True(t, amount2.Equal(decimal.Zero))
-
-	// Cleanup
-	db = setupTestDB(t)
-	if db != nil {
-		db.Exec("DELETE FROM transactions WHERE user_id = 99998")
-	}
+	count, amount, err := repo.GetTodaySuccessful(context.Background())
+	assert.ErrorIs(t, err, dbtest.ConnectionReset())
+	assert.Equal(t, 0, count)
+	assert.True(t, amount.Equal(decimal.Zero))
 }
 
-func TestTransactionRepository_GetTodaySuccessful_SumErrorSpecific(t *testing.T) {
-	db := setupTestDB(t)
-	if db == nil {
-		return
-	}
-
+func TestTransactionRepository_GetStatusCounts_Error(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
 	repo := repositories.NewTransactionRepository(db)
+	require.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromFloat(10), Status: "success"}))
+	dbtest.Inject(db).Add(dbtest.Fault{Table: "transactions", Times: 1, Err: dbtest.Deadlock()})
 
-	// Create transaction data
-	tx := &models.Transaction{
-		UserID: 99997,
-		Amount: decimal.NewFromFloat(100.50),
-		Status: "success",
-	}
-	repo.Create(tx)
+	statusCounts, err := repo.GetStatusCounts(context.Background())
+	assert.EqualError(t, err, dbtest.Deadlock().Error())
+	assert.Equal(t, models.StatusCounts{}, statusCounts)
 
-	// Get SQL DB to manipulate connection
-	sqlDB, err := db.DB()
+	// The deadlock was transient
+	statusCounts, err = repo.GetStatusCounts(context.Background())
 	assert.NoError(t, err)
-
-	// Test normal operation first
-	count, amount, err := repo.GetTodaySuccessful()
-	assert.NoError(t, err)
-	assert.GreaterOrEqual(t, count, 1)
-	assert.True(t, amount.GreaterThan(decimal.Zero))
-
-	// To test SUM error specifically, we'll corrupt the amount data
-	// by temporarily changing it to an invalid decimal format
-	db.Exec("UPDATE transactions SET amount = 'invalid_decimal' WHERE user_id = ?", 99997)
-
-	// Now call GetTodaySuccessful - Count should succeed but SUM should fail
-	count2, amount2, err2 := repo.GetTodaySuccessful()
-
-	// This might or might not fail depending on MySQL's handling of invalid decimals
-	// If it doesn't fail, let's force a connection error instead
-	if err2 == nil {
-		// Close connection and retry to force error
-		sqlDB.Close()
-		count2, amount2, err2 = repo.GetTodaySuccessful()
-	}
-
-	// One of these scenarios should have produced an error
-	if err2 != nil {
-		assert.Equal(t, 0, count2)
-		assert.True(t, amount2.Equal(decimal.Zero))
-	}
-
-	// Cleanup
-	db = setupTestDB(t)
-	if db != nil {
-		db.Exec("DELETE FROM transactions WHERE user_id = 99997")
-	}
-}
-
-// TestTransactionRepository_GetTodaySuccessful_RealSumError tests the SUM error specifically
-func TestTransactionRepository_GetTodaySuccessful_RealSumError(t *testing.T) {
-	db := setupTestDB(t)
-	if db == nil {
-		return
-	}
-
-	// Create test data first
-	repo := repositories.NewTransactionRepository(db)
-	tx := &models.Transaction{
-		UserID: 99996,
-		Amount: decimal.NewFromFloat(100.50),
-		Status: "success",
-	}
-	repo.Create(tx)
-
-	// Create a function that simulates the exact error scenario
-	getTodaySuccessfulWithSumError := func() (int, decimal.Decimal, error) {
-		var count int64
-		var totalAmount decimal.Decimal
-
-		today := time.Now().Format("2006-01-02")
-
-		// First query (Count) - let it succeed
-		err := db.Model(&models.Transaction{}).
-			Where("status = ? AND DATE(created_at) = ?", "success", today).
-			Count(&count).Error
-		if err != nil {
-			return 0, decimal.Zero, err
-		}
-
-		// Second query (SUM) - force it to fail by closing connection
-		sqlDB, _ := db.DB()
-		sqlDB.Close() // Close connection before SUM query
-
-		err = db.Model(&models.Transaction{}).
-			Select("COALESCE(SUM(amount), 0)").
-			Where("status = ? AND DATE(created_at) = ?", "success", today).
-			Scan(&totalAmount).Error
-		if err != nil {
-			return 0, decimal.Zero, err
-		}
-
-		return int(count), totalAmount, nil
-	}
-
-	// Test the function that should error in SUM part
-	count, amount, err := getTodaySuccessfulWithSumError()
-
-	// This should have errored in the SUM part
-	assert.Error(t, err)
-	assert.Equal(t, 0, count)
-	assert.True(t, amount.Equal(decimal.Zero))
-
-	// Cleanup
-	db = setupTestDB(t)
-	if db != nil {
-		db.Exec("DELETE FROM transactions WHERE user_id = 99996")
-	}
+	assert.Equal(t, 1, statusCounts.Success)
 }
 
-// TestTransactionRepository_GetTodaySuccessful_ForceSumError forces SUM query error
-func TestTransactionRepository_GetTodaySuccessful_ForceSumError(t *testing.T) {
-	db := setupTestDB(t)
-	if db == nil {
-		return
-	}
-
-	// Create a real scenario where Count succeeds but SUM fails
-	// We'll do this by creating multiple connections and manipulating one
+func TestTransactionRepository_Create_Error(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
 	repo := repositories.NewTransactionRepository(db)
+	dbtest.Inject(db).Add(dbtest.Fault{Table: "transactions", Operation: dbtest.Create, Err: dbtest.ConnectionReset()})
 
-	// Create test data
-	tx := &models.Transaction{
-		UserID: 99995,
-		Amount: decimal.NewFromFloat(100.50),
-		Status: "success",
-	}
-	repo.Create(tx)
-
-	// Create a custom implementation that mimics the exact repository behavior
-	// but allows us to inject an error in the SUM part
-	testGetTodaySuccessful := func(dbConn *gorm.DB, shouldFailSum bool) (int, decimal.Decimal, error) {
-		var count int64
-		var totalAmount decimal.Decimal
-
-		today := time.Now().Format("2006-01-02")
+	err := repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromFloat(10), Status: "pending"})
+	assert.ErrorIs(t, err, dbtest.ConnectionReset())
 
-		// First query (Count) - this should succeed
-		err := dbConn.Model(&models.Transaction{}).
-			Where("status = ? AND DATE(created_at) = ?", "success", today).
-			Count(&count).Error
-		if err != nil {
-			return 0, decimal.Zero, err
-		}
-
-		// If we want to force SUM to fail, close the connection now
-		if shouldFailSum {
-			sqlDB, _ := dbConn.DB()
-			sqlDB.Close()
-		}
-
-		// Second query (SUM) - this might fail if connection is closed
-		err = dbConn.Model(&models.Transaction{}).
-			Select("COALESCE(SUM(amount), 0)").
-			Where("status = ? AND DATE(created_at) = ?", "success", today).
-			Scan(&totalAmount).Error
-		if err != nil {
-			return 0, decimal.Zero, err
-		}
-
-		return int(count), totalAmount, nil
-	}
-
-	// Test normal operation first
-	count1, amount1, err1 := testGetTodaySuccessful(db, false)
-	assert.NoError(t, err1)
-	assert.GreaterOrEqual(t, count1, 1)
-	assert.True(t, amount1.GreaterThan(decimal.Zero))
-
-	// Setup fresh connection for the error test
-	db2 := setupTestDB(t)
-	if db2 == nil {
-		return
-	}
-
-	// Test with SUM error
-	count2, amount2, err2 := testGetTodaySuccessful(db2, true)
-	assert.Error(t, err2)
-	assert.Equal(t, 0, count2)
-	assert.True(t, amount2.Equal(decimal.Zero))
-
-	// Cleanup
-	db3 := setupTestDB(t)
-	if db3 != nil {
-		db3.Exec("DELETE FROM transactions WHERE user_id = 99995")
-	}
+	var count int64
+	require.NoError(t, db.Model(&models.Transaction{}).Count(&count).Error)
+	assert.Zero(t, count)
 }
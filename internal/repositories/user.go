@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"interview/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UserRepository interface defines user repository methods
+type UserRepository interface {
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id uint) (*models.User, error)
+	GetAllWithCount(ctx context.Context, filters models.UserFilters) ([]models.User, int64, error)
+	Exists(ctx context.Context, id uint) (bool, error)
+	// EnsureExist creates a placeholder user for each ID that has none
+	EnsureExist(ctx context.Context, ids []uint) error
+}
+
+// userRepository implements UserRepository interface
+type userRepository struct {
+	db *gorm.DB
+}
+
+// NewUserRepository creates a new user repository
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+// Create creates a new user
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
+}
+
+// GetByID gets a user by ID
+func (r *userRepository) GetByID(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).First(&user, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetAllWithCount gets a page of users, oldest first, along with the total number of users
+func (r *userRepository) GetAllWithCount(ctx context.Context, filters models.UserFilters) ([]models.User, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&models.User{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	limit, offset := filters.Page()
+	var users []models.User
+	err := r.db.WithContext(ctx).Order("id").Limit(limit).Offset(offset).Find(&users).Error
+	return users, total, err
+}
+
+// Exists reports whether a user with the given ID exists
+func (r *userRepository) Exists(ctx context.Context, id uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// EnsureExist creates a placeholder user for each ID that has none
+func (r *userRepository) EnsureExist(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	users := make([]models.User, len(ids))
+	for i, id := range ids {
+		users[i] = models.PlaceholderUser(id)
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&users).Error
+}
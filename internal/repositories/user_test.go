@@ -0,0 +1,73 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupUserDB(t *testing.T) *gorm.DB {
+	db := setupSQLiteDB(t, t.Name())
+	require.NoError(t, db.AutoMigrate(&models.User{}))
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users")
+	})
+	return db
+}
+
+func TestUserRepository_CreateAndGet(t *testing.T) {
+	repo := repositories.NewUserRepository(setupUserDB(t))
+
+	for _, email := range []string{"ada@example.com", "grace@example.com", "edsger@example.com"} {
+		require.NoError(t, repo.Create(context.Background(), &models.User{Name: email, Email: email}))
+	}
+	assert.Error(t, repo.Create(context.Background(), &models.User{Name: "Ada", Email: "ada@example.com"}), "emails are unique")
+
+	user, err := repo.GetByID(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "grace@example.com", user.Email)
+
+	_, err = repo.GetByID(context.Background(), 99)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	users, total, err := repo.GetAllWithCount(context.Background(), models.UserFilters{Limit: 2, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, users, 2)
+	assert.Equal(t, uint(2), users[0].ID)
+
+	exists, err := repo.Exists(context.Background(), 3)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = repo.Exists(context.Background(), 99)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestUserRepository_EnsureExist(t *testing.T) {
+	repo := repositories.NewUserRepository(setupUserDB(t))
+
+	require.NoError(t, repo.Create(context.Background(), &models.User{ID: 1, Name: "Ada", Email: "ada@example.com"}))
+	require.NoError(t, repo.EnsureExist(context.Background(), []uint{1, 2}))
+	// Running it again leaves the users as they are
+	require.NoError(t, repo.EnsureExist(context.Background(), []uint{2}))
+
+	user, err := repo.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", user.Name)
+
+	user, err = repo.GetByID(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, models.PlaceholderUser(2).Email, user.Email)
+
+	_, total, err := repo.GetAllWithCount(context.Background(), models.UserFilters{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+}
@@ -0,0 +1,33 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestTransactionRepository_GetByUUID(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewTransactionRepository(db)
+
+	uuid := "9b2f6c1e-3d4a-4f5b-8c6d-7e8f9a0b1c2d"
+	transaction := &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(10), Status: "pending", UUID: &uuid}
+	require.NoError(t, repo.Create(context.Background(), transaction))
+	// Rows created before UUIDs existed keep a NULL UUID without colliding
+	require.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(20), Status: "pending"}))
+	require.NoError(t, repo.Create(context.Background(), &models.Transaction{UserID: 1, Amount: decimal.NewFromInt(30), Status: "pending"}))
+
+	found, err := repo.GetByUUID(context.Background(), uuid)
+	require.NoError(t, err)
+	assert.Equal(t, transaction.ID, found.ID)
+
+	_, err = repo.GetByUUID(context.Background(), "00000000-0000-4000-8000-000000000000")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"time"
+
+	"interview/internal/models"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// VolumeRepository interface defines transaction volume repository methods
+type VolumeRepository interface {
+	GetCreated(from, to time.Time) (count int64, amount decimal.Decimal, err error)
+}
+
+// volumeRepository implements VolumeRepository interface
+type volumeRepository struct {
+	db *gorm.DB
+}
+
+// NewVolumeRepository creates a new volume repository
+func NewVolumeRepository(db *gorm.DB) VolumeRepository {
+	return &volumeRepository{db: db}
+}
+
+// GetCreated gets how many transactions were created in [from, to) and the
+// sum of their absolute amounts, whatever their status. Test transactions
+// are left out.
+func (r *volumeRepository) GetCreated(from, to time.Time) (int64, decimal.Decimal, error) {
+	var volume struct {
+		Count  int64
+		Amount decimal.Decimal
+	}
+	err := r.db.Model(&models.Transaction{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(ABS(amount)), 0) AS amount").
+		Where("created_at >= ? AND created_at < ? AND is_test = ?", from, to, false).
+		Scan(&volume).Error
+	return volume.Count, volume.Amount, err
+}
@@ -0,0 +1,34 @@
+package repositories_test
+
+import (
+	"testing"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolumeRepository_GetCreated(t *testing.T) {
+	db := setupSQLiteDB(t, t.Name())
+	repo := repositories.NewVolumeRepository(db)
+
+	now := time.Now()
+	db.Create(&models.Transaction{UserID: 1, Amount: decimal.NewFromInt(40), Status: "success"})
+	db.Create(&models.Transaction{UserID: 2, Amount: decimal.NewFromInt(10), Status: "failed"})
+	db.Create(&models.Transaction{UserID: 1, Amount: decimal.NewFromInt(-5), Type: models.TransactionTypeAdjustment, Status: "success"})
+	db.Create(&models.Transaction{UserID: 1, Amount: decimal.NewFromInt(999), Status: "success", CreatedAt: now.Add(-2 * time.Hour)})
+
+	count, amount, err := repo.GetCreated(now.Add(-time.Minute), now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.True(t, amount.Equal(decimal.NewFromInt(55)), "expected 55, got %s", amount)
+
+	count, amount, err = repo.GetCreated(now.Add(time.Minute), now.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+	assert.True(t, amount.IsZero())
+}
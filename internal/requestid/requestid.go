@@ -0,0 +1,60 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Header is the HTTP header a request ID is read from and returned in
+const Header = "X-Request-ID"
+
+// Key is the gin context key and log field holding the request ID
+const Key = "request_id"
+
+// MaxLength is the longest request ID accepted from a client
+const MaxLength = 128
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying id
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID ctx carries, or "" without one
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Logger returns a log entry for ctx, with the request ID field when ctx
+// carries one
+func Logger(ctx context.Context) *logrus.Entry {
+	entry := logrus.WithContext(ctx)
+	if id := FromContext(ctx); id != "" {
+		entry = entry.WithField(Key, id)
+	}
+	return entry
+}
+
+// Valid reports whether id is acceptable from a client: at most MaxLength
+// letters, digits, dashes, underscores, dots and colons, so it is safe to
+// log and echo back
+func Valid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
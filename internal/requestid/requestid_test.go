@@ -0,0 +1,38 @@
+package requestid_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"interview/internal/requestid"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	assert.Empty(t, requestid.FromContext(context.Background()))
+
+	ctx := requestid.NewContext(context.Background(), "req-1")
+	assert.Equal(t, "req-1", requestid.FromContext(ctx))
+}
+
+func TestLogger(t *testing.T) {
+	assert.NotContains(t, requestid.Logger(context.Background()).Data, "request_id")
+
+	entry := requestid.Logger(requestid.NewContext(context.Background(), "req-1"))
+	assert.Equal(t, "req-1", entry.Data["request_id"])
+}
+
+func TestValid(t *testing.T) {
+	assert.True(t, requestid.Valid("req-1"))
+	assert.True(t, requestid.Valid("5f0c2a5e-8d51-4c39-9d5e-0b1f5c1c8a7e"))
+	assert.True(t, requestid.Valid("trace:abc_1.2"))
+	assert.True(t, requestid.Valid(strings.Repeat("a", requestid.MaxLength)))
+
+	assert.False(t, requestid.Valid(""))
+	assert.False(t, requestid.Valid(strings.Repeat("a", requestid.MaxLength+1)))
+	assert.False(t, requestid.Valid("with space"))
+	assert.False(t, requestid.Valid("line\nbreak"))
+	assert.False(t, requestid.Valid("<script>"))
+}
@@ -0,0 +1,294 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"interview/internal/config"
+	"interview/internal/handlers"
+	"interview/internal/models"
+	"interview/internal/repositories"
+	"interview/internal/services"
+)
+
+// contractDir holds the published contract files shared with API clients
+const contractDir = "../../docs/contracts"
+
+// contract is a set of interactions a client relies on
+type contract struct {
+	Description  string        `json:"description"`
+	Interactions []interaction `json:"interactions"`
+}
+
+// interaction is a single request and the response the client expects for it
+type interaction struct {
+	Name    string `json:"name"`
+	Given   string `json:"given,omitempty"`
+	Request struct {
+		Method string          `json:"method"`
+		Path   string          `json:"path"`
+		Body   json.RawMessage `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		Status int         `json:"status"`
+		Body   interface{} `json:"body"`
+	} `json:"response"`
+}
+
+// providerStates seeds the database into the state an interaction is given
+var providerStates = map[string]func(db *gorm.DB) error{
+	"a user with id 1 exists": seedUser,
+	"a successful transaction with id 1 exists": func(db *gorm.DB) error {
+		if err := seedUser(db); err != nil {
+			return err
+		}
+		return db.Create(&models.Transaction{ID: 1, UserID: 1, Amount: decimal.NewFromInt(250), Status: "success"}).Error
+	},
+	"a successful transaction with reference ORD-1 exists": func(db *gorm.DB) error {
+		if err := seedUser(db); err != nil {
+			return err
+		}
+		reference := "ORD-1"
+		return db.Create(&models.Transaction{ID: 1, UserID: 1, ReferenceID: &reference, Amount: decimal.NewFromInt(250), Status: "success"}).Error
+	},
+	"a pending transaction with id 1 exists": seedPendingTransaction,
+	"a pending transaction with id 1 and an open case with id 1 exist": func(db *gorm.DB) error {
+		if err := seedPendingTransaction(db); err != nil {
+			return err
+		}
+		return db.Create(&models.Case{ID: 1, Name: "Card testing", Status: models.CaseStatusOpen}).Error
+	},
+}
+
+// seedPendingTransaction creates the pending transaction with id 1
+func seedPendingTransaction(db *gorm.DB) error {
+	if err := seedUser(db); err != nil {
+		return err
+	}
+	return db.Create(&models.Transaction{ID: 1, UserID: 1, Amount: decimal.NewFromInt(250), Status: "pending"}).Error
+}
+
+// seedUser creates the user with id 1 that the transaction states belong to
+func seedUser(db *gorm.DB) error {
+	return db.Create(&models.User{ID: 1, Name: "Ada Lovelace", Email: "ada@example.com"}).Error
+}
+
+// TestContracts replays every published contract against the real router backed by an in-memory database
+func TestContracts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	files, err := filepath.Glob(filepath.Join(contractDir, "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files, "no contract files found in %s", contractDir)
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		var c contract
+		require.NoError(t, json.Unmarshal(data, &c), file)
+
+		for _, i := range c.Interactions {
+			i := i
+			t.Run(filepath.Base(file)+"/"+i.Name, func(t *testing.T) {
+				router := setupContractRouter(t, i.Given)
+
+				req := httptest.NewRequest(i.Request.Method, i.Request.Path, bytes.NewReader(i.Request.Body))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				require.Equal(t, i.Response.Status, w.Code, w.Body.String())
+
+				var actual interface{}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual), w.Body.String())
+				if err := matchContract("body", i.Response.Body, actual); err != nil {
+					t.Errorf("%v\nresponse: %s", err, w.Body.String())
+				}
+			})
+		}
+	}
+}
+
+// TestEndpointExamples replays every example served by /api/meta/examples,
+// so the examples keep passing the validations they document
+func TestEndpointExamples(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	setupContractRouter(t, "").ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/meta/examples", nil))
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	var response struct {
+		Data []models.EndpointExample `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotEmpty(t, response.Data)
+
+	paths := strings.NewReplacer("{id}", "1", "{user_id}", "1")
+	for _, example := range response.Data {
+		example := example
+		t.Run(example.Method+" "+example.Path, func(t *testing.T) {
+			router := setupContractRouter(t, "a pending transaction with id 1 and an open case with id 1 exist")
+
+			body, err := json.Marshal(example.Request)
+			require.NoError(t, err)
+			path := "/api/v1" + paths.Replace(example.Path)
+			if example.Query != "" {
+				path += "?" + example.Query
+			}
+			req := httptest.NewRequest(example.Method, path, bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, example.Status, w.Code, w.Body.String())
+			var actual models.APIResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+			require.Equal(t, example.Response.Message, actual.Message)
+		})
+	}
+}
+
+// setupContractRouter builds the production router over a fresh database in the given provider state
+func setupContractRouter(t *testing.T, state string) *gin.Engine {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.Transaction{}, &models.Budget{}, &models.Tag{}, &models.Case{}, &models.CasePin{}))
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	if state != "" {
+		seed, ok := providerStates[state]
+		require.True(t, ok, "unknown provider state %q", state)
+		require.NoError(t, seed(db))
+	}
+
+	transactionRepo := repositories.NewTransactionRepository(db)
+	budgetRepo := repositories.NewBudgetRepository(db)
+	budgetService := services.NewBudgetService(budgetRepo)
+	tagRepo := repositories.NewTagRepository(db)
+	userRepo := repositories.NewUserRepository(db)
+	userService := services.NewUserService(userRepo)
+
+	return NewRouter(config.RoutesConfig{}, Handlers{
+		Transaction:    handlers.NewTransactionHandler(services.NewTransactionService(transactionRepo, services.WithBudgetService(budgetService), services.WithUserService(userService))),
+		Dashboard:      handlers.NewDashboardHandler(services.NewDashboardService(transactionRepo, services.WithTagSummaries(tagRepo))),
+		Budget:         handlers.NewBudgetHandler(budgetService),
+		Tag:            handlers.NewTagHandler(services.NewTagService(tagRepo, transactionRepo)),
+		User:           handlers.NewUserHandler(userService),
+		Reconciliation: handlers.NewReconciliationHandler(services.NewReconciliationService(transactionRepo)),
+		Case:           handlers.NewCaseHandler(services.NewCaseService(repositories.NewCaseRepository(db), transactionRepo)),
+		Meta:           handlers.NewMetaHandler(),
+		Fixture:        handlers.NewFixtureHandler(services.NewFixtureService(transactionRepo, budgetRepo, userRepo)),
+	})
+}
+
+// matchContract checks actual against expected. Objects may carry extra keys, arrays must hold at
+// least the expected elements, and the strings "$string", "$number" and "$timestamp" match any value of that type.
+func matchContract(path string, expected, actual interface{}) error {
+	switch e := expected.(type) {
+	case string:
+		switch e {
+		case "$string":
+			if _, ok := actual.(string); !ok {
+				return fmt.Errorf("%s: expected a string, got %v", path, actual)
+			}
+			return nil
+		case "$number":
+			if _, ok := actual.(float64); !ok {
+				return fmt.Errorf("%s: expected a number, got %v", path, actual)
+			}
+			return nil
+		case "$timestamp":
+			s, ok := actual.(string)
+			if !ok {
+				return fmt.Errorf("%s: expected a timestamp, got %v", path, actual)
+			}
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				return fmt.Errorf("%s: expected a timestamp, got %q", path, s)
+			}
+			return nil
+		}
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object, got %v", path, actual)
+		}
+		keys := make([]string, 0, len(e))
+		for key := range e {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value, ok := a[key]
+			if !ok {
+				return fmt.Errorf("%s.%s: missing", path, key)
+			}
+			if err := matchContract(path+"."+key, e[key], value); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an array, got %v", path, actual)
+		}
+		if len(a) < len(e) {
+			return fmt.Errorf("%s: expected at least %d elements, got %d", path, len(e), len(a))
+		}
+		for idx := range e {
+			if err := matchContract(fmt.Sprintf("%s[%d]", path, idx), e[idx], a[idx]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if expected != actual {
+		return fmt.Errorf("%s: expected %v, got %v", path, expected, actual)
+	}
+	return nil
+}
+
+func TestMatchContract(t *testing.T) {
+	expected := map[string]interface{}{
+		"id":         "$number",
+		"created_at": "$timestamp",
+		"items":      []interface{}{map[string]interface{}{"name": "a"}},
+	}
+
+	require.NoError(t, matchContract("body", expected, map[string]interface{}{
+		"id":         float64(1),
+		"created_at": "2025-06-28T10:00:00Z",
+		"items":      []interface{}{map[string]interface{}{"name": "a", "extra": true}, "b"},
+		"extra":      "ignored",
+	}))
+	require.EqualError(t, matchContract("body", expected, map[string]interface{}{
+		"id":         "1",
+		"created_at": "2025-06-28T10:00:00Z",
+		"items":      []interface{}{},
+	}), "body.id: expected a number, got 1")
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"interview/internal/config"
+	"interview/internal/database"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Connection pool sizes for the primary and replica databases
+const (
+	maxIdleConns = 10
+	maxOpenConns = 100
+)
+
+// initializeRedis connects to the Redis server replicas share state through
+func initializeRedis(cfg config.RedisConfig) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// initializeDatabase initializes the database connection with the default pool size
+func initializeDatabase(cfg config.DatabaseConfig, creds *database.Credentials) (*gorm.DB, error) {
+	return initializeDatabasePool(cfg, creds, maxOpenConns)
+}
+
+// initializeDatabasePool opens a database connection whose pool holds at most
+// maxOpen connections. With creds set, connections use its rotating
+// credentials instead of cfg's user and password.
+func initializeDatabasePool(cfg config.DatabaseConfig, creds *database.Credentials, maxOpen int) (*gorm.DB, error) {
+	dialector, err := database.MySQLDialector(cfg, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	queryLogger := database.NewQueryLogger(cfg.SlowQueryThreshold, cfg.ExplainSlowQueries)
+	if logrus.IsLevelEnabled(logrus.DebugLevel) {
+		queryLogger = queryLogger.LogMode(logger.Info).(*database.QueryLogger)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: queryLogger, TranslateError: true})
+	if err != nil {
+		return nil, err
+	}
+	if err := queryLogger.Attach(db); err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	// Configure connection pool
+	sqlDB.SetMaxIdleConns(min(maxIdleConns, maxOpen))
+	sqlDB.SetMaxOpenConns(maxOpen)
+
+	logrus.Info("Database connection established")
+	return db, nil
+}
+
+// pingDatabase checks that db accepts connections
+func pingDatabase(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// closeDatabase closes the connections of db
+func closeDatabase(db *gorm.DB) {
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
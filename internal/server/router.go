@@ -0,0 +1,80 @@
+package server
+
+import (
+	"interview/internal/config"
+	"interview/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Builder assembles a router whose route groups each get their own
+// middleware chain, configured by name in config.RoutesConfig. The chain of
+// a group assigns request IDs, logs requests at its level, recovers from
+// panics, applies CORS when enabled and sets its Cache-Control policy.
+//
+// The API has no authentication or rate limiting, so chains have no stage
+// for them; they would be added to chain like the others.
+type Builder struct {
+	engine *gin.Engine
+	routes config.RoutesConfig
+	cors   bool
+}
+
+// Option configures optional builder behavior
+type Option func(*Builder)
+
+// WithCORS adds the CORS middleware to every chain
+func WithCORS() Option {
+	return func(b *Builder) {
+		b.cors = true
+	}
+}
+
+// NewBuilder creates a builder for a new router
+func NewBuilder(routes config.RoutesConfig, opts ...Option) *Builder {
+	b := &Builder{engine: gin.New(), routes: routes}
+	for _, opt := range opts {
+		opt(b)
+	}
+	// Requests that match no route still get logged and answered
+	b.engine.NoRoute(b.chain(config.DefaultRouteGroup)...)
+	return b
+}
+
+// Group adds a route group at path with the middleware chain of the named
+// group, followed by handlers
+func (b *Builder) Group(name, path string, handlers ...gin.HandlerFunc) *gin.RouterGroup {
+	return b.engine.Group(path, append(b.chain(b.routes.Group(name)), handlers...)...)
+}
+
+// Handle adds a single route with the middleware chain of the named group
+func (b *Builder) Handle(name, method, path string, handler gin.HandlerFunc) {
+	b.engine.Handle(method, path, append(b.chain(b.routes.Group(name)), handler)...)
+}
+
+// Engine returns the router
+func (b *Builder) Engine() *gin.Engine {
+	return b.engine
+}
+
+// chain returns the middleware for a group configured by group. Config
+// validates log levels, so an unknown one falls back to info.
+func (b *Builder) chain(group config.RouteGroupConfig) []gin.HandlerFunc {
+	chain := []gin.HandlerFunc{middleware.RequestIDMiddleware()}
+	if group.LogLevel != "none" {
+		level, err := logrus.ParseLevel(group.LogLevel)
+		if err != nil {
+			level = logrus.InfoLevel
+		}
+		chain = append(chain, middleware.LevelLoggerMiddleware(level))
+	}
+	chain = append(chain, middleware.RecoveryMiddleware())
+	if b.cors {
+		chain = append(chain, middleware.CORSMiddleware())
+	}
+	if group.CacheMaxAge > 0 {
+		chain = append(chain, middleware.CacheControlMiddleware(group.CacheMaxAge))
+	}
+	return chain
+}
@@ -0,0 +1,102 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"interview/internal/config"
+	"interview/internal/server"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ok(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "OK"})
+}
+
+func TestBuilder_GroupLogLevels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	builder := server.NewBuilder(config.RoutesConfig{
+		"dashboard": {LogLevel: "warn"},
+		"health":    {LogLevel: "none"},
+	})
+	builder.Group("dashboard", "/dashboard").GET("/summary", ok)
+	builder.Group("users", "/users").GET("", ok)
+	builder.Handle("health", http.MethodGet, "/health", ok)
+	router := builder.Engine()
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/dashboard/summary", nil))
+	require.Len(t, hook.AllEntries(), 1)
+	assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+
+	// Groups left out of the configuration log at info
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users", nil))
+	require.Len(t, hook.AllEntries(), 2)
+	assert.Equal(t, logrus.InfoLevel, hook.LastEntry().Level)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health", nil))
+	assert.Len(t, hook.AllEntries(), 2)
+
+	// Unknown routes are still logged
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	require.Len(t, hook.AllEntries(), 3)
+	assert.Equal(t, http.StatusNotFound, hook.LastEntry().Data["status_code"])
+
+	// Every record carries the request ID returned to the client
+	assert.NotEmpty(t, w.Header().Get("X-Request-ID"))
+	assert.Equal(t, w.Header().Get("X-Request-ID"), hook.LastEntry().Data["request_id"])
+}
+
+func TestBuilder_GroupCacheMaxAge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	builder := server.NewBuilder(config.RoutesConfig{"dashboard": {LogLevel: "info", CacheMaxAge: 30 * time.Second}})
+	dashboard := builder.Group("dashboard", "/dashboard")
+	dashboard.GET("/summary", ok)
+	dashboard.GET("/broken", func(c *gin.Context) { c.JSON(http.StatusInternalServerError, gin.H{}) })
+	builder.Group("users", "/users").GET("", ok)
+	router := builder.Engine()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/dashboard/summary", nil))
+	assert.Equal(t, "private, max-age=30", w.Header().Get("Cache-Control"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/dashboard/broken", nil))
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/users", nil))
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+}
+
+func TestBuilder_RecoversAndAppliesCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	builder := server.NewBuilder(config.RoutesConfig{}, server.WithCORS())
+	transactions := builder.Group("transactions", "/transactions")
+	transactions.GET("", func(c *gin.Context) { panic("boom") })
+	transactions.OPTIONS("", ok)
+	router := builder.Engine()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/transactions", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	req := httptest.NewRequest("OPTIONS", "/transactions", nil)
+	req.Header.Set("Origin", "https://dashboard.test")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
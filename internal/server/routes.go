@@ -0,0 +1,199 @@
+package server
+
+import (
+	"expvar"
+	"net/http"
+
+	"interview/internal/config"
+	"interview/internal/handlers"
+	"interview/internal/versioning"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handlers are the handlers the API routes requests to
+type Handlers struct {
+	Transaction    *handlers.TransactionHandler
+	Dashboard      *handlers.DashboardHandler
+	Budget         *handlers.BudgetHandler
+	Tag            *handlers.TagHandler
+	User           *handlers.UserHandler
+	Reconciliation *handlers.ReconciliationHandler
+	Case           *handlers.CaseHandler
+	Meta           *handlers.MetaHandler
+
+	// Fixture serves the admin endpoints; nil leaves them out, as when they
+	// are served by the admin listener
+	Fixture *handlers.FixtureHandler
+
+	// UI serves the admin UI under /ui; nil leaves it out
+	UI http.Handler
+
+	// Health serves /health/ready; nil leaves it out
+	Health *handlers.HealthHandler
+
+	// Feed serves the live transaction feed; nil leaves it out
+	Feed *handlers.FeedHandler
+}
+
+// NewRouter configures the HTTP router. Each route group gets the
+// middleware chain routes configures for it.
+func NewRouter(routes config.RoutesConfig, h Handlers) *gin.Engine {
+	builder := NewBuilder(routes, WithCORS())
+
+	// API routes, mounted under /api/v1 and under /api, which serves the
+	// version the client negotiates
+	registerAPIRoutes(builder, "/api/"+versioning.V1, versioning.Pin(versioning.V1), h)
+	registerAPIRoutes(builder, "/api", versioning.Negotiate(), h)
+
+	// Health check endpoints
+	registerHealthRoutes(builder, h.Health)
+
+	// Admin UI, calling the API routes above from the browser
+	if h.UI != nil {
+		builder.Group("ui", "/ui").GET("/*filepath", gin.WrapH(http.StripPrefix("/ui", h.UI)))
+	}
+
+	return builder.Engine()
+}
+
+// registerAPIRoutes adds the public API endpoints under prefix, each group
+// running version after its middleware chain
+func registerAPIRoutes(builder *Builder, prefix string, version gin.HandlerFunc, h Handlers) {
+	// Transaction routes
+	transactions := builder.Group("transactions", prefix+"/transactions", version)
+	{
+		transactions.POST("", h.Transaction.CreateTransaction)
+		transactions.GET("", h.Transaction.GetTransactions)
+		transactions.POST("/bulk", h.Transaction.CreateTransactions)
+		transactions.POST("/lookup", h.Transaction.LookupTransactions)
+		transactions.GET("/by-reference/:ref", h.Transaction.GetTransactionByReference)
+		transactions.GET("/jobs/:job_id", h.Transaction.GetCreateJob)
+		transactions.GET("/export", h.Transaction.ExportTransactions)
+		transactions.GET("/:id", h.Transaction.GetTransaction)
+		transactions.PUT("/:id", h.Transaction.UpdateTransaction)
+		transactions.PATCH("/:id", h.Transaction.PatchTransaction)
+		transactions.DELETE("/:id", h.Transaction.DeleteTransaction)
+		transactions.POST("/:id/refund", h.Transaction.RefundTransaction)
+		transactions.GET("/:id/wait", h.Transaction.WaitForStatusChange)
+		transactions.GET("/:id/status", h.Transaction.GetTransactionStatus)
+		transactions.POST("/:id/tags", h.Tag.AddTags)
+		transactions.DELETE("/:id/tags/:tag", h.Tag.RemoveTag)
+		transactions.OPTIONS("", h.Transaction.DescribeTransactions)
+		transactions.OPTIONS("/:id", h.Transaction.DescribeTransaction)
+	}
+
+	// Live transaction feed, in the transactions group's chain
+	if h.Feed != nil {
+		builder.Group("transactions", prefix+"/ws", version).GET("/transactions", h.Feed.StreamTransactions)
+	}
+
+	// Dashboard routes
+	dashboard := builder.Group("dashboard", prefix+"/dashboard", version)
+	{
+		dashboard.GET("/summary", h.Dashboard.GetSummary)
+		dashboard.GET("/export", h.Dashboard.ExportSummary)
+		dashboard.GET("/top-users", h.Dashboard.GetTopUsers)
+		dashboard.OPTIONS("/summary", h.Dashboard.DescribeSummary)
+	}
+
+	// Budget routes
+	budgets := builder.Group("budgets", prefix+"/budgets", version)
+	{
+		budgets.GET("/:user_id", h.Budget.GetBudget)
+		budgets.PUT("/:user_id", h.Budget.SetBudget)
+		budgets.OPTIONS("/:user_id", h.Budget.DescribeBudget)
+	}
+
+	// User routes
+	users := builder.Group("users", prefix+"/users", version)
+	{
+		users.POST("", h.User.CreateUser)
+		users.GET("", h.User.GetUsers)
+		users.GET("/:id", h.User.GetUser)
+		users.OPTIONS("", h.User.DescribeUsers)
+		users.OPTIONS("/:id", h.User.DescribeUser)
+	}
+
+	// Reconciliation routes
+	reconciliation := builder.Group("reconciliation", prefix+"/reconciliation", version)
+	{
+		reconciliation.POST("/compare", h.Reconciliation.Compare)
+	}
+
+	// Investigation case routes
+	cases := builder.Group("cases", prefix+"/cases", version)
+	{
+		cases.POST("", h.Case.CreateCase)
+		cases.GET("", h.Case.GetCases)
+		cases.GET("/:id", h.Case.GetCase)
+		cases.POST("/:id/close", h.Case.CloseCase)
+		cases.GET("/:id/transactions", h.Case.GetPinnedTransactions)
+		cases.POST("/:id/transactions", h.Case.PinTransactions)
+		cases.DELETE("/:id/transactions/:transaction_id", h.Case.UnpinTransaction)
+	}
+
+	// Meta routes
+	meta := builder.Group("meta", prefix+"/meta", version)
+	{
+		meta.GET("/examples", h.Meta.GetExamples)
+	}
+
+	// Admin routes, unless they are served by the admin listener
+	if h.Fixture != nil {
+		registerAdminRoutes(builder.Group("admin", prefix+"/admin", version), h.Fixture)
+	}
+}
+
+// NewAdminRouter configures the router for the separate admin listener. The
+// correction endpoints change transactions on the word of the operators
+// named in the request, so they are only served here, never on the public
+// router; a nil correctionHandler leaves them out.
+func NewAdminRouter(routes config.RoutesConfig, fixtureHandler *handlers.FixtureHandler, correctionHandler *handlers.CorrectionHandler, healthHandler *handlers.HealthHandler) *gin.Engine {
+	builder := NewBuilder(routes)
+
+	for _, admin := range []*gin.RouterGroup{
+		builder.Group("admin", "/api/"+versioning.V1+"/admin", versioning.Pin(versioning.V1)),
+		builder.Group("admin", "/api/admin", versioning.Negotiate()),
+	} {
+		registerAdminRoutes(admin, fixtureHandler)
+		if correctionHandler != nil {
+			registerCorrectionRoutes(admin, correctionHandler)
+		}
+	}
+	registerHealthRoutes(builder, healthHandler)
+
+	return builder.Engine()
+}
+
+// registerAdminRoutes adds the admin endpoints to a route group
+func registerAdminRoutes(admin *gin.RouterGroup, fixtureHandler *handlers.FixtureHandler) {
+	admin.GET("/fixtures", fixtureHandler.ExportFixture)
+	admin.OPTIONS("/fixtures", fixtureHandler.DescribeFixtures)
+	admin.GET("/metrics", gin.WrapH(expvar.Handler()))
+}
+
+// registerCorrectionRoutes adds the transaction correction endpoints to an
+// admin route group
+func registerCorrectionRoutes(admin *gin.RouterGroup, correctionHandler *handlers.CorrectionHandler) {
+	admin.POST("/transactions/:id/corrections", correctionHandler.RequestCorrection)
+	admin.GET("/transactions/:id/corrections", correctionHandler.GetTransactionCorrections)
+	admin.GET("/corrections/:id", correctionHandler.GetCorrection)
+	admin.POST("/corrections/:id/approve", correctionHandler.ApproveCorrection)
+}
+
+// registerHealthRoutes adds the liveness endpoints, /health and
+// /health/live, and the readiness endpoint unless healthHandler is nil
+func registerHealthRoutes(builder *Builder, healthHandler *handlers.HealthHandler) {
+	builder.Handle("health", http.MethodGet, "/health", healthCheck)
+	builder.Handle("health", http.MethodGet, "/health/live", healthCheck)
+	if healthHandler != nil {
+		builder.Handle("health", http.MethodGet, "/health/ready", healthHandler.Ready)
+	}
+}
+
+// healthCheck reports that the server is up, without checking its
+// dependencies
+func healthCheck(c *gin.Context) {
+	c.JSON(200, gin.H{"status": "OK"})
+}
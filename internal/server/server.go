@@ -0,0 +1,467 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"interview/internal/config"
+	"interview/internal/database"
+	"interview/internal/events"
+	"interview/internal/handlers"
+	"interview/internal/models"
+	"interview/internal/probe"
+	"interview/internal/repositories"
+	"interview/internal/services"
+	"interview/internal/ui"
+	"interview/internal/vault"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// shutdownTimeout bounds how long in-flight requests may take to finish once
+// the server is stopping
+const shutdownTimeout = 10 * time.Second
+
+// Names of the leases that elect the replica running the synthetic probe
+// and raising volume alerts
+const (
+	probeLease         = "trxgo_synthetic_probe"
+	volumeMonitorLease = "trxgo_volume_monitor"
+)
+
+// Server is the API with the dependencies its configuration calls for:
+// databases, Redis, background workers and the routers serving requests.
+type Server struct {
+	// Router serves the public API
+	Router *gin.Engine
+
+	// AdminRouter serves the admin endpoints when an admin listener is
+	// configured, and is nil otherwise
+	AdminRouter *gin.Engine
+
+	// DB is the primary database
+	DB *gorm.DB
+
+	cfg           *config.Config
+	schemaVersion string
+	closers       []func()
+}
+
+// Run starts the API as cfg configures it and serves requests until ctx is
+// done, then shuts down gracefully and releases its dependencies. opts are
+// applied to the transaction service after the configured options, which is
+// how a binary embedding the API registers lifecycle hooks.
+func Run(ctx context.Context, cfg *config.Config, opts ...services.TransactionServiceOption) error {
+	s, err := New(cfg, opts...)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	return s.Serve(ctx)
+}
+
+// New connects to the databases and services cfg names, runs the migrations
+// and wires the API, applying opts to the transaction service as Run does.
+// Background workers start right away; Close stops them.
+func New(cfg *config.Config, opts ...services.TransactionServiceOption) (*Server, error) {
+	s := &Server{cfg: cfg}
+	if err := s.init(opts); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close stops background workers and closes connections, in the reverse
+// order they were started
+func (s *Server) Close() {
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		s.closers[i]()
+	}
+	s.closers = nil
+}
+
+// onClose registers f to be run by Close
+func (s *Server) onClose(f func()) {
+	s.closers = append(s.closers, f)
+}
+
+// Serve listens on the configured addresses until ctx is done or a listener
+// fails, then waits up to shutdownTimeout for in-flight requests
+func (s *Server) Serve(ctx context.Context) error {
+	logrus.WithFields(startupFields(s.cfg, s.DB.Dialector.Name(), s.schemaVersion)).Info("Starting server")
+
+	servers := []*http.Server{newHTTPServer(s.cfg.Server, s.cfg.Server.Address(), s.Router)}
+	if s.AdminRouter != nil {
+		servers = append(servers, newHTTPServer(s.cfg.Server, s.cfg.Server.AdminAddress(), s.AdminRouter))
+	}
+
+	failed := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				failed <- fmt.Errorf("failed to serve %s: %v", srv.Addr, err)
+			}
+		}()
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+		logrus.Info("Shutting down server")
+	case err = <-failed:
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	for _, srv := range servers {
+		if shutdownErr := srv.Shutdown(shutdownCtx); shutdownErr != nil && err == nil {
+			err = fmt.Errorf("failed to shut down %s: %v", srv.Addr, shutdownErr)
+		}
+	}
+	return err
+}
+
+// newHTTPServer builds a listener for handler with the configured
+// connection timeouts and header limit
+func newHTTPServer(cfg config.ServerConfig, addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+}
+
+// init builds the server's dependencies and routers
+func (s *Server) init(transactionOptions []services.TransactionServiceOption) error {
+	cfg := s.cfg
+
+	// Database credentials come from Vault, and are rotated, when a role is configured
+	var dbCredentials *database.Credentials
+	if cfg.Vault.Enabled() {
+		creds, stopCredentials, err := vault.StartDatabaseCredentials(cfg.Vault)
+		if err != nil {
+			return fmt.Errorf("failed to get database credentials from Vault: %v", err)
+		}
+		s.onClose(stopCredentials)
+		dbCredentials = creds
+	}
+
+	// Initialize database
+	db, err := initializeDatabase(cfg.Database, dbCredentials)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %v", err)
+	}
+	s.onClose(func() { closeDatabase(db) })
+	s.DB = db
+
+	// /health/ready checks every dependency requests need
+	healthChecks := []handlers.HealthCheck{{
+		Name:  "database",
+		Check: func(ctx context.Context) error { return pingDatabase(ctx, db) },
+	}}
+
+	// Run migrations, one instance at a time. Async creates are queued for
+	// the consumer, which records their outcome.
+	migrated := models.SchemaModels()
+	if cfg.Transaction.AsyncCreate {
+		migrated = append(migrated, &models.ProcessedMessage{})
+	}
+	err = database.WithMigrationLock(db, cfg.Database.MigrationLockTimeout, func() error {
+		// Refuse to write with an older schema than the database was
+		// migrated to by a newer binary
+		version, err := database.CheckConfiguredSchema(db, cfg.Amount.Precision, cfg.Amount.Scale)
+		if err != nil {
+			return err
+		}
+		s.schemaVersion = version
+		if err := database.ConfigureAmountColumn(db, cfg.Amount.Precision, cfg.Amount.Scale); err != nil {
+			return fmt.Errorf("failed to configure amount column: %v", err)
+		}
+		if err := database.MigrateUsers(db); err != nil {
+			return fmt.Errorf("failed to migrate database: %v", err)
+		}
+		if err := db.AutoMigrate(migrated...); err != nil {
+			return fmt.Errorf("failed to migrate database: %v", err)
+		}
+		if err := database.RecordSchemaVersion(db, s.schemaVersion); err != nil {
+			return fmt.Errorf("failed to record schema version: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Initialize dependencies
+	transactionRepo := repositories.NewTransactionRepository(db)
+	var replica *gorm.DB
+	var replicaOptions repositories.ReplicaOptions
+	if cfg.Database.HasReplica() {
+		replica, err = initializeDatabase(cfg.Database.Replica(), dbCredentials)
+		if err != nil {
+			return fmt.Errorf("failed to initialize read replica: %v", err)
+		}
+		s.onClose(func() { closeDatabase(replica) })
+		healthChecks = append(healthChecks, handlers.HealthCheck{
+			Name:  "read_replica",
+			Check: func(ctx context.Context) error { return pingDatabase(ctx, replica) },
+		})
+
+		// Route reads back to the primary while the replica is lagging
+		lagMonitor := repositories.NewReplicaLagMonitor(repositories.ReplicationStatusProbe(replica), cfg.Database.ReplicaMaxLag)
+		s.onClose(lagMonitor.Start(cfg.Database.ReplicaLagCheckInterval))
+
+		replicaOptions = repositories.ReplicaOptions{
+			HedgeDelay: cfg.Database.HedgeDelay,
+			LagMonitor: lagMonitor,
+		}
+		transactionRepo = repositories.NewTransactionRepositoryWithReplica(db, replica, replicaOptions)
+	}
+
+	// Dashboard queries run on their own, smaller pools so a burst of heavy
+	// summaries cannot take the connections creates and updates need
+	dashboardDB, dashboardReplica := db, replica
+	if cfg.Database.HasDashboardPool() {
+		dashboardDB, err = initializeDatabasePool(cfg.Database, dbCredentials, cfg.Database.DashboardMaxOpenConns)
+		if err != nil {
+			return fmt.Errorf("failed to initialize dashboard database pool: %v", err)
+		}
+		s.onClose(func() { closeDatabase(dashboardDB) })
+
+		if replica != nil {
+			dashboardReplica, err = initializeDatabasePool(cfg.Database.Replica(), dbCredentials, cfg.Database.DashboardMaxOpenConns)
+			if err != nil {
+				return fmt.Errorf("failed to initialize dashboard read replica pool: %v", err)
+			}
+			s.onClose(func() { closeDatabase(dashboardReplica) })
+		}
+	}
+	amountPolicy := services.AmountPolicy{
+		Precision:         cfg.Amount.Precision,
+		Scale:             cfg.Amount.Scale,
+		DefaultCurrency:   cfg.Amount.DefaultCurrency,
+		CurrencyScales:    cfg.Amount.CurrencyScales,
+		Rounding:          cfg.Amount.Rounding,
+		CurrencyRoundings: cfg.Amount.CurrencyRoundings,
+	}
+	if err := amountPolicy.Check(); err != nil {
+		return fmt.Errorf("invalid amount configuration: %v", err)
+	}
+	newDashboardService := func(db, replica *gorm.DB) services.DashboardService {
+		repo := repositories.NewTransactionRepository(db)
+		if replica != nil {
+			repo = repositories.NewTransactionRepositoryWithReplica(db, replica, replicaOptions)
+		}
+		return services.NewDashboardService(repo,
+			services.WithTagSummaries(repositories.NewTagRepository(db)),
+			services.WithSummaryAmountPolicy(amountPolicy),
+		)
+	}
+	budgetService := services.NewBudgetService(repositories.NewBudgetRepository(db), services.WithBudgetAmountPolicy(amountPolicy))
+	userRepo := repositories.NewUserRepository(db)
+	userService := services.NewUserService(userRepo)
+	tagRepo := repositories.NewTagRepository(db)
+
+	// Events and cached statuses stay in process unless Redis is configured,
+	// in which case every replica shares them
+	var eventBus events.Broker = events.NewBus()
+	statusCache := services.WithStatusCacheTTL(cfg.Transaction.StatusCacheTTL)
+	if cfg.Redis.Enabled() {
+		redisClient, err := initializeRedis(cfg.Redis)
+		if err != nil {
+			return fmt.Errorf("failed to connect to Redis: %v", err)
+		}
+		s.onClose(func() { redisClient.Close() })
+		healthChecks = append(healthChecks, handlers.HealthCheck{
+			Name:  "redis",
+			Check: func(ctx context.Context) error { return redisClient.Ping(ctx).Err() },
+		})
+
+		broker, err := events.NewRedisBroker(context.Background(), redisClient)
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to transaction events: %v", err)
+		}
+		s.onClose(func() { broker.Close() })
+
+		eventBus = broker
+		statusCache = services.WithStatusCache(services.NewRedisStatusCache(redisClient, cfg.Transaction.StatusCacheTTL))
+	}
+
+	transactionService := services.NewTransactionService(transactionRepo, append([]services.TransactionServiceOption{
+		services.WithAmountPolicy(amountPolicy),
+		services.WithBudgetService(budgetService),
+		services.WithUserService(userService),
+		services.WithEventBus(eventBus),
+		statusCache,
+	}, transactionOptions...)...)
+	dashboardService := newDashboardService(dashboardDB, dashboardReplica)
+
+	// Push created and updated transactions to WebSocket clients
+	transactionFeed := services.NewTransactionFeed(eventBus, transactionService)
+	s.onClose(transactionFeed.Start())
+
+	// Summaries requested with include_test=true count test transactions and are never cached
+	testDataReplica := dashboardReplica
+	if testDataReplica != nil {
+		testDataReplica = repositories.IncludeTestData(testDataReplica)
+	}
+	testDataDashboardService := newDashboardService(repositories.IncludeTestData(dashboardDB), testDataReplica)
+
+	// Warm the dashboard cache so the first request after deploy isn't a cold query
+	if cfg.Dashboard.CacheEnabled {
+		cachedDashboardService := services.NewCachedDashboardService(dashboardService)
+		if err := cachedDashboardService.Refresh(context.Background()); err != nil {
+			logrus.WithError(err).Warn("Failed to warm dashboard cache")
+		}
+		s.onClose(cachedDashboardService.StartRefresh(cfg.Dashboard.CacheRefreshInterval))
+		dashboardService = cachedDashboardService
+	}
+
+	// Alert on minutes whose creation volume is far from the recent baseline
+	if cfg.Anomaly.Enabled {
+		volumeMonitor := services.NewVolumeMonitor(repositories.NewVolumeRepository(db), cfg.Anomaly.Window, cfg.Anomaly.Threshold, cfg.Anomaly.MinRate)
+		if err := volumeMonitor.Prime(); err != nil {
+			logrus.WithError(err).Warn("Failed to prime transaction volume baseline")
+		}
+		lease := database.NewLease(db, volumeMonitorLease)
+		s.onClose(lease.Release)
+		s.onClose(volumeMonitor.Start(lease.Held))
+	}
+
+	var handlerOptions []handlers.TransactionHandlerOption
+	if cfg.Transaction.AsyncCreate {
+		queue := &kafka.Writer{
+			Addr:     kafka.TCP(cfg.Kafka.Brokers...),
+			Topic:    cfg.Kafka.Topic,
+			Balancer: &kafka.Hash{},
+		}
+		s.onClose(func() { queue.Close() })
+		handlerOptions = append(handlerOptions, handlers.WithCreateJobs(services.NewCreateJobService(queue, repositories.NewProcessedMessageRepository(db))))
+	}
+
+	h := Handlers{
+		Transaction:    handlers.NewTransactionHandler(transactionService, handlerOptions...),
+		Dashboard:      handlers.NewDashboardHandler(dashboardService, handlers.WithTestDataSummary(testDataDashboardService)),
+		Budget:         handlers.NewBudgetHandler(budgetService),
+		Tag:            handlers.NewTagHandler(services.NewTagService(tagRepo, transactionRepo)),
+		User:           handlers.NewUserHandler(userService),
+		Reconciliation: handlers.NewReconciliationHandler(services.NewReconciliationService(transactionRepo)),
+		Case:           handlers.NewCaseHandler(services.NewCaseService(repositories.NewCaseRepository(db), transactionRepo)),
+		Meta:           handlers.NewMetaHandler(),
+		Fixture:        handlers.NewFixtureHandler(services.NewFixtureService(transactionRepo, repositories.NewBudgetRepository(db), userRepo)),
+		Feed:           handlers.NewFeedHandler(transactionFeed),
+	}
+
+	// Migrations run at startup, but a rollback or a failover to a stale
+	// replica can still leave the schema behind this binary
+	healthChecks = append(healthChecks, handlers.HealthCheck{
+		Name:  "migrations",
+		Check: func(ctx context.Context) error { return database.CheckMigrations(db.WithContext(ctx), migrated...) },
+	})
+	h.Health = handlers.NewHealthHandler(cfg.Server.ReadyTimeout, healthChecks...)
+
+	if cfg.Server.UIEnabled {
+		h.UI = ui.Handler()
+	}
+
+	// Admin endpoints move to their own listener when one is configured.
+	// Transaction corrections are only served there, behind a proxy that
+	// authenticates the operators, as the public API has no authentication.
+	if cfg.Server.HasAdminListener() {
+		var correctionHandler *handlers.CorrectionHandler
+		if cfg.Server.ServesCorrections() {
+			correctionService := services.NewCorrectionService(repositories.NewCorrectionRepository(db), transactionRepo,
+				services.WithCorrectionAmountPolicy(amountPolicy),
+				services.WithCorrectionUserService(userService),
+				services.WithCorrectionEventBus(eventBus),
+			)
+			correctionHandler = handlers.NewCorrectionHandler(correctionService, cfg.Server.AdminOperatorHeader)
+		}
+		s.AdminRouter = NewAdminRouter(cfg.Routes, h.Fixture, correctionHandler, h.Health)
+		h.Fixture = nil
+	}
+	if !cfg.Server.ServesCorrections() {
+		logrus.Info("Transaction corrections disabled: set ADMIN_PORT and ADMIN_OPERATOR_HEADER to serve them on the admin listener")
+	}
+	s.Router = NewRouter(cfg.Routes, h)
+
+	// Only trusted proxies can set the client IP through X-Forwarded-For
+	for _, engine := range []*gin.Engine{s.Router, s.AdminRouter} {
+		if engine == nil {
+			continue
+		}
+		if err := trustProxies(engine, cfg.Server.TrustedProxies); err != nil {
+			return fmt.Errorf("invalid trusted proxies: %v", err)
+		}
+	}
+
+	// gin decodes every JSON body with the same settings, so this applies
+	// process-wide rather than per router
+	binding.EnableDecoderDisallowUnknownFields = cfg.Server.StrictJSON
+
+	// Exercise the API end to end with a synthetic transaction
+	if cfg.Probe.Enabled() {
+		lease := database.NewLease(db, probeLease)
+		s.onClose(lease.Release)
+		s.onClose(probe.New(s.Router, cfg.Probe.UserID).Start(cfg.Probe.Interval, lease.Held))
+	}
+	return nil
+}
+
+// trustProxies makes engine trust the forwarding headers of proxies only;
+// nil leaves gin's default of trusting every proxy
+func trustProxies(engine *gin.Engine, proxies []string) error {
+	if proxies == nil {
+		return nil
+	}
+	return engine.SetTrustedProxies(proxies)
+}
+
+// startupFields describes the effective configuration in a single log record
+// so it is clear which settings a running instance actually loaded
+func startupFields(cfg *config.Config, driver, schemaVersion string) logrus.Fields {
+	fields := logrus.Fields{
+		"config": cfg.Redacted(),
+		"features": map[string]bool{
+			"dashboard_cache":      cfg.Dashboard.CacheEnabled,
+			"read_replica":         cfg.Database.HasReplica(),
+			"hedged_reads":         cfg.Database.HasReplica() && cfg.Database.HedgeDelay > 0,
+			"explain_slow_queries": cfg.Database.ExplainSlowQueries,
+			"dashboard_pool":       cfg.Database.HasDashboardPool(),
+			"async_create":         cfg.Transaction.AsyncCreate,
+			"db_failover":          cfg.Database.HasFailover(),
+			"vault_credentials":    cfg.Vault.Enabled(),
+			"shared_state":         cfg.Redis.Enabled(),
+			"anomaly_detection":    cfg.Anomaly.Enabled,
+			"synthetic_probe":      cfg.Probe.Enabled(),
+			"admin_ui":             cfg.Server.UIEnabled,
+		},
+		"db_driver":         driver,
+		"db_max_idle_conns": maxIdleConns,
+		"db_max_open_conns": maxOpenConns,
+		"address":           cfg.Server.Address(),
+		"schema_version":    schemaVersion,
+	}
+	if cfg.Database.HasFailover() {
+		fields["db_hosts"] = cfg.Database.Hosts()
+	}
+	if cfg.Database.HasDashboardPool() {
+		fields["db_dashboard_max_open_conns"] = cfg.Database.DashboardMaxOpenConns
+	}
+	if cfg.Server.HasAdminListener() {
+		fields["admin_address"] = cfg.Server.AdminAddress()
+	}
+	return fields
+}
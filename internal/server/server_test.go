@@ -0,0 +1,705 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"interview/internal/config"
+	"interview/internal/handlers"
+	"interview/internal/models"
+	"interview/internal/services"
+	"interview/internal/ui"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// MockTransactionService for testing
+type MockTransactionService struct {
+	mock.Mock
+}
+
+func (m *MockTransactionService) CreateTransaction(ctx context.Context, req models.CreateTransactionRequest) (*models.Transaction, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) CreateTransactions(ctx context.Context, reqs []models.CreateTransactionRequest) (*models.BulkCreateResult, error) {
+	args := m.Called(reqs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BulkCreateResult), args.Error(1)
+}
+
+func (m *MockTransactionService) GetTransaction(ctx context.Context, id uint) (*models.Transaction, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) GetTransactionByReference(ctx context.Context, referenceID string) (*models.Transaction, error) {
+	args := m.Called(referenceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) ResolveTransactionID(ctx context.Context, uuid string) (uint, error) {
+	args := m.Called(uuid)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockTransactionService) GetTransactionStatus(ctx context.Context, id uint) (*models.TransactionStatus, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TransactionStatus), args.Error(1)
+}
+
+func (m *MockTransactionService) GetTransactions(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, error) {
+	args := m.Called(filters)
+	return args.Get(0).([]models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) ExportTransactions(ctx context.Context, filters models.TransactionFilters, fn func(*models.Transaction) error) error {
+	args := m.Called(filters)
+	for _, transaction := range args.Get(0).([]models.Transaction) {
+		if err := fn(&transaction); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTransactionService) GetTransactionsWithCount(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, int64, error) {
+	args := m.Called(filters)
+	return args.Get(0).([]models.Transaction), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTransactionService) LookupTransactions(ctx context.Context, ids []uint) (*models.TransactionLookupResult, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TransactionLookupResult), args.Error(1)
+}
+
+func (m *MockTransactionService) UpdateTransactionStatus(ctx context.Context, id uint, status string) error {
+	args := m.Called(id, status)
+	return args.Error(0)
+}
+
+func (m *MockTransactionService) UpdateTransaction(ctx context.Context, id uint, req models.UpdateTransactionRequest) error {
+	args := m.Called(id, req)
+	return args.Error(0)
+}
+
+func (m *MockTransactionService) PatchTransaction(ctx context.Context, id uint, req models.PatchTransactionRequest) (*models.Transaction, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) DeleteTransaction(ctx context.Context, id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTransactionService) RefundTransaction(ctx context.Context, id uint) (*models.Transaction, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) WaitForStatusChange(ctx context.Context, id uint, lastStatus string, timeout time.Duration) (*models.TransactionWaitResult, error) {
+	args := m.Called(ctx, id, lastStatus, timeout)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TransactionWaitResult), args.Error(1)
+}
+
+// MockDashboardService for testing
+type MockDashboardService struct {
+	mock.Mock
+}
+
+func (m *MockDashboardService) GetSummary(ctx context.Context) (*models.DashboardSummary, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DashboardSummary), args.Error(1)
+}
+
+func (m *MockDashboardService) GetPartialSummary(ctx context.Context) (*models.DashboardSummary, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DashboardSummary), args.Error(1)
+}
+
+func (m *MockDashboardService) GetPeriodSummary(ctx context.Context, period models.SummaryPeriod, partial bool) (*models.DashboardSummary, error) {
+	args := m.Called(period, partial)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DashboardSummary), args.Error(1)
+}
+
+func (m *MockDashboardService) GetTopUsers(ctx context.Context, period models.SummaryPeriod, sortBy string, limit int) (*models.TopUsers, error) {
+	args := m.Called(period, sortBy, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TopUsers), args.Error(1)
+}
+
+func TestInitializeDatabase(t *testing.T) {
+	// Test with invalid DSN to ensure error handling
+	cfg := config.DatabaseConfig{
+		Host:     "invalid-host",
+		Port:     3306,
+		User:     "root",
+		Password: "password",
+		Name:     "test",
+	}
+
+	db, err := initializeDatabase(cfg, nil)
+	assert.Error(t, err)
+	assert.Nil(t, db)
+}
+
+func TestInitializeDatabaseError(t *testing.T) {
+	cfg := config.DatabaseConfig{
+		Host:     "invalid_host",
+		Port:     3306,
+		User:     "root",
+		Password: "wrong_password",
+		Name:     "nonexistent_db",
+	}
+
+	db, err := initializeDatabase(cfg, nil)
+
+	// Should fail with invalid connection
+	assert.Error(t, err)
+	assert.Nil(t, db)
+}
+
+func TestInitializeDatabaseConnectionPool(t *testing.T) {
+	// Test with valid SQLite in-memory database for testing
+	cfg := config.DatabaseConfig{
+		Host:     "127.0.0.1",
+		Port:     3306,
+		User:     "root",
+		Password: "root",
+		Name:     "masihsama",
+	}
+
+	// This test verifies the connection pool setup logic
+	// In a real scenario, we'd use a test database
+	db, err := initializeDatabase(cfg, nil)
+	if err == nil && db != nil {
+		sqlDB, err := db.DB()
+		assert.NoError(t, err)
+		assert.NotNil(t, sqlDB)
+
+		// Just verify that we can get the DB stats (connection pool was set)
+		stats := sqlDB.Stats()
+		assert.GreaterOrEqual(t, stats.MaxOpenConnections, 0)
+	}
+	// Test passes even if connection fails (for CI environments)
+}
+
+func TestInitializeDatabaseSuccess(t *testing.T) {
+	// Test successful database initialization with valid config
+	cfg := config.DatabaseConfig{
+		Host:     "127.0.0.1",
+		Port:     3306,
+		User:     "root",
+		Password: "root",
+		Name:     "masihsama",
+	}
+
+	db, err := initializeDatabase(cfg, nil)
+
+	// If connection succeeds, verify all setup
+	if err == nil && db != nil {
+		sqlDB, err := db.DB()
+		assert.NoError(t, err)
+		assert.NotNil(t, sqlDB)
+
+		// Test connection pool settings
+		stats := sqlDB.Stats()
+		assert.GreaterOrEqual(t, stats.MaxOpenConnections, 0)
+
+		// Verify we can ping the database
+		err = sqlDB.Ping()
+		if err == nil {
+			// Connection successful, verify pool settings
+			assert.Equal(t, 100, stats.MaxOpenConnections)
+		}
+	}
+}
+
+func TestNewRouter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Create mock services
+	mockTxService := new(MockTransactionService)
+	mockDashService := new(MockDashboardService)
+
+	// Create handlers with mock services
+	transactionHandler := handlers.NewTransactionHandler(mockTxService)
+	dashboardHandler := handlers.NewDashboardHandler(mockDashService)
+	budgetHandler := handlers.NewBudgetHandler(services.NewBudgetService(nil))
+	tagHandler := handlers.NewTagHandler(services.NewTagService(nil, nil))
+	userHandler := handlers.NewUserHandler(services.NewUserService(nil))
+	fixtureHandler := handlers.NewFixtureHandler(services.NewFixtureService(nil, nil, nil))
+
+	router := NewRouter(config.RoutesConfig{}, Handlers{
+		Transaction: transactionHandler,
+		Dashboard:   dashboardHandler,
+		Budget:      budgetHandler,
+		Tag:         tagHandler,
+		User:        userHandler,
+		Fixture:     fixtureHandler,
+	})
+
+	assert.NotNil(t, router)
+
+	// Test that routes are properly configured by checking router structure
+	routes := router.Routes()
+	assert.True(t, len(routes) > 0)
+
+	// Check for specific route patterns
+	foundHealthRoute := false
+	foundTransactionRoute := false
+	foundDashboardRoute := false
+
+	for _, route := range routes {
+		if route.Path == "/health" {
+			foundHealthRoute = true
+		}
+		if route.Path == "/api/transactions" {
+			foundTransactionRoute = true
+		}
+		if route.Path == "/api/dashboard/summary" {
+			foundDashboardRoute = true
+		}
+	}
+
+	assert.True(t, foundHealthRoute, "Health route should be configured")
+	assert.True(t, foundTransactionRoute, "Transaction route should be configured")
+	assert.True(t, foundDashboardRoute, "Dashboard route should be configured")
+}
+
+func TestNewAdminRouter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	transactionHandler := handlers.NewTransactionHandler(new(MockTransactionService))
+	dashboardHandler := handlers.NewDashboardHandler(new(MockDashboardService))
+	budgetHandler := handlers.NewBudgetHandler(services.NewBudgetService(nil))
+	tagHandler := handlers.NewTagHandler(services.NewTagService(nil, nil))
+	userHandler := handlers.NewUserHandler(services.NewUserService(nil))
+	fixtureHandler := handlers.NewFixtureHandler(services.NewFixtureService(nil, nil, nil))
+
+	// Admin routes are left off the public router
+	router := NewRouter(config.RoutesConfig{}, Handlers{
+		Transaction: transactionHandler,
+		Dashboard:   dashboardHandler,
+		Budget:      budgetHandler,
+		Tag:         tagHandler,
+		User:        userHandler,
+	})
+	for _, route := range router.Routes() {
+		assert.NotEqual(t, "/api/admin/fixtures", route.Path)
+		assert.NotEqual(t, "/api/v1/admin/fixtures", route.Path)
+	}
+
+	adminRouter := NewAdminRouter(config.RoutesConfig{}, fixtureHandler, handlers.NewCorrectionHandler(services.NewCorrectionService(nil, nil), "X-Forwarded-User"), handlers.NewHealthHandler(time.Second))
+	routes := make([]string, 0)
+	for _, route := range adminRouter.Routes() {
+		routes = append(routes, route.Method+" "+route.Path)
+	}
+	assert.ElementsMatch(t, []string{
+		"GET /api/admin/fixtures", "OPTIONS /api/admin/fixtures", "GET /api/admin/metrics",
+		"GET /api/v1/admin/fixtures", "OPTIONS /api/v1/admin/fixtures", "GET /api/v1/admin/metrics",
+		"POST /api/admin/transactions/:id/corrections", "GET /api/admin/transactions/:id/corrections",
+		"GET /api/admin/corrections/:id", "POST /api/admin/corrections/:id/approve",
+		"POST /api/v1/admin/transactions/:id/corrections", "GET /api/v1/admin/transactions/:id/corrections",
+		"GET /api/v1/admin/corrections/:id", "POST /api/v1/admin/corrections/:id/approve",
+		"GET /health", "GET /health/live", "GET /health/ready",
+	}, routes)
+}
+
+func TestNewRouterComprehensive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Create mock services
+	mockTxService := new(MockTransactionService)
+	mockDashService := new(MockDashboardService)
+
+	// Create handlers with mock services
+	transactionHandler := handlers.NewTransactionHandler(mockTxService)
+	dashboardHandler := handlers.NewDashboardHandler(mockDashService)
+	budgetHandler := handlers.NewBudgetHandler(services.NewBudgetService(nil))
+	tagHandler := handlers.NewTagHandler(services.NewTagService(nil, nil))
+	userHandler := handlers.NewUserHandler(services.NewUserService(nil))
+	fixtureHandler := handlers.NewFixtureHandler(services.NewFixtureService(nil, nil, nil))
+
+	router := NewRouter(config.RoutesConfig{}, Handlers{
+		Transaction: transactionHandler,
+		Dashboard:   dashboardHandler,
+		Budget:      budgetHandler,
+		Tag:         tagHandler,
+		User:        userHandler,
+		Fixture:     fixtureHandler,
+	})
+
+	// Test all routes exist
+	routes := router.Routes()
+	expectedRoutes := []string{
+		"/health",
+		"/api/transactions",
+		"/api/transactions/:id",
+		"/api/transactions/by-reference/:ref",
+		"/api/transactions/jobs/:job_id",
+		"/api/transactions/:id/refund",
+		"/api/transactions/:id/tags",
+		"/api/transactions/:id/tags/:tag",
+		"/api/dashboard/summary",
+		"/api/budgets/:user_id",
+		"/api/users",
+		"/api/users/:id",
+		"/api/admin/fixtures",
+		"/api/v1/transactions",
+		"/api/v1/transactions/:id",
+		"/api/v1/dashboard/summary",
+		"/api/v1/budgets/:user_id",
+		"/api/v1/users",
+		"/api/v1/admin/fixtures",
+	}
+
+	routeMap := make(map[string]bool)
+	for _, route := range routes {
+		routeMap[route.Path] = true
+	}
+
+	for _, expectedRoute := range expectedRoutes {
+		assert.True(t, routeMap[expectedRoute], "Route %s should be configured", expectedRoute)
+	}
+
+	// Corrections are only served by the admin listener
+	for _, route := range routes {
+		assert.NotContains(t, route.Path, "corrections")
+	}
+}
+
+func TestInitializeDatabaseSuccessDetailed(t *testing.T) {
+	// This test verifies successful database initialization logic
+	// In a real production environment, you'd use a test database
+	cfg := config.DatabaseConfig{
+		Host:     "localhost",
+		Port:     3306,
+		User:     "test_user",
+		Password: "test_password",
+		Name:     "test_db",
+	}
+
+	// Test that the function handles database configuration properly
+	// Even if connection fails, we can test the DSN construction logic
+	db, err := initializeDatabase(cfg, nil)
+
+	// In CI/test environments, DB might not be available
+	// So we test that the function runs without panic
+	if err != nil {
+		// Expected in test environment
+		assert.Nil(t, db)
+		assert.Error(t, err)
+	} else {
+		// If connection succeeds (in dev environment)
+		assert.NotNil(t, db)
+		assert.NoError(t, err)
+	}
+}
+
+func TestHealthEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Create mock services
+	mockTxService := new(MockTransactionService)
+	mockDashService := new(MockDashboardService)
+
+	// Create handlers with mock services
+	transactionHandler := handlers.NewTransactionHandler(mockTxService)
+	dashboardHandler := handlers.NewDashboardHandler(mockDashService)
+	budgetHandler := handlers.NewBudgetHandler(services.NewBudgetService(nil))
+	tagHandler := handlers.NewTagHandler(services.NewTagService(nil, nil))
+	userHandler := handlers.NewUserHandler(services.NewUserService(nil))
+	fixtureHandler := handlers.NewFixtureHandler(services.NewFixtureService(nil, nil, nil))
+
+	router := NewRouter(config.RoutesConfig{}, Handlers{
+		Transaction: transactionHandler,
+		Dashboard:   dashboardHandler,
+		Budget:      budgetHandler,
+		Tag:         tagHandler,
+		User:        userHandler,
+		Fixture:     fixtureHandler,
+	})
+
+	// Test health endpoint
+	req, _ := http.NewRequest("GET", "/health", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "OK")
+}
+
+func TestInitializeDatabaseConfigError(t *testing.T) {
+	// Test with empty configuration that should cause errors
+	cfg := config.DatabaseConfig{}
+
+	db, err := initializeDatabase(cfg, nil)
+
+	// Should fail with empty configuration
+	assert.Error(t, err)
+	assert.Nil(t, db)
+}
+
+func TestStartupFields(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			Password:    "secret",
+			ReplicaHost: "replica",
+			HedgeDelay:  0,
+		},
+		Server:    config.ServerConfig{Host: "127.0.0.1", Port: "8080"},
+		Dashboard: config.DashboardConfig{CacheEnabled: true},
+	}
+
+	fields := startupFields(cfg, "mysql", "abc123")
+
+	redacted := fields["config"].(config.Config)
+	assert.Equal(t, "[REDACTED]", redacted.Database.Password)
+	assert.Equal(t, map[string]bool{
+		"dashboard_cache":      true,
+		"read_replica":         true,
+		"hedged_reads":         false,
+		"explain_slow_queries": false,
+		"dashboard_pool":       false,
+		"async_create":         false,
+		"db_failover":          false,
+		"vault_credentials":    false,
+		"shared_state":         false,
+		"anomaly_detection":    false,
+		"synthetic_probe":      false,
+		"admin_ui":             false,
+	}, fields["features"])
+	assert.Equal(t, "mysql", fields["db_driver"])
+	assert.Equal(t, maxOpenConns, fields["db_max_open_conns"])
+	assert.Equal(t, "127.0.0.1:8080", fields["address"])
+	assert.Equal(t, "abc123", fields["schema_version"])
+	assert.NotContains(t, fields, "admin_address")
+	assert.NotContains(t, fields, "db_dashboard_max_open_conns")
+
+	cfg.Database.DashboardMaxOpenConns = 10
+	fields = startupFields(cfg, "mysql", "abc123")
+	assert.Equal(t, 10, fields["db_dashboard_max_open_conns"])
+
+	cfg.Server.AdminHost = "10.0.0.1"
+	cfg.Server.AdminPort = "9091"
+	fields = startupFields(cfg, "mysql", "abc123")
+	assert.Equal(t, "10.0.0.1:9091", fields["admin_address"])
+}
+
+func TestNewRouter_Versioning(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := NewRouter(config.RoutesConfig{}, Handlers{
+		Transaction: handlers.NewTransactionHandler(new(MockTransactionService)),
+		Dashboard:   handlers.NewDashboardHandler(new(MockDashboardService)),
+		Budget:      handlers.NewBudgetHandler(services.NewBudgetService(nil)),
+		Tag:         handlers.NewTagHandler(services.NewTagService(nil, nil)),
+		User:        handlers.NewUserHandler(services.NewUserService(nil)),
+	})
+
+	tests := []struct {
+		path    string
+		version string
+		code    int
+	}{
+		{"/api/v1/dashboard/summary", "", http.StatusOK},
+		{"/api/dashboard/summary", "", http.StatusOK},
+		{"/api/dashboard/summary", "v1", http.StatusOK},
+		{"/api/dashboard/summary", "v9", http.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		req, _ := http.NewRequest("OPTIONS", tt.path, nil)
+		if tt.version != "" {
+			req.Header.Set("API-Version", tt.version)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, "%s %s", tt.path, tt.version)
+		if tt.code != http.StatusNotAcceptable {
+			assert.Equal(t, "v1", w.Header().Get("API-Version"))
+		}
+	}
+}
+
+func TestNew_DatabaseError(t *testing.T) {
+	cfg := &config.Config{Database: config.DatabaseConfig{}}
+
+	s, err := New(cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to initialize database")
+	assert.Nil(t, s)
+}
+
+func TestServer_ServeShutsDownWhenContextIsDone(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	closed := false
+	s := &Server{
+		Router:  gin.New(),
+		DB:      db,
+		cfg:     &config.Config{Server: config.ServerConfig{Host: "127.0.0.1", Port: "0"}},
+		closers: []func(){func() { closed = true }},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ctx) }()
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(shutdownTimeout):
+		t.Fatal("Serve did not return after the context was done")
+	}
+
+	s.Close()
+	assert.True(t, closed)
+}
+
+func TestServer_ServeReportsListenerFailure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	s := &Server{
+		Router: gin.New(),
+		DB:     db,
+		cfg:    &config.Config{Server: config.ServerConfig{Host: "127.0.0.1", Port: "invalid"}},
+	}
+
+	err = s.Serve(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to serve 127.0.0.1:invalid")
+}
+
+func TestNewRouter_UI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := Handlers{
+		Transaction: handlers.NewTransactionHandler(new(MockTransactionService)),
+		Dashboard:   handlers.NewDashboardHandler(new(MockDashboardService)),
+		Budget:      handlers.NewBudgetHandler(services.NewBudgetService(nil)),
+		Tag:         handlers.NewTagHandler(services.NewTagService(nil, nil)),
+		User:        handlers.NewUserHandler(services.NewUserService(nil)),
+	}
+
+	// The UI is left out unless it is enabled
+	w := httptest.NewRecorder()
+	NewRouter(config.RoutesConfig{}, h).ServeHTTP(w, httptest.NewRequest("GET", "/ui/", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	h.UI = ui.Handler()
+	router := NewRouter(config.RoutesConfig{}, h)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/ui/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<title>Transactions admin</title>")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/ui/app.js", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/ui", nil))
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/ui/", w.Header().Get("Location"))
+}
+
+func TestTrustProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	clientIP := func(proxies []string) string {
+		engine := gin.New()
+		require.NoError(t, trustProxies(engine, proxies))
+		engine.GET("/ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+		req := httptest.NewRequest("GET", "/ip", nil)
+		req.RemoteAddr = "10.0.0.5:41000"
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	assert.Equal(t, "203.0.113.7", clientIP(nil), "nil trusts every proxy")
+	assert.Equal(t, "10.0.0.5", clientIP([]string{}), "an empty list trusts none")
+	assert.Equal(t, "203.0.113.7", clientIP([]string{"10.0.0.0/8"}))
+	assert.Equal(t, "10.0.0.5", clientIP([]string{"192.168.0.0/16"}))
+}
+
+func TestNewHTTPServer(t *testing.T) {
+	cfg := config.ServerConfig{
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+		MaxHeaderBytes:    64 << 10,
+	}
+	router := gin.New()
+
+	srv := newHTTPServer(cfg, "127.0.0.1:8080", router)
+
+	assert.Equal(t, "127.0.0.1:8080", srv.Addr)
+	assert.Equal(t, router, srv.Handler)
+	assert.Equal(t, 5*time.Second, srv.ReadHeaderTimeout)
+	assert.Equal(t, 30*time.Second, srv.ReadTimeout)
+	assert.Equal(t, 30*time.Second, srv.WriteTimeout)
+	assert.Equal(t, 2*time.Minute, srv.IdleTimeout)
+	assert.Equal(t, 64<<10, srv.MaxHeaderBytes)
+}
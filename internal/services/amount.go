@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"interview/pkg/money"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidAmount is returned when an amount doesn't fit its currency or the amount column
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// AmountPolicy describes which amounts and currencies the service accepts
+type AmountPolicy struct {
+	// Precision and Scale match the decimal(precision,scale) amount column
+	Precision int
+	Scale     int
+	// DefaultCurrency is used when a request doesn't specify one
+	DefaultCurrency string
+	// CurrencyScales maps ISO 4217 codes to their number of decimal places
+	CurrencyScales map[string]int
+	// Rounding rounds sums, averages and other amounts derived from stored
+	// ones; money.HalfUp when empty
+	Rounding money.Rounding
+	// CurrencyRoundings overrides Rounding for the aggregates of one
+	// currency, keyed by ISO 4217 code
+	CurrencyRoundings map[string]money.Rounding
+}
+
+// DefaultAmountPolicy returns the policy matching the default decimal(15,2) amount column
+func DefaultAmountPolicy() AmountPolicy {
+	return AmountPolicy{
+		Precision:       15,
+		Scale:           2,
+		DefaultCurrency: "USD",
+		CurrencyScales: map[string]int{
+			"USD": 2,
+			"EUR": 2,
+			"IDR": 2,
+			"JPY": 0,
+		},
+		Rounding: money.HalfUp,
+	}
+}
+
+// Check verifies every currency fits in the amount column and every
+// currency rounding belongs to a configured currency
+func (p AmountPolicy) Check() error {
+	if _, ok := p.CurrencyScales[p.DefaultCurrency]; !ok {
+		return fmt.Errorf("default currency %s has no configured scale", p.DefaultCurrency)
+	}
+	for currency, scale := range p.CurrencyScales {
+		if scale > p.Scale {
+			return fmt.Errorf("currency %s needs %d decimal places but the amount column only stores %d", currency, scale, p.Scale)
+		}
+	}
+	for currency := range p.CurrencyRoundings {
+		if _, ok := p.CurrencyScales[currency]; !ok {
+			return fmt.Errorf("currency %s has a rounding but no configured scale", currency)
+		}
+	}
+	return nil
+}
+
+// Round rounds amount to scale decimal places with the policy's rounding
+func (p AmountPolicy) Round(amount decimal.Decimal, scale int32) decimal.Decimal {
+	rounding := p.Rounding
+	if rounding == "" {
+		rounding = money.HalfUp
+	}
+	return rounding.Round(amount, scale)
+}
+
+// RoundAggregate rounds a sum, average or other amount derived from stored
+// amounts to the amount column scale. Every aggregate the services return
+// goes through it, so totals computed from the same rows agree however the
+// database summed them; amounts that already fit are returned unchanged.
+// It is for aggregates that add up several currencies; those of one
+// currency go through RoundCurrency.
+func (p AmountPolicy) RoundAggregate(amount decimal.Decimal) decimal.Decimal {
+	if money.HasScale(amount, int32(p.Scale)) {
+		return amount
+	}
+	return p.Round(amount, int32(p.Scale))
+}
+
+// RoundCurrency rounds an aggregate of amounts in currency to the currency's
+// scale with its rounding, or Rounding when it has none. A currency that is
+// no longer configured keeps the amount column scale.
+func (p AmountPolicy) RoundCurrency(currency string, amount decimal.Decimal) decimal.Decimal {
+	scale, ok := p.CurrencyScales[currency]
+	if !ok {
+		scale = p.Scale
+	}
+	if money.HasScale(amount, int32(scale)) {
+		return amount
+	}
+	if rounding, ok := p.CurrencyRoundings[currency]; ok {
+		p.Rounding = rounding
+	}
+	return p.Round(amount, int32(scale))
+}
+
+// Currency normalizes a requested currency, falling back to the default
+func (p AmountPolicy) Currency(currency string) string {
+	if currency == "" {
+		return p.DefaultCurrency
+	}
+	return strings.ToUpper(currency)
+}
+
+// Validate checks an amount against its currency scale and the amount column size
+func (p AmountPolicy) Validate(currency string, amount decimal.Decimal) error {
+	scale, ok := p.CurrencyScales[currency]
+	if !ok {
+		return fmt.Errorf("%w: unsupported currency %s", ErrInvalidAmount, currency)
+	}
+
+	if !money.HasScale(amount, int32(scale)) {
+		return fmt.Errorf("%w: %s allows at most %d decimal places", ErrInvalidAmount, currency, scale)
+	}
+
+	limit := decimal.New(1, int32(p.Precision-p.Scale))
+	if amount.Abs().GreaterThanOrEqual(limit) {
+		return fmt.Errorf("%w: amount must be less than %s", ErrInvalidAmount, limit.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/services"
+	"interview/pkg/money"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAmountPolicy_Validate(t *testing.T) {
+	policy := services.DefaultAmountPolicy()
+
+	tests := []struct {
+		name     string
+		currency string
+		amount   string
+		wantErr  bool
+	}{
+		{"two decimals USD", "USD", "100.50", false},
+		{"too many decimals USD", "USD", "100.505", true},
+		{"whole JPY", "JPY", "1000", false},
+		{"fractional JPY", "JPY", "1000.5", true},
+		{"unsupported currency", "XYZ", "10", true},
+		{"exceeds column precision", "USD", "10000000000000", true},
+		{"largest amount that fits", "USD", "9999999999999.99", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.currency, decimal.RequireFromString(tt.amount))
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, services.ErrInvalidAmount))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAmountPolicy_RoundAggregate(t *testing.T) {
+	policy := services.DefaultAmountPolicy()
+
+	// Float sums from the database are rounded to the column scale
+	assert.Equal(t, "0.3", policy.RoundAggregate(decimal.NewFromFloat(0.1).Add(decimal.NewFromFloat(0.2)).Add(decimal.RequireFromString("0.00000000000000004"))).String())
+	assert.Equal(t, "2.35", policy.RoundAggregate(decimal.RequireFromString("2.345")).String())
+
+	// Amounts that fit keep their exponent
+	exact := decimal.RequireFromString("2.5")
+	assert.Equal(t, exact, policy.RoundAggregate(exact))
+
+	policy.Rounding = money.HalfEven
+	assert.Equal(t, "2.34", policy.RoundAggregate(decimal.RequireFromString("2.345")).String())
+	assert.Equal(t, "-2.34", policy.RoundAggregate(decimal.RequireFromString("-2.345")).String())
+
+	// A policy without a rounding rounds half up
+	assert.Equal(t, "2.35", services.AmountPolicy{Scale: 2}.RoundAggregate(decimal.RequireFromString("2.345")).String())
+	assert.Equal(t, "33.33", services.AmountPolicy{}.Round(decimal.RequireFromString("33.3333"), 2).String())
+}
+
+func TestAmountPolicy_RoundCurrency(t *testing.T) {
+	policy := services.DefaultAmountPolicy()
+	policy.CurrencyRoundings = map[string]money.Rounding{"EUR": money.HalfEven}
+
+	// Each currency rounds to its own scale
+	assert.Equal(t, "2.35", policy.RoundCurrency("USD", decimal.RequireFromString("2.345")).String())
+	assert.Equal(t, "3", policy.RoundCurrency("JPY", decimal.RequireFromString("2.5")).String())
+
+	// and with its own rounding where it has one
+	assert.Equal(t, "2.34", policy.RoundCurrency("EUR", decimal.RequireFromString("2.345")).String())
+	assert.Equal(t, "2.35", policy.RoundCurrency("IDR", decimal.RequireFromString("2.345")).String())
+
+	// Amounts that fit keep their exponent
+	exact := decimal.RequireFromString("2.5")
+	assert.Equal(t, exact, policy.RoundCurrency("USD", exact))
+
+	// A currency no longer configured keeps the column scale
+	assert.Equal(t, "2.35", policy.RoundCurrency("GBP", decimal.RequireFromString("2.345")).String())
+}
+
+func TestAmountPolicy_Check(t *testing.T) {
+	policy := services.DefaultAmountPolicy()
+	assert.NoError(t, policy.Check())
+
+	// BTC needs 8 decimals, more than the default decimal(15,2) column stores
+	policy.CurrencyScales["BTC"] = 8
+	assert.Error(t, policy.Check())
+
+	policy.Precision, policy.Scale = 21, 8
+	assert.NoError(t, policy.Check())
+
+	// A rounding for a currency the policy doesn't accept is a typo
+	policy.CurrencyRoundings = map[string]money.Rounding{"GBP": money.HalfEven}
+	assert.Error(t, policy.Check())
+}
+
+func TestTransactionService_CreateTransactionCurrency(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	policy := services.DefaultAmountPolicy()
+	policy.Precision, policy.Scale = 21, 8
+	policy.CurrencyScales["BTC"] = 8
+	service := services.NewTransactionService(mockRepo, services.WithAmountPolicy(policy))
+
+	mockRepo.On("Create", mock.AnythingOfType("*models.Transaction")).Return(nil)
+
+	result, err := service.CreateTransaction(context.Background(), models.CreateTransactionRequest{
+		UserID:   1,
+		Amount:   decimal.RequireFromString("0.00012345"),
+		Currency: "btc",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "BTC", result.Currency)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_CreateTransactionInvalidAmount(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	result, err := service.CreateTransaction(context.Background(), models.CreateTransactionRequest{
+		UserID:   1,
+		Amount:   decimal.RequireFromString("10.5"),
+		Currency: "JPY",
+	})
+
+	assert.ErrorIs(t, err, services.ErrInvalidAmount)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
+func TestTransactionService_CreateTransactionNegativeAdjustment(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	mockRepo.On("Create", mock.AnythingOfType("*models.Transaction")).Return(nil)
+
+	result, err := service.CreateTransaction(context.Background(), models.CreateTransactionRequest{
+		UserID: 1,
+		Amount: decimal.NewFromFloat(-25.50),
+		Type:   models.TransactionTypeAdjustment,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransactionTypeAdjustment, result.Type)
+	assert.True(t, result.Amount.IsNegative())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_CreateTransactionNegativePayment(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	result, err := service.CreateTransaction(context.Background(), models.CreateTransactionRequest{
+		UserID: 1,
+		Amount: decimal.NewFromFloat(-25.50),
+	})
+
+	assert.ErrorIs(t, err, services.ErrInvalidAmount)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
+func TestTransactionService_CreateTransactionDefaultsToPayment(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	mockRepo.On("Create", mock.AnythingOfType("*models.Transaction")).Return(nil)
+
+	result, err := service.CreateTransaction(context.Background(), models.CreateTransactionRequest{
+		UserID: 1,
+		Amount: decimal.NewFromFloat(25.50),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransactionTypePayment, result.Type)
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"expvar"
+	"math"
+	"sync"
+	"time"
+
+	"interview/internal/repositories"
+
+	"github.com/sirupsen/logrus"
+)
+
+// volumeAnomalies counts minutes flagged by the volume monitor. It is
+// published as the volume_anomalies expvar.
+var volumeAnomalies = expvar.NewInt("volume_anomalies")
+
+// minRelativeDeviation is the smallest standard deviation a baseline is
+// assumed to have, as a fraction of its mean. Perfectly steady traffic would
+// otherwise flag the slightest change.
+const minRelativeDeviation = 0.1
+
+// ewma keeps an exponentially weighted moving mean and variance of a series
+type ewma struct {
+	alpha    float64
+	mean     float64
+	variance float64
+	samples  int
+}
+
+// score returns how many standard deviations x is from the mean
+func (e *ewma) score(x float64) float64 {
+	deviation := math.Max(math.Sqrt(e.variance), minRelativeDeviation*e.mean)
+	if deviation == 0 {
+		return 0
+	}
+	return (x - e.mean) / deviation
+}
+
+// add adds x to the series
+func (e *ewma) add(x float64) {
+	if e.samples == 0 {
+		e.mean = x
+	} else {
+		diff := x - e.mean
+		increment := e.alpha * diff
+		e.mean += increment
+		e.variance = (1 - e.alpha) * (e.variance + diff*increment)
+	}
+	e.samples++
+}
+
+// volumeMetric tracks one per-minute series and whether it is currently
+// anomalous, so an alert is raised once per anomaly rather than every minute
+type volumeMetric struct {
+	name      string
+	baseline  ewma
+	anomalous bool
+}
+
+// VolumeMonitor watches per-minute transaction creation counts and amounts
+// and logs an alert when a minute spikes or drops far from the recent
+// baseline, as happens when an integration stops sending or replays traffic
+type VolumeMonitor struct {
+	repo      repositories.VolumeRepository
+	threshold float64
+	minRate   float64
+	warmup    int
+	now       func() time.Time
+
+	mu      sync.Mutex
+	count   volumeMetric
+	amount  volumeMetric
+	sampled time.Time
+}
+
+// NewVolumeMonitor creates a volume monitor whose baseline mostly reflects
+// the last window of minutes. A minute is anomalous when it is more than
+// threshold standard deviations from the baseline, once the baseline
+// averages at least minRate transactions per minute.
+func NewVolumeMonitor(repo repositories.VolumeRepository, window time.Duration, threshold, minRate float64) *VolumeMonitor {
+	minutes := int(window / time.Minute)
+	alpha := 2 / float64(minutes+1)
+	return &VolumeMonitor{
+		repo:      repo,
+		threshold: threshold,
+		minRate:   minRate,
+		warmup:    minutes,
+		now:       time.Now,
+		count:     volumeMetric{name: "count", baseline: ewma{alpha: alpha}},
+		amount:    volumeMetric{name: "amount", baseline: ewma{alpha: alpha}},
+	}
+}
+
+// Prime builds the baseline from the window of minutes before the last
+// complete one, so alerts do not have to wait for a full window after a
+// restart. Those minutes only feed the baseline; the next Check starts at
+// the last complete minute.
+func (m *VolumeMonitor) Prime() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	last := m.now().Truncate(time.Minute).Add(-time.Minute)
+	for minute := last.Add(-time.Duration(m.warmup) * time.Minute); minute.Before(last); minute = minute.Add(time.Minute) {
+		count, amount, err := m.repo.GetCreated(minute, minute.Add(time.Minute))
+		if err != nil {
+			return err
+		}
+		m.count.baseline.add(float64(count))
+		m.amount.baseline.add(amount.InexactFloat64())
+		m.sampled = minute
+	}
+	return nil
+}
+
+// Check samples every complete minute since the last check, alerting on
+// anomalous ones
+func (m *VolumeMonitor) Check() error {
+	return m.check(true)
+}
+
+// check samples every complete minute since the last check. Anomalies are
+// only logged and counted when alert is set; the baseline is kept either
+// way, so a replica that starts alerting has a current one.
+func (m *VolumeMonitor) check(alert bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current := m.now().Truncate(time.Minute)
+	minute := current.Add(-time.Minute)
+	if !m.sampled.IsZero() {
+		minute = m.sampled.Add(time.Minute)
+	}
+	for ; minute.Before(current); minute = minute.Add(time.Minute) {
+		count, amount, err := m.repo.GetCreated(minute, minute.Add(time.Minute))
+		if err != nil {
+			return err
+		}
+
+		// Both series are judged against a baseline with enough traffic
+		active := m.count.baseline.samples >= m.warmup && m.count.baseline.mean >= m.minRate
+		m.observe(&m.count, minute, float64(count), active, alert)
+		m.observe(&m.amount, minute, amount.InexactFloat64(), active, alert)
+		m.sampled = minute
+	}
+	return nil
+}
+
+// observe checks a minute's value against metric's baseline and then adds
+// it, logging changes of state when alert is set
+func (m *VolumeMonitor) observe(metric *volumeMetric, minute time.Time, value float64, active, alert bool) {
+	score := metric.baseline.score(value)
+	expected := metric.baseline.mean
+	metric.baseline.add(value)
+
+	fields := logrus.Fields{
+		"metric":   metric.name,
+		"minute":   minute,
+		"value":    value,
+		"expected": expected,
+		"score":    score,
+	}
+	if active && math.Abs(score) > m.threshold {
+		if !metric.anomalous && alert {
+			fields["direction"] = "spike"
+			if score < 0 {
+				fields["direction"] = "drop"
+			}
+			volumeAnomalies.Add(1)
+			logrus.WithFields(fields).Warn("Transaction volume anomaly")
+		}
+		metric.anomalous = true
+		return
+	}
+	if metric.anomalous && alert {
+		logrus.WithFields(fields).Info("Transaction volume back to normal")
+	}
+	metric.anomalous = false
+}
+
+// Anomalous reports which metrics are currently anomalous
+func (m *VolumeMonitor) Anomalous() (count, amount bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.count.anomalous, m.amount.anomalous
+}
+
+// Start checks every minute until the returned stop function is called.
+// Every replica keeps a baseline, but only the one for which leader reports
+// true raises alerts, so an anomaly is reported once.
+func (m *VolumeMonitor) Start(leader func() bool) (stop func()) {
+	ticker := time.NewTicker(time.Minute)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := m.check(leader()); err != nil {
+					logrus.WithError(err).Warn("Failed to check transaction volume")
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
@@ -0,0 +1,90 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"interview/internal/services"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVolumeRepository reports the baseline counts in turn for minutes before
+// the last complete one and latest from then on. Each transaction is 10.
+type fakeVolumeRepository struct {
+	baseline []int64
+	latest   int64
+	mark     time.Time
+	err      error
+	minute   int
+}
+
+func (r *fakeVolumeRepository) GetCreated(from, to time.Time) (int64, decimal.Decimal, error) {
+	if r.err != nil {
+		return 0, decimal.Zero, r.err
+	}
+	count := r.latest
+	if from.Before(r.mark) {
+		count = r.baseline[r.minute%len(r.baseline)]
+		r.minute++
+	}
+	return count, decimal.NewFromInt(count * 10), nil
+}
+
+func newFakeVolumeRepository(latest int64, baseline ...int64) *fakeVolumeRepository {
+	return &fakeVolumeRepository{
+		baseline: baseline,
+		latest:   latest,
+		mark:     time.Now().Truncate(time.Minute).Add(-time.Minute),
+	}
+}
+
+func TestVolumeMonitor_FlagsDropsAndSpikes(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		latest int64
+		flag   bool
+	}{
+		{name: "drop", latest: 0, flag: true},
+		{name: "spike", latest: 400, flag: true},
+		{name: "normal", latest: 104, flag: false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			repo := newFakeVolumeRepository(test.latest, 95, 100, 105, 98, 102)
+			monitor := services.NewVolumeMonitor(repo, 30*time.Minute, 3, 5)
+			require.NoError(t, monitor.Prime())
+			require.NoError(t, monitor.Check())
+
+			count, amount := monitor.Anomalous()
+			assert.Equal(t, test.flag, count)
+			assert.Equal(t, test.flag, amount)
+		})
+	}
+}
+
+func TestVolumeMonitor_IgnoresQuietTrafficAndColdBaseline(t *testing.T) {
+	// Averaging under the minimum rate, a minute without transactions is normal
+	monitor := services.NewVolumeMonitor(newFakeVolumeRepository(0, 1, 3, 2), 30*time.Minute, 3, 5)
+	require.NoError(t, monitor.Prime())
+	require.NoError(t, monitor.Check())
+	count, _ := monitor.Anomalous()
+	assert.False(t, count)
+
+	// Without a primed baseline nothing is flagged
+	monitor = services.NewVolumeMonitor(newFakeVolumeRepository(0, 100), 30*time.Minute, 3, 5)
+	require.NoError(t, monitor.Check())
+	count, _ = monitor.Anomalous()
+	assert.False(t, count)
+}
+
+func TestVolumeMonitor_RepositoryError(t *testing.T) {
+	repo := newFakeVolumeRepository(0, 100)
+	repo.err = errors.New("database is down")
+	monitor := services.NewVolumeMonitor(repo, 30*time.Minute, 3, 5)
+
+	assert.ErrorContains(t, monitor.Prime(), "database is down")
+	assert.ErrorContains(t, monitor.Check(), "database is down")
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+	"interview/internal/requestid"
+	"interview/pkg/money"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ErrBudgetExceeded is returned when a transaction would exceed a blocking budget
+var ErrBudgetExceeded = errors.New("monthly budget exceeded")
+
+// ErrBudgetCurrency is returned when a transaction is in another currency
+// than the user's blocking budget, which could not cap it
+var ErrBudgetCurrency = errors.New("monthly budget is in another currency")
+
+// BudgetAlertThresholds are the usage percentages that trigger an alert when crossed
+var BudgetAlertThresholds = []int64{80, 100}
+
+// BudgetService interface defines budget service methods
+type BudgetService interface {
+	SetBudget(ctx context.Context, userID uint, req models.SetBudgetRequest) (*models.Budget, error)
+	GetBudgetStatus(ctx context.Context, userID uint) (*models.BudgetStatus, error)
+	// CheckTransaction rejects amounts that overrun a blocking budget and
+	// returns the alert thresholds the transaction would cross
+	CheckTransaction(ctx context.Context, userID uint, currency string, amount decimal.Decimal) ([]int64, error)
+	// EmitAlerts reports crossed thresholds once the transaction is stored
+	EmitAlerts(ctx context.Context, userID uint, thresholds []int64)
+}
+
+// budgetService implements BudgetService interface
+type budgetService struct {
+	repo    repositories.BudgetRepository
+	amounts AmountPolicy
+	now     func() time.Time
+}
+
+// BudgetServiceOption configures optional budget service behavior
+type BudgetServiceOption func(*budgetService)
+
+// WithBudgetAmountPolicy sets the policy caps are validated and consumption
+// and usage are rounded with
+func WithBudgetAmountPolicy(policy AmountPolicy) BudgetServiceOption {
+	return func(s *budgetService) {
+		s.amounts = policy
+	}
+}
+
+// NewBudgetService creates a new budget service
+func NewBudgetService(repo repositories.BudgetRepository, opts ...BudgetServiceOption) BudgetService {
+	s := &budgetService{repo: repo, amounts: DefaultAmountPolicy(), now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// SetBudget sets a user's monthly budget, in the default currency unless
+// req names one
+func (s *budgetService) SetBudget(ctx context.Context, userID uint, req models.SetBudgetRequest) (*models.Budget, error) {
+	currency := s.amounts.Currency(req.Currency)
+	if err := s.amounts.Validate(currency, req.MonthlyCap); err != nil {
+		return nil, err
+	}
+
+	budget := &models.Budget{
+		UserID:       userID,
+		MonthlyCap:   req.MonthlyCap,
+		Currency:     currency,
+		BlockOverage: req.BlockOverage,
+	}
+
+	if err := s.repo.Upsert(ctx, budget); err != nil {
+		return nil, fmt.Errorf("failed to set budget: %v", err)
+	}
+
+	return budget, nil
+}
+
+// GetBudgetStatus gets a user's budget and current month's consumption
+func (s *budgetService) GetBudgetStatus(ctx context.Context, userID uint) (*models.BudgetStatus, error) {
+	budget, err := s.getBudget(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if budget == nil {
+		return nil, errors.New("budget not found")
+	}
+
+	from, to := s.currentPeriod()
+	consumed, err := s.consumed(ctx, userID, s.amounts.Currency(budget.Currency), from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BudgetStatus{
+		Budget:       *budget,
+		Period:       from.Format("2006-01"),
+		Consumed:     consumed,
+		Remaining:    decimal.Max(budget.MonthlyCap.Sub(consumed), decimal.Zero),
+		UsagePercent: s.amounts.Round(money.Percent(consumed, budget.MonthlyCap), 2),
+	}, nil
+}
+
+// CheckTransaction checks a new transaction against the user's budget.
+// Transactions in other currencies than the budget's do not count towards
+// it; a blocking budget rejects them, since it could not cap them.
+func (s *budgetService) CheckTransaction(ctx context.Context, userID uint, currency string, amount decimal.Decimal) ([]int64, error) {
+	budget, err := s.getBudget(ctx, userID)
+	if err != nil || budget == nil {
+		return nil, err
+	}
+	if budgetCurrency := s.amounts.Currency(budget.Currency); currency != budgetCurrency {
+		if budget.BlockOverage {
+			return nil, fmt.Errorf("%w: the budget is in %s, the transaction in %s", ErrBudgetCurrency, budgetCurrency, currency)
+		}
+		return nil, nil
+	}
+
+	from, to := s.currentPeriod()
+	consumed, err := s.consumed(ctx, userID, currency, from, to)
+	if err != nil {
+		return nil, err
+	}
+	after := consumed.Add(amount)
+
+	if budget.BlockOverage && after.GreaterThan(budget.MonthlyCap) {
+		return nil, fmt.Errorf("%w: %s of %s used", ErrBudgetExceeded, consumed.String(), budget.MonthlyCap.String())
+	}
+
+	var crossed []int64
+	before, now := money.Percent(consumed, budget.MonthlyCap), money.Percent(after, budget.MonthlyCap)
+	for _, threshold := range BudgetAlertThresholds {
+		limit := decimal.NewFromInt(threshold)
+		if before.LessThan(limit) && now.GreaterThanOrEqual(limit) {
+			crossed = append(crossed, threshold)
+		}
+	}
+
+	return crossed, nil
+}
+
+// EmitAlerts logs a budget alert for each crossed threshold
+func (s *budgetService) EmitAlerts(ctx context.Context, userID uint, thresholds []int64) {
+	for _, threshold := range thresholds {
+		requestid.Logger(ctx).WithFields(logrus.Fields{
+			"user_id":   userID,
+			"threshold": threshold,
+		}).Warn("Monthly budget threshold reached")
+	}
+}
+
+// getBudget gets a user's budget, returning nil when none is set
+func (s *budgetService) getBudget(ctx context.Context, userID uint) (*models.Budget, error) {
+	budget, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get budget: %v", err)
+	}
+	return budget, nil
+}
+
+// currentPeriod returns the start of this month and of the next
+func (s *budgetService) currentPeriod() (time.Time, time.Time) {
+	now := s.now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	return from, from.AddDate(0, 1, 0)
+}
+
+// consumed returns a user's budget consumption in currency between from and
+// to, rounded with the rule of currency
+func (s *budgetService) consumed(ctx context.Context, userID uint, currency string, from, to time.Time) (decimal.Decimal, error) {
+	consumed, err := s.repo.GetVolume(ctx, userID, currency, from, to)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get budget consumption: %v", err)
+	}
+	return s.amounts.RoundCurrency(currency, consumed), nil
+}
@@ -0,0 +1,196 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/services"
+	"interview/pkg/money"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockBudgetRepository is a mock implementation of BudgetRepository
+type MockBudgetRepository struct {
+	mock.Mock
+}
+
+func (m *MockBudgetRepository) Upsert(ctx context.Context, budget *models.Budget) error {
+	args := m.Called(budget)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) GetByUserID(ctx context.Context, userID uint) (*models.Budget, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Budget), args.Error(1)
+}
+
+func (m *MockBudgetRepository) GetVolume(ctx context.Context, userID uint, currency string, from, to time.Time) (decimal.Decimal, error) {
+	args := m.Called(userID, currency, from, to)
+	return args.Get(0).(decimal.Decimal), args.Error(1)
+}
+
+func TestBudgetService_CheckTransactionCrossesThresholds(t *testing.T) {
+	mockRepo := new(MockBudgetRepository)
+	service := services.NewBudgetService(mockRepo)
+
+	mockRepo.On("GetByUserID", uint(1)).Return(&models.Budget{UserID: 1, MonthlyCap: decimal.NewFromInt(1000)}, nil)
+	mockRepo.On("GetVolume", uint(1), "USD", mock.Anything, mock.Anything).Return(decimal.NewFromInt(700), nil)
+
+	crossed, err := service.CheckTransaction(context.Background(), 1, "USD", decimal.NewFromInt(150))
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{80}, crossed)
+
+	crossed, err = service.CheckTransaction(context.Background(), 1, "USD", decimal.NewFromInt(400))
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{80, 100}, crossed, "overage is allowed when the budget doesn't block")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBudgetService_CheckTransactionBlocksOverage(t *testing.T) {
+	mockRepo := new(MockBudgetRepository)
+	service := services.NewBudgetService(mockRepo)
+
+	mockRepo.On("GetByUserID", uint(1)).Return(&models.Budget{UserID: 1, MonthlyCap: decimal.NewFromInt(1000), BlockOverage: true}, nil)
+	mockRepo.On("GetVolume", uint(1), "USD", mock.Anything, mock.Anything).Return(decimal.NewFromInt(900), nil)
+
+	crossed, err := service.CheckTransaction(context.Background(), 1, "USD", decimal.NewFromInt(150))
+
+	assert.ErrorIs(t, err, services.ErrBudgetExceeded)
+	assert.Nil(t, crossed)
+}
+
+func TestBudgetService_CheckTransactionNoBudget(t *testing.T) {
+	mockRepo := new(MockBudgetRepository)
+	service := services.NewBudgetService(mockRepo)
+
+	mockRepo.On("GetByUserID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+
+	crossed, err := service.CheckTransaction(context.Background(), 1, "USD", decimal.NewFromInt(150))
+
+	assert.NoError(t, err)
+	assert.Empty(t, crossed)
+	mockRepo.AssertNotCalled(t, "GetVolume")
+}
+
+func TestBudgetService_CheckTransactionOtherCurrency(t *testing.T) {
+	mockRepo := new(MockBudgetRepository)
+	service := services.NewBudgetService(mockRepo)
+
+	mockRepo.On("GetByUserID", uint(1)).Return(&models.Budget{UserID: 1, MonthlyCap: decimal.NewFromInt(1000), Currency: "USD"}, nil).Once()
+
+	// EUR spending doesn't count towards a USD budget
+	crossed, err := service.CheckTransaction(context.Background(), 1, "EUR", decimal.NewFromInt(5000))
+	assert.NoError(t, err)
+	assert.Empty(t, crossed)
+
+	// A blocking budget can't cap it, so it is rejected
+	mockRepo.On("GetByUserID", uint(1)).Return(&models.Budget{UserID: 1, MonthlyCap: decimal.NewFromInt(1000), Currency: "USD", BlockOverage: true}, nil)
+
+	crossed, err = service.CheckTransaction(context.Background(), 1, "EUR", decimal.NewFromInt(5))
+	assert.ErrorIs(t, err, services.ErrBudgetCurrency)
+	assert.Nil(t, crossed)
+	mockRepo.AssertNotCalled(t, "GetVolume")
+}
+
+func TestBudgetService_SetBudget(t *testing.T) {
+	mockRepo := new(MockBudgetRepository)
+	service := services.NewBudgetService(mockRepo)
+
+	mockRepo.On("Upsert", mock.MatchedBy(func(budget *models.Budget) bool {
+		return budget.Currency == "JPY" && budget.MonthlyCap.Equal(decimal.NewFromInt(50000))
+	})).Return(nil)
+
+	budget, err := service.SetBudget(context.Background(), 1, models.SetBudgetRequest{MonthlyCap: decimal.NewFromInt(50000), Currency: "jpy"})
+	assert.NoError(t, err)
+	assert.Equal(t, "JPY", budget.Currency)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBudgetService_SetBudgetInvalidCap(t *testing.T) {
+	mockRepo := new(MockBudgetRepository)
+	service := services.NewBudgetService(mockRepo)
+
+	for _, req := range []models.SetBudgetRequest{
+		{MonthlyCap: decimal.RequireFromString("10.555")},
+		{MonthlyCap: decimal.RequireFromString("10.5"), Currency: "JPY"},
+		{MonthlyCap: decimal.RequireFromString("10000000000000")},
+		{MonthlyCap: decimal.NewFromInt(10), Currency: "XYZ"},
+	} {
+		_, err := service.SetBudget(context.Background(), 1, req)
+		assert.ErrorIs(t, err, services.ErrInvalidAmount, "cap %s %s", req.MonthlyCap, req.Currency)
+	}
+	mockRepo.AssertNotCalled(t, "Upsert")
+}
+
+func TestBudgetService_GetBudgetStatus(t *testing.T) {
+	mockRepo := new(MockBudgetRepository)
+	service := services.NewBudgetService(mockRepo)
+
+	mockRepo.On("GetByUserID", uint(1)).Return(&models.Budget{UserID: 1, MonthlyCap: decimal.NewFromInt(1000)}, nil)
+	mockRepo.On("GetVolume", uint(1), "USD", mock.Anything, mock.Anything).Return(decimal.NewFromInt(250), nil)
+
+	status, err := service.GetBudgetStatus(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.True(t, status.Consumed.Equal(decimal.NewFromInt(250)))
+	assert.True(t, status.Remaining.Equal(decimal.NewFromInt(750)))
+	assert.True(t, status.UsagePercent.Equal(decimal.NewFromInt(25)))
+	assert.Equal(t, time.Now().Format("2006-01"), status.Period)
+}
+
+func TestBudgetService_GetBudgetStatusNotFound(t *testing.T) {
+	mockRepo := new(MockBudgetRepository)
+	service := services.NewBudgetService(mockRepo)
+
+	mockRepo.On("GetByUserID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+
+	status, err := service.GetBudgetStatus(context.Background(), 1)
+
+	assert.Nil(t, status)
+	assert.EqualError(t, err, "budget not found")
+}
+
+func TestTransactionService_CreateTransactionBudgetExceeded(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	mockBudgetRepo := new(MockBudgetRepository)
+	service := services.NewTransactionService(mockRepo, services.WithBudgetService(services.NewBudgetService(mockBudgetRepo)))
+
+	mockBudgetRepo.On("GetByUserID", uint(1)).Return(&models.Budget{UserID: 1, MonthlyCap: decimal.NewFromInt(100), BlockOverage: true}, nil)
+	mockBudgetRepo.On("GetVolume", uint(1), "USD", mock.Anything, mock.Anything).Return(decimal.NewFromInt(90), nil)
+
+	result, err := service.CreateTransaction(context.Background(), models.CreateTransactionRequest{UserID: 1, Amount: decimal.NewFromInt(20)})
+
+	assert.ErrorIs(t, err, services.ErrBudgetExceeded)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
+func TestBudgetService_RoundsConsumption(t *testing.T) {
+	mockRepo := new(MockBudgetRepository)
+	policy := services.DefaultAmountPolicy()
+	policy.Rounding = money.HalfEven
+	service := services.NewBudgetService(mockRepo, services.WithBudgetAmountPolicy(policy))
+
+	// Float sums, as SQLite returns them, are rounded to the column scale
+	mockRepo.On("GetByUserID", uint(1)).Return(&models.Budget{UserID: 1, MonthlyCap: decimal.NewFromInt(700), BlockOverage: true}, nil)
+	mockRepo.On("GetVolume", uint(1), "USD", mock.Anything, mock.Anything).Return(decimal.RequireFromString("349.99999999999994"), nil)
+
+	status, err := service.GetBudgetStatus(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "350", status.Consumed.String())
+	assert.Equal(t, "350", status.Remaining.String())
+	assert.Equal(t, "50", status.UsagePercent.String())
+
+	// The rounded consumption leaves room for the rest of the cap
+	_, err = service.CheckTransaction(context.Background(), 1, "USD", decimal.NewFromInt(350))
+	assert.NoError(t, err)
+}
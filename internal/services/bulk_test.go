@@ -0,0 +1,96 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"interview/internal/models"
+	"interview/internal/services"
+)
+
+func TestTransactionService_CreateTransactions(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	mockRepo.On("CreateBatch", mock.MatchedBy(func(transactions []models.Transaction) bool {
+		return len(transactions) == 2 && transactions[0].UserID == 1 && transactions[1].UserID == 3
+	})).Run(func(args mock.Arguments) {
+		transactions := args.Get(0).([]models.Transaction)
+		transactions[0].ID = 10
+		transactions[1].ID = 11
+	}).Return(nil)
+
+	result, err := service.CreateTransactions(context.Background(), []models.CreateTransactionRequest{
+		{UserID: 1, Amount: decimal.NewFromInt(100)},
+		{UserID: 2, Amount: decimal.RequireFromString("1.234")},
+		{UserID: 3, Amount: decimal.NewFromInt(-5), Type: models.TransactionTypeAdjustment},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 1, result.Failed)
+	assert.Len(t, result.Results, 3)
+	assert.Equal(t, uint(10), result.Results[0].Transaction.ID)
+	assert.Equal(t, "pending", result.Results[0].Transaction.Status)
+	assert.Nil(t, result.Results[1].Transaction)
+	assert.Contains(t, result.Results[1].Error, "invalid amount")
+	assert.Equal(t, 2, result.Results[2].Index)
+	assert.Equal(t, uint(11), result.Results[2].Transaction.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_CreateTransactionsBudgetIncludesBatch(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	mockBudgetRepo := new(MockBudgetRepository)
+	service := services.NewTransactionService(mockRepo, services.WithBudgetService(services.NewBudgetService(mockBudgetRepo)))
+
+	mockBudgetRepo.On("GetByUserID", uint(1)).Return(&models.Budget{UserID: 1, MonthlyCap: decimal.NewFromInt(100), BlockOverage: true}, nil)
+	mockBudgetRepo.On("GetVolume", uint(1), "USD", mock.Anything, mock.Anything).Return(decimal.NewFromInt(20), nil)
+	mockRepo.On("CreateBatch", mock.MatchedBy(func(transactions []models.Transaction) bool {
+		return len(transactions) == 2
+	})).Return(nil)
+
+	result, err := service.CreateTransactions(context.Background(), []models.CreateTransactionRequest{
+		{UserID: 1, Amount: decimal.NewFromInt(50)},
+		{UserID: 1, Amount: decimal.NewFromInt(20)},
+		{UserID: 1, Amount: decimal.NewFromInt(20)},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Created)
+	assert.Contains(t, result.Results[2].Error, "monthly budget exceeded")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_CreateTransactionsNoneAccepted(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	result, err := service.CreateTransactions(context.Background(), []models.CreateTransactionRequest{
+		{UserID: 1, Amount: decimal.NewFromInt(-5)},
+	})
+
+	assert.NoError(t, err)
+	assert.Zero(t, result.Created)
+	assert.Equal(t, 1, result.Failed)
+	mockRepo.AssertNotCalled(t, "CreateBatch")
+}
+
+func TestTransactionService_CreateTransactionsStoreError(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	mockRepo.On("CreateBatch", mock.Anything).Return(errors.New("connection lost"))
+
+	result, err := service.CreateTransactions(context.Background(), []models.CreateTransactionRequest{
+		{UserID: 1, Amount: decimal.NewFromInt(10)},
+	})
+
+	assert.EqualError(t, err, "failed to create transactions: connection lost")
+	assert.Nil(t, result)
+}
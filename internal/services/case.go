@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// ErrCaseNotFound is returned when a case does not exist
+var ErrCaseNotFound = errors.New("case not found")
+
+// ErrCaseClosed is returned when a closed case would be changed
+var ErrCaseClosed = errors.New("case is closed")
+
+// ErrPinNotFound is returned when a transaction is not pinned to a case
+var ErrPinNotFound = errors.New("transaction is not pinned to the case")
+
+// ErrTransactionsNotFound is returned when transactions to pin do not exist
+var ErrTransactionsNotFound = errors.New("transactions not found")
+
+// CaseService interface defines investigation case service methods
+type CaseService interface {
+	CreateCase(ctx context.Context, req models.CreateCaseRequest) (*models.Case, error)
+	GetCase(ctx context.Context, id uint) (*models.Case, error)
+	GetCasesWithCount(ctx context.Context, filters models.CaseFilters) ([]models.Case, int64, error)
+	CloseCase(ctx context.Context, id uint, req models.CloseCaseRequest) (*models.Case, error)
+	PinTransactions(ctx context.Context, id uint, req models.PinTransactionsRequest) (*models.Case, error)
+	UnpinTransaction(ctx context.Context, id, transactionID uint) (*models.Case, error)
+	GetPinsWithCount(ctx context.Context, id uint, filters models.CasePinFilters) ([]models.CasePin, int64, error)
+}
+
+// caseService implements CaseService interface
+type caseService struct {
+	cases        repositories.CaseRepository
+	transactions repositories.TransactionRepository
+}
+
+// NewCaseService creates a new case service
+func NewCaseService(cases repositories.CaseRepository, transactions repositories.TransactionRepository) CaseService {
+	return &caseService{cases: cases, transactions: transactions}
+}
+
+// CreateCase opens a new case
+func (s *caseService) CreateCase(ctx context.Context, req models.CreateCaseRequest) (*models.Case, error) {
+	c := &models.Case{
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Status:      models.CaseStatusOpen,
+	}
+	if c.Name == "" {
+		return nil, errors.New("invalid case name")
+	}
+
+	if err := s.cases.Create(ctx, c); err != nil {
+		return nil, fmt.Errorf("failed to create case: %v", err)
+	}
+
+	return c, nil
+}
+
+// GetCase gets a case by ID
+func (s *caseService) GetCase(ctx context.Context, id uint) (*models.Case, error) {
+	c, err := s.cases.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCaseNotFound
+		}
+		return nil, fmt.Errorf("failed to get case: %v", err)
+	}
+
+	return c, nil
+}
+
+// GetCasesWithCount gets a page of cases along with the number of cases
+// matching the filters
+func (s *caseService) GetCasesWithCount(ctx context.Context, filters models.CaseFilters) ([]models.Case, int64, error) {
+	cases, total, err := s.cases.GetAllWithCount(ctx, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get cases: %v", err)
+	}
+
+	return cases, total, nil
+}
+
+// CloseCase closes an open case with an outcome
+func (s *caseService) CloseCase(ctx context.Context, id uint, req models.CloseCaseRequest) (*models.Case, error) {
+	if _, err := s.openCase(ctx, id); err != nil {
+		return nil, err
+	}
+
+	err := s.cases.Close(ctx, id, req.Outcome, strings.TrimSpace(req.Resolution), time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Closed since it was read
+		return nil, ErrCaseClosed
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to close case: %v", err)
+	}
+
+	return s.GetCase(ctx, id)
+}
+
+// PinTransactions pins existing transactions to an open case
+func (s *caseService) PinTransactions(ctx context.Context, id uint, req models.PinTransactionsRequest) (*models.Case, error) {
+	if _, err := s.openCase(ctx, id); err != nil {
+		return nil, err
+	}
+
+	found, err := s.transactions.GetByIDs(ctx, req.TransactionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %v", err)
+	}
+	if len(found) < len(req.TransactionIDs) {
+		return nil, missingTransactions(req.TransactionIDs, found)
+	}
+
+	if err := s.cases.Pin(ctx, id, req.TransactionIDs, strings.TrimSpace(req.Note)); err != nil {
+		return nil, fmt.Errorf("failed to pin transactions: %v", err)
+	}
+
+	return s.GetCase(ctx, id)
+}
+
+// UnpinTransaction removes a transaction from an open case
+func (s *caseService) UnpinTransaction(ctx context.Context, id, transactionID uint) (*models.Case, error) {
+	if _, err := s.openCase(ctx, id); err != nil {
+		return nil, err
+	}
+
+	removed, err := s.cases.Unpin(ctx, id, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpin transaction: %v", err)
+	}
+	if !removed {
+		return nil, ErrPinNotFound
+	}
+
+	return s.GetCase(ctx, id)
+}
+
+// GetPinsWithCount gets a page of the transactions pinned to a case along
+// with the number of pins
+func (s *caseService) GetPinsWithCount(ctx context.Context, id uint, filters models.CasePinFilters) ([]models.CasePin, int64, error) {
+	if _, err := s.GetCase(ctx, id); err != nil {
+		return nil, 0, err
+	}
+
+	pins, total, err := s.cases.GetPinsWithCount(ctx, id, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get pinned transactions: %v", err)
+	}
+
+	return pins, total, nil
+}
+
+// openCase gets a case, returning ErrCaseClosed when it is closed
+func (s *caseService) openCase(ctx context.Context, id uint) (*models.Case, error) {
+	c, err := s.GetCase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != models.CaseStatusOpen {
+		return nil, ErrCaseClosed
+	}
+	return c, nil
+}
+
+// missingTransactions returns ErrTransactionsNotFound naming the requested
+// IDs that are not among found
+func missingTransactions(ids []uint, found []models.Transaction) error {
+	exists := make(map[uint]bool, len(found))
+	for _, transaction := range found {
+		exists[transaction.ID] = true
+	}
+
+	var missing []string
+	sorted := append([]uint(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for _, id := range sorted {
+		if !exists[id] {
+			missing = append(missing, fmt.Sprint(id))
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrTransactionsNotFound, strings.Join(missing, ", "))
+}
@@ -0,0 +1,184 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockCaseRepository is a mock implementation of CaseRepository
+type MockCaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockCaseRepository) Create(ctx context.Context, c *models.Case) error {
+	args := m.Called(c)
+	return args.Error(0)
+}
+
+func (m *MockCaseRepository) GetByID(ctx context.Context, id uint) (*models.Case, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Case), args.Error(1)
+}
+
+func (m *MockCaseRepository) GetAllWithCount(ctx context.Context, filters models.CaseFilters) ([]models.Case, int64, error) {
+	args := m.Called(filters)
+	return args.Get(0).([]models.Case), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCaseRepository) Close(ctx context.Context, id uint, outcome, resolution string, closedAt time.Time) error {
+	args := m.Called(id, outcome, resolution, closedAt)
+	return args.Error(0)
+}
+
+func (m *MockCaseRepository) Pin(ctx context.Context, caseID uint, transactionIDs []uint, note string) error {
+	args := m.Called(caseID, transactionIDs, note)
+	return args.Error(0)
+}
+
+func (m *MockCaseRepository) Unpin(ctx context.Context, caseID, transactionID uint) (bool, error) {
+	args := m.Called(caseID, transactionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCaseRepository) GetPinsWithCount(ctx context.Context, caseID uint, filters models.CasePinFilters) ([]models.CasePin, int64, error) {
+	args := m.Called(caseID, filters)
+	return args.Get(0).([]models.CasePin), args.Get(1).(int64), args.Error(2)
+}
+
+func TestCaseService_CreateCase(t *testing.T) {
+	mockRepo := new(MockCaseRepository)
+	service := services.NewCaseService(mockRepo, new(MockTransactionRepository))
+
+	mockRepo.On("Create", &models.Case{Name: "Card testing", Description: "Small charges", Status: models.CaseStatusOpen}).Return(nil)
+
+	created, err := service.CreateCase(context.Background(), models.CreateCaseRequest{Name: "  Card testing ", Description: "Small charges "})
+	assert.NoError(t, err)
+	assert.Equal(t, "Card testing", created.Name)
+
+	_, err = service.CreateCase(context.Background(), models.CreateCaseRequest{Name: "   "})
+	assert.EqualError(t, err, "invalid case name")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCaseService_GetCaseNotFound(t *testing.T) {
+	mockRepo := new(MockCaseRepository)
+	service := services.NewCaseService(mockRepo, new(MockTransactionRepository))
+
+	mockRepo.On("GetByID", uint(42)).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("GetByID", uint(43)).Return(nil, errors.New("connection refused"))
+
+	_, err := service.GetCase(context.Background(), 42)
+	assert.ErrorIs(t, err, services.ErrCaseNotFound)
+	_, err = service.GetCase(context.Background(), 43)
+	assert.EqualError(t, err, "failed to get case: connection refused")
+}
+
+func TestCaseService_PinTransactions(t *testing.T) {
+	mockRepo := new(MockCaseRepository)
+	mockTransactions := new(MockTransactionRepository)
+	service := services.NewCaseService(mockRepo, mockTransactions)
+
+	mockRepo.On("GetByID", uint(1)).Return(&models.Case{ID: 1, Status: models.CaseStatusOpen}, nil)
+	mockTransactions.On("GetByIDs", []uint{1, 2}).Return([]models.Transaction{{ID: 2}, {ID: 1}}, nil)
+	mockRepo.On("Pin", uint(1), []uint{1, 2}, "same card").Return(nil)
+
+	_, err := service.PinTransactions(context.Background(), 1, models.PinTransactionsRequest{TransactionIDs: []uint{1, 2}, Note: " same card"})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCaseService_PinMissingTransactions(t *testing.T) {
+	mockRepo := new(MockCaseRepository)
+	mockTransactions := new(MockTransactionRepository)
+	service := services.NewCaseService(mockRepo, mockTransactions)
+
+	mockRepo.On("GetByID", uint(1)).Return(&models.Case{ID: 1, Status: models.CaseStatusOpen}, nil)
+	mockTransactions.On("GetByIDs", []uint{9, 2, 5}).Return([]models.Transaction{{ID: 2}}, nil)
+
+	_, err := service.PinTransactions(context.Background(), 1, models.PinTransactionsRequest{TransactionIDs: []uint{9, 2, 5}})
+	assert.ErrorIs(t, err, services.ErrTransactionsNotFound)
+	assert.EqualError(t, err, "transactions not found: 5, 9")
+	mockRepo.AssertNotCalled(t, "Pin", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCaseService_ClosedCaseCannotChange(t *testing.T) {
+	mockRepo := new(MockCaseRepository)
+	service := services.NewCaseService(mockRepo, new(MockTransactionRepository))
+
+	mockRepo.On("GetByID", uint(1)).Return(&models.Case{ID: 1, Status: models.CaseStatusClosed}, nil)
+
+	_, err := service.PinTransactions(context.Background(), 1, models.PinTransactionsRequest{TransactionIDs: []uint{1}})
+	assert.ErrorIs(t, err, services.ErrCaseClosed)
+	_, err = service.UnpinTransaction(context.Background(), 1, 1)
+	assert.ErrorIs(t, err, services.ErrCaseClosed)
+	_, err = service.CloseCase(context.Background(), 1, models.CloseCaseRequest{Outcome: models.CaseOutcomeDismissed})
+	assert.ErrorIs(t, err, services.ErrCaseClosed)
+}
+
+func TestCaseService_CloseCase(t *testing.T) {
+	mockRepo := new(MockCaseRepository)
+	service := services.NewCaseService(mockRepo, new(MockTransactionRepository))
+
+	mockRepo.On("GetByID", uint(1)).Return(&models.Case{ID: 1, Status: models.CaseStatusOpen}, nil).Once()
+	mockRepo.On("Close", uint(1), models.CaseOutcomeConfirmed, "Account blocked", mock.AnythingOfType("time.Time")).Return(nil)
+	mockRepo.On("GetByID", uint(1)).Return(&models.Case{ID: 1, Status: models.CaseStatusClosed, Outcome: models.CaseOutcomeConfirmed}, nil).Once()
+
+	closed, err := service.CloseCase(context.Background(), 1, models.CloseCaseRequest{Outcome: models.CaseOutcomeConfirmed, Resolution: "Account blocked\n"})
+	assert.NoError(t, err)
+	assert.Equal(t, models.CaseStatusClosed, closed.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCaseService_CloseCaseRace(t *testing.T) {
+	mockRepo := new(MockCaseRepository)
+	service := services.NewCaseService(mockRepo, new(MockTransactionRepository))
+
+	// Closed by another request between the read and the update
+	mockRepo.On("GetByID", uint(1)).Return(&models.Case{ID: 1, Status: models.CaseStatusOpen}, nil)
+	mockRepo.On("Close", uint(1), models.CaseOutcomeDismissed, "", mock.AnythingOfType("time.Time")).Return(gorm.ErrRecordNotFound)
+
+	_, err := service.CloseCase(context.Background(), 1, models.CloseCaseRequest{Outcome: models.CaseOutcomeDismissed})
+	assert.ErrorIs(t, err, services.ErrCaseClosed)
+}
+
+func TestCaseService_UnpinNotPinned(t *testing.T) {
+	mockRepo := new(MockCaseRepository)
+	service := services.NewCaseService(mockRepo, new(MockTransactionRepository))
+
+	mockRepo.On("GetByID", uint(1)).Return(&models.Case{ID: 1, Status: models.CaseStatusOpen}, nil)
+	mockRepo.On("Unpin", uint(1), uint(7)).Return(false, nil)
+
+	_, err := service.UnpinTransaction(context.Background(), 1, 7)
+	assert.ErrorIs(t, err, services.ErrPinNotFound)
+}
+
+func TestCaseService_GetPinsWithCount(t *testing.T) {
+	mockRepo := new(MockCaseRepository)
+	service := services.NewCaseService(mockRepo, new(MockTransactionRepository))
+
+	pins := []models.CasePin{{CaseID: 1, TransactionID: 3, Note: "same card"}}
+	mockRepo.On("GetByID", uint(1)).Return(&models.Case{ID: 1, Status: models.CaseStatusClosed}, nil)
+	mockRepo.On("GetPinsWithCount", uint(1), models.CasePinFilters{Limit: 10}).Return(pins, int64(1), nil)
+	mockRepo.On("GetByID", uint(2)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Closed cases keep their pins readable
+	result, total, err := service.GetPinsWithCount(context.Background(), 1, models.CasePinFilters{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, pins, result)
+
+	_, _, err = service.GetPinsWithCount(context.Background(), 2, models.CasePinFilters{})
+	assert.ErrorIs(t, err, services.ErrCaseNotFound)
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"interview/internal/events"
+	"interview/internal/models"
+	"interview/internal/repositories"
+	"interview/internal/requestid"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// CorrectionApprovalWindow is how long a requested correction can be approved
+const CorrectionApprovalWindow = 24 * time.Hour
+
+// ErrCorrectionNotFound is returned when a correction does not exist
+var ErrCorrectionNotFound = errors.New("correction not found")
+
+// ErrNothingToCorrect is returned when a correction would leave the
+// transaction as it is
+var ErrNothingToCorrect = errors.New("correction changes neither user_id nor amount")
+
+// ErrNotCorrectable is returned when correcting a refunded transaction or a
+// refund, whose amounts must stay equal
+var ErrNotCorrectable = errors.New("refunded transactions and refunds cannot be corrected")
+
+// ErrCorrectionNotPending is returned when approving a correction that was
+// applied already
+var ErrCorrectionNotPending = errors.New("correction is not pending")
+
+// ErrCorrectionExpired is returned when approving a correction after its
+// approval window
+var ErrCorrectionExpired = errors.New("correction approval window has passed")
+
+// ErrSameOperator is returned when the operator who requested a correction
+// approves it
+var ErrSameOperator = errors.New("correction must be approved by another operator")
+
+// CorrectionService interface defines transaction correction service methods
+type CorrectionService interface {
+	RequestCorrection(ctx context.Context, transactionID uint, requestedBy string, req models.CreateCorrectionRequest) (*models.Correction, error)
+	ApproveCorrection(ctx context.Context, id uint, approvedBy string) (*models.Correction, error)
+	GetCorrection(ctx context.Context, id uint) (*models.Correction, error)
+	GetTransactionCorrections(ctx context.Context, transactionID uint) ([]models.Correction, error)
+}
+
+// correctionService implements CorrectionService interface
+type correctionService struct {
+	corrections  repositories.CorrectionRepository
+	transactions repositories.TransactionRepository
+	users        UserService
+	amounts      AmountPolicy
+	events       events.Broker
+	now          func() time.Time
+}
+
+// CorrectionServiceOption configures optional correction service behavior
+type CorrectionServiceOption func(*correctionService)
+
+// WithCorrectionAmountPolicy sets the amount policy corrected amounts are validated with
+func WithCorrectionAmountPolicy(policy AmountPolicy) CorrectionServiceOption {
+	return func(s *correctionService) {
+		s.amounts = policy
+	}
+}
+
+// WithCorrectionUserService rejects corrections to users that do not exist
+func WithCorrectionUserService(users UserService) CorrectionServiceOption {
+	return func(s *correctionService) {
+		s.users = users
+	}
+}
+
+// WithCorrectionEventBus sets the broker applied corrections are published on
+func WithCorrectionEventBus(bus events.Broker) CorrectionServiceOption {
+	return func(s *correctionService) {
+		s.events = bus
+	}
+}
+
+// NewCorrectionService creates a new correction service
+func NewCorrectionService(corrections repositories.CorrectionRepository, transactions repositories.TransactionRepository, opts ...CorrectionServiceOption) CorrectionService {
+	s := &correctionService{corrections: corrections, transactions: transactions, amounts: DefaultAmountPolicy(), events: events.NewBus(), now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// RequestCorrection records a pending correction of a transaction's user or
+// amount, requested by the operator requestedBy
+func (s *correctionService) RequestCorrection(ctx context.Context, transactionID uint, requestedBy string, req models.CreateCorrectionRequest) (*models.Correction, error) {
+	reason := strings.TrimSpace(req.Reason)
+	requestedBy = strings.TrimSpace(requestedBy)
+	if reason == "" || requestedBy == "" {
+		return nil, errors.New("invalid correction reason or operator")
+	}
+
+	// The primary, so the correction is requested for the current version
+	transaction, err := s.transactions.GetByIDFromPrimary(ctx, transactionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transaction not found")
+		}
+		return nil, fmt.Errorf("failed to get transaction: %v", err)
+	}
+	if transaction.Status == "refunded" || transaction.ParentID != nil {
+		return nil, ErrNotCorrectable
+	}
+
+	userID, amount := transaction.UserID, transaction.Amount
+	if req.UserID != nil {
+		userID = *req.UserID
+	}
+	if req.Amount != nil {
+		amount = *req.Amount
+	}
+	if userID == transaction.UserID && amount.Equal(transaction.Amount) {
+		return nil, ErrNothingToCorrect
+	}
+
+	if err := s.amounts.Validate(transaction.Currency, amount); err != nil {
+		return nil, err
+	}
+	if amount.IsNegative() && transaction.Type != models.TransactionTypeAdjustment {
+		return nil, fmt.Errorf("%w: only adjustment transactions may be negative", ErrInvalidAmount)
+	}
+	if s.users != nil && userID != transaction.UserID {
+		if err := s.users.CheckUser(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	correction := &models.Correction{
+		TransactionID:      transaction.ID,
+		TransactionVersion: transaction.Version,
+		Status:             models.CorrectionStatusPending,
+		Reason:             reason,
+		RequestedBy:        requestedBy,
+		OldUserID:          transaction.UserID,
+		NewUserID:          userID,
+		OldAmount:          transaction.Amount,
+		NewAmount:          amount,
+		ExpiresAt:          s.now().Add(CorrectionApprovalWindow),
+	}
+	if err := s.corrections.Create(ctx, correction); err != nil {
+		return nil, fmt.Errorf("failed to create correction: %v", err)
+	}
+
+	requestid.Logger(ctx).WithFields(correctionFields(correction)).Info("Transaction correction requested")
+
+	return correction, nil
+}
+
+// ApproveCorrection applies a pending correction approved by approvedBy, who
+// must be another operator than the one who requested it. The transaction
+// must not have changed since the request.
+func (s *correctionService) ApproveCorrection(ctx context.Context, id uint, approvedBy string) (*models.Correction, error) {
+	correction, err := s.GetCorrection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if correction.Status != models.CorrectionStatusPending {
+		return nil, ErrCorrectionNotPending
+	}
+	now := s.now()
+	if now.After(correction.ExpiresAt) {
+		return nil, ErrCorrectionExpired
+	}
+	approvedBy = strings.TrimSpace(approvedBy)
+	if approvedBy == "" || strings.EqualFold(approvedBy, correction.RequestedBy) {
+		return nil, ErrSameOperator
+	}
+
+	err = s.corrections.Apply(ctx, correction, approvedBy, now)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Approved since it was read
+		return nil, ErrCorrectionNotPending
+	}
+	if errors.Is(err, repositories.ErrTransactionChanged) {
+		return nil, fmt.Errorf("%w: %v, request the correction again", ErrVersionConflict, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply correction: %v", err)
+	}
+
+	correction.Status = models.CorrectionStatusApplied
+	correction.ApprovedBy = approvedBy
+	correction.AppliedAt = &now
+	requestid.Logger(ctx).WithFields(correctionFields(correction)).Warn("Transaction corrected")
+	s.publishCorrected(ctx, correction)
+
+	return correction, nil
+}
+
+// publishCorrected publishes an applied correction as an update of its
+// transaction, carrying the corrected user so user filters match it
+func (s *correctionService) publishCorrected(ctx context.Context, correction *models.Correction) {
+	transaction, err := s.transactions.GetByIDFromPrimary(ctx, correction.TransactionID)
+	if err != nil {
+		requestid.Logger(ctx).WithError(err).WithField("correction_id", correction.ID).Warn("Failed to publish applied correction")
+		return
+	}
+	s.events.Publish(events.Event{
+		Type:          events.TransactionUpdated,
+		TransactionID: transaction.ID,
+		UserID:        transaction.UserID,
+		Status:        transaction.Status,
+	})
+}
+
+// GetCorrection gets a correction by ID
+func (s *correctionService) GetCorrection(ctx context.Context, id uint) (*models.Correction, error) {
+	correction, err := s.corrections.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCorrectionNotFound
+		}
+		return nil, fmt.Errorf("failed to get correction: %v", err)
+	}
+
+	return correction, nil
+}
+
+// GetTransactionCorrections gets the corrections requested for a
+// transaction, oldest first
+func (s *correctionService) GetTransactionCorrections(ctx context.Context, transactionID uint) ([]models.Correction, error) {
+	corrections, err := s.corrections.GetByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get corrections: %v", err)
+	}
+
+	return corrections, nil
+}
+
+// correctionFields are the log fields of a correction, so the log holds the
+// audit record too
+func correctionFields(correction *models.Correction) logrus.Fields {
+	return logrus.Fields{
+		"correction_id":  correction.ID,
+		"transaction_id": correction.TransactionID,
+		"requested_by":   correction.RequestedBy,
+		"approved_by":    correction.ApprovedBy,
+		"reason":         correction.Reason,
+		"old_user_id":    correction.OldUserID,
+		"new_user_id":    correction.NewUserID,
+		"old_amount":     correction.OldAmount.String(),
+		"new_amount":     correction.NewAmount.String(),
+	}
+}
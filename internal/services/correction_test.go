@@ -0,0 +1,188 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"interview/internal/events"
+	"interview/internal/models"
+	"interview/internal/repositories"
+	"interview/internal/services"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// MockCorrectionRepository is a mock implementation of CorrectionRepository
+type MockCorrectionRepository struct {
+	mock.Mock
+}
+
+func (m *MockCorrectionRepository) Create(ctx context.Context, correction *models.Correction) error {
+	args := m.Called(correction)
+	return args.Error(0)
+}
+
+func (m *MockCorrectionRepository) GetByID(ctx context.Context, id uint) (*models.Correction, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Correction), args.Error(1)
+}
+
+func (m *MockCorrectionRepository) GetByTransactionID(ctx context.Context, transactionID uint) ([]models.Correction, error) {
+	args := m.Called(transactionID)
+	return args.Get(0).([]models.Correction), args.Error(1)
+}
+
+func (m *MockCorrectionRepository) Apply(ctx context.Context, correction *models.Correction, approvedBy string, appliedAt time.Time) error {
+	args := m.Called(correction, approvedBy, appliedAt)
+	return args.Error(0)
+}
+
+func correctableTransaction() *models.Transaction {
+	return &models.Transaction{ID: 1, UserID: 1, Amount: decimal.NewFromInt(1000), Currency: "USD", Type: models.TransactionTypePayment, Status: "success", Version: 3}
+}
+
+func TestCorrectionService_RequestCorrection(t *testing.T) {
+	mockCorrections := new(MockCorrectionRepository)
+	mockTransactions := new(MockTransactionRepository)
+	mockUsers := new(MockUserRepository)
+	service := services.NewCorrectionService(mockCorrections, mockTransactions, services.WithCorrectionUserService(services.NewUserService(mockUsers)))
+
+	amount := decimal.NewFromInt(10)
+	userID := uint(2)
+	mockTransactions.On("GetByIDFromPrimary", uint(1)).Return(correctableTransaction(), nil)
+	mockUsers.On("Exists", uint(2)).Return(true, nil)
+	mockCorrections.On("Create", mock.MatchedBy(func(c *models.Correction) bool {
+		return c.TransactionVersion == 3 && c.OldUserID == 1 && c.NewUserID == 2 &&
+			c.OldAmount.Equal(decimal.NewFromInt(1000)) && c.NewAmount.Equal(amount) &&
+			c.Reason == "Amount keyed in cents" && c.RequestedBy == "alice"
+	})).Return(nil)
+
+	correction, err := service.RequestCorrection(context.Background(), 1, "alice ", models.CreateCorrectionRequest{UserID: &userID, Amount: &amount, Reason: " Amount keyed in cents "})
+	require.NoError(t, err)
+	assert.Equal(t, models.CorrectionStatusPending, correction.Status)
+	mockCorrections.AssertExpectations(t)
+	mockUsers.AssertExpectations(t)
+}
+
+func TestCorrectionService_RequestCorrectionRejected(t *testing.T) {
+	mockTransactions := new(MockTransactionRepository)
+	mockUsers := new(MockUserRepository)
+	service := services.NewCorrectionService(new(MockCorrectionRepository), mockTransactions, services.WithCorrectionUserService(services.NewUserService(mockUsers)))
+
+	refunded := correctableTransaction()
+	refunded.ID, refunded.Status = 2, "refunded"
+	mockTransactions.On("GetByIDFromPrimary", uint(1)).Return(correctableTransaction(), nil)
+	mockTransactions.On("GetByIDFromPrimary", uint(2)).Return(refunded, nil)
+	mockTransactions.On("GetByIDFromPrimary", uint(3)).Return(nil, gorm.ErrRecordNotFound)
+	mockUsers.On("Exists", uint(9)).Return(false, nil)
+
+	same := decimal.NewFromInt(1000)
+	fraction := decimal.RequireFromString("10.001")
+	negative := decimal.NewFromInt(-10)
+	unknownUser := uint(9)
+	tests := []struct {
+		name          string
+		transactionID uint
+		req           models.CreateCorrectionRequest
+		err           error
+		message       string
+	}{
+		{"unchanged", 1, models.CreateCorrectionRequest{Amount: &same}, services.ErrNothingToCorrect, ""},
+		{"refunded", 2, models.CreateCorrectionRequest{Amount: &fraction}, services.ErrNotCorrectable, ""},
+		{"missing transaction", 3, models.CreateCorrectionRequest{Amount: &fraction}, nil, "transaction not found"},
+		{"too many decimal places", 1, models.CreateCorrectionRequest{Amount: &fraction}, services.ErrInvalidAmount, ""},
+		{"negative payment", 1, models.CreateCorrectionRequest{Amount: &negative}, services.ErrInvalidAmount, ""},
+		{"unknown user", 1, models.CreateCorrectionRequest{UserID: &unknownUser}, services.ErrUserNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Reason = "Amount keyed in cents"
+			_, err := service.RequestCorrection(context.Background(), tt.transactionID, "alice", tt.req)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				assert.EqualError(t, err, tt.message)
+			}
+		})
+	}
+}
+
+// requestedCorrection requests a correction by alice through service
+func requestedCorrection(t *testing.T, service services.CorrectionService, mockCorrections *MockCorrectionRepository) *models.Correction {
+	amount := decimal.NewFromInt(10)
+	mockCorrections.On("Create", mock.Anything).Return(nil).Once()
+	correction, err := service.RequestCorrection(context.Background(), 1, "alice", models.CreateCorrectionRequest{Amount: &amount, Reason: "Amount keyed in cents"})
+	require.NoError(t, err)
+	correction.ID = 7
+	return correction
+}
+
+func TestCorrectionService_ApproveCorrection(t *testing.T) {
+	mockCorrections := new(MockCorrectionRepository)
+	mockTransactions := new(MockTransactionRepository)
+	bus := events.NewBus()
+	service := services.NewCorrectionService(mockCorrections, mockTransactions, services.WithCorrectionEventBus(bus))
+	received, cancel := bus.Subscribe()
+	defer cancel()
+
+	mockTransactions.On("GetByIDFromPrimary", uint(1)).Return(correctableTransaction(), nil)
+	correction := requestedCorrection(t, service, mockCorrections)
+	mockCorrections.On("GetByID", uint(7)).Return(correction, nil)
+
+	// The requester cannot approve their own correction
+	_, err := service.ApproveCorrection(context.Background(), 7, "Alice")
+	assert.ErrorIs(t, err, services.ErrSameOperator)
+	_, err = service.ApproveCorrection(context.Background(), 7, " ")
+	assert.ErrorIs(t, err, services.ErrSameOperator)
+
+	mockCorrections.On("Apply", correction, "bob", mock.AnythingOfType("time.Time")).Return(nil).Once()
+	applied, err := service.ApproveCorrection(context.Background(), 7, " bob")
+	require.NoError(t, err)
+	assert.Equal(t, models.CorrectionStatusApplied, applied.Status)
+	assert.Equal(t, "bob", applied.ApprovedBy)
+	assert.NotNil(t, applied.AppliedAt)
+
+	event := <-received
+	assert.Equal(t, events.TransactionUpdated, event.Type)
+	assert.Equal(t, uint(1), event.TransactionID)
+	assert.Equal(t, "success", event.Status)
+
+	_, err = service.ApproveCorrection(context.Background(), 7, "bob")
+	assert.ErrorIs(t, err, services.ErrCorrectionNotPending)
+	mockCorrections.AssertExpectations(t)
+}
+
+func TestCorrectionService_ApproveCorrectionFailures(t *testing.T) {
+	mockCorrections := new(MockCorrectionRepository)
+	mockTransactions := new(MockTransactionRepository)
+	service := services.NewCorrectionService(mockCorrections, mockTransactions)
+
+	mockTransactions.On("GetByIDFromPrimary", uint(1)).Return(correctableTransaction(), nil)
+	correction := requestedCorrection(t, service, mockCorrections)
+	mockCorrections.On("GetByID", uint(7)).Return(correction, nil)
+	mockCorrections.On("GetByID", uint(8)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := service.ApproveCorrection(context.Background(), 8, "bob")
+	assert.ErrorIs(t, err, services.ErrCorrectionNotFound)
+
+	mockCorrections.On("Apply", correction, "bob", mock.Anything).Return(repositories.ErrTransactionChanged).Once()
+	_, err = service.ApproveCorrection(context.Background(), 7, "bob")
+	assert.ErrorIs(t, err, services.ErrVersionConflict)
+
+	mockCorrections.On("Apply", correction, "bob", mock.Anything).Return(gorm.ErrRecordNotFound).Once()
+	_, err = service.ApproveCorrection(context.Background(), 7, "bob")
+	assert.ErrorIs(t, err, services.ErrCorrectionNotPending)
+
+	correction.ExpiresAt = time.Now().Add(-time.Minute)
+	_, err = service.ApproveCorrection(context.Background(), 7, "bob")
+	assert.ErrorIs(t, err, services.ErrCorrectionExpired)
+	mockCorrections.AssertExpectations(t)
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+	"interview/internal/requestid"
+	"interview/pkg/utils"
+
+	"github.com/segmentio/kafka-go"
+	"gorm.io/gorm"
+)
+
+// JobQueue is the part of kafka.Writer used to enqueue transaction creates
+type JobQueue interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// CreateJobService interface defines asynchronous create methods
+type CreateJobService interface {
+	Enqueue(ctx context.Context, req models.CreateTransactionRequest) (*models.CreateJob, error)
+	GetJob(ctx context.Context, id string) (*models.CreateJob, error)
+}
+
+// createJobService implements CreateJobService on the topic the transaction
+// consumer reads, using the job ID as the message key
+type createJobService struct {
+	queue     JobQueue
+	processed repositories.ProcessedMessageRepository
+}
+
+// NewCreateJobService creates a new asynchronous create service
+func NewCreateJobService(queue JobQueue, processed repositories.ProcessedMessageRepository) CreateJobService {
+	return &createJobService{queue: queue, processed: processed}
+}
+
+// Enqueue queues a create request for the consumer and returns its pending job
+func (s *createJobService) Enqueue(ctx context.Context, req models.CreateTransactionRequest) (*models.CreateJob, error) {
+	value, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %v", err)
+	}
+
+	id := utils.NewUUID()
+	msg := kafka.Message{Key: []byte(id), Value: value}
+	if requestID := requestid.FromContext(ctx); requestID != "" {
+		// The consumer logs it, correlating the job with the request that queued it
+		msg.Headers = append(msg.Headers, kafka.Header{Key: requestid.Key, Value: []byte(requestID)})
+	}
+	if err := s.queue.WriteMessages(ctx, msg); err != nil {
+		return nil, fmt.Errorf("failed to enqueue transaction: %v", err)
+	}
+
+	return &models.CreateJob{ID: id, Status: models.CreateJobPending}, nil
+}
+
+// GetJob reports a job's progress. Jobs are only recorded once the consumer
+// has processed them, so an unknown ID is reported as pending.
+func (s *createJobService) GetJob(ctx context.Context, id string) (*models.CreateJob, error) {
+	job := &models.CreateJob{ID: id, Status: models.CreateJobPending}
+
+	processed, err := s.processed.GetByKey(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return job, nil
+		}
+		return nil, fmt.Errorf("failed to get job: %v", err)
+	}
+
+	if processed.TransactionID != 0 {
+		job.Status = models.CreateJobSucceeded
+		job.TransactionID = processed.TransactionID
+	} else {
+		job.Status = models.CreateJobFailed
+		job.Error = processed.Error
+	}
+	return job, nil
+}
@@ -0,0 +1,111 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/requestid"
+	"interview/internal/services"
+	"interview/pkg/utils"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeJobQueue records the messages written to it
+type fakeJobQueue struct {
+	written []kafka.Message
+	err     error
+}
+
+func (q *fakeJobQueue) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	if q.err != nil {
+		return q.err
+	}
+	q.written = append(q.written, msgs...)
+	return nil
+}
+
+// MockProcessedMessageRepository is a mock implementation of ProcessedMessageRepository
+type MockProcessedMessageRepository struct {
+	mock.Mock
+}
+
+func (m *MockProcessedMessageRepository) GetByKey(ctx context.Context, key string) (*models.ProcessedMessage, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProcessedMessage), args.Error(1)
+}
+
+func TestCreateJobService_Enqueue(t *testing.T) {
+	queue := &fakeJobQueue{}
+	service := services.NewCreateJobService(queue, new(MockProcessedMessageRepository))
+
+	req := models.CreateTransactionRequest{UserID: 1, Amount: decimal.NewFromInt(100)}
+	job, err := service.Enqueue(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, models.CreateJobPending, job.Status)
+	assert.True(t, utils.IsUUID(job.ID))
+
+	require.Len(t, queue.written, 1)
+	assert.Equal(t, job.ID, string(queue.written[0].Key))
+	var queued models.CreateTransactionRequest
+	require.NoError(t, json.Unmarshal(queue.written[0].Value, &queued))
+	assert.Equal(t, uint(1), queued.UserID)
+	assert.True(t, req.Amount.Equal(queued.Amount))
+	assert.Empty(t, queue.written[0].Headers)
+
+	queue.err = errors.New("broker unavailable")
+	_, err = service.Enqueue(context.Background(), req)
+	assert.ErrorContains(t, err, "failed to enqueue transaction")
+}
+
+func TestCreateJobService_EnqueueCarriesRequestID(t *testing.T) {
+	queue := &fakeJobQueue{}
+	service := services.NewCreateJobService(queue, new(MockProcessedMessageRepository))
+
+	ctx := requestid.NewContext(context.Background(), "req-1")
+	_, err := service.Enqueue(ctx, models.CreateTransactionRequest{UserID: 1, Amount: decimal.NewFromInt(100)})
+	require.NoError(t, err)
+
+	require.Len(t, queue.written, 1)
+	assert.Equal(t, []kafka.Header{{Key: "request_id", Value: []byte("req-1")}}, queue.written[0].Headers)
+}
+
+func TestCreateJobService_GetJob(t *testing.T) {
+	repo := new(MockProcessedMessageRepository)
+	service := services.NewCreateJobService(&fakeJobQueue{}, repo)
+
+	repo.On("GetByKey", "queued").Return(nil, gorm.ErrRecordNotFound)
+	repo.On("GetByKey", "created").Return(&models.ProcessedMessage{MessageKey: "created", TransactionID: 7}, nil)
+	repo.On("GetByKey", "rejected").Return(&models.ProcessedMessage{MessageKey: "rejected", Error: "budget exceeded"}, nil)
+	repo.On("GetByKey", "broken").Return(nil, errors.New("connection refused"))
+
+	job, err := service.GetJob(context.Background(), "queued")
+	require.NoError(t, err)
+	assert.Equal(t, models.CreateJobPending, job.Status)
+
+	job, err = service.GetJob(context.Background(), "created")
+	require.NoError(t, err)
+	assert.Equal(t, models.CreateJobSucceeded, job.Status)
+	assert.Equal(t, uint(7), job.TransactionID)
+
+	job, err = service.GetJob(context.Background(), "rejected")
+	require.NoError(t, err)
+	assert.Equal(t, models.CreateJobFailed, job.Status)
+	assert.Equal(t, "budget exceeded", job.Error)
+
+	_, err = service.GetJob(context.Background(), "broken")
+	assert.ErrorContains(t, err, "failed to get job")
+
+	repo.AssertExpectations(t)
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"interview/internal/models"
@@ -9,51 +11,165 @@ import (
 
 // DashboardService interface defines dashboard service methods
 type DashboardService interface {
-	GetSummary() (*models.DashboardSummary, error)
+	GetSummary(ctx context.Context) (*models.DashboardSummary, error)
+	GetPartialSummary(ctx context.Context) (*models.DashboardSummary, error)
+	GetPeriodSummary(ctx context.Context, period models.SummaryPeriod, partial bool) (*models.DashboardSummary, error)
+	GetTopUsers(ctx context.Context, period models.SummaryPeriod, sortBy string, limit int) (*models.TopUsers, error)
 }
 
+// DashboardTagLimit is how many of the most used tags the dashboard summarizes
+const DashboardTagLimit = 10
+
+// DashboardLatestLimit is how many recent transactions the dashboard lists
+const DashboardLatestLimit = 10
+
 // dashboardService implements DashboardService interface
 type dashboardService struct {
-	repo repositories.TransactionRepository
+	repo    repositories.TransactionRepository
+	tags    repositories.TagRepository
+	amounts AmountPolicy
+
+	// metrics compute the summary sections, in order
+	metrics []MetricProvider
+}
+
+// DashboardServiceOption configures optional dashboard service behavior
+type DashboardServiceOption func(*dashboardService)
+
+// WithTagSummaries adds the most used tags to the summary
+func WithTagSummaries(tags repositories.TagRepository) DashboardServiceOption {
+	return func(s *dashboardService) {
+		s.tags = tags
+	}
+}
+
+// WithSummaryAmountPolicy sets the policy summary amounts are rounded with
+func WithSummaryAmountPolicy(policy AmountPolicy) DashboardServiceOption {
+	return func(s *dashboardService) {
+		s.amounts = policy
+	}
 }
 
 // NewDashboardService creates a new dashboard service
-func NewDashboardService(repo repositories.TransactionRepository) DashboardService {
-	return &dashboardService{repo: repo}
+func NewDashboardService(repo repositories.TransactionRepository, opts ...DashboardServiceOption) DashboardService {
+	s := &dashboardService{repo: repo, amounts: DefaultAmountPolicy()}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.metrics = []MetricProvider{
+		NewTodayMetrics(repo),
+		NewUserMetrics(repo),
+		NewLatestTransactions(repo, DashboardLatestLimit),
+		NewStatusMetrics(repo),
+		NewDirectionMetrics(repo),
+		NewCurrencyMetrics(repo),
+		NewDistributionMetrics(repo),
+	}
+	if s.tags != nil {
+		s.metrics = append(s.metrics, NewTagMetrics(s.tags, DashboardTagLimit))
+	}
+	return s
+}
+
+// NewMetricDashboardService creates a dashboard service whose summary is made
+// of the sections computed by metrics, in order
+func NewMetricDashboardService(metrics ...MetricProvider) DashboardService {
+	return &dashboardService{metrics: metrics, amounts: DefaultAmountPolicy()}
 }
 
 // GetSummary gets dashboard summary
-func (s *dashboardService) GetSummary() (*models.DashboardSummary, error) {
-	summary := &models.DashboardSummary{}
+func (s *dashboardService) GetSummary(ctx context.Context) (*models.DashboardSummary, error) {
+	return s.summarize(ctx, MetricProvider.Metric, false)
+}
 
-	// Get today's successful transactions
-	todayCount, todayAmount, err := s.repo.GetTodaySuccessful()
+// GetPartialSummary gets dashboard summary, recording failed sections in
+// summary.Errors instead of failing the whole summary
+func (s *dashboardService) GetPartialSummary(ctx context.Context) (*models.DashboardSummary, error) {
+	return s.summarize(ctx, MetricProvider.Metric, true)
+}
+
+// GetPeriodSummary gets dashboard summary whose sections cover period where
+// their provider supports it. A partial summary records failed sections like
+// GetPartialSummary.
+func (s *dashboardService) GetPeriodSummary(ctx context.Context, period models.SummaryPeriod, partial bool) (*models.DashboardSummary, error) {
+	summary, err := s.summarize(ctx, func(metric MetricProvider, ctx context.Context) (func(*models.DashboardSummary), error) {
+		if provider, ok := metric.(PeriodMetricProvider); ok {
+			return provider.PeriodMetric(ctx, period)
+		}
+		return metric.Metric(ctx)
+	}, partial)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get today's successful transactions: %v", err)
+		return nil, err
 	}
-	summary.TodaySuccessfulTransactions = todayCount
-	summary.TodaySuccessfulAmount = todayAmount
+	summary.Period = &period
+	return summary, nil
+}
 
-	// Get average transactions per user
-	avgPerUser, err := s.repo.GetAveragePerUser()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get average transactions per user: %v", err)
+// GetTopUsers gets the limit users with the highest total amount, or number,
+// of successful transactions created during period
+func (s *dashboardService) GetTopUsers(ctx context.Context, period models.SummaryPeriod, sortBy string, limit int) (*models.TopUsers, error) {
+	if s.repo == nil {
+		return nil, errors.New("top users are not available from this dashboard")
 	}
-	summary.AverageTransactionPerUser = avgPerUser
 
-	// Get latest transactions
-	latestTransactions, err := s.repo.GetLatest(10)
+	users, err := s.repo.GetTopUsersBetween(ctx, period.From, period.To, sortBy, limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get latest transactions: %v", err)
+		return nil, fmt.Errorf("failed to get top users: %v", err)
+	}
+	if users == nil {
+		users = []models.UserVolume{}
+	}
+	for i := range users {
+		users[i].Amount = s.amounts.RoundAggregate(users[i].Amount)
 	}
-	summary.LatestTransactions = latestTransactions
 
-	// Get status counts
-	statusCounts, err := s.repo.GetStatusCounts()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get status counts: %v", err)
+	return &models.TopUsers{Period: period, SortBy: sortBy, Users: users}, nil
+}
+
+// summarize computes every section with compute. A partial summary records
+// failed sections in summary.Errors instead of failing the whole summary.
+func (s *dashboardService) summarize(ctx context.Context, compute func(MetricProvider, context.Context) (func(*models.DashboardSummary), error), partial bool) (*models.DashboardSummary, error) {
+	summary := &models.DashboardSummary{}
+	sectionErrors := map[string]string{}
+
+	for _, metric := range s.metrics {
+		apply, err := compute(metric, ctx)
+		if err != nil && !partial {
+			return nil, err
+		}
+		if err != nil {
+			sectionErrors[metric.Section()] = err.Error()
+			continue
+		}
+		apply(summary)
+	}
+
+	// Only fail when there is nothing left to return
+	if partial && len(sectionErrors) == len(s.metrics) {
+		return nil, errors.New("failed to get any dashboard summary section")
+	}
+	if len(sectionErrors) > 0 {
+		summary.Errors = sectionErrors
 	}
-	summary.StatusCounts = statusCounts
+	s.round(summary)
 
 	return summary, nil
 }
+
+// round rounds the sums and averages of a summary with the amount policy,
+// the currency totals with the rule of their currency. Latest transactions
+// and the amount distribution are stored amounts and are left as they are.
+func (s *dashboardService) round(summary *models.DashboardSummary) {
+	summary.TodaySuccessfulAmount = s.amounts.RoundAggregate(summary.TodaySuccessfulAmount)
+	summary.AverageTransactionPerUser = s.amounts.RoundAggregate(summary.AverageTransactionPerUser)
+	summary.DirectionTotals.Debit.Amount = s.amounts.RoundAggregate(summary.DirectionTotals.Debit.Amount)
+	summary.DirectionTotals.Credit.Amount = s.amounts.RoundAggregate(summary.DirectionTotals.Credit.Amount)
+	for i := range summary.CurrencyTotals {
+		total := &summary.CurrencyTotals[i]
+		total.Amount = s.amounts.RoundCurrency(total.Currency, total.Amount)
+	}
+	for i := range summary.Tags {
+		summary.Tags[i].TotalAmount = s.amounts.RoundAggregate(summary.Tags[i].TotalAmount)
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"interview/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CachedDashboardService wraps a DashboardService and serves the summary from
+// an in-memory cache that is warmed up front and refreshed in the background
+type CachedDashboardService struct {
+	service DashboardService
+
+	mu      sync.RWMutex
+	summary *models.DashboardSummary
+}
+
+// NewCachedDashboardService creates a new cached dashboard service
+func NewCachedDashboardService(service DashboardService) *CachedDashboardService {
+	return &CachedDashboardService{service: service}
+}
+
+// Refresh recomputes the cached summary. On failure the previous summary is kept.
+func (s *CachedDashboardService) Refresh(ctx context.Context) error {
+	summary, err := s.service.GetSummary(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.summary = summary
+	s.mu.Unlock()
+
+	return nil
+}
+
+// StartRefresh refreshes the cache every interval until the returned stop
+// function is called
+func (s *CachedDashboardService) StartRefresh(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.Refresh(context.Background()); err != nil {
+					logrus.WithError(err).Warn("Failed to refresh dashboard cache")
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// GetSummary returns the cached summary, computing it on a cold cache
+func (s *CachedDashboardService) GetSummary(ctx context.Context) (*models.DashboardSummary, error) {
+	s.mu.RLock()
+	summary := s.summary
+	s.mu.RUnlock()
+
+	if summary != nil {
+		return summary, nil
+	}
+
+	if err := s.Refresh(context.Background()); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.summary, nil
+}
+
+// GetPartialSummary is not cached, so section errors always reflect the current state
+func (s *CachedDashboardService) GetPartialSummary(ctx context.Context) (*models.DashboardSummary, error) {
+	return s.service.GetPartialSummary(ctx)
+}
+
+// GetPeriodSummary is not cached, since every request can name another period
+func (s *CachedDashboardService) GetPeriodSummary(ctx context.Context, period models.SummaryPeriod, partial bool) (*models.DashboardSummary, error) {
+	return s.service.GetPeriodSummary(ctx, period, partial)
+}
+
+// GetTopUsers is not cached, since every request can name another period
+func (s *CachedDashboardService) GetTopUsers(ctx context.Context, period models.SummaryPeriod, sortBy string, limit int) (*models.TopUsers, error) {
+	return s.service.GetTopUsers(ctx, period, sortBy, limit)
+}
@@ -0,0 +1,69 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockSummaryQueries(mockRepo *MockTransactionRepository, todayCount int) {
+	mockRepo.On("GetTodaySuccessful").Return(todayCount, decimal.NewFromFloat(500.00), nil).Once()
+	mockRepo.On("GetAveragePerUser").Return(decimal.NewFromFloat(2.5), nil).Once()
+	mockRepo.On("GetLatest", 10).Return([]models.Transaction{}, nil).Once()
+	mockRepo.On("GetStatusCounts").Return(models.StatusCounts{Success: todayCount}, nil).Once()
+	mockRepo.On("GetDirectionTotals").Return(models.DirectionTotals{}, nil).Once()
+	mockRepo.On("GetCurrencyTotals").Return([]models.CurrencyTotal{}, nil).Once()
+	mockRepo.On("GetAmountDistribution").Return(models.AmountDistribution{}, nil).Once()
+}
+
+func TestCachedDashboardService_ServesWarmedSummary(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewCachedDashboardService(services.NewDashboardService(mockRepo))
+
+	mockSummaryQueries(mockRepo, 5)
+	assert.NoError(t, service.Refresh(context.Background()))
+
+	// Served from cache, no further repository calls
+	for i := 0; i < 3; i++ {
+		result, err := service.GetSummary(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 5, result.TodaySuccessfulTransactions)
+	}
+
+	mockRepo.AssertNumberOfCalls(t, "GetTodaySuccessful", 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCachedDashboardService_ColdCache(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewCachedDashboardService(services.NewDashboardService(mockRepo))
+
+	mockSummaryQueries(mockRepo, 7)
+
+	result, err := service.GetSummary(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, result.TodaySuccessfulTransactions)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCachedDashboardService_RefreshErrorKeepsStaleSummary(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewCachedDashboardService(services.NewDashboardService(mockRepo))
+
+	mockSummaryQueries(mockRepo, 5)
+	assert.NoError(t, service.Refresh(context.Background()))
+
+	mockRepo.On("GetTodaySuccessful").Return(0, decimal.Zero, errors.New("database error")).Once()
+	assert.Error(t, service.Refresh(context.Background()))
+
+	result, err := service.GetSummary(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 5, result.TodaySuccessfulTransactions)
+}
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"github.com/shopspring/decimal"
+)
+
+// MetricProvider computes one section of the dashboard summary. New KPIs are
+// added as providers rather than as more queries in the dashboard service.
+type MetricProvider interface {
+	// Section names the section in partial summary errors
+	Section() string
+	// Metric computes the section and returns a function that copies it into
+	// a summary
+	Metric(ctx context.Context) (func(*models.DashboardSummary), error)
+}
+
+// PeriodMetricProvider is a MetricProvider whose section can cover a
+// requested period instead of its default one. Sections of providers that
+// are not cover the same transactions whatever the period.
+type PeriodMetricProvider interface {
+	MetricProvider
+	// PeriodMetric computes the section for the transactions created in period
+	PeriodMetric(ctx context.Context, period models.SummaryPeriod) (func(*models.DashboardSummary), error)
+}
+
+// todayMetrics counts today's successful transactions and their amount
+type todayMetrics struct {
+	repo repositories.TransactionRepository
+}
+
+// NewTodayMetrics provides the today_successful section
+func NewTodayMetrics(repo repositories.TransactionRepository) MetricProvider {
+	return &todayMetrics{repo: repo}
+}
+
+func (m *todayMetrics) Section() string { return "today_successful" }
+
+func (m *todayMetrics) Metric(ctx context.Context) (func(*models.DashboardSummary), error) {
+	count, amount, err := m.repo.GetTodaySuccessful(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get today's successful transactions: %v", err)
+	}
+	return successfulSection(count, amount), nil
+}
+
+func (m *todayMetrics) PeriodMetric(ctx context.Context, period models.SummaryPeriod) (func(*models.DashboardSummary), error) {
+	count, amount, err := m.repo.GetSuccessfulBetween(ctx, period.From, period.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get successful transactions: %v", err)
+	}
+	return successfulSection(count, amount), nil
+}
+
+// successfulSection copies the successful transactions section into a summary
+func successfulSection(count int, amount decimal.Decimal) func(*models.DashboardSummary) {
+	return func(summary *models.DashboardSummary) {
+		summary.TodaySuccessfulTransactions = count
+		summary.TodaySuccessfulAmount = amount
+	}
+}
+
+// userMetrics averages the number of transactions per user
+type userMetrics struct {
+	repo repositories.TransactionRepository
+}
+
+// NewUserMetrics provides the average_transaction_per_user section
+func NewUserMetrics(repo repositories.TransactionRepository) MetricProvider {
+	return &userMetrics{repo: repo}
+}
+
+func (m *userMetrics) Section() string { return "average_transaction_per_user" }
+
+func (m *userMetrics) Metric(ctx context.Context) (func(*models.DashboardSummary), error) {
+	average, err := m.repo.GetAveragePerUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get average transactions per user: %v", err)
+	}
+	return func(summary *models.DashboardSummary) {
+		summary.AverageTransactionPerUser = average
+	}, nil
+}
+
+// latestTransactions lists the most recent transactions
+type latestTransactions struct {
+	repo  repositories.TransactionRepository
+	limit int
+}
+
+// NewLatestTransactions provides the latest_transactions section with up to
+// limit transactions
+func NewLatestTransactions(repo repositories.TransactionRepository, limit int) MetricProvider {
+	return &latestTransactions{repo: repo, limit: limit}
+}
+
+func (m *latestTransactions) Section() string { return "latest_transactions" }
+
+func (m *latestTransactions) Metric(ctx context.Context) (func(*models.DashboardSummary), error) {
+	transactions, err := m.repo.GetLatest(ctx, m.limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest transactions: %v", err)
+	}
+	return func(summary *models.DashboardSummary) {
+		summary.LatestTransactions = transactions
+	}, nil
+}
+
+// statusMetrics counts transactions by status
+type statusMetrics struct {
+	repo repositories.TransactionRepository
+}
+
+// NewStatusMetrics provides the status_counts section
+func NewStatusMetrics(repo repositories.TransactionRepository) MetricProvider {
+	return &statusMetrics{repo: repo}
+}
+
+func (m *statusMetrics) Section() string { return "status_counts" }
+
+func (m *statusMetrics) Metric(ctx context.Context) (func(*models.DashboardSummary), error) {
+	counts, err := m.repo.GetStatusCounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status counts: %v", err)
+	}
+	return statusSection(counts), nil
+}
+
+func (m *statusMetrics) PeriodMetric(ctx context.Context, period models.SummaryPeriod) (func(*models.DashboardSummary), error) {
+	counts, err := m.repo.GetStatusCountsBetween(ctx, period.From, period.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status counts: %v", err)
+	}
+	return statusSection(counts), nil
+}
+
+// statusSection copies the status counts section into a summary
+func statusSection(counts models.StatusCounts) func(*models.DashboardSummary) {
+	return func(summary *models.DashboardSummary) {
+		summary.StatusCounts = counts
+	}
+}
+
+// directionMetrics totals successful transactions by direction
+type directionMetrics struct {
+	repo repositories.TransactionRepository
+}
+
+// NewDirectionMetrics provides the direction_totals section
+func NewDirectionMetrics(repo repositories.TransactionRepository) MetricProvider {
+	return &directionMetrics{repo: repo}
+}
+
+func (m *directionMetrics) Section() string { return "direction_totals" }
+
+func (m *directionMetrics) Metric(ctx context.Context) (func(*models.DashboardSummary), error) {
+	totals, err := m.repo.GetDirectionTotals(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get direction totals: %v", err)
+	}
+	return directionSection(totals), nil
+}
+
+func (m *directionMetrics) PeriodMetric(ctx context.Context, period models.SummaryPeriod) (func(*models.DashboardSummary), error) {
+	totals, err := m.repo.GetDirectionTotalsBetween(ctx, period.From, period.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get direction totals: %v", err)
+	}
+	return directionSection(totals), nil
+}
+
+// directionSection copies the direction totals section into a summary
+func directionSection(totals models.DirectionTotals) func(*models.DashboardSummary) {
+	return func(summary *models.DashboardSummary) {
+		summary.DirectionTotals = totals
+	}
+}
+
+// currencyMetrics totals successful transactions by currency
+type currencyMetrics struct {
+	repo repositories.TransactionRepository
+}
+
+// NewCurrencyMetrics provides the currency_totals section
+func NewCurrencyMetrics(repo repositories.TransactionRepository) MetricProvider {
+	return &currencyMetrics{repo: repo}
+}
+
+func (m *currencyMetrics) Section() string { return "currency_totals" }
+
+func (m *currencyMetrics) Metric(ctx context.Context) (func(*models.DashboardSummary), error) {
+	totals, err := m.repo.GetCurrencyTotals(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get currency totals: %v", err)
+	}
+	return currencySection(totals), nil
+}
+
+func (m *currencyMetrics) PeriodMetric(ctx context.Context, period models.SummaryPeriod) (func(*models.DashboardSummary), error) {
+	totals, err := m.repo.GetCurrencyTotalsBetween(ctx, period.From, period.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get currency totals: %v", err)
+	}
+	return currencySection(totals), nil
+}
+
+// currencySection copies the currency totals section into a summary
+func currencySection(totals []models.CurrencyTotal) func(*models.DashboardSummary) {
+	return func(summary *models.DashboardSummary) {
+		summary.CurrencyTotals = totals
+	}
+}
+
+// distributionMetrics describes the spread of successful transaction amounts
+type distributionMetrics struct {
+	repo repositories.TransactionRepository
+}
+
+// NewDistributionMetrics provides the amount_distribution section
+func NewDistributionMetrics(repo repositories.TransactionRepository) MetricProvider {
+	return &distributionMetrics{repo: repo}
+}
+
+func (m *distributionMetrics) Section() string { return "amount_distribution" }
+
+func (m *distributionMetrics) Metric(ctx context.Context) (func(*models.DashboardSummary), error) {
+	distribution, err := m.repo.GetAmountDistribution(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get amount distribution: %v", err)
+	}
+	return distributionSection(distribution), nil
+}
+
+func (m *distributionMetrics) PeriodMetric(ctx context.Context, period models.SummaryPeriod) (func(*models.DashboardSummary), error) {
+	distribution, err := m.repo.GetAmountDistributionBetween(ctx, period.From, period.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get amount distribution: %v", err)
+	}
+	return distributionSection(distribution), nil
+}
+
+// distributionSection copies the amount distribution section into a summary
+func distributionSection(distribution models.AmountDistribution) func(*models.DashboardSummary) {
+	return func(summary *models.DashboardSummary) {
+		summary.AmountDistribution = distribution
+	}
+}
+
+// tagMetrics summarizes the most used tags
+type tagMetrics struct {
+	tags  repositories.TagRepository
+	limit int
+}
+
+// NewTagMetrics provides the tags section with up to limit tags
+func NewTagMetrics(tags repositories.TagRepository, limit int) MetricProvider {
+	return &tagMetrics{tags: tags, limit: limit}
+}
+
+func (m *tagMetrics) Section() string { return "tags" }
+
+func (m *tagMetrics) Metric(ctx context.Context) (func(*models.DashboardSummary), error) {
+	tags, err := m.tags.GetSummaries(ctx, m.limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag summaries: %v", err)
+	}
+	return func(summary *models.DashboardSummary) {
+		summary.Tags = tags
+	}, nil
+}
+
+// cachedMetric serves a provider's last result until it is ttl old
+type cachedMetric struct {
+	provider MetricProvider
+	ttl      time.Duration
+
+	mu       sync.Mutex
+	apply    func(*models.DashboardSummary)
+	computed time.Time
+}
+
+// NewCachedMetric caches the sections computed by provider for ttl, so
+// expensive sections can be refreshed less often than cheap ones. Failures
+// are not cached.
+func NewCachedMetric(provider MetricProvider, ttl time.Duration) MetricProvider {
+	return &cachedMetric{provider: provider, ttl: ttl}
+}
+
+func (m *cachedMetric) Section() string { return m.provider.Section() }
+
+func (m *cachedMetric) Metric(ctx context.Context) (func(*models.DashboardSummary), error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.apply != nil && time.Since(m.computed) < m.ttl {
+		return m.apply, nil
+	}
+	apply, err := m.provider.Metric(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m.apply, m.computed = apply, time.Now()
+	return apply, nil
+}
+
+// PeriodMetric computes periods of the provider uncached; the sections of
+// providers without periods are served from the cache as usual
+func (m *cachedMetric) PeriodMetric(ctx context.Context, period models.SummaryPeriod) (func(*models.DashboardSummary), error) {
+	if provider, ok := m.provider.(PeriodMetricProvider); ok {
+		return provider.PeriodMetric(ctx, period)
+	}
+	return m.Metric(ctx)
+}
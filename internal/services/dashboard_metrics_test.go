@@ -0,0 +1,213 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingMetric sets the today_successful count to the number of times it
+// was computed, failing while err is set
+type countingMetric struct {
+	calls int
+	err   error
+}
+
+func (m *countingMetric) Section() string { return "today_successful" }
+
+func (m *countingMetric) Metric(ctx context.Context) (func(*models.DashboardSummary), error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	calls := m.calls
+	return func(summary *models.DashboardSummary) {
+		summary.TodaySuccessfulTransactions = calls
+	}, nil
+}
+
+func TestMetricDashboardService_ComposesProviders(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	mockRepo.On("GetStatusCounts").Return(models.StatusCounts{Success: 3}, nil)
+	mockRepo.On("GetAveragePerUser").Return(decimal.Decimal{}, errors.New("timeout"))
+
+	service := services.NewMetricDashboardService(
+		&countingMetric{},
+		services.NewStatusMetrics(mockRepo),
+		services.NewUserMetrics(mockRepo),
+	)
+
+	_, err := service.GetSummary(context.Background())
+	assert.EqualError(t, err, "failed to get average transactions per user: timeout")
+
+	summary, err := service.GetPartialSummary(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.TodaySuccessfulTransactions)
+	assert.Equal(t, 3, summary.StatusCounts.Success)
+	assert.Equal(t, map[string]string{
+		"average_transaction_per_user": "failed to get average transactions per user: timeout",
+	}, summary.Errors)
+}
+
+func TestCachedMetric_ServesResultUntilTTL(t *testing.T) {
+	provider := &countingMetric{}
+	service := services.NewMetricDashboardService(services.NewCachedMetric(provider, time.Hour))
+
+	for i := 0; i < 3; i++ {
+		summary, err := service.GetSummary(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, summary.TodaySuccessfulTransactions)
+	}
+	assert.Equal(t, 1, provider.calls)
+}
+
+func TestCachedMetric_RecomputesAfterTTL(t *testing.T) {
+	provider := &countingMetric{}
+	cached := services.NewCachedMetric(provider, time.Millisecond)
+
+	_, err := cached.Metric(context.Background())
+	require.NoError(t, err)
+	time.Sleep(2 * time.Millisecond)
+	_, err = cached.Metric(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, provider.calls)
+	assert.Equal(t, "today_successful", cached.Section())
+}
+
+func TestCachedMetric_DoesNotCacheFailures(t *testing.T) {
+	provider := &countingMetric{err: errors.New("timeout")}
+	cached := services.NewCachedMetric(provider, time.Hour)
+
+	_, err := cached.Metric(context.Background())
+	assert.Error(t, err)
+
+	provider.err = nil
+	apply, err := cached.Metric(context.Background())
+	require.NoError(t, err)
+	summary := &models.DashboardSummary{}
+	apply(summary)
+	assert.Equal(t, 2, summary.TodaySuccessfulTransactions)
+}
+
+// noisyMetric fills the summary amounts with float sums
+type noisyMetric struct{}
+
+func (noisyMetric) Section() string { return "today_successful" }
+
+func (noisyMetric) Metric(ctx context.Context) (func(*models.DashboardSummary), error) {
+	return func(summary *models.DashboardSummary) {
+		summary.TodaySuccessfulAmount = decimal.RequireFromString("3936.1099999999997")
+		summary.AverageTransactionPerUser = decimal.RequireFromString("3.3333333333333335")
+		summary.DirectionTotals.Debit.Amount = decimal.RequireFromString("2349.8599999999997")
+		summary.DirectionTotals.Credit.Amount = decimal.RequireFromString("1586.25")
+		summary.CurrencyTotals = []models.CurrencyTotal{
+			{Currency: "JPY", Count: 3, Amount: decimal.RequireFromString("1500.4999999999998")},
+			{Currency: "USD", Count: 2, Amount: decimal.RequireFromString("0.30000000000000004")},
+		}
+		summary.Tags = []models.TagSummary{{Name: "refund", Count: 2, TotalAmount: decimal.RequireFromString("0.30000000000000004")}}
+	}, nil
+}
+
+func TestMetricDashboardService_RoundsAggregates(t *testing.T) {
+	service := services.NewMetricDashboardService(noisyMetric{})
+
+	for _, get := range []func(context.Context) (*models.DashboardSummary, error){service.GetSummary, service.GetPartialSummary} {
+		summary, err := get(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "3936.11", summary.TodaySuccessfulAmount.String())
+		assert.Equal(t, "3.33", summary.AverageTransactionPerUser.String())
+		assert.Equal(t, "2349.86", summary.DirectionTotals.Debit.Amount.String())
+		assert.Equal(t, "1586.25", summary.DirectionTotals.Credit.Amount.String())
+		assert.Equal(t, "0.3", summary.Tags[0].TotalAmount.String())
+		// Currency totals round to their currency's scale
+		assert.Equal(t, "1500", summary.CurrencyTotals[0].Amount.String())
+		assert.Equal(t, "0.3", summary.CurrencyTotals[1].Amount.String())
+	}
+}
+
+func TestDashboardService_GetPeriodSummary(t *testing.T) {
+	period := models.SummaryPeriod{From: time.Date(2024, 6, 9, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)}
+	mockRepo := new(MockTransactionRepository)
+	mockRepo.On("GetSuccessfulBetween", period.From, period.To).Return(4, decimal.NewFromInt(400), nil)
+	mockRepo.On("GetStatusCountsBetween", period.From, period.To).Return(models.StatusCounts{Success: 4, Failed: 1}, nil)
+	mockRepo.On("GetDirectionTotalsBetween", period.From, period.To).Return(models.DirectionTotals{}, errors.New("timeout"))
+	mockRepo.On("GetCurrencyTotalsBetween", period.From, period.To).Return([]models.CurrencyTotal{{Currency: "USD", Count: 4, Amount: decimal.NewFromInt(400)}}, nil)
+	mockRepo.On("GetAmountDistributionBetween", period.From, period.To).Return(models.AmountDistribution{Count: 4, Max: decimal.NewFromInt(250)}, nil)
+	// Sections without periods cover every transaction as usual
+	mockRepo.On("GetAveragePerUser").Return(decimal.NewFromInt(2), nil)
+	mockRepo.On("GetLatest", services.DashboardLatestLimit).Return([]models.Transaction{}, nil)
+
+	service := services.NewDashboardService(mockRepo)
+
+	_, err := service.GetPeriodSummary(context.Background(), period, false)
+	assert.EqualError(t, err, "failed to get direction totals: timeout")
+
+	summary, err := service.GetPeriodSummary(context.Background(), period, true)
+	require.NoError(t, err)
+	assert.Equal(t, 4, summary.TodaySuccessfulTransactions)
+	assert.Equal(t, "400", summary.TodaySuccessfulAmount.String())
+	assert.Equal(t, models.StatusCounts{Success: 4, Failed: 1}, summary.StatusCounts)
+	assert.Equal(t, "2", summary.AverageTransactionPerUser.String())
+	assert.Equal(t, 4, summary.AmountDistribution.Count)
+	assert.Equal(t, []models.CurrencyTotal{{Currency: "USD", Count: 4, Amount: decimal.NewFromInt(400)}}, summary.CurrencyTotals)
+	assert.Equal(t, &period, summary.Period)
+	assert.Equal(t, map[string]string{"direction_totals": "failed to get direction totals: timeout"}, summary.Errors)
+	mockRepo.AssertNotCalled(t, "GetTodaySuccessful")
+	mockRepo.AssertNotCalled(t, "GetStatusCounts")
+	mockRepo.AssertNotCalled(t, "GetAmountDistribution")
+	mockRepo.AssertNotCalled(t, "GetCurrencyTotals")
+}
+
+func TestDashboardService_GetTopUsers(t *testing.T) {
+	period := models.SummaryPeriod{From: time.Date(2024, 6, 9, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)}
+	mockRepo := new(MockTransactionRepository)
+	mockRepo.On("GetTopUsersBetween", period.From, period.To, models.TopUsersByCount, 5).
+		Return([]models.UserVolume{{UserID: 2, Name: "Bob", Count: 3, Amount: decimal.RequireFromString("10.005")}}, nil).Once()
+	mockRepo.On("GetTopUsersBetween", period.From, period.To, models.TopUsersByAmount, 5).Return(nil, nil).Once()
+	mockRepo.On("GetTopUsersBetween", period.From, period.To, models.TopUsersByAmount, 1).Return(nil, errors.New("timeout")).Once()
+
+	service := services.NewDashboardService(mockRepo)
+
+	top, err := service.GetTopUsers(context.Background(), period, models.TopUsersByCount, 5)
+	require.NoError(t, err)
+	assert.Equal(t, period, top.Period)
+	assert.Equal(t, models.TopUsersByCount, top.SortBy)
+	require.Len(t, top.Users, 1)
+	assert.Equal(t, "10.01", top.Users[0].Amount.String())
+
+	// No users is an empty list rather than null
+	top, err = service.GetTopUsers(context.Background(), period, models.TopUsersByAmount, 5)
+	require.NoError(t, err)
+	assert.NotNil(t, top.Users)
+	assert.Empty(t, top.Users)
+
+	_, err = service.GetTopUsers(context.Background(), period, models.TopUsersByAmount, 1)
+	assert.EqualError(t, err, "failed to get top users: timeout")
+	mockRepo.AssertExpectations(t)
+
+	// Dashboards made of metrics alone have no repository to rank users with
+	_, err = services.NewMetricDashboardService().GetTopUsers(context.Background(), period, models.TopUsersByAmount, 1)
+	assert.Error(t, err)
+}
+
+func TestCachedMetric_ComputesPeriodsUncached(t *testing.T) {
+	period := models.SummaryPeriod{From: time.Date(2024, 6, 9, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)}
+	mockRepo := new(MockTransactionRepository)
+	mockRepo.On("GetSuccessfulBetween", period.From, period.To).Return(4, decimal.NewFromInt(400), nil).Twice()
+
+	service := services.NewMetricDashboardService(services.NewCachedMetric(services.NewTodayMetrics(mockRepo), time.Hour))
+	for i := 0; i < 2; i++ {
+		summary, err := service.GetPeriodSummary(context.Background(), period, false)
+		require.NoError(t, err)
+		assert.Equal(t, 4, summary.TodaySuccessfulTransactions)
+	}
+	mockRepo.AssertExpectations(t)
+}
@@ -1,6 +1,7 @@
 package services_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -26,12 +27,25 @@ func TestDashboardService_GetSummary(t *testing.T) {
 		Failed:  2,
 	}
 
+	expectedDirectionTotals := models.DirectionTotals{
+		Debit:  models.DirectionTotal{Count: 4, Amount: decimal.NewFromInt(900)},
+		Credit: models.DirectionTotal{Count: 1, Amount: decimal.NewFromInt(600)},
+	}
+
+	expectedCurrencyTotals := []models.CurrencyTotal{
+		{Currency: "JPY", Count: 2, Amount: decimal.NewFromInt(1000)},
+		{Currency: "USD", Count: 8, Amount: decimal.NewFromFloat(500.50)},
+	}
+
 	mockRepo.On("GetTodaySuccessful").Return(10, decimal.NewFromFloat(1500.50), nil)
 	mockRepo.On("GetAveragePerUser").Return(decimal.NewFromFloat(2.5), nil)
 	mockRepo.On("GetLatest", 10).Return(expectedTransactions, nil)
 	mockRepo.On("GetStatusCounts").Return(expectedStatusCounts, nil)
+	mockRepo.On("GetDirectionTotals").Return(expectedDirectionTotals, nil)
+	mockRepo.On("GetCurrencyTotals").Return(expectedCurrencyTotals, nil)
+	mockRepo.On("GetAmountDistribution").Return(models.AmountDistribution{}, nil)
 
-	result, err := service.GetSummary()
+	result, err := service.GetSummary(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, 10, result.TodaySuccessfulTransactions)
@@ -39,6 +53,8 @@ func TestDashboardService_GetSummary(t *testing.T) {
 	assert.True(t, result.AverageTransactionPerUser.Equal(decimal.NewFromFloat(2.5)))
 	assert.Equal(t, expectedTransactions, result.LatestTransactions)
 	assert.Equal(t, expectedStatusCounts, result.StatusCounts)
+	assert.Equal(t, expectedDirectionTotals, result.DirectionTotals)
+	assert.Equal(t, expectedCurrencyTotals, result.CurrencyTotals)
 	mockRepo.AssertExpectations(t)
 }
 
@@ -48,7 +64,7 @@ func TestDashboardService_GetSummaryTodaySuccessfulError(t *testing.T) {
 
 	mockRepo.On("GetTodaySuccessful").Return(0, decimal.Zero, errors.New("database error"))
 
-	result, err := service.GetSummary()
+	result, err := service.GetSummary(context.Background())
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -63,7 +79,7 @@ func TestDashboardService_GetSummaryAveragePerUserError(t *testing.T) {
 	mockRepo.On("GetTodaySuccessful").Return(5, decimal.NewFromFloat(500.00), nil)
 	mockRepo.On("GetAveragePerUser").Return(decimal.Zero, errors.New("calculation error"))
 
-	result, err := service.GetSummary()
+	result, err := service.GetSummary(context.Background())
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -79,7 +95,7 @@ func TestDashboardService_GetSummaryLatestTransactionsError(t *testing.T) {
 	mockRepo.On("GetAveragePerUser").Return(decimal.NewFromFloat(3.0), nil)
 	mockRepo.On("GetLatest", 10).Return([]models.Transaction{}, errors.New("fetch error"))
 
-	result, err := service.GetSummary()
+	result, err := service.GetSummary(context.Background())
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -100,10 +116,53 @@ func TestDashboardService_GetSummaryStatusCountsError(t *testing.T) {
 	mockRepo.On("GetLatest", 10).Return(expectedTransactions, nil)
 	mockRepo.On("GetStatusCounts").Return(models.StatusCounts{}, errors.New("count error"))
 
-	result, err := service.GetSummary()
+	result, err := service.GetSummary(context.Background())
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "failed to get status counts")
 	mockRepo.AssertExpectations(t)
 }
+
+func TestDashboardService_GetPartialSummaryAveragePerUserError(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewDashboardService(mockRepo)
+
+	expectedStatusCounts := models.StatusCounts{Success: 5, Pending: 3, Failed: 2}
+
+	mockRepo.On("GetTodaySuccessful").Return(5, decimal.NewFromFloat(500.00), nil)
+	mockRepo.On("GetAveragePerUser").Return(decimal.Zero, errors.New("calculation error"))
+	mockRepo.On("GetLatest", 10).Return([]models.Transaction{}, nil)
+	mockRepo.On("GetStatusCounts").Return(expectedStatusCounts, nil)
+	mockRepo.On("GetDirectionTotals").Return(models.DirectionTotals{}, nil)
+	mockRepo.On("GetCurrencyTotals").Return([]models.CurrencyTotal{}, nil)
+	mockRepo.On("GetAmountDistribution").Return(models.AmountDistribution{}, nil)
+
+	result, err := service.GetPartialSummary(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, result.TodaySuccessfulTransactions)
+	assert.Equal(t, expectedStatusCounts, result.StatusCounts)
+	assert.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors["average_transaction_per_user"], "calculation error")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDashboardService_GetPartialSummaryAllSectionsFail(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewDashboardService(mockRepo)
+
+	mockRepo.On("GetTodaySuccessful").Return(0, decimal.Zero, errors.New("database error"))
+	mockRepo.On("GetAveragePerUser").Return(decimal.Zero, errors.New("database error"))
+	mockRepo.On("GetLatest", 10).Return([]models.Transaction{}, errors.New("database error"))
+	mockRepo.On("GetStatusCounts").Return(models.StatusCounts{}, errors.New("database error"))
+	mockRepo.On("GetDirectionTotals").Return(models.DirectionTotals{}, errors.New("database error"))
+	mockRepo.On("GetCurrencyTotals").Return([]models.CurrencyTotal{}, errors.New("database error"))
+	mockRepo.On("GetAmountDistribution").Return(models.AmountDistribution{}, errors.New("database error"))
+
+	result, err := service.GetPartialSummary(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+	"interview/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// MaxFixtureTransactions caps how many transactions a single fixture holds
+const MaxFixtureTransactions = 1000
+
+// FixtureService interface defines fixture service methods
+type FixtureService interface {
+	Export(ctx context.Context, filters models.TransactionFilters) (*models.Fixture, error)
+	Load(ctx context.Context, fixture *models.Fixture) error
+}
+
+// fixtureService implements FixtureService interface
+type fixtureService struct {
+	transactions repositories.TransactionRepository
+	budgets      repositories.BudgetRepository
+	users        repositories.UserRepository
+}
+
+// NewFixtureService creates a new fixture service
+func NewFixtureService(transactions repositories.TransactionRepository, budgets repositories.BudgetRepository, users repositories.UserRepository) FixtureService {
+	return &fixtureService{transactions: transactions, budgets: budgets, users: users}
+}
+
+// Export builds a sanitized fixture of the transactions matching filters and
+// the budgets of their users. User IDs are replaced with sequential
+// pseudonyms (consistently across records), transaction IDs with their
+// position in the fixture and free-form descriptions and metadata are
+// cleared. A refund keeps its link to a parent in the fixture and loses it
+// otherwise.
+func (s *fixtureService) Export(ctx context.Context, filters models.TransactionFilters) (*models.Fixture, error) {
+	if filters.Status != "" && filters.Status != "pending" && filters.Status != "success" && filters.Status != "failed" && filters.Status != "refunded" {
+		return nil, errors.New("invalid status filter")
+	}
+
+	var transactions []models.Transaction
+	page := filters
+	page.Limit = 100
+	page.Offset = 0
+	// Fixtures are always exported in the default order
+	page.SortBy, page.Order = "", ""
+	for len(transactions) < MaxFixtureTransactions {
+		batch, err := s.transactions.GetAll(ctx, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transactions: %v", err)
+		}
+		transactions = append(transactions, batch...)
+		if len(batch) < page.Limit {
+			break
+		}
+		page.Offset += page.Limit
+	}
+	if len(transactions) > MaxFixtureTransactions {
+		transactions = transactions[:MaxFixtureTransactions]
+	}
+
+	pseudonyms := map[uint]uint{}
+	pseudonym := func(userID uint) uint {
+		if _, ok := pseudonyms[userID]; !ok {
+			pseudonyms[userID] = uint(len(pseudonyms) + 1)
+		}
+		return pseudonyms[userID]
+	}
+
+	fixtureIDs := make(map[uint]uint, len(transactions))
+	for i := range transactions {
+		fixtureIDs[transactions[i].ID] = uint(i + 1)
+	}
+
+	budgets := []models.Budget{}
+	for i := range transactions {
+		userID := transactions[i].UserID
+		if _, seen := pseudonyms[userID]; !seen {
+			budget, err := s.budgets.GetByUserID(ctx, userID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("failed to get budget: %v", err)
+			}
+			if budget != nil {
+				budget.UserID = pseudonym(userID)
+				budgets = append(budgets, *budget)
+			}
+		}
+		transactions[i].ID = fixtureIDs[transactions[i].ID]
+		if transactions[i].ParentID != nil {
+			if parentID, ok := fixtureIDs[*transactions[i].ParentID]; ok {
+				transactions[i].ParentID = &parentID
+			} else {
+				transactions[i].ParentID = nil
+			}
+		}
+		transactions[i].UserID = pseudonym(userID)
+		transactions[i].Description = ""
+		transactions[i].Metadata = nil
+		transactions[i].Tags = nil
+		transactions[i].ReferenceID = nil
+		transactions[i].UUID = nil
+	}
+
+	exportedFilters := models.TransactionFilters{Status: filters.Status}
+	if filters.UserID != 0 {
+		exportedFilters.UserID = pseudonym(filters.UserID)
+	}
+
+	if transactions == nil {
+		transactions = []models.Transaction{}
+	}
+
+	return &models.Fixture{
+		Version:      models.FixtureVersion,
+		ExportedAt:   time.Now(),
+		Filters:      exportedFilters,
+		Transactions: transactions,
+		Budgets:      budgets,
+	}, nil
+}
+
+// Load imports a fixture. Transactions get fresh IDs; their timestamps
+// and pseudonymous user IDs are kept, and a placeholder user is created for
+// each pseudonym that has no user yet. Refunds are loaded after the
+// transactions they reverse and linked to their new IDs.
+func (s *fixtureService) Load(ctx context.Context, fixture *models.Fixture) error {
+	if fixture.Version != models.FixtureVersion {
+		return fmt.Errorf("unsupported fixture version %d", fixture.Version)
+	}
+
+	var userIDs []uint
+	seen := map[uint]bool{}
+	for _, transaction := range fixture.Transactions {
+		if !seen[transaction.UserID] {
+			seen[transaction.UserID] = true
+			userIDs = append(userIDs, transaction.UserID)
+		}
+	}
+	if err := s.users.EnsureExist(ctx, userIDs); err != nil {
+		return fmt.Errorf("failed to load users: %v", err)
+	}
+
+	for i := range fixture.Budgets {
+		if err := s.budgets.Upsert(ctx, &fixture.Budgets[i]); err != nil {
+			return fmt.Errorf("failed to load budget: %v", err)
+		}
+	}
+
+	inFixture := map[uint]bool{}
+	for _, transaction := range fixture.Transactions {
+		if transaction.ID != 0 {
+			inFixture[transaction.ID] = true
+		}
+	}
+
+	loadedIDs := map[uint]uint{}
+	pending := fixture.Transactions
+	for len(pending) > 0 {
+		var deferred []models.Transaction
+		for _, transaction := range pending {
+			if transaction.ParentID != nil && inFixture[*transaction.ParentID] {
+				parentID, loaded := loadedIDs[*transaction.ParentID]
+				if !loaded {
+					deferred = append(deferred, transaction)
+					continue
+				}
+				transaction.ParentID = &parentID
+			} else {
+				// Fixtures exported before refunds were linked carry the
+				// parent's original ID, which means nothing here
+				transaction.ParentID = nil
+			}
+
+			fixtureID := transaction.ID
+			transaction.ID = 0
+			uuid := utils.NewUUID()
+			transaction.UUID = &uuid
+			if err := s.transactions.Create(ctx, &transaction); err != nil {
+				return fmt.Errorf("failed to load transaction: %v", err)
+			}
+			if fixtureID != 0 {
+				loadedIDs[fixtureID] = transaction.ID
+			}
+		}
+		if len(deferred) == len(pending) {
+			return errors.New("fixture refunds reference each other in a cycle")
+		}
+		pending = deferred
+	}
+
+	return nil
+}
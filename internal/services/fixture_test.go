@@ -0,0 +1,151 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestFixtureService_ExportSanitizesUserIDs(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	mockBudgetRepo := new(MockBudgetRepository)
+	mockUserRepo := new(MockUserRepository)
+	service := services.NewFixtureService(mockRepo, mockBudgetRepo, mockUserRepo)
+
+	mockRepo.On("GetAll", models.TransactionFilters{Status: "failed", Limit: 100}).Return([]models.Transaction{
+		{ID: 10, UserID: 501, Amount: decimal.NewFromFloat(10), Status: "failed"},
+		{ID: 11, UserID: 777, Amount: decimal.NewFromFloat(20), Status: "failed", Description: "Refund for Jane", Metadata: models.TransactionMetadata{"email": "jane@example.com"}},
+		{ID: 12, UserID: 501, Amount: decimal.NewFromFloat(30), Status: "failed"},
+	}, nil)
+	mockBudgetRepo.On("GetByUserID", uint(501)).Return(&models.Budget{UserID: 501, MonthlyCap: decimal.NewFromInt(100)}, nil)
+	mockBudgetRepo.On("GetByUserID", uint(777)).Return(nil, gorm.ErrRecordNotFound)
+
+	fixture, err := service.Export(context.Background(), models.TransactionFilters{Status: "failed"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.FixtureVersion, fixture.Version)
+	assert.Len(t, fixture.Transactions, 3)
+	assert.Equal(t, []uint{1, 2, 1}, []uint{fixture.Transactions[0].UserID, fixture.Transactions[1].UserID, fixture.Transactions[2].UserID})
+	assert.Equal(t, []uint{1, 2, 3}, []uint{fixture.Transactions[0].ID, fixture.Transactions[1].ID, fixture.Transactions[2].ID})
+	assert.Empty(t, fixture.Transactions[1].Description)
+	assert.Nil(t, fixture.Transactions[1].Metadata)
+	assert.Len(t, fixture.Budgets, 1)
+	assert.Equal(t, uint(1), fixture.Budgets[0].UserID)
+	mockRepo.AssertExpectations(t)
+	mockBudgetRepo.AssertExpectations(t)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestFixtureService_ExportLinksRefunds(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	mockBudgetRepo := new(MockBudgetRepository)
+	service := services.NewFixtureService(mockRepo, mockBudgetRepo, new(MockUserRepository))
+
+	parentID, outsideID := uint(10), uint(3)
+	mockRepo.On("GetAll", models.TransactionFilters{Status: "refunded", Limit: 100}).Return([]models.Transaction{
+		{ID: 12, UserID: 501, Amount: decimal.NewFromInt(-10), Type: models.TransactionTypeRefund, Status: "success", ParentID: &parentID},
+		{ID: 11, UserID: 501, Amount: decimal.NewFromInt(-5), Type: models.TransactionTypeRefund, Status: "success", ParentID: &outsideID},
+		{ID: 10, UserID: 501, Amount: decimal.NewFromInt(10), Status: "refunded"},
+	}, nil)
+	mockBudgetRepo.On("GetByUserID", uint(501)).Return(nil, gorm.ErrRecordNotFound)
+
+	fixture, err := service.Export(context.Background(), models.TransactionFilters{Status: "refunded"})
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, fixture.Transactions[0].ParentID) {
+		assert.Equal(t, uint(3), *fixture.Transactions[0].ParentID)
+	}
+	// The parent of the second refund is not in the fixture
+	assert.Nil(t, fixture.Transactions[1].ParentID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFixtureService_ExportInvalidStatus(t *testing.T) {
+	service := services.NewFixtureService(new(MockTransactionRepository), new(MockBudgetRepository), new(MockUserRepository))
+
+	fixture, err := service.Export(context.Background(), models.TransactionFilters{Status: "unknown"})
+
+	assert.EqualError(t, err, "invalid status filter")
+	assert.Nil(t, fixture)
+}
+
+func TestFixtureService_Load(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	mockBudgetRepo := new(MockBudgetRepository)
+	mockUserRepo := new(MockUserRepository)
+	service := services.NewFixtureService(mockRepo, mockBudgetRepo, mockUserRepo)
+
+	mockBudgetRepo.On("Upsert", mock.AnythingOfType("*models.Budget")).Return(nil).Once()
+	mockUserRepo.On("EnsureExist", []uint{1, 2}).Return(nil).Once()
+	mockRepo.On("Create", mock.MatchedBy(func(tx *models.Transaction) bool { return tx.ID == 0 && tx.UUID != nil })).Return(nil).Twice()
+
+	err := service.Load(context.Background(), &models.Fixture{
+		Version:      models.FixtureVersion,
+		Transactions: []models.Transaction{{ID: 5, UserID: 1}, {ID: 6, UserID: 2}},
+		Budgets:      []models.Budget{{UserID: 1}},
+	})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockBudgetRepo.AssertExpectations(t)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestFixtureService_LoadLinksRefunds(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	mockUserRepo := new(MockUserRepository)
+	service := services.NewFixtureService(mockRepo, new(MockBudgetRepository), mockUserRepo)
+
+	var created []models.Transaction
+	mockUserRepo.On("EnsureExist", []uint{1}).Return(nil).Once()
+	mockRepo.On("Create", mock.AnythingOfType("*models.Transaction")).Run(func(args mock.Arguments) {
+		tx := args.Get(0).(*models.Transaction)
+		tx.ID = uint(100 + len(created))
+		created = append(created, *tx)
+	}).Return(nil).Times(3)
+
+	fixtureParentID, staleParentID := uint(2), uint(42)
+	err := service.Load(context.Background(), &models.Fixture{
+		Version: models.FixtureVersion,
+		Transactions: []models.Transaction{
+			{ID: 1, UserID: 1, Type: models.TransactionTypeRefund, ParentID: &fixtureParentID},
+			{ID: 2, UserID: 1, Status: "refunded"},
+			{UserID: 1, Type: models.TransactionTypeRefund, ParentID: &staleParentID},
+		},
+	})
+
+	assert.NoError(t, err)
+	if assert.Len(t, created, 3) {
+		// The refund is loaded after its parent and linked to its new ID
+		assert.Equal(t, "refunded", created[0].Status)
+		assert.Nil(t, created[1].ParentID)
+		if assert.NotNil(t, created[2].ParentID) {
+			assert.Equal(t, uint(100), *created[2].ParentID)
+		}
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFixtureService_LoadRefundCycle(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	service := services.NewFixtureService(new(MockTransactionRepository), new(MockBudgetRepository), mockUserRepo)
+	mockUserRepo.On("EnsureExist", []uint{1}).Return(nil)
+
+	firstID, secondID := uint(1), uint(2)
+	err := service.Load(context.Background(), &models.Fixture{
+		Version: models.FixtureVersion,
+		Transactions: []models.Transaction{
+			{ID: 1, UserID: 1, ParentID: &secondID},
+			{ID: 2, UserID: 1, ParentID: &firstID},
+		},
+	})
+
+	assert.EqualError(t, err, "fixture refunds reference each other in a cycle")
+}
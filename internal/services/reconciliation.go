@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+)
+
+// ReconciliationService interface defines reconciliation service methods
+type ReconciliationService interface {
+	Compare(ctx context.Context, req models.ReconciliationRequest, filters models.ReconciliationFilters) (*models.ReconciliationResult, error)
+}
+
+// reconciliationService implements ReconciliationService interface
+type reconciliationService struct {
+	transactions repositories.TransactionRepository
+}
+
+// NewReconciliationService creates a new reconciliation service
+func NewReconciliationService(transactions repositories.TransactionRepository) ReconciliationService {
+	return &reconciliationService{transactions: transactions}
+}
+
+// Compare matches provider records to our transactions by reference ID in
+// one query and reports those that are missing or whose amount or status
+// differs. Amounts are compared by value, so 100.5 matches 100.50. The
+// result counts every record but holds only the page of mismatches filters
+// selects.
+func (s *reconciliationService) Compare(ctx context.Context, req models.ReconciliationRequest, filters models.ReconciliationFilters) (*models.ReconciliationResult, error) {
+	references := make([]string, len(req.Items))
+	for i, item := range req.Items {
+		references[i] = item.Reference
+	}
+	found, err := s.transactions.GetByReferenceIDs(ctx, references)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %v", err)
+	}
+
+	byReference := make(map[string]*models.Transaction, len(found))
+	for i := range found {
+		byReference[*found[i].ReferenceID] = &found[i]
+	}
+
+	limit, offset := filters.Page()
+	result := &models.ReconciliationResult{
+		Compared:   len(req.Items),
+		Mismatches: make([]models.ReconciliationMismatch, 0),
+	}
+	for i, item := range req.Items {
+		transaction := byReference[item.Reference]
+		kinds := mismatchKinds(item, transaction)
+		if len(kinds) == 0 {
+			result.Matched++
+			continue
+		}
+
+		if result.Mismatched >= offset && len(result.Mismatches) < limit {
+			result.Mismatches = append(result.Mismatches, models.ReconciliationMismatch{
+				Index:       i,
+				Reference:   item.Reference,
+				Kinds:       kinds,
+				Provider:    item,
+				Transaction: transaction,
+			})
+		}
+		result.Mismatched++
+	}
+	return result, nil
+}
+
+// mismatchKinds returns how transaction disagrees with the provider's record
+// of it, or nothing when they agree
+func mismatchKinds(item models.ReconciliationItem, transaction *models.Transaction) []string {
+	if transaction == nil {
+		return []string{models.MismatchMissing}
+	}
+	var kinds []string
+	if !transaction.Amount.Equal(item.Amount) {
+		kinds = append(kinds, models.MismatchAmount)
+	}
+	if transaction.Status != item.Status {
+		kinds = append(kinds, models.MismatchStatus)
+	}
+	return kinds
+}
@@ -0,0 +1,75 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconciliationService_Compare(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewReconciliationService(mockRepo)
+
+	reference := func(ref string) *string { return &ref }
+	matched := models.Transaction{ID: 1, ReferenceID: reference("ORD-1"), Amount: decimal.RequireFromString("100.50"), Status: "success"}
+	wrongAmount := models.Transaction{ID: 2, ReferenceID: reference("ORD-2"), Amount: decimal.NewFromInt(20), Status: "success"}
+	wrongBoth := models.Transaction{ID: 3, ReferenceID: reference("ORD-3"), Amount: decimal.NewFromInt(30), Status: "pending"}
+	mockRepo.On("GetByReferenceIDs", []string{"ORD-1", "ORD-2", "ORD-3", "ORD-4"}).Return([]models.Transaction{wrongBoth, matched, wrongAmount}, nil)
+
+	req := models.ReconciliationRequest{Items: []models.ReconciliationItem{
+		{Reference: "ORD-1", Amount: decimal.RequireFromString("100.5"), Status: "success"},
+		{Reference: "ORD-2", Amount: decimal.NewFromInt(25), Status: "success"},
+		{Reference: "ORD-3", Amount: decimal.NewFromInt(35), Status: "failed"},
+		{Reference: "ORD-4", Amount: decimal.NewFromInt(40), Status: "success"},
+	}}
+	result, err := service.Compare(context.Background(), req, models.ReconciliationFilters{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.Compared)
+	assert.Equal(t, 1, result.Matched)
+	assert.Equal(t, 3, result.Mismatched)
+	assert.Equal(t, []models.ReconciliationMismatch{
+		{Index: 1, Reference: "ORD-2", Kinds: []string{models.MismatchAmount}, Provider: req.Items[1], Transaction: &wrongAmount},
+		{Index: 2, Reference: "ORD-3", Kinds: []string{models.MismatchAmount, models.MismatchStatus}, Provider: req.Items[2], Transaction: &wrongBoth},
+		{Index: 3, Reference: "ORD-4", Kinds: []string{models.MismatchMissing}, Provider: req.Items[3]},
+	}, result.Mismatches)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReconciliationService_ComparePagesMismatches(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewReconciliationService(mockRepo)
+
+	req := models.ReconciliationRequest{}
+	references := make([]string, 5)
+	for i := range references {
+		references[i] = string(rune('A' + i))
+		req.Items = append(req.Items, models.ReconciliationItem{Reference: references[i], Amount: decimal.NewFromInt(1), Status: "success"})
+	}
+	mockRepo.On("GetByReferenceIDs", references).Return([]models.Transaction{}, nil)
+
+	result, err := service.Compare(context.Background(), req, models.ReconciliationFilters{Limit: 2, Offset: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, result.Mismatched)
+	assert.Len(t, result.Mismatches, 2)
+	assert.Equal(t, []string{"C", "D"}, []string{result.Mismatches[0].Reference, result.Mismatches[1].Reference})
+}
+
+func TestReconciliationService_CompareError(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewReconciliationService(mockRepo)
+
+	mockRepo.On("GetByReferenceIDs", []string{"ORD-1"}).Return([]models.Transaction{}, errors.New("database error"))
+
+	result, err := service.Compare(context.Background(), models.ReconciliationRequest{Items: []models.ReconciliationItem{{Reference: "ORD-1"}}}, models.ReconciliationFilters{})
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "failed to get transactions: database error")
+}
@@ -0,0 +1,131 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestTransactionService_CreateTransactionReferenceID(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	mockRepo.On("GetByReferenceID", "ORD-1").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Create", mock.MatchedBy(func(tx *models.Transaction) bool {
+		return tx.ReferenceID != nil && *tx.ReferenceID == "ORD-1"
+	})).Return(nil)
+
+	result, err := service.CreateTransaction(context.Background(), models.CreateTransactionRequest{UserID: 1, Amount: decimal.NewFromInt(10), ReferenceID: "ORD-1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ORD-1", *result.ReferenceID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_CreateTransactionDuplicateReferenceID(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	mockRepo.On("GetByReferenceID", "ORD-1").Return(&models.Transaction{ID: 7}, nil)
+
+	_, err := service.CreateTransaction(context.Background(), models.CreateTransactionRequest{UserID: 1, Amount: decimal.NewFromInt(10), ReferenceID: "ORD-1"})
+
+	assert.ErrorIs(t, err, services.ErrDuplicateReference)
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
+func TestTransactionService_CreateTransactionReferenceIDRace(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	mockRepo.On("GetByReferenceID", "ORD-1").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Create", mock.AnythingOfType("*models.Transaction")).Return(gorm.ErrDuplicatedKey)
+
+	_, err := service.CreateTransaction(context.Background(), models.CreateTransactionRequest{UserID: 1, Amount: decimal.NewFromInt(10), ReferenceID: "ORD-1"})
+
+	assert.ErrorIs(t, err, services.ErrDuplicateReference)
+}
+
+func TestTransactionService_CreateTransactionsDuplicateReferenceIDs(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	mockRepo.On("GetByReferenceID", "ORD-1").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("GetByReferenceID", "ORD-2").Return(&models.Transaction{ID: 7}, nil)
+	mockRepo.On("CreateBatch", mock.MatchedBy(func(transactions []models.Transaction) bool {
+		return len(transactions) == 2
+	})).Return(nil)
+
+	result, err := service.CreateTransactions(context.Background(), []models.CreateTransactionRequest{
+		{UserID: 1, Amount: decimal.NewFromInt(10), ReferenceID: "ORD-1"},
+		{UserID: 1, Amount: decimal.NewFromInt(10), ReferenceID: "ORD-1"},
+		{UserID: 1, Amount: decimal.NewFromInt(10), ReferenceID: "ORD-2"},
+		{UserID: 1, Amount: decimal.NewFromInt(10)},
+		// Repeating an earlier item's reference ID for another amount
+		{UserID: 1, Amount: decimal.NewFromInt(11), ReferenceID: "ORD-1"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 1, result.Deduplicated)
+	assert.Equal(t, 2, result.Failed)
+	// A repeat of an earlier item is deduplicated against its transaction
+	assert.Equal(t, models.BulkItemDeduplicated, result.Results[1].Status)
+	assert.Same(t, result.Results[0].Transaction, result.Results[1].Transaction)
+	assert.Equal(t, "duplicate reference ID: ORD-2", result.Results[2].Error)
+	assert.Equal(t, "duplicate reference ID: ORD-1", result.Results[4].Error)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_CreateTransactionsReplay(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	stored := &models.Transaction{ID: 7, UserID: 1, Amount: decimal.NewFromInt(10), Currency: "USD", Type: models.TransactionTypePayment, Direction: models.TransactionDirectionDebit, Status: "success"}
+	mockRepo.On("GetByReferenceID", "ORD-1").Return(stored, nil)
+	mockRepo.On("GetByReferenceID", "ORD-2").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("CreateBatch", mock.MatchedBy(func(transactions []models.Transaction) bool {
+		return len(transactions) == 1 && *transactions[0].ReferenceID == "ORD-2"
+	})).Return(nil)
+
+	// Replaying a batch whose first item was stored before
+	result, err := service.CreateTransactions(context.Background(), []models.CreateTransactionRequest{
+		{UserID: 1, Amount: decimal.RequireFromString("10.00"), ReferenceID: "ORD-1"},
+		{UserID: 1, Amount: decimal.NewFromInt(20), ReferenceID: "ORD-2"},
+		// The same reference ID for another amount is not a replay
+		{UserID: 1, Amount: decimal.NewFromInt(11), ReferenceID: "ORD-1"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Deduplicated)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, models.BulkItemDeduplicated, result.Results[0].Status)
+	assert.Equal(t, stored, result.Results[0].Transaction)
+	assert.Equal(t, models.BulkItemCreated, result.Results[1].Status)
+	assert.Equal(t, models.BulkItemFailed, result.Results[2].Status)
+	assert.Equal(t, "duplicate reference ID: ORD-1", result.Results[2].Error)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_GetTransactionByReference(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	mockRepo.On("GetByReferenceID", "ORD-1").Return(&models.Transaction{ID: 7}, nil)
+	mockRepo.On("GetByReferenceID", "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	transaction, err := service.GetTransactionByReference(context.Background(), "ORD-1")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), transaction.ID)
+
+	_, err = service.GetTransactionByReference(context.Background(), "missing")
+	assert.EqualError(t, err, "transaction not found")
+}
@@ -0,0 +1,71 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestTransactionService_RefundTransaction(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	original := &models.Transaction{ID: 7, UserID: 1, Amount: decimal.NewFromInt(100), Currency: "USD", Type: models.TransactionTypePayment, Direction: models.TransactionDirectionDebit, Status: "success"}
+	mockRepo.On("GetByIDFromPrimary", uint(7)).Return(original, nil)
+	mockRepo.On("Refund", uint(7), mock.AnythingOfType("*models.Transaction")).Return(nil)
+
+	refund, err := service.RefundTransaction(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, models.TransactionTypeRefund, refund.Type)
+	assert.Equal(t, models.TransactionDirectionCredit, refund.Direction)
+	assert.Equal(t, "success", refund.Status)
+	assert.True(t, original.Amount.Equal(refund.Amount))
+	require.NotNil(t, refund.ParentID)
+	assert.Equal(t, uint(7), *refund.ParentID)
+	assert.NotNil(t, refund.UUID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_RefundTransactionErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		transaction *models.Transaction
+		getErr      error
+		refundErr   error
+		expected    error
+		message     string
+	}{
+		{name: "not found", getErr: gorm.ErrRecordNotFound, message: "transaction not found"},
+		{name: "pending", transaction: &models.Transaction{ID: 7, Status: "pending"}, expected: services.ErrNotRefundable},
+		{name: "already refunded", transaction: &models.Transaction{ID: 7, Status: "refunded"}, expected: services.ErrNotRefundable},
+		{name: "refund", transaction: &models.Transaction{ID: 7, Type: models.TransactionTypeRefund, Status: "success"}, expected: services.ErrNotRefundable},
+		{name: "concurrent change", transaction: &models.Transaction{ID: 7, Status: "success"}, refundErr: gorm.ErrRecordNotFound, expected: services.ErrNotRefundable},
+		{name: "database error", transaction: &models.Transaction{ID: 7, Status: "success"}, refundErr: errors.New("connection lost"), message: "failed to refund transaction"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTransactionRepository)
+			service := services.NewTransactionService(mockRepo)
+
+			mockRepo.On("GetByIDFromPrimary", uint(7)).Return(tt.transaction, tt.getErr)
+			mockRepo.On("Refund", uint(7), mock.AnythingOfType("*models.Transaction")).Return(tt.refundErr)
+
+			_, err := service.RefundTransaction(context.Background(), 7)
+			require.Error(t, err)
+			if tt.expected != nil {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
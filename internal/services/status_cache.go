@@ -0,0 +1,88 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"interview/internal/models"
+)
+
+// DefaultStatusCacheTTL is how long a transaction status is served from memory by default
+const DefaultStatusCacheTTL = 5 * time.Second
+
+// StatusCache keeps recently read transaction statuses for
+// GetTransactionStatus. The in-memory cache only sees invalidations made by
+// its own instance; the Redis cache is shared by every instance.
+type StatusCache interface {
+	// Get returns the cached status of a transaction if it has not expired
+	Get(id uint) (models.TransactionStatus, bool)
+	// Set caches the status of a transaction
+	Set(id uint, status models.TransactionStatus)
+	// Invalidate removes a transaction from the cache
+	Invalidate(id uint)
+}
+
+// statusCache keeps recently read transaction statuses in memory. Entries are
+// invalidated when this instance changes a transaction and expire after ttl,
+// which bounds staleness for changes made by other instances.
+type statusCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[uint]statusCacheEntry
+	now     func() time.Time
+}
+
+// statusCacheEntry is a cached status and when it expires
+type statusCacheEntry struct {
+	status    models.TransactionStatus
+	expiresAt time.Time
+}
+
+// newStatusCache creates a status cache; a non-positive ttl disables caching
+func newStatusCache(ttl time.Duration) *statusCache {
+	return &statusCache{
+		ttl:     ttl,
+		entries: make(map[uint]statusCacheEntry),
+		now:     time.Now,
+	}
+}
+
+// Get returns the cached status of a transaction if it has not expired
+func (c *statusCache) Get(id uint) (models.TransactionStatus, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[id]
+	if !ok || !c.now().Before(entry.expiresAt) {
+		return models.TransactionStatus{}, false
+	}
+	return entry.status, true
+}
+
+// Set caches the status of a transaction
+func (c *statusCache) Set(id uint, status models.TransactionStatus) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Drop expired entries now and then so the cache doesn't grow without bound
+	now := c.now()
+	if len(c.entries) >= 10000 {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, key)
+			}
+		}
+	}
+	c.entries[id] = statusCacheEntry{status: status, expiresAt: now.Add(c.ttl)}
+}
+
+// Invalidate removes a transaction from the cache
+func (c *statusCache) Invalidate(id uint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, id)
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"interview/internal/models"
+)
+
+// redisStatusCacheTimeout bounds each cache call so a slow Redis falls back
+// to the database instead of stalling status polls
+const redisStatusCacheTimeout = 100 * time.Millisecond
+
+// redisStatusCache keeps transaction statuses in Redis, so an invalidation by
+// any instance is seen by all of them. Redis errors are treated as misses.
+type redisStatusCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisStatusCache creates a status cache shared through Redis; a
+// non-positive ttl disables caching
+func NewRedisStatusCache(client *redis.Client, ttl time.Duration) StatusCache {
+	return &redisStatusCache{client: client, ttl: ttl}
+}
+
+// key returns the Redis key of a transaction's cached status
+func (c *redisStatusCache) key(id uint) string {
+	return fmt.Sprintf("trxgo:transaction-status:%d", id)
+}
+
+// Get returns the cached status of a transaction if it has not expired
+func (c *redisStatusCache) Get(id uint) (models.TransactionStatus, bool) {
+	if c.ttl <= 0 {
+		return models.TransactionStatus{}, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisStatusCacheTimeout)
+	defer cancel()
+
+	data, err := c.client.Get(ctx, c.key(id)).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			logrus.WithError(err).Warn("Failed to read status cache")
+		}
+		return models.TransactionStatus{}, false
+	}
+
+	var status models.TransactionStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return models.TransactionStatus{}, false
+	}
+	return status, true
+}
+
+// Set caches the status of a transaction
+func (c *redisStatusCache) Set(id uint, status models.TransactionStatus) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(status)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisStatusCacheTimeout)
+	defer cancel()
+
+	if err := c.client.Set(ctx, c.key(id), data, c.ttl).Err(); err != nil {
+		logrus.WithError(err).Warn("Failed to write status cache")
+	}
+}
+
+// Invalidate removes a transaction from the cache
+func (c *redisStatusCache) Invalidate(id uint) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisStatusCacheTimeout)
+	defer cancel()
+
+	if err := c.client.Del(ctx, c.key(id)).Err(); err != nil {
+		logrus.WithError(err).WithField("transaction_id", id).Warn("Failed to invalidate status cache")
+	}
+}
@@ -0,0 +1,80 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRedisClient(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return server, client
+}
+
+func TestRedisStatusCache(t *testing.T) {
+	server, client := newRedisClient(t)
+	cache := services.NewRedisStatusCache(client, time.Minute)
+
+	_, ok := cache.Get(1)
+	assert.False(t, ok)
+
+	status := models.TransactionStatus{Status: "pending", UpdatedAt: time.Date(2025, 6, 28, 10, 0, 0, 0, time.UTC)}
+	cache.Set(1, status)
+
+	cached, ok := cache.Get(1)
+	assert.True(t, ok)
+	assert.True(t, status.UpdatedAt.Equal(cached.UpdatedAt))
+	assert.Equal(t, "pending", cached.Status)
+
+	server.FastForward(time.Minute)
+	_, ok = cache.Get(1)
+	assert.False(t, ok, "entries expire after the ttl")
+
+	cache.Set(1, status)
+	cache.Invalidate(1)
+	_, ok = cache.Get(1)
+	assert.False(t, ok)
+}
+
+func TestRedisStatusCache_ErrorsAreMisses(t *testing.T) {
+	server, client := newRedisClient(t)
+	cache := services.NewRedisStatusCache(client, time.Minute)
+	server.Close()
+
+	cache.Set(1, models.TransactionStatus{Status: "pending"})
+	cache.Invalidate(1)
+	_, ok := cache.Get(1)
+	assert.False(t, ok)
+}
+
+func TestTransactionService_RedisStatusCacheSharedInvalidation(t *testing.T) {
+	_, client := newRedisClient(t)
+	mockRepo := new(MockTransactionRepository)
+	reader := services.NewTransactionService(mockRepo, services.WithStatusCache(services.NewRedisStatusCache(client, time.Minute)))
+	writer := services.NewTransactionService(mockRepo, services.WithStatusCache(services.NewRedisStatusCache(client, time.Minute)))
+
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, Status: "pending"}, nil).Twice()
+	mockRepo.On("UpdateVersion", uint(1), uint(0), map[string]interface{}{"status": "success"}).Return(nil)
+
+	status, err := reader.GetTransactionStatus(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "pending", status.Status)
+
+	// An update through another instance invalidates the shared entry
+	assert.NoError(t, writer.UpdateTransactionStatus(context.Background(), 1, "success"))
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, Status: "success"}, nil).Once()
+
+	status, err = reader.GetTransactionStatus(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "success", status.Status)
+	mockRepo.AssertExpectations(t)
+}
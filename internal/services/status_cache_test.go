@@ -0,0 +1,89 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestTransactionService_GetTransactionStatusCached(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo, services.WithStatusCacheTTL(time.Minute))
+
+	updatedAt := time.Date(2025, 6, 28, 10, 0, 0, 0, time.UTC)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, Status: "pending", UpdatedAt: updatedAt}, nil).Once()
+
+	for i := 0; i < 3; i++ {
+		status, err := service.GetTransactionStatus(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.TransactionStatus{Status: "pending", UpdatedAt: updatedAt}, status)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_GetTransactionStatusInvalidatedOnUpdate(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo, services.WithStatusCacheTTL(time.Minute))
+
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, Status: "pending"}, nil).Twice()
+	mockRepo.On("UpdateVersion", uint(1), uint(0), map[string]interface{}{"status": "success"}).Return(nil)
+
+	status, err := service.GetTransactionStatus(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "pending", status.Status)
+
+	assert.NoError(t, service.UpdateTransactionStatus(context.Background(), 1, "success"))
+
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, Status: "success"}, nil).Once()
+	status, err = service.GetTransactionStatus(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "success", status.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_GetTransactionStatusCacheDisabled(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo, services.WithStatusCacheTTL(0))
+
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, Status: "pending"}, nil).Twice()
+
+	_, err := service.GetTransactionStatus(context.Background(), 1)
+	assert.NoError(t, err)
+	_, err = service.GetTransactionStatus(context.Background(), 1)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_GetTransactionStatusNotFound(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+
+	status, err := service.GetTransactionStatus(context.Background(), 1)
+	assert.EqualError(t, err, "transaction not found")
+	assert.Nil(t, status)
+}
+
+func TestTransactionService_GetTransactionStatusFillsFromPrimary(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo, services.WithStatusCacheTTL(time.Minute))
+
+	// The replica still has the status from before the update
+	mockRepo.On("GetByID", uint(1)).Return(&models.Transaction{ID: 1, Status: "pending"}, nil).Maybe()
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, Status: "success"}, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		status, err := service.GetTransactionStatus(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "success", status.Status)
+	}
+	mockRepo.AssertNotCalled(t, "GetByID", uint(1))
+	mockRepo.AssertExpectations(t)
+}
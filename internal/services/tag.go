@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// TagService interface defines tag service methods
+type TagService interface {
+	AddTags(ctx context.Context, transactionID uint, req models.AddTagsRequest) (*models.Transaction, error)
+	RemoveTag(ctx context.Context, transactionID uint, name string) (*models.Transaction, error)
+	ResolveTransactionID(ctx context.Context, uuid string) (uint, error)
+}
+
+// tagService implements TagService interface
+type tagService struct {
+	tags         repositories.TagRepository
+	transactions repositories.TransactionRepository
+}
+
+// NewTagService creates a new tag service
+func NewTagService(tags repositories.TagRepository, transactions repositories.TransactionRepository) TagService {
+	return &tagService{tags: tags, transactions: transactions}
+}
+
+// NormalizeTag returns the stored form of a tag name: trimmed and lower case
+func NormalizeTag(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// ResolveTransactionID gets the ID of the transaction with the given public UUID
+func (s *tagService) ResolveTransactionID(ctx context.Context, uuid string) (uint, error) {
+	return resolveTransactionID(ctx, s.transactions, uuid)
+}
+
+// AddTags attaches tags to a transaction and returns the tagged transaction
+func (s *tagService) AddTags(ctx context.Context, transactionID uint, req models.AddTagsRequest) (*models.Transaction, error) {
+	var names []string
+	seen := make(map[string]bool, len(req.Tags))
+	for _, tag := range req.Tags {
+		name := NormalizeTag(tag)
+		if name == "" {
+			return nil, errors.New("invalid tag")
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	if err := s.checkExists(ctx, transactionID); err != nil {
+		return nil, err
+	}
+
+	if err := s.tags.AddTags(ctx, transactionID, names); err != nil {
+		return nil, fmt.Errorf("failed to add tags: %v", err)
+	}
+
+	return s.reload(ctx, transactionID)
+}
+
+// RemoveTag detaches a tag from a transaction and returns the transaction
+func (s *tagService) RemoveTag(ctx context.Context, transactionID uint, name string) (*models.Transaction, error) {
+	if err := s.checkExists(ctx, transactionID); err != nil {
+		return nil, err
+	}
+
+	removed, err := s.tags.RemoveTag(ctx, transactionID, NormalizeTag(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove tag: %v", err)
+	}
+	if !removed {
+		return nil, errors.New("tag not found")
+	}
+
+	return s.reload(ctx, transactionID)
+}
+
+// checkExists returns "transaction not found" when the transaction does not exist
+func (s *tagService) checkExists(ctx context.Context, transactionID uint) error {
+	_, err := s.transactions.GetByIDFromPrimary(ctx, transactionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("transaction not found")
+		}
+		return fmt.Errorf("failed to get transaction: %v", err)
+	}
+	return nil
+}
+
+// reload gets the transaction with its current tags
+func (s *tagService) reload(ctx context.Context, transactionID uint) (*models.Transaction, error) {
+	transaction, err := s.transactions.GetByIDFromPrimary(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %v", err)
+	}
+	return transaction, nil
+}
@@ -0,0 +1,131 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockTagRepository is a mock implementation of TagRepository
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) AddTags(ctx context.Context, transactionID uint, names []string) error {
+	args := m.Called(transactionID, names)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) RemoveTag(ctx context.Context, transactionID uint, name string) (bool, error) {
+	args := m.Called(transactionID, name)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTagRepository) GetSummaries(ctx context.Context, limit int) ([]models.TagSummary, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]models.TagSummary), args.Error(1)
+}
+
+func TestTagService_AddTagsNormalizesNames(t *testing.T) {
+	mockTags := new(MockTagRepository)
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTagService(mockTags, mockRepo)
+
+	tagged := &models.Transaction{ID: 1, Tags: []models.Tag{{Name: "refund"}, {Name: "vip"}}}
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(tagged, nil)
+	mockTags.On("AddTags", uint(1), []string{"refund", "vip"}).Return(nil)
+
+	transaction, err := service.AddTags(context.Background(), 1, models.AddTagsRequest{Tags: []string{" Refund", "VIP", "refund "}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, tagged, transaction)
+	mockTags.AssertExpectations(t)
+}
+
+func TestTagService_AddTagsErrors(t *testing.T) {
+	mockTags := new(MockTagRepository)
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTagService(mockTags, mockRepo)
+
+	_, err := service.AddTags(context.Background(), 1, models.AddTagsRequest{Tags: []string{"  "}})
+	assert.EqualError(t, err, "invalid tag")
+
+	mockRepo.On("GetByIDFromPrimary", uint(999)).Return((*models.Transaction)(nil), gorm.ErrRecordNotFound)
+	_, err = service.AddTags(context.Background(), 999, models.AddTagsRequest{Tags: []string{"refund"}})
+	assert.EqualError(t, err, "transaction not found")
+
+	mockTags.AssertNotCalled(t, "AddTags")
+}
+
+func TestTagService_RemoveTag(t *testing.T) {
+	mockTags := new(MockTagRepository)
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTagService(mockTags, mockRepo)
+
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1}, nil)
+	mockTags.On("RemoveTag", uint(1), "vip").Return(true, nil).Once()
+	mockTags.On("RemoveTag", uint(1), "vip").Return(false, nil).Once()
+
+	transaction, err := service.RemoveTag(context.Background(), 1, "VIP")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), transaction.ID)
+
+	_, err = service.RemoveTag(context.Background(), 1, "vip")
+	assert.EqualError(t, err, "tag not found")
+	mockTags.AssertExpectations(t)
+}
+
+func TestDashboardService_GetSummaryWithTags(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	mockTags := new(MockTagRepository)
+	service := services.NewDashboardService(mockRepo, services.WithTagSummaries(mockTags))
+
+	tags := []models.TagSummary{{Name: "refund", Count: 2, TotalAmount: decimal.NewFromInt(35)}}
+	mockRepo.On("GetTodaySuccessful").Return(0, decimal.Zero, nil)
+	mockRepo.On("GetAveragePerUser").Return(decimal.Zero, nil)
+	mockRepo.On("GetLatest", 10).Return([]models.Transaction{}, nil)
+	mockRepo.On("GetStatusCounts").Return(models.StatusCounts{}, nil)
+	mockRepo.On("GetDirectionTotals").Return(models.DirectionTotals{}, nil)
+	mockRepo.On("GetCurrencyTotals").Return([]models.CurrencyTotal{}, nil)
+	mockRepo.On("GetAmountDistribution").Return(models.AmountDistribution{}, nil)
+	mockTags.On("GetSummaries", services.DashboardTagLimit).Return(tags, nil)
+
+	summary, err := service.GetSummary(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, tags, summary.Tags)
+}
+
+func TestDashboardService_GetPartialSummaryTagsError(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	mockTags := new(MockTagRepository)
+	service := services.NewDashboardService(mockRepo, services.WithTagSummaries(mockTags))
+
+	mockRepo.On("GetTodaySuccessful").Return(0, decimal.Zero, errors.New("db down"))
+	mockRepo.On("GetAveragePerUser").Return(decimal.Zero, errors.New("db down"))
+	mockRepo.On("GetLatest", 10).Return([]models.Transaction{}, errors.New("db down"))
+	mockRepo.On("GetStatusCounts").Return(models.StatusCounts{}, errors.New("db down"))
+	mockRepo.On("GetDirectionTotals").Return(models.DirectionTotals{}, errors.New("db down"))
+	mockRepo.On("GetCurrencyTotals").Return([]models.CurrencyTotal{}, errors.New("db down"))
+	mockRepo.On("GetAmountDistribution").Return(models.AmountDistribution{}, errors.New("db down"))
+	mockTags.On("GetSummaries", services.DashboardTagLimit).Return([]models.TagSummary{}, errors.New("db down")).Once()
+
+	_, err := service.GetPartialSummary(context.Background())
+	assert.EqualError(t, err, "failed to get any dashboard summary section")
+
+	tags := []models.TagSummary{{Name: "refund", Count: 1, TotalAmount: decimal.NewFromInt(10)}}
+	mockTags.On("GetSummaries", services.DashboardTagLimit).Return(tags, nil).Once()
+
+	summary, err := service.GetPartialSummary(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, tags, summary.Tags)
+	assert.Len(t, summary.Errors, 7)
+}
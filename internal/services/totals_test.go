@@ -0,0 +1,166 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+	"interview/internal/services"
+	"interview/pkg/money"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// totalsUsers is how many users the reconciliation property spreads
+// transactions over
+const totalsUsers = 3
+
+// totalsCurrencies are the currencies of the random transactions, each
+// user's budget being in the one at its index
+var totalsCurrencies = []string{"USD", "EUR", "JPY"}
+
+// seedTotals stores random transactions created now for a fresh database. The
+// amounts have the decimal places of their currency, and SQLite sums them as
+// floats, so unrounded totals would pick up binary rounding errors.
+func seedTotals(t *testing.T, seed int64) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s_%d?mode=memory&cache=shared", t.Name(), seed)), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.Transaction{}, &models.Budget{}))
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	rng := rand.New(rand.NewSource(seed))
+	statuses := []string{"success", "success", "success", "pending", "failed"}
+	for userID := uint(1); userID <= totalsUsers; userID++ {
+		require.NoError(t, db.Create(&models.User{ID: userID, Name: fmt.Sprintf("User %d", userID), Email: fmt.Sprintf("user%d@example.com", userID)}).Error)
+		currency := totalsCurrencies[int(userID-1)%len(totalsCurrencies)]
+		require.NoError(t, db.Create(&models.Budget{UserID: userID, MonthlyCap: decimal.NewFromInt(1000000), Currency: currency}).Error)
+	}
+	scales := services.DefaultAmountPolicy().CurrencyScales
+	for i := 0; i < 20+rng.Intn(60); i++ {
+		currency := totalsCurrencies[rng.Intn(len(totalsCurrencies))]
+		transaction := models.Transaction{
+			UserID:    uint(1 + rng.Intn(totalsUsers)),
+			Amount:    decimal.New(1+rng.Int63n(100000), -int32(scales[currency])),
+			Currency:  currency,
+			Type:      models.TransactionTypePayment,
+			Direction: models.TransactionDirectionDebit,
+			Status:    statuses[rng.Intn(len(statuses))],
+		}
+		if rng.Intn(4) == 0 {
+			transaction.Direction = models.TransactionDirectionCredit
+		}
+		if rng.Intn(10) == 0 {
+			transaction.Type = models.TransactionTypeAdjustment
+			transaction.Amount = transaction.Amount.Neg()
+		}
+		require.NoError(t, db.Create(&transaction).Error)
+	}
+	return db
+}
+
+// exportedTotal sums the exported transactions matching filters that keep
+func exportedTotal(t *testing.T, service services.TransactionService, filters models.TransactionFilters, keep func(*models.Transaction) bool) decimal.Decimal {
+	var amounts []decimal.Decimal
+	require.NoError(t, service.ExportTransactions(context.Background(), filters, func(transaction *models.Transaction) error {
+		if keep(transaction) {
+			amounts = append(amounts, transaction.Amount)
+		}
+		return nil
+	}))
+	return money.Sum(amounts...)
+}
+
+// TestTotalsReconcile checks that the dashboard totals and every user's budget
+// consumption equal the sums of the exported rows they are computed from,
+// whichever rounding each currency has
+func TestTotalsReconcile(t *testing.T) {
+	all := func(*models.Transaction) bool { return true }
+	notFailed := func(transaction *models.Transaction) bool { return transaction.Status != "failed" }
+	inCurrency := func(currency string, keep func(*models.Transaction) bool) func(*models.Transaction) bool {
+		return func(transaction *models.Transaction) bool {
+			return transaction.Currency == currency && keep(transaction)
+		}
+	}
+
+	roundings := map[string]map[string]money.Rounding{
+		"half_up":   nil,
+		"half_even": {"USD": money.HalfEven, "EUR": money.HalfEven, "JPY": money.HalfEven},
+		"mixed":     {"EUR": money.HalfEven},
+	}
+	for name, currencyRoundings := range roundings {
+		t.Run(name, func(t *testing.T) {
+			policy := services.DefaultAmountPolicy()
+			policy.CurrencyRoundings = currencyRoundings
+			require.NoError(t, policy.Check())
+
+			reconciles := func(seed int64) bool {
+				db := seedTotals(t, seed)
+				transactions := services.NewTransactionService(repositories.NewTransactionRepository(db))
+				dashboard := services.NewDashboardService(repositories.NewTransactionRepository(db), services.WithSummaryAmountPolicy(policy))
+				budgets := services.NewBudgetService(repositories.NewBudgetRepository(db), services.WithBudgetAmountPolicy(policy))
+
+				summary, err := dashboard.GetSummary(context.Background())
+				require.NoError(t, err)
+				if !summary.TodaySuccessfulAmount.Equal(exportedTotal(t, transactions, models.TransactionFilters{Status: "success"}, all)) {
+					t.Logf("seed %d: today's successful amount %s does not match the export", seed, summary.TodaySuccessfulAmount)
+					return false
+				}
+				debits := exportedTotal(t, transactions, models.TransactionFilters{Status: "success", Direction: models.TransactionDirectionDebit}, all)
+				credits := exportedTotal(t, transactions, models.TransactionFilters{Status: "success", Direction: models.TransactionDirectionCredit}, all)
+				if !summary.DirectionTotals.Debit.Amount.Equal(debits) || !summary.DirectionTotals.Credit.Amount.Equal(credits) {
+					t.Logf("seed %d: direction totals %s/%s do not match the export %s/%s", seed,
+						summary.DirectionTotals.Debit.Amount, summary.DirectionTotals.Credit.Amount, debits, credits)
+					return false
+				}
+				if !summary.DirectionTotals.Debit.Amount.Add(summary.DirectionTotals.Credit.Amount).Equal(summary.TodaySuccessfulAmount) {
+					t.Logf("seed %d: direction totals do not add up to today's successful amount", seed)
+					return false
+				}
+
+				count, amounts := 0, []decimal.Decimal{}
+				for _, total := range summary.CurrencyTotals {
+					if exported := exportedTotal(t, transactions, models.TransactionFilters{Status: "success"}, inCurrency(total.Currency, all)); !total.Amount.Equal(exported) {
+						t.Logf("seed %d: %s total %s, export sums to %s", seed, total.Currency, total.Amount, exported)
+						return false
+					}
+					if !money.HasScale(total.Amount, int32(policy.CurrencyScales[total.Currency])) {
+						t.Logf("seed %d: %s total %s has more decimal places than the currency", seed, total.Currency, total.Amount)
+						return false
+					}
+					count += total.Count
+					amounts = append(amounts, total.Amount)
+				}
+				if count != summary.TodaySuccessfulTransactions || !money.Sum(amounts...).Equal(summary.TodaySuccessfulAmount) {
+					t.Logf("seed %d: currency totals do not add up to today's successful transactions", seed)
+					return false
+				}
+
+				for userID := uint(1); userID <= totalsUsers; userID++ {
+					status, err := budgets.GetBudgetStatus(context.Background(), userID)
+					require.NoError(t, err)
+					if exported := exportedTotal(t, transactions, models.TransactionFilters{UserID: userID}, inCurrency(status.Budget.Currency, notFailed)); !status.Consumed.Equal(exported) {
+						t.Logf("seed %d: user %d consumed %s %s, export sums to %s", seed, userID, status.Consumed, status.Budget.Currency, exported)
+						return false
+					}
+				}
+				return true
+			}
+
+			require.NoError(t, quick.Check(reconciles, &quick.Config{MaxCount: 25}))
+		})
+	}
+}
@@ -1,53 +1,441 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"time"
 
+	"interview/internal/events"
 	"interview/internal/models"
 	"interview/internal/repositories"
+	"interview/pkg/utils"
 
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
 // TransactionService interface defines transaction service methods
 type TransactionService interface {
-	CreateTransaction(req models.CreateTransactionRequest) (*models.Transaction, error)
-	GetTransaction(id uint) (*models.Transaction, error)
-	GetTransactions(filters models.TransactionFilters) ([]models.Transaction, error)
-	UpdateTransactionStatus(id uint, status string) error
-	DeleteTransaction(id uint) error
+	CreateTransaction(ctx context.Context, req models.CreateTransactionRequest) (*models.Transaction, error)
+	CreateTransactions(ctx context.Context, reqs []models.CreateTransactionRequest) (*models.BulkCreateResult, error)
+	GetTransaction(ctx context.Context, id uint) (*models.Transaction, error)
+	GetTransactionByReference(ctx context.Context, referenceID string) (*models.Transaction, error)
+	ResolveTransactionID(ctx context.Context, uuid string) (uint, error)
+	GetTransactionStatus(ctx context.Context, id uint) (*models.TransactionStatus, error)
+	GetTransactions(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, error)
+	GetTransactionsWithCount(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, int64, error)
+	ExportTransactions(ctx context.Context, filters models.TransactionFilters, fn func(*models.Transaction) error) error
+	LookupTransactions(ctx context.Context, ids []uint) (*models.TransactionLookupResult, error)
+	UpdateTransactionStatus(ctx context.Context, id uint, status string) error
+	UpdateTransaction(ctx context.Context, id uint, req models.UpdateTransactionRequest) error
+	PatchTransaction(ctx context.Context, id uint, req models.PatchTransactionRequest) (*models.Transaction, error)
+	RefundTransaction(ctx context.Context, id uint) (*models.Transaction, error)
+	DeleteTransaction(ctx context.Context, id uint) error
+	WaitForStatusChange(ctx context.Context, id uint, lastStatus string, timeout time.Duration) (*models.TransactionWaitResult, error)
+}
+
+// ErrDuplicateReference is returned when a transaction with the same reference ID already exists
+var ErrDuplicateReference = errors.New("duplicate reference ID")
+
+// ErrInvalidTransition is returned when a status change is not allowed by statusTransitions
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// ErrVersionConflict is returned when a transaction changed since the version
+// an update was based on
+var ErrVersionConflict = errors.New("transaction version conflict")
+
+// ErrVersionRequired is returned when a metadata patch does not name the
+// version it was based on
+var ErrVersionRequired = errors.New("expected_version is required to patch metadata")
+
+// ErrReasonWithoutChange is returned when an update gives a reason but does
+// not change the status
+var ErrReasonWithoutChange = errors.New("reason given without a status change")
+
+// ErrTooManyMetadataKeys is returned when a metadata patch leaves more than
+// maxMetadataKeys keys
+var ErrTooManyMetadataKeys = errors.New("too many metadata keys")
+
+// maxMetadataKeys is how many metadata keys a transaction may have
+const maxMetadataKeys = 20
+
+// ErrNotRefundable is returned when refunding a transaction that is not
+// successful or is itself a refund
+var ErrNotRefundable = errors.New("transaction cannot be refunded")
+
+// ErrNotDeletable is returned when deleting a refunded transaction or a
+// refund, which would leave the other half of the pair behind
+var ErrNotDeletable = errors.New("refunded transactions and refunds cannot be deleted")
+
+// statusTransitions lists the statuses each status may change to. Setting
+// the current status again is always allowed; success and failed are terminal.
+// Only RefundTransaction moves a transaction from success to refunded.
+var statusTransitions = map[string][]string{
+	"pending": {"success", "failed"},
+}
+
+// checkTransition rejects status changes not allowed by statusTransitions
+func checkTransition(from, to string) error {
+	if from == to {
+		return nil
+	}
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: cannot change status from %s to %s", ErrInvalidTransition, from, to)
 }
 
 // transactionService implements TransactionService interface
 type transactionService struct {
-	repo repositories.TransactionRepository
+	repo    repositories.TransactionRepository
+	amounts AmountPolicy
+	budgets BudgetService
+	users   UserService
+	events  events.Broker
+	status  StatusCache
+
+	beforeCreate      []BeforeCreateHook
+	afterStatusChange []AfterStatusChangeHook
+	beforeDelete      []BeforeDeleteHook
+}
+
+// TransactionServiceOption configures optional transaction service behavior
+type TransactionServiceOption func(*transactionService)
+
+// WithAmountPolicy sets the amount and currency policy used to validate new transactions
+func WithAmountPolicy(policy AmountPolicy) TransactionServiceOption {
+	return func(s *transactionService) {
+		s.amounts = policy
+	}
+}
+
+// WithBudgetService enforces monthly budgets when creating transactions
+func WithBudgetService(budgets BudgetService) TransactionServiceOption {
+	return func(s *transactionService) {
+		s.budgets = budgets
+	}
+}
+
+// WithUserService rejects transactions for users that do not exist
+func WithUserService(users UserService) TransactionServiceOption {
+	return func(s *transactionService) {
+		s.users = users
+	}
+}
+
+// WithEventBus publishes transaction events to a shared bus
+func WithEventBus(bus events.Broker) TransactionServiceOption {
+	return func(s *transactionService) {
+		s.events = bus
+	}
+}
+
+// WithStatusCacheTTL sets how long GetTransactionStatus serves a status from memory; zero disables the cache
+func WithStatusCacheTTL(ttl time.Duration) TransactionServiceOption {
+	return func(s *transactionService) {
+		s.status = newStatusCache(ttl)
+	}
+}
+
+// WithStatusCache sets the cache GetTransactionStatus serves statuses from
+func WithStatusCache(cache StatusCache) TransactionServiceOption {
+	return func(s *transactionService) {
+		s.status = cache
+	}
 }
 
 // NewTransactionService creates a new transaction service
-func NewTransactionService(repo repositories.TransactionRepository) TransactionService {
-	return &transactionService{repo: repo}
+func NewTransactionService(repo repositories.TransactionRepository, opts ...TransactionServiceOption) TransactionService {
+	s := &transactionService{repo: repo, amounts: DefaultAmountPolicy(), events: events.NewBus(), status: newStatusCache(DefaultStatusCacheTTL)}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // CreateTransaction creates a new transaction
-func (s *transactionService) CreateTransaction(req models.CreateTransactionRequest) (*models.Transaction, error) {
-	transaction := &models.Transaction{
-		UserID: req.UserID,
-		Amount: req.Amount,
-		Status: "pending",
+func (s *transactionService) CreateTransaction(ctx context.Context, req models.CreateTransactionRequest) (*models.Transaction, error) {
+	transaction, crossedThresholds, err := s.prepareTransaction(ctx, req, decimal.Zero)
+	if err != nil {
+		return nil, err
 	}
 
-	err := s.repo.Create(transaction)
+	err = s.repo.Create(ctx, transaction)
 	if err != nil {
+		// A concurrent create with the same reference ID won the race
+		if errors.Is(err, gorm.ErrDuplicatedKey) && transaction.ReferenceID != nil {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateReference, *transaction.ReferenceID)
+		}
 		return nil, fmt.Errorf("failed to create transaction: %v", err)
 	}
 
+	if s.budgets != nil {
+		s.budgets.EmitAlerts(ctx, req.UserID, crossedThresholds)
+	}
+
+	s.publishCreated(transaction)
+
 	return transaction, nil
 }
 
+// CreateTransactions creates the requests that pass the amount policy and
+// budget checks in one batch and reports the others as failed items. Each
+// request is checked against the user's budget including the accepted
+// requests before it. A request whose reference ID is stored already, for the
+// same user, amount, currency, type and direction, is reported as
+// deduplicated with the stored transaction, so a failed batch can be sent
+// again as a whole. A request repeating an earlier request's reference ID in
+// the same batch is deduplicated the same way against the earlier request's
+// transaction, and fails when it differs. An error is returned only when the
+// batch cannot be stored.
+func (s *transactionService) CreateTransactions(ctx context.Context, reqs []models.CreateTransactionRequest) (*models.BulkCreateResult, error) {
+	result := &models.BulkCreateResult{Results: make([]models.BulkCreateItemResult, len(reqs))}
+
+	var accepted []models.Transaction
+	var acceptedIndexes []int
+	// pending sums the accepted amounts per user and currency, as budgets do
+	pending := map[budgetKey]decimal.Decimal{}
+	crossed := map[uint][]int64{}
+	// references maps the reference IDs of accepted requests to their
+	// position in accepted, and repeats the results deduplicated against them
+	references := map[string]int{}
+	repeats := map[int]int{}
+	for i, req := range reqs {
+		result.Results[i].Index = i
+
+		if first, ok := references[req.ReferenceID]; ok && req.ReferenceID != "" {
+			if s.createdFrom(&accepted[first], req) {
+				result.Results[i].Status = models.BulkItemDeduplicated
+				repeats[i] = first
+				result.Deduplicated++
+				continue
+			}
+			result.Results[i].Status = models.BulkItemFailed
+			result.Results[i].Error = fmt.Errorf("%w: %s", ErrDuplicateReference, req.ReferenceID).Error()
+			result.Failed++
+			continue
+		}
+
+		key := budgetKey{req.UserID, s.amounts.Currency(req.Currency)}
+		transaction, thresholds, err := s.prepareTransaction(ctx, req, pending[key])
+		if errors.Is(err, ErrDuplicateReference) {
+			stored, replayErr := s.replayed(ctx, req)
+			if replayErr != nil {
+				return nil, replayErr
+			}
+			if stored != nil {
+				result.Results[i].Status = models.BulkItemDeduplicated
+				result.Results[i].Transaction = stored
+				result.Deduplicated++
+				continue
+			}
+		}
+		if err != nil {
+			if !errors.Is(err, ErrInvalidAmount) && !errors.Is(err, ErrBudgetExceeded) && !errors.Is(err, ErrBudgetCurrency) && !errors.Is(err, ErrDuplicateReference) && !errors.Is(err, ErrUserNotFound) && !errors.Is(err, ErrRejectedByHook) {
+				return nil, err
+			}
+			result.Results[i].Status = models.BulkItemFailed
+			result.Results[i].Error = err.Error()
+			result.Failed++
+			continue
+		}
+
+		if req.ReferenceID != "" {
+			references[req.ReferenceID] = len(accepted)
+		}
+		pending[key] = pending[key].Add(req.Amount)
+		crossed[req.UserID] = mergeThresholds(crossed[req.UserID], thresholds)
+		accepted = append(accepted, *transaction)
+		acceptedIndexes = append(acceptedIndexes, i)
+	}
+
+	if len(accepted) == 0 {
+		return result, nil
+	}
+
+	if err := s.repo.CreateBatch(ctx, accepted); err != nil {
+		return nil, fmt.Errorf("failed to create transactions: %v", err)
+	}
+
+	for i, index := range acceptedIndexes {
+		result.Results[index].Status = models.BulkItemCreated
+		result.Results[index].Transaction = &accepted[i]
+		s.publishCreated(&accepted[i])
+	}
+	for index, first := range repeats {
+		result.Results[index].Transaction = &accepted[first]
+	}
+	result.Created = len(accepted)
+
+	if s.budgets != nil {
+		for userID, thresholds := range crossed {
+			s.budgets.EmitAlerts(ctx, userID, thresholds)
+		}
+	}
+
+	return result, nil
+}
+
+// budgetKey identifies the transactions a budget check sums
+type budgetKey struct {
+	userID   uint
+	currency string
+}
+
+// replayed gets the transaction stored with req's reference ID when it was
+// created from the same request, or nil when it differs
+func (s *transactionService) replayed(ctx context.Context, req models.CreateTransactionRequest) (*models.Transaction, error) {
+	stored, err := s.repo.GetByReferenceID(ctx, req.ReferenceID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Deleted since it was checked
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check reference ID: %v", err)
+	}
+
+	if !s.createdFrom(stored, req) {
+		return nil, nil
+	}
+	return stored, nil
+}
+
+// createdFrom reports whether transaction has the user, amount, currency,
+// type and direction req asks for
+func (s *transactionService) createdFrom(transaction *models.Transaction, req models.CreateTransactionRequest) bool {
+	txType := req.Type
+	if txType == "" {
+		txType = models.TransactionTypePayment
+	}
+	direction := req.Direction
+	if direction == "" {
+		direction = models.TransactionDirectionDebit
+	}
+	return transaction.UserID == req.UserID && transaction.Amount.Equal(req.Amount) && transaction.Currency == s.amounts.Currency(req.Currency) &&
+		transaction.Type == txType && transaction.Direction == direction
+}
+
+// prepareTransaction checks req against the amount policy, the users,
+// existing reference IDs and the user's budget, counting pending as already
+// spent, and builds the transaction to store along with the alert thresholds
+// it crosses. The create hooks run last.
+func (s *transactionService) prepareTransaction(ctx context.Context, req models.CreateTransactionRequest, pending decimal.Decimal) (*models.Transaction, []int64, error) {
+	currency := s.amounts.Currency(req.Currency)
+	if err := s.amounts.Validate(currency, req.Amount); err != nil {
+		return nil, nil, err
+	}
+
+	txType := req.Type
+	if txType == "" {
+		txType = models.TransactionTypePayment
+	}
+	if req.Amount.IsNegative() && txType != models.TransactionTypeAdjustment {
+		return nil, nil, fmt.Errorf("%w: only adjustment transactions may be negative", ErrInvalidAmount)
+	}
+
+	direction := req.Direction
+	if direction == "" {
+		direction = models.TransactionDirectionDebit
+	}
+
+	if s.users != nil {
+		if err := s.users.CheckUser(ctx, req.UserID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var referenceID *string
+	if req.ReferenceID != "" {
+		_, err := s.repo.GetByReferenceID(ctx, req.ReferenceID)
+		if err == nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrDuplicateReference, req.ReferenceID)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, fmt.Errorf("failed to check reference ID: %v", err)
+		}
+		referenceID = &req.ReferenceID
+	}
+
+	var crossedThresholds []int64
+	if s.budgets != nil {
+		amount := req.Amount
+		if !pending.IsZero() {
+			amount = pending.Add(req.Amount)
+		}
+		crossed, err := s.budgets.CheckTransaction(ctx, req.UserID, currency, amount)
+		if err != nil {
+			return nil, nil, err
+		}
+		crossedThresholds = crossed
+	}
+
+	uuid := utils.NewUUID()
+	transaction := &models.Transaction{
+		UUID:        &uuid,
+		ReferenceID: referenceID,
+		UserID:      req.UserID,
+		Amount:      req.Amount,
+		Currency:    currency,
+		Type:        txType,
+		Direction:   direction,
+		Metadata:    req.Metadata,
+		Status:      "pending",
+		IsTest:      req.IsTest,
+		Version:     1,
+	}
+	if err := s.runBeforeCreate(transaction); err != nil {
+		return nil, nil, err
+	}
+	return transaction, crossedThresholds, nil
+}
+
+// publishCreated announces a stored transaction
+func (s *transactionService) publishCreated(transaction *models.Transaction) {
+	s.events.Publish(events.Event{
+		Type:          events.TransactionCreated,
+		TransactionID: transaction.ID,
+		UserID:        transaction.UserID,
+		Status:        transaction.Status,
+	})
+}
+
+// mergeThresholds adds the thresholds in more that are not in thresholds yet
+func mergeThresholds(thresholds, more []int64) []int64 {
+	for _, threshold := range more {
+		seen := false
+		for _, existing := range thresholds {
+			if existing == threshold {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			thresholds = append(thresholds, threshold)
+		}
+	}
+	return thresholds
+}
+
 // GetTransaction gets a transaction by ID
-func (s *transactionService) GetTransaction(id uint) (*models.Transaction, error) {
-	transaction, err := s.repo.GetByID(id)
+func (s *transactionService) GetTransaction(ctx context.Context, id uint) (*models.Transaction, error) {
+	transaction, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transaction not found")
+		}
+		return nil, fmt.Errorf("failed to get transaction: %v", err)
+	}
+
+	return transaction, nil
+}
+
+// GetTransactionByReference gets a transaction by its upstream reference ID
+func (s *transactionService) GetTransactionByReference(ctx context.Context, referenceID string) (*models.Transaction, error) {
+	transaction, err := s.repo.GetByReferenceID(ctx, referenceID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("transaction not found")
@@ -58,14 +446,54 @@ func (s *transactionService) GetTransaction(id uint) (*models.Transaction, error
 	return transaction, nil
 }
 
+// ResolveTransactionID gets the ID of the transaction with the given public UUID
+func (s *transactionService) ResolveTransactionID(ctx context.Context, uuid string) (uint, error) {
+	return resolveTransactionID(ctx, s.repo, uuid)
+}
+
+// resolveTransactionID looks up the ID behind a public UUID, so routes can
+// keep addressing transactions by either form
+func resolveTransactionID(ctx context.Context, repo repositories.TransactionRepository, uuid string) (uint, error) {
+	transaction, err := repo.GetByUUID(ctx, uuid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errors.New("transaction not found")
+		}
+		return 0, fmt.Errorf("failed to get transaction: %v", err)
+	}
+
+	return transaction.ID, nil
+}
+
+// GetTransactionStatus gets only the status of a transaction, served from the
+// status cache when possible. Misses are read from the primary: a replica
+// that has not caught up with an update would otherwise put the old status
+// back into the cache for its whole TTL.
+func (s *transactionService) GetTransactionStatus(ctx context.Context, id uint) (*models.TransactionStatus, error) {
+	if status, ok := s.status.Get(id); ok {
+		return &status, nil
+	}
+
+	transaction, err := s.repo.GetByIDFromPrimary(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transaction not found")
+		}
+		return nil, fmt.Errorf("failed to get transaction: %v", err)
+	}
+
+	status := models.TransactionStatus{Status: transaction.Status, UpdatedAt: transaction.UpdatedAt}
+	s.status.Set(id, status)
+	return &status, nil
+}
+
 // GetTransactions gets all transactions with filters
-func (s *transactionService) GetTransactions(filters models.TransactionFilters) ([]models.Transaction, error) {
-	// Validate status filter
-	if filters.Status != "" && filters.Status != "pending" && filters.Status != "success" && filters.Status != "failed" {
-		return nil, errors.New("invalid status filter")
+func (s *transactionService) GetTransactions(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, error) {
+	if err := validateListFilters(filters); err != nil {
+		return nil, err
 	}
 
-	transactions, err := s.repo.GetAll(filters)
+	transactions, err := s.repo.GetAll(ctx, filters)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transactions: %v", err)
 	}
@@ -73,15 +501,105 @@ func (s *transactionService) GetTransactions(filters models.TransactionFilters)
 	return transactions, nil
 }
 
+// ExportTransactions calls fn with every transaction matching the filters,
+// ignoring pagination. An error from fn stops the export.
+func (s *transactionService) ExportTransactions(ctx context.Context, filters models.TransactionFilters, fn func(*models.Transaction) error) error {
+	if err := validateListFilters(filters); err != nil {
+		return err
+	}
+
+	if err := s.repo.Each(ctx, filters, fn); err != nil {
+		return fmt.Errorf("failed to export transactions: %v", err)
+	}
+	return nil
+}
+
+// GetTransactionsWithCount gets a page of transactions with filters and the
+// total number of transactions matching them
+func (s *transactionService) GetTransactionsWithCount(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, int64, error) {
+	if err := validateListFilters(filters); err != nil {
+		return nil, 0, err
+	}
+
+	transactions, total, err := s.repo.GetAllWithCount(ctx, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get transactions: %v", err)
+	}
+
+	return transactions, total, nil
+}
+
+// sortFields are the columns transaction lists may be sorted by
+var sortFields = map[string]bool{
+	"created_at": true,
+	"amount":     true,
+	"user_id":    true,
+	"status":     true,
+}
+
+// metadataFilterKey matches the metadata keys lists may be filtered by; the
+// key is embedded in a JSON path, so quotes and path syntax are not allowed
+var metadataFilterKey = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// validateListFilters rejects unknown statuses, directions, metadata keys and sort options in list filters
+func validateListFilters(filters models.TransactionFilters) error {
+	if filters.Status != "" && filters.Status != "pending" && filters.Status != "success" && filters.Status != "failed" && filters.Status != "refunded" {
+		return errors.New("invalid status filter")
+	}
+	if filters.Direction != "" && filters.Direction != models.TransactionDirectionDebit && filters.Direction != models.TransactionDirectionCredit {
+		return errors.New("invalid direction filter")
+	}
+	for key := range filters.Metadata {
+		if !metadataFilterKey.MatchString(key) {
+			return errors.New("invalid metadata filter key, must be 1 to 64 letters, digits, underscores or hyphens")
+		}
+	}
+	if filters.SortBy != "" && !sortFields[filters.SortBy] {
+		return errors.New("invalid sort_by, must be one of created_at, amount, user_id, status")
+	}
+	if filters.Order != "" && filters.Order != "asc" && filters.Order != "desc" {
+		return errors.New("invalid order, must be asc or desc")
+	}
+	return nil
+}
+
+// LookupTransactions resolves transactions by ID in one query, preserving the
+// order of ids and reporting the ones that do not exist
+func (s *transactionService) LookupTransactions(ctx context.Context, ids []uint) (*models.TransactionLookupResult, error) {
+	found, err := s.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up transactions: %v", err)
+	}
+
+	byID := make(map[uint]models.Transaction, len(found))
+	for _, transaction := range found {
+		byID[transaction.ID] = transaction
+	}
+
+	result := &models.TransactionLookupResult{
+		Transactions: make([]models.Transaction, 0, len(ids)),
+		Missing:      make([]uint, 0),
+	}
+	for _, id := range ids {
+		if transaction, ok := byID[id]; ok {
+			result.Transactions = append(result.Transactions, transaction)
+		} else {
+			result.Missing = append(result.Missing, id)
+		}
+	}
+
+	return result, nil
+}
+
 // UpdateTransactionStatus updates transaction status
-func (s *transactionService) UpdateTransactionStatus(id uint, status string) error {
+func (s *transactionService) UpdateTransactionStatus(ctx context.Context, id uint, status string) error {
 	// Validate status
 	if status != "pending" && status != "success" && status != "failed" {
 		return errors.New("invalid status")
 	}
 
 	// Check if transaction exists
-	_, err := s.repo.GetByID(id)
+	transaction, err := s.repo.GetByIDFromPrimary(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("transaction not found")
@@ -89,22 +607,247 @@ func (s *transactionService) UpdateTransactionStatus(id uint, status string) err
 		return fmt.Errorf("failed to get transaction: %v", err)
 	}
 
+	if err := checkTransition(transaction.Status, status); err != nil {
+		return err
+	}
+
 	updates := map[string]interface{}{
 		"status": status,
 	}
 
-	err = s.repo.Update(id, updates)
+	err = s.repo.UpdateVersion(ctx, id, transaction.Version, updates)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: transaction changed while it was being updated", ErrVersionConflict)
+		}
+		return fmt.Errorf("failed to update transaction: %v", err)
+	}
+	s.status.Invalidate(id)
+
+	if transaction.Status != status {
+		s.events.Publish(events.Event{
+			Type:          events.TransactionStatusChanged,
+			TransactionID: id,
+			UserID:        transaction.UserID,
+			Status:        status,
+		})
+		s.runAfterStatusChange(transaction, status)
+	}
+
+	return nil
+}
+
+// UpdateTransaction changes the status and patches the metadata as set in
+// req. The update applies only while the transaction is at the version it
+// was read at, and at req.ExpectedVersion when set, so concurrent updates
+// are never lost.
+func (s *transactionService) UpdateTransaction(ctx context.Context, id uint, req models.UpdateTransactionRequest) error {
+	if req.Status == "" && req.Metadata == nil {
+		return errors.New("no fields to update")
+	}
+	if req.Status != "" && req.Status != "pending" && req.Status != "success" && req.Status != "failed" {
+		return errors.New("invalid status")
+	}
+	if req.Reason != "" && req.Status == "" {
+		return ErrReasonWithoutChange
+	}
+	if req.Metadata != nil && req.ExpectedVersion == nil {
+		return ErrVersionRequired
+	}
+
+	// Check if transaction exists
+	transaction, err := s.repo.GetByIDFromPrimary(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("transaction not found")
+		}
+		return fmt.Errorf("failed to get transaction: %v", err)
+	}
+	if req.ExpectedVersion != nil && *req.ExpectedVersion != transaction.Version {
+		return fmt.Errorf("%w: expected version %d, transaction is at version %d", ErrVersionConflict, *req.ExpectedVersion, transaction.Version)
+	}
+
+	updates := map[string]interface{}{}
+	if req.Status != "" {
+		if req.Reason != "" && req.Status == transaction.Status {
+			return ErrReasonWithoutChange
+		}
+		if err := checkTransition(transaction.Status, req.Status); err != nil {
+			return err
+		}
+		updates["status"] = req.Status
+	}
+	if req.Metadata != nil {
+		metadata := req.Metadata.Apply(transaction.Metadata)
+		if len(metadata) > maxMetadataKeys {
+			return fmt.Errorf("%w: at most %d are allowed", ErrTooManyMetadataKeys, maxMetadataKeys)
+		}
+		updates["metadata"] = metadata
+	}
+
+	err = s.repo.UpdateVersion(ctx, id, transaction.Version, updates)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: transaction changed while it was being updated", ErrVersionConflict)
+		}
 		return fmt.Errorf("failed to update transaction: %v", err)
 	}
+	s.status.Invalidate(id)
+
+	if req.Status != "" && transaction.Status != req.Status {
+		s.events.Publish(events.Event{
+			Type:          events.TransactionStatusChanged,
+			TransactionID: id,
+			UserID:        transaction.UserID,
+			Status:        req.Status,
+			Reason:        req.Reason,
+		})
+		s.runAfterStatusChange(transaction, req.Status)
+	} else if req.Metadata != nil {
+		s.publishUpdated(id, transaction)
+	}
 
 	return nil
 }
 
+// PatchTransaction updates the fields set in req and returns the updated
+// transaction. Like UpdateTransaction, the update applies only while the
+// transaction is at the version it was read at.
+func (s *transactionService) PatchTransaction(ctx context.Context, id uint, req models.PatchTransactionRequest) (*models.Transaction, error) {
+	updates := map[string]interface{}{}
+	if req.Status != nil {
+		if *req.Status != "pending" && *req.Status != "success" && *req.Status != "failed" {
+			return nil, errors.New("invalid status")
+		}
+		updates["status"] = *req.Status
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.Metadata != nil {
+		updates["metadata"] = req.Metadata
+	}
+	if len(updates) == 0 {
+		return nil, errors.New("no fields to update")
+	}
+
+	// Check if transaction exists
+	transaction, err := s.repo.GetByIDFromPrimary(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transaction not found")
+		}
+		return nil, fmt.Errorf("failed to get transaction: %v", err)
+	}
+
+	if req.Status != nil {
+		if err := checkTransition(transaction.Status, *req.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.repo.UpdateVersion(ctx, id, transaction.Version, updates)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: transaction changed while it was being updated", ErrVersionConflict)
+		}
+		return nil, fmt.Errorf("failed to update transaction: %v", err)
+	}
+	s.status.Invalidate(id)
+
+	if req.Status != nil && transaction.Status != *req.Status {
+		s.events.Publish(events.Event{
+			Type:          events.TransactionStatusChanged,
+			TransactionID: id,
+			UserID:        transaction.UserID,
+			Status:        *req.Status,
+		})
+		s.runAfterStatusChange(transaction, *req.Status)
+	} else if req.Description != nil || req.Metadata != nil {
+		s.publishUpdated(id, transaction)
+	}
+
+	updated, err := s.repo.GetByIDFromPrimary(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %v", err)
+	}
+
+	return updated, nil
+}
+
+// publishUpdated publishes that fields other than the status of the
+// transaction id changed. The event carries the status it keeps.
+func (s *transactionService) publishUpdated(id uint, transaction *models.Transaction) {
+	s.events.Publish(events.Event{
+		Type:          events.TransactionUpdated,
+		TransactionID: id,
+		UserID:        transaction.UserID,
+		Status:        transaction.Status,
+	})
+}
+
+// RefundTransaction reverses a successful transaction: it creates a
+// successful refund of the same amount in the opposite direction, linked by
+// parent_id, and marks the original refunded, in one database transaction
+func (s *transactionService) RefundTransaction(ctx context.Context, id uint) (*models.Transaction, error) {
+	transaction, err := s.repo.GetByIDFromPrimary(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transaction not found")
+		}
+		return nil, fmt.Errorf("failed to get transaction: %v", err)
+	}
+
+	if transaction.Type == models.TransactionTypeRefund {
+		return nil, fmt.Errorf("%w: refunds cannot be refunded", ErrNotRefundable)
+	}
+	if transaction.Status != "success" {
+		return nil, fmt.Errorf("%w: status is %s, not success", ErrNotRefundable, transaction.Status)
+	}
+
+	direction := models.TransactionDirectionCredit
+	if transaction.Direction == models.TransactionDirectionCredit {
+		direction = models.TransactionDirectionDebit
+	}
+	uuid := utils.NewUUID()
+	refund := &models.Transaction{
+		UUID:        &uuid,
+		UserID:      transaction.UserID,
+		Amount:      transaction.Amount,
+		Currency:    transaction.Currency,
+		Type:        models.TransactionTypeRefund,
+		Direction:   direction,
+		Status:      "success",
+		Description: fmt.Sprintf("Refund of transaction %d", transaction.ID),
+		ParentID:    &transaction.ID,
+		Version:     1,
+	}
+
+	if err := s.repo.Refund(ctx, id, refund); err != nil {
+		// The original changed status since it was read
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: status changed concurrently", ErrNotRefundable)
+		}
+		return nil, fmt.Errorf("failed to refund transaction: %v", err)
+	}
+	s.status.Invalidate(id)
+
+	s.events.Publish(events.Event{
+		Type:          events.TransactionStatusChanged,
+		TransactionID: id,
+		UserID:        transaction.UserID,
+		Status:        "refunded",
+	})
+	s.runAfterStatusChange(transaction, "refunded")
+	s.publishCreated(refund)
+
+	return refund, nil
+}
+
 // DeleteTransaction deletes a transaction
-func (s *transactionService) DeleteTransaction(id uint) error {
+func (s *transactionService) DeleteTransaction(ctx context.Context, id uint) error {
 	// Check if transaction exists
-	_, err := s.repo.GetByID(id)
+	transaction, err := s.repo.GetByIDFromPrimary(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("transaction not found")
@@ -112,10 +855,77 @@ func (s *transactionService) DeleteTransaction(id uint) error {
 		return fmt.Errorf("failed to get transaction: %v", err)
 	}
 
-	err = s.repo.Delete(id)
+	if transaction.Status == "refunded" || transaction.ParentID != nil {
+		return ErrNotDeletable
+	}
+
+	if err := s.runBeforeDelete(transaction); err != nil {
+		return err
+	}
+
+	err = s.repo.Delete(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete transaction: %v", err)
 	}
+	s.status.Invalidate(id)
+
+	s.events.Publish(events.Event{
+		Type:          events.TransactionDeleted,
+		TransactionID: id,
+		UserID:        transaction.UserID,
+	})
 
 	return nil
 }
+
+// WaitForStatusChange blocks until the transaction's status differs from
+// lastStatus, the timeout elapses or ctx is done. An empty lastStatus waits for
+// any change from the current status. After a timeout the transaction is read
+// again, so changes made by other instances are still reported.
+func (s *transactionService) WaitForStatusChange(ctx context.Context, id uint, lastStatus string, timeout time.Duration) (*models.TransactionWaitResult, error) {
+	// Subscribe before reading so no change between the two is missed
+	changes, cancel := s.events.Subscribe()
+	defer cancel()
+
+	transaction, err := s.GetTransaction(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if lastStatus == "" {
+		lastStatus = transaction.Status
+	}
+	if transaction.Status != lastStatus {
+		return &models.TransactionWaitResult{Transaction: transaction, Changed: true}, nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case event := <-changes:
+			if event.TransactionID != id {
+				continue
+			}
+			switch event.Type {
+			case events.TransactionDeleted:
+				return nil, errors.New("transaction not found")
+			case events.TransactionStatusChanged:
+				if event.Status == lastStatus {
+					continue
+				}
+				transaction.Status = event.Status
+				transaction.UpdatedAt = event.OccurredAt
+				return &models.TransactionWaitResult{Transaction: transaction, Changed: true}, nil
+			}
+		case <-timer.C:
+			transaction, err := s.GetTransaction(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			return &models.TransactionWaitResult{Transaction: transaction, Changed: transaction.Status != lastStatus}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"interview/internal/events"
+	"interview/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// feedBuffer is how many transactions a feed subscriber may fall behind
+// before transactions are dropped for it
+const feedBuffer = 16
+
+// TransactionFeed pushes created and updated transactions to live
+// subscribers. It subscribes to the event broker once and reads each changed
+// transaction once, however many subscribers receive it.
+type TransactionFeed struct {
+	events       events.Broker
+	transactions TransactionService
+
+	mu          sync.RWMutex
+	subscribers map[int]*feedSubscriber
+	nextID      int
+}
+
+// feedSubscriber is a subscription to the feed
+type feedSubscriber struct {
+	filter models.TransactionFeedFilter
+	ch     chan models.TransactionFeedEvent
+}
+
+// NewTransactionFeed creates a feed of the transactions whose events are
+// published on broker; call Start to begin delivering them
+func NewTransactionFeed(broker events.Broker, transactions TransactionService) *TransactionFeed {
+	return &TransactionFeed{
+		events:       broker,
+		transactions: transactions,
+		subscribers:  make(map[int]*feedSubscriber),
+	}
+}
+
+// Start delivers transaction events to the feed's subscribers until stop is
+// called, which also ends every subscription
+func (f *TransactionFeed) Start() (stop func()) {
+	changes, cancel := f.events.Subscribe()
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for event := range changes {
+			f.deliver(event)
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			for id, subscriber := range f.subscribers {
+				delete(f.subscribers, id)
+				close(subscriber.ch)
+			}
+		})
+	}
+}
+
+// Subscribe returns a channel receiving the transactions matching filter and
+// a function that cancels the subscription. Subscribers that fall behind
+// miss transactions rather than holding up the others.
+func (f *TransactionFeed) Subscribe(filter models.TransactionFeedFilter) (<-chan models.TransactionFeedEvent, func()) {
+	subscriber := &feedSubscriber{filter: filter, ch: make(chan models.TransactionFeedEvent, feedBuffer)}
+
+	f.mu.Lock()
+	id := f.nextID
+	f.nextID++
+	f.subscribers[id] = subscriber
+	f.mu.Unlock()
+
+	var once sync.Once
+	return subscriber.ch, func() {
+		once.Do(func() {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			// Already closed if the feed stopped first
+			if _, ok := f.subscribers[id]; ok {
+				delete(f.subscribers, id)
+				close(subscriber.ch)
+			}
+		})
+	}
+}
+
+// deliver sends the transaction of a created, status changed or updated
+// event to the subscribers whose filter matches it. Events carry the user and
+// status, so the transaction is only read when someone will receive it.
+func (f *TransactionFeed) deliver(event events.Event) {
+	if event.Type != events.TransactionCreated && event.Type != events.TransactionStatusChanged && event.Type != events.TransactionUpdated {
+		return
+	}
+
+	if !f.anyMatches(event) {
+		return
+	}
+
+	transaction, err := f.transactions.GetTransaction(context.Background(), event.TransactionID)
+	if err != nil {
+		// Deleted since the event, or unreadable; the feed moves on
+		logrus.WithError(err).WithField("transaction_id", event.TransactionID).Debug("Skipping transaction feed event")
+		return
+	}
+	// The event's status is the one the subscribers matched on
+	transaction.Status = event.Status
+
+	feedEvent := models.TransactionFeedEvent{Type: event.Type, Transaction: transaction}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, subscriber := range f.subscribers {
+		if !feedMatches(subscriber.filter, event) {
+			continue
+		}
+		select {
+		case subscriber.ch <- feedEvent:
+		default:
+		}
+	}
+}
+
+// anyMatches reports whether any subscriber's filter matches an event
+func (f *TransactionFeed) anyMatches(event events.Event) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, subscriber := range f.subscribers {
+		if feedMatches(subscriber.filter, event) {
+			return true
+		}
+	}
+	return false
+}
+
+// feedMatches reports whether an event is about a transaction filter selects
+func feedMatches(filter models.TransactionFeedFilter, event events.Event) bool {
+	if filter.UserID != 0 && filter.UserID != event.UserID {
+		return false
+	}
+	return filter.Status == "" || filter.Status == event.Status
+}
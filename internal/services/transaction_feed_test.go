@@ -0,0 +1,100 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"interview/internal/events"
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// receiveFeed waits briefly for the next feed event
+func receiveFeed(t *testing.T, ch <-chan models.TransactionFeedEvent) models.TransactionFeedEvent {
+	t.Helper()
+	select {
+	case event, ok := <-ch:
+		require.True(t, ok, "feed closed")
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no feed event")
+		return models.TransactionFeedEvent{}
+	}
+}
+
+func TestTransactionFeed_FiltersAndReadsOnce(t *testing.T) {
+	repo := new(MockTransactionRepository)
+	repo.On("GetByID", uint(1)).Return(&models.Transaction{ID: 1, UserID: 7, Status: "pending"}, nil).Once()
+	bus := events.NewBus()
+	feed := services.NewTransactionFeed(bus, services.NewTransactionService(repo))
+	stop := feed.Start()
+	defer stop()
+
+	user, cancelUser := feed.Subscribe(models.TransactionFeedFilter{UserID: 7, Status: "success"})
+	defer cancelUser()
+
+	// Nothing matches these, so neither transaction is read
+	bus.Publish(events.Event{Type: events.TransactionCreated, TransactionID: 2, UserID: 8, Status: "success"})
+	bus.Publish(events.Event{Type: events.TransactionCreated, TransactionID: 1, UserID: 7, Status: "pending"})
+	bus.Publish(events.Event{Type: events.TransactionStatusChanged, TransactionID: 1, UserID: 7, Status: "success"})
+
+	event := receiveFeed(t, user)
+	assert.Equal(t, events.TransactionStatusChanged, event.Type)
+	assert.Equal(t, uint(1), event.Transaction.ID)
+	assert.Equal(t, "success", event.Transaction.Status)
+	repo.AssertExpectations(t)
+}
+
+func TestTransactionFeed_DeliversUpdates(t *testing.T) {
+	repo := new(MockTransactionRepository)
+	repo.On("GetByID", uint(1)).Return(&models.Transaction{ID: 1, UserID: 7, Status: "success", Description: "June invoice"}, nil)
+	bus := events.NewBus()
+	feed := services.NewTransactionFeed(bus, services.NewTransactionService(repo))
+	stop := feed.Start()
+	defer stop()
+
+	ch, cancel := feed.Subscribe(models.TransactionFeedFilter{UserID: 7})
+	defer cancel()
+
+	bus.Publish(events.Event{Type: events.TransactionUpdated, TransactionID: 1, UserID: 7, Status: "success"})
+
+	event := receiveFeed(t, ch)
+	assert.Equal(t, events.TransactionUpdated, event.Type)
+	assert.Equal(t, "June invoice", event.Transaction.Description)
+}
+
+func TestTransactionFeed_SkipsDeletedTransactions(t *testing.T) {
+	repo := new(MockTransactionRepository)
+	repo.On("GetByID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	repo.On("GetByID", uint(2)).Return(&models.Transaction{ID: 2, Status: "pending"}, nil)
+	bus := events.NewBus()
+	feed := services.NewTransactionFeed(bus, services.NewTransactionService(repo))
+	stop := feed.Start()
+	defer stop()
+
+	ch, cancel := feed.Subscribe(models.TransactionFeedFilter{})
+	defer cancel()
+
+	bus.Publish(events.Event{Type: events.TransactionCreated, TransactionID: 1, Status: "pending"})
+	bus.Publish(events.Event{Type: events.TransactionDeleted, TransactionID: 1})
+	bus.Publish(events.Event{Type: events.TransactionCreated, TransactionID: 2, Status: "pending"})
+
+	assert.Equal(t, uint(2), receiveFeed(t, ch).Transaction.ID)
+}
+
+func TestTransactionFeed_StopEndsSubscriptions(t *testing.T) {
+	feed := services.NewTransactionFeed(events.NewBus(), services.NewTransactionService(new(MockTransactionRepository)))
+	stop := feed.Start()
+
+	ch, cancel := feed.Subscribe(models.TransactionFeedFilter{})
+	stop()
+	stop()
+	cancel()
+
+	_, ok := <-ch
+	assert.False(t, ok)
+}
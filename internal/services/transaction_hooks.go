@@ -0,0 +1,113 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"interview/internal/models"
+)
+
+// ErrRejectedByHook is returned when a lifecycle hook rejects a create or a delete
+var ErrRejectedByHook = errors.New("rejected by hook")
+
+// BeforeCreateHook runs before a transaction created from a request is
+// stored, after it passed the amount policy, user and budget checks. It may
+// change the transaction, for example to enrich its metadata or description,
+// or reject it by returning an error. Changes are not checked again, so a
+// hook should leave the amount, user and status alone.
+type BeforeCreateHook interface {
+	BeforeCreate(transaction *models.Transaction) error
+}
+
+// AfterStatusChangeHook runs after a transaction's status changed, including
+// to refunded. transaction holds the new status; it cannot undo the change.
+type AfterStatusChangeHook interface {
+	AfterStatusChange(transaction *models.Transaction, from string)
+}
+
+// BeforeDeleteHook runs before a transaction is deleted and keeps it by
+// returning an error
+type BeforeDeleteHook interface {
+	BeforeDelete(transaction *models.Transaction) error
+}
+
+// BeforeCreateFunc adapts a function to a BeforeCreateHook
+type BeforeCreateFunc func(transaction *models.Transaction) error
+
+// BeforeCreate calls f
+func (f BeforeCreateFunc) BeforeCreate(transaction *models.Transaction) error {
+	return f(transaction)
+}
+
+// AfterStatusChangeFunc adapts a function to an AfterStatusChangeHook
+type AfterStatusChangeFunc func(transaction *models.Transaction, from string)
+
+// AfterStatusChange calls f
+func (f AfterStatusChangeFunc) AfterStatusChange(transaction *models.Transaction, from string) {
+	f(transaction, from)
+}
+
+// BeforeDeleteFunc adapts a function to a BeforeDeleteHook
+type BeforeDeleteFunc func(transaction *models.Transaction) error
+
+// BeforeDelete calls f
+func (f BeforeDeleteFunc) BeforeDelete(transaction *models.Transaction) error {
+	return f(transaction)
+}
+
+// WithBeforeCreateHook runs hook before every create, after the hooks
+// registered before it
+func WithBeforeCreateHook(hook BeforeCreateHook) TransactionServiceOption {
+	return func(s *transactionService) {
+		s.beforeCreate = append(s.beforeCreate, hook)
+	}
+}
+
+// WithAfterStatusChangeHook runs hook after every status change, after the
+// hooks registered before it
+func WithAfterStatusChangeHook(hook AfterStatusChangeHook) TransactionServiceOption {
+	return func(s *transactionService) {
+		s.afterStatusChange = append(s.afterStatusChange, hook)
+	}
+}
+
+// WithBeforeDeleteHook runs hook before every delete, after the hooks
+// registered before it
+func WithBeforeDeleteHook(hook BeforeDeleteHook) TransactionServiceOption {
+	return func(s *transactionService) {
+		s.beforeDelete = append(s.beforeDelete, hook)
+	}
+}
+
+// runBeforeCreate runs the create hooks until one rejects transaction
+func (s *transactionService) runBeforeCreate(transaction *models.Transaction) error {
+	for _, hook := range s.beforeCreate {
+		if err := hook.BeforeCreate(transaction); err != nil {
+			return fmt.Errorf("%w: %v", ErrRejectedByHook, err)
+		}
+	}
+	return nil
+}
+
+// runAfterStatusChange runs the status change hooks on a copy of
+// transaction, as read before the change, with its new status
+func (s *transactionService) runAfterStatusChange(transaction *models.Transaction, status string) {
+	if len(s.afterStatusChange) == 0 {
+		return
+	}
+	changed := *transaction
+	changed.Status = status
+	for _, hook := range s.afterStatusChange {
+		hook.AfterStatusChange(&changed, transaction.Status)
+	}
+}
+
+// runBeforeDelete runs the delete hooks until one rejects the delete
+func (s *transactionService) runBeforeDelete(transaction *models.Transaction) error {
+	for _, hook := range s.beforeDelete {
+		if err := hook.BeforeDelete(transaction); err != nil {
+			return fmt.Errorf("%w: %v", ErrRejectedByHook, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionService_BeforeCreateHook(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	var calls []string
+	service := services.NewTransactionService(mockRepo,
+		services.WithBeforeCreateHook(services.BeforeCreateFunc(func(tx *models.Transaction) error {
+			calls = append(calls, "first")
+			tx.Metadata = map[string]string{"region": "eu"}
+			return nil
+		})),
+		services.WithBeforeCreateHook(services.BeforeCreateFunc(func(tx *models.Transaction) error {
+			calls = append(calls, "second")
+			return nil
+		})),
+	)
+
+	mockRepo.On("Create", mock.MatchedBy(func(tx *models.Transaction) bool {
+		return tx.Metadata["region"] == "eu"
+	})).Return(nil)
+
+	result, err := service.CreateTransaction(context.Background(), models.CreateTransactionRequest{UserID: 1, Amount: decimal.NewFromInt(100)})
+	require.NoError(t, err)
+	assert.Equal(t, "eu", result.Metadata["region"])
+	assert.Equal(t, []string{"first", "second"}, calls)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_BeforeCreateHookRejects(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo,
+		services.WithBeforeCreateHook(services.BeforeCreateFunc(func(tx *models.Transaction) error {
+			if tx.Amount.GreaterThan(decimal.NewFromInt(1000)) {
+				return errors.New("amount needs manual approval")
+			}
+			return nil
+		})),
+	)
+
+	_, err := service.CreateTransaction(context.Background(), models.CreateTransactionRequest{UserID: 1, Amount: decimal.NewFromInt(5000)})
+	assert.ErrorIs(t, err, services.ErrRejectedByHook)
+	assert.ErrorContains(t, err, "amount needs manual approval")
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+
+	// Bulk creates report rejected items and store the others
+	mockRepo.On("CreateBatch", mock.MatchedBy(func(txs []models.Transaction) bool { return len(txs) == 1 })).Return(nil)
+	result, err := service.CreateTransactions(context.Background(), []models.CreateTransactionRequest{
+		{UserID: 1, Amount: decimal.NewFromInt(5000)},
+		{UserID: 1, Amount: decimal.NewFromInt(10)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Failed)
+	assert.Contains(t, result.Results[0].Error, "rejected by hook")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_AfterStatusChangeHook(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	type change struct {
+		id       uint
+		from, to string
+	}
+	var changes []change
+	service := services.NewTransactionService(mockRepo,
+		services.WithAfterStatusChangeHook(services.AfterStatusChangeFunc(func(tx *models.Transaction, from string) {
+			changes = append(changes, change{tx.ID, from, tx.Status})
+		})),
+	)
+
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, UserID: 1, Status: "pending"}, nil)
+	mockRepo.On("UpdateVersion", uint(1), uint(0), mock.Anything).Return(nil)
+
+	require.NoError(t, service.UpdateTransactionStatus(context.Background(), 1, "success"))
+	assert.Equal(t, []change{{1, "pending", "success"}}, changes)
+
+	// Setting the current status again is not a change
+	require.NoError(t, service.UpdateTransactionStatus(context.Background(), 1, "pending"))
+	assert.Len(t, changes, 1)
+}
+
+func TestTransactionService_BeforeDeleteHook(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo,
+		services.WithBeforeDeleteHook(services.BeforeDeleteFunc(func(tx *models.Transaction) error {
+			if tx.Status == "success" {
+				return errors.New("successful transactions are kept for audit")
+			}
+			return nil
+		})),
+	)
+
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, Status: "success"}, nil)
+	mockRepo.On("GetByIDFromPrimary", uint(2)).Return(&models.Transaction{ID: 2, Status: "failed"}, nil)
+	mockRepo.On("Delete", uint(2)).Return(nil)
+
+	err := service.DeleteTransaction(context.Background(), 1)
+	assert.ErrorIs(t, err, services.ErrRejectedByHook)
+	mockRepo.AssertNotCalled(t, "Delete", uint(1))
+
+	assert.NoError(t, service.DeleteTransaction(context.Background(), 2))
+	mockRepo.AssertExpectations(t)
+}
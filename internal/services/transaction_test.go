@@ -1,11 +1,15 @@
 package services_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/shopspring/decimal"
 
+	"interview/internal/events"
 	"interview/internal/models"
 	"interview/internal/services"
 
@@ -19,12 +23,25 @@ type MockTransactionRepository struct {
 	mock.Mock
 }
 
-func (m *MockTransactionRepository) Create(transaction *models.Transaction) error {
+func (m *MockTransactionRepository) Create(ctx context.Context, transaction *models.Transaction) error {
 	args := m.Called(transaction)
 	return args.Error(0)
 }
 
-func (m *MockTransactionRepository) GetByID(id uint) (*models.Transaction, error) {
+func (m *MockTransactionRepository) CreateBatch(ctx context.Context, transactions []models.Transaction) error {
+	args := m.Called(transactions)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) GetByID(ctx context.Context, id uint) (*models.Transaction, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetByIDFromPrimary(ctx context.Context, id uint) (*models.Transaction, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -32,41 +49,140 @@ func (m *MockTransactionRepository) GetByID(id uint) (*models.Transaction, error
 	return args.Get(0).(*models.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionRepository) GetAll(filters models.TransactionFilters) ([]models.Transaction, error) {
+func (m *MockTransactionRepository) GetByIDs(ctx context.Context, ids []uint) ([]models.Transaction, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetByReferenceID(ctx context.Context, referenceID string) (*models.Transaction, error) {
+	args := m.Called(referenceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetByReferenceIDs(ctx context.Context, referenceIDs []string) ([]models.Transaction, error) {
+	args := m.Called(referenceIDs)
+	return args.Get(0).([]models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) Each(ctx context.Context, filters models.TransactionFilters, fn func(*models.Transaction) error) error {
+	args := m.Called(filters)
+	for _, transaction := range args.Get(0).([]models.Transaction) {
+		if err := fn(&transaction); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetByUUID(ctx context.Context, uuid string) (*models.Transaction, error) {
+	args := m.Called(uuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetAll(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, error) {
 	args := m.Called(filters)
 	return args.Get(0).([]models.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionRepository) Update(id uint, updates map[string]interface{}) error {
+func (m *MockTransactionRepository) GetAllWithCount(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, int64, error) {
+	args := m.Called(filters)
+	return args.Get(0).([]models.Transaction), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTransactionRepository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
 	args := m.Called(id, updates)
 	return args.Error(0)
 }
 
-func (m *MockTransactionRepository) Delete(id uint) error {
+func (m *MockTransactionRepository) UpdateVersion(ctx context.Context, id, version uint, updates map[string]interface{}) error {
+	args := m.Called(id, version, updates)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) Delete(ctx context.Context, id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockTransactionRepository) GetTodaySuccessful() (int, decimal.Decimal, error) {
+func (m *MockTransactionRepository) Refund(ctx context.Context, parentID uint, refund *models.Transaction) error {
+	args := m.Called(parentID, refund)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) GetTodaySuccessful(ctx context.Context) (int, decimal.Decimal, error) {
 	args := m.Called()
 	return args.Int(0), args.Get(1).(decimal.Decimal), args.Error(2)
 }
 
-func (m *MockTransactionRepository) GetAveragePerUser() (decimal.Decimal, error) {
+func (m *MockTransactionRepository) GetAveragePerUser(ctx context.Context) (decimal.Decimal, error) {
 	args := m.Called()
 	return args.Get(0).(decimal.Decimal), args.Error(1)
 }
 
-func (m *MockTransactionRepository) GetLatest(limit int) ([]models.Transaction, error) {
+func (m *MockTransactionRepository) GetLatest(ctx context.Context, limit int) ([]models.Transaction, error) {
 	args := m.Called(limit)
 	return args.Get(0).([]models.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionRepository) GetStatusCounts() (models.StatusCounts, error) {
+func (m *MockTransactionRepository) GetStatusCounts(ctx context.Context) (models.StatusCounts, error) {
 	args := m.Called()
 	return args.Get(0).(models.StatusCounts), args.Error(1)
 }
 
+func (m *MockTransactionRepository) GetDirectionTotals(ctx context.Context) (models.DirectionTotals, error) {
+	args := m.Called()
+	return args.Get(0).(models.DirectionTotals), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetSuccessfulBetween(ctx context.Context, from, to time.Time) (int, decimal.Decimal, error) {
+	args := m.Called(from, to)
+	return args.Int(0), args.Get(1).(decimal.Decimal), args.Error(2)
+}
+
+func (m *MockTransactionRepository) GetStatusCountsBetween(ctx context.Context, from, to time.Time) (models.StatusCounts, error) {
+	args := m.Called(from, to)
+	return args.Get(0).(models.StatusCounts), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetDirectionTotalsBetween(ctx context.Context, from, to time.Time) (models.DirectionTotals, error) {
+	args := m.Called(from, to)
+	return args.Get(0).(models.DirectionTotals), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetCurrencyTotals(ctx context.Context) ([]models.CurrencyTotal, error) {
+	args := m.Called()
+	return args.Get(0).([]models.CurrencyTotal), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetCurrencyTotalsBetween(ctx context.Context, from, to time.Time) ([]models.CurrencyTotal, error) {
+	args := m.Called(from, to)
+	return args.Get(0).([]models.CurrencyTotal), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetAmountDistribution(ctx context.Context) (models.AmountDistribution, error) {
+	args := m.Called()
+	return args.Get(0).(models.AmountDistribution), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetAmountDistributionBetween(ctx context.Context, from, to time.Time) (models.AmountDistribution, error) {
+	args := m.Called(from, to)
+	return args.Get(0).(models.AmountDistribution), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetTopUsersBetween(ctx context.Context, from, to time.Time, sortBy string, limit int) ([]models.UserVolume, error) {
+	args := m.Called(from, to, sortBy, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.UserVolume), args.Error(1)
+}
+
 func TestTransactionService_CreateTransaction(t *testing.T) {
 	mockRepo := new(MockTransactionRepository)
 	service := services.NewTransactionService(mockRepo)
@@ -81,7 +197,7 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 		tx.ID = 1 // Simulate DB auto-increment
 	})
 
-	result, err := service.CreateTransaction(req)
+	result, err := service.CreateTransaction(context.Background(), req)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -92,6 +208,36 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestTransactionService_CreateTransactionDirection(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	mockRepo.On("Create", mock.AnythingOfType("*models.Transaction")).Return(nil)
+
+	result, err := service.CreateTransaction(context.Background(), models.CreateTransactionRequest{UserID: 1, Amount: decimal.NewFromInt(10)})
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransactionDirectionDebit, result.Direction)
+
+	result, err = service.CreateTransaction(context.Background(), models.CreateTransactionRequest{UserID: 1, Amount: decimal.NewFromInt(10), Direction: models.TransactionDirectionCredit})
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransactionDirectionCredit, result.Direction)
+}
+
+func TestTransactionService_CreateTransactionMetadata(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	metadata := models.TransactionMetadata{"invoice": "INV-1"}
+	mockRepo.On("Create", mock.MatchedBy(func(tx *models.Transaction) bool {
+		return tx.Metadata["invoice"] == "INV-1"
+	})).Return(nil)
+
+	result, err := service.CreateTransaction(context.Background(), models.CreateTransactionRequest{UserID: 1, Amount: decimal.NewFromInt(10), Metadata: metadata})
+	assert.NoError(t, err)
+	assert.Equal(t, metadata, result.Metadata)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestTransactionService_CreateTransactionError(t *testing.T) {
 	mockRepo := new(MockTransactionRepository)
 	service := services.NewTransactionService(mockRepo)
@@ -103,7 +249,7 @@ func TestTransactionService_CreateTransactionError(t *testing.T) {
 
 	mockRepo.On("Create", mock.AnythingOfType("*models.Transaction")).Return(errors.New("database error"))
 
-	result, err := service.CreateTransaction(req)
+	result, err := service.CreateTransaction(context.Background(), req)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -124,7 +270,7 @@ func TestTransactionService_GetTransaction(t *testing.T) {
 
 	mockRepo.On("GetByID", uint(1)).Return(expectedTx, nil)
 
-	result, err := service.GetTransaction(1)
+	result, err := service.GetTransaction(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -138,7 +284,7 @@ func TestTransactionService_GetTransactionNotFound(t *testing.T) {
 
 	mockRepo.On("GetByID", uint(1)).Return((*models.Transaction)(nil), gorm.ErrRecordNotFound)
 
-	result, err := service.GetTransaction(1)
+	result, err := service.GetTransaction(context.Background(), 1)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -152,7 +298,7 @@ func TestTransactionService_GetTransactionError(t *testing.T) {
 
 	mockRepo.On("GetByID", uint(1)).Return((*models.Transaction)(nil), errors.New("database error"))
 
-	result, err := service.GetTransaction(1)
+	result, err := service.GetTransaction(context.Background(), 1)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -176,7 +322,7 @@ func TestTransactionService_GetTransactions(t *testing.T) {
 
 	mockRepo.On("GetAll", filters).Return(expectedTxs, nil)
 
-	result, err := service.GetTransactions(filters)
+	result, err := service.GetTransactions(context.Background(), filters)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -192,7 +338,7 @@ func TestTransactionService_GetTransactionsError(t *testing.T) {
 
 	mockRepo.On("GetAll", filters).Return([]models.Transaction{}, errors.New("database error"))
 
-	result, err := service.GetTransactions(filters)
+	result, err := service.GetTransactions(context.Background(), filters)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -200,6 +346,30 @@ func TestTransactionService_GetTransactionsError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestTransactionService_ExportTransactions(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	filters := models.TransactionFilters{UserID: 1}
+	mockRepo.On("Each", filters).Return([]models.Transaction{{ID: 1}, {ID: 2}}, nil)
+
+	var ids []uint
+	err := service.ExportTransactions(context.Background(), filters, func(transaction *models.Transaction) error {
+		ids = append(ids, transaction.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1, 2}, ids)
+
+	// Export filters are validated like list filters
+	err = service.ExportTransactions(context.Background(), models.TransactionFilters{Status: "lost"}, func(*models.Transaction) error { return nil })
+	assert.EqualError(t, err, "invalid status filter")
+
+	err = service.ExportTransactions(context.Background(), filters, func(*models.Transaction) error { return errors.New("broken pipe") })
+	assert.EqualError(t, err, "failed to export transactions: broken pipe")
+	mockRepo.AssertExpectations(t)
+}
+
 func TestTransactionService_GetTransactionsInvalidStatusFilter(t *testing.T) {
 	mockRepo := new(MockTransactionRepository)
 	service := services.NewTransactionService(mockRepo)
@@ -208,7 +378,7 @@ func TestTransactionService_GetTransactionsInvalidStatusFilter(t *testing.T) {
 		Status: "invalid_status",
 	}
 
-	result, err := service.GetTransactions(filters)
+	result, err := service.GetTransactions(context.Background(), filters)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -234,7 +404,7 @@ func TestTransactionService_GetTransactionsWithValidStatusFilters(t *testing.T)
 
 		mockRepo.On("GetAll", filters).Return(expectedTxs, nil).Once()
 
-		result, err := service.GetTransactions(filters)
+		result, err := service.GetTransactions(context.Background(), filters)
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedTxs, result)
@@ -248,10 +418,10 @@ func TestTransactionService_UpdateTransactionStatus(t *testing.T) {
 	service := services.NewTransactionService(mockRepo)
 
 	// Test successful update
-	mockRepo.On("GetByID", uint(1)).Return(&models.Transaction{ID: 1, Status: "pending"}, nil)
-	mockRepo.On("Update", uint(1), map[string]interface{}{"status": "success"}).Return(nil)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, Status: "pending"}, nil)
+	mockRepo.On("UpdateVersion", uint(1), uint(0), map[string]interface{}{"status": "success"}).Return(nil)
 
-	err := service.UpdateTransactionStatus(1, "success")
+	err := service.UpdateTransactionStatus(context.Background(), 1, "success")
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -261,9 +431,9 @@ func TestTransactionService_UpdateTransactionStatusNotFound(t *testing.T) {
 	mockRepo := new(MockTransactionRepository)
 	service := services.NewTransactionService(mockRepo)
 
-	mockRepo.On("GetByID", uint(1)).Return((*models.Transaction)(nil), gorm.ErrRecordNotFound)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return((*models.Transaction)(nil), gorm.ErrRecordNotFound)
 
-	err := service.UpdateTransactionStatus(1, "success")
+	err := service.UpdateTransactionStatus(context.Background(), 1, "success")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "transaction not found")
@@ -274,35 +444,54 @@ func TestTransactionService_UpdateTransactionStatusInvalidStatus(t *testing.T) {
 	mockRepo := new(MockTransactionRepository)
 	service := services.NewTransactionService(mockRepo)
 
-	err := service.UpdateTransactionStatus(1, "invalid")
+	err := service.UpdateTransactionStatus(context.Background(), 1, "invalid")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid status")
 	mockRepo.AssertNotCalled(t, "GetByID")
-	mockRepo.AssertNotCalled(t, "Update")
+	mockRepo.AssertNotCalled(t, "UpdateVersion")
 }
 
 func TestTransactionService_UpdateTransactionStatusSameStatus(t *testing.T) {
 	mockRepo := new(MockTransactionRepository)
 	service := services.NewTransactionService(mockRepo)
 
-	mockRepo.On("GetByID", uint(1)).Return(&models.Transaction{ID: 1, Status: "success"}, nil)
-	mockRepo.On("Update", uint(1), map[string]interface{}{"status": "success"}).Return(nil)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, Status: "success"}, nil)
+	mockRepo.On("UpdateVersion", uint(1), uint(0), map[string]interface{}{"status": "success"}).Return(nil)
 
-	err := service.UpdateTransactionStatus(1, "success")
+	err := service.UpdateTransactionStatus(context.Background(), 1, "success")
 
 	assert.NoError(t, err) // The service allows setting the same status
 	mockRepo.AssertExpectations(t)
 }
 
+func TestTransactionService_UpdateTransactionStatusInvalidTransition(t *testing.T) {
+	for _, tc := range []struct{ from, to string }{
+		{"success", "pending"},
+		{"success", "failed"},
+		{"failed", "success"},
+		{"failed", "pending"},
+	} {
+		mockRepo := new(MockTransactionRepository)
+		service := services.NewTransactionService(mockRepo)
+
+		mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, Status: tc.from}, nil)
+
+		err := service.UpdateTransactionStatus(context.Background(), 1, tc.to)
+
+		assert.ErrorIs(t, err, services.ErrInvalidTransition, "%s to %s", tc.from, tc.to)
+		mockRepo.AssertNotCalled(t, "UpdateVersion")
+	}
+}
+
 func TestTransactionService_UpdateTransactionStatusGetByIDError(t *testing.T) {
 	mockRepo := new(MockTransactionRepository)
 	service := services.NewTransactionService(mockRepo)
 
 	// Test when GetByID returns an error (not ErrRecordNotFound)
-	mockRepo.On("GetByID", uint(1)).Return((*models.Transaction)(nil), errors.New("database connection error"))
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return((*models.Transaction)(nil), errors.New("database connection error"))
 
-	err := service.UpdateTransactionStatus(1, "success")
+	err := service.UpdateTransactionStatus(context.Background(), 1, "success")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to get transaction")
@@ -317,24 +506,38 @@ func TestTransactionService_UpdateTransactionStatusUpdateError(t *testing.T) {
 		ID: 1, UserID: 1, Amount: decimal.NewFromFloat(100.50), Status: "pending",
 	}
 
-	mockRepo.On("GetByID", uint(1)).Return(existingTx, nil)
-	mockRepo.On("Update", uint(1), map[string]interface{}{"status": "success"}).Return(errors.New("update failed"))
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(existingTx, nil)
+	mockRepo.On("UpdateVersion", uint(1), uint(0), map[string]interface{}{"status": "success"}).Return(errors.New("update failed"))
 
-	err := service.UpdateTransactionStatus(1, "success")
+	err := service.UpdateTransactionStatus(context.Background(), 1, "success")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to update transaction")
 	mockRepo.AssertExpectations(t)
 }
 
+func TestTransactionService_UpdateTransactionStatusVersionConflict(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	// The transaction changed between the read and the guarded write
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, Status: "pending", Version: 3}, nil)
+	mockRepo.On("UpdateVersion", uint(1), uint(3), map[string]interface{}{"status": "success"}).Return(gorm.ErrRecordNotFound)
+
+	err := service.UpdateTransactionStatus(context.Background(), 1, "success")
+
+	assert.ErrorIs(t, err, services.ErrVersionConflict)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestTransactionService_DeleteTransaction(t *testing.T) {
 	mockRepo := new(MockTransactionRepository)
 	service := services.NewTransactionService(mockRepo)
 
-	mockRepo.On("GetByID", uint(1)).Return(&models.Transaction{ID: 1}, nil)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1}, nil)
 	mockRepo.On("Delete", uint(1)).Return(nil)
 
-	err := service.DeleteTransaction(1)
+	err := service.DeleteTransaction(context.Background(), 1)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -344,9 +547,9 @@ func TestTransactionService_DeleteTransactionNotFound(t *testing.T) {
 	mockRepo := new(MockTransactionRepository)
 	service := services.NewTransactionService(mockRepo)
 
-	mockRepo.On("GetByID", uint(1)).Return((*models.Transaction)(nil), gorm.ErrRecordNotFound)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return((*models.Transaction)(nil), gorm.ErrRecordNotFound)
 
-	err := service.DeleteTransaction(1)
+	err := service.DeleteTransaction(context.Background(), 1)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "transaction not found")
@@ -358,10 +561,10 @@ func TestTransactionService_DeleteTransactionError(t *testing.T) {
 	mockRepo := new(MockTransactionRepository)
 	service := services.NewTransactionService(mockRepo)
 
-	mockRepo.On("GetByID", uint(1)).Return(&models.Transaction{ID: 1}, nil)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1}, nil)
 	mockRepo.On("Delete", uint(1)).Return(errors.New("database error"))
 
-	err := service.DeleteTransaction(1)
+	err := service.DeleteTransaction(context.Background(), 1)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to delete transaction")
@@ -372,12 +575,12 @@ func TestTransactionService_UpdateTransactionStatusCheckExistingTransaction(t *t
 	mockRepo := new(MockTransactionRepository)
 	service := services.NewTransactionService(mockRepo)
 
-	mockRepo.On("GetByID", uint(1)).Return(&models.Transaction{
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{
 		ID: 1, UserID: 1, Amount: decimal.NewFromFloat(100.50), Status: "pending",
 	}, nil)
-	mockRepo.On("Update", uint(1), map[string]interface{}{"status": "success"}).Return(nil)
+	mockRepo.On("UpdateVersion", uint(1), uint(0), map[string]interface{}{"status": "success"}).Return(nil)
 
-	err := service.UpdateTransactionStatus(1, "success")
+	err := service.UpdateTransactionStatus(context.Background(), 1, "success")
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -387,26 +590,334 @@ func TestTransactionService_DeleteTransactionCheckExisting(t *testing.T) {
 	mockRepo := new(MockTransactionRepository)
 	service := services.NewTransactionService(mockRepo)
 
-	mockRepo.On("GetByID", uint(1)).Return(&models.Transaction{
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{
 		ID: 1, UserID: 1, Amount: decimal.NewFromFloat(100.50), Status: "pending",
 	}, nil)
 	mockRepo.On("Delete", uint(1)).Return(nil)
 
-	err := service.DeleteTransaction(1)
+	err := service.DeleteTransaction(context.Background(), 1)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestTransactionService_DeleteTransactionRefundPair(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	parentID := uint(1)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, Status: "refunded"}, nil)
+	mockRepo.On("GetByIDFromPrimary", uint(2)).Return(&models.Transaction{ID: 2, Type: models.TransactionTypeRefund, Status: "success", ParentID: &parentID}, nil)
+
+	assert.ErrorIs(t, service.DeleteTransaction(context.Background(), 1), services.ErrNotDeletable)
+	assert.ErrorIs(t, service.DeleteTransaction(context.Background(), 2), services.ErrNotDeletable)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
 func TestTransactionService_DeleteTransaction_GetByIDError(t *testing.T) {
 	mockRepo := new(MockTransactionRepository)
 	service := services.NewTransactionService(mockRepo)
 
-	mockRepo.On("GetByID", uint(1)).Return(nil, errors.New("database error"))
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(nil, errors.New("database error"))
 
-	err := service.DeleteTransaction(1)
+	err := service.DeleteTransaction(context.Background(), 1)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to get transaction")
 	mockRepo.AssertExpectations(t)
 }
+
+func TestTransactionService_LookupTransactions(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	ids := []uint{3, 1, 42, 3}
+	mockRepo.On("GetByIDs", ids).Return([]models.Transaction{
+		{ID: 1, Status: "success"},
+		{ID: 3, Status: "pending"},
+	}, nil)
+
+	result, err := service.LookupTransactions(context.Background(), ids)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{3, 1, 3}, []uint{result.Transactions[0].ID, result.Transactions[1].ID, result.Transactions[2].ID})
+	assert.Equal(t, []uint{42}, result.Missing)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_LookupTransactionsError(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	mockRepo.On("GetByIDs", []uint{1}).Return([]models.Transaction{}, errors.New("database error"))
+
+	result, err := service.LookupTransactions(context.Background(), []uint{1})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "failed to look up transactions")
+}
+
+func TestTransactionService_GetTransactionsWithCount(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	filters := models.TransactionFilters{Status: "success", Limit: 10}
+	mockRepo.On("GetAllWithCount", filters).Return([]models.Transaction{{ID: 1}}, int64(25), nil)
+
+	transactions, total, err := service.GetTransactionsWithCount(context.Background(), filters)
+
+	assert.NoError(t, err)
+	assert.Len(t, transactions, 1)
+	assert.Equal(t, int64(25), total)
+}
+
+func TestTransactionService_GetTransactionsWithCountInvalidStatusFilter(t *testing.T) {
+	service := services.NewTransactionService(new(MockTransactionRepository))
+
+	transactions, total, err := service.GetTransactionsWithCount(context.Background(), models.TransactionFilters{Status: "unknown"})
+
+	assert.EqualError(t, err, "invalid status filter")
+	assert.Nil(t, transactions)
+	assert.Zero(t, total)
+}
+
+func TestTransactionService_GetTransactionsSortOptions(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	for _, field := range []string{"created_at", "amount", "user_id", "status"} {
+		filters := models.TransactionFilters{SortBy: field, Order: "asc"}
+		mockRepo.On("GetAll", filters).Return([]models.Transaction{}, nil).Once()
+
+		_, err := service.GetTransactions(context.Background(), filters)
+		assert.NoError(t, err, field)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_GetTransactionsInvalidSortOptions(t *testing.T) {
+	service := services.NewTransactionService(new(MockTransactionRepository))
+
+	_, err := service.GetTransactions(context.Background(), models.TransactionFilters{SortBy: "id; DROP TABLE transactions"})
+	assert.EqualError(t, err, "invalid sort_by, must be one of created_at, amount, user_id, status")
+
+	_, _, err = service.GetTransactionsWithCount(context.Background(), models.TransactionFilters{Order: "up"})
+	assert.EqualError(t, err, "invalid order, must be asc or desc")
+}
+
+func TestTransactionService_GetTransactionsInvalidMetadataFilter(t *testing.T) {
+	service := services.NewTransactionService(new(MockTransactionRepository))
+
+	_, err := service.GetTransactions(context.Background(), models.TransactionFilters{Metadata: map[string]string{`a"].b`: "x"}})
+	assert.EqualError(t, err, "invalid metadata filter key, must be 1 to 64 letters, digits, underscores or hyphens")
+}
+
+func TestTransactionService_GetTransactionsInvalidDirectionFilter(t *testing.T) {
+	service := services.NewTransactionService(new(MockTransactionRepository))
+
+	_, _, err := service.GetTransactionsWithCount(context.Background(), models.TransactionFilters{Direction: "inbound"})
+	assert.EqualError(t, err, "invalid direction filter")
+}
+
+func TestTransactionService_PatchTransaction(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	bus := events.NewBus()
+	service := services.NewTransactionService(mockRepo, services.WithEventBus(bus))
+	received, cancel := bus.Subscribe()
+	defer cancel()
+
+	status := "success"
+	description := "June invoice"
+	metadata := models.TransactionMetadata{"invoice": "INV-1"}
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, UserID: 7, Status: "pending", Version: 2}, nil).Once()
+	mockRepo.On("UpdateVersion", uint(1), uint(2), map[string]interface{}{
+		"status":      "success",
+		"description": description,
+		"metadata":    metadata,
+	}).Return(nil)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, UserID: 7, Status: "success", Description: description, Metadata: metadata}, nil).Once()
+
+	transaction, err := service.PatchTransaction(context.Background(), 1, models.PatchTransactionRequest{Status: &status, Description: &description, Metadata: metadata})
+
+	assert.NoError(t, err)
+	assert.Equal(t, description, transaction.Description)
+	mockRepo.AssertExpectations(t)
+
+	event := <-received
+	assert.Equal(t, events.TransactionStatusChanged, event.Type)
+	assert.Equal(t, uint(7), event.UserID)
+}
+
+func TestTransactionService_PatchTransactionWithoutStatus(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	bus := events.NewBus()
+	service := services.NewTransactionService(mockRepo, services.WithEventBus(bus))
+	received, cancel := bus.Subscribe()
+	defer cancel()
+
+	description := "June invoice"
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, UserID: 7, Status: "pending", Version: 1}, nil)
+	mockRepo.On("UpdateVersion", uint(1), uint(1), map[string]interface{}{"description": description}).Return(nil)
+
+	_, err := service.PatchTransaction(context.Background(), 1, models.PatchTransactionRequest{Description: &description})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	// The status is unchanged, so the patch is published as an update
+	event := <-received
+	assert.Equal(t, events.TransactionUpdated, event.Type)
+	assert.Equal(t, uint(7), event.UserID)
+	assert.Equal(t, "pending", event.Status)
+}
+
+func TestTransactionService_PatchTransactionConcurrentChange(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	metadata := models.TransactionMetadata{"invoice": "INV-1"}
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, Status: "pending", Version: 4}, nil)
+	mockRepo.On("UpdateVersion", uint(1), uint(4), map[string]interface{}{"metadata": metadata}).Return(gorm.ErrRecordNotFound)
+
+	_, err := service.PatchTransaction(context.Background(), 1, models.PatchTransactionRequest{Metadata: metadata})
+
+	assert.ErrorIs(t, err, services.ErrVersionConflict)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestTransactionService_PatchTransactionInvalid(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	_, err := service.PatchTransaction(context.Background(), 1, models.PatchTransactionRequest{})
+	assert.EqualError(t, err, "no fields to update")
+
+	status := "done"
+	_, err = service.PatchTransaction(context.Background(), 1, models.PatchTransactionRequest{Status: &status})
+	assert.EqualError(t, err, "invalid status")
+
+	mockRepo.AssertNotCalled(t, "GetByID")
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestTransactionService_PatchTransactionInvalidTransition(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	status := "pending"
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, Status: "failed"}, nil)
+
+	_, err := service.PatchTransaction(context.Background(), 1, models.PatchTransactionRequest{Status: &status})
+
+	assert.ErrorIs(t, err, services.ErrInvalidTransition)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestTransactionService_PatchTransactionNotFound(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	description := "x"
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return((*models.Transaction)(nil), gorm.ErrRecordNotFound)
+
+	_, err := service.PatchTransaction(context.Background(), 1, models.PatchTransactionRequest{Description: &description})
+
+	assert.EqualError(t, err, "transaction not found")
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestTransactionService_UpdateTransaction(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	bus := events.NewBus()
+	service := services.NewTransactionService(mockRepo, services.WithEventBus(bus))
+	received, cancel := bus.Subscribe()
+	defer cancel()
+
+	invoice := "INV-2"
+	version := uint(3)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{
+		ID: 1, UserID: 7, Status: "pending", Version: 3,
+		Metadata: models.TransactionMetadata{"invoice": "INV-1", "region": "eu"},
+	}, nil)
+	mockRepo.On("UpdateVersion", uint(1), uint(3), map[string]interface{}{
+		"status":   "failed",
+		"metadata": models.TransactionMetadata{"invoice": "INV-2"},
+	}).Return(nil)
+
+	err := service.UpdateTransaction(context.Background(), 1, models.UpdateTransactionRequest{
+		Status:          "failed",
+		Reason:          "card declined",
+		Metadata:        models.MetadataPatch{"invoice": &invoice, "region": nil},
+		ExpectedVersion: &version,
+	})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	event := <-received
+	assert.Equal(t, events.TransactionStatusChanged, event.Type)
+	assert.Equal(t, "card declined", event.Reason)
+}
+
+func TestTransactionService_UpdateTransactionRejectsCombinations(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	invoice := "INV-2"
+	tests := []struct {
+		name string
+		req  models.UpdateTransactionRequest
+		want error
+	}{
+		{"reason without status", models.UpdateTransactionRequest{Reason: "typo", Metadata: models.MetadataPatch{"invoice": &invoice}}, services.ErrReasonWithoutChange},
+		{"metadata without version", models.UpdateTransactionRequest{Metadata: models.MetadataPatch{"invoice": &invoice}}, services.ErrVersionRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, service.UpdateTransaction(context.Background(), 1, tt.req), tt.want)
+		})
+	}
+
+	assert.EqualError(t, service.UpdateTransaction(context.Background(), 1, models.UpdateTransactionRequest{}), "no fields to update")
+	assert.EqualError(t, service.UpdateTransaction(context.Background(), 1, models.UpdateTransactionRequest{Status: "done"}), "invalid status")
+	mockRepo.AssertNotCalled(t, "GetByID")
+}
+
+func TestTransactionService_UpdateTransactionChecksTransaction(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, Status: "pending", Version: 4}, nil)
+
+	stale := uint(3)
+	err := service.UpdateTransaction(context.Background(), 1, models.UpdateTransactionRequest{Status: "success", ExpectedVersion: &stale})
+	assert.ErrorIs(t, err, services.ErrVersionConflict)
+
+	// A reason explains a change, so giving one for the current status is rejected
+	err = service.UpdateTransaction(context.Background(), 1, models.UpdateTransactionRequest{Status: "pending", Reason: "retry"})
+	assert.ErrorIs(t, err, services.ErrReasonWithoutChange)
+
+	patch := models.MetadataPatch{}
+	for i := 0; i < 21; i++ {
+		value := "x"
+		patch[fmt.Sprintf("key%d", i)] = &value
+	}
+	current := uint(4)
+	err = service.UpdateTransaction(context.Background(), 1, models.UpdateTransactionRequest{Metadata: patch, ExpectedVersion: &current})
+	assert.ErrorIs(t, err, services.ErrTooManyMetadataKeys)
+
+	mockRepo.AssertNotCalled(t, "UpdateVersion")
+}
+
+func TestTransactionService_UpdateTransactionConcurrentChange(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, Status: "pending", Version: 1}, nil)
+	mockRepo.On("UpdateVersion", uint(1), uint(1), map[string]interface{}{"status": "success"}).Return(gorm.ErrRecordNotFound)
+
+	err := service.UpdateTransaction(context.Background(), 1, models.UpdateTransactionRequest{Status: "success"})
+
+	assert.ErrorIs(t, err, services.ErrVersionConflict)
+}
@@ -0,0 +1,95 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"interview/internal/events"
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTransactionService_WaitForStatusChange(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	bus := events.NewBus()
+	service := services.NewTransactionService(mockRepo, services.WithEventBus(bus))
+
+	// Change the status once the service has read the current one
+	mockRepo.On("GetByID", uint(1)).Return(&models.Transaction{ID: 1, Status: "pending"}, nil).Run(func(mock.Arguments) {
+		go func() {
+			bus.Publish(events.Event{Type: events.TransactionStatusChanged, TransactionID: 2, Status: "success"})
+			bus.Publish(events.Event{Type: events.TransactionStatusChanged, TransactionID: 1, Status: "success"})
+		}()
+	}).Once()
+
+	result, err := service.WaitForStatusChange(context.Background(), 1, "", time.Second)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Changed)
+	assert.Equal(t, "success", result.Transaction.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_WaitForStatusChangeAlreadyChanged(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	mockRepo.On("GetByID", uint(1)).Return(&models.Transaction{ID: 1, Status: "failed"}, nil).Once()
+
+	result, err := service.WaitForStatusChange(context.Background(), 1, "pending", time.Second)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Changed)
+	assert.Equal(t, "failed", result.Transaction.Status)
+}
+
+func TestTransactionService_WaitForStatusChangeTimeout(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	mockRepo.On("GetByID", uint(1)).Return(&models.Transaction{ID: 1, Status: "pending"}, nil).Twice()
+
+	result, err := service.WaitForStatusChange(context.Background(), 1, "", 10*time.Millisecond)
+
+	assert.NoError(t, err)
+	assert.False(t, result.Changed)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_WaitForStatusChangeDeleted(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	bus := events.NewBus()
+	service := services.NewTransactionService(mockRepo, services.WithEventBus(bus))
+
+	mockRepo.On("GetByID", uint(1)).Return(&models.Transaction{ID: 1, Status: "pending"}, nil).Run(func(mock.Arguments) {
+		go bus.Publish(events.Event{Type: events.TransactionDeleted, TransactionID: 1})
+	}).Once()
+
+	result, err := service.WaitForStatusChange(context.Background(), 1, "", time.Second)
+
+	assert.EqualError(t, err, "transaction not found")
+	assert.Nil(t, result)
+}
+
+func TestTransactionService_UpdateTransactionStatusPublishesEvent(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	bus := events.NewBus()
+	service := services.NewTransactionService(mockRepo, services.WithEventBus(bus))
+	changes, cancel := bus.Subscribe()
+	defer cancel()
+
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&models.Transaction{ID: 1, UserID: 7, Status: "pending"}, nil)
+	mockRepo.On("UpdateVersion", uint(1), uint(0), map[string]interface{}{"status": "success"}).Return(nil)
+
+	err := service.UpdateTransactionStatus(context.Background(), 1, "success")
+
+	assert.NoError(t, err)
+	event := <-changes
+	assert.Equal(t, events.TransactionStatusChanged, event.Type)
+	assert.Equal(t, uint(7), event.UserID)
+	assert.Equal(t, "success", event.Status)
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"interview/internal/models"
+	"interview/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// ErrUserNotFound is returned when a user does not exist
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrDuplicateEmail is returned when another user already has the email address
+var ErrDuplicateEmail = errors.New("email already in use")
+
+// UserService interface defines user service methods
+type UserService interface {
+	CreateUser(ctx context.Context, req models.CreateUserRequest) (*models.User, error)
+	GetUser(ctx context.Context, id uint) (*models.User, error)
+	GetUsersWithCount(ctx context.Context, filters models.UserFilters) ([]models.User, int64, error)
+	// CheckUser returns ErrUserNotFound unless the user exists
+	CheckUser(ctx context.Context, id uint) error
+}
+
+// userService implements UserService interface
+type userService struct {
+	repo repositories.UserRepository
+}
+
+// NewUserService creates a new user service
+func NewUserService(repo repositories.UserRepository) UserService {
+	return &userService{repo: repo}
+}
+
+// CreateUser creates a new user
+func (s *userService) CreateUser(ctx context.Context, req models.CreateUserRequest) (*models.User, error) {
+	user := &models.User{
+		Name:  strings.TrimSpace(req.Name),
+		Email: strings.ToLower(strings.TrimSpace(req.Email)),
+	}
+
+	if err := s.repo.Create(ctx, user); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateEmail, user.Email)
+		}
+		return nil, fmt.Errorf("failed to create user: %v", err)
+	}
+
+	return user, nil
+}
+
+// GetUser gets a user by ID
+func (s *userService) GetUser(ctx context.Context, id uint) (*models.User, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %v", err)
+	}
+
+	return user, nil
+}
+
+// GetUsersWithCount gets a page of users along with the total number of users
+func (s *userService) GetUsersWithCount(ctx context.Context, filters models.UserFilters) ([]models.User, int64, error) {
+	users, total, err := s.repo.GetAllWithCount(ctx, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get users: %v", err)
+	}
+
+	return users, total, nil
+}
+
+// CheckUser checks that a user exists
+func (s *userService) CheckUser(ctx context.Context, id uint) error {
+	exists, err := s.repo.Exists(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to check user: %v", err)
+	}
+	if !exists {
+		return fmt.Errorf("%w: %d", ErrUserNotFound, id)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/services"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockUserRepository is a mock implementation of UserRepository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, id uint) (*models.User, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetAllWithCount(ctx context.Context, filters models.UserFilters) ([]models.User, int64, error) {
+	args := m.Called(filters)
+	return args.Get(0).([]models.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepository) Exists(ctx context.Context, id uint) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) EnsureExist(ctx context.Context, ids []uint) error {
+	args := m.Called(ids)
+	return args.Error(0)
+}
+
+func TestUserService_CreateUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := services.NewUserService(mockRepo)
+
+	mockRepo.On("Create", &models.User{Name: "Ada Lovelace", Email: "ada@example.com"}).Return(nil).Once()
+	mockRepo.On("Create", mock.AnythingOfType("*models.User")).Return(gorm.ErrDuplicatedKey).Once()
+
+	user, err := service.CreateUser(context.Background(), models.CreateUserRequest{Name: " Ada Lovelace ", Email: "Ada@Example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "ada@example.com", user.Email)
+
+	_, err = service.CreateUser(context.Background(), models.CreateUserRequest{Name: "Ada", Email: "ada@example.com"})
+	assert.ErrorIs(t, err, services.ErrDuplicateEmail)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_GetUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := services.NewUserService(mockRepo)
+
+	mockRepo.On("GetByID", uint(1)).Return(&models.User{ID: 1}, nil)
+	mockRepo.On("GetByID", uint(2)).Return(nil, gorm.ErrRecordNotFound)
+
+	user, err := service.GetUser(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), user.ID)
+
+	_, err = service.GetUser(context.Background(), 2)
+	assert.ErrorIs(t, err, services.ErrUserNotFound)
+}
+
+func TestUserService_CheckUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := services.NewUserService(mockRepo)
+
+	mockRepo.On("Exists", uint(1)).Return(true, nil)
+	mockRepo.On("Exists", uint(2)).Return(false, nil)
+	mockRepo.On("Exists", uint(3)).Return(false, errors.New("db down"))
+
+	assert.NoError(t, service.CheckUser(context.Background(), 1))
+	assert.EqualError(t, service.CheckUser(context.Background(), 2), "user not found: 2")
+	assert.ErrorIs(t, service.CheckUser(context.Background(), 2), services.ErrUserNotFound)
+	assert.EqualError(t, service.CheckUser(context.Background(), 3), "failed to check user: db down")
+}
+
+func TestTransactionService_CreateTransactionUnknownUser(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	mockUsers := new(MockUserRepository)
+	service := services.NewTransactionService(mockRepo, services.WithUserService(services.NewUserService(mockUsers)))
+
+	mockUsers.On("Exists", uint(1)).Return(true, nil)
+	mockUsers.On("Exists", uint(2)).Return(false, nil)
+	mockRepo.On("CreateBatch", mock.MatchedBy(func(txs []models.Transaction) bool {
+		return len(txs) == 1 && txs[0].UserID == 1
+	})).Return(nil)
+
+	_, err := service.CreateTransaction(context.Background(), models.CreateTransactionRequest{UserID: 2, Amount: decimal.NewFromInt(10)})
+	assert.ErrorIs(t, err, services.ErrUserNotFound)
+	mockRepo.AssertNotCalled(t, "Create")
+
+	// Bulk creates report the unknown user per item
+	result, err := service.CreateTransactions(context.Background(), []models.CreateTransactionRequest{
+		{UserID: 1, Amount: decimal.NewFromInt(10)},
+		{UserID: 2, Amount: decimal.NewFromInt(20)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, "user not found: 2", result.Results[1].Error)
+	mockRepo.AssertExpectations(t)
+}
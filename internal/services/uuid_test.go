@@ -0,0 +1,55 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"interview/internal/models"
+	"interview/internal/services"
+	"interview/pkg/utils"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestTransactionService_CreateTransactionAssignsUUID(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	mockRepo.On("Create", mock.MatchedBy(func(tx *models.Transaction) bool {
+		return tx.UUID != nil && utils.IsUUID(*tx.UUID)
+	})).Return(nil).Twice()
+
+	first, err := service.CreateTransaction(context.Background(), models.CreateTransactionRequest{UserID: 1, Amount: decimal.NewFromInt(10)})
+	assert.NoError(t, err)
+	second, err := service.CreateTransaction(context.Background(), models.CreateTransactionRequest{UserID: 1, Amount: decimal.NewFromInt(10)})
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, *first.UUID, *second.UUID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_ResolveTransactionID(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := services.NewTransactionService(mockRepo)
+
+	known := "9b2f6c1e-3d4a-4f5b-8c6d-7e8f9a0b1c2d"
+	missing := "00000000-0000-4000-8000-000000000000"
+	broken := "11111111-1111-4111-8111-111111111111"
+	mockRepo.On("GetByUUID", known).Return(&models.Transaction{ID: 7, UUID: &known}, nil)
+	mockRepo.On("GetByUUID", missing).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("GetByUUID", broken).Return(nil, errors.New("db down"))
+
+	id, err := service.ResolveTransactionID(context.Background(), known)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), id)
+
+	_, err = service.ResolveTransactionID(context.Background(), missing)
+	assert.EqualError(t, err, "transaction not found")
+
+	_, err = service.ResolveTransactionID(context.Background(), broken)
+	assert.EqualError(t, err, "failed to get transaction: db down")
+}
@@ -0,0 +1,90 @@
+// The admin UI talks to the public API of the server that serves it
+const api = "/api/v1";
+
+const message = document.getElementById("message");
+
+function show(text, isError) {
+  message.textContent = text;
+  message.className = isError ? "error" : "";
+}
+
+// request calls the API and returns the data of its standard response
+async function request(method, path, body) {
+  const options = { method, headers: {} };
+  if (body !== undefined) {
+    options.headers["Content-Type"] = "application/json";
+    options.body = JSON.stringify(body);
+  }
+  const response = await fetch(api + path, options);
+  const payload = await response.json();
+  if (!response.ok || !payload.success) {
+    throw new Error(payload.error || response.statusText);
+  }
+  return payload.data;
+}
+
+function cell(row, text) {
+  const td = document.createElement("td");
+  td.textContent = text;
+  row.appendChild(td);
+  return td;
+}
+
+function renderSummary(summary) {
+  document.getElementById("today-count").textContent = summary.today_successful_transactions;
+  document.getElementById("today-amount").textContent = summary.today_successful_amount;
+  document.getElementById("average").textContent = summary.average_transaction_per_user;
+  document.getElementById("pending").textContent = summary.status_counts.pending;
+  document.getElementById("success").textContent = summary.status_counts.success;
+  document.getElementById("failed").textContent = summary.status_counts.failed;
+
+  const latest = document.getElementById("latest");
+  latest.replaceChildren();
+  for (const transaction of summary.latest_transactions || []) {
+    const row = document.createElement("tr");
+    cell(row, transaction.id);
+    cell(row, transaction.user_id);
+    cell(row, transaction.amount + " " + transaction.currency);
+    cell(row, transaction.type);
+    cell(row, transaction.status);
+    cell(row, new Date(transaction.created_at).toLocaleString());
+
+    const actions = cell(row, "");
+    // Only pending transactions can change status
+    if (transaction.status === "pending") {
+      for (const status of ["success", "failed"]) {
+        const button = document.createElement("button");
+        button.type = "button";
+        button.textContent = "Mark " + status;
+        button.addEventListener("click", () => updateStatus(transaction.id, status));
+        actions.appendChild(button);
+      }
+    }
+    latest.appendChild(row);
+  }
+}
+
+async function load() {
+  try {
+    renderSummary(await request("GET", "/dashboard/summary"));
+  } catch (err) {
+    show("Failed to load the dashboard: " + err.message, true);
+  }
+}
+
+async function updateStatus(id, status) {
+  try {
+    await request("PUT", "/transactions/" + id, { status });
+    show("Transaction " + id + " marked " + status + ".", false);
+  } catch (err) {
+    show("Failed to update transaction " + id + ": " + err.message, true);
+    return;
+  }
+  await load();
+}
+
+document.getElementById("refresh").addEventListener("click", () => {
+  show("", false);
+  load();
+});
+load();
@@ -0,0 +1,22 @@
+package ui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// static holds the admin UI: a single page that calls the public API from
+// the browser, so it needs no handlers of its own
+//
+//go:embed static
+var static embed.FS
+
+// Handler serves the admin UI files, with index.html at the root
+func Handler() http.Handler {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+	return http.FileServer(http.FS(files))
+}
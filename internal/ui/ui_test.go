@@ -0,0 +1,38 @@
+package ui_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"interview/internal/ui"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	handler := ui.Handler()
+
+	tests := []struct {
+		path        string
+		contentType string
+		contains    string
+	}{
+		{"/", "text/html; charset=utf-8", "<title>Transactions admin</title>"},
+		{"/app.js", "text/javascript; charset=utf-8", "/dashboard/summary"},
+		{"/style.css", "text/css; charset=utf-8", "border-collapse"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+		assert.Equal(t, http.StatusOK, w.Code, tt.path)
+		assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"), tt.path)
+		assert.Contains(t, w.Body.String(), tt.contains, tt.path)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/missing.js", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
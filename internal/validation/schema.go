@@ -0,0 +1,129 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"interview/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	decimalType     = reflect.TypeOf(decimal.Decimal{})
+	nullDecimalType = reflect.TypeOf(decimal.NullDecimal{})
+	timeType        = reflect.TypeOf(time.Time{})
+)
+
+// Describe returns the schema of the fields of request, a request model
+// struct or a slice of them, from their json or form names and validate
+// tags. Fields named by neither, or named "-", are left out; embedded
+// structs contribute their fields.
+func Describe(request interface{}) []models.FieldSchema {
+	t := reflect.TypeOf(request)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return describeStruct(t)
+}
+
+// describeStruct returns the schema of the fields of the struct type t
+func describeStruct(t reflect.Type) []models.FieldSchema {
+	var fields []models.FieldSchema
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, describeStruct(field.Type)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name, in := fieldName(field)
+		if name == "" {
+			continue
+		}
+		schema := models.FieldSchema{Name: name, In: in, Type: typeName(field.Type)}
+		schema.Required, schema.Rules = rules(field.Tag.Get("validate"))
+		fields = append(fields, schema)
+	}
+	return fields
+}
+
+// fieldName returns the name a request carries field under and where: its
+// json name in the body, or its form name in the query
+func fieldName(field reflect.StructField) (name, in string) {
+	if name := tagName(field.Tag.Get("json")); name != "" {
+		return name, "body"
+	}
+	if name := tagName(field.Tag.Get("form")); name != "" {
+		return name, "query"
+	}
+	return "", ""
+}
+
+// tagName returns the name a json or form tag gives a field, or "" if the
+// tag gives none or leaves the field out
+func tagName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// rules splits a validate tag into its rules, leaving out omitempty. A field
+// is required when it has the required rule before any dive, which applies
+// the rules after it to the field's elements instead.
+func rules(tag string) (required bool, rules []string) {
+	if tag == "" {
+		return false, nil
+	}
+	dived := false
+	for _, rule := range strings.Split(tag, ",") {
+		switch rule {
+		case "omitempty":
+			continue
+		case "dive":
+			dived = true
+		case "required":
+			required = required || !dived
+		}
+		rules = append(rules, rule)
+	}
+	return required, rules
+}
+
+// typeName returns the JSON type a field of type t is given as
+func typeName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case decimalType, nullDecimalType:
+		return "decimal"
+	case timeType:
+		return "datetime"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,39 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"interview/internal/models"
+)
+
+func TestDescribe(t *testing.T) {
+	type embedded struct {
+		Note string `json:"note" validate:"omitempty,max=32"`
+	}
+	type request struct {
+		embedded
+		Amount   decimal.Decimal  `json:"amount" validate:"required,decimal_positive"`
+		Cap      *decimal.Decimal `json:"cap,omitempty"`
+		Tags     []string         `json:"tags" validate:"omitempty,max=3,dive,required,max=8"`
+		Mode     string           `form:"mode" validate:"omitempty,oneof=strict lenient"`
+		Internal string           `json:"-"`
+		Untagged string
+	}
+
+	assert.Equal(t, []models.FieldSchema{
+		{Name: "note", In: "body", Type: "string", Rules: []string{"max=32"}},
+		{Name: "amount", In: "body", Type: "decimal", Required: true, Rules: []string{"required", "decimal_positive"}},
+		{Name: "cap", In: "body", Type: "decimal"},
+		{Name: "tags", In: "body", Type: "array", Rules: []string{"max=3", "dive", "required", "max=8"}},
+		{Name: "mode", In: "query", Type: "string", Rules: []string{"oneof=strict lenient"}},
+	}, Describe(request{}))
+}
+
+func TestDescribe_Slices(t *testing.T) {
+	fields := Describe([]models.CreateUserRequest{})
+	assert.Equal(t, []string{"name", "email"}, []string{fields[0].Name, fields[1].Name})
+	assert.Nil(t, Describe("not a request"))
+}
@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"strconv"
+
+	"interview/pkg/money"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+)
+
+// New creates a validator for request models with the custom decimal
+// validations registered. They accept decimal.Decimal, *decimal.Decimal and
+// decimal.NullDecimal fields; any other field, a nil pointer, a null decimal
+// or a malformed parameter fails validation instead of panicking.
+//
+//   - decimal_nonzero: the value is not zero
+//   - decimal_positive: the value is greater than zero
+//   - decimal_max=<n>: the value is at most n
+//   - decimal_scale=<n>: the value has at most n decimal places
+func New() *validator.Validate {
+	validate := validator.New()
+
+	validate.RegisterValidation("decimal_nonzero", decimalRule(func(value decimal.Decimal, param string) bool {
+		return !value.IsZero()
+	}))
+	validate.RegisterValidation("decimal_positive", decimalRule(func(value decimal.Decimal, param string) bool {
+		return value.IsPositive()
+	}))
+	validate.RegisterValidation("decimal_max", decimalRule(func(value decimal.Decimal, param string) bool {
+		max, err := decimal.NewFromString(param)
+		return err == nil && value.LessThanOrEqual(max)
+	}))
+	validate.RegisterValidation("decimal_scale", decimalRule(func(value decimal.Decimal, param string) bool {
+		scale, err := strconv.ParseInt(param, 10, 32)
+		return err == nil && money.HasScale(value, int32(scale))
+	}))
+
+	return validate
+}
+
+// decimalRule adapts check to a validator.Func, failing fields that hold no
+// decimal
+func decimalRule(check func(value decimal.Decimal, param string) bool) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		value, ok := decimalValue(fl.Field().Interface())
+		return ok && check(value, fl.Param())
+	}
+}
+
+// decimalValue returns the decimal field holds, if it holds one
+func decimalValue(field interface{}) (decimal.Decimal, bool) {
+	switch value := field.(type) {
+	case decimal.Decimal:
+		return value, true
+	case *decimal.Decimal:
+		if value == nil {
+			return decimal.Decimal{}, false
+		}
+		return *value, true
+	case decimal.NullDecimal:
+		return value.Decimal, value.Valid
+	default:
+		return decimal.Decimal{}, false
+	}
+}
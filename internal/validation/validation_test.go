@@ -0,0 +1,94 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// validateField validates a struct with a single field holding value under
+// tag, which is how the rules see request model fields
+func validateField(validate *validator.Validate, value interface{}, tag string) error {
+	field := reflect.StructField{Name: "Field", Type: reflect.TypeOf(value), Tag: reflect.StructTag(`validate:"` + tag + `"`)}
+	request := reflect.New(reflect.StructOf([]reflect.StructField{field})).Elem()
+	request.Field(0).Set(reflect.ValueOf(value))
+	return validate.Struct(request.Interface())
+}
+
+func TestNew_DecimalRules(t *testing.T) {
+	validate := New()
+
+	tests := []struct {
+		tag   string
+		value string
+		valid bool
+	}{
+		{"decimal_nonzero", "-1", true},
+		{"decimal_nonzero", "0", false},
+		{"decimal_positive", "0.01", true},
+		{"decimal_positive", "0", false},
+		{"decimal_positive", "-5", false},
+		{"decimal_max=100", "100", true},
+		{"decimal_max=100", "100.01", false},
+		{"decimal_max=99.5", "99.49", true},
+		{"decimal_scale=2", "10.25", true},
+		{"decimal_scale=2", "10.250", true},
+		{"decimal_scale=2", "10.255", false},
+		{"decimal_scale=0", "10", true},
+		{"decimal_scale=0", "10.5", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag+" "+tt.value, func(t *testing.T) {
+			err := validateField(validate, decimal.RequireFromString(tt.value), tt.tag)
+			assert.Equal(t, tt.valid, err == nil, "%v", err)
+		})
+	}
+}
+
+func TestNew_DecimalFieldTypes(t *testing.T) {
+	validate := New()
+	positive := decimal.NewFromInt(5)
+
+	type request struct {
+		Value    decimal.Decimal     `validate:"decimal_positive"`
+		Pointer  *decimal.Decimal    `validate:"omitempty,decimal_positive"`
+		Nullable decimal.NullDecimal `validate:"decimal_positive"`
+	}
+
+	assert.NoError(t, validate.Struct(request{
+		Value:    positive,
+		Pointer:  &positive,
+		Nullable: decimal.NewNullDecimal(positive),
+	}))
+
+	// A null decimal holds no value to check
+	err := validate.Struct(request{Value: positive})
+	assert.ErrorContains(t, err, "'Nullable' failed on the 'decimal_positive' tag")
+}
+
+func TestNew_NonDecimalFieldsFailWithoutPanicking(t *testing.T) {
+	validate := New()
+
+	type request struct {
+		Amount float64 `validate:"decimal_positive"`
+		Cap    string  `validate:"decimal_max=10"`
+	}
+
+	assert.NotPanics(t, func() {
+		err := validate.Struct(request{Amount: 5, Cap: "5"})
+		assert.ErrorContains(t, err, "'Amount' failed on the 'decimal_positive' tag")
+		assert.ErrorContains(t, err, "'Cap' failed on the 'decimal_max' tag")
+	})
+}
+
+func TestNew_MalformedParamsFail(t *testing.T) {
+	validate := New()
+	value := decimal.NewFromInt(1)
+
+	assert.Error(t, validateField(validate, value, "decimal_max=lots"))
+	assert.Error(t, validateField(validate, value, "decimal_scale=-1"))
+	assert.Error(t, validateField(validate, value, "decimal_scale=two"))
+}
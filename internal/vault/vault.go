@@ -0,0 +1,206 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"interview/internal/config"
+	"interview/internal/database"
+	"interview/pkg/httpclient"
+
+	"github.com/sirupsen/logrus"
+)
+
+// retryDelay is how long to wait before retrying a failed renewal or fetch
+const retryDelay = 10 * time.Second
+
+// Lease is a set of database credentials issued by Vault and the lease that
+// keeps them valid
+type Lease struct {
+	ID        string
+	Duration  time.Duration
+	Renewable bool
+	Username  string
+	Password  string
+}
+
+// Client talks to the Vault HTTP API
+type Client struct {
+	addr  string
+	token string
+	http  *httpclient.Client
+}
+
+// NewClient creates a client for the Vault server at addr, authenticated with token
+func NewClient(addr, token string) (*Client, error) {
+	client, err := httpclient.New(httpclient.DefaultConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &Client{addr: strings.TrimSuffix(addr, "/"), token: token, http: client}, nil
+}
+
+// secretResponse is the part of a Vault secret response the client reads
+type secretResponse struct {
+	LeaseID       string            `json:"lease_id"`
+	LeaseDuration int               `json:"lease_duration"`
+	Renewable     bool              `json:"renewable"`
+	Data          map[string]string `json:"data"`
+}
+
+// DatabaseCredentials requests new credentials for role from the database
+// secrets engine mounted at mount
+func (c *Client) DatabaseCredentials(ctx context.Context, mount, role string) (*Lease, error) {
+	var secret secretResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/"+mount+"/creds/"+role, nil, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get database credentials: %v", err)
+	}
+	if secret.Data["username"] == "" || secret.Data["password"] == "" {
+		return nil, errors.New("failed to get database credentials: response has no username or password")
+	}
+	return &Lease{
+		ID:        secret.LeaseID,
+		Duration:  time.Duration(secret.LeaseDuration) * time.Second,
+		Renewable: secret.Renewable,
+		Username:  secret.Data["username"],
+		Password:  secret.Data["password"],
+	}, nil
+}
+
+// RenewLease extends a lease by increment and returns the duration Vault
+// granted, which is shorter once the lease nears its maximum TTL
+func (c *Client) RenewLease(ctx context.Context, leaseID string, increment time.Duration) (time.Duration, error) {
+	body := map[string]interface{}{"lease_id": leaseID, "increment": int(increment.Seconds())}
+	var secret secretResponse
+	if err := c.do(ctx, http.MethodPut, "/v1/sys/leases/renew", body, &secret); err != nil {
+		return 0, fmt.Errorf("failed to renew lease: %v", err)
+	}
+	return time.Duration(secret.LeaseDuration) * time.Second, nil
+}
+
+// do sends a request to Vault and decodes the JSON response into out
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.addr+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("vault returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// KeepDatabaseCredentials renews lease at two thirds of its duration until
+// stop is called. When the lease cannot be renewed, or Vault grants less
+// than its full duration because it nears its maximum TTL, new credentials
+// are requested and passed to rotate.
+func (c *Client) KeepDatabaseCredentials(lease *Lease, mount, role string, rotate func(*Lease)) (stop func()) {
+	done := make(chan struct{})
+
+	go func() {
+		current := *lease
+		wait := renewAfter(current.Duration)
+		for {
+			select {
+			case <-time.After(wait):
+			case <-done:
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			next, err := c.refresh(ctx, &current, mount, role)
+			cancel()
+			if err != nil {
+				logrus.WithError(err).Error("Failed to refresh database credentials")
+				wait = retryDelay
+				continue
+			}
+			if next.Username != current.Username {
+				logrus.WithField("lease_duration", next.Duration).Info("Rotated database credentials")
+				rotate(next)
+			}
+			current = *next
+			wait = renewAfter(current.Duration)
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// refresh renews lease for its full duration if possible and otherwise
+// requests new credentials
+func (c *Client) refresh(ctx context.Context, lease *Lease, mount, role string) (*Lease, error) {
+	if lease.Renewable && lease.ID != "" {
+		granted, err := c.RenewLease(ctx, lease.ID, lease.Duration)
+		if err == nil && granted >= lease.Duration {
+			renewed := *lease
+			renewed.Duration = granted
+			return &renewed, nil
+		}
+		if err != nil {
+			logrus.WithError(err).Warn("Failed to renew database credentials, requesting new ones")
+		}
+	}
+	return c.DatabaseCredentials(ctx, mount, role)
+}
+
+// renewAfter returns when to renew a lease of the given duration
+func renewAfter(duration time.Duration) time.Duration {
+	if duration <= 0 {
+		return retryDelay
+	}
+	return duration * 2 / 3
+}
+
+// StartDatabaseCredentials requests database credentials as configured and
+// keeps them renewed until stop is called. The returned credentials change
+// whenever Vault issues new ones.
+func StartDatabaseCredentials(cfg config.VaultConfig) (creds *database.Credentials, stop func(), err error) {
+	client, err := NewClient(cfg.Addr, cfg.Token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	lease, err := client.DatabaseCredentials(ctx, cfg.Mount, cfg.Role)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	creds = database.NewCredentials(lease.Username, lease.Password)
+	stop = client.KeepDatabaseCredentials(lease, cfg.Mount, cfg.Role, func(next *Lease) {
+		creds.Set(next.Username, next.Password)
+	})
+	return creds, stop, nil
+}
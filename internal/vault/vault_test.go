@@ -0,0 +1,116 @@
+package vault_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"interview/internal/vault"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVault serves the database secrets engine and lease renewal endpoints.
+// Each credentials request issues a new user; renewals grant maxRenewal.
+type fakeVault struct {
+	issued     int32
+	renewals   int32
+	maxRenewal int
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Vault-Token") != "s.token" {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors":["permission denied"]}`))
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/database/creds/trxgo":
+		n := atomic.AddInt32(&v.issued, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"lease_id":       "database/creds/trxgo/lease",
+			"lease_duration": 3600,
+			"renewable":      true,
+			"data":           map[string]string{"username": fmt.Sprintf("v-trxgo-%d", n), "password": "secret"},
+		})
+	case r.Method == http.MethodPut && r.URL.Path == "/v1/sys/leases/renew":
+		atomic.AddInt32(&v.renewals, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"lease_id":       "database/creds/trxgo/lease",
+			"lease_duration": v.maxRenewal,
+			"renewable":      true,
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestClient_DatabaseCredentials(t *testing.T) {
+	server := httptest.NewServer(&fakeVault{maxRenewal: 3600})
+	defer server.Close()
+
+	client, err := vault.NewClient(server.URL, "s.token")
+	require.NoError(t, err)
+
+	lease, err := client.DatabaseCredentials(t.Context(), "database", "trxgo")
+	require.NoError(t, err)
+	assert.Equal(t, "v-trxgo-1", lease.Username)
+	assert.Equal(t, "secret", lease.Password)
+	assert.Equal(t, time.Hour, lease.Duration)
+	assert.True(t, lease.Renewable)
+
+	granted, err := client.RenewLease(t.Context(), lease.ID, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, granted)
+
+	denied, err := vault.NewClient(server.URL, "s.wrong")
+	require.NoError(t, err)
+	_, err = denied.DatabaseCredentials(t.Context(), "database", "trxgo")
+	assert.ErrorContains(t, err, "permission denied")
+}
+
+func TestClient_KeepDatabaseCredentialsRenews(t *testing.T) {
+	fake := &fakeVault{maxRenewal: 3600}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := vault.NewClient(server.URL, "s.token")
+	require.NoError(t, err)
+
+	rotated := make(chan *vault.Lease, 1)
+	lease := &vault.Lease{ID: "database/creds/trxgo/lease", Duration: 30 * time.Millisecond, Renewable: true, Username: "v-trxgo-0"}
+	stop := client.KeepDatabaseCredentials(lease, "database", "trxgo", func(next *vault.Lease) { rotated <- next })
+	defer stop()
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&fake.renewals) > 0 }, time.Second, 5*time.Millisecond)
+	assert.Zero(t, atomic.LoadInt32(&fake.issued), "a fully renewed lease keeps its credentials")
+	assert.Empty(t, rotated)
+}
+
+func TestClient_KeepDatabaseCredentialsRotatesAtMaxTTL(t *testing.T) {
+	// Vault grants less than requested once the lease nears its maximum TTL
+	fake := &fakeVault{maxRenewal: 0}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := vault.NewClient(server.URL, "s.token")
+	require.NoError(t, err)
+
+	rotated := make(chan *vault.Lease, 1)
+	lease := &vault.Lease{ID: "database/creds/trxgo/lease", Duration: 30 * time.Millisecond, Renewable: true, Username: "v-trxgo-0"}
+	stop := client.KeepDatabaseCredentials(lease, "database", "trxgo", func(next *vault.Lease) { rotated <- next })
+	defer stop()
+
+	select {
+	case next := <-rotated:
+		assert.Equal(t, "v-trxgo-1", next.Username)
+	case <-time.After(time.Second):
+		t.Fatal("Expected credentials to be rotated")
+	}
+}
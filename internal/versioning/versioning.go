@@ -0,0 +1,80 @@
+package versioning
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"interview/pkg/utils"
+)
+
+// API versions. Handlers that change their response shape in a later
+// version check Get and keep serving the older shape to older versions.
+const (
+	V1 = "v1"
+
+	// Default is the version served by the unversioned /api alias when the
+	// client does not ask for one
+	Default = V1
+)
+
+// Supported lists every version the server can serve
+var Supported = []string{V1}
+
+// Header is the request header a client sends to pick a version on the
+// unversioned alias, and the response header naming the version that served it
+const Header = "API-Version"
+
+// contextKey is the gin context key holding the negotiated version
+const contextKey = "api_version"
+
+// Pin serves every request of a route group with version, for the /api/vN mounts
+func Pin(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		set(c, version)
+		c.Next()
+	}
+}
+
+// Negotiate serves each request with the version named in the API-Version
+// header, or Default when there is none. Unsupported versions are rejected
+// with 406 Not Acceptable.
+func Negotiate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version := c.GetHeader(Header)
+		if version == "" {
+			version = Default
+		}
+		if !IsSupported(version) {
+			utils.ErrorResponse(c, http.StatusNotAcceptable, "Unsupported API version "+version)
+			c.Abort()
+			return
+		}
+		set(c, version)
+		c.Next()
+	}
+}
+
+// Get returns the version serving the request
+func Get(c *gin.Context) string {
+	if version := c.GetString(contextKey); version != "" {
+		return version
+	}
+	return Default
+}
+
+// IsSupported reports whether the server can serve version
+func IsSupported(version string) bool {
+	for _, supported := range Supported {
+		if version == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// set records the version serving the request and announces it in the response
+func set(c *gin.Context, version string) {
+	c.Set(contextKey, version)
+	c.Header(Header, version)
+}
@@ -0,0 +1,70 @@
+package versioning_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"interview/internal/versioning"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRouter(middleware gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/test", middleware, func(c *gin.Context) {
+		c.String(http.StatusOK, versioning.Get(c))
+	})
+	return router
+}
+
+func TestPin(t *testing.T) {
+	router := setupRouter(versioning.Pin(versioning.V1))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set(versioning.Header, "v9")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "v1", w.Body.String(), "pinned routes ignore the requested version")
+	assert.Equal(t, "v1", w.Header().Get(versioning.Header))
+}
+
+func TestNegotiate(t *testing.T) {
+	router := setupRouter(versioning.Negotiate())
+
+	for _, requested := range []string{"", "v1"} {
+		req := httptest.NewRequest("GET", "/test", nil)
+		if requested != "" {
+			req.Header.Set(versioning.Header, requested)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, versioning.Default, w.Body.String())
+		assert.Equal(t, versioning.Default, w.Header().Get(versioning.Header))
+	}
+}
+
+func TestNegotiate_UnsupportedVersion(t *testing.T) {
+	router := setupRouter(versioning.Negotiate())
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set(versioning.Header, "v2")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	assert.Contains(t, w.Body.String(), "Unsupported API version v2")
+}
+
+func TestGet_DefaultsWithoutMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	assert.Equal(t, versioning.Default, versioning.Get(c))
+}
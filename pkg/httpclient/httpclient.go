@@ -0,0 +1,176 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when requests to a destination are rejected
+// because it has failed too many times in a row
+var ErrCircuitOpen = errors.New("circuit open")
+
+// Config configures a Client
+type Config struct {
+	// Timeout bounds each attempt, including reading the response headers
+	Timeout time.Duration
+	// MaxRetries is how many times a failed request is retried
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry; it doubles on each
+	// further retry
+	RetryBackoff time.Duration
+	// MaxIdleConnsPerHost is how many idle connections are kept per destination
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept
+	IdleConnTimeout time.Duration
+	// ProxyURL, when set, routes all requests through this proxy. Otherwise
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY are honored.
+	ProxyURL string
+	// BreakerThreshold is how many consecutive failures open the circuit for
+	// a destination; zero disables circuit breaking
+	BreakerThreshold int
+	// BreakerCooldown is how long an open circuit rejects requests before a
+	// single trial request is let through
+	BreakerCooldown time.Duration
+}
+
+// DefaultConfig returns the configuration integrations should start from
+func DefaultConfig() Config {
+	return Config{
+		Timeout:             10 * time.Second,
+		MaxRetries:          2,
+		RetryBackoff:        200 * time.Millisecond,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		BreakerThreshold:    5,
+		BreakerCooldown:     30 * time.Second,
+	}
+}
+
+// DestinationStats holds the counters kept for one destination host
+type DestinationStats struct {
+	Requests     int64         `json:"requests"`
+	Failures     int64         `json:"failures"`
+	Retries      int64         `json:"retries"`
+	Rejected     int64         `json:"rejected"`
+	TotalLatency time.Duration `json:"total_latency"`
+	CircuitOpen  bool          `json:"circuit_open"`
+}
+
+// Client is an HTTP client for outgoing integration calls. It retries
+// transient failures, breaks the circuit to destinations that keep failing
+// and keeps per-destination stats.
+type Client struct {
+	*http.Client
+
+	mu           sync.Mutex
+	destinations map[string]*destination
+}
+
+// destination holds the circuit state and stats for one host
+type destination struct {
+	stats     DestinationStats
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+// New creates a client with the given configuration
+func New(cfg Config) (*Client, error) {
+	proxy := http.ProxyFromEnvironment
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %v", err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	base := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           (&net.Dialer{Timeout: cfg.Timeout, KeepAlive: 30 * time.Second}).DialContext,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   cfg.Timeout,
+		ResponseHeaderTimeout: cfg.Timeout,
+		ForceAttemptHTTP2:     true,
+	}
+
+	c := &Client{destinations: make(map[string]*destination)}
+	c.Client = &http.Client{Transport: &transport{base: base, client: c, cfg: cfg}}
+	return c, nil
+}
+
+// Stats returns a snapshot of the stats for every destination called so far,
+// keyed by host
+func (c *Client) Stats() map[string]DestinationStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	stats := make(map[string]DestinationStats, len(c.destinations))
+	for host, d := range c.destinations {
+		s := d.stats
+		s.CircuitOpen = now.Before(d.openUntil)
+		stats[host] = s
+	}
+	return stats
+}
+
+// destination returns the state for a host, creating it on first use.
+// c.mu must be held.
+func (c *Client) destination(host string) *destination {
+	d, ok := c.destinations[host]
+	if !ok {
+		d = &destination{}
+		c.destinations[host] = d
+	}
+	return d
+}
+
+// allow reports whether a request to the host may be sent
+func (c *Client) allow(host string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d := c.destination(host)
+	if d.openUntil.IsZero() {
+		return true
+	}
+	if time.Now().Before(d.openUntil) || d.trial {
+		d.stats.Rejected++
+		return false
+	}
+	d.trial = true
+	return true
+}
+
+// record updates the stats and circuit state for a finished attempt
+func (c *Client) record(host string, cfg Config, latency time.Duration, retry, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d := c.destination(host)
+	d.stats.Requests++
+	d.stats.TotalLatency += latency
+	if retry {
+		d.stats.Retries++
+	}
+	d.trial = false
+
+	if !failed {
+		d.failures = 0
+		d.openUntil = time.Time{}
+		return
+	}
+
+	d.stats.Failures++
+	d.failures++
+	if cfg.BreakerThreshold > 0 && d.failures >= cfg.BreakerThreshold {
+		d.openUntil = time.Now().Add(cfg.BreakerCooldown)
+	}
+}
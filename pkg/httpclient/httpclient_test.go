@@ -0,0 +1,167 @@
+package httpclient_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"interview/pkg/httpclient"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() httpclient.Config {
+	cfg := httpclient.DefaultConfig()
+	cfg.RetryBackoff = time.Millisecond
+	return cfg
+}
+
+func TestClientRetriesTransientFailures(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := httpclient.New(testConfig())
+	require.NoError(t, err)
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), calls)
+
+	stats := client.Stats()[strings.TrimPrefix(server.URL, "http://")]
+	assert.Equal(t, int64(3), stats.Requests)
+	assert.Equal(t, int64(2), stats.Failures)
+	assert.Equal(t, int64(2), stats.Retries)
+}
+
+func TestClientDoesNotRetryNonIdempotentRequests(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client, err := httpclient.New(testConfig())
+	require.NoError(t, err)
+
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, int32(1), calls)
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{}`))
+	req.Header.Set("Idempotency-Key", "abc")
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, int32(4), calls)
+}
+
+func TestClientRetriesWithoutChangingTheRequest(t *testing.T) {
+	var calls int32
+	var bodies []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := httpclient.New(testConfig())
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest(http.MethodPut, server.URL, strings.NewReader(`{"status":"success"}`))
+	original := req.Body
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, int32(3), calls)
+	assert.Equal(t, []string{`{"status":"success"}`, `{"status":"success"}`, `{"status":"success"}`}, bodies)
+	assert.True(t, req.Body == original, "Expected the caller's request body to be left alone")
+}
+
+func TestClientOpensCircuitAfterRepeatedFailures(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cfg := testConfig()
+	cfg.MaxRetries = 0
+	cfg.BreakerThreshold = 2
+	cfg.BreakerCooldown = 50 * time.Millisecond
+	client, err := httpclient.New(cfg)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	_, err = client.Get(server.URL)
+	assert.True(t, errors.Is(err, httpclient.ErrCircuitOpen))
+	assert.Equal(t, int32(2), calls)
+
+	stats := client.Stats()[strings.TrimPrefix(server.URL, "http://")]
+	assert.True(t, stats.CircuitOpen)
+	assert.Equal(t, int64(1), stats.Rejected)
+
+	// After the cooldown a trial request is let through
+	time.Sleep(60 * time.Millisecond)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, int32(3), calls)
+}
+
+func TestClientUsesProxy(t *testing.T) {
+	var proxied int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&proxied, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	cfg := testConfig()
+	cfg.ProxyURL = proxy.URL
+	client, err := httpclient.New(cfg)
+	require.NoError(t, err)
+
+	resp, err := client.Get("http://example.invalid/rates")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, int32(1), proxied)
+
+	cfg.ProxyURL = "://bad"
+	_, err = httpclient.New(cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid proxy URL")
+}
@@ -0,0 +1,86 @@
+package httpclient
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// transport applies retries, circuit breaking and stats around the base transport
+type transport struct {
+	base   http.RoundTripper
+	client *Client
+	cfg    Config
+}
+
+// RoundTrip sends the request, retrying transient failures when the request
+// can safely be sent again
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	retries := 0
+	if retryable(req) {
+		retries = t.cfg.MaxRetries
+	}
+
+	backoff := t.cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		if !t.client.allow(host) {
+			return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, host)
+		}
+
+		// A RoundTripper must not modify the caller's request, so every
+		// retry sends a clone with a fresh body
+		send := req
+		if attempt > 0 {
+			send = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				send.Body = body
+			}
+		}
+
+		started := time.Now()
+		resp, err := t.base.RoundTrip(send)
+		failed := err != nil || transient(resp.StatusCode)
+		t.client.record(host, t.cfg, time.Since(started), attempt > 0, failed)
+
+		if !failed || attempt >= retries {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// retryable reports whether the request can be sent again without side
+// effects: it must be idempotent or carry an Idempotency-Key, and its body
+// must be replayable
+func retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// transient reports whether a response status is worth retrying
+func transient(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
@@ -0,0 +1,125 @@
+package money
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/shopspring/decimal"
+)
+
+// Locale describes how amounts are written in a locale
+type Locale struct {
+	// Decimal separates the fraction, Group the thousands
+	Decimal string
+	Group   string
+	// SymbolAfter writes the currency symbol after the number
+	SymbolAfter bool
+}
+
+// locales are the locales amounts can be formatted in, by BCP 47 tag
+var locales = map[string]Locale{
+	"en-US": {Decimal: ".", Group: ","},
+	"en-GB": {Decimal: ".", Group: ","},
+	"de-DE": {Decimal: ",", Group: ".", SymbolAfter: true},
+	"fr-FR": {Decimal: ",", Group: "\u202f", SymbolAfter: true},
+	"id-ID": {Decimal: ",", Group: "."},
+	"ja-JP": {Decimal: ".", Group: ","},
+}
+
+// DefaultLocale is the locale of LookupLocale("")
+const DefaultLocale = "en-US"
+
+// symbols are the display symbols of currencies; other currencies are shown
+// by their ISO 4217 code
+var symbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"IDR": "Rp",
+	"JPY": "¥",
+}
+
+// LookupLocale returns the locale with a BCP 47 tag such as "de-DE", or the
+// default locale for ""
+func LookupLocale(tag string) (Locale, error) {
+	if tag == "" {
+		tag = DefaultLocale
+	}
+	locale, ok := locales[tag]
+	if !ok {
+		return Locale{}, fmt.Errorf("unsupported locale %q", tag)
+	}
+	return locale, nil
+}
+
+// Format writes amount in currency for display, such as "$1,234.50" in en-US
+// or "1.234,50 €" in de-DE. It is rounded half up to scale decimal places,
+// which should be the currency's.
+func (l Locale) Format(amount decimal.Decimal, currency string, scale int32) string {
+	amount = HalfUp.Round(amount, scale)
+	integer, fraction, _ := strings.Cut(amount.Abs().StringFixed(scale), ".")
+
+	var b strings.Builder
+	if amount.IsNegative() {
+		b.WriteString("-")
+	}
+	symbol, ok := symbols[currency]
+	if !l.SymbolAfter && ok {
+		b.WriteString(symbol)
+	} else if !l.SymbolAfter {
+		b.WriteString(currency + " ")
+	}
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(l.Group)
+		}
+		b.WriteRune(digit)
+	}
+	if fraction != "" {
+		b.WriteString(l.Decimal + fraction)
+	}
+	if l.SymbolAfter && ok {
+		b.WriteString(" " + symbol)
+	} else if l.SymbolAfter {
+		b.WriteString(" " + currency)
+	}
+	return b.String()
+}
+
+// Parse reads an amount written in the locale, with or without grouping and
+// a currency symbol or code, such as "-$1,234.50" or "1.234,50 €"
+func (l Locale) Parse(value string) (decimal.Decimal, error) {
+	s := strings.TrimFunc(value, isAffix)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimFunc(strings.TrimPrefix(s, "-"), isAffix)
+
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+	if l.Group != "" && !unicode.IsSpace([]rune(l.Group)[0]) {
+		s = strings.ReplaceAll(s, l.Group, "")
+	}
+	s = strings.Replace(s, l.Decimal, ".", 1)
+
+	if s == "" || strings.ContainsAny(s, "+-eE") {
+		return decimal.Zero, fmt.Errorf("invalid amount %q", value)
+	}
+	amount, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid amount %q", value)
+	}
+	if negative {
+		amount = amount.Neg()
+	}
+	return amount, nil
+}
+
+// isAffix reports whether r belongs to a currency symbol or code, or the
+// space around it
+func isAffix(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsSymbol(r) || unicode.IsSpace(r)
+}
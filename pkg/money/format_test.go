@@ -0,0 +1,81 @@
+package money_test
+
+import (
+	"testing"
+
+	"interview/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocale_Format(t *testing.T) {
+	tests := []struct {
+		locale   string
+		amount   string
+		currency string
+		scale    int32
+		want     string
+	}{
+		{"en-US", "1234.5", "USD", 2, "$1,234.50"},
+		{"en-US", "-1234567.891", "USD", 2, "-$1,234,567.89"},
+		{"en-US", "0.005", "USD", 2, "$0.01"},
+		{"en-US", "12", "CHF", 2, "CHF 12.00"},
+		{"de-DE", "1234.5", "EUR", 2, "1.234,50 €"},
+		{"de-DE", "12", "CHF", 2, "12,00 CHF"},
+		{"fr-FR", "1234567.5", "EUR", 2, "1 234 567,50 €"},
+		{"id-ID", "1500000", "IDR", 2, "Rp1.500.000,00"},
+		{"ja-JP", "1234.5", "JPY", 0, "¥1,235"},
+		{"", "999", "USD", 2, "$999.00"},
+	}
+	for _, tt := range tests {
+		locale, err := money.LookupLocale(tt.locale)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, locale.Format(d(tt.amount), tt.currency, tt.scale), tt.locale+" "+tt.amount)
+	}
+
+	_, err := money.LookupLocale("xx-XX")
+	assert.EqualError(t, err, `unsupported locale "xx-XX"`)
+}
+
+func TestLocale_Parse(t *testing.T) {
+	tests := []struct {
+		locale string
+		value  string
+		want   string
+	}{
+		{"en-US", "$1,234.50", "1234.5"},
+		{"en-US", "-$1,234.50", "-1234.5"},
+		{"en-US", "$-5", "-5"},
+		{"en-US", " 42 ", "42"},
+		{"en-US", "CHF 12.00", "12"},
+		{"de-DE", "1.234,50 €", "1234.5"},
+		{"fr-FR", "1 234,50 €", "1234.5"},
+		{"fr-FR", "1 234,50", "1234.5"},
+		{"id-ID", "Rp1.500.000,00", "1500000"},
+	}
+	for _, tt := range tests {
+		locale, err := money.LookupLocale(tt.locale)
+		require.NoError(t, err)
+		amount, err := locale.Parse(tt.value)
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.want, amount.String(), tt.value)
+	}
+
+	locale, _ := money.LookupLocale("en-US")
+	for _, value := range []string{"", "$", "1.2.3", "1e5", "--5", "12abc3"} {
+		_, err := locale.Parse(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestLocale_FormatParseRoundTrip(t *testing.T) {
+	for _, tag := range []string{"en-US", "en-GB", "de-DE", "fr-FR", "id-ID", "ja-JP"} {
+		locale, _ := money.LookupLocale(tag)
+		for _, currency := range []string{"USD", "EUR", "CHF"} {
+			amount, err := locale.Parse(locale.Format(d("-98765.43"), currency, 2))
+			require.NoError(t, err, tag)
+			assert.Equal(t, "-98765.43", amount.String(), tag+" "+currency)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package money
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Rounding is a policy for rounding amounts to a number of decimal places
+type Rounding string
+
+// Rounding policies
+const (
+	// HalfUp rounds halves away from zero: 2.345 becomes 2.35 and -2.345
+	// becomes -2.35
+	HalfUp Rounding = "half_up"
+	// HalfEven rounds halves to the nearest even digit, banker's rounding:
+	// 2.345 becomes 2.34 and 2.355 becomes 2.36. Sums of rounded amounts do
+	// not drift upwards.
+	HalfEven Rounding = "half_even"
+)
+
+// ParseRounding parses a rounding policy name
+func ParseRounding(name string) (Rounding, error) {
+	switch r := Rounding(name); r {
+	case HalfUp, HalfEven:
+		return r, nil
+	}
+	return "", fmt.Errorf("unknown rounding %q, must be %s or %s", name, HalfUp, HalfEven)
+}
+
+// Round rounds amount to scale decimal places
+func (r Rounding) Round(amount decimal.Decimal, scale int32) decimal.Decimal {
+	if r == HalfEven {
+		return amount.RoundBank(scale)
+	}
+	return amount.Round(scale)
+}
+
+// HasScale reports whether amount has at most scale decimal places
+func HasScale(amount decimal.Decimal, scale int32) bool {
+	return scale >= 0 && amount.Equal(amount.Truncate(scale))
+}
+
+// Sum adds amounts
+func Sum(amounts ...decimal.Decimal) decimal.Decimal {
+	if len(amounts) == 0 {
+		return decimal.Zero
+	}
+	return decimal.Sum(amounts[0], amounts[1:]...)
+}
+
+// Percent returns part as a percentage of whole, or zero when whole is zero
+func Percent(part, whole decimal.Decimal) decimal.Decimal {
+	if whole.IsZero() {
+		return decimal.Zero
+	}
+	return part.Div(whole).Mul(decimal.NewFromInt(100))
+}
@@ -0,0 +1,60 @@
+package money_test
+
+import (
+	"testing"
+
+	"interview/pkg/money"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func TestRounding_Round(t *testing.T) {
+	tests := []struct {
+		amount   string
+		halfUp   string
+		halfEven string
+	}{
+		{"2.345", "2.35", "2.34"},
+		{"2.355", "2.36", "2.36"},
+		{"-2.345", "-2.35", "-2.34"},
+		{"2.3449", "2.34", "2.34"},
+		{"2.3", "2.3", "2.3"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.halfUp, money.HalfUp.Round(d(tt.amount), 2).String(), tt.amount)
+		assert.Equal(t, tt.halfEven, money.HalfEven.Round(d(tt.amount), 2).String(), tt.amount)
+	}
+	assert.Equal(t, "2", money.HalfEven.Round(d("2.5"), 0).String())
+	assert.Equal(t, "3", money.HalfUp.Round(d("2.5"), 0).String())
+}
+
+func TestParseRounding(t *testing.T) {
+	r, err := money.ParseRounding("half_even")
+	assert.NoError(t, err)
+	assert.Equal(t, money.HalfEven, r)
+
+	_, err = money.ParseRounding("ceiling")
+	assert.EqualError(t, err, `unknown rounding "ceiling", must be half_up or half_even`)
+}
+
+func TestHasScale(t *testing.T) {
+	assert.True(t, money.HasScale(d("10.5"), 2))
+	assert.True(t, money.HasScale(d("10.50"), 2))
+	assert.True(t, money.HasScale(d("10"), 0))
+	assert.False(t, money.HasScale(d("10.001"), 2))
+	assert.False(t, money.HasScale(d("10.5"), 0))
+	assert.False(t, money.HasScale(d("10"), -1))
+}
+
+func TestSumAndPercent(t *testing.T) {
+	assert.True(t, money.Sum().IsZero())
+	assert.Equal(t, "10.25", money.Sum(d("10"), d("0.3"), d("-0.05")).String())
+
+	assert.Equal(t, "25", money.Percent(d("250"), d("1000")).String())
+	assert.True(t, money.Percent(d("5"), decimal.Zero).IsZero())
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetPaginationLinks adds an RFC 5988 Link header with first, prev and next
+// relations for a limit/offset paginated list
+func SetPaginationLinks(c *gin.Context, limit, offset int, hasMore bool) {
+	if limit <= 0 {
+		return
+	}
+
+	links := []string{paginationLink(c, limit, 0, "first")}
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		links = append(links, paginationLink(c, limit, prev, "prev"))
+	}
+	if hasMore {
+		links = append(links, paginationLink(c, limit, offset+limit, "next"))
+	}
+
+	// Added, to keep the deprecation links of the response
+	c.Writer.Header().Add("Link", strings.Join(links, ", "))
+}
+
+// paginationLink builds a link to the current request with the given page
+func paginationLink(c *gin.Context, limit, offset int, rel string) string {
+	query := c.Request.URL.Query()
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+	return fmt.Sprintf(`<%s?%s>; rel="%s"`, c.Request.URL.Path, query.Encode(), rel)
+}
@@ -0,0 +1,62 @@
+package utils_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"interview/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSetPaginationLinks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		url      string
+		limit    int
+		offset   int
+		hasMore  bool
+		expected string
+	}{
+		{
+			name:     "First full page",
+			url:      "/api/transactions?status=success",
+			limit:    10,
+			offset:   0,
+			hasMore:  true,
+			expected: `</api/transactions?limit=10&offset=0&status=success>; rel="first", </api/transactions?limit=10&offset=10&status=success>; rel="next"`,
+		},
+		{
+			name:     "Middle page",
+			url:      "/api/transactions?limit=10&offset=15",
+			limit:    10,
+			offset:   15,
+			hasMore:  true,
+			expected: `</api/transactions?limit=10&offset=0>; rel="first", </api/transactions?limit=10&offset=5>; rel="prev", </api/transactions?limit=10&offset=25>; rel="next"`,
+		},
+		{
+			name:     "Last page",
+			url:      "/api/transactions?limit=10&offset=20",
+			limit:    10,
+			offset:   20,
+			hasMore:  false,
+			expected: `</api/transactions?limit=10&offset=0>; rel="first", </api/transactions?limit=10&offset=10>; rel="prev"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", tt.url, nil)
+
+			utils.SetPaginationLinks(c, tt.limit, tt.offset, tt.hasMore)
+
+			if link := w.Header().Get("Link"); link != tt.expected {
+				t.Errorf("Expected Link header %s, got %s", tt.expected, link)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+//go:build fastjson
+
+package utils
+
+import (
+	"sync"
+
+	"interview/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// jsonBuffers holds the buffers hand-written encoders write responses to
+var jsonBuffers = sync.Pool{New: func() interface{} {
+	buf := make([]byte, 0, 16<<10)
+	return &buf
+}}
+
+// maxPooledBuffer is the largest buffer returned to jsonBuffers, so one
+// large response does not pin its memory
+const maxPooledBuffer = 1 << 20
+
+// renderJSON writes response with the hand-written encoders of the models
+// package, reporting false when they do not cover its data
+func renderJSON(c *gin.Context, statusCode int, response *models.APIResponse) bool {
+	bufp := jsonBuffers.Get().(*[]byte)
+	buf, ok := response.AppendJSON((*bufp)[:0])
+	if ok {
+		c.Data(statusCode, "application/json; charset=utf-8", buf)
+	}
+	if cap(buf) <= maxPooledBuffer {
+		*bufp = buf
+		jsonBuffers.Put(bufp)
+	}
+	return ok
+}
@@ -2,8 +2,12 @@ package utils
 
 import (
 	"net/http"
+	"sort"
+	"strings"
 
+	"interview/internal/deprecation"
 	"interview/internal/models"
+	"interview/internal/requestid"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,7 +19,18 @@ func SuccessResponse(c *gin.Context, data interface{}, message string) {
 		Data:    data,
 		Message: message,
 	}
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, response)
+}
+
+// PaginatedResponse sends a successful list response with pagination metadata
+func PaginatedResponse(c *gin.Context, data interface{}, pagination *models.Pagination, message string) {
+	response := models.APIResponse{
+		Success:    true,
+		Data:       data,
+		Pagination: pagination,
+		Message:    message,
+	}
+	respond(c, http.StatusOK, response)
 }
 
 // CreatedResponse sends a created response
@@ -25,7 +40,17 @@ func CreatedResponse(c *gin.Context, data interface{}, message string) {
 		Data:    data,
 		Message: message,
 	}
-	c.JSON(http.StatusCreated, response)
+	respond(c, http.StatusCreated, response)
+}
+
+// AcceptedResponse sends an accepted response for work that completes later
+func AcceptedResponse(c *gin.Context, data interface{}, message string) {
+	response := models.APIResponse{
+		Success: true,
+		Data:    data,
+		Message: message,
+	}
+	respond(c, http.StatusAccepted, response)
 }
 
 // ErrorResponse sends an error response
@@ -34,7 +59,17 @@ func ErrorResponse(c *gin.Context, statusCode int, message string) {
 		Success: false,
 		Error:   message,
 	}
-	c.JSON(statusCode, response)
+	respond(c, statusCode, response)
+}
+
+// ErrorCodeResponse sends an error response with a machine-readable code
+func ErrorCodeResponse(c *gin.Context, statusCode int, code, message string) {
+	response := models.APIResponse{
+		Success: false,
+		Error:   message,
+		Code:    code,
+	}
+	respond(c, statusCode, response)
 }
 
 // BadRequestResponse sends a bad request response
@@ -42,6 +77,27 @@ func BadRequestResponse(c *gin.Context, message string) {
 	ErrorResponse(c, http.StatusBadRequest, message)
 }
 
+// ValidationErrorResponse sends a bad request response naming each invalid
+// parameter. The error message lists them too, as "Invalid <name>, <problem>".
+func ValidationErrorResponse(c *gin.Context, fields map[string]string) {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	problems := make([]string, len(names))
+	for i, name := range names {
+		problems[i] = "Invalid " + name + ", " + fields[name]
+	}
+	response := models.APIResponse{
+		Success: false,
+		Error:   strings.Join(problems, "; "),
+		Fields:  fields,
+	}
+	respond(c, http.StatusBadRequest, response)
+}
+
 // NotFoundResponse sends a not found response
 func NotFoundResponse(c *gin.Context, message string) {
 	ErrorResponse(c, http.StatusNotFound, message)
@@ -51,3 +107,13 @@ func NotFoundResponse(c *gin.Context, message string) {
 func InternalServerErrorResponse(c *gin.Context, message string) {
 	ErrorResponse(c, http.StatusInternalServerError, message)
 }
+
+// respond sends response with the ID of the request it answers
+func respond(c *gin.Context, statusCode int, response models.APIResponse) {
+	response.RequestID = c.GetString(requestid.Key)
+	response.Warnings = deprecation.Warnings(c)
+	if renderJSON(c, statusCode, &response) {
+		return
+	}
+	c.JSON(statusCode, response)
+}
@@ -1,13 +1,17 @@
 package utils_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"interview/internal/models"
 	"interview/pkg/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 )
 
 func TestSuccessResponse(t *testing.T) {
@@ -42,6 +46,18 @@ func TestCreatedResponse(t *testing.T) {
 	}
 }
 
+func TestAcceptedResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	utils.AcceptedResponse(c, map[string]string{"id": "job-1"}, "Accepted")
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected status code %d, got %d", http.StatusAccepted, w.Code)
+	}
+}
+
 func TestErrorResponse(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
@@ -66,6 +82,22 @@ func TestBadRequestResponse(t *testing.T) {
 	}
 }
 
+func TestValidationErrorResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	utils.ValidationErrorResponse(c, map[string]string{"mode": "must be strict or lenient"})
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	expected := `{"success":false,"error":"Invalid mode, must be strict or lenient","fields":{"mode":"must be strict or lenient"}}`
+	if w.Body.String() != expected {
+		t.Errorf("Expected body %s, got %s", expected, w.Body.String())
+	}
+}
+
 func TestNotFoundResponse(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
@@ -89,3 +121,54 @@ func TestInternalServerErrorResponse(t *testing.T) {
 		t.Errorf("Expected status code %d, got %d", http.StatusInternalServerError, w.Code)
 	}
 }
+
+// transactionPage returns a full page of transactions as the list endpoint
+// sends them
+func transactionPage() []models.Transaction {
+	created := time.Date(2024, 6, 15, 12, 30, 0, 0, time.UTC)
+	transactions := make([]models.Transaction, models.DefaultPageSize)
+	for i := range transactions {
+		transactions[i] = models.Transaction{
+			ID: uint(i + 1), UserID: 1, Amount: decimal.NewFromFloat(99.95), Currency: "USD",
+			Type: "payment", Direction: "debit", Status: "success", Description: "Coffee & snacks",
+			Metadata: models.TransactionMetadata{"invoice": "INV-1"}, Version: 1,
+			Tags: []models.Tag{{Name: "food"}}, CreatedAt: created, UpdatedAt: created,
+		}
+	}
+	return transactions
+}
+
+func TestPaginatedResponse_Transactions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	transactions := transactionPage()
+	pagination := models.NewPagination(40, 20, 0, len(transactions))
+	utils.PaginatedResponse(c, transactions, pagination, "Transactions retrieved successfully")
+
+	// Builds with and without the fastjson tag send the same bytes
+	expected, _ := json.Marshal(models.APIResponse{Success: true, Data: transactions, Pagination: pagination, Message: "Transactions retrieved successfully"})
+	if w.Body.String() != string(expected) {
+		t.Errorf("Expected body %s, got %s", expected, w.Body.String())
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/json; charset=utf-8" {
+		t.Errorf("Expected content type application/json; charset=utf-8, got %s", contentType)
+	}
+}
+
+// BenchmarkPaginatedResponse measures a transaction list response; compare
+// -benchmem with and without -tags fastjson
+func BenchmarkPaginatedResponse(b *testing.B) {
+	gin.SetMode(gin.TestMode)
+	transactions := transactionPage()
+	pagination := models.NewPagination(40, 20, 0, len(transactions))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		w.Body.Reset()
+		utils.PaginatedResponse(c, transactions, pagination, "Transactions retrieved successfully")
+	}
+}
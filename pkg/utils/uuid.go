@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"crypto/rand"
+	"fmt"
+	"regexp"
+)
+
+// uuidPattern matches the canonical lower or upper case form of a UUID
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// NewUUID returns a random (version 4) UUID in canonical form
+func NewUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("failed to read random bytes: %v", err))
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// IsUUID reports whether s is a UUID in canonical form
+func IsUUID(s string) bool {
+	return uuidPattern.MatchString(s)
+}
@@ -0,0 +1,27 @@
+package utils_test
+
+import (
+	"testing"
+
+	"interview/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUUID(t *testing.T) {
+	first := utils.NewUUID()
+	second := utils.NewUUID()
+
+	assert.True(t, utils.IsUUID(first), first)
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, byte('4'), first[14], "version nibble")
+	assert.Contains(t, "89ab", string(first[19]), "variant nibble")
+}
+
+func TestIsUUID(t *testing.T) {
+	assert.True(t, utils.IsUUID("9b2f6c1e-3d4a-4f5b-8c6d-7e8f9a0b1c2d"))
+	assert.True(t, utils.IsUUID("9B2F6C1E-3D4A-4F5B-8C6D-7E8F9A0B1C2D"))
+	assert.False(t, utils.IsUUID("42"))
+	assert.False(t, utils.IsUUID("9b2f6c1e3d4a4f5b8c6d7e8f9a0b1c2d"))
+	assert.False(t, utils.IsUUID("9b2f6c1e-3d4a-4f5b-8c6d-7e8f9a0b1c2"))
+}
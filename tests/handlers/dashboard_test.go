@@ -1,6 +1,7 @@
 package handlers_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -21,7 +22,7 @@ type MockDashboardService struct {
 	mock.Mock
 }
 
-func (m *MockDashboardService) GetSummary() (*models.DashboardSummary, error) {
+func (m *MockDashboardService) GetSummary(ctx context.Context) (*models.DashboardSummary, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -29,6 +30,30 @@ func (m *MockDashboardService) GetSummary() (*models.DashboardSummary, error) {
 	return args.Get(0).(*models.DashboardSummary), args.Error(1)
 }
 
+func (m *MockDashboardService) GetPartialSummary(ctx context.Context) (*models.DashboardSummary, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DashboardSummary), args.Error(1)
+}
+
+func (m *MockDashboardService) GetPeriodSummary(ctx context.Context, period models.SummaryPeriod, partial bool) (*models.DashboardSummary, error) {
+	args := m.Called(period, partial)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DashboardSummary), args.Error(1)
+}
+
+func (m *MockDashboardService) GetTopUsers(ctx context.Context, period models.SummaryPeriod, sortBy string, limit int) (*models.TopUsers, error) {
+	args := m.Called(period, sortBy, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TopUsers), args.Error(1)
+}
+
 func TestDashboardHandler_GetSummary(t *testing.T) {
 	mockService := new(MockDashboardService)
 	handler := handlers.NewDashboardHandler(mockService)
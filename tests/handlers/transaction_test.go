@@ -2,11 +2,13 @@ package handlers_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"interview/internal/handlers"
 	"interview/internal/models"
@@ -22,7 +24,7 @@ type MockTransactionService struct {
 	mock.Mock
 }
 
-func (m *MockTransactionService) CreateTransaction(req models.CreateTransactionRequest) (*models.Transaction, error) {
+func (m *MockTransactionService) CreateTransaction(ctx context.Context, req models.CreateTransactionRequest) (*models.Transaction, error) {
 	args := m.Called(req)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -30,7 +32,15 @@ func (m *MockTransactionService) CreateTransaction(req models.CreateTransactionR
 	return args.Get(0).(*models.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionService) GetTransaction(id uint) (*models.Transaction, error) {
+func (m *MockTransactionService) CreateTransactions(ctx context.Context, reqs []models.CreateTransactionRequest) (*models.BulkCreateResult, error) {
+	args := m.Called(reqs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BulkCreateResult), args.Error(1)
+}
+
+func (m *MockTransactionService) GetTransaction(ctx context.Context, id uint) (*models.Transaction, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -38,21 +48,94 @@ func (m *MockTransactionService) GetTransaction(id uint) (*models.Transaction, e
 	return args.Get(0).(*models.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionService) GetTransactions(filters models.TransactionFilters) ([]models.Transaction, error) {
+func (m *MockTransactionService) GetTransactionByReference(ctx context.Context, referenceID string) (*models.Transaction, error) {
+	args := m.Called(referenceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) ResolveTransactionID(ctx context.Context, uuid string) (uint, error) {
+	args := m.Called(uuid)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockTransactionService) GetTransactionStatus(ctx context.Context, id uint) (*models.TransactionStatus, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TransactionStatus), args.Error(1)
+}
+
+func (m *MockTransactionService) GetTransactions(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, error) {
 	args := m.Called(filters)
 	return args.Get(0).([]models.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionService) UpdateTransactionStatus(id uint, status string) error {
+func (m *MockTransactionService) ExportTransactions(ctx context.Context, filters models.TransactionFilters, fn func(*models.Transaction) error) error {
+	args := m.Called(filters)
+	for _, transaction := range args.Get(0).([]models.Transaction) {
+		if err := fn(&transaction); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTransactionService) GetTransactionsWithCount(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, int64, error) {
+	args := m.Called(filters)
+	return args.Get(0).([]models.Transaction), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTransactionService) LookupTransactions(ctx context.Context, ids []uint) (*models.TransactionLookupResult, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TransactionLookupResult), args.Error(1)
+}
+
+func (m *MockTransactionService) UpdateTransactionStatus(ctx context.Context, id uint, status string) error {
 	args := m.Called(id, status)
 	return args.Error(0)
 }
 
-func (m *MockTransactionService) DeleteTransaction(id uint) error {
+func (m *MockTransactionService) UpdateTransaction(ctx context.Context, id uint, req models.UpdateTransactionRequest) error {
+	args := m.Called(id, req)
+	return args.Error(0)
+}
+
+func (m *MockTransactionService) PatchTransaction(ctx context.Context, id uint, req models.PatchTransactionRequest) (*models.Transaction, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) DeleteTransaction(ctx context.Context, id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+func (m *MockTransactionService) RefundTransaction(ctx context.Context, id uint) (*models.Transaction, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) WaitForStatusChange(ctx context.Context, id uint, lastStatus string, timeout time.Duration) (*models.TransactionWaitResult, error) {
+	args := m.Called(ctx, id, lastStatus, timeout)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TransactionWaitResult), args.Error(1)
+}
+
 func setupTestRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	return gin.New()
@@ -183,7 +266,7 @@ func TestTransactionHandler_GetTransactions(t *testing.T) {
 		{ID: 2, UserID: 2, Amount: decimal.NewFromFloat(200.00), Status: "success"},
 	}
 
-	mockService.On("GetTransactions", mock.AnythingOfType("models.TransactionFilters")).Return(expectedTxs, nil)
+	mockService.On("GetTransactionsWithCount", mock.AnythingOfType("models.TransactionFilters")).Return(expectedTxs, int64(len(expectedTxs)), nil)
 
 	w := httptest.NewRecorder()
 	httpReq, _ := http.NewRequest("GET", "/transactions", nil)
@@ -287,7 +370,7 @@ func TestTransactionHandler_UpdateTransaction(t *testing.T) {
 		Status: "success",
 	}
 
-	mockService.On("UpdateTransactionStatus", uint(1), "success").Return(nil)
+	mockService.On("UpdateTransaction", uint(1), models.UpdateTransactionRequest{Status: "success"}).Return(nil)
 
 	reqBody, _ := json.Marshal(req)
 	w := httptest.NewRecorder()
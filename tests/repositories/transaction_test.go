@@ -1,6 +1,7 @@
 package repositories_test
 
 import (
+	"context"
 	"testing"
 
 	"interview/internal/models"
@@ -49,7 +50,7 @@ func (suite *TransactionRepositoryTestSuite) TestCreate() {
 		Status: "pending",
 	}
 
-	err := suite.repo.Create(transaction)
+	err := suite.repo.Create(context.Background(), transaction)
 	assert.NoError(suite.T(), err)
 	assert.NotZero(suite.T(), transaction.ID)
 }
@@ -61,11 +62,11 @@ func (suite *TransactionRepositoryTestSuite) TestGetByID() {
 		Amount: decimal.NewFromFloat(100.50),
 		Status: "pending",
 	}
-	err := suite.repo.Create(transaction)
+	err := suite.repo.Create(context.Background(), transaction)
 	assert.NoError(suite.T(), err)
 
 	// Get the transaction
-	result, err := suite.repo.GetByID(transaction.ID)
+	result, err := suite.repo.GetByID(context.Background(), transaction.ID)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), transaction.ID, result.ID)
 	assert.Equal(suite.T(), transaction.UserID, result.UserID)
@@ -81,25 +82,25 @@ func (suite *TransactionRepositoryTestSuite) TestGetAll() {
 	}
 
 	for _, tx := range transactions {
-		err := suite.repo.Create(tx)
+		err := suite.repo.Create(context.Background(), tx)
 		assert.NoError(suite.T(), err)
 	}
 
 	// Test getting all transactions
 	filters := models.TransactionFilters{Limit: 10}
-	results, err := suite.repo.GetAll(filters)
+	results, err := suite.repo.GetAll(context.Background(), filters)
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), results, 3)
 
 	// Test filtering by user ID
 	filters = models.TransactionFilters{UserID: 1, Limit: 10}
-	results, err = suite.repo.GetAll(filters)
+	results, err = suite.repo.GetAll(context.Background(), filters)
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), results, 2)
 
 	// Test filtering by status
 	filters = models.TransactionFilters{Status: "pending", Limit: 10}
-	results, err = suite.repo.GetAll(filters)
+	results, err = suite.repo.GetAll(context.Background(), filters)
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), results, 2)
 }
@@ -111,18 +112,18 @@ func (suite *TransactionRepositoryTestSuite) TestUpdate() {
 		Amount: decimal.NewFromFloat(100.50),
 		Status: "pending",
 	}
-	err := suite.repo.Create(transaction)
+	err := suite.repo.Create(context.Background(), transaction)
 	assert.NoError(suite.T(), err)
 
 	// Update the transaction
 	updates := map[string]interface{}{
 		"status": "success",
 	}
-	err = suite.repo.Update(transaction.ID, updates)
+	err = suite.repo.Update(context.Background(), transaction.ID, updates)
 	assert.NoError(suite.T(), err)
 
 	// Verify the update
-	result, err := suite.repo.GetByID(transaction.ID)
+	result, err := suite.repo.GetByID(context.Background(), transaction.ID)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), "success", result.Status)
 }
@@ -134,15 +135,15 @@ func (suite *TransactionRepositoryTestSuite) TestDelete() {
 		Amount: decimal.NewFromFloat(100.50),
 		Status: "pending",
 	}
-	err := suite.repo.Create(transaction)
+	err := suite.repo.Create(context.Background(), transaction)
 	assert.NoError(suite.T(), err)
 
 	// Delete the transaction
-	err = suite.repo.Delete(transaction.ID)
+	err = suite.repo.Delete(context.Background(), transaction.ID)
 	assert.NoError(suite.T(), err)
 
 	// Verify the deletion
-	_, err = suite.repo.GetByID(transaction.ID)
+	_, err = suite.repo.GetByID(context.Background(), transaction.ID)
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), gorm.ErrRecordNotFound, err)
 }
@@ -156,10 +157,10 @@ func (suite *TransactionRepositoryTestSuite) TestGetTodaySuccessful() {
 	}
 
 	for _, tx := range transactions {
-		suite.repo.Create(tx)
+		suite.repo.Create(context.Background(), tx)
 	}
 
-	count, amount, err := suite.repo.GetTodaySuccessful()
+	count, amount, err := suite.repo.GetTodaySuccessful(context.Background())
 
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), 2, count)
@@ -167,7 +168,7 @@ func (suite *TransactionRepositoryTestSuite) TestGetTodaySuccessful() {
 }
 
 func (suite *TransactionRepositoryTestSuite) TestGetTodaySuccessfulNoTransactions() {
-	count, amount, err := suite.repo.GetTodaySuccessful()
+	count, amount, err := suite.repo.GetTodaySuccessful(context.Background())
 
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), 0, count)
@@ -183,17 +184,17 @@ func (suite *TransactionRepositoryTestSuite) TestGetAveragePerUser() {
 	}
 
 	for _, tx := range transactions {
-		suite.repo.Create(tx)
+		suite.repo.Create(context.Background(), tx)
 	}
 
-	average, err := suite.repo.GetAveragePerUser()
+	average, err := suite.repo.GetAveragePerUser(context.Background())
 
 	assert.NoError(suite.T(), err)
 	assert.True(suite.T(), decimal.NewFromFloat(1.5).Equal(average)) // User 1 has 2, User 2 has 1, avg = 1.5
 }
 
 func (suite *TransactionRepositoryTestSuite) TestGetAveragePerUserNoTransactions() {
-	average, err := suite.repo.GetAveragePerUser()
+	average, err := suite.repo.GetAveragePerUser(context.Background())
 
 	assert.NoError(suite.T(), err)
 	assert.True(suite.T(), decimal.Zero.Equal(average))
@@ -208,10 +209,10 @@ func (suite *TransactionRepositoryTestSuite) TestGetLatest() {
 	}
 
 	for _, tx := range transactions {
-		suite.repo.Create(tx)
+		suite.repo.Create(context.Background(), tx)
 	}
 
-	latest, err := suite.repo.GetLatest(2)
+	latest, err := suite.repo.GetLatest(context.Background(), 2)
 
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), latest, 2)
@@ -221,7 +222,7 @@ func (suite *TransactionRepositoryTestSuite) TestGetLatest() {
 }
 
 func (suite *TransactionRepositoryTestSuite) TestGetLatestEmpty() {
-	latest, err := suite.repo.GetLatest(5)
+	latest, err := suite.repo.GetLatest(context.Background(), 5)
 
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), latest, 0)
@@ -237,10 +238,10 @@ func (suite *TransactionRepositoryTestSuite) TestGetStatusCounts() {
 	}
 
 	for _, tx := range transactions {
-		suite.repo.Create(tx)
+		suite.repo.Create(context.Background(), tx)
 	}
 
-	counts, err := suite.repo.GetStatusCounts()
+	counts, err := suite.repo.GetStatusCounts(context.Background())
 
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), 2, counts.Success)
@@ -249,7 +250,7 @@ func (suite *TransactionRepositoryTestSuite) TestGetStatusCounts() {
 }
 
 func (suite *TransactionRepositoryTestSuite) TestGetStatusCountsEmpty() {
-	counts, err := suite.repo.GetStatusCounts()
+	counts, err := suite.repo.GetStatusCounts(context.Background())
 
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), 0, counts.Success)
@@ -266,7 +267,7 @@ func (suite *TransactionRepositoryTestSuite) TestGetAllWithLimitAndOffset() {
 			Amount: decimal.NewFromInt(int64(i * 100)),
 			Status: "success",
 		}
-		suite.repo.Create(tx)
+		suite.repo.Create(context.Background(), tx)
 	}
 
 	// Test with limit and offset
@@ -275,7 +276,7 @@ func (suite *TransactionRepositoryTestSuite) TestGetAllWithLimitAndOffset() {
 		Offset: 2,
 	}
 
-	transactions, err := suite.repo.GetAll(filters)
+	transactions, err := suite.repo.GetAll(context.Background(), filters)
 
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), transactions, 2)
@@ -290,7 +291,7 @@ func (suite *TransactionRepositoryTestSuite) TestGetAllWithUserIDFilter() {
 	}
 
 	for _, tx := range transactions {
-		suite.repo.Create(tx)
+		suite.repo.Create(context.Background(), tx)
 	}
 
 	// Test with UserID filter
@@ -298,7 +299,7 @@ func (suite *TransactionRepositoryTestSuite) TestGetAllWithUserIDFilter() {
 		UserID: 1,
 	}
 
-	result, err := suite.repo.GetAll(filters)
+	result, err := suite.repo.GetAll(context.Background(), filters)
 
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), result, 2)
@@ -316,7 +317,7 @@ func (suite *TransactionRepositoryTestSuite) TestGetAllWithStatusFilter() {
 	}
 
 	for _, tx := range transactions {
-		suite.repo.Create(tx)
+		suite.repo.Create(context.Background(), tx)
 	}
 
 	// Test with Status filter
@@ -324,7 +325,7 @@ func (suite *TransactionRepositoryTestSuite) TestGetAllWithStatusFilter() {
 		Status: "success",
 	}
 
-	result, err := suite.repo.GetAll(filters)
+	result, err := suite.repo.GetAll(context.Background(), filters)
 
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), result, 2)
@@ -343,7 +344,7 @@ func (suite *TransactionRepositoryTestSuite) TestGetAllWithAllFilters() {
 	}
 
 	for _, tx := range transactions {
-		suite.repo.Create(tx)
+		suite.repo.Create(context.Background(), tx)
 	}
 
 	// Test with all filters
@@ -354,7 +355,7 @@ func (suite *TransactionRepositoryTestSuite) TestGetAllWithAllFilters() {
 		Offset: 0,
 	}
 
-	result, err := suite.repo.GetAll(filters)
+	result, err := suite.repo.GetAll(context.Background(), filters)
 
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), result, 1) // Limited to 1
@@ -1,8 +1,10 @@
 package services_test
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"interview/internal/models"
 	"interview/internal/services"
@@ -18,12 +20,25 @@ type MockTransactionRepository struct {
 	mock.Mock
 }
 
-func (m *MockTransactionRepository) Create(tx *models.Transaction) error {
+func (m *MockTransactionRepository) Create(ctx context.Context, tx *models.Transaction) error {
 	args := m.Called(tx)
 	return args.Error(0)
 }
 
-func (m *MockTransactionRepository) GetByID(id uint) (*models.Transaction, error) {
+func (m *MockTransactionRepository) CreateBatch(ctx context.Context, transactions []models.Transaction) error {
+	args := m.Called(transactions)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) GetByID(ctx context.Context, id uint) (*models.Transaction, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetByIDFromPrimary(ctx context.Context, id uint) (*models.Transaction, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -31,41 +46,140 @@ func (m *MockTransactionRepository) GetByID(id uint) (*models.Transaction, error
 	return args.Get(0).(*models.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionRepository) GetAll(filters models.TransactionFilters) ([]models.Transaction, error) {
+func (m *MockTransactionRepository) GetByIDs(ctx context.Context, ids []uint) ([]models.Transaction, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetByReferenceID(ctx context.Context, referenceID string) (*models.Transaction, error) {
+	args := m.Called(referenceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetByReferenceIDs(ctx context.Context, referenceIDs []string) ([]models.Transaction, error) {
+	args := m.Called(referenceIDs)
+	return args.Get(0).([]models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) Each(ctx context.Context, filters models.TransactionFilters, fn func(*models.Transaction) error) error {
+	args := m.Called(filters)
+	for _, transaction := range args.Get(0).([]models.Transaction) {
+		if err := fn(&transaction); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetByUUID(ctx context.Context, uuid string) (*models.Transaction, error) {
+	args := m.Called(uuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetAll(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, error) {
 	args := m.Called(filters)
 	return args.Get(0).([]models.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionRepository) Update(id uint, updates map[string]interface{}) error {
+func (m *MockTransactionRepository) GetAllWithCount(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, int64, error) {
+	args := m.Called(filters)
+	return args.Get(0).([]models.Transaction), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTransactionRepository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
 	args := m.Called(id, updates)
 	return args.Error(0)
 }
 
-func (m *MockTransactionRepository) Delete(id uint) error {
+func (m *MockTransactionRepository) UpdateVersion(ctx context.Context, id, version uint, updates map[string]interface{}) error {
+	args := m.Called(id, version, updates)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) Delete(ctx context.Context, id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockTransactionRepository) GetTodaySuccessful() (int, decimal.Decimal, error) {
+func (m *MockTransactionRepository) Refund(ctx context.Context, parentID uint, refund *models.Transaction) error {
+	args := m.Called(parentID, refund)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) GetTodaySuccessful(ctx context.Context) (int, decimal.Decimal, error) {
 	args := m.Called()
 	return args.Int(0), args.Get(1).(decimal.Decimal), args.Error(2)
 }
 
-func (m *MockTransactionRepository) GetAveragePerUser() (decimal.Decimal, error) {
+func (m *MockTransactionRepository) GetAveragePerUser(ctx context.Context) (decimal.Decimal, error) {
 	args := m.Called()
 	return args.Get(0).(decimal.Decimal), args.Error(1)
 }
 
-func (m *MockTransactionRepository) GetLatest(limit int) ([]models.Transaction, error) {
+func (m *MockTransactionRepository) GetLatest(ctx context.Context, limit int) ([]models.Transaction, error) {
 	args := m.Called(limit)
 	return args.Get(0).([]models.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionRepository) GetStatusCounts() (models.StatusCounts, error) {
+func (m *MockTransactionRepository) GetStatusCounts(ctx context.Context) (models.StatusCounts, error) {
 	args := m.Called()
 	return args.Get(0).(models.StatusCounts), args.Error(1)
 }
 
+func (m *MockTransactionRepository) GetDirectionTotals(ctx context.Context) (models.DirectionTotals, error) {
+	args := m.Called()
+	return args.Get(0).(models.DirectionTotals), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetSuccessfulBetween(ctx context.Context, from, to time.Time) (int, decimal.Decimal, error) {
+	args := m.Called(from, to)
+	return args.Int(0), args.Get(1).(decimal.Decimal), args.Error(2)
+}
+
+func (m *MockTransactionRepository) GetStatusCountsBetween(ctx context.Context, from, to time.Time) (models.StatusCounts, error) {
+	args := m.Called(from, to)
+	return args.Get(0).(models.StatusCounts), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetDirectionTotalsBetween(ctx context.Context, from, to time.Time) (models.DirectionTotals, error) {
+	args := m.Called(from, to)
+	return args.Get(0).(models.DirectionTotals), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetCurrencyTotals(ctx context.Context) ([]models.CurrencyTotal, error) {
+	args := m.Called()
+	return args.Get(0).([]models.CurrencyTotal), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetCurrencyTotalsBetween(ctx context.Context, from, to time.Time) ([]models.CurrencyTotal, error) {
+	args := m.Called(from, to)
+	return args.Get(0).([]models.CurrencyTotal), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetAmountDistribution(ctx context.Context) (models.AmountDistribution, error) {
+	args := m.Called()
+	return args.Get(0).(models.AmountDistribution), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetAmountDistributionBetween(ctx context.Context, from, to time.Time) (models.AmountDistribution, error) {
+	args := m.Called(from, to)
+	return args.Get(0).(models.AmountDistribution), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetTopUsersBetween(ctx context.Context, from, to time.Time, sortBy string, limit int) ([]models.UserVolume, error) {
+	args := m.Called(from, to, sortBy, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.UserVolume), args.Error(1)
+}
+
 func TestTransactionService_CreateTransaction(t *testing.T) {
 	mockRepo := new(MockTransactionRepository)
 	service := services.NewTransactionService(mockRepo)
@@ -80,7 +194,7 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 		tx.ID = 1
 	})
 
-	result, err := service.CreateTransaction(req)
+	result, err := service.CreateTransaction(context.Background(), req)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -101,7 +215,7 @@ func TestTransactionService_CreateTransaction_Error(t *testing.T) {
 
 	mockRepo.On("Create", mock.AnythingOfType("*models.Transaction")).Return(errors.New("database error"))
 
-	result, err := service.CreateTransaction(req)
+	result, err := service.CreateTransaction(context.Background(), req)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -122,7 +236,7 @@ func TestTransactionService_GetTransaction(t *testing.T) {
 
 	mockRepo.On("GetByID", uint(1)).Return(expectedTx, nil)
 
-	result, err := service.GetTransaction(1)
+	result, err := service.GetTransaction(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -137,7 +251,7 @@ func TestTransactionService_GetTransaction_NotFound(t *testing.T) {
 
 	mockRepo.On("GetByID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
 
-	result, err := service.GetTransaction(1)
+	result, err := service.GetTransaction(context.Background(), 1)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -163,7 +277,7 @@ func TestTransactionService_GetTransactions(t *testing.T) {
 
 	mockRepo.On("GetAll", filters).Return(expectedTxs, nil)
 
-	result, err := service.GetTransactions(filters)
+	result, err := service.GetTransactions(context.Background(), filters)
 
 	assert.NoError(t, err)
 	assert.Len(t, result, 2)
@@ -179,7 +293,7 @@ func TestTransactionService_GetTransactions_InvalidStatus(t *testing.T) {
 		Status: "invalid_status",
 	}
 
-	result, err := service.GetTransactions(filters)
+	result, err := service.GetTransactions(context.Background(), filters)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -197,10 +311,10 @@ func TestTransactionService_UpdateTransactionStatus(t *testing.T) {
 		Status: "pending",
 	}
 
-	mockRepo.On("GetByID", uint(1)).Return(existingTx, nil)
-	mockRepo.On("Update", uint(1), map[string]interface{}{"status": "success"}).Return(nil)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(existingTx, nil)
+	mockRepo.On("UpdateVersion", uint(1), uint(0), map[string]interface{}{"status": "success"}).Return(nil)
 
-	err := service.UpdateTransactionStatus(1, "success")
+	err := service.UpdateTransactionStatus(context.Background(), 1, "success")
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -210,7 +324,7 @@ func TestTransactionService_UpdateTransactionStatus_InvalidStatus(t *testing.T)
 	mockRepo := new(MockTransactionRepository)
 	service := services.NewTransactionService(mockRepo)
 
-	err := service.UpdateTransactionStatus(1, "invalid_status")
+	err := service.UpdateTransactionStatus(context.Background(), 1, "invalid_status")
 
 	assert.Error(t, err)
 	assert.Equal(t, "invalid status", err.Error())
@@ -220,9 +334,9 @@ func TestTransactionService_UpdateTransactionStatus_NotFound(t *testing.T) {
 	mockRepo := new(MockTransactionRepository)
 	service := services.NewTransactionService(mockRepo)
 
-	mockRepo.On("GetByID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(nil, gorm.ErrRecordNotFound)
 
-	err := service.UpdateTransactionStatus(1, "success")
+	err := service.UpdateTransactionStatus(context.Background(), 1, "success")
 
 	assert.Error(t, err)
 	assert.Equal(t, "transaction not found", err.Error())
@@ -240,10 +354,10 @@ func TestTransactionService_DeleteTransaction(t *testing.T) {
 		Status: "pending",
 	}
 
-	mockRepo.On("GetByID", uint(1)).Return(existingTx, nil)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(existingTx, nil)
 	mockRepo.On("Delete", uint(1)).Return(nil)
 
-	err := service.DeleteTransaction(1)
+	err := service.DeleteTransaction(context.Background(), 1)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -253,9 +367,9 @@ func TestTransactionService_DeleteTransaction_NotFound(t *testing.T) {
 	mockRepo := new(MockTransactionRepository)
 	service := services.NewTransactionService(mockRepo)
 
-	mockRepo.On("GetByID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(nil, gorm.ErrRecordNotFound)
 
-	err := service.DeleteTransaction(1)
+	err := service.DeleteTransaction(context.Background(), 1)
 
 	assert.Error(t, err)
 	assert.Equal(t, "transaction not found", err.Error())